@@ -0,0 +1,196 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+
+	"github.com/kubewharf/kubeadmiral/cmd/agent/app/options"
+	"github.com/kubewharf/kubeadmiral/pkg/agent"
+	fedclient "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned"
+	"github.com/kubewharf/kubeadmiral/pkg/client/generic"
+	fedinformers "github.com/kubewharf/kubeadmiral/pkg/client/informers/externalversions"
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/healthcheck"
+	fedleaderelection "github.com/kubewharf/kubeadmiral/pkg/controllermanager/leaderelection"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/federatedcluster"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	"github.com/kubewharf/kubeadmiral/pkg/stats"
+)
+
+// Run starts the agent according to the given options.
+func Run(ctx context.Context, opts *options.Options) {
+	hostConfig, err := buildRestConfig(opts.HostMaster, opts.HostKubeConfig, opts)
+	if err != nil {
+		klog.Fatalf("Error creating host cluster config: %v", err)
+	}
+	memberConfig, err := buildRestConfig(opts.Master, opts.KubeConfig, opts)
+	if err != nil {
+		klog.Fatalf("Error creating member cluster config: %v", err)
+	}
+
+	hostClients, err := createHostClients(hostConfig)
+	if err != nil {
+		klog.Fatalf("Error creating host cluster clients: %v", err)
+	}
+	memberClients, err := createMemberClients(memberConfig)
+	if err != nil {
+		klog.Fatalf("Error creating member cluster clients: %v", err)
+	}
+
+	healthCheckHandler := healthcheck.NewMutableHealthCheckHandler()
+	healthCheckHandler.AddLivezChecker("ping", healthz.Ping)
+
+	run := func(ctx context.Context) {
+		defer klog.Infoln("Ready to stop agent")
+		klog.Infoln("Ready to start agent")
+
+		fedInformerFactory := fedinformers.NewSharedInformerFactory(hostClients.FedClient, util.NoResyncPeriod)
+		memberKubeInformerFactory := informers.NewSharedInformerFactory(memberClients.KubeClient, util.NoResyncPeriod)
+
+		clusterAgent := agent.NewAgent(
+			opts.ClusterName,
+			hostClients,
+			memberClients,
+			fedInformerFactory.Core().V1alpha1().FederatedTypeConfigs(),
+			memberKubeInformerFactory,
+			opts.StatusReportPeriod,
+			&federatedcluster.ClusterHealthCheckConfig{
+				Path:             opts.ClusterHealthCheckPath,
+				Timeout:          opts.ClusterHealthCheckTimeout,
+				SuccessThreshold: opts.ClusterHealthCheckSuccessThreshold,
+				FailureThreshold: opts.ClusterHealthCheckFailureThreshold,
+			},
+			stats.NewMock("", agent.AgentName, false),
+			opts.WorkerCount,
+			common.DefaultFedSystemNamespace,
+		)
+		healthCheckHandler.AddReadyzChecker(agent.AgentName, func(_ *http.Request) error {
+			if clusterAgent.IsControllerReady() {
+				return nil
+			}
+			return fmt.Errorf("agent not ready")
+		})
+
+		fedInformerFactory.Start(ctx.Done())
+		clusterAgent.Run(ctx)
+	}
+
+	go func() {
+		server := &http.Server{
+			Addr:              fmt.Sprintf("0.0.0.0:%d", opts.Port),
+			ReadHeaderTimeout: time.Second * 3,
+			Handler:           healthCheckHandler,
+		}
+		if err := server.ListenAndServe(); err != nil {
+			klog.Fatalf("Failed to start health check server: %v", err)
+		}
+	}()
+
+	if opts.EnableLeaderElect {
+		healthzAdaptor := leaderelection.NewLeaderHealthzAdaptor(time.Second * 20)
+
+		// The lease is held in the member cluster since there is one agent deployment per member cluster.
+		elector, err := fedleaderelection.NewFederationLeaderElector(
+			memberConfig,
+			run,
+			common.DefaultFedSystemNamespace,
+			opts.LeaderElectionResourceName,
+			fedleaderelection.DefaultTiming,
+			healthzAdaptor,
+		)
+		if err != nil {
+			klog.Fatalf("Cannot create elector: %v", err)
+		}
+
+		healthCheckHandler.AddLivezChecker("leaderElection", healthzAdaptor.Check)
+
+		elector.Run(ctx)
+	} else {
+		run(ctx)
+	}
+}
+
+// buildRestConfig builds a rest config from the given master and kubeconfig, falling back to the in-cluster config if
+// both are empty.
+func buildRestConfig(master, kubeConfig string, opts *options.Options) (*rest.Config, error) {
+	restConfig, err := clientcmd.BuildConfigFromFlags(master, kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	restConfig.QPS = opts.KubeAPIQPS
+	restConfig.Burst = opts.KubeAPIBurst
+	return restConfig, nil
+}
+
+func createHostClients(restConfig *rest.Config) (agent.HostClients, error) {
+	restConfig = rest.AddUserAgent(restConfig, agent.AgentName)
+
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return agent.HostClients{}, fmt.Errorf("failed to create kube clientset: %w", err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return agent.HostClients{}, fmt.Errorf("failed to create dynamic clientset: %w", err)
+	}
+	fedClient, err := fedclient.NewForConfig(restConfig)
+	if err != nil {
+		return agent.HostClients{}, fmt.Errorf("failed to create fed clientset: %w", err)
+	}
+
+	return agent.HostClients{
+		KubeClient:    kubeClient,
+		DynamicClient: dynamicClient,
+		FedClient:     fedClient,
+	}, nil
+}
+
+func createMemberClients(restConfig *rest.Config) (agent.MemberClients, error) {
+	restConfig = rest.AddUserAgent(restConfig, agent.AgentName)
+
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return agent.MemberClients{}, fmt.Errorf("failed to create kube clientset: %w", err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return agent.MemberClients{}, fmt.Errorf("failed to create dynamic clientset: %w", err)
+	}
+	genericClient, err := generic.New(restConfig)
+	if err != nil {
+		return agent.MemberClients{}, fmt.Errorf("failed to create generic client: %w", err)
+	}
+
+	return agent.MemberClients{
+		KubeClient:    kubeClient,
+		DynamicClient: dynamicClient,
+		GenericClient: genericClient,
+	}, nil
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/federatedcluster"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+)
+
+const (
+	DefaultPort = 11258
+)
+
+type Options struct {
+	Port int
+
+	ClusterName string
+
+	EnableLeaderElect          bool
+	LeaderElectionResourceName string
+
+	HostMaster     string
+	HostKubeConfig string
+	Master         string
+	KubeConfig     string
+	KubeAPIQPS     float32
+	KubeAPIBurst   int
+
+	WorkerCount        int
+	StatusReportPeriod time.Duration
+
+	ClusterHealthCheckPath             string
+	ClusterHealthCheckTimeout          time.Duration
+	ClusterHealthCheckSuccessThreshold int
+	ClusterHealthCheckFailureThreshold int
+}
+
+func NewOptions() *Options {
+	return &Options{
+		WorkerCount: 1,
+	}
+}
+
+//nolint:lll
+func (o *Options) AddFlags(flags *pflag.FlagSet) {
+	flags.IntVar(&o.Port, "port", DefaultPort, "The port for kubeadmiral agent to listen on.")
+
+	flags.StringVar(&o.ClusterName, "cluster-name", "", "The name of the FederatedCluster object of the member cluster the agent runs in.")
+
+	flags.BoolVar(
+		&o.EnableLeaderElect,
+		"enable-leader-elect",
+		false,
+		"Enable leader election for the agent. Enabling this will ensure there is only one active agent in the member cluster.",
+	)
+	flags.StringVar(
+		&o.LeaderElectionResourceName,
+		"leader-elect-resource-name",
+		"kubeadmiral-agent",
+		"The name of resource object that is used for locking during leader election.",
+	)
+
+	flags.StringVar(&o.HostMaster, "host-master", "", "The address of the host Kubernetes cluster.")
+	flags.StringVar(&o.HostKubeConfig, "host-kubeconfig", "", "The path of the kubeconfig for the host Kubernetes cluster.")
+	flags.StringVar(&o.Master, "master", "", "The address of the member Kubernetes cluster. Defaults to the in-cluster config.")
+	flags.StringVar(&o.KubeConfig, "kubeconfig", "", "The path of the kubeconfig for the member Kubernetes cluster. Defaults to the in-cluster config.")
+	flags.Float32Var(&o.KubeAPIQPS, "kube-api-qps", 100, "The maximum QPS from each Kubernetes client.")
+	flags.IntVar(&o.KubeAPIBurst, "kube-api-burst", 200, "The maximum burst for throttling requests from each Kubernetes client.")
+
+	flags.IntVar(&o.WorkerCount, "worker-count", 1, "The number of workers to use for each federated type.")
+	flags.DurationVar(
+		&o.StatusReportPeriod,
+		"status-report-period",
+		30*time.Second,
+		"The period at which the agent reports the status of the member cluster to the host cluster.",
+	)
+	flags.StringVar(
+		&o.ClusterHealthCheckPath,
+		"cluster-health-check-path",
+		"/healthz",
+		"The default health endpoint of the apiserver of the member cluster.",
+	)
+	flags.DurationVar(
+		&o.ClusterHealthCheckTimeout,
+		"cluster-health-check-timeout",
+		time.Second*30,
+		"The default timeout of health check requests to the member cluster.",
+	)
+	flags.IntVar(
+		&o.ClusterHealthCheckSuccessThreshold,
+		"cluster-health-check-success-threshold",
+		1,
+		"The default number of consecutive successful health checks after which the member cluster becomes ready.",
+	)
+	flags.IntVar(
+		&o.ClusterHealthCheckFailureThreshold,
+		"cluster-health-check-failure-threshold",
+		1,
+		"The default number of consecutive failed health checks after which the member cluster becomes not ready.",
+	)
+
+	o.addKlogFlags(flags)
+}
+
+// Validate returns an error if the options are invalid.
+func (o *Options) Validate() error {
+	if o.ClusterName == "" {
+		return errors.New("--cluster-name is required")
+	}
+	if err := util.ValidatePullClusterName(o.ClusterName); err != nil {
+		return fmt.Errorf("invalid --cluster-name: %w", err)
+	}
+	if o.HostMaster == "" && o.HostKubeConfig == "" {
+		return errors.New("one of --host-master and --host-kubeconfig is required")
+	}
+	if o.StatusReportPeriod <= 0 {
+		return errors.New("--status-report-period must be positive")
+	}
+	if !strings.HasPrefix(o.ClusterHealthCheckPath, "/") {
+		return errors.New("--cluster-health-check-path must start with /")
+	}
+	if o.ClusterHealthCheckTimeout <= 0 {
+		return errors.New("--cluster-health-check-timeout must be positive")
+	}
+	limit := federatedcluster.ClusterHealthProbeHistoryLimit
+	if o.ClusterHealthCheckSuccessThreshold < 1 || o.ClusterHealthCheckSuccessThreshold > limit {
+		return fmt.Errorf("--cluster-health-check-success-threshold must be between 1 and %d", limit)
+	}
+	if o.ClusterHealthCheckFailureThreshold < 1 || o.ClusterHealthCheckFailureThreshold > limit {
+		return fmt.Errorf("--cluster-health-check-failure-threshold must be between 1 and %d", limit)
+	}
+	return nil
+}
+
+func (o *Options) addKlogFlags(flags *pflag.FlagSet) {
+	klogFlags := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	klog.InitFlags(klogFlags)
+
+	klogFlags.VisitAll(func(f *flag.Flag) {
+		f.Name = fmt.Sprintf("klog-%s", strings.ReplaceAll(f.Name, "_", "-"))
+	})
+	flags.AddGoFlagSet(klogFlags)
+}
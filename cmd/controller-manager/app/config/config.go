@@ -0,0 +1,32 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"k8s.io/client-go/rest"
+
+	ctrlmgrconfig "github.com/kubewharf/kubeadmiral/pkg/controllermanager/apis/config"
+)
+
+// Config is the configuration of the controller manager, which is built from the component configuration file and
+// command line flags.
+type Config struct {
+	// ComponentConfig is the validated component configuration.
+	ComponentConfig *ctrlmgrconfig.KubeAdmiralControllerManagerConfiguration
+	// RestConfig is the client configuration of the host cluster.
+	RestConfig *rest.Config
+}
@@ -20,18 +20,22 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"os"
 	"time"
 
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 
-	"github.com/kubewharf/kubeadmiral/cmd/controller-manager/app/options"
+	appconfig "github.com/kubewharf/kubeadmiral/cmd/controller-manager/app/config"
 	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
 	"github.com/kubewharf/kubeadmiral/pkg/controllermanager"
+	ctrlmgrconfig "github.com/kubewharf/kubeadmiral/pkg/controllermanager/apis/config"
 	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/healthcheck"
 	fedleaderelection "github.com/kubewharf/kubeadmiral/pkg/controllermanager/leaderelection"
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/sharding"
 	controllercontext "github.com/kubewharf/kubeadmiral/pkg/controllers/context"
 )
 
@@ -40,6 +44,9 @@ const (
 	TypeConfigControllerName       = "typeconfig"
 	MonitorControllerName          = "monitor"
 	FollowerControllerName         = "follower"
+	FederatedHPAControllerName     = "federatedhpa"
+	MCSControllerName              = "mcs"
+	GlobalDNSControllerName        = "globaldns"
 )
 
 var knownControllers = map[string]controllermanager.StartControllerFunc{
@@ -47,18 +54,37 @@ var knownControllers = map[string]controllermanager.StartControllerFunc{
 	TypeConfigControllerName:       startTypeConfigController,
 	MonitorControllerName:          startMonitorController,
 	FollowerControllerName:         startFollowerController,
+	FederatedHPAControllerName:     startFederatedHPAController,
+	MCSControllerName:              startMCSController,
+	GlobalDNSControllerName:        startGlobalDNSController,
 }
 
-var controllersDisabledByDefault = sets.New(MonitorControllerName)
+var controllersDisabledByDefault = sets.New(MonitorControllerName, MCSControllerName, GlobalDNSControllerName)
 
-// Run starts the controller manager according to the given options.
-func Run(ctx context.Context, opts *options.Options) {
-	controllerCtx, err := createControllerContext(opts)
+// shardedControllers are run by each shard instead of the leader if sharding is enabled.
+var shardedControllers = sets.New(TypeConfigControllerName)
+
+// internalControllers are only run if sharding is enabled and cannot be disabled.
+var internalControllers = sets.New(PolicyRCControllerName, ShardLabelerControllerName)
+
+// controllerConfigReloadPeriod is the interval at which the controller config file is checked for changes.
+const controllerConfigReloadPeriod = 10 * time.Second
+
+// Run starts the controller manager according to the given config.
+func Run(ctx context.Context, cfg *appconfig.Config) {
+	componentConfig := cfg.ComponentConfig
+	enabledControllers := componentConfig.Generic.Controllers
+
+	controllerCtx, err := createControllerContext(cfg)
 	if err != nil {
 		klog.Fatalf("Error creating controller context: %v", err)
 	}
 
-	if opts.EnableProfiling {
+	if tuningConfigFile := componentConfig.Generic.ControllerTuningConfigFile; tuningConfigFile != "" {
+		go controllerCtx.Tuning.Watch(ctx, tuningConfigFile, controllerConfigReloadPeriod)
+	}
+
+	if componentConfig.Generic.EnableProfiling {
 		go func() {
 			server := &http.Server{
 				Addr:              "0.0.0.0:6060",
@@ -77,9 +103,20 @@ func Run(ctx context.Context, opts *options.Options) {
 		defer klog.Infoln("Ready to stop controllers")
 		klog.Infoln("Ready to start controllers")
 
-		err := startControllers(ctx, controllerCtx, knownControllers, knownFTCSubControllers, opts.Controllers, healthCheckHandler)
+		startControllerFuncs, ftcSubControllerInitFuncs := knownControllers, knownFTCSubControllers
+		if componentConfig.Sharding.Enabled {
+			startControllerFuncs = make(map[string]controllermanager.StartControllerFunc, len(knownControllers))
+			for name, startFunc := range knownControllers {
+				if !shardedControllers.Has(name) {
+					startControllerFuncs[name] = startFunc
+				}
+			}
+			ftcSubControllerInitFuncs = leaderFTCSubControllers
+		}
+
+		err := startControllers(ctx, controllerCtx, startControllerFuncs, ftcSubControllerInitFuncs, enabledControllers, healthCheckHandler)
 		if err != nil {
-			klog.Fatalf("Error starting controllers %s: %v", opts.Controllers, err)
+			klog.Fatalf("Error starting controllers %s: %v", enabledControllers, err)
 		}
 
 		controllerCtx.StartFactories(ctx)
@@ -89,7 +126,7 @@ func Run(ctx context.Context, opts *options.Options) {
 
 	go func() {
 		server := &http.Server{
-			Addr:              fmt.Sprintf("0.0.0.0:%d", opts.Port),
+			Addr:              fmt.Sprintf("0.0.0.0:%d", componentConfig.Generic.Port),
 			ReadHeaderTimeout: time.Second * 3,
 			Handler:           healthCheckHandler,
 		}
@@ -98,14 +135,30 @@ func Run(ctx context.Context, opts *options.Options) {
 		}
 	}()
 
-	if opts.EnableLeaderElect {
+	if componentConfig.Sharding.Enabled {
+		coordinator, err := newShardCoordinator(controllerCtx, componentConfig)
+		if err != nil {
+			klog.Fatalf("Cannot create shard coordinator: %v", err)
+		}
+
+		go coordinator.Run(ctx, func(ctx context.Context, shard *sharding.Shard) {
+			runShard(ctx, controllerCtx, shard, enabledControllers, healthCheckHandler)
+		})
+	}
+
+	if componentConfig.LeaderElection.LeaderElect {
 		healthzAdaptor := leaderelection.NewLeaderHealthzAdaptor(time.Second * 20)
 
 		elector, err := fedleaderelection.NewFederationLeaderElector(
 			controllerCtx.RestConfig,
 			run,
 			controllerCtx.FedSystemNamespace,
-			opts.LeaderElectionResourceName,
+			componentConfig.LeaderElection.ResourceName,
+			fedleaderelection.Timing{
+				LeaseDuration: componentConfig.LeaderElection.LeaseDuration.Duration,
+				RenewDeadline: componentConfig.LeaderElection.RenewDeadline.Duration,
+				RetryPeriod:   componentConfig.LeaderElection.RetryPeriod.Duration,
+			},
 			healthzAdaptor,
 		)
 		if err != nil {
@@ -120,6 +173,75 @@ func Run(ctx context.Context, opts *options.Options) {
 	}
 }
 
+func newShardCoordinator(
+	controllerCtx *controllercontext.Context,
+	componentConfig *ctrlmgrconfig.KubeAdmiralControllerManagerConfiguration,
+) (*sharding.Coordinator, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hostname: %w", err)
+	}
+
+	leaderElection := componentConfig.LeaderElection
+	return sharding.NewCoordinator(controllerCtx.KubeClientset.CoordinationV1(), sharding.CoordinatorConfig{
+		Namespace:     controllerCtx.FedSystemNamespace,
+		Group:         leaderElection.ResourceName,
+		Identity:      hostname + "_" + string(uuid.NewUUID()),
+		BucketCount:   int(componentConfig.Sharding.BucketCount),
+		LeaseDuration: leaderElection.LeaseDuration.Duration,
+		RenewDeadline: leaderElection.RenewDeadline.Duration,
+		RetryPeriod:   leaderElection.RetryPeriod.Duration,
+	})
+}
+
+// runShard runs the controllers of federated objects for the given shard until ctx is done. The informers of federated
+// objects are limited to the shard, and the controllers skip objects owned by other shards.
+func runShard(
+	ctx context.Context,
+	controllerCtx *controllercontext.Context,
+	shard *sharding.Shard,
+	enabledControllers []string,
+	healthCheckHandler *healthcheck.MutableHealthCheckHandler,
+) {
+	klog.Infof("Ready to start controllers for shard %s", shard)
+	defer klog.Infof("Stopped controllers for shard %s", shard)
+
+	shardCtx := createShardControllerContext(controllerCtx, shard)
+
+	startControllerFuncs := make(map[string]controllermanager.StartControllerFunc, shardedControllers.Len())
+	for name := range shardedControllers {
+		startControllerFuncs[name] = knownControllers[name]
+	}
+	ftcSubControllerInitFuncs := make(
+		map[string]controllermanager.FTCSubControllerInitFuncs,
+		len(knownFTCSubControllers)+len(shardFTCSubControllers),
+	)
+	for name, initFuncs := range knownFTCSubControllers {
+		ftcSubControllerInitFuncs[name] = initFuncs
+	}
+	for name, initFuncs := range shardFTCSubControllers {
+		ftcSubControllerInitFuncs[name] = initFuncs
+	}
+
+	err := startControllers(ctx, shardCtx, startControllerFuncs, ftcSubControllerInitFuncs, enabledControllers, healthCheckHandler)
+	if err != nil {
+		klog.Fatalf("Error starting controllers %s for shard %s: %v", enabledControllers, shard, err)
+	}
+
+	// The other informer factories are shared with the leader and later shards, so their informers must outlive the
+	// shard.
+	controllerCtx.KubeInformerFactory.Start(context.TODO().Done())
+	controllerCtx.DynamicInformerFactory.Start(context.TODO().Done())
+	controllerCtx.FedInformerFactory.Start(context.TODO().Done())
+	shardCtx.FederatedObjectInformerFactory.Start(ctx.Done())
+
+	<-ctx.Done()
+
+	for name := range shardedControllers {
+		healthCheckHandler.RemoveReadyzChecker(name)
+	}
+}
+
 // startControllers loops through startControllerFuncs in sequence and starts the given controller if it is enabled.
 // An error is returned if one of the controller fails to start. startControllers will not block on the controllers
 // and will return once they have all been successfully started.
@@ -163,7 +285,8 @@ func startControllers(
 		controllerName := controllerName
 		initFuncs := initFuncs
 		manager.RegisterSubController(controllerName, initFuncs.StartFunc, func(typeConfig *fedcorev1a1.FederatedTypeConfig) bool {
-			if !isControllerEnabled(controllerName, controllersDisabledByDefault, enabledControllers) {
+			if !internalControllers.Has(controllerName) &&
+				!isControllerEnabled(controllerName, controllersDisabledByDefault, enabledControllers) {
 				return false
 			}
 			if initFuncs.IsEnabledFunc != nil {
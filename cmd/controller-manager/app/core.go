@@ -29,10 +29,15 @@ import (
 	controllercontext "github.com/kubewharf/kubeadmiral/pkg/controllers/context"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/federate"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/federatedcluster"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/federatedhpa"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/federatedtypeconfig"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/follower"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/globaldns"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/mcs"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/monitor"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/policyrc"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/shardlabeler"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
 	schemautil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/schema"
 )
@@ -42,12 +47,27 @@ func startFederatedClusterController(ctx context.Context, controllerCtx *control
 		controllerCtx.FedClientset,
 		controllerCtx.KubeClientset,
 		controllerCtx.FedInformerFactory.Core().V1alpha1().FederatedClusters(),
+		controllerCtx.FedInformerFactory.Core().V1alpha1().FederatedTypeConfigs(),
 		controllerCtx.FederatedClientFactory,
 		controllerCtx.Metrics,
 		controllerCtx.FedSystemNamespace,
 		controllerCtx.RestConfig,
-		controllerCtx.WorkerCount,
+		controllerCtx.WorkerConfig(FederatedClusterControllerName, ""),
 		controllerCtx.ComponentConfig.ClusterJoinTimeout,
+		controllerCtx.ComponentConfig.ClusterTokenRotationPeriod,
+		&federatedcluster.ClusterHealthCheckConfig{
+			Period:             controllerCtx.ComponentConfig.ClusterHealthCheckPeriod,
+			AgentStatusTimeout: controllerCtx.ComponentConfig.ClusterAgentStatusTimeout,
+			FlapDetection: federatedcluster.FlapDetectionConfig{
+				Window:    controllerCtx.ComponentConfig.ClusterFlapDetectionWindow,
+				Threshold: controllerCtx.ComponentConfig.ClusterFlapDetectionThreshold,
+			},
+			Path:             controllerCtx.ComponentConfig.ClusterHealthCheckPath,
+			Timeout:          controllerCtx.ComponentConfig.ClusterHealthCheckTimeout,
+			SuccessThreshold: controllerCtx.ComponentConfig.ClusterHealthCheckSuccessThreshold,
+			FailureThreshold: controllerCtx.ComponentConfig.ClusterHealthCheckFailureThreshold,
+		},
+		controllerCtx.ComponentConfig.ClusterCredentialProviderPolicy,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error creating federated cluster controller: %w", err)
@@ -67,7 +87,7 @@ func startTypeConfigController(ctx context.Context, controllerCtx *controllercon
 		controllerCtx.DynamicClientset,
 		controllerCtx.FedClientset,
 		controllerCtx.KubeInformerFactory,
-		controllerCtx.DynamicInformerFactory,
+		controllerCtx.FederatedObjectInformerFactory,
 		controllerCtx.FedInformerFactory,
 	)
 	if err != nil {
@@ -102,7 +122,7 @@ func startFollowerController(ctx context.Context, controllerCtx *controllerconte
 		controllerCtx.FedClientset,
 		controllerCtx.DynamicInformerFactory,
 		controllerCtx.Metrics,
-		controllerCtx.WorkerCount,
+		controllerCtx.WorkerConfig(FollowerControllerName, ""),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error creating follower controller: %w", err)
@@ -113,6 +133,68 @@ func startFollowerController(ctx context.Context, controllerCtx *controllerconte
 	return controller, nil
 }
 
+func startFederatedHPAController(ctx context.Context, controllerCtx *controllercontext.Context) (controllermanager.Controller, error) {
+	controller, err := federatedhpa.NewFederatedHPAController(
+		controllerCtx.KubeClientset,
+		controllerCtx.DynamicClientset,
+		controllerCtx.KubeInformerFactory.Autoscaling().V2().HorizontalPodAutoscalers(),
+		controllerCtx.FedInformerFactory.Core().V1alpha1().FederatedTypeConfigs(),
+		controllerCtx.FederatedClientFactory,
+		controllerCtx.Metrics,
+		controllerCtx.WorkerConfig(FederatedHPAControllerName, ""),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating federated hpa controller: %w", err)
+	}
+
+	go controller.Run(ctx)
+
+	return controller, nil
+}
+
+func startMCSController(ctx context.Context, controllerCtx *controllercontext.Context) (controllermanager.Controller, error) {
+	controller, err := mcs.NewMCSController(
+		controllerCtx.KubeInformerFactory.Core().V1().Services(),
+		controllerCtx.FederatedClientFactory,
+		controllerCtx.Metrics,
+		controllerCtx.WorkerConfig(MCSControllerName, ""),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating mcs controller: %w", err)
+	}
+
+	go controller.Run(ctx)
+
+	return controller, nil
+}
+
+func startGlobalDNSController(
+	ctx context.Context,
+	controllerCtx *controllercontext.Context,
+) (controllermanager.Controller, error) {
+	if controllerCtx.ComponentConfig.GlobalDNSProvider == nil {
+		return nil, fmt.Errorf("global dns controller requires a dns provider to be configured")
+	}
+
+	controller, err := globaldns.NewGlobalDNSController(
+		controllerCtx.KubeClientset,
+		controllerCtx.KubeInformerFactory.Core().V1().Services(),
+		controllerCtx.KubeInformerFactory.Networking().V1().Ingresses(),
+		controllerCtx.FedInformerFactory.Core().V1alpha1().FederatedClusters(),
+		controllerCtx.FederatedClientFactory,
+		controllerCtx.ComponentConfig.GlobalDNSProvider,
+		controllerCtx.Metrics,
+		controllerCtx.WorkerConfig(GlobalDNSControllerName, ""),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating global dns controller: %w", err)
+	}
+
+	go controller.Run(ctx)
+
+	return controller, nil
+}
+
 // TODO: remove this function once all controllers are fully refactored
 func controllerConfigFromControllerContext(controllerCtx *controllercontext.Context) *util.ControllerConfig {
 	return &util.ControllerConfig{
@@ -127,6 +209,11 @@ func controllerConfigFromControllerContext(controllerCtx *controllercontext.Cont
 		WorkerCount:                           controllerCtx.WorkerCount,
 		NamespaceAutoPropagationExcludeRegexp: controllerCtx.ComponentConfig.NSAutoPropExcludeRegexp,
 		CreateCrdForFtcs:                      controllerCtx.ComponentConfig.FederatedTypeConfigCreateCRDsForFTCs,
+		PausePropagation:                      controllerCtx.ComponentConfig.PausePropagation,
+		OutOfSyncRecheckDelay:                 controllerCtx.ComponentConfig.MonitorOutOfSyncRecheckDelay,
+		Shard:                                 controllerCtx.Shard,
+		Tuning:                                controllerCtx.Tuning,
+		CredentialProviderPolicy:              controllerCtx.ComponentConfig.ClusterCredentialProviderPolicy,
 		Metrics:                               controllerCtx.Metrics,
 	}
 }
@@ -145,14 +232,21 @@ func startGlobalScheduler(
 		controllerCtx.KubeClientset,
 		controllerCtx.FedClientset,
 		controllerCtx.DynamicClientset,
-		controllerCtx.DynamicInformerFactory.ForResource(federatedGVR),
+		controllerCtx.FederatedObjectInformerFactory.ForResource(federatedGVR),
+		controllerCtx.DynamicInformerFactory,
 		controllerCtx.FedInformerFactory.Core().V1alpha1().PropagationPolicies(),
 		controllerCtx.FedInformerFactory.Core().V1alpha1().ClusterPropagationPolicies(),
 		controllerCtx.FedInformerFactory.Core().V1alpha1().FederatedClusters(),
 		controllerCtx.FedInformerFactory.Core().V1alpha1().SchedulingProfiles(),
 		controllerCtx.FedInformerFactory.Core().V1alpha1().SchedulerPluginWebhookConfigurations(),
+		controllerCtx.FedInformerFactory.Core().V1alpha1().FederatedTypeConfigs(),
+		controllerCtx.KubeInformerFactory.Scheduling().V1().PriorityClasses(),
 		controllerCtx.Metrics,
-		controllerCtx.WorkerCount,
+		controllerCtx.WorkerConfig(GlobalSchedulerName, typeConfig.Name),
+		scheduler.Config{
+			SupplyLimitProportion: controllerCtx.ComponentConfig.SchedulerSupplyLimitProportion,
+			WebhookTimeout:        controllerCtx.ComponentConfig.SchedulerWebhookTimeout,
+		},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error creating global scheduler: %w", err)
@@ -189,11 +283,13 @@ func startFederateController(
 		typeConfig,
 		controllerCtx.KubeClientset,
 		controllerCtx.DynamicClientset,
-		controllerCtx.DynamicInformerFactory.ForResource(federatedGVR),
+		controllerCtx.FederatedObjectInformerFactory.ForResource(federatedGVR),
 		controllerCtx.DynamicInformerFactory.ForResource(sourceGVR),
 		controllerCtx.Metrics,
-		controllerCtx.WorkerCount,
+		controllerCtx.WorkerConfig(FederateControllerName, typeConfig.Name),
 		controllerCtx.FedSystemNamespace,
+		controllerCtx.ComponentConfig.FederateMetadataPropagation,
+		controllerCtx.Shard,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error creating federate controller: %w", err)
@@ -228,7 +324,7 @@ func startAutoMigrationController(
 		genericClient,
 		controllerCtx.KubeClientset,
 		controllerCtx.DynamicClientset.Resource(federatedGVR),
-		controllerCtx.DynamicInformerFactory.ForResource(federatedGVR),
+		controllerCtx.FederatedObjectInformerFactory.ForResource(federatedGVR),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error creating auto-migration controller: %w", err)
@@ -242,3 +338,48 @@ func startAutoMigrationController(
 func isAutoMigrationControllerEnabled(typeConfig *fedcorev1a1.FederatedTypeConfig) bool {
 	return typeConfig.Spec.AutoMigration != nil && typeConfig.Spec.AutoMigration.Enabled
 }
+
+func startPolicyRCController(
+	ctx context.Context,
+	controllerCtx *controllercontext.Context,
+	typeConfig *fedcorev1a1.FederatedTypeConfig,
+) (controllermanager.Controller, error) {
+	//nolint:contextcheck
+	controller, err := policyrc.NewController(controllerConfigFromControllerContext(controllerCtx), typeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating policyrc controller: %w", err)
+	}
+
+	go controller.Run(ctx.Done())
+
+	return controller, nil
+}
+
+func isPolicyRCControllerEnabled(typeConfig *fedcorev1a1.FederatedTypeConfig) bool {
+	return typeConfig.GetPolicyRcEnabled()
+}
+
+func startShardLabelerController(
+	ctx context.Context,
+	controllerCtx *controllercontext.Context,
+	typeConfig *fedcorev1a1.FederatedTypeConfig,
+) (controllermanager.Controller, error) {
+	federatedAPIResource := typeConfig.GetFederatedType()
+	federatedGVR := schemautil.APIResourceToGVR(&federatedAPIResource)
+
+	controller, err := shardlabeler.NewShardLabelerController(
+		typeConfig,
+		controllerCtx.DynamicClientset,
+		controllerCtx.FederatedObjectInformerFactory.ForResource(federatedGVR),
+		controllerCtx.Shard,
+		controllerCtx.Metrics,
+		controllerCtx.WorkerConfig(ShardLabelerControllerName, typeConfig.Name),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating shard labeler controller: %w", err)
+	}
+
+	go controller.Run(ctx)
+
+	return controller, nil
+}
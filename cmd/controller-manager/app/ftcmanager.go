@@ -43,6 +43,8 @@ const (
 	FederateControllerName      = "federate"
 	GlobalSchedulerName         = "scheduler"
 	AutoMigrationControllerName = "automigration"
+	PolicyRCControllerName      = "policyrc"
+	ShardLabelerControllerName  = "shardlabeler"
 )
 
 var knownFTCSubControllers = map[string]controllermanager.FTCSubControllerInitFuncs{
@@ -60,10 +62,29 @@ var knownFTCSubControllers = map[string]controllermanager.FTCSubControllerInitFu
 	},
 }
 
+// leaderFTCSubControllers are run by the leader if sharding is enabled. The policyrc controller is otherwise started
+// by the typeconfig controller.
+var leaderFTCSubControllers = map[string]controllermanager.FTCSubControllerInitFuncs{
+	PolicyRCControllerName: {
+		StartFunc:     startPolicyRCController,
+		IsEnabledFunc: isPolicyRCControllerEnabled,
+	},
+}
+
+// shardFTCSubControllers are run by each shard in addition to knownFTCSubControllers if sharding is enabled.
+var shardFTCSubControllers = map[string]controllermanager.FTCSubControllerInitFuncs{
+	ShardLabelerControllerName: {
+		StartFunc: startShardLabelerController,
+	},
+}
+
 type FederatedTypeConfigManager struct {
 	informer fedcorev1a1informers.FederatedTypeConfigInformer
 	handle   cache.ResourceEventHandlerRegistration
 
+	// ctx is the context passed to Run, from which the contexts of subcontrollers are derived.
+	ctx context.Context
+
 	lock                        sync.Mutex
 	registeredSubControllers    map[string]controllermanager.StartFTCSubControllerFunc
 	isSubControllerEnabledFuncs map[string]controllermanager.IsFTCSubControllerEnabledFunc
@@ -132,8 +153,23 @@ func (m *FederatedTypeConfigManager) Run(ctx context.Context) {
 		return
 	}
 
+	m.lock.Lock()
+	m.ctx = ctx
+	m.lock.Unlock()
+
 	m.worker.Run(ctx.Done())
 	<-ctx.Done()
+
+	// Subcontrollers are stopped together with the manager, e.g. when the shard of the controller manager changes.
+	m.lock.Lock()
+	ftcNames := make([]string, 0, len(m.subControllerCancelFuncs))
+	for ftcName := range m.subControllerCancelFuncs {
+		ftcNames = append(ftcNames, ftcName)
+	}
+	m.lock.Unlock()
+	for _, ftcName := range ftcNames {
+		m.processFTCDeletion(ftcName)
+	}
 }
 
 func (m *FederatedTypeConfigManager) reconcile(qualifiedName common.QualifiedName) (status worker.Result) {
@@ -169,7 +205,7 @@ func (m *FederatedTypeConfigManager) reconcile(qualifiedName common.QualifiedNam
 	}
 	subControllerCtx, ok := m.subControllerContexts[qualifiedName.Name]
 	if !ok {
-		subControllerCtx, m.subControllerCancelFuncs[qualifiedName.Name] = context.WithCancel(context.TODO())
+		subControllerCtx, m.subControllerCancelFuncs[qualifiedName.Name] = context.WithCancel(m.ctx)
 		m.subControllerContexts[qualifiedName.Name] = subControllerCtx
 	}
 
@@ -216,6 +252,8 @@ func (m *FederatedTypeConfigManager) reconcile(qualifiedName common.QualifiedNam
 	m.controllerCtx.KubeInformerFactory.Start(ctx.Done())
 	m.controllerCtx.DynamicInformerFactory.Start(ctx.Done())
 	m.controllerCtx.FedInformerFactory.Start(ctx.Done())
+	// Informers of federated objects may be limited to the shard of the manager, so they are stopped with the manager.
+	m.controllerCtx.FederatedObjectInformerFactory.Start(m.ctx.Done())
 
 	if needRetry {
 		return worker.StatusError
@@ -0,0 +1,173 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
+
+	appconfig "github.com/kubewharf/kubeadmiral/cmd/controller-manager/app/config"
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	ctrlmgrconfig "github.com/kubewharf/kubeadmiral/pkg/controllermanager/apis/config"
+	ctrlmgrscheme "github.com/kubewharf/kubeadmiral/pkg/controllermanager/apis/config/scheme"
+	ctrlmgrv1a1 "github.com/kubewharf/kubeadmiral/pkg/controllermanager/apis/config/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/apis/config/validation"
+)
+
+// Config returns the configuration of the controller manager. The component configuration is loaded from the file
+// given by --config, or defaulted if it is not set, and overridden by the flags that are set explicitly.
+func (o *Options) Config(flags *pflag.FlagSet) (*appconfig.Config, error) {
+	componentConfig, err := LoadComponentConfig(o.ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := o.applyFlags(componentConfig, flags); err != nil {
+		return nil, err
+	}
+
+	if errs := validation.ValidateKubeAdmiralControllerManagerConfiguration(componentConfig); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid component config: %w", errs.ToAggregate())
+	}
+
+	clientConnection := componentConfig.Generic.ClientConnection
+	restConfig, err := clientcmd.BuildConfigFromFlags(o.Master, clientConnection.Kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rest config: %w", err)
+	}
+	restConfig.QPS = clientConnection.QPS
+	restConfig.Burst = int(clientConnection.Burst)
+
+	return &appconfig.Config{
+		ComponentConfig: componentConfig,
+		RestConfig:      restConfig,
+	}, nil
+}
+
+// LoadComponentConfig decodes, defaults and converts the component configuration in the given file to the internal
+// version. The defaults are returned if path is empty.
+func LoadComponentConfig(path string) (*ctrlmgrconfig.KubeAdmiralControllerManagerConfiguration, error) {
+	if path == "" {
+		versioned := &ctrlmgrv1a1.KubeAdmiralControllerManagerConfiguration{}
+		ctrlmgrscheme.Scheme.Default(versioned)
+		componentConfig := &ctrlmgrconfig.KubeAdmiralControllerManagerConfiguration{}
+		if err := ctrlmgrscheme.Scheme.Convert(versioned, componentConfig, nil); err != nil {
+			return nil, fmt.Errorf("failed to convert default component config: %w", err)
+		}
+		return componentConfig, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read component config: %w", err)
+	}
+	obj, err := runtime.Decode(ctrlmgrscheme.Codecs.UniversalDecoder(), data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode component config: %w", err)
+	}
+	componentConfig, ok := obj.(*ctrlmgrconfig.KubeAdmiralControllerManagerConfiguration)
+	if !ok {
+		return nil, fmt.Errorf("unexpected component config type %T", obj)
+	}
+	return componentConfig, nil
+}
+
+// applyFlags overrides the component configuration with the flags that are set explicitly.
+func (o *Options) applyFlags(cfg *ctrlmgrconfig.KubeAdmiralControllerManagerConfiguration, flags *pflag.FlagSet) error {
+	set := func(name string, apply func()) {
+		if flags.Changed(name) {
+			apply()
+		}
+	}
+
+	generic := &cfg.Generic
+	set("port", func() { generic.Port = int32(o.Port) })
+	set("controllers", func() { generic.Controllers = o.Controllers })
+	set("kubeconfig", func() { generic.ClientConnection.Kubeconfig = o.KubeConfig })
+	set("kube-api-qps", func() { generic.ClientConnection.QPS = o.KubeAPIQPS })
+	set("kube-api-burst", func() { generic.ClientConnection.Burst = int32(o.KubeAPIBurst) })
+	set("worker-count", func() { generic.WorkerCount = int32(o.WorkerCount) })
+	set("controller-config", func() { generic.ControllerTuningConfigFile = o.ControllerConfig })
+	set("enable-profiling", func() { generic.EnableProfiling = o.EnableProfiling })
+	set("max-pod-listers", func() { generic.MaxPodListers = o.MaxPodListers })
+	set("enable-pod-pruning", func() { generic.EnablePodPruning = o.EnablePodPruning })
+
+	set("enable-leader-elect", func() { cfg.LeaderElection.LeaderElect = o.EnableLeaderElect })
+	set("leader-elect-resource-name", func() { cfg.LeaderElection.ResourceName = o.LeaderElectionResourceName })
+	set("enable-sharding", func() { cfg.Sharding.Enabled = o.EnableSharding })
+	set("shard-bucket-count", func() { cfg.Sharding.BucketCount = int32(o.ShardBucketCount) })
+
+	cluster := &cfg.FederatedClusterController
+	set("cluster-join-timeout", func() { cluster.JoinTimeout.Duration = o.ClusterJoinTimeout })
+	set("cluster-token-rotation-period", func() { cluster.TokenRotationPeriod.Duration = o.ClusterTokenRotationPeriod })
+	set("cluster-flap-detection-window", func() { cluster.FlapDetectionWindow.Duration = o.ClusterFlapDetectionWindow })
+	set("cluster-flap-detection-threshold", func() { cluster.FlapDetectionThreshold = int32(o.ClusterFlapDetectionThreshold) })
+	set("cluster-health-check-period", func() { cluster.HealthCheck.Period.Duration = o.ClusterHealthCheckPeriod })
+	set("cluster-health-check-path", func() { cluster.HealthCheck.Path = o.ClusterHealthCheckPath })
+	set("cluster-health-check-timeout", func() { cluster.HealthCheck.Timeout.Duration = o.ClusterHealthCheckTimeout })
+	set("cluster-health-check-success-threshold", func() {
+		cluster.HealthCheck.SuccessThreshold = int32(o.ClusterHealthCheckSuccessThreshold)
+	})
+	set("cluster-health-check-failure-threshold", func() {
+		cluster.HealthCheck.FailureThreshold = int32(o.ClusterHealthCheckFailureThreshold)
+	})
+	set("cluster-agent-status-timeout", func() { cluster.AgentStatusTimeout.Duration = o.ClusterAgentStatusTimeout })
+	set("cluster-allowed-exec-commands", func() {
+		cluster.CredentialProviders.AllowedExecCommands = o.ClusterAllowedExecCommands
+	})
+	set("cluster-allowed-token-file-dirs", func() {
+		cluster.CredentialProviders.AllowedTokenFileDirectories = o.ClusterAllowedTokenFileDirs
+	})
+
+	set("create-crds-for-ftcs", func() { cfg.TypeConfigController.CreateCRDsForFTCs = o.CreateCRDsForFTCs })
+	set("pause-propagation", func() { cfg.SyncController.PausePropagation = o.PausePropagation })
+	set("ns-autoprop-exclude-regexp", func() {
+		cfg.NamespaceAutoPropagationController.ExcludeRegexp = o.NSAutoPropExcludeRegexp
+	})
+
+	dns := &cfg.GlobalDNSController
+	set("global-dns-provider", func() { dns.Provider = o.GlobalDNSProvider })
+	set("global-dns-rfc2136-server", func() { dns.RFC2136.Server = o.GlobalDNSRFC2136Server })
+	set("global-dns-rfc2136-zone", func() { dns.RFC2136.Zone = o.GlobalDNSRFC2136Zone })
+	set("global-dns-rfc2136-tsig-key-name", func() { dns.RFC2136.TSIGKeyName = o.GlobalDNSRFC2136TSIGKeyName })
+	set("global-dns-rfc2136-tsig-algorithm", func() { dns.RFC2136.TSIGAlgorithm = o.GlobalDNSRFC2136TSIGAlgorithm })
+	set("global-dns-rfc2136-tsig-secret-file", func() { dns.RFC2136.TSIGSecretFile = o.GlobalDNSRFC2136TSIGSecretFile })
+	set("global-dns-coredns-etcd-endpoint", func() { dns.CoreDNSEtcd.Endpoint = o.GlobalDNSCoreDNSEtcdEndpoint })
+	set("global-dns-coredns-etcd-prefix", func() { dns.CoreDNSEtcd.Prefix = o.GlobalDNSCoreDNSEtcdPrefix })
+	set("global-dns-coredns-etcd-ca-file", func() { dns.CoreDNSEtcd.CAFile = o.GlobalDNSCoreDNSEtcdCAFile })
+	set("global-dns-coredns-etcd-cert-file", func() { dns.CoreDNSEtcd.CertFile = o.GlobalDNSCoreDNSEtcdCertFile })
+	set("global-dns-coredns-etcd-key-file", func() { dns.CoreDNSEtcd.KeyFile = o.GlobalDNSCoreDNSEtcdKeyFile })
+
+	if flags.Changed("federate-metadata-propagation-config") && o.FederateMetadataPropagationConfig != "" {
+		data, err := os.ReadFile(o.FederateMetadataPropagationConfig)
+		if err != nil {
+			return fmt.Errorf("failed to read federate metadata propagation config: %w", err)
+		}
+		metadataPropagation := &fedcorev1a1.MetadataPropagation{}
+		if err := yaml.UnmarshalStrict(data, metadataPropagation); err != nil {
+			return fmt.Errorf("failed to parse federate metadata propagation config: %w", err)
+		}
+		cfg.FederateController.MetadataPropagation = metadataPropagation
+	}
+
+	return nil
+}
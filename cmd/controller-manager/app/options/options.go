@@ -32,6 +32,8 @@ const (
 )
 
 type Options struct {
+	ConfigFile string
+
 	Port int
 
 	Controllers []string
@@ -39,21 +41,56 @@ type Options struct {
 	EnableLeaderElect          bool
 	LeaderElectionResourceName string
 
+	EnableSharding   bool
+	ShardBucketCount int
+
 	Master       string
 	KubeConfig   string
 	KubeAPIQPS   float32
 	KubeAPIBurst int
 
-	WorkerCount     int
-	EnableProfiling bool
-	LogFile         string
-	LogVerbosity    int
-	KlogVerbosity   int
+	WorkerCount      int
+	ControllerConfig string
+	EnableProfiling  bool
+	LogFile          string
+	LogVerbosity     int
+	KlogVerbosity    int
 
 	NSAutoPropExcludeRegexp string
 	CreateCRDsForFTCs       bool
 	ClusterJoinTimeout      time.Duration
 
+	ClusterTokenRotationPeriod time.Duration
+
+	ClusterFlapDetectionWindow    time.Duration
+	ClusterFlapDetectionThreshold int
+
+	ClusterHealthCheckPeriod           time.Duration
+	ClusterHealthCheckPath             string
+	ClusterHealthCheckTimeout          time.Duration
+	ClusterHealthCheckSuccessThreshold int
+	ClusterHealthCheckFailureThreshold int
+	ClusterAgentStatusTimeout          time.Duration
+
+	ClusterAllowedExecCommands  []string
+	ClusterAllowedTokenFileDirs []string
+
+	GlobalDNSProvider              string
+	GlobalDNSRFC2136Server         string
+	GlobalDNSRFC2136Zone           string
+	GlobalDNSRFC2136TSIGKeyName    string
+	GlobalDNSRFC2136TSIGAlgorithm  string
+	GlobalDNSRFC2136TSIGSecretFile string
+	GlobalDNSCoreDNSEtcdEndpoint   string
+	GlobalDNSCoreDNSEtcdPrefix     string
+	GlobalDNSCoreDNSEtcdCAFile     string
+	GlobalDNSCoreDNSEtcdCertFile   string
+	GlobalDNSCoreDNSEtcdKeyFile    string
+
+	FederateMetadataPropagationConfig string
+
+	PausePropagation bool
+
 	MaxPodListers    int64
 	EnablePodPruning bool
 }
@@ -66,6 +103,12 @@ func NewOptions() *Options {
 
 //nolint:lll
 func (o *Options) AddFlags(flags *pflag.FlagSet, allControllers []string, disabledByDefaultControllers []string) {
+	flags.StringVar(
+		&o.ConfigFile,
+		"config",
+		"",
+		"The path of the KubeAdmiralControllerManagerConfiguration file. Flags that are set explicitly override the values in the file.",
+	)
 	flags.IntVar(&o.Port, "port", DefaultPort, "The port for kubeadmiral controller-manager to listen on.")
 
 	defaultControllers := []string{"*"}
@@ -90,12 +133,34 @@ func (o *Options) AddFlags(flags *pflag.FlagSet, allControllers []string, disabl
 		"The name of resource object that is used for locking during leader election.",
 	)
 
+	flags.BoolVar(
+		&o.EnableSharding,
+		"enable-sharding",
+		false,
+		"Enable sharding of federated objects among controller manager replicas. Each replica runs the controllers of "+
+			"federated objects for the keys it owns, while the other controllers run in the leader. Requires leader election.",
+	)
+	flags.IntVar(
+		&o.ShardBucketCount,
+		"shard-bucket-count",
+		64,
+		"The number of hash buckets that the keys of federated objects are distributed into when sharding is enabled. "+
+			"Must be the same for all replicas.",
+	)
+
 	flags.StringVar(&o.Master, "master", "", "The address of the host Kubernetes cluster.")
 	flags.StringVar(&o.KubeConfig, "kubeconfig", "", "The path of the kubeconfig for the host Kubernetes cluster.")
 	flags.Float32Var(&o.KubeAPIQPS, "kube-api-qps", 500, "The maximum QPS from each Kubernetes client.")
 	flags.IntVar(&o.KubeAPIBurst, "kube-api-burst", 1000, "The maximum burst for throttling requests from each Kubernetes client.")
 
 	flags.IntVar(&o.WorkerCount, "worker-count", 1, "The number of workers to use for Kubeadmiral controllers")
+	flags.StringVar(
+		&o.ControllerConfig,
+		"controller-config",
+		"",
+		"The path of a YAML file containing the worker counts, rate limits and member cluster client QPS of controllers, "+
+			"which override --worker-count and --kube-api-qps. The file is reloaded when it changes.",
+	)
 	flags.BoolVar(&o.EnableProfiling, "enable-profiling", false, "Enable profiling for the controller manager.")
 
 	flags.StringVar(
@@ -111,6 +176,154 @@ func (o *Options) AddFlags(flags *pflag.FlagSet, allControllers []string, disabl
 		time.Minute*10,
 		"The maximum amount of time to wait for a new cluster to join the federation before timing out.",
 	)
+	flags.DurationVar(
+		&o.ClusterTokenRotationPeriod,
+		"cluster-token-rotation-period",
+		time.Hour*24,
+		"The interval at which the service account tokens of member clusters using service account tokens are rotated. "+
+			"Set to 0 to disable rotation.",
+	)
+	flags.DurationVar(
+		&o.ClusterFlapDetectionWindow,
+		"cluster-flap-detection-window",
+		time.Minute*10,
+		"The period in which changes of a member cluster's readiness are counted for flap detection.",
+	)
+	flags.IntVar(
+		&o.ClusterFlapDetectionThreshold,
+		"cluster-flap-detection-threshold",
+		3,
+		"The number of changes of a member cluster's readiness within the flap detection window at which the cluster is "+
+			"marked unstable and deprioritised by the scheduler. Set to 0 to disable flap detection.",
+	)
+	flags.DurationVar(
+		&o.ClusterHealthCheckPeriod,
+		"cluster-health-check-period",
+		time.Second*30,
+		"The default interval between health checks of member clusters.",
+	)
+	flags.StringVar(
+		&o.ClusterHealthCheckPath,
+		"cluster-health-check-path",
+		"/healthz",
+		"The default health endpoint of the apiservers of member clusters.",
+	)
+	flags.DurationVar(
+		&o.ClusterHealthCheckTimeout,
+		"cluster-health-check-timeout",
+		time.Second*30,
+		"The default timeout of health check requests to member clusters.",
+	)
+	flags.IntVar(
+		&o.ClusterHealthCheckSuccessThreshold,
+		"cluster-health-check-success-threshold",
+		1,
+		"The default number of consecutive successful health checks after which a member cluster becomes ready.",
+	)
+	flags.IntVar(
+		&o.ClusterHealthCheckFailureThreshold,
+		"cluster-health-check-failure-threshold",
+		1,
+		"The default number of consecutive failed health checks after which a member cluster becomes not ready.",
+	)
+	flags.DurationVar(
+		&o.ClusterAgentStatusTimeout,
+		"cluster-agent-status-timeout",
+		time.Second*90,
+		"The time after which a pull-mode member cluster is marked as not ready if its agent has not reported its status.",
+	)
+	flags.StringSliceVar(
+		&o.ClusterAllowedExecCommands,
+		"cluster-allowed-exec-commands",
+		nil,
+		"The commands that exec credential providers of member clusters may run. Exec credential providers are "+
+			"rejected unless their command is listed.",
+	)
+	flags.StringSliceVar(
+		&o.ClusterAllowedTokenFileDirs,
+		"cluster-allowed-token-file-dirs",
+		nil,
+		"The absolute paths of the directories that token file credential providers of member clusters may read "+
+			"from. Token file credential providers are rejected unless their file is in a listed directory.",
+	)
+
+	flags.StringVar(
+		&o.GlobalDNSProvider,
+		"global-dns-provider",
+		"",
+		"The DNS provider used by the global DNS controller to publish records. One of rfc2136 and coredns-etcd.",
+	)
+	flags.StringVar(
+		&o.GlobalDNSRFC2136Server,
+		"global-dns-rfc2136-server",
+		"",
+		"The address of the DNS server in the form host:port to which RFC 2136 dynamic updates are sent.",
+	)
+	flags.StringVar(&o.GlobalDNSRFC2136Zone, "global-dns-rfc2136-zone", "", "The zone in which DNS records are updated.")
+	flags.StringVar(
+		&o.GlobalDNSRFC2136TSIGKeyName,
+		"global-dns-rfc2136-tsig-key-name",
+		"",
+		"The name of the TSIG key used to sign dynamic updates. Updates are not signed if empty.",
+	)
+	flags.StringVar(
+		&o.GlobalDNSRFC2136TSIGAlgorithm,
+		"global-dns-rfc2136-tsig-algorithm",
+		"hmac-sha256",
+		"The algorithm of the TSIG key. One of hmac-sha1, hmac-sha256 and hmac-sha512.",
+	)
+	flags.StringVar(
+		&o.GlobalDNSRFC2136TSIGSecretFile,
+		"global-dns-rfc2136-tsig-secret-file",
+		"",
+		"The path of the file containing the base64-encoded secret of the TSIG key.",
+	)
+	flags.StringVar(
+		&o.GlobalDNSCoreDNSEtcdEndpoint,
+		"global-dns-coredns-etcd-endpoint",
+		"",
+		"The URL of the etcd server used by the CoreDNS etcd plugin, e.g. http://127.0.0.1:2379.",
+	)
+	flags.StringVar(
+		&o.GlobalDNSCoreDNSEtcdPrefix,
+		"global-dns-coredns-etcd-prefix",
+		"/skydns",
+		"The path prefix configured in the CoreDNS etcd plugin.",
+	)
+	flags.StringVar(
+		&o.GlobalDNSCoreDNSEtcdCAFile,
+		"global-dns-coredns-etcd-ca-file",
+		"",
+		"The path of the CA bundle used to verify the certificate of the etcd server.",
+	)
+	flags.StringVar(
+		&o.GlobalDNSCoreDNSEtcdCertFile,
+		"global-dns-coredns-etcd-cert-file",
+		"",
+		"The path of the client certificate presented to the etcd server.",
+	)
+	flags.StringVar(
+		&o.GlobalDNSCoreDNSEtcdKeyFile,
+		"global-dns-coredns-etcd-key-file",
+		"",
+		"The path of the private key of the client certificate presented to the etcd server.",
+	)
+
+	flags.StringVar(
+		&o.FederateMetadataPropagationConfig,
+		"federate-metadata-propagation-config",
+		"",
+		"The path of a YAML file containing the rules for classifying the labels and annotations of source objects, "+
+			"in the same format as the metadataPropagation field of FederatedTypeConfigs.",
+	)
+
+	flags.BoolVar(
+		&o.PausePropagation,
+		"pause-propagation",
+		false,
+		"Pause the propagation of all federated objects to member clusters. Propagation may also be paused at "+
+			"runtime with the propagation-pause ConfigMap in the fed system namespace.",
+	)
 
 	flags.Int64Var(&o.MaxPodListers, "max-pod-listers", 0, "The maximum number of concurrent pod listing requests to member clusters. "+
 		"A non-positive number means unlimited, but may increase the instantaneous memory usage.")
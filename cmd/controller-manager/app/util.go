@@ -17,9 +17,12 @@ limitations under the License.
 package app
 
 import (
+	"context"
+	"encoding/base64"
 	"fmt"
+	"os"
 	"regexp"
-	"time"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -27,13 +30,25 @@ import (
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/rest"
 
-	"github.com/kubewharf/kubeadmiral/cmd/controller-manager/app/options"
+	appconfig "github.com/kubewharf/kubeadmiral/cmd/controller-manager/app/config"
 	fedclient "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned"
 	fedinformers "github.com/kubewharf/kubeadmiral/pkg/client/informers/externalversions"
+	ctrlmgrconfig "github.com/kubewharf/kubeadmiral/pkg/controllermanager/apis/config"
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/sharding"
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/tuning"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
 	controllercontext "github.com/kubewharf/kubeadmiral/pkg/controllers/context"
+	dnsprovider "github.com/kubewharf/kubeadmiral/pkg/controllers/globaldns/provider"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/globaldns/provider/corednsetcd"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/globaldns/provider/rfc2136"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/monitor"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/nsautoprop"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/override"
+	statuscontroller "github.com/kubewharf/kubeadmiral/pkg/controllers/status"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/statusaggregator"
+	synccontroller "github.com/kubewharf/kubeadmiral/pkg/controllers/sync"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/federatedclient"
 	"github.com/kubewharf/kubeadmiral/pkg/stats"
@@ -77,20 +92,53 @@ func isControllerEnabled(name string, disabledByDefaultControllers sets.Set[stri
 	return true
 }
 
-func createControllerContext(opts *options.Options) (*controllercontext.Context, error) {
-	restConfig, err := clientcmd.BuildConfigFromFlags(opts.Master, opts.KubeConfig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create controller context: %w", err)
+// tuningNames returns the names that may be used in the tuning configuration of controllers.
+func tuningNames(fedClientset fedclient.Interface) tuning.Names {
+	return tuning.Names{
+		Controllers: sets.New(
+			FederatedClusterControllerName,
+			FollowerControllerName,
+			FederatedHPAControllerName,
+			MCSControllerName,
+			GlobalDNSControllerName,
+		),
+		FederatedTypeConfigControllers: sets.New(
+			FederateControllerName,
+			GlobalSchedulerName,
+			AutoMigrationControllerName,
+			PolicyRCControllerName,
+			ShardLabelerControllerName,
+			synccontroller.TuningName,
+			statuscontroller.TuningName,
+			statusaggregator.TuningName,
+			override.TuningName,
+			nsautoprop.TuningName,
+			monitor.TuningName,
+		),
+		FederatedTypeConfigs: func() (sets.Set[string], error) {
+			ftcList, err := fedClientset.CoreV1alpha1().FederatedTypeConfigs().List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			names := sets.New[string]()
+			for _, ftc := range ftcList.Items {
+				names.Insert(ftc.Name)
+			}
+			return names, nil
+		},
 	}
-	restConfig.QPS = opts.KubeAPIQPS
-	restConfig.Burst = opts.KubeAPIBurst
+}
+
+func createControllerContext(cfg *appconfig.Config) (*controllercontext.Context, error) {
+	restConfig := cfg.RestConfig
+	generic := cfg.ComponentConfig.Generic
 
-	componentConfig, err := getComponentConfig(opts)
+	componentConfig, err := getComponentConfig(cfg.ComponentConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create component config: %w", err)
 	}
 
-	metrics := stats.NewMock("", "kube-federation-manager", false)
+	metrics := stats.NewMock(cfg.ComponentConfig.Metrics.Env, "kube-federation-manager", cfg.ComponentConfig.Metrics.LogMetrics)
 
 	kubeClientset, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
@@ -110,26 +158,48 @@ func createControllerContext(opts *options.Options) (*controllercontext.Context,
 	dynamicInformerFactory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClientset, informerResyncPeriod)
 	fedInformerFactory := fedinformers.NewSharedInformerFactory(fedClientset, informerResyncPeriod)
 
+	tuningProvider := tuning.NewProvider(
+		tuning.Defaults{
+			WorkerCount:        generic.WorkerCount,
+			ClusterClientQPS:   generic.ClientConnection.QPS,
+			ClusterClientBurst: generic.ClientConnection.Burst,
+		},
+		tuningNames(fedClientset),
+	)
+	if generic.ControllerTuningConfigFile != "" {
+		if err := tuningProvider.LoadFile(generic.ControllerTuningConfigFile); err != nil {
+			return nil, fmt.Errorf("failed to load controller config: %w", err)
+		}
+	} else if err := tuningProvider.Load(&cfg.ComponentConfig.ControllerTuning); err != nil {
+		return nil, fmt.Errorf("failed to load controller config: %w", err)
+	}
+
+	// The clients of the federated client factory are shared by all controllers, so they only use the default settings.
+	clusterRestConfig := rest.CopyConfig(restConfig)
+	clusterRestConfig.QPS, clusterRestConfig.Burst = tuningProvider.ClusterClient("", "")
+
 	federatedClientFactory := federatedclient.NewFederatedClientsetFactory(
 		fedClientset,
 		kubeClientset,
 		fedInformerFactory.Core().V1alpha1().FederatedClusters(),
 		common.DefaultFedSystemNamespace,
-		restConfig,
-		opts.MaxPodListers,
-		opts.EnablePodPruning,
+		clusterRestConfig,
+		componentConfig.ClusterCredentialProviderPolicy,
+		generic.MaxPodListers,
+		generic.EnablePodPruning,
 	)
 
 	return &controllercontext.Context{
 		FedSystemNamespace: common.DefaultFedSystemNamespace,
 		TargetNamespace:    metav1.NamespaceAll,
 
-		WorkerCount:             opts.WorkerCount,
-		ClusterAvailableDelay:   20 * time.Second,
-		ClusterUnavailableDelay: 60 * time.Second,
+		WorkerCount:             int(generic.WorkerCount),
+		ClusterAvailableDelay:   generic.ClusterAvailableDelay.Duration,
+		ClusterUnavailableDelay: generic.ClusterUnavailableDelay.Duration,
 
 		RestConfig:      restConfig,
 		ComponentConfig: componentConfig,
+		Tuning:          tuningProvider,
 
 		Metrics: metrics,
 
@@ -140,18 +210,64 @@ func createControllerContext(opts *options.Options) (*controllercontext.Context,
 		DynamicInformerFactory: dynamicInformerFactory,
 		FedInformerFactory:     fedInformerFactory,
 
+		FederatedObjectInformerFactory: dynamicInformerFactory,
+
 		FederatedClientFactory: federatedClientFactory,
 	}, nil
 }
 
-func getComponentConfig(opts *options.Options) (*controllercontext.ComponentConfig, error) {
+// createShardControllerContext returns a copy of the controller context whose informers of federated objects are
+// limited to the given shard. Other informer factories are shared with the original context.
+func createShardControllerContext(
+	controllerCtx *controllercontext.Context,
+	shard *sharding.Shard,
+) *controllercontext.Context {
+	shardCtx := *controllerCtx
+	shardCtx.Shard = shard
+	shardCtx.FederatedObjectInformerFactory = dynamicinformer.NewFilteredDynamicSharedInformerFactory(
+		controllerCtx.DynamicClientset,
+		util.NoResyncPeriod,
+		controllerCtx.TargetNamespace,
+		shard.TweakListOptions,
+	)
+	return &shardCtx
+}
+
+func getComponentConfig(
+	cfg *ctrlmgrconfig.KubeAdmiralControllerManagerConfiguration,
+) (*controllercontext.ComponentConfig, error) {
+	cluster := cfg.FederatedClusterController
 	componentConfig := &controllercontext.ComponentConfig{
-		FederatedTypeConfigCreateCRDsForFTCs: opts.CreateCRDsForFTCs,
-		ClusterJoinTimeout:                   opts.ClusterJoinTimeout,
+		FederatedTypeConfigCreateCRDsForFTCs: cfg.TypeConfigController.CreateCRDsForFTCs,
+		ClusterJoinTimeout:                   cluster.JoinTimeout.Duration,
+		ClusterTokenRotationPeriod:           cluster.TokenRotationPeriod.Duration,
+		ClusterFlapDetectionWindow:           cluster.FlapDetectionWindow.Duration,
+		ClusterFlapDetectionThreshold:        int(cluster.FlapDetectionThreshold),
+		ClusterHealthCheckPeriod:             cluster.HealthCheck.Period.Duration,
+		ClusterHealthCheckPath:               cluster.HealthCheck.Path,
+		ClusterHealthCheckTimeout:            cluster.HealthCheck.Timeout.Duration,
+		ClusterHealthCheckSuccessThreshold:   int(cluster.HealthCheck.SuccessThreshold),
+		ClusterHealthCheckFailureThreshold:   int(cluster.HealthCheck.FailureThreshold),
+		ClusterAgentStatusTimeout:            cluster.AgentStatusTimeout.Duration,
+		ClusterCredentialProviderPolicy: &util.CredentialProviderPolicy{
+			AllowedExecCommands:         cluster.CredentialProviders.AllowedExecCommands,
+			AllowedTokenFileDirectories: cluster.CredentialProviders.AllowedTokenFileDirectories,
+		},
+		FederateMetadataPropagation:    cfg.FederateController.MetadataPropagation,
+		PausePropagation:               cfg.SyncController.PausePropagation,
+		MonitorOutOfSyncRecheckDelay:   cfg.MonitorController.OutOfSyncRecheckDelay.Duration,
+		SchedulerSupplyLimitProportion: cfg.Scheduler.SupplyLimitProportion,
+		SchedulerWebhookTimeout:        cfg.Scheduler.WebhookTimeout.Duration,
+	}
+
+	globalDNSProvider, err := newGlobalDNSProvider(&cfg.GlobalDNSController)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create global DNS provider: %w", err)
 	}
+	componentConfig.GlobalDNSProvider = globalDNSProvider
 
-	if opts.NSAutoPropExcludeRegexp != "" {
-		nsAutoPropExcludeRegexp, err := regexp.Compile(opts.NSAutoPropExcludeRegexp)
+	if expr := cfg.NamespaceAutoPropagationController.ExcludeRegexp; expr != "" {
+		nsAutoPropExcludeRegexp, err := regexp.Compile(expr)
 		if err != nil {
 			return nil, fmt.Errorf("failed to compile nsautoprop exclude regexp: %w", err)
 		}
@@ -160,3 +276,38 @@ func getComponentConfig(opts *options.Options) (*controllercontext.ComponentConf
 
 	return componentConfig, nil
 }
+
+func newGlobalDNSProvider(cfg *ctrlmgrconfig.GlobalDNSControllerConfiguration) (dnsprovider.Provider, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case "rfc2136":
+		var secret []byte
+		if cfg.RFC2136.TSIGSecretFile != "" {
+			data, err := os.ReadFile(cfg.RFC2136.TSIGSecretFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read TSIG secret: %w", err)
+			}
+			if secret, err = base64.StdEncoding.DecodeString(strings.TrimSpace(string(data))); err != nil {
+				return nil, fmt.Errorf("failed to decode TSIG secret: %w", err)
+			}
+		}
+		return rfc2136.NewProvider(rfc2136.Config{
+			Server:        cfg.RFC2136.Server,
+			Zone:          cfg.RFC2136.Zone,
+			TSIGKeyName:   cfg.RFC2136.TSIGKeyName,
+			TSIGAlgorithm: cfg.RFC2136.TSIGAlgorithm,
+			TSIGSecret:    secret,
+		})
+	case "coredns-etcd":
+		return corednsetcd.NewProvider(corednsetcd.Config{
+			Endpoint: cfg.CoreDNSEtcd.Endpoint,
+			Prefix:   cfg.CoreDNSEtcd.Prefix,
+			CAFile:   cfg.CoreDNSEtcd.CAFile,
+			CertFile: cfg.CoreDNSEtcd.CertFile,
+			KeyFile:  cfg.CoreDNSEtcd.KeyFile,
+		})
+	default:
+		return nil, fmt.Errorf("unknown DNS provider %q", cfg.Provider)
+	}
+}
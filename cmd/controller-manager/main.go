@@ -39,8 +39,13 @@ func main() {
 		klog.Infof("Flag: %v=%v", f.Name, f.Value.String())
 	})
 
+	cfg, err := opts.Config(flags)
+	if err != nil {
+		klog.Fatalf("Error loading config: %v", err)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	signals.SetupSignalHandler(cancel)
 
-	app.Run(ctx, opts)
+	app.Run(ctx, cfg)
 }
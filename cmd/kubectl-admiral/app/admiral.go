@@ -0,0 +1,224 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedclient "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	schemautil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/schema"
+)
+
+// options holds the flags shared by all subcommands.
+type options struct {
+	out io.Writer
+
+	loadingRules       *clientcmd.ClientConfigLoadingRules
+	configFlags        clientcmd.ClientConfig
+	fedSystemNamespace string
+
+	// newClients returns the clients of the host cluster. It is replaced by fake clients in tests.
+	newClients func() (*clients, error)
+}
+
+// clients holds the clients of the host cluster.
+type clients struct {
+	kubeClient    kubernetes.Interface
+	fedClient     fedclient.Interface
+	dynamicClient dynamic.Interface
+	restMapper    meta.ResettableRESTMapper
+	namespace     string
+}
+
+// NewAdmiralCommand returns the root command of the kubectl-admiral plugin.
+func NewAdmiralCommand(out io.Writer) *cobra.Command {
+	o := &options{out: out}
+	o.newClients = o.buildClients
+
+	cmd := &cobra.Command{
+		Use:           "kubectl-admiral",
+		Short:         "Inspect and operate a KubeAdmiral control plane",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	o.loadingRules = clientcmd.NewDefaultClientConfigLoadingRules()
+	overrides := &clientcmd.ConfigOverrides{}
+	cmd.PersistentFlags().StringVar(
+		&o.loadingRules.ExplicitPath,
+		"kubeconfig",
+		"",
+		"The path of the kubeconfig for the host cluster of the KubeAdmiral control plane.",
+	)
+	clientcmd.BindOverrideFlags(overrides, cmd.PersistentFlags(), clientcmd.RecommendedConfigOverrideFlags(""))
+	o.configFlags = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(o.loadingRules, overrides)
+
+	cmd.PersistentFlags().StringVar(
+		&o.fedSystemNamespace,
+		"fed-system-namespace",
+		common.DefaultFedSystemNamespace,
+		"The namespace of the KubeAdmiral control plane.",
+	)
+
+	cmd.AddCommand(
+		newJoinCommand(o),
+		newUnjoinCommand(o),
+		newGetCommand(o),
+		newDescribeCommand(o),
+		newPendingCommand(o),
+		newClustersCommand(o),
+		newMaintenanceCommand(o, "cordon", fedcorev1a1.ClusterMaintenanceModeCordon),
+		newMaintenanceCommand(o, "drain", fedcorev1a1.ClusterMaintenanceModeDrain),
+		newMaintenanceCommand(o, "uncordon", ""),
+		newBackupCommand(o),
+		newRestoreCommand(o),
+		newResumeCommand(o),
+	)
+
+	return cmd
+}
+
+func (o *options) clients() (*clients, error) {
+	return o.newClients()
+}
+
+// buildClients returns the clients of the host cluster configured by the kubeconfig flags.
+func (o *options) buildClients() (*clients, error) {
+	restConfig, err := o.configFlags.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	namespace, _, err := o.configFlags.Namespace()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get namespace: %w", err)
+	}
+
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	fedClient, err := fedclient.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &clients{
+		kubeClient:    kubeClient,
+		fedClient:     fedClient,
+		dynamicClient: dynamicClient,
+		restMapper:    restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(kubeClient.Discovery())),
+		namespace:     namespace,
+	}, nil
+}
+
+// resolveTypeConfig returns the FederatedTypeConfig whose source or federated type is referred to by resource.
+// resource may be the plural name, the plural name qualified by the group (e.g. deployments.apps) or the kind of
+// the type, or the name of the FederatedTypeConfig.
+func (c *clients) resolveTypeConfig(ctx context.Context, resource string) (*fedcorev1a1.FederatedTypeConfig, error) {
+	ftcList, err := c.fedClient.CoreV1alpha1().FederatedTypeConfigs().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list FederatedTypeConfigs: %w", err)
+	}
+
+	var matches []*fedcorev1a1.FederatedTypeConfig
+	for i := range ftcList.Items {
+		ftc := &ftcList.Items[i]
+
+		types := []metav1.APIResource{ftc.GetFederatedType()}
+		if sourceType := ftc.GetSourceType(); sourceType != nil {
+			types = append(types, *sourceType)
+		}
+
+		matched := ftc.Name == resource
+		for _, apiResource := range types {
+			matched = matched || apiResourceMatches(&apiResource, resource)
+		}
+		if matched {
+			matches = append(matches, ftc)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no FederatedTypeConfig found for resource %q", resource)
+	case 1:
+		return matches[0], nil
+	default:
+		names := make([]string, 0, len(matches))
+		for _, ftc := range matches {
+			names = append(names, ftc.Name)
+		}
+		return nil, fmt.Errorf(
+			"resource %q is ambiguous, it matches FederatedTypeConfigs %s",
+			resource,
+			strings.Join(names, ", "),
+		)
+	}
+}
+
+func apiResourceMatches(apiResource *metav1.APIResource, resource string) bool {
+	qualifiedName := apiResource.Name
+	if apiResource.Group != "" {
+		qualifiedName += "." + apiResource.Group
+	}
+
+	return strings.EqualFold(resource, apiResource.Name) ||
+		strings.EqualFold(resource, qualifiedName) ||
+		strings.EqualFold(resource, apiResource.Kind)
+}
+
+// getFederatedObject returns the federated object of the given type and name.
+func (c *clients) getFederatedObject(
+	ctx context.Context,
+	ftc *fedcorev1a1.FederatedTypeConfig,
+	name string,
+) (*unstructured.Unstructured, error) {
+	federatedType := ftc.GetFederatedType()
+	resourceClient := c.dynamicClient.Resource(schemautil.APIResourceToGVR(&federatedType))
+
+	var obj *unstructured.Unstructured
+	var err error
+	if ftc.GetNamespaced() {
+		obj, err = resourceClient.Namespace(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	} else {
+		obj, err = resourceClient.Get(ctx, name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %q: %w", federatedType.Kind, name, err)
+	}
+
+	return obj, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kubewharf/kubeadmiral/pkg/backup"
+)
+
+type backupOptions struct {
+	*options
+
+	output string
+}
+
+func newBackupCommand(o *options) *cobra.Command {
+	bo := &backupOptions{options: o}
+
+	cmd := &cobra.Command{
+		Use:   "backup -o FILE",
+		Short: "Back up the state of the control plane",
+		Long: "Back up the FederatedTypeConfigs, policies, federated objects, propagated versions and member " +
+			"clusters of the control plane, together with the secrets of the clusters, to a gzipped tar archive. " +
+			"The archive contains credentials of the member clusters and must be stored securely.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return bo.run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVarP(&bo.output, "output", "o", "", "The path of the archive to write.")
+	_ = cmd.MarkFlagRequired("output")
+
+	return cmd
+}
+
+func (o *backupOptions) run(ctx context.Context) error {
+	c, err := o.clients()
+	if err != nil {
+		return err
+	}
+
+	archive, err := backup.Backup(ctx, c.dynamicClient, o.fedSystemNamespace)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(o.output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	if err := archive.Write(file); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	for _, resources := range archive.Resources {
+		fmt.Fprintf(o.out, "%s: %d backed up\n", resources.String(), len(resources.Items))
+	}
+	return nil
+}
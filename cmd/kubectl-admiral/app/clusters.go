@@ -0,0 +1,115 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+func newClustersCommand(o *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "clusters",
+		Short: "List member clusters with their resources",
+		Long: "List member clusters with their conditions, sync mode, maintenance mode, number of schedulable " +
+			"nodes, and available and allocatable CPU and memory.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.runClusters(cmd.Context())
+		},
+	}
+}
+
+func (o *options) runClusters(ctx context.Context) error {
+	c, err := o.clients()
+	if err != nil {
+		return err
+	}
+
+	clusterList, err := c.fedClient.CoreV1alpha1().FederatedClusters().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list FederatedClusters: %w", err)
+	}
+	clusters := clusterList.Items
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].Name < clusters[j].Name
+	})
+
+	w := tabwriter.NewWriter(o.out, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tREADY\tJOINED\tMODE\tMAINTENANCE\tNODES\tCPU\tMEMORY\tAGE")
+	for i := range clusters {
+		cluster := &clusters[i]
+
+		mode := cluster.Spec.SyncMode
+		if mode == "" {
+			mode = fedcorev1a1.ClusterSyncModePush
+		}
+		maintenance := "-"
+		if cluster.Spec.Maintenance != nil {
+			maintenance = string(cluster.Spec.Maintenance.Mode)
+		}
+
+		resources := &cluster.Status.Resources
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			cluster.Name,
+			clusterConditionStatus(cluster, fedcorev1a1.ClusterReady),
+			clusterConditionStatus(cluster, fedcorev1a1.ClusterJoined),
+			mode,
+			maintenance,
+			optionalInt64(resources.SchedulableNodes),
+			formatAvailableResource(resources, corev1.ResourceCPU),
+			formatAvailableResource(resources, corev1.ResourceMemory),
+			duration.HumanDuration(time.Since(cluster.CreationTimestamp.Time)),
+		)
+	}
+	return w.Flush()
+}
+
+func clusterConditionStatus(
+	cluster *fedcorev1a1.FederatedCluster,
+	conditionType fedcorev1a1.ClusterConditionType,
+) corev1.ConditionStatus {
+	for _, condition := range cluster.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status
+		}
+	}
+	return corev1.ConditionUnknown
+}
+
+// formatAvailableResource returns the available and allocatable quantities of a resource in the form
+// available/allocatable.
+func formatAvailableResource(resources *fedcorev1a1.Resources, name corev1.ResourceName) string {
+	available, hasAvailable := resources.Available[name]
+	allocatable, hasAllocatable := resources.Allocatable[name]
+	if !hasAvailable && !hasAllocatable {
+		return "-"
+	}
+	return fmt.Sprintf("%s/%s", available.String(), allocatable.String())
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedfake "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned/fake"
+)
+
+func TestClusters(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	created := metav1.NewTime(time.Now().Add(-3 * 24 * time.Hour))
+	fedClient := fedfake.NewSimpleClientset(
+		&fedcorev1a1.FederatedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "member-2", CreationTimestamp: created},
+			Spec: fedcorev1a1.FederatedClusterSpec{
+				SyncMode:    fedcorev1a1.ClusterSyncModePull,
+				Maintenance: &fedcorev1a1.ClusterMaintenance{Mode: fedcorev1a1.ClusterMaintenanceModeCordon},
+			},
+		},
+		&fedcorev1a1.FederatedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "member-1", CreationTimestamp: created},
+			Status: fedcorev1a1.FederatedClusterStatus{
+				Conditions: []fedcorev1a1.ClusterCondition{
+					{Type: fedcorev1a1.ClusterReady, Status: corev1.ConditionTrue},
+					{Type: fedcorev1a1.ClusterJoined, Status: corev1.ConditionTrue},
+				},
+				Resources: fedcorev1a1.Resources{
+					SchedulableNodes: pointer.Int64(3),
+					Allocatable: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("12"),
+						corev1.ResourceMemory: resource.MustParse("48Gi"),
+					},
+					Available: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("5"),
+						corev1.ResourceMemory: resource.MustParse("10Gi"),
+					},
+				},
+			},
+		},
+	)
+	o, out := newTestOptions(kubefake.NewSimpleClientset(), fedClient)
+
+	g.Expect(o.runClusters(context.Background())).To(gomega.Succeed())
+
+	rows := [][]string{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		rows = append(rows, strings.Fields(line))
+	}
+	g.Expect(rows).To(gomega.Equal([][]string{
+		{"NAME", "READY", "JOINED", "MODE", "MAINTENANCE", "NODES", "CPU", "MEMORY", "AGE"},
+		{"member-1", "True", "True", "Push", "-", "3", "5/12", "10Gi/48Gi", "3d"},
+		{"member-2", "Unknown", "Unknown", "Pull", "Cordon", "-", "-", "-", "3d"},
+	}))
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/pendingcontrollers"
+)
+
+func newDescribeCommand(o *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "describe RESOURCE NAME",
+		Short: "Show the propagation status and overrides of an object",
+		Long: "Show the pending controllers, conditions, per-cluster propagation status, placement and overrides " +
+			"of the federated object of an object.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.runDescribe(cmd.Context(), args[0], args[1])
+		},
+	}
+}
+
+func (o *options) runDescribe(ctx context.Context, resource, name string) error {
+	c, err := o.clients()
+	if err != nil {
+		return err
+	}
+
+	ftc, err := c.resolveTypeConfig(ctx, resource)
+	if err != nil {
+		return err
+	}
+
+	fedObject, err := c.getFederatedObject(ctx, ftc, name)
+	if err != nil {
+		return err
+	}
+
+	return describeFederatedObject(o.out, ftc, fedObject)
+}
+
+func describeFederatedObject(
+	out io.Writer,
+	ftc *fedcorev1a1.FederatedTypeConfig,
+	fedObject *unstructured.Unstructured,
+) error {
+	statusObj := &fedtypesv1a1.GenericObjectWithStatus{}
+	if err := util.UnstructuredToInterface(fedObject, statusObj); err != nil {
+		return fmt.Errorf("failed to unmarshal status: %w", err)
+	}
+	status := statusObj.Status
+	if status == nil {
+		status = &fedtypesv1a1.GenericFederatedStatus{}
+	}
+
+	overridesObj, err := util.UnmarshalGenericOverrides(fedObject)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal overrides: %w", err)
+	}
+
+	placements, err := clusterPlacements(ftc, fedObject)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintf(w, "Name:\t%s\n", fedObject.GetName())
+	if namespace := fedObject.GetNamespace(); namespace != "" {
+		fmt.Fprintf(w, "Namespace:\t%s\n", namespace)
+	}
+	fmt.Fprintf(w, "Kind:\t%s\n", fedObject.GetKind())
+	fmt.Fprintf(w, "Generation:\t%d\n", fedObject.GetGeneration())
+	fmt.Fprintf(w, "Synced Generation:\t%d\n", status.SyncedGeneration)
+	fmt.Fprintf(w, "Pending Controllers:\t%s\n", formatPendingControllers(fedObject))
+	if followers, exists := fedObject.GetAnnotations()[common.FollowersAnnotation]; exists {
+		fmt.Fprintf(w, "Followers:\t%s\n", followers)
+	}
+
+	fmt.Fprintln(w, "Conditions:")
+	if len(status.Conditions) == 0 {
+		fmt.Fprintln(w, "  <none>")
+	} else {
+		fmt.Fprintln(w, "  TYPE\tSTATUS\tREASON\tLAST UPDATE")
+		for _, condition := range status.Conditions {
+			fmt.Fprintf(
+				w,
+				"  %s\t%s\t%s\t%s\n",
+				condition.Type,
+				condition.Status,
+				condition.Reason,
+				condition.LastUpdateTime,
+			)
+		}
+	}
+
+	fmt.Fprintln(w, "Placement:")
+	if len(placements) == 0 {
+		fmt.Fprintln(w, "  <none>")
+	} else {
+		fmt.Fprintln(w, "  CLUSTER\tREPLICAS\tCAPACITY\tCONTROLLERS")
+		for _, placement := range placements {
+			fmt.Fprintf(
+				w,
+				"  %s\t%s\t%s\t%s\n",
+				placement.cluster,
+				optionalInt64(placement.replicas),
+				optionalInt64(placement.capacity),
+				strings.Join(placement.controllers, ","),
+			)
+		}
+	}
+
+	fmt.Fprintln(w, "Clusters:")
+	if len(status.Clusters) == 0 {
+		fmt.Fprintln(w, "  <none>")
+	} else {
+		fmt.Fprintln(w, "  CLUSTER\tSTATUS\tGENERATION\tDRIFTED FIELDS")
+		for _, cluster := range status.Clusters {
+			driftedFields := "-"
+			if len(cluster.DriftedFields) > 0 {
+				driftedFields = strings.Join(cluster.DriftedFields, ",")
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%d\t%s\n", cluster.Name, cluster.Status, cluster.Generation, driftedFields)
+		}
+	}
+
+	fmt.Fprintln(w, "Overrides:")
+	if overridesObj.Spec == nil || len(overridesObj.Spec.Overrides) == 0 {
+		fmt.Fprintln(w, "  <none>")
+	} else {
+		fmt.Fprintln(w, "  CONTROLLER\tCLUSTER\tOP\tPATH\tVALUE")
+		for _, controllerOverride := range overridesObj.Spec.Overrides {
+			for _, clusterOverride := range controllerOverride.Clusters {
+				for _, patch := range clusterOverride.Patches {
+					op := patch.Op
+					if op == "" {
+						op = "replace"
+					}
+					value, err := json.Marshal(patch.Value)
+					if err != nil {
+						return fmt.Errorf("failed to marshal override value: %w", err)
+					}
+					fmt.Fprintf(
+						w,
+						"  %s\t%s\t%s\t%s\t%s\n",
+						controllerOverride.Controller,
+						clusterOverride.ClusterName,
+						op,
+						patch.Path,
+						value,
+					)
+				}
+			}
+		}
+	}
+
+	return w.Flush()
+}
+
+// formatPendingControllers returns the pending controllers of a federated object, with the controllers of each
+// step separated by commas and the steps separated by semicolons.
+func formatPendingControllers(fedObject *unstructured.Unstructured) string {
+	if _, exists := fedObject.GetAnnotations()[pendingcontrollers.PendingControllersAnnotation]; !exists {
+		return "<none>"
+	}
+
+	controllers, err := pendingcontrollers.GetPendingControllers(fedObject)
+	if err != nil {
+		return fmt.Sprintf("<invalid: %v>", err)
+	}
+	if len(controllers) == 0 {
+		return "<none>"
+	}
+
+	steps := make([]string, 0, len(controllers))
+	for _, step := range controllers {
+		steps = append(steps, strings.Join(step, ","))
+	}
+	return strings.Join(steps, ";")
+}
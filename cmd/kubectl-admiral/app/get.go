@@ -0,0 +1,196 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	utilunstructured "github.com/kubewharf/kubeadmiral/pkg/controllers/util/unstructured"
+)
+
+func newGetCommand(o *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Display federation information of objects",
+	}
+
+	cmd.AddCommand(newGetPlacementCommand(o))
+
+	return cmd
+}
+
+func newGetPlacementCommand(o *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "placement RESOURCE NAME",
+		Short: "Display the clusters in which an object is placed",
+		Long: "Display the clusters in which an object is placed, the replicas assigned to each cluster by the " +
+			"scheduler, the replicas each cluster is estimated to accommodate by auto migration and the controllers " +
+			"that placed the object in each cluster.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.runGetPlacement(cmd.Context(), args[0], args[1])
+		},
+	}
+}
+
+func (o *options) runGetPlacement(ctx context.Context, resource, name string) error {
+	c, err := o.clients()
+	if err != nil {
+		return err
+	}
+
+	ftc, err := c.resolveTypeConfig(ctx, resource)
+	if err != nil {
+		return err
+	}
+
+	fedObject, err := c.getFederatedObject(ctx, ftc, name)
+	if err != nil {
+		return err
+	}
+
+	placements, err := clusterPlacements(ftc, fedObject)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(o.out, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "CLUSTER\tREPLICAS\tCAPACITY\tCONTROLLERS")
+	for _, placement := range placements {
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\n",
+			placement.cluster,
+			optionalInt64(placement.replicas),
+			optionalInt64(placement.capacity),
+			strings.Join(placement.controllers, ","),
+		)
+	}
+	return w.Flush()
+}
+
+// clusterPlacement describes the placement of an object in a cluster.
+type clusterPlacement struct {
+	cluster     string
+	controllers []string
+	// replicas is the number of replicas assigned to the cluster by the scheduler, if any.
+	replicas *int64
+	// capacity is the number of replicas the cluster is estimated to accommodate by auto migration, if any.
+	capacity *int64
+}
+
+func clusterPlacements(
+	ftc *fedcorev1a1.FederatedTypeConfig,
+	fedObject *unstructured.Unstructured,
+) ([]clusterPlacement, error) {
+	placementObj, err := util.UnmarshalGenericPlacements(fedObject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal placements: %w", err)
+	}
+
+	controllers := map[string]sets.Set[string]{}
+	for _, placement := range placementObj.Spec.Placements {
+		for _, cluster := range placement.Placement.Clusters {
+			if controllers[cluster.Name] == nil {
+				controllers[cluster.Name] = sets.New[string]()
+			}
+			controllers[cluster.Name].Insert(placement.Controller)
+		}
+	}
+
+	replicas := map[string]int64{}
+	if replicasPath := ftc.Spec.PathDefinition.ReplicasSpec; replicasPath != "" {
+		overrides, err := util.GetOverrides(fedObject, scheduler.PrefixedGlobalSchedulerName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get overrides: %w", err)
+		}
+		for cluster, patches := range overrides {
+			for _, patch := range patches {
+				if patch.Path != utilunstructured.ToSlashPath(replicasPath) {
+					continue
+				}
+				if value, ok := toInt64(patch.Value); ok {
+					replicas[cluster] = value
+				}
+			}
+		}
+	}
+
+	autoMigrationInfo := &framework.AutoMigrationInfo{}
+	if value, exists := fedObject.GetAnnotations()[common.AutoMigrationInfoAnnotation]; exists {
+		if err := json.Unmarshal([]byte(value), autoMigrationInfo); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal auto migration info: %w", err)
+		}
+	}
+
+	result := make([]clusterPlacement, 0, len(controllers))
+	for cluster, clusterControllers := range controllers {
+		placement := clusterPlacement{
+			cluster:     cluster,
+			controllers: sets.List(clusterControllers),
+		}
+		if value, exists := replicas[cluster]; exists {
+			placement.replicas = &value
+		}
+		if value, exists := autoMigrationInfo.EstimatedCapacity[cluster]; exists {
+			placement.capacity = &value
+		}
+		result = append(result, placement)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].cluster < result[j].cluster
+	})
+
+	return result, nil
+}
+
+func toInt64(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case int32:
+		return int64(v), true
+	case int:
+		return int64(v), true
+	case float64:
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func optionalInt64(value *int64) string {
+	if value == nil {
+		return "-"
+	}
+	return strconv.FormatInt(*value, 10)
+}
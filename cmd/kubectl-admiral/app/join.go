@@ -0,0 +1,344 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+type joinOptions struct {
+	*options
+
+	clusterKubeConfig string
+	clusterContext    string
+	useServiceAccount bool
+	insecure          bool
+}
+
+func newJoinCommand(o *options) *cobra.Command {
+	jo := &joinOptions{options: o}
+
+	cmd := &cobra.Command{
+		Use:   "join CLUSTER_NAME",
+		Short: "Join a member cluster using the credentials of a kubeconfig context",
+		Long: "Join a member cluster using the credentials of a kubeconfig context. The context may authenticate " +
+			"with a client certificate, a bearer token, an exec credential plugin or the oidc auth provider. A " +
+			"secret containing the credentials and a FederatedCluster object are created in the host cluster.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return jo.run(cmd.Context(), args[0])
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&jo.clusterKubeConfig,
+		"cluster-kubeconfig",
+		"",
+		"The path of the kubeconfig for the member cluster. Defaults to the kubeconfig of the host cluster.",
+	)
+	cmd.Flags().StringVar(
+		&jo.clusterContext,
+		"cluster-context",
+		"",
+		"The context of the member cluster in the kubeconfig. Defaults to the current context.",
+	)
+	cmd.Flags().BoolVar(
+		&jo.useServiceAccount,
+		"use-service-account",
+		true,
+		"Access the member cluster with a service account created by KubeAdmiral instead of the client certificate "+
+			"or bearer token. Ignored for exec plugins and the oidc auth provider, which are used for all access.",
+	)
+	cmd.Flags().BoolVar(&jo.insecure, "insecure", false, "Skip verifying the certificate of the member cluster.")
+
+	return cmd
+}
+
+func (o *joinOptions) run(ctx context.Context, clusterName string) error {
+	c, err := o.clients()
+	if err != nil {
+		return err
+	}
+
+	clusterConfig, err := o.clusterRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	if len(clusterConfig.CAData) == 0 && !o.insecure {
+		return fmt.Errorf("the kubeconfig context of the member cluster has no certificate authority, use --insecure")
+	}
+
+	data, credentialProvider, err := clusterCredentials(clusterConfig)
+	if err != nil {
+		return err
+	}
+	if len(clusterConfig.CAData) > 0 {
+		data[common.ClusterCertificateAuthorityKey] = clusterConfig.CAData
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      clusterName,
+			Namespace: o.fedSystemNamespace,
+		},
+		Data: data,
+	}
+
+	if _, err := c.kubeClient.CoreV1().Secrets(o.fedSystemNamespace).Create(
+		ctx,
+		secret,
+		metav1.CreateOptions{},
+	); err != nil {
+		return fmt.Errorf("failed to create cluster secret: %w", err)
+	}
+	fmt.Fprintf(o.out, "secret/%s created\n", clusterName)
+
+	cluster := &fedcorev1a1.FederatedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterName,
+		},
+		Spec: fedcorev1a1.FederatedClusterSpec{
+			APIEndpoint:            clusterConfig.Host,
+			Insecure:               o.insecure,
+			UseServiceAccountToken: o.useServiceAccount && credentialProvider == nil,
+			SecretRef: fedcorev1a1.LocalSecretReference{
+				Name: clusterName,
+			},
+			CredentialProvider: credentialProvider,
+		},
+	}
+	if _, err := c.fedClient.CoreV1alpha1().FederatedClusters().Create(
+		ctx,
+		cluster,
+		metav1.CreateOptions{},
+	); err != nil {
+		// roll back the secret so that the join can be retried
+		deleteErr := c.kubeClient.CoreV1().Secrets(o.fedSystemNamespace).Delete(ctx, clusterName, metav1.DeleteOptions{})
+		if deleteErr != nil && !apierrors.IsNotFound(deleteErr) {
+			return fmt.Errorf(
+				"failed to create FederatedCluster: %w; failed to delete secret/%s, delete it before retrying: %v",
+				err,
+				clusterName,
+				deleteErr,
+			)
+		}
+		fmt.Fprintf(o.out, "secret/%s deleted\n", clusterName)
+		return fmt.Errorf("failed to create FederatedCluster: %w", err)
+	}
+	fmt.Fprintf(o.out, "federatedcluster/%s created\n", clusterName)
+
+	return nil
+}
+
+// clusterCredentials returns the data of the cluster secret and the credential provider of the FederatedCluster for
+// the credentials of the member cluster's kubeconfig context. Client certificates and bearer tokens are stored in the
+// secret, while exec plugins and the oidc auth provider are converted to credential providers. The certificate
+// authority is not included.
+func clusterCredentials(
+	clusterConfig *restclient.Config,
+) (map[string][]byte, *fedcorev1a1.ClusterCredentialProvider, error) {
+	switch {
+	case len(clusterConfig.CertData) > 0 || len(clusterConfig.KeyData) > 0:
+		if len(clusterConfig.CertData) == 0 || len(clusterConfig.KeyData) == 0 {
+			return nil, nil, fmt.Errorf("the client certificate of the member cluster has no matching key")
+		}
+		return map[string][]byte{
+			common.ClusterClientCertificateKey: clusterConfig.CertData,
+			common.ClusterClientKeyKey:         clusterConfig.KeyData,
+		}, nil, nil
+
+	case len(clusterConfig.BearerToken) > 0 || len(clusterConfig.BearerTokenFile) > 0:
+		token := []byte(clusterConfig.BearerToken)
+		if len(token) == 0 {
+			var err error
+			if token, err = os.ReadFile(clusterConfig.BearerTokenFile); err != nil {
+				return nil, nil, fmt.Errorf("failed to read the token file of the member cluster: %w", err)
+			}
+		}
+		return map[string][]byte{common.ClusterBearerTokenKey: bytes.TrimSpace(token)}, nil, nil
+
+	case clusterConfig.ExecProvider != nil:
+		exec := clusterConfig.ExecProvider
+		env := make([]fedcorev1a1.ExecEnvVar, 0, len(exec.Env))
+		for _, envVar := range exec.Env {
+			env = append(env, fedcorev1a1.ExecEnvVar{Name: envVar.Name, Value: envVar.Value})
+		}
+		return map[string][]byte{}, &fedcorev1a1.ClusterCredentialProvider{
+			Exec: &fedcorev1a1.ExecCredentialProvider{
+				Command:    exec.Command,
+				Args:       exec.Args,
+				Env:        env,
+				APIVersion: exec.APIVersion,
+			},
+		}, nil
+
+	case clusterConfig.AuthProvider != nil && clusterConfig.AuthProvider.Name == "oidc":
+		config := clusterConfig.AuthProvider.Config
+		if config["idp-certificate-authority"] != "" || config["idp-certificate-authority-data"] != "" {
+			return nil, nil, fmt.Errorf("oidc issuers with a custom certificate authority are not supported")
+		}
+		if config["refresh-token"] == "" {
+			return nil, nil, fmt.Errorf("the oidc auth provider of the member cluster has no refresh token")
+		}
+
+		data := map[string][]byte{common.ClusterOIDCRefreshTokenKey: []byte(config["refresh-token"])}
+		if config["client-secret"] != "" {
+			data[common.ClusterOIDCClientSecretKey] = []byte(config["client-secret"])
+		}
+		if config["id-token"] != "" {
+			data[common.ClusterOIDCIDTokenKey] = []byte(config["id-token"])
+		}
+
+		var extraScopes []string
+		if config["extra-scopes"] != "" {
+			extraScopes = strings.Split(config["extra-scopes"], ",")
+		}
+		return data, &fedcorev1a1.ClusterCredentialProvider{
+			OIDC: &fedcorev1a1.OIDCCredentialProvider{
+				IssuerURL:   config["idp-issuer-url"],
+				ClientID:    config["client-id"],
+				ExtraScopes: extraScopes,
+			},
+		}, nil
+
+	case clusterConfig.AuthProvider != nil:
+		return nil, nil, fmt.Errorf("the %q auth provider of the member cluster is not supported", clusterConfig.AuthProvider.Name)
+
+	default:
+		return nil, nil, fmt.Errorf(
+			"the kubeconfig context of the member cluster must authenticate with a client certificate, a bearer " +
+				"token, an exec credential plugin or the oidc auth provider",
+		)
+	}
+}
+
+// clusterRESTConfig returns the rest config of the member cluster with the contents of certificate files inlined.
+func (o *joinOptions) clusterRESTConfig() (*restclient.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.loadingRules.ExplicitPath
+	if o.clusterKubeConfig != "" {
+		loadingRules.ExplicitPath = o.clusterKubeConfig
+	}
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: o.clusterContext}
+
+	clusterConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig of the member cluster: %w", err)
+	}
+	if err := restclient.LoadTLSFiles(clusterConfig); err != nil {
+		return nil, fmt.Errorf("failed to load certificates of the member cluster: %w", err)
+	}
+
+	return clusterConfig, nil
+}
+
+type unjoinOptions struct {
+	*options
+
+	wait       bool
+	timeout    time.Duration
+	keepSecret bool
+}
+
+func newUnjoinCommand(o *options) *cobra.Command {
+	uo := &unjoinOptions{options: o}
+
+	cmd := &cobra.Command{
+		Use:   "unjoin CLUSTER_NAME",
+		Short: "Remove a member cluster from the federation",
+		Long: "Remove a member cluster from the federation by deleting its FederatedCluster object. Once the " +
+			"cluster has been cleaned up and removed, its secret is deleted as well.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return uo.run(cmd.Context(), args[0])
+		},
+	}
+
+	cmd.Flags().BoolVar(
+		&uo.wait,
+		"wait",
+		true,
+		"Wait for the FederatedCluster to be removed. The secret of the cluster is only deleted if true.",
+	)
+	cmd.Flags().DurationVar(&uo.timeout, "timeout", 5*time.Minute, "The maximum time to wait for the removal.")
+	cmd.Flags().BoolVar(&uo.keepSecret, "keep-secret", false, "Keep the secret of the cluster.")
+
+	return cmd
+}
+
+func (o *unjoinOptions) run(ctx context.Context, clusterName string) error {
+	c, err := o.clients()
+	if err != nil {
+		return err
+	}
+
+	clusterClient := c.fedClient.CoreV1alpha1().FederatedClusters()
+	cluster, err := clusterClient.Get(ctx, clusterName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get FederatedCluster: %w", err)
+	}
+	secretName := cluster.Spec.SecretRef.Name
+
+	if err := clusterClient.Delete(ctx, clusterName, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("failed to delete FederatedCluster: %w", err)
+	}
+	fmt.Fprintf(o.out, "federatedcluster/%s deleted\n", clusterName)
+
+	if !o.wait {
+		return nil
+	}
+
+	// The secret is still required to clean up the member cluster until the FederatedCluster is removed.
+	if err := wait.PollImmediate(time.Second, o.timeout, func() (bool, error) {
+		_, err := clusterClient.Get(ctx, clusterName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}); err != nil {
+		return fmt.Errorf("failed to wait for FederatedCluster to be removed: %w", err)
+	}
+
+	if o.keepSecret || secretName == "" {
+		return nil
+	}
+
+	err = c.kubeClient.CoreV1().Secrets(o.fedSystemNamespace).Delete(ctx, secretName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete cluster secret: %w", err)
+	}
+	fmt.Fprintf(o.out, "secret/%s deleted\n", secretName)
+
+	return nil
+}
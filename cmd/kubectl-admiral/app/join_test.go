@@ -0,0 +1,284 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedfake "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned/fake"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+const testNamespace = "kube-admiral-system"
+
+// newTestOptions returns options using the fake clientsets, along with the buffer the output of commands is written to.
+func newTestOptions(
+	kubeClient *kubefake.Clientset,
+	fedClient *fedfake.Clientset,
+) (*options, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	o := &options{
+		out:                out,
+		loadingRules:       clientcmd.NewDefaultClientConfigLoadingRules(),
+		fedSystemNamespace: testNamespace,
+	}
+	o.newClients = func() (*clients, error) {
+		return &clients{kubeClient: kubeClient, fedClient: fedClient, namespace: metav1.NamespaceDefault}, nil
+	}
+	return o, out
+}
+
+// writeMemberKubeConfig writes a kubeconfig of a member cluster authenticating with the given auth info and returns its
+// path.
+func writeMemberKubeConfig(g *gomega.WithT, dir string, authInfo *clientcmdapi.AuthInfo) string {
+	config := clientcmdapi.NewConfig()
+	config.Clusters["member"] = &clientcmdapi.Cluster{
+		Server:                   "https://member.example.com:6443",
+		CertificateAuthorityData: []byte("ca"),
+	}
+	config.AuthInfos["member"] = authInfo
+	config.Contexts["member"] = &clientcmdapi.Context{Cluster: "member", AuthInfo: "member"}
+	config.CurrentContext = "member"
+
+	path := filepath.Join(dir, "member.kubeconfig")
+	g.Expect(clientcmd.WriteToFile(*config, path)).To(gomega.Succeed())
+	return path
+}
+
+// certAuthInfo authenticates with a client certificate.
+var certAuthInfo = &clientcmdapi.AuthInfo{
+	ClientCertificateData: []byte("cert"),
+	ClientKeyData:         []byte("key"),
+}
+
+func TestJoin(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		authInfo                  *clientcmdapi.AuthInfo
+		expectedData              map[string][]byte
+		expectedProvider          *fedcorev1a1.ClusterCredentialProvider
+		expectedUseServiceAccount bool
+		expectedErrorSubstring    string
+	}{
+		"client certificate": {
+			authInfo: certAuthInfo,
+			expectedData: map[string][]byte{
+				common.ClusterCertificateAuthorityKey: []byte("ca"),
+				common.ClusterClientCertificateKey:    []byte("cert"),
+				common.ClusterClientKeyKey:            []byte("key"),
+			},
+			expectedUseServiceAccount: true,
+		},
+		"bearer token": {
+			authInfo: &clientcmdapi.AuthInfo{Token: "token"},
+			expectedData: map[string][]byte{
+				common.ClusterCertificateAuthorityKey: []byte("ca"),
+				common.ClusterBearerTokenKey:          []byte("token"),
+			},
+			expectedUseServiceAccount: true,
+		},
+		"token file": {
+			authInfo: &clientcmdapi.AuthInfo{TokenFile: tokenFile},
+			expectedData: map[string][]byte{
+				common.ClusterCertificateAuthorityKey: []byte("ca"),
+				common.ClusterBearerTokenKey:          []byte("file-token"),
+			},
+			expectedUseServiceAccount: true,
+		},
+		"exec plugin": {
+			authInfo: &clientcmdapi.AuthInfo{Exec: &clientcmdapi.ExecConfig{
+				Command:         "get-token",
+				Args:            []string{"--cluster", "member"},
+				Env:             []clientcmdapi.ExecEnvVar{{Name: "REGION", Value: "us"}},
+				APIVersion:      "client.authentication.k8s.io/v1",
+				InteractiveMode: clientcmdapi.IfAvailableExecInteractiveMode,
+			}},
+			expectedData: map[string][]byte{
+				common.ClusterCertificateAuthorityKey: []byte("ca"),
+			},
+			expectedProvider: &fedcorev1a1.ClusterCredentialProvider{
+				Exec: &fedcorev1a1.ExecCredentialProvider{
+					Command:    "get-token",
+					Args:       []string{"--cluster", "member"},
+					Env:        []fedcorev1a1.ExecEnvVar{{Name: "REGION", Value: "us"}},
+					APIVersion: "client.authentication.k8s.io/v1",
+				},
+			},
+		},
+		"oidc auth provider": {
+			authInfo: &clientcmdapi.AuthInfo{AuthProvider: &clientcmdapi.AuthProviderConfig{
+				Name: "oidc",
+				Config: map[string]string{
+					"idp-issuer-url": "https://issuer.example.com",
+					"client-id":      "kubeadmiral",
+					"client-secret":  "client-secret",
+					"refresh-token":  "refresh",
+					"id-token":       "id",
+					"extra-scopes":   "groups,email",
+				},
+			}},
+			expectedData: map[string][]byte{
+				common.ClusterCertificateAuthorityKey: []byte("ca"),
+				common.ClusterOIDCRefreshTokenKey:     []byte("refresh"),
+				common.ClusterOIDCClientSecretKey:     []byte("client-secret"),
+				common.ClusterOIDCIDTokenKey:          []byte("id"),
+			},
+			expectedProvider: &fedcorev1a1.ClusterCredentialProvider{
+				OIDC: &fedcorev1a1.OIDCCredentialProvider{
+					IssuerURL:   "https://issuer.example.com",
+					ClientID:    "kubeadmiral",
+					ExtraScopes: []string{"groups", "email"},
+				},
+			},
+		},
+		"oidc auth provider without refresh token": {
+			authInfo: &clientcmdapi.AuthInfo{AuthProvider: &clientcmdapi.AuthProviderConfig{
+				Name:   "oidc",
+				Config: map[string]string{"idp-issuer-url": "https://issuer.example.com", "client-id": "kubeadmiral"},
+			}},
+			expectedErrorSubstring: "no refresh token",
+		},
+		"unsupported auth provider": {
+			authInfo:               &clientcmdapi.AuthInfo{AuthProvider: &clientcmdapi.AuthProviderConfig{Name: "gcp"}},
+			expectedErrorSubstring: `"gcp" auth provider`,
+		},
+		"basic auth": {
+			authInfo:               &clientcmdapi.AuthInfo{Username: "admin", Password: "password"},
+			expectedErrorSubstring: "must authenticate with",
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			ctx := context.Background()
+
+			kubeClient := kubefake.NewSimpleClientset()
+			fedClient := fedfake.NewSimpleClientset()
+			o, out := newTestOptions(kubeClient, fedClient)
+			jo := &joinOptions{
+				options:           o,
+				clusterKubeConfig: writeMemberKubeConfig(g, t.TempDir(), tc.authInfo),
+				useServiceAccount: true,
+			}
+
+			err := jo.run(ctx, "member-1")
+			if tc.expectedErrorSubstring != "" {
+				g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring(tc.expectedErrorSubstring)))
+				g.Expect(kubeClient.Actions()).To(gomega.BeEmpty())
+				return
+			}
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(out.String()).To(gomega.Equal("secret/member-1 created\nfederatedcluster/member-1 created\n"))
+
+			secret, err := kubeClient.CoreV1().Secrets(testNamespace).Get(ctx, "member-1", metav1.GetOptions{})
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(secret.Data).To(gomega.Equal(tc.expectedData))
+
+			cluster, err := fedClient.CoreV1alpha1().FederatedClusters().Get(ctx, "member-1", metav1.GetOptions{})
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(cluster.Spec.APIEndpoint).To(gomega.Equal("https://member.example.com:6443"))
+			g.Expect(cluster.Spec.UseServiceAccountToken).To(gomega.Equal(tc.expectedUseServiceAccount))
+			g.Expect(cluster.Spec.CredentialProvider).To(gomega.Equal(tc.expectedProvider))
+			g.Expect(cluster.Spec.SecretRef.Name).To(gomega.Equal("member-1"))
+		})
+	}
+}
+
+func TestJoinRollsBackSecret(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctx := context.Background()
+
+	kubeClient := kubefake.NewSimpleClientset()
+	fedClient := fedfake.NewSimpleClientset()
+	fedClient.PrependReactor("create", "federatedclusters", func(clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("admission webhook denied the request")
+	})
+	o, out := newTestOptions(kubeClient, fedClient)
+	jo := &joinOptions{options: o, clusterKubeConfig: writeMemberKubeConfig(g, t.TempDir(), certAuthInfo)}
+
+	g.Expect(jo.run(ctx, "member-1")).To(gomega.MatchError(gomega.ContainSubstring("admission webhook denied")))
+	g.Expect(out.String()).To(gomega.Equal("secret/member-1 created\nsecret/member-1 deleted\n"))
+
+	_, err := kubeClient.CoreV1().Secrets(testNamespace).Get(ctx, "member-1", metav1.GetOptions{})
+	g.Expect(apierrors.IsNotFound(err)).To(gomega.BeTrue())
+}
+
+func TestUnjoin(t *testing.T) {
+	testCases := map[string]struct {
+		keepSecret           bool
+		expectedOutput       string
+		expectSecretToRemain bool
+	}{
+		"secret is deleted": {
+			expectedOutput: "federatedcluster/member-1 deleted\nsecret/member-1 deleted\n",
+		},
+		"secret is kept": {
+			keepSecret:           true,
+			expectedOutput:       "federatedcluster/member-1 deleted\n",
+			expectSecretToRemain: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			ctx := context.Background()
+
+			kubeClient := kubefake.NewSimpleClientset(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "member-1", Namespace: testNamespace},
+			})
+			fedClient := fedfake.NewSimpleClientset(&fedcorev1a1.FederatedCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "member-1"},
+				Spec:       fedcorev1a1.FederatedClusterSpec{SecretRef: fedcorev1a1.LocalSecretReference{Name: "member-1"}},
+			})
+			o, out := newTestOptions(kubeClient, fedClient)
+			uo := &unjoinOptions{options: o, wait: true, timeout: wait.ForeverTestTimeout, keepSecret: tc.keepSecret}
+
+			g.Expect(uo.run(ctx, "member-1")).To(gomega.Succeed())
+			g.Expect(out.String()).To(gomega.Equal(tc.expectedOutput))
+
+			_, err := fedClient.CoreV1alpha1().FederatedClusters().Get(ctx, "member-1", metav1.GetOptions{})
+			g.Expect(apierrors.IsNotFound(err)).To(gomega.BeTrue())
+			_, err = kubeClient.CoreV1().Secrets(testNamespace).Get(ctx, "member-1", metav1.GetOptions{})
+			g.Expect(err == nil).To(gomega.Equal(tc.expectSecretToRemain))
+		})
+	}
+}
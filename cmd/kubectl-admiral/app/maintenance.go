@@ -0,0 +1,92 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+// newMaintenanceCommand returns a command that puts member clusters into the maintenance mode, or takes them out of
+// maintenance if mode is empty.
+func newMaintenanceCommand(o *options, use string, mode fedcorev1a1.ClusterMaintenanceMode) *cobra.Command {
+	short := fmt.Sprintf("Put member clusters into %s maintenance mode", mode)
+	long := fmt.Sprintf(
+		"Put member clusters into %s maintenance mode by setting spec.maintenance of their FederatedCluster "+
+			"objects. The progress of the maintenance is reported by the UnderMaintenance condition.",
+		mode,
+	)
+	if mode == "" {
+		short = "Take member clusters out of maintenance mode"
+		long = "Take member clusters out of maintenance mode by removing spec.maintenance of their FederatedCluster " +
+			"objects."
+	}
+
+	return &cobra.Command{
+		Use:   use + " CLUSTER_NAME...",
+		Short: short,
+		Long:  long,
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.runMaintenance(cmd.Context(), use, mode, args)
+		},
+	}
+}
+
+func (o *options) runMaintenance(
+	ctx context.Context,
+	verb string,
+	mode fedcorev1a1.ClusterMaintenanceMode,
+	clusterNames []string,
+) error {
+	c, err := o.clients()
+	if err != nil {
+		return err
+	}
+
+	var maintenance *fedcorev1a1.ClusterMaintenance
+	if mode != "" {
+		maintenance = &fedcorev1a1.ClusterMaintenance{Mode: mode}
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"maintenance": maintenance},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, clusterName := range clusterNames {
+		if _, err := c.fedClient.CoreV1alpha1().FederatedClusters().Patch(
+			ctx,
+			clusterName,
+			types.MergePatchType,
+			patch,
+			metav1.PatchOptions{},
+		); err != nil {
+			return fmt.Errorf("failed to %s FederatedCluster %q: %w", verb, clusterName, err)
+		}
+		fmt.Fprintf(o.out, "federatedcluster/%s %sed\n", clusterName, verb)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedfake "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned/fake"
+)
+
+func TestMaintenance(t *testing.T) {
+	testCases := map[string]struct {
+		verb                string
+		mode                fedcorev1a1.ClusterMaintenanceMode
+		existing            *fedcorev1a1.ClusterMaintenance
+		expectedMaintenance *fedcorev1a1.ClusterMaintenance
+		expectedOutput      string
+	}{
+		"cordon": {
+			verb:                "cordon",
+			mode:                fedcorev1a1.ClusterMaintenanceModeCordon,
+			expectedMaintenance: &fedcorev1a1.ClusterMaintenance{Mode: fedcorev1a1.ClusterMaintenanceModeCordon},
+			expectedOutput:      "federatedcluster/member-1 cordoned\n",
+		},
+		"drain a cordoned cluster": {
+			verb:                "drain",
+			mode:                fedcorev1a1.ClusterMaintenanceModeDrain,
+			existing:            &fedcorev1a1.ClusterMaintenance{Mode: fedcorev1a1.ClusterMaintenanceModeCordon},
+			expectedMaintenance: &fedcorev1a1.ClusterMaintenance{Mode: fedcorev1a1.ClusterMaintenanceModeDrain},
+			expectedOutput:      "federatedcluster/member-1 drained\n",
+		},
+		"uncordon a drained cluster": {
+			verb:           "uncordon",
+			existing:       &fedcorev1a1.ClusterMaintenance{Mode: fedcorev1a1.ClusterMaintenanceModeDrain},
+			expectedOutput: "federatedcluster/member-1 uncordoned\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			ctx := context.Background()
+
+			fedClient := fedfake.NewSimpleClientset(&fedcorev1a1.FederatedCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "member-1"},
+				Spec:       fedcorev1a1.FederatedClusterSpec{Maintenance: tc.existing},
+			})
+			o, out := newTestOptions(kubefake.NewSimpleClientset(), fedClient)
+
+			g.Expect(o.runMaintenance(ctx, tc.verb, tc.mode, []string{"member-1"})).To(gomega.Succeed())
+			g.Expect(out.String()).To(gomega.Equal(tc.expectedOutput))
+
+			cluster, err := fedClient.CoreV1alpha1().FederatedClusters().Get(ctx, "member-1", metav1.GetOptions{})
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(cluster.Spec.Maintenance).To(gomega.Equal(tc.expectedMaintenance))
+
+			err = o.runMaintenance(ctx, tc.verb, tc.mode, []string{"member-2"})
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("member-2")))
+		})
+	}
+}
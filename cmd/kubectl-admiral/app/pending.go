@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	schemautil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/schema"
+)
+
+type pendingOptions struct {
+	*options
+
+	allNamespaces bool
+}
+
+func newPendingCommand(o *options) *cobra.Command {
+	po := &pendingOptions{options: o}
+
+	cmd := &cobra.Command{
+		Use:   "pending [RESOURCE]",
+		Short: "List federated objects with pending controllers",
+		Long: "List the federated objects whose pending controllers have not all completed, e.g. objects that " +
+			"cannot be scheduled. If RESOURCE is omitted, the objects of all federated types are listed.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resource := ""
+			if len(args) > 0 {
+				resource = args[0]
+			}
+			return po.run(cmd.Context(), resource)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&po.allNamespaces, "all-namespaces", "A", false, "List objects in all namespaces.")
+
+	return cmd
+}
+
+func (o *pendingOptions) run(ctx context.Context, resource string) error {
+	c, err := o.clients()
+	if err != nil {
+		return err
+	}
+
+	var ftcs []*fedcorev1a1.FederatedTypeConfig
+	if resource != "" {
+		ftc, err := c.resolveTypeConfig(ctx, resource)
+		if err != nil {
+			return err
+		}
+		ftcs = append(ftcs, ftc)
+	} else {
+		ftcList, err := c.fedClient.CoreV1alpha1().FederatedTypeConfigs().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to list FederatedTypeConfigs: %w", err)
+		}
+		for i := range ftcList.Items {
+			ftcs = append(ftcs, &ftcList.Items[i])
+		}
+	}
+
+	var pendingObjects []*unstructured.Unstructured
+	for _, ftc := range ftcs {
+		federatedType := ftc.GetFederatedType()
+		resourceClient := c.dynamicClient.Resource(schemautil.APIResourceToGVR(&federatedType))
+
+		var objects *unstructured.UnstructuredList
+		if ftc.GetNamespaced() && !o.allNamespaces {
+			objects, err = resourceClient.Namespace(c.namespace).List(ctx, metav1.ListOptions{})
+		} else {
+			objects, err = resourceClient.List(ctx, metav1.ListOptions{})
+		}
+		if apierrors.IsNotFound(err) {
+			// The CRD of the federated type is not installed.
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list %s: %w", federatedType.Kind, err)
+		}
+
+		for i := range objects.Items {
+			object := &objects.Items[i]
+			if formatPendingControllers(object) != "<none>" {
+				pendingObjects = append(pendingObjects, object)
+			}
+		}
+	}
+
+	sort.Slice(pendingObjects, func(i, j int) bool {
+		a, b := pendingObjects[i], pendingObjects[j]
+		if a.GetKind() != b.GetKind() {
+			return a.GetKind() < b.GetKind()
+		}
+		if a.GetNamespace() != b.GetNamespace() {
+			return a.GetNamespace() < b.GetNamespace()
+		}
+		return a.GetName() < b.GetName()
+	})
+
+	w := tabwriter.NewWriter(o.out, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tKIND\tNAME\tPENDING CONTROLLERS\tAGE")
+	for _, object := range pendingObjects {
+		namespace := object.GetNamespace()
+		if namespace == "" {
+			namespace = "-"
+		}
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\t%s\n",
+			namespace,
+			object.GetKind(),
+			object.GetName(),
+			formatPendingControllers(object),
+			duration.HumanDuration(time.Since(object.GetCreationTimestamp().Time)),
+		)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/kubewharf/kubeadmiral/pkg/backup"
+)
+
+type restoreOptions struct {
+	*options
+
+	file       string
+	skipResume bool
+	timeout    time.Duration
+}
+
+func newRestoreCommand(o *options) *cobra.Command {
+	ro := &restoreOptions{options: o}
+
+	cmd := &cobra.Command{
+		Use:   "restore -f FILE",
+		Short: "Restore the state of the control plane from a backup",
+		Long: "Restore the objects in an archive created by the backup command. Objects that already exist are " +
+			"left unchanged. Federated objects are paused until all member clusters are ready, so that objects " +
+			"in member clusters are neither updated nor deleted while the control plane is partially restored.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return ro.run(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVarP(&ro.file, "filename", "f", "", "The path of the archive to restore.")
+	_ = cmd.MarkFlagRequired("filename")
+	cmd.Flags().BoolVar(
+		&ro.skipResume,
+		"skip-resume",
+		false,
+		"Leave federated objects paused after restoring them. They can be resumed with the resume command.",
+	)
+	cmd.Flags().DurationVar(
+		&ro.timeout,
+		"timeout",
+		5*time.Minute,
+		"The maximum time to wait for each member cluster to become ready.",
+	)
+
+	return cmd
+}
+
+func (o *restoreOptions) run(ctx context.Context) error {
+	c, err := o.clients()
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(o.file)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	archive, err := backup.ReadArchive(file)
+	_ = file.Close()
+	if err != nil {
+		return err
+	}
+
+	if archive.Metadata.FedSystemNamespace != o.fedSystemNamespace {
+		return fmt.Errorf(
+			"the archive was created from the control plane in namespace %s, not %s",
+			archive.Metadata.FedSystemNamespace,
+			o.fedSystemNamespace,
+		)
+	}
+
+	restorer := backup.NewRestorer(c.dynamicClient, c.restMapper, o.out)
+	if err := restorer.Restore(ctx, archive); err != nil {
+		return err
+	}
+
+	if o.skipResume {
+		fmt.Fprintln(o.out, "Federated objects are paused, run \"kubectl admiral resume\" to resume them")
+		return nil
+	}
+
+	if err := restorer.WaitForClusters(ctx, archive, o.timeout); err != nil {
+		return fmt.Errorf(
+			"%w; federated objects remain paused, run \"kubectl admiral resume\" to resume them",
+			err,
+		)
+	}
+	return restorer.Resume(ctx)
+}
+
+func newResumeCommand(o *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "resume",
+		Short: "Resume the federated objects paused by restore",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := o.clients()
+			if err != nil {
+				return err
+			}
+			return backup.NewRestorer(c.dynamicClient, c.restMapper, o.out).Resume(cmd.Context())
+		},
+	}
+}
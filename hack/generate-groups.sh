@@ -34,7 +34,7 @@ groups=(
 )
 
 # install code-generator binaries
-go install k8s.io/code-generator/cmd/{client-gen,lister-gen,informer-gen,deepcopy-gen}@${CODEGEN_VERSION}
+go install k8s.io/code-generator/cmd/{client-gen,lister-gen,informer-gen,deepcopy-gen,defaulter-gen,conversion-gen}@${CODEGEN_VERSION}
 go install sigs.k8s.io/controller-tools/cmd/controller-gen@${CONTROLLERGEN_VERSION}
 go install github.com/mikefarah/yq/v4@${YQ_VERSION}
 
@@ -52,6 +52,12 @@ for group in "${groups[@]}"; do
   INPUT_DIRS+=("${INPUT_BASE}/${group}")
 done
 
+# component configuration of the controller manager, which has an internal version and is not served as a CRD
+CONFIG_INTERNAL_DIR="${MODULE_NAME}/pkg/controllermanager/apis/config"
+CONFIG_VERSIONED_DIRS=(
+  "${CONFIG_INTERNAL_DIR}/v1alpha1"
+)
+
 # generate code
 function codegen::join() { local IFS="$1"; shift; echo "$*"; }
 
@@ -76,10 +82,24 @@ done
 # generate deepcopy
 echo "Generating deepcopy funcs"
 ${GOBIN}/deepcopy-gen -h ${HEADER_FILE} -o ${OUTPUT_DIR} \
-  --input-dirs=$(codegen::join , "${INPUT_DIRS[@]}") \
+  --input-dirs=$(codegen::join , "${INPUT_DIRS[@]}" "${CONFIG_INTERNAL_DIR}" "${CONFIG_VERSIONED_DIRS[@]}") \
   --output-file-base="zz_generated.deepcopy" \
   "$@"
 
+# generate defaulters
+echo "Generating defaulters"
+${GOBIN}/defaulter-gen -h ${HEADER_FILE} -o ${OUTPUT_DIR} \
+  --input-dirs=$(codegen::join , "${CONFIG_VERSIONED_DIRS[@]}") \
+  --output-file-base="zz_generated.defaults" \
+  "$@"
+
+# generate conversions
+echo "Generating conversions"
+${GOBIN}/conversion-gen -h ${HEADER_FILE} -o ${OUTPUT_DIR} \
+  --input-dirs=$(codegen::join , "${CONFIG_VERSIONED_DIRS[@]}") \
+  --output-file-base="zz_generated.conversion" \
+  "$@"
+
 # generate client
 CLIENT_OUTPUT_PACKAGE="${MODULE_NAME}/pkg/client/clientset"
 
@@ -0,0 +1,236 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package agent implements the agent of a pull-mode member cluster. The agent runs inside the member cluster, watches
+// the federated objects placed in the cluster on the host cluster, applies them locally and reports the status of the
+// objects and the cluster back to the host cluster.
+package agent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	dynamicclient "k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedclient "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned"
+	"github.com/kubewharf/kubeadmiral/pkg/client/generic"
+	fedcorev1a1informers "github.com/kubewharf/kubeadmiral/pkg/client/informers/externalversions/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/federatedcluster"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/delayingdeliver"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/eventsink"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/worker"
+	"github.com/kubewharf/kubeadmiral/pkg/stats"
+)
+
+const (
+	AgentName = "cluster-agent"
+)
+
+// HostClients are the clients used by the agent to access the host cluster.
+type HostClients struct {
+	KubeClient    kubeclient.Interface
+	DynamicClient dynamicclient.Interface
+	FedClient     fedclient.Interface
+}
+
+// MemberClients are the clients used by the agent to access its member cluster.
+type MemberClients struct {
+	KubeClient    kubeclient.Interface
+	DynamicClient dynamicclient.Interface
+	GenericClient generic.Client
+}
+
+// Agent syncs the federated objects placed in a pull-mode member cluster and reports the status of the cluster.
+type Agent struct {
+	clusterName string
+
+	host   HostClients
+	member MemberClients
+
+	typeConfigInformer fedcorev1a1informers.FederatedTypeConfigInformer
+	memberKubeInformer informers.SharedInformerFactory
+
+	statusReportPeriod time.Duration
+	healthCheckConfig  *federatedcluster.ClusterHealthCheckConfig
+	workerCount        int
+	// namespace is the namespace in the member cluster in which the agent persists its state.
+	namespace string
+
+	// clusterTerminating is set once the FederatedCluster of the member cluster is being deleted.
+	clusterTerminating atomic.Bool
+
+	lock            sync.Mutex
+	syncerCancels   map[string]context.CancelFunc
+	syncerObjectIDs map[string]string
+
+	worker        worker.ReconcileWorker
+	eventRecorder record.EventRecorder
+	metrics       stats.Metrics
+	logger        klog.Logger
+}
+
+func NewAgent(
+	clusterName string,
+	host HostClients,
+	member MemberClients,
+	typeConfigInformer fedcorev1a1informers.FederatedTypeConfigInformer,
+	memberKubeInformer informers.SharedInformerFactory,
+	statusReportPeriod time.Duration,
+	healthCheckConfig *federatedcluster.ClusterHealthCheckConfig,
+	metrics stats.Metrics,
+	workerCount int,
+	namespace string,
+) *Agent {
+	if healthCheckConfig == nil {
+		healthCheckConfig = federatedcluster.DefaultClusterHealthCheckConfig()
+	}
+
+	a := &Agent{
+		clusterName:        clusterName,
+		host:               host,
+		member:             member,
+		typeConfigInformer: typeConfigInformer,
+		memberKubeInformer: memberKubeInformer,
+		statusReportPeriod: statusReportPeriod,
+		healthCheckConfig:  healthCheckConfig,
+		workerCount:        workerCount,
+		namespace:          namespace,
+		syncerCancels:      map[string]context.CancelFunc{},
+		syncerObjectIDs:    map[string]string{},
+		eventRecorder:      eventsink.NewDefederatingRecorderMux(host.KubeClient, AgentName, 4),
+		metrics:            metrics,
+		logger:             klog.LoggerWithValues(klog.Background(), "controller", AgentName, "cluster-name", clusterName),
+	}
+
+	a.worker = worker.NewReconcileWorker(
+		a.reconcileTypeConfig,
+		worker.WorkerTiming{},
+		1,
+		metrics,
+		delayingdeliver.NewMetricTags(AgentName, "FederatedTypeConfig"),
+	)
+
+	typeConfigInformer.Informer().AddEventHandler(util.NewTriggerOnAllChanges(a.worker.EnqueueObject))
+
+	// The informers used for cluster status collection must be accessed before the factory is started.
+	memberKubeInformer.Core().V1().Pods().Informer()
+	memberKubeInformer.Core().V1().Nodes().Informer()
+
+	return a
+}
+
+func (a *Agent) IsControllerReady() bool {
+	return a.typeConfigInformer.Informer().HasSynced()
+}
+
+func (a *Agent) Run(ctx context.Context) {
+	a.logger.Info("Starting agent")
+	defer a.logger.Info("Stopping agent")
+
+	a.memberKubeInformer.Start(ctx.Done())
+
+	if !cache.WaitForNamedCacheSync(AgentName, ctx.Done(), a.typeConfigInformer.Informer().HasSynced) {
+		return
+	}
+
+	go wait.UntilWithContext(ctx, a.reportClusterStatus, a.statusReportPeriod)
+
+	a.worker.Run(ctx.Done())
+	<-ctx.Done()
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	for name, cancel := range a.syncerCancels {
+		cancel()
+		delete(a.syncerCancels, name)
+	}
+}
+
+// reconcileTypeConfig starts a syncer for each FederatedTypeConfig with propagation enabled, and stops the syncer
+// when the FederatedTypeConfig is deleted or changed.
+func (a *Agent) reconcileTypeConfig(qualifiedName common.QualifiedName) (status worker.Result) {
+	logger := a.logger.WithValues("federated-type-config", qualifiedName.String())
+	startTime := time.Now()
+
+	logger.V(3).Info("Starting reconcile")
+	defer func() {
+		logger.WithValues("duration", time.Since(startTime), "status", status.String()).V(3).Info("Finished reconcile")
+	}()
+
+	typeConfig, err := a.typeConfigInformer.Lister().Get(qualifiedName.Name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		logger.Error(err, "Failed to get FederatedTypeConfig")
+		return worker.StatusError
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	var objectID string
+	if typeConfig != nil && typeConfig.GetPropagationEnabled() {
+		objectID = syncerObjectID(typeConfig)
+	}
+	if a.syncerObjectIDs[qualifiedName.Name] == objectID {
+		return worker.StatusAllOK
+	}
+
+	if cancel, ok := a.syncerCancels[qualifiedName.Name]; ok {
+		logger.V(2).Info("Stopping syncer")
+		cancel()
+		delete(a.syncerCancels, qualifiedName.Name)
+		delete(a.syncerObjectIDs, qualifiedName.Name)
+	}
+	if objectID == "" {
+		return worker.StatusAllOK
+	}
+
+	syncer := newResourceSyncer(
+		a.clusterName,
+		typeConfig.DeepCopy(),
+		a.host,
+		a.member,
+		a.eventRecorder,
+		a.metrics,
+		a.workerCount,
+		a.namespace,
+		a.clusterTerminating.Load,
+	)
+	ctx, cancel := context.WithCancel(context.TODO())
+	a.syncerCancels[qualifiedName.Name] = cancel
+	a.syncerObjectIDs[qualifiedName.Name] = objectID
+	go syncer.Run(ctx)
+
+	logger.V(2).Info("Started syncer")
+	return worker.StatusAllOK
+}
+
+// syncerObjectID identifies the parts of a FederatedTypeConfig that require the syncer to be restarted when changed.
+func syncerObjectID(typeConfig *fedcorev1a1.FederatedTypeConfig) string {
+	return fmt.Sprintf("%s/%d", typeConfig.UID, typeConfig.Generation)
+}
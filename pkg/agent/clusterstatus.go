@@ -0,0 +1,175 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/federatedcluster"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/managedlabel"
+	schemautil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/schema"
+)
+
+// reportClusterStatus collects the status of the member cluster and writes it to the FederatedCluster object on the
+// host cluster. The first report also marks the cluster as joined.
+func (a *Agent) reportClusterStatus(ctx context.Context) {
+	logger := a.logger.WithValues("control-loop", "status-report")
+	ctx = klog.NewContext(ctx, logger)
+	startTime := time.Now()
+
+	logger.V(3).Info("Starting cluster status report")
+	defer func() {
+		logger.WithValues("duration", time.Since(startTime)).V(3).Info("Finished cluster status report")
+	}()
+
+	cluster, err := a.host.FedClient.CoreV1alpha1().FederatedClusters().Get(ctx, a.clusterName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		logger.V(2).Info("Cluster is deleted, stopping syncing")
+		a.clusterTerminating.Store(true)
+		return
+	}
+	if err != nil {
+		logger.Error(err, "Failed to get cluster")
+		return
+	}
+	if !util.IsPullModeCluster(cluster) {
+		logger.Error(nil, "Cluster is not in pull mode, skipping status report")
+		return
+	}
+	if cluster.GetDeletionTimestamp() != nil {
+		logger.V(3).Info("Cluster is terminating, skipping status report")
+		a.clusterTerminating.Store(true)
+		if util.IsCascadingDeleteEnabled(cluster) {
+			a.cascadeDelete(ctx, cluster)
+		}
+		return
+	}
+
+	status := cluster.Status.DeepCopy()
+	federatedcluster.SetClusterJoinedByAgent(status)
+	federatedcluster.CollectClusterStatus(
+		ctx,
+		status,
+		a.member.KubeClient,
+		a.memberKubeInformer,
+		a.healthCheckConfig.ProbeConfigForCluster(cluster),
+	)
+	if typeConfigs, err := a.typeConfigInformer.Lister().List(labels.Everything()); err != nil {
+		logger.Error(err, "Failed to list FederatedTypeConfigs")
+	} else if err := federatedcluster.CollectClusterMaintenanceStatus(
+		ctx, cluster, status, a.member.DynamicClient, typeConfigs,
+	); err != nil {
+		logger.Error(err, "Failed to collect cluster maintenance status")
+	}
+
+	if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		latestCluster, err := a.host.FedClient.CoreV1alpha1().FederatedClusters().Get(ctx, a.clusterName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		status.DeepCopyInto(&latestCluster.Status)
+		_, err = a.host.FedClient.CoreV1alpha1().FederatedClusters().UpdateStatus(ctx, latestCluster, metav1.UpdateOptions{})
+		return err
+	}); err != nil {
+		logger.Error(err, "Failed to update cluster status")
+	}
+}
+
+// cascadeDelete deletes the objects managed by KubeAdmiral from the member cluster of a terminating FederatedCluster,
+// and marks the FederatedCluster once all of them are gone so that the sync controllers can release the cluster.
+func (a *Agent) cascadeDelete(ctx context.Context, cluster *fedcorev1a1.FederatedCluster) {
+	logger := klog.FromContext(ctx)
+	if _, completed := cluster.GetAnnotations()[common.AgentCascadingDeleteCompletedAnnotation]; completed {
+		return
+	}
+
+	typeConfigs, err := a.typeConfigInformer.Lister().List(labels.Everything())
+	if err != nil {
+		logger.Error(err, "Failed to list FederatedTypeConfigs")
+		return
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{
+		managedlabel.ManagedByKubeAdmiralLabelKey: managedlabel.ManagedByKubeAdmiralLabelValue,
+	}).String()
+	remaining := 0
+	for _, typeConfig := range typeConfigs {
+		if !typeConfig.GetPropagationEnabled() {
+			continue
+		}
+
+		targetType := typeConfig.GetTargetType()
+		client := a.member.DynamicClient.Resource(schemautil.APIResourceToGVR(&targetType))
+		objects, err := client.List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+				continue
+			}
+			logger.Error(err, "Failed to list managed objects", "ftc", typeConfig.Name)
+			return
+		}
+
+		for i := range objects.Items {
+			object := &objects.Items[i]
+			remaining++
+			if object.GetDeletionTimestamp() != nil {
+				continue
+			}
+			if err := client.Namespace(object.GetNamespace()).Delete(
+				ctx, object.GetName(), metav1.DeleteOptions{},
+			); err != nil && !apierrors.IsNotFound(err) {
+				logger.Error(err, "Failed to delete managed object", "ftc", typeConfig.Name, "object",
+					common.NewQualifiedName(object).String())
+			}
+		}
+	}
+
+	if remaining > 0 {
+		logger.V(2).Info("Waiting for cascading delete of managed objects", "remaining", remaining)
+		return
+	}
+
+	if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		latestCluster, err := a.host.FedClient.CoreV1alpha1().FederatedClusters().Get(ctx, a.clusterName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		annotations := latestCluster.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[common.AgentCascadingDeleteCompletedAnnotation] = "true"
+		latestCluster.SetAnnotations(annotations)
+		_, err = a.host.FedClient.CoreV1alpha1().FederatedClusters().Update(ctx, latestCluster, metav1.UpdateOptions{})
+		return err
+	}); err != nil && !apierrors.IsNotFound(err) {
+		logger.Error(err, "Failed to mark cascading delete as completed")
+		return
+	}
+	logger.V(2).Info("Cascading delete completed")
+}
@@ -0,0 +1,591 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/client/generic"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	synccontroller "github.com/kubewharf/kubeadmiral/pkg/controllers/sync"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/sync/dispatch"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/sync/status"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/delayingdeliver"
+	finalizersutil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/finalizers"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/managedlabel"
+	schemautil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/schema"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/worker"
+	"github.com/kubewharf/kubeadmiral/pkg/stats"
+)
+
+const (
+	// statusObjectRecheckDelay is the delay before retrying to report the status of an object if its federated
+	// status object has not been created by the status controller yet.
+	statusObjectRecheckDelay = 10 * time.Second
+	// versionFlushPeriod is the period at which the propagated versions are persisted.
+	versionFlushPeriod = 5 * time.Second
+)
+
+// resourceSyncer syncs the federated objects of a FederatedTypeConfig that are placed in the member cluster.
+type resourceSyncer struct {
+	name        string
+	clusterName string
+	typeConfig  *fedcorev1a1.FederatedTypeConfig
+
+	host   HostClients
+	member MemberClients
+
+	federatedInformer     cache.SharedIndexInformer
+	clusterObjectInformer cache.SharedIndexInformer
+
+	// versions are the versions of the objects last propagated to the member cluster.
+	versions *versionStore
+	// clusterTerminating returns true if the FederatedCluster of the member cluster is being deleted.
+	clusterTerminating func() bool
+
+	worker        worker.ReconcileWorker
+	eventRecorder record.EventRecorder
+	metrics       stats.Metrics
+	logger        klog.Logger
+}
+
+func newResourceSyncer(
+	clusterName string,
+	typeConfig *fedcorev1a1.FederatedTypeConfig,
+	host HostClients,
+	member MemberClients,
+	eventRecorder record.EventRecorder,
+	metrics stats.Metrics,
+	workerCount int,
+	versionNamespace string,
+	clusterTerminating func() bool,
+) *resourceSyncer {
+	name := fmt.Sprintf("%s-syncer", typeConfig.Name)
+	s := &resourceSyncer{
+		name:               name,
+		clusterName:        clusterName,
+		typeConfig:         typeConfig,
+		host:               host,
+		member:             member,
+		versions:           newVersionStore(member.KubeClient, versionNamespace, typeConfig.Name),
+		clusterTerminating: clusterTerminating,
+		eventRecorder:      eventRecorder,
+		metrics:            metrics,
+		logger: klog.LoggerWithValues(
+			klog.Background(),
+			"controller", AgentName,
+			"cluster-name", clusterName,
+			"ftc", typeConfig.Name,
+		),
+	}
+
+	s.worker = worker.NewReconcileWorker(
+		s.reconcile,
+		worker.WorkerTiming{},
+		workerCount,
+		metrics,
+		delayingdeliver.NewMetricTags(name, typeConfig.GetFederatedType().Kind),
+	)
+
+	// only the federated objects placed in the member cluster are labeled with its key by the sync controller
+	federatedType := typeConfig.GetFederatedType()
+	s.federatedInformer = dynamicinformer.NewFilteredDynamicInformer(
+		host.DynamicClient,
+		schemautil.APIResourceToGVR(&federatedType),
+		metav1.NamespaceAll,
+		util.NoResyncPeriod,
+		cache.Indexers{},
+		func(options *metav1.ListOptions) {
+			options.LabelSelector = util.PullClusterKey(clusterName)
+		},
+	).Informer()
+	s.federatedInformer.AddEventHandler(util.NewTriggerOnAllChanges(s.worker.EnqueueObject))
+
+	targetType := typeConfig.GetTargetType()
+	s.clusterObjectInformer = dynamicinformer.NewFilteredDynamicInformer(
+		member.DynamicClient,
+		schemautil.APIResourceToGVR(&targetType),
+		metav1.NamespaceAll,
+		util.NoResyncPeriod,
+		cache.Indexers{},
+		func(options *metav1.ListOptions) {
+			options.LabelSelector = labels.SelectorFromSet(labels.Set{
+				managedlabel.ManagedByKubeAdmiralLabelKey: managedlabel.ManagedByKubeAdmiralLabelValue,
+			}).String()
+		},
+	).Informer()
+	s.clusterObjectInformer.AddEventHandler(util.NewTriggerOnAllChanges(s.worker.EnqueueObject))
+
+	return s
+}
+
+func (s *resourceSyncer) Run(ctx context.Context) {
+	s.logger.Info("Starting syncer")
+	defer s.logger.Info("Stopping syncer")
+
+	// The versions must be loaded before any object is synced, otherwise all objects would be updated again.
+	if err := wait.PollImmediateUntilWithContext(ctx, versionFlushPeriod, func(ctx context.Context) (bool, error) {
+		if err := s.versions.load(ctx); err != nil {
+			s.logger.Error(err, "Failed to load propagated versions")
+			return false, nil
+		}
+		return true, nil
+	}); err != nil {
+		return
+	}
+
+	go s.federatedInformer.Run(ctx.Done())
+	go s.clusterObjectInformer.Run(ctx.Done())
+
+	if !cache.WaitForNamedCacheSync(
+		s.name,
+		ctx.Done(),
+		s.federatedInformer.HasSynced,
+		s.clusterObjectInformer.HasSynced,
+	) {
+		return
+	}
+
+	s.worker.Run(ctx.Done())
+	wait.UntilWithContext(ctx, s.flushVersions, versionFlushPeriod)
+
+	flushCtx, cancel := context.WithTimeout(context.Background(), versionFlushPeriod)
+	defer cancel()
+	s.flushVersions(flushCtx)
+}
+
+func (s *resourceSyncer) flushVersions(ctx context.Context) {
+	if err := s.versions.flush(ctx); err != nil {
+		s.logger.Error(err, "Failed to persist propagated versions")
+	}
+}
+
+func (s *resourceSyncer) reconcile(qualifiedName common.QualifiedName) (status worker.Result) {
+	_ = s.metrics.Rate(s.name+".throughput", 1)
+	logger := s.logger.WithValues("object", qualifiedName.String())
+	ctx := klog.NewContext(context.TODO(), logger)
+	startTime := time.Now()
+
+	logger.V(3).Info("Starting reconcile")
+	defer s.metrics.Duration(s.name+".latency", startTime)
+	defer func() {
+		logger.WithValues("duration", time.Since(startTime), "status", status.String()).V(3).Info("Finished reconcile")
+	}()
+
+	fedObject, err := s.objectFromStore(s.federatedInformer.GetStore(), qualifiedName)
+	if err != nil {
+		logger.Error(err, "Failed to get federated object from store")
+		return worker.StatusError
+	}
+	clusterObj, err := s.objectFromStore(s.clusterObjectInformer.GetStore(), qualifiedName)
+	if err != nil {
+		logger.Error(err, "Failed to get cluster object from store")
+		return worker.StatusError
+	}
+
+	if s.clusterTerminating() {
+		// the objects in the cluster are deleted by the agent if cascading delete is enabled, and left in place otherwise
+		logger.V(2).Info("Cluster is terminating, skip syncing to member cluster")
+		return worker.StatusAllOK
+	}
+
+	if fedObject != nil {
+		paused, err := synccontroller.IsPropagationPaused(fedObject)
+		if err != nil {
+			logger.Error(err, "Failed to check whether propagation is paused")
+			return worker.StatusError
+		}
+		if paused {
+			logger.V(2).Info("Propagation paused, skip syncing to member cluster")
+			return s.ensureStatusReported(ctx, qualifiedName, clusterObj)
+		}
+	}
+
+	if fedObject == nil || fedObject.GetDeletionTimestamp() != nil {
+		return s.ensureRemoved(ctx, qualifiedName, fedObject, clusterObj)
+	}
+
+	placed, err := s.isPlacedInCluster(fedObject)
+	if err != nil {
+		logger.Error(err, "Failed to get placement of federated object")
+		return worker.StatusError
+	}
+	if !placed {
+		return s.ensureRemoved(ctx, qualifiedName, nil, clusterObj)
+	}
+
+	return s.ensureSynced(ctx, qualifiedName, fedObject, clusterObj)
+}
+
+// ensureSynced creates or updates the object in the member cluster and reports its status to the host cluster.
+func (s *resourceSyncer) ensureSynced(
+	ctx context.Context,
+	qualifiedName common.QualifiedName,
+	fedObject, clusterObj *unstructured.Unstructured,
+) worker.Result {
+	logger := klog.FromContext(ctx)
+
+	if err := s.ensureAgentFinalizer(ctx, fedObject); err != nil {
+		if apierrors.IsConflict(err) {
+			return worker.StatusConflict
+		}
+		logger.Error(err, "Failed to ensure agent finalizer")
+		return worker.StatusError
+	}
+
+	templateVersion, err := synccontroller.GetTemplateHash(fedObject.Object)
+	if err != nil {
+		logger.Error(err, "Failed to compute template version")
+		return worker.StatusError
+	}
+	overrideVersion, err := synccontroller.GetOverrideHash(fedObject)
+	if err != nil {
+		logger.Error(err, "Failed to compute override version")
+		return worker.StatusError
+	}
+
+	versionMap := map[string]string{}
+	if version, ok := s.versions.Get(qualifiedName, templateVersion, overrideVersion); ok {
+		versionMap[s.clusterName] = version
+	}
+	fedResource := synccontroller.NewFederatedResourceForDispatch(s.typeConfig, fedObject, versionMap, s.eventRecorder)
+
+	dispatcher := dispatch.NewManagedDispatcher(
+		s.memberClientForCluster,
+		fedResource,
+		!util.ShouldAdoptPreexistingResources(fedObject),
+		s.metrics,
+	)
+	if clusterObj == nil {
+		dispatcher.Create(ctx, s.clusterName)
+	} else {
+		dispatcher.Update(ctx, s.clusterName, clusterObj)
+	}
+
+	dispatchOk, timeoutErr := dispatcher.Wait()
+	if timeoutErr != nil {
+		logger.Error(timeoutErr, "Sync to cluster timeout")
+		return worker.StatusError
+	}
+	if version, ok := dispatcher.VersionMap()[s.clusterName]; ok {
+		s.versions.Set(qualifiedName, propagatedVersion{
+			TemplateVersion: templateVersion,
+			OverrideVersion: overrideVersion,
+			ClusterVersion:  version,
+		})
+	}
+
+	collectedStatus := dispatcher.CollectedStatus()
+	if err := s.reportPropagationStatus(
+		ctx,
+		qualifiedName,
+		collectedStatus.StatusMap[s.clusterName],
+		collectedStatus.GenerationMap[s.clusterName],
+		collectedStatus.DriftMap[s.clusterName],
+	); err != nil {
+		logger.Error(err, "Failed to report propagation status")
+		return worker.StatusError
+	}
+
+	if s.typeConfig.GetStatusEnabled() && clusterObj != nil {
+		exists, err := s.reportObjectStatus(ctx, qualifiedName, clusterObj)
+		if err != nil {
+			logger.Error(err, "Failed to report object status")
+			return worker.StatusError
+		}
+		if !exists {
+			delay := statusObjectRecheckDelay
+			return worker.Result{Success: dispatchOk, RequeueAfter: &delay}
+		}
+	}
+
+	if !dispatchOk {
+		return worker.StatusError
+	}
+	return worker.StatusAllOK
+}
+
+// ensureStatusReported reports the status of the object in the member cluster without syncing it, which continues
+// while propagation is paused.
+func (s *resourceSyncer) ensureStatusReported(
+	ctx context.Context,
+	qualifiedName common.QualifiedName,
+	clusterObj *unstructured.Unstructured,
+) worker.Result {
+	if !s.typeConfig.GetStatusEnabled() || clusterObj == nil {
+		return worker.StatusAllOK
+	}
+
+	exists, err := s.reportObjectStatus(ctx, qualifiedName, clusterObj)
+	if err != nil {
+		klog.FromContext(ctx).Error(err, "Failed to report object status")
+		return worker.StatusError
+	}
+	if !exists {
+		delay := statusObjectRecheckDelay
+		return worker.Result{Success: true, RequeueAfter: &delay}
+	}
+	return worker.StatusAllOK
+}
+
+// ensureRemoved deletes the object from the member cluster, or removes the managed label from it if the federated
+// object is terminating and the object should be orphaned.
+func (s *resourceSyncer) ensureRemoved(
+	ctx context.Context,
+	qualifiedName common.QualifiedName,
+	terminatingFedObject, clusterObj *unstructured.Unstructured,
+) worker.Result {
+	s.versions.Delete(qualifiedName)
+	logger := klog.FromContext(ctx)
+
+	if clusterObj == nil {
+		// The object is removed from the cluster, allow the federated object to be deleted. The cluster object is
+		// also missing from the informer once it is orphaned, since the informer only watches managed objects.
+		if terminatingFedObject != nil {
+			if err := s.removeAgentFinalizer(ctx, terminatingFedObject); err != nil {
+				if apierrors.IsConflict(err) {
+					return worker.StatusConflict
+				}
+				logger.Error(err, "Failed to remove agent finalizer")
+				return worker.StatusError
+			}
+		}
+		return worker.StatusAllOK
+	}
+	if clusterObj.GetDeletionTimestamp() != nil {
+		return worker.StatusAllOK
+	}
+
+	targetType := s.typeConfig.GetTargetType()
+	dispatcher := dispatch.NewUnmanagedDispatcher(
+		s.memberClientForCluster,
+		schemautil.APIResourceToGVK(&targetType),
+		qualifiedName,
+	)
+
+	shouldBeOrphaned := false
+	if terminatingFedObject != nil {
+		orphaningBehavior := util.GetOrphaningBehavior(terminatingFedObject)
+		shouldBeOrphaned = orphaningBehavior == util.OrphanManagedResourcesAll ||
+			orphaningBehavior == util.OrphanManagedResourcesAdopted && util.HasAdoptedAnnotation(clusterObj)
+	}
+	if shouldBeOrphaned {
+		logger.V(2).Info("Cluster object is going to be orphaned")
+		dispatcher.RemoveManagedLabel(ctx, s.clusterName, clusterObj)
+	} else {
+		dispatcher.Delete(ctx, s.clusterName, clusterObj)
+	}
+
+	ok, timeoutErr := dispatcher.Wait()
+	if timeoutErr != nil {
+		logger.Error(timeoutErr, "Removal from cluster timeout")
+		return worker.StatusError
+	}
+	if !ok {
+		return worker.StatusError
+	}
+	return worker.StatusAllOK
+}
+
+// isPlacedInCluster returns true if the federated object is placed in the member cluster.
+func (s *resourceSyncer) isPlacedInCluster(fedObject *unstructured.Unstructured) (bool, error) {
+	placements, err := util.UnmarshalGenericPlacements(fedObject)
+	if err != nil {
+		return false, err
+	}
+	for _, placement := range placements.Spec.Placements {
+		if _, ok := placement.Placement.ClusterNames()[s.clusterName]; ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// reportPropagationStatus writes the propagation status of the member cluster to the federated object.
+func (s *resourceSyncer) reportPropagationStatus(
+	ctx context.Context,
+	qualifiedName common.QualifiedName,
+	propStatus fedtypesv1a1.PropagationStatus,
+	generation int64,
+	driftedFields []string,
+) error {
+	federatedType := s.typeConfig.GetFederatedType()
+	client := s.host.DynamicClient.Resource(schemautil.APIResourceToGVR(&federatedType)).
+		Namespace(qualifiedName.Namespace)
+
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		fedObject, err := client.Get(ctx, qualifiedName.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		changed, err := status.SetClusterPropagationStatus(fedObject, s.clusterName, propStatus, generation, driftedFields)
+		if err != nil || !changed {
+			return err
+		}
+		_, err = client.UpdateStatus(ctx, fedObject, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// reportObjectStatus writes the status fields collected from the cluster object to the federated status object.
+// Returns false if the federated status object does not exist yet.
+func (s *resourceSyncer) reportObjectStatus(
+	ctx context.Context,
+	qualifiedName common.QualifiedName,
+	clusterObj *unstructured.Unstructured,
+) (bool, error) {
+	clusterStatus := util.ResourceClusterStatus{ClusterName: s.clusterName, CollectedFields: map[string]interface{}{}}
+	if s.typeConfig.Spec.StatusCollection != nil {
+		collectedFields, failedFields := util.CollectStatusFields(clusterObj, s.typeConfig.Spec.StatusCollection.Fields)
+		clusterStatus.CollectedFields = collectedFields
+		if len(failedFields) > 0 {
+			sort.Strings(failedFields)
+			clusterStatus.Error = fmt.Sprintf("Failed to get those fields: %s", strings.Join(failedFields, ", "))
+		}
+	}
+
+	statusType := s.typeConfig.GetStatusType()
+	client := s.host.DynamicClient.Resource(schemautil.APIResourceToGVR(statusType)).Namespace(qualifiedName.Namespace)
+
+	exists := true
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		statusObj, err := client.Get(ctx, qualifiedName.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			exists = false
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		changed, err := setResourceClusterStatus(statusObj, clusterStatus)
+		if err != nil || !changed {
+			return err
+		}
+		_, err = client.Update(ctx, statusObj, metav1.UpdateOptions{})
+		return err
+	})
+	return exists, err
+}
+
+func (s *resourceSyncer) memberClientForCluster(clusterName string) (generic.Client, error) {
+	if clusterName != s.clusterName {
+		return nil, fmt.Errorf("cluster %s is not the member cluster of the agent", clusterName)
+	}
+	return s.member.GenericClient, nil
+}
+
+func (s *resourceSyncer) objectFromStore(
+	store cache.Store,
+	qualifiedName common.QualifiedName,
+) (*unstructured.Unstructured, error) {
+	obj, exists, err := store.GetByKey(qualifiedName.String())
+	if err != nil || !exists {
+		return nil, err
+	}
+	return obj.(*unstructured.Unstructured).DeepCopy(), nil
+}
+
+// ensureAgentFinalizer adds the finalizer of the agent to the federated object, so that the object is removed from the
+// member cluster before the federated object is deleted.
+func (s *resourceSyncer) ensureAgentFinalizer(ctx context.Context, fedObject *unstructured.Unstructured) error {
+	added, err := finalizersutil.AddFinalizers(fedObject, sets.NewString(util.PullClusterKey(s.clusterName)))
+	if err != nil || !added {
+		return err
+	}
+
+	klog.FromContext(ctx).V(1).Info("Adding agent finalizer to federated object")
+	return s.updateFederatedObject(ctx, fedObject)
+}
+
+func (s *resourceSyncer) removeAgentFinalizer(ctx context.Context, fedObject *unstructured.Unstructured) error {
+	removed, err := finalizersutil.RemoveFinalizers(fedObject, sets.NewString(util.PullClusterKey(s.clusterName)))
+	if err != nil || !removed {
+		return err
+	}
+
+	klog.FromContext(ctx).V(1).Info("Removing agent finalizer from federated object")
+	return s.updateFederatedObject(ctx, fedObject)
+}
+
+func (s *resourceSyncer) updateFederatedObject(ctx context.Context, fedObject *unstructured.Unstructured) error {
+	federatedType := s.typeConfig.GetFederatedType()
+	updated, err := s.host.DynamicClient.Resource(schemautil.APIResourceToGVR(&federatedType)).
+		Namespace(fedObject.GetNamespace()).
+		Update(ctx, fedObject, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	fedObject.SetResourceVersion(updated.GetResourceVersion())
+	return nil
+}
+
+// setResourceClusterStatus replaces the status of the cluster in the federated status object with the given status.
+// Returns true if the object is changed.
+func setResourceClusterStatus(statusObj *unstructured.Unstructured, status util.ResourceClusterStatus) (bool, error) {
+	clusterStatuses, err := util.ClusterStatusesFromObject(statusObj)
+	if err != nil {
+		return false, err
+	}
+
+	found := false
+	for i := range clusterStatuses {
+		if clusterStatuses[i].ClusterName == status.ClusterName {
+			clusterStatuses[i] = status
+			found = true
+		}
+	}
+	if !found {
+		clusterStatuses = append(clusterStatuses, status)
+	}
+	sort.Slice(clusterStatuses, func(i, j int) bool {
+		return clusterStatuses[i].ClusterName < clusterStatuses[j].ClusterName
+	})
+
+	updated, err := util.GetUnstructured(util.FederatedResource{
+		TypeMeta:      metav1.TypeMeta{APIVersion: statusObj.GetAPIVersion(), Kind: statusObj.GetKind()},
+		ClusterStatus: clusterStatuses,
+	})
+	if err != nil {
+		return false, err
+	}
+	if reflect.DeepEqual(statusObj.Object["clusterStatus"], updated.Object["clusterStatus"]) {
+		return false, nil
+	}
+	statusObj.Object["clusterStatus"] = updated.Object["clusterStatus"]
+	return true, nil
+}
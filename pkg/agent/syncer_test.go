@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+)
+
+func TestSetResourceClusterStatus(t *testing.T) {
+	newStatusObj := func(clusterStatuses ...interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion":    "core.kubeadmiral.io/v1alpha1",
+			"kind":          "FederatedDeploymentStatus",
+			"metadata":      map[string]interface{}{"name": "foo", "namespace": "default"},
+			"clusterStatus": clusterStatuses,
+		}}
+	}
+	replicas := func(clusterName string, replicas int64) interface{} {
+		return map[string]interface{}{
+			"clusterName":     clusterName,
+			"collectedFields": map[string]interface{}{"status": map[string]interface{}{"replicas": replicas}},
+		}
+	}
+
+	testCases := map[string]struct {
+		statusObj       *unstructured.Unstructured
+		expectedChanged bool
+		expectedObj     *unstructured.Unstructured
+	}{
+		"new cluster status is inserted in order": {
+			statusObj:       newStatusObj(replicas("a", 1), replicas("c", 1)),
+			expectedChanged: true,
+			expectedObj:     newStatusObj(replicas("a", 1), replicas("b", 2), replicas("c", 1)),
+		},
+		"existing cluster status is replaced": {
+			statusObj:       newStatusObj(replicas("a", 1), replicas("b", 1)),
+			expectedChanged: true,
+			expectedObj:     newStatusObj(replicas("a", 1), replicas("b", 2)),
+		},
+		"unchanged cluster status": {
+			statusObj:   newStatusObj(replicas("b", 2)),
+			expectedObj: newStatusObj(replicas("b", 2)),
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			changed, err := setResourceClusterStatus(tc.statusObj, util.ResourceClusterStatus{
+				ClusterName: "b",
+				CollectedFields: map[string]interface{}{
+					"status": map[string]interface{}{"replicas": int64(2)},
+				},
+			})
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(changed).To(gomega.Equal(tc.expectedChanged))
+			g.Expect(tc.statusObj.Object["clusterStatus"]).To(gomega.Equal(tc.expectedObj.Object["clusterStatus"]))
+		})
+	}
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclient "k8s.io/client-go/kubernetes"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+// versionsKey is the key of the ConfigMap data that holds the propagated versions.
+const versionsKey = "versions"
+
+// propagatedVersion is the version of an object last propagated to the member cluster, together with the versions of
+// the template and overrides of the federated object it was propagated from.
+type propagatedVersion struct {
+	TemplateVersion string `json:"templateVersion"`
+	OverrideVersion string `json:"overrideVersion"`
+	ClusterVersion  string `json:"clusterVersion"`
+}
+
+// versionStore records the versions of the objects propagated to the member cluster by a syncer. The versions are
+// persisted to a ConfigMap in the member cluster, so that unchanged objects are not updated again after the agent
+// restarts.
+type versionStore struct {
+	client    kubeclient.Interface
+	namespace string
+	name      string
+
+	lock     sync.Mutex
+	versions map[string]propagatedVersion
+	dirty    bool
+	// exists is whether the ConfigMap has been created.
+	exists bool
+	// resourceVersion is the resource version of the ConfigMap last read or written.
+	resourceVersion string
+}
+
+func newVersionStore(client kubeclient.Interface, namespace, typeConfigName string) *versionStore {
+	return &versionStore{
+		client:    client,
+		namespace: namespace,
+		name:      fmt.Sprintf("%s-versions-%s", AgentName, typeConfigName),
+		versions:  map[string]propagatedVersion{},
+	}
+}
+
+// load reads the persisted versions from the ConfigMap.
+func (s *versionStore) load(ctx context.Context) error {
+	configMap, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	versions := map[string]propagatedVersion{}
+	if data := configMap.Data[versionsKey]; data != "" {
+		if err := json.Unmarshal([]byte(data), &versions); err != nil {
+			return fmt.Errorf("failed to unmarshal versions in ConfigMap %s/%s: %w", s.namespace, s.name, err)
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.versions = versions
+	s.exists = true
+	s.resourceVersion = configMap.ResourceVersion
+	return nil
+}
+
+// Get returns the version last propagated for the object if it was propagated from the given template and override
+// versions.
+func (s *versionStore) Get(qualifiedName common.QualifiedName, templateVersion, overrideVersion string) (string, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	version, ok := s.versions[qualifiedName.String()]
+	if !ok || version.TemplateVersion != templateVersion || version.OverrideVersion != overrideVersion {
+		return "", false
+	}
+	return version.ClusterVersion, true
+}
+
+func (s *versionStore) Set(qualifiedName common.QualifiedName, version propagatedVersion) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.versions[qualifiedName.String()] != version {
+		s.versions[qualifiedName.String()] = version
+		s.dirty = true
+	}
+}
+
+func (s *versionStore) Delete(qualifiedName common.QualifiedName) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.versions[qualifiedName.String()]; ok {
+		delete(s.versions, qualifiedName.String())
+		s.dirty = true
+	}
+}
+
+// flush writes the versions to the ConfigMap if they have changed since the last flush.
+func (s *versionStore) flush(ctx context.Context) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(s.versions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal versions: %w", err)
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       s.namespace,
+			Name:            s.name,
+			ResourceVersion: s.resourceVersion,
+		},
+		Data: map[string]string{versionsKey: string(data)},
+	}
+	if !s.exists {
+		configMap, err = s.client.CoreV1().ConfigMaps(s.namespace).Create(ctx, configMap, metav1.CreateOptions{})
+	} else {
+		configMap, err = s.client.CoreV1().ConfigMaps(s.namespace).Update(ctx, configMap, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		if apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err) {
+			// the ConfigMap is only written by this store, so ours is the latest content
+			s.exists, s.resourceVersion = s.latestResourceVersion(ctx)
+		}
+		return err
+	}
+
+	s.exists = true
+	s.resourceVersion = configMap.ResourceVersion
+	s.dirty = false
+	return nil
+}
+
+// latestResourceVersion returns whether the ConfigMap exists and its current resource version. The ConfigMap is
+// assumed to not exist if it cannot be read.
+func (s *versionStore) latestResourceVersion(ctx context.Context) (bool, string) {
+	configMap, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if err != nil {
+		return false, ""
+	}
+	return true, configMap.ResourceVersion
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+func TestVersionStore(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctx := context.Background()
+	client := fake.NewSimpleClientset()
+	name := common.QualifiedName{Namespace: "default", Name: "foo"}
+
+	store := newVersionStore(client, "kube-admiral-system", "deployments.apps")
+	g.Expect(store.load(ctx)).To(gomega.Succeed())
+	_, found := store.Get(name, "t1", "o1")
+	g.Expect(found).To(gomega.BeFalse())
+
+	store.Set(name, propagatedVersion{TemplateVersion: "t1", OverrideVersion: "o1", ClusterVersion: "gen:1"})
+	g.Expect(store.flush(ctx)).To(gomega.Succeed())
+
+	// a new store, e.g. after the agent restarts, sees the persisted versions
+	restarted := newVersionStore(client, "kube-admiral-system", "deployments.apps")
+	g.Expect(restarted.load(ctx)).To(gomega.Succeed())
+	version, found := restarted.Get(name, "t1", "o1")
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(version).To(gomega.Equal("gen:1"))
+
+	// the version is not used once the template or overrides change
+	_, found = restarted.Get(name, "t2", "o1")
+	g.Expect(found).To(gomega.BeFalse())
+	_, found = restarted.Get(name, "t1", "o2")
+	g.Expect(found).To(gomega.BeFalse())
+
+	restarted.Delete(name)
+	g.Expect(restarted.flush(ctx)).To(gomega.Succeed())
+	g.Expect(store.load(ctx)).To(gomega.Succeed())
+	_, found = store.Get(name, "t1", "o1")
+	g.Expect(found).To(gomega.BeFalse())
+}
@@ -92,6 +92,11 @@ func (f *FederatedTypeConfig) GetRolloutPlanEnabled() bool {
 		*f.Spec.RolloutPlan == RolloutPlanEnabled
 }
 
+func (f *FederatedTypeConfig) GetServerSideApplyEnabled() bool {
+	return f.Spec.DispatchMode != nil &&
+		*f.Spec.DispatchMode == DispatchModeServerSideApply
+}
+
 func (f *FederatedTypeConfig) GetControllers() [][]string {
 	return f.Spec.Controllers
 }
@@ -28,6 +28,8 @@ func GetDefaultEnabledPlugins() *fedcore.EnabledPlugins {
 		names.ClusterResourcesFit,
 		names.PlacementFilter,
 		names.ClusterAffinity,
+		names.WorkloadAffinity,
+		names.ClusterMaintenance,
 	}
 
 	scorePlugins := []string{
@@ -35,6 +37,7 @@ func GetDefaultEnabledPlugins() *fedcore.EnabledPlugins {
 		names.ClusterResourcesBalancedAllocation,
 		names.ClusterResourcesLeastAllocated,
 		names.ClusterAffinity,
+		names.ClusterStability,
 	}
 
 	selectPlugins := []string{names.MaxCluster}
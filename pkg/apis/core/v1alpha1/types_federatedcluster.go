@@ -33,6 +33,7 @@ import (
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name=ready,type=string,JSONPath=.status.conditions[?(@.type=='Ready')].status
 // +kubebuilder:printcolumn:name=joined,type=string,JSONPath=.status.conditions[?(@.type=='Joined')].status
+// +kubebuilder:printcolumn:name=maintenance,type=string,JSONPath=.spec.maintenance.mode
 // +kubebuilder:printcolumn:name=age,type=date,JSONPath=.metadata.creationTimestamp
 // +kubebuilder:object:root=true
 
@@ -56,7 +57,17 @@ type FederatedClusterList struct {
 
 // FederatedClusterSpec defines the desired state of FederatedCluster
 type FederatedClusterSpec struct {
+	// The mode in which resources are synced to the member cluster. In Push mode, the control plane connects to the
+	// member cluster to sync resources and collect status. In Pull mode, an agent running in the member cluster watches
+	// the resources placed in the cluster, applies them locally and reports status back to the control plane.
+	// +kubebuilder:validation:Enum=Push;Pull
+	// +kubebuilder:default=Push
+	// +optional
+	SyncMode ClusterSyncMode `json:"syncMode,omitempty"`
+
 	// The API endpoint of the member cluster. This can be a hostname, hostname:port, IP or IP:port.
+	// Required in Push mode.
+	// +optional
 	APIEndpoint string `json:"apiEndpoint"`
 
 	// Access API endpoint with security.
@@ -68,12 +79,192 @@ type FederatedClusterSpec struct {
 	UseServiceAccountToken bool `json:"useServiceAccount"`
 
 	// Name of the secret containing the token required to access the member cluster.
-	// The secret needs to exist in the fed system namespace.
+	// The secret needs to exist in the fed system namespace. Required in Push mode.
+	// +optional
 	SecretRef LocalSecretReference `json:"secretRef"`
 
+	// CredentialProvider configures how short-lived credentials for the member cluster are obtained. If set, it takes
+	// precedence over the client certificate and service account token in the secret referenced by SecretRef, which
+	// is then only used for the CA and the secret values of the provider.
+	// +optional
+	CredentialProvider *ClusterCredentialProvider `json:"credentialProvider,omitempty"`
+
+	// Proxy configures the proxy used to access the API endpoint of the member cluster.
+	// +optional
+	Proxy *ClusterProxy `json:"proxy,omitempty"`
+
 	// If specified, the cluster's taints.
 	// +optional
 	Taints []corev1.Taint `json:"taints,omitempty"`
+
+	// Maintenance puts the cluster into maintenance mode, e.g. before the cluster is upgraded.
+	// +optional
+	Maintenance *ClusterMaintenance `json:"maintenance,omitempty"`
+
+	// HealthCheck overrides the global health check configuration for the cluster.
+	// +optional
+	HealthCheck *ClusterHealthCheck `json:"healthCheck,omitempty"`
+}
+
+// ClusterSyncMode is the mode in which resources are synced to a member cluster.
+type ClusterSyncMode string
+
+const (
+	// ClusterSyncModePush means the control plane connects to the member cluster to sync resources.
+	ClusterSyncModePush ClusterSyncMode = "Push"
+	// ClusterSyncModePull means an agent in the member cluster pulls resources from the control plane.
+	ClusterSyncModePull ClusterSyncMode = "Pull"
+)
+
+// ClusterMaintenance configures the maintenance mode of a member cluster.
+type ClusterMaintenance struct {
+	// Mode is the maintenance mode of the cluster. In Cordon mode, no new objects are placed in the cluster, while
+	// objects already placed in the cluster are kept. In Drain mode, all objects are moved away from the cluster.
+	// Replicas of objects scheduled in Divide mode are only removed from the cluster once the replicas moved to other
+	// clusters are available.
+	// +kubebuilder:validation:Enum=Cordon;Drain
+	Mode ClusterMaintenanceMode `json:"mode"`
+}
+
+// ClusterMaintenanceMode is the maintenance mode of a member cluster.
+type ClusterMaintenanceMode string
+
+const (
+	// ClusterMaintenanceModeCordon means no new objects are placed in the cluster.
+	ClusterMaintenanceModeCordon ClusterMaintenanceMode = "Cordon"
+	// ClusterMaintenanceModeDrain means all objects are moved away from the cluster.
+	ClusterMaintenanceModeDrain ClusterMaintenanceMode = "Drain"
+)
+
+// ClusterHealthCheck configures how the health of a member cluster is checked. Unset fields default to the global
+// health check configuration of the controller manager.
+type ClusterHealthCheck struct {
+	// Path of the health endpoint of the cluster's apiserver.
+	// +kubebuilder:validation:Enum=/healthz;/readyz;/livez
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Period between health checks. For pull-mode clusters, the period is determined by the agent instead.
+	// +optional
+	Period *metav1.Duration `json:"period,omitempty"`
+
+	// Timeout of the request to the health endpoint.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// SuccessThreshold is the number of consecutive successful health checks after which a not ready cluster becomes
+	// ready.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	SuccessThreshold int32 `json:"successThreshold,omitempty"`
+
+	// FailureThreshold is the number of consecutive failed health checks after which a ready cluster becomes not
+	// ready.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+
+	// MinSchedulableNodes fails the health check if the cluster has fewer schedulable nodes.
+	// +optional
+	MinSchedulableNodes *int64 `json:"minSchedulableNodes,omitempty"`
+
+	// RequiredAPIResources fails the health check if any of the API resources is not served by the cluster, e.g.
+	// if a CRD required by the workloads of the cluster is missing.
+	// +optional
+	RequiredAPIResources []RequiredAPIResource `json:"requiredAPIResources,omitempty"`
+}
+
+// RequiredAPIResource identifies an API resource that must be served by a cluster.
+type RequiredAPIResource struct {
+	// Group of the resource.
+	// +optional
+	Group string `json:"group,omitempty"`
+	// Version of the resource. Any version is accepted if empty.
+	// +optional
+	Version string `json:"version,omitempty"`
+	// Kind of the resource.
+	Kind string `json:"kind"`
+}
+
+// ClusterCredentialProvider configures a provider of short-lived credentials for a member cluster. Exactly one of
+// the providers must be set. Credentials are refreshed by the clients before they expire.
+// +kubebuilder:validation:MinProperties=1
+// +kubebuilder:validation:MaxProperties=1
+type ClusterCredentialProvider struct {
+	// Exec obtains credentials by running a client-go credential plugin. The command must be allowed by the operator
+	// of the controller manager.
+	// +optional
+	Exec *ExecCredentialProvider `json:"exec,omitempty"`
+
+	// OIDC obtains ID tokens from an OIDC provider using a refresh token.
+	// +optional
+	OIDC *OIDCCredentialProvider `json:"oidc,omitempty"`
+
+	// TokenFile reads a bearer token from a file, such as a projected service account token. The file is reread
+	// periodically so that rotated tokens are picked up. The file must be in a directory allowed by the operator of the
+	// controller manager.
+	// +optional
+	TokenFile *TokenFileCredentialProvider `json:"tokenFile,omitempty"`
+}
+
+// ExecCredentialProvider runs a client-go credential plugin.
+type ExecCredentialProvider struct {
+	// Command to execute.
+	Command string `json:"command"`
+
+	// Arguments to pass to the command.
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// Env defines additional environment variables to expose to the command.
+	// +optional
+	Env []ExecEnvVar `json:"env,omitempty"`
+
+	// APIVersion of the ExecCredential exchanged with the plugin.
+	// +kubebuilder:validation:Enum=client.authentication.k8s.io/v1;client.authentication.k8s.io/v1beta1
+	// +kubebuilder:default=client.authentication.k8s.io/v1
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+}
+
+// ExecEnvVar is an environment variable passed to a credential plugin.
+type ExecEnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// OIDCCredentialProvider obtains ID tokens from an OIDC provider. The refresh token, and optionally the client secret
+// and an initial ID token, are read from the cluster secret.
+type OIDCCredentialProvider struct {
+	// IssuerURL is the URL of the OIDC provider.
+	IssuerURL string `json:"issuerURL"`
+
+	// ClientID is the OAuth client ID.
+	ClientID string `json:"clientID"`
+
+	// ExtraScopes are the scopes requested in addition to openid.
+	// +optional
+	ExtraScopes []string `json:"extraScopes,omitempty"`
+}
+
+// TokenFileCredentialProvider reads a bearer token from a file.
+type TokenFileCredentialProvider struct {
+	// Path of the token file in the controller manager's file system.
+	Path string `json:"path"`
+}
+
+// ClusterProxy configures the proxy used to access a member cluster.
+type ClusterProxy struct {
+	// URL of the proxy. The http, https and socks5 schemes are supported.
+	// +kubebuilder:validation:Pattern=`^(http|https|socks5)://.+$`
+	URL string `json:"url"`
+
+	// Name of the secret containing the headers sent to the proxy in CONNECT requests. Each key of the secret is a
+	// header name and its value is the header value. The secret needs to exist in the fed system namespace.
+	// +optional
+	HeadersSecretRef *LocalSecretReference `json:"headersSecretRef,omitempty"`
 }
 
 // FederatedClusterStatus defines the observed state of FederatedCluster
@@ -93,6 +284,48 @@ type FederatedClusterStatus struct {
 	// If true, clean-up is required on cluster removal to undo the side-effects.
 	// +optional
 	JoinPerformed bool `json:"joinPerformed,omitempty"`
+	// Health is a rolling history of the cluster's recent health probes and transitions.
+	// +optional
+	Health *ClusterHealth `json:"health,omitempty"`
+}
+
+// ClusterHealth is a rolling history of a cluster's recent health probes and transitions.
+type ClusterHealth struct {
+	// Probes are the most recent health probes of the cluster, newest first.
+	// +optional
+	Probes []ClusterHealthProbe `json:"probes,omitempty"`
+	// Transitions are the most recent transitions of the cluster's Ready condition, newest first.
+	// +optional
+	Transitions []ClusterHealthTransition `json:"transitions,omitempty"`
+}
+
+// ClusterHealthProbe is the result of a health probe of a cluster.
+type ClusterHealthProbe struct {
+	// Time of the probe.
+	Time metav1.Time `json:"time"`
+	// Result of the probe. The Ready condition only changes to the result once the success or failure threshold of
+	// the health check is reached.
+	Status corev1.ConditionStatus `json:"status"`
+	// Reason of the result of the probe, e.g. ClusterAPIDiscoveryFailed.
+	Reason string `json:"reason"`
+	// Human readable message indicating details about the result of the probe.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Latency of the health check request, if it was sent.
+	// +optional
+	HealthCheckLatency *metav1.Duration `json:"healthCheckLatency,omitempty"`
+}
+
+// ClusterHealthTransition is a transition of a cluster's Ready condition.
+type ClusterHealthTransition struct {
+	// Time of the transition.
+	Time metav1.Time `json:"time"`
+	// Status of the Ready condition before the transition.
+	From corev1.ConditionStatus `json:"from"`
+	// Status of the Ready condition after the transition.
+	To corev1.ConditionStatus `json:"to"`
+	// Reason of the Ready condition after the transition.
+	Reason string `json:"reason"`
 }
 
 // LocalSecretReference is a reference to a secret within the enclosing namespace.
@@ -127,6 +360,13 @@ const (
 	ClusterReady ClusterConditionType = "Ready"
 	// ClusterOffline means the cluster is temporarily down or not reachable.
 	ClusterOffline ClusterConditionType = "Offline"
+	// ClusterTokenRotated means the service account token used to access the cluster has been rotated.
+	ClusterTokenRotated ClusterConditionType = "TokenRotated"
+	// ClusterUnderMaintenance means the cluster is in maintenance mode. The reason of the condition reports the progress
+	// of the maintenance, e.g. whether a drain has completed.
+	ClusterUnderMaintenance ClusterConditionType = "UnderMaintenance"
+	// ClusterUnstable means the cluster's Ready condition has transitioned too frequently recently.
+	ClusterUnstable ClusterConditionType = "Unstable"
 )
 
 // Resources describes a cluster's resources
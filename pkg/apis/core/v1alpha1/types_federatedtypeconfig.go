@@ -34,6 +34,9 @@ const (
 
 	RolloutPlanEnabled  RolloutPlanMode = "Enabled"
 	RolloutPlanDisabled RolloutPlanMode = "Disabled"
+
+	DispatchModeUpdate          DispatchMode = "Update"
+	DispatchModeServerSideApply DispatchMode = "ServerSideApply"
 )
 
 // +genclient
@@ -96,6 +99,22 @@ type FederatedTypeConfigSpec struct {
 	// Configurations for auto migration.
 	// +optional
 	AutoMigration *AutoMigrationConfig `json:"autoMigration,omitempty"`
+	// How target objects are updated in member clusters. In Update mode, the whole object is replaced and fields set
+	// by controllers in member clusters are retained by type-specific logic. In ServerSideApply mode, only the fields
+	// owned by KubeAdmiral are applied, and conflicts with other field managers are reported in the propagation
+	// status. Defaults to Update.
+	// +optional
+	DispatchMode *DispatchMode `json:"dispatchMode,omitempty"`
+	// The fields of target objects that are set in member clusters and are retained from the objects in member
+	// clusters when the objects are updated, in addition to the fields retained for built-in types. Ignored in
+	// ServerSideApply dispatch mode.
+	// +optional
+	RetainFields []RetainField `json:"retainFields,omitempty"`
+	// Rules for classifying the labels and annotations of source objects into those copied to the template, those
+	// copied to the federated object and those ignored. These rules take precedence over the rules configured in
+	// the controller manager. Labels and annotations reserved by KubeAdmiral cannot be reclassified.
+	// +optional
+	MetadataPropagation *MetadataPropagation `json:"metadataPropagation,omitempty"`
 
 	// The controllers that must run before the resource can be propagated to member clusters.
 	// Each inner slice specifies a step. Step T must complete before step T+1 can commence.
@@ -156,11 +175,73 @@ type RevisionHistoryMode string
 
 type RolloutPlanMode string
 
+// DispatchMode defines how target objects are updated in member clusters.
+// +kubebuilder:validation:Enum=Update;ServerSideApply
+type DispatchMode string
+
 type AutoMigrationConfig struct {
 	// Whether or not to enable auto migration.
 	Enabled bool `json:"enabled"`
 }
 
+// MetadataPropagation defines how the labels and annotations of source objects are propagated.
+type MetadataPropagation struct {
+	// Rules for classifying labels.
+	// +optional
+	Labels *MetadataClassification `json:"labels,omitempty"`
+	// Rules for classifying annotations.
+	// +optional
+	Annotations *MetadataClassification `json:"annotations,omitempty"`
+}
+
+// MetadataClassification classifies the keys of labels or annotations. A key matched by more than one rule is
+// ignored if matched by Ignored, and otherwise copied to the federated object if matched by FederatedOnly. Keys not
+// matched by any rule are copied to the template.
+type MetadataClassification struct {
+	// Keys copied to the federated object only. They are not propagated to member clusters.
+	// +optional
+	FederatedOnly *KeyMatcher `json:"federatedOnly,omitempty"`
+	// Keys copied to the template only, e.g. to exempt keys from broader rules configured in the controller manager.
+	// +optional
+	TemplateOnly *KeyMatcher `json:"templateOnly,omitempty"`
+	// Keys copied to neither the federated object nor the template.
+	// +optional
+	Ignored *KeyMatcher `json:"ignored,omitempty"`
+}
+
+// KeyMatcher matches the keys that are matched by Include and not matched by Exclude.
+type KeyMatcher struct {
+	// +optional
+	Include KeySelector `json:"include,omitempty"`
+	// +optional
+	Exclude KeySelector `json:"exclude,omitempty"`
+}
+
+// KeySelector selects the keys that have any of the prefixes or match any of the regular expressions.
+type KeySelector struct {
+	// Prefixes of keys, e.g. `argocd.argoproj.io/`. An exact key may be given as a prefix as well.
+	// +optional
+	Prefixes []string `json:"prefixes,omitempty"`
+	// Go regular expressions matched against keys. Expressions are unanchored, e.g. `^app\.kubernetes\.io/`
+	// must be used to match keys beginning with `app.kubernetes.io/`.
+	// +optional
+	Regexps []string `json:"regexps,omitempty"`
+}
+
+// RetainField defines a field of target objects that is retained from the objects in member clusters.
+type RetainField struct {
+	// Path of the field, in the form of dot-separated field names, e.g. `spec.clusterIP`. A field name may be
+	// suffixed with the comma-separated keys of a list in brackets, e.g. `spec.ports[name,protocol].nodePort`, to
+	// select the items of the list that are matched by the values of the keys in the desired and member cluster
+	// objects, or with `[*]` to select the items of the list that are matched by their indices.
+	//
+	// If the field exists in the member cluster object, its value replaces the desired value, except that maps are
+	// merged with the desired entries taking precedence, and lists selected by keys are merged by appending the items
+	// of the member cluster object whose keys do not match any desired item.
+	// +kubebuilder:validation:Pattern=`^([^.[\]]+(\[(\*|[\w-]+(, *[\w-]+)*)\])?\.)*[^.[\]]+(\[(\*|[\w-]+(, *[\w-]+)*)\])?$`
+	Path string `json:"path"`
+}
+
 // APIResource defines how to configure the dynamic client for an API resource.
 type APIResource struct {
 	// metav1.GroupVersion is not used since the json annotation of
@@ -5,6 +5,7 @@ package v1alpha1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // +genclient
@@ -66,6 +67,13 @@ type PropagationPolicySpec struct {
 
 	// SchedulingMode determines the mode used for scheduling.
 	SchedulingMode SchedulingMode `json:"schedulingMode"`
+	// DuplicateWithScale determines if the replicas of Duplicate mode federated objects are scaled per cluster.
+	// If true, the number of replicas in each cluster is derived from the template replicas and the preferences of the
+	// cluster's placement, and is written as a replicas override just like in Divide mode.
+	// A cluster without percentage or weight preferences gets the template replicas.
+	// Ignored in Divide mode and for types without replicas.
+	// +optional
+	DuplicateWithScale bool `json:"duplicateWithScale,omitempty"`
 	// StickyCluster determines if a federated object can be rescheduled.
 	// +optional
 	StickyCluster bool `json:"stickyCluster"`
@@ -98,6 +106,36 @@ type PropagationPolicySpec struct {
 	// +optional
 	DisableFollowerScheduling bool `json:"disableFollowerScheduling,omitempty"`
 
+	// WorkloadAffinity describes co-location rules between the federated object and other federated objects.
+	// The rules are evaluated against the current placements of the referenced objects.
+	// +optional
+	WorkloadAffinity *WorkloadAffinity `json:"workloadAffinity,omitempty"`
+
+	// PriorityClassName is the name of a PriorityClass (scheduling.k8s.io/v1) in the host cluster that determines
+	// the scheduling priority of federated objects using this policy. Federated objects with higher priorities
+	// are scheduled first. If the clusters selected for a Divide mode federated object do not have enough free
+	// resources for its replicas, the scheduler may preempt replicas of federated objects with lower priorities
+	// in these clusters, unless the preemptionPolicy of the PriorityClass is Never. Only federated objects of the
+	// same federated type are preempted, e.g. a FederatedDeployment never preempts replicas of a FederatedStatefulSet.
+	// Federated objects without a priority class have a priority of 0.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// DisruptionBudget limits the number of replicas that can be preempted by federated objects with higher priorities.
+	// +optional
+	DisruptionBudget *DisruptionBudget `json:"disruptionBudget,omitempty"`
+
+	// GangScheduling enables all-or-nothing scheduling for Divide mode federated objects.
+	// If present, the scheduler only commits a placement if enough replicas can run in the selected clusters.
+	// +optional
+	GangScheduling *GangScheduling `json:"gangScheduling,omitempty"`
+
+	// DriftPolicy determines how changes made directly to propagated objects in member clusters are handled.
+	// Correct overwrites the drifted fields with their desired values, Report only reports the drifted fields.
+	// Defaults to Correct.
+	// +optional
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+
 	// Configures behaviors related to auto migration. If absent, auto migration will be disabled.
 	// +optional
 	AutoMigration *AutoMigration `json:"autoMigration,omitempty"`
@@ -124,6 +162,17 @@ const (
 	SchedulingModeDivide SchedulingMode = "Divide"
 )
 
+// DriftPolicy determines how drifted objects in member clusters are handled.
+// +kubebuilder:validation:Enum=Correct;Report
+type DriftPolicy string
+
+const (
+	// DriftPolicyCorrect means drifted fields are overwritten with their desired values and reported.
+	DriftPolicyCorrect DriftPolicy = "Correct"
+	// DriftPolicyReport means drifted fields are only reported.
+	DriftPolicyReport DriftPolicy = "Report"
+)
+
 // Placement describes a cluster that a federated object can be propagated to and its propagation preferences.
 type Placement struct {
 	// Cluster is the name of the FederatedCluster to propagate to.
@@ -148,9 +197,74 @@ type Preferences struct {
 	MaxReplicas *int64 `json:"maxReplicas,omitempty"`
 
 	// A number expressing the preference to put an additional replica to this cluster workload object.
+	// In Duplicate mode with scaling, the cluster gets the template replicas multiplied by its weight relative to
+	// the largest weight among the selected clusters, rounded up.
 	// +optional
 	// +kubebuilder:validation:Minimum=0
 	Weight *int64 `json:"weight,omitempty"`
+
+	// Percentage of the template replicas that should be assigned to this cluster workload object in Duplicate mode
+	// with scaling, rounded up. Takes precedence over Weight. Ignored in Divide mode.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Percentage *int64 `json:"percentage,omitempty"`
+}
+
+// WorkloadAffinity is a group of inter-workload affinity scheduling rules.
+type WorkloadAffinity struct {
+	// Affinity is a list of workload affinity terms, the terms are ANDed.
+	// The federated object will only be scheduled to clusters where, for every term,
+	// at least one of the objects selected by the term is placed.
+	// +optional
+	Affinity []WorkloadAffinityTerm `json:"affinity,omitempty"`
+
+	// AntiAffinity is a list of workload anti-affinity terms, the terms are ORed.
+	// The federated object will not be scheduled to clusters where
+	// any of the objects selected by any of the terms is placed.
+	// +optional
+	AntiAffinity []WorkloadAffinityTerm `json:"antiAffinity,omitempty"`
+}
+
+// WorkloadAffinityTerm selects a set of federated objects by the group, kind and labels of their templates.
+type WorkloadAffinityTerm struct {
+	// Group of the referenced objects, e.g. "apps". Empty for the core API group.
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// Kind of the referenced objects, e.g. "StatefulSet".
+	Kind string `json:"kind"`
+
+	// LabelSelector is a label query over the labels of the referenced objects.
+	LabelSelector metav1.LabelSelector `json:"labelSelector"`
+
+	// Namespaces specifies the namespaces to select the referenced objects from.
+	// If empty, the namespace of the federated object is used.
+	// Ignored for cluster-scoped types.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// DisruptionBudget limits the disruption caused to a federated object by preemption.
+type DisruptionBudget struct {
+	// MaxPreemptedReplicas is the maximum number of replicas of the federated object that can be preempted at the same
+	// time. The value can be an absolute number or a percentage of the desired replicas, rounded down.
+	// If unset, any number of replicas can be preempted.
+	// +optional
+	MaxPreemptedReplicas *intstr.IntOrString `json:"maxPreemptedReplicas,omitempty"`
+}
+
+// GangScheduling configures all-or-nothing scheduling.
+type GangScheduling struct {
+	// MinAvailable is the minimum number of replicas that must be able to run for the federated object to be scheduled.
+	// The number of replicas that can run in a cluster is limited by the free resources of the cluster and,
+	// if auto migration is enabled, the capacity estimated from unschedulable replicas.
+	// If a scheduled federated object loses capacity so that fewer than MinAvailable of its current replicas can
+	// still run, e.g. because one of its clusters is no longer feasible, its placements are released and it stays
+	// pending until MinAvailable replicas can be scheduled again.
+	// Defaults to the desired replicas of the federated object.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MinAvailable *int64 `json:"minAvailable,omitempty"`
 }
 
 // Preferences regarding auto migration.
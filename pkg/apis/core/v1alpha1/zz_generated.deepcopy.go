@@ -9,6 +9,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -99,6 +100,158 @@ func (in *ClusterCondition) DeepCopy() *ClusterCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCredentialProvider) DeepCopyInto(out *ClusterCredentialProvider) {
+	*out = *in
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(ExecCredentialProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OIDCCredentialProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TokenFile != nil {
+		in, out := &in.TokenFile, &out.TokenFile
+		*out = new(TokenFileCredentialProvider)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCredentialProvider.
+func (in *ClusterCredentialProvider) DeepCopy() *ClusterCredentialProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCredentialProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHealth) DeepCopyInto(out *ClusterHealth) {
+	*out = *in
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = make([]ClusterHealthProbe, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Transitions != nil {
+		in, out := &in.Transitions, &out.Transitions
+		*out = make([]ClusterHealthTransition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHealth.
+func (in *ClusterHealth) DeepCopy() *ClusterHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHealthCheck) DeepCopyInto(out *ClusterHealthCheck) {
+	*out = *in
+	if in.Period != nil {
+		in, out := &in.Period, &out.Period
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MinSchedulableNodes != nil {
+		in, out := &in.MinSchedulableNodes, &out.MinSchedulableNodes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RequiredAPIResources != nil {
+		in, out := &in.RequiredAPIResources, &out.RequiredAPIResources
+		*out = make([]RequiredAPIResource, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHealthCheck.
+func (in *ClusterHealthCheck) DeepCopy() *ClusterHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHealthProbe) DeepCopyInto(out *ClusterHealthProbe) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.HealthCheckLatency != nil {
+		in, out := &in.HealthCheckLatency, &out.HealthCheckLatency
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHealthProbe.
+func (in *ClusterHealthProbe) DeepCopy() *ClusterHealthProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHealthProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHealthTransition) DeepCopyInto(out *ClusterHealthTransition) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHealthTransition.
+func (in *ClusterHealthTransition) DeepCopy() *ClusterHealthTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHealthTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMaintenance) DeepCopyInto(out *ClusterMaintenance) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMaintenance.
+func (in *ClusterMaintenance) DeepCopy() *ClusterMaintenance {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMaintenance)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterObjectVersion) DeepCopyInto(out *ClusterObjectVersion) {
 	*out = *in
@@ -297,6 +450,27 @@ func (in *ClusterPropagationPolicyList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterProxy) DeepCopyInto(out *ClusterProxy) {
+	*out = *in
+	if in.HeadersSecretRef != nil {
+		in, out := &in.HeadersSecretRef, &out.HeadersSecretRef
+		*out = new(LocalSecretReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterProxy.
+func (in *ClusterProxy) DeepCopy() *ClusterProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterSelectorRequirement) DeepCopyInto(out *ClusterSelectorRequirement) {
 	*out = *in
@@ -348,6 +522,69 @@ func (in *ClusterSelectorTerm) DeepCopy() *ClusterSelectorTerm {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisruptionBudget) DeepCopyInto(out *DisruptionBudget) {
+	*out = *in
+	if in.MaxPreemptedReplicas != nil {
+		in, out := &in.MaxPreemptedReplicas, &out.MaxPreemptedReplicas
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionBudget.
+func (in *DisruptionBudget) DeepCopy() *DisruptionBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(DisruptionBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecCredentialProvider) DeepCopyInto(out *ExecCredentialProvider) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]ExecEnvVar, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecCredentialProvider.
+func (in *ExecCredentialProvider) DeepCopy() *ExecCredentialProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecCredentialProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExecEnvVar) DeepCopyInto(out *ExecEnvVar) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExecEnvVar.
+func (in *ExecEnvVar) DeepCopy() *ExecEnvVar {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecEnvVar)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FederatedCluster) DeepCopyInto(out *FederatedCluster) {
 	*out = *in
@@ -413,6 +650,16 @@ func (in *FederatedClusterList) DeepCopyObject() runtime.Object {
 func (in *FederatedClusterSpec) DeepCopyInto(out *FederatedClusterSpec) {
 	*out = *in
 	out.SecretRef = in.SecretRef
+	if in.CredentialProvider != nil {
+		in, out := &in.CredentialProvider, &out.CredentialProvider
+		*out = new(ClusterCredentialProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(ClusterProxy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Taints != nil {
 		in, out := &in.Taints, &out.Taints
 		*out = make([]corev1.Taint, len(*in))
@@ -420,6 +667,16 @@ func (in *FederatedClusterSpec) DeepCopyInto(out *FederatedClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(ClusterMaintenance)
+		**out = **in
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(ClusterHealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -449,6 +706,11 @@ func (in *FederatedClusterStatus) DeepCopyInto(out *FederatedClusterStatus) {
 		*out = make([]APIResource, len(*in))
 		copy(*out, *in)
 	}
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(ClusterHealth)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -563,6 +825,21 @@ func (in *FederatedTypeConfigSpec) DeepCopyInto(out *FederatedTypeConfigSpec) {
 		*out = new(AutoMigrationConfig)
 		**out = **in
 	}
+	if in.DispatchMode != nil {
+		in, out := &in.DispatchMode, &out.DispatchMode
+		*out = new(DispatchMode)
+		**out = **in
+	}
+	if in.RetainFields != nil {
+		in, out := &in.RetainFields, &out.RetainFields
+		*out = make([]RetainField, len(*in))
+		copy(*out, *in)
+	}
+	if in.MetadataPropagation != nil {
+		in, out := &in.MetadataPropagation, &out.MetadataPropagation
+		*out = new(MetadataPropagation)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Controllers != nil {
 		in, out := &in.Controllers, &out.Controllers
 		*out = make([][]string, len(*in))
@@ -604,6 +881,27 @@ func (in *FederatedTypeConfigStatus) DeepCopy() *FederatedTypeConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GangScheduling) DeepCopyInto(out *GangScheduling) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GangScheduling.
+func (in *GangScheduling) DeepCopy() *GangScheduling {
+	if in == nil {
+		return nil
+	}
+	out := new(GangScheduling)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GenericOverridePolicySpec) DeepCopyInto(out *GenericOverridePolicySpec) {
 	*out = *in
@@ -665,6 +963,50 @@ func (in *JsonPatchOverrider) DeepCopy() *JsonPatchOverrider {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeyMatcher) DeepCopyInto(out *KeyMatcher) {
+	*out = *in
+	in.Include.DeepCopyInto(&out.Include)
+	in.Exclude.DeepCopyInto(&out.Exclude)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyMatcher.
+func (in *KeyMatcher) DeepCopy() *KeyMatcher {
+	if in == nil {
+		return nil
+	}
+	out := new(KeyMatcher)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeySelector) DeepCopyInto(out *KeySelector) {
+	*out = *in
+	if in.Prefixes != nil {
+		in, out := &in.Prefixes, &out.Prefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Regexps != nil {
+		in, out := &in.Regexps, &out.Regexps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeySelector.
+func (in *KeySelector) DeepCopy() *KeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(KeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LocalSecretReference) DeepCopyInto(out *LocalSecretReference) {
 	*out = *in
@@ -681,6 +1023,84 @@ func (in *LocalSecretReference) DeepCopy() *LocalSecretReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataClassification) DeepCopyInto(out *MetadataClassification) {
+	*out = *in
+	if in.FederatedOnly != nil {
+		in, out := &in.FederatedOnly, &out.FederatedOnly
+		*out = new(KeyMatcher)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TemplateOnly != nil {
+		in, out := &in.TemplateOnly, &out.TemplateOnly
+		*out = new(KeyMatcher)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Ignored != nil {
+		in, out := &in.Ignored, &out.Ignored
+		*out = new(KeyMatcher)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataClassification.
+func (in *MetadataClassification) DeepCopy() *MetadataClassification {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataClassification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataPropagation) DeepCopyInto(out *MetadataPropagation) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = new(MetadataClassification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = new(MetadataClassification)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataPropagation.
+func (in *MetadataPropagation) DeepCopy() *MetadataPropagation {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataPropagation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCCredentialProvider) DeepCopyInto(out *OIDCCredentialProvider) {
+	*out = *in
+	if in.ExtraScopes != nil {
+		in, out := &in.ExtraScopes, &out.ExtraScopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCCredentialProvider.
+func (in *OIDCCredentialProvider) DeepCopy() *OIDCCredentialProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCCredentialProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OverridePolicy) DeepCopyInto(out *OverridePolicy) {
 	*out = *in
@@ -932,6 +1352,11 @@ func (in *Preferences) DeepCopyInto(out *Preferences) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.Percentage != nil {
+		in, out := &in.Percentage, &out.Percentage
+		*out = new(int64)
+		**out = **in
+	}
 	return
 }
 
@@ -1123,6 +1548,21 @@ func (in *PropagationPolicySpec) DeepCopyInto(out *PropagationPolicySpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.WorkloadAffinity != nil {
+		in, out := &in.WorkloadAffinity, &out.WorkloadAffinity
+		*out = new(WorkloadAffinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DisruptionBudget != nil {
+		in, out := &in.DisruptionBudget, &out.DisruptionBudget
+		*out = new(DisruptionBudget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GangScheduling != nil {
+		in, out := &in.GangScheduling, &out.GangScheduling
+		*out = new(GangScheduling)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AutoMigration != nil {
 		in, out := &in.AutoMigration, &out.AutoMigration
 		*out = new(AutoMigration)
@@ -1179,6 +1619,22 @@ func (in *ReplicaRescheduling) DeepCopy() *ReplicaRescheduling {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequiredAPIResource) DeepCopyInto(out *RequiredAPIResource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequiredAPIResource.
+func (in *RequiredAPIResource) DeepCopy() *RequiredAPIResource {
+	if in == nil {
+		return nil
+	}
+	out := new(RequiredAPIResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Resources) DeepCopyInto(out *Resources) {
 	*out = *in
@@ -1214,6 +1670,22 @@ func (in *Resources) DeepCopy() *Resources {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetainField) DeepCopyInto(out *RetainField) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetainField.
+func (in *RetainField) DeepCopy() *RetainField {
+	if in == nil {
+		return nil
+	}
+	out := new(RetainField)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SchedulerPluginWebhookConfiguration) DeepCopyInto(out *SchedulerPluginWebhookConfiguration) {
 	*out = *in
@@ -1445,6 +1917,22 @@ func (in *TargetClusters) DeepCopy() *TargetClusters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenFileCredentialProvider) DeepCopyInto(out *TokenFileCredentialProvider) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenFileCredentialProvider.
+func (in *TokenFileCredentialProvider) DeepCopy() *TokenFileCredentialProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenFileCredentialProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TypedRefCount) DeepCopyInto(out *TypedRefCount) {
 	*out = *in
@@ -1491,3 +1979,55 @@ func (in *WebhookTLSConfig) DeepCopy() *WebhookTLSConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadAffinity) DeepCopyInto(out *WorkloadAffinity) {
+	*out = *in
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = make([]WorkloadAffinityTerm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AntiAffinity != nil {
+		in, out := &in.AntiAffinity, &out.AntiAffinity
+		*out = make([]WorkloadAffinityTerm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadAffinity.
+func (in *WorkloadAffinity) DeepCopy() *WorkloadAffinity {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadAffinity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadAffinityTerm) DeepCopyInto(out *WorkloadAffinityTerm) {
+	*out = *in
+	in.LabelSelector.DeepCopyInto(&out.LabelSelector)
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadAffinityTerm.
+func (in *WorkloadAffinityTerm) DeepCopy() *WorkloadAffinityTerm {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadAffinityTerm)
+	in.DeepCopyInto(out)
+	return out
+}
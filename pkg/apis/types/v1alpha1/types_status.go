@@ -63,6 +63,9 @@ type GenericClusterStatus struct {
 	Name       string            `json:"name"`
 	Status     PropagationStatus `json:"status,omitempty"`
 	Generation int64             `json:"generation,omitempty"`
+	// DriftedFields are the fields of the object in the cluster that were changed in the cluster and differ from
+	// their desired values when the object was last synced.
+	DriftedFields []string `json:"driftedFields,omitempty"`
 }
 
 type PropagationStatus string
@@ -70,6 +73,15 @@ type PropagationStatus string
 const (
 	ClusterPropagationOK PropagationStatus = "OK"
 	WaitingForRemoval    PropagationStatus = "WaitingForRemoval"
+	// WaitingForAgent means the object has not yet been synced by the agent of a pull-mode cluster.
+	WaitingForAgent PropagationStatus = "WaitingForAgent"
+	// WaitingForDrain means the object is kept in a draining cluster until its replicas in other clusters are available.
+	WaitingForDrain PropagationStatus = "WaitingForDrain"
+	// DrainBlocked means the object is kept in a draining cluster because it is not placed in any other cluster.
+	DrainBlocked PropagationStatus = "DrainBlocked"
+	// Drifted means the object was changed in the cluster and the drift is only reported according to the drift
+	// policy.
+	Drifted PropagationStatus = "Drifted"
 
 	// Cluster-specific errors
 
@@ -90,6 +102,9 @@ const (
 	ClientRetrievalFailed       PropagationStatus = "ClientRetrievalFailed"
 	ManagedLabelFalse           PropagationStatus = "ManagedLabelFalse"
 	FinalizerCheckFailed        PropagationStatus = "FinalizerCheckFailed"
+	DrainCheckFailed            PropagationStatus = "DrainCheckFailed"
+	// ApplyConflict means a server-side apply conflicted with the fields owned by another field manager.
+	ApplyConflict PropagationStatus = "ApplyConflict"
 
 	// Operation timeout errors
 
@@ -110,10 +125,24 @@ const (
 	CheckClusters          AggregateReason = "CheckClusters"
 	NamespaceNotFederated  AggregateReason = "NamespaceNotFederated"
 	EnsureDeletionFailed   AggregateReason = "EnsureDeletionFailed"
+	InsufficientCapacity   AggregateReason = "InsufficientCapacity"
+
+	// Reasons of the Paused condition
+
+	// ObjectPaused means the propagation of the federated object is paused by its paused annotation.
+	ObjectPaused AggregateReason = "ObjectPaused"
+	// NamespacePaused means the propagation of the objects in the namespace is paused by its paused label.
+	NamespacePaused AggregateReason = "NamespacePaused"
+	// FederationPaused means the propagation of all federated objects is paused.
+	FederationPaused AggregateReason = "FederationPaused"
 )
 
 type ConditionType string
 
 const (
-	PropagationConditionType ConditionType = "Propagation"
+	PropagationConditionType   ConditionType = "Propagation"
+	GangScheduledConditionType ConditionType = "GangScheduled"
+	// PausedConditionType indicates whether the sync controller skips dispatching the federated object to member
+	// clusters.
+	PausedConditionType ConditionType = "Paused"
 )
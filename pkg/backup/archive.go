@@ -0,0 +1,189 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ArchiveVersion is the version of the format of backup archives.
+const ArchiveVersion = "v1alpha1"
+
+const metadataFileName = "metadata.json"
+
+// Archive is a portable backup of the state of a KubeAdmiral control plane.
+type Archive struct {
+	Metadata Metadata
+	// Resources are stored in the order in which they are restored.
+	Resources []Resources
+}
+
+// Metadata describes a backup archive.
+type Metadata struct {
+	Version            string         `json:"version"`
+	CreationTimestamp  metav1.Time    `json:"creationTimestamp"`
+	FedSystemNamespace string         `json:"fedSystemNamespace"`
+	Resources          []ResourceInfo `json:"resources"`
+}
+
+// ResourceInfo describes the objects of a resource in a backup archive.
+type ResourceInfo struct {
+	Group      string `json:"group,omitempty"`
+	Version    string `json:"version"`
+	Resource   string `json:"resource"`
+	Namespaced bool   `json:"namespaced,omitempty"`
+	// Federated indicates that the objects are federated objects, which are paused when they are restored.
+	Federated bool `json:"federated,omitempty"`
+	// File is the name of the file containing the objects in the archive.
+	File string `json:"file"`
+}
+
+func (r *ResourceInfo) GroupVersionResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Resource}
+}
+
+func (r *ResourceInfo) String() string {
+	return r.GroupVersionResource().GroupResource().String()
+}
+
+// Resources holds the objects of a resource.
+type Resources struct {
+	ResourceInfo
+	Items []*unstructured.Unstructured
+}
+
+// Write writes the archive to w as a gzipped tarball.
+func (a *Archive) Write(w io.Writer) error {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	metadata := a.Metadata
+	metadata.Resources = make([]ResourceInfo, 0, len(a.Resources))
+	for i := range a.Resources {
+		resources := &a.Resources[i]
+
+		info := resources.ResourceInfo
+		info.File = fmt.Sprintf("resources/%03d-%s.json", i, info.String())
+		metadata.Resources = append(metadata.Resources, info)
+
+		items := resources.Items
+		if items == nil {
+			items = []*unstructured.Unstructured{}
+		}
+		if err := writeJSONFile(tarWriter, info.File, metadata.CreationTimestamp.Time, items); err != nil {
+			return err
+		}
+	}
+
+	if err := writeJSONFile(tarWriter, metadataFileName, metadata.CreationTimestamp.Time, &metadata); err != nil {
+		return err
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("failed to close tar writer: %w", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return fmt.Errorf("failed to close gzip writer: %w", err)
+	}
+	return nil
+}
+
+func writeJSONFile(tarWriter *tar.Writer, name string, modTime time.Time, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+
+	if err := tarWriter.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o600,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}); err != nil {
+		return fmt.Errorf("failed to write header of %s: %w", name, err)
+	}
+	if _, err := tarWriter.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// ReadArchive reads an archive written by Archive.Write.
+func ReadArchive(r io.Reader) (*Archive, error) {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gzip stream: %w", err)
+	}
+	defer gzipReader.Close()
+
+	files := map[string][]byte{}
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar stream: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		data, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+		files[header.Name] = data
+	}
+
+	metadataData, exists := files[metadataFileName]
+	if !exists {
+		return nil, fmt.Errorf("%s is missing from the archive", metadataFileName)
+	}
+	archive := &Archive{}
+	if err := json.Unmarshal(metadataData, &archive.Metadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", metadataFileName, err)
+	}
+	if archive.Metadata.Version != ArchiveVersion {
+		return nil, fmt.Errorf("unsupported archive version %q", archive.Metadata.Version)
+	}
+
+	for _, info := range archive.Metadata.Resources {
+		data, exists := files[info.File]
+		if !exists {
+			return nil, fmt.Errorf("%s is missing from the archive", info.File)
+		}
+
+		var items []*unstructured.Unstructured
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %w", info.File, err)
+		}
+		archive.Resources = append(archive.Resources, Resources{ResourceInfo: info, Items: items})
+	}
+
+	return archive, nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	policy := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "core.kubeadmiral.io/v1alpha1",
+		"kind":       "PropagationPolicy",
+		"metadata": map[string]interface{}{
+			"name":      "policy",
+			"namespace": "default",
+		},
+		"spec": map[string]interface{}{
+			"schedulingMode": "Divide",
+		},
+	}}
+
+	archive := &Archive{
+		Metadata: Metadata{
+			Version:            ArchiveVersion,
+			CreationTimestamp:  metav1.Unix(1000, 0),
+			FedSystemNamespace: "kube-admiral-system",
+		},
+		Resources: []Resources{
+			{
+				ResourceInfo: ResourceInfo{
+					Group:      "core.kubeadmiral.io",
+					Version:    "v1alpha1",
+					Resource:   "propagationpolicies",
+					Namespaced: true,
+				},
+				Items: []*unstructured.Unstructured{policy},
+			},
+			{
+				ResourceInfo: ResourceInfo{
+					Group:    "core.kubeadmiral.io",
+					Version:  "v1alpha1",
+					Resource: "clusterpropagationpolicies",
+				},
+			},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	g.Expect(archive.Write(buf)).To(gomega.Succeed())
+
+	read, err := ReadArchive(buf)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(read.Metadata.FedSystemNamespace).To(gomega.Equal("kube-admiral-system"))
+	g.Expect(read.Metadata.CreationTimestamp.Equal(&archive.Metadata.CreationTimestamp)).To(gomega.BeTrue())
+	g.Expect(read.Resources).To(gomega.HaveLen(2))
+	g.Expect(read.Resources[0].GroupVersionResource()).To(gomega.Equal(archive.Resources[0].GroupVersionResource()))
+	g.Expect(read.Resources[0].Namespaced).To(gomega.BeTrue())
+	g.Expect(read.Resources[0].Items).To(gomega.Equal([]*unstructured.Unstructured{policy}))
+	g.Expect(read.Resources[1].Items).To(gomega.BeEmpty())
+}
+
+func TestReadArchiveRejectsUnknownVersion(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	archive := &Archive{Metadata: Metadata{Version: "v0"}}
+	buf := &bytes.Buffer{}
+	g.Expect(archive.Write(buf)).To(gomega.Succeed())
+
+	_, err := ReadArchive(buf)
+	g.Expect(err).To(gomega.HaveOccurred())
+}
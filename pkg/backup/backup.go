@@ -0,0 +1,201 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	schemautil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/schema"
+)
+
+var (
+	federatedTypeConfigsResource = fedcorev1a1.SchemeGroupVersion.WithResource("federatedtypeconfigs")
+	federatedClustersResource    = fedcorev1a1.SchemeGroupVersion.WithResource("federatedclusters")
+	secretsResource              = corev1.SchemeGroupVersion.WithResource("secrets")
+	namespacesResource           = corev1.SchemeGroupVersion.WithResource("namespaces")
+
+	policyResources = []ResourceInfo{
+		resourceInfo(fedcorev1a1.SchemeGroupVersion.WithResource("schedulingprofiles"), false),
+		resourceInfo(fedcorev1a1.SchemeGroupVersion.WithResource("schedulerpluginwebhookconfigurations"), false),
+		resourceInfo(fedcorev1a1.SchemeGroupVersion.WithResource("clusterpropagationpolicies"), false),
+		resourceInfo(fedcorev1a1.SchemeGroupVersion.WithResource("propagationpolicies"), true),
+		resourceInfo(fedcorev1a1.SchemeGroupVersion.WithResource("clusteroverridepolicies"), false),
+		resourceInfo(fedcorev1a1.SchemeGroupVersion.WithResource("overridepolicies"), true),
+	}
+
+	versionResources = []ResourceInfo{
+		resourceInfo(fedcorev1a1.SchemeGroupVersion.WithResource("clusterpropagatedversions"), false),
+		resourceInfo(fedcorev1a1.SchemeGroupVersion.WithResource("propagatedversions"), true),
+	}
+)
+
+func resourceInfo(gvr schema.GroupVersionResource, namespaced bool) ResourceInfo {
+	return ResourceInfo{Group: gvr.Group, Version: gvr.Version, Resource: gvr.Resource, Namespaced: namespaced}
+}
+
+// Backup exports the state of a KubeAdmiral control plane, in the order in which it is restored:
+//
+//  1. FederatedTypeConfigs, which must be restored before the federated objects of their types.
+//  2. Scheduling profiles, scheduler plugin webhook configurations, propagation policies and override policies.
+//  3. Federated objects.
+//  4. Propagated versions, which record the versions of the objects in member clusters.
+//  5. The secrets of FederatedClusters and the FederatedClusters. They are restored last, so that the sync
+//     controller does not observe objects in member clusters before their federated objects are restored.
+func Backup(ctx context.Context, dynamicClient dynamic.Interface, fedSystemNamespace string) (*Archive, error) {
+	archive := &Archive{
+		Metadata: Metadata{
+			Version:            ArchiveVersion,
+			CreationTimestamp:  metav1.Now(),
+			FedSystemNamespace: fedSystemNamespace,
+		},
+	}
+
+	ftcs, err := listObjects(ctx, dynamicClient, resourceInfo(federatedTypeConfigsResource, false))
+	if err != nil {
+		return nil, err
+	}
+	archive.Resources = append(archive.Resources, Resources{
+		ResourceInfo: resourceInfo(federatedTypeConfigsResource, false),
+		Items:        ftcs,
+	})
+
+	for _, info := range policyResources {
+		items, err := listObjects(ctx, dynamicClient, info)
+		if err != nil {
+			return nil, err
+		}
+		archive.Resources = append(archive.Resources, Resources{ResourceInfo: info, Items: items})
+	}
+
+	for _, ftcObj := range ftcs {
+		ftc := &fedcorev1a1.FederatedTypeConfig{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(ftcObj.Object, ftc); err != nil {
+			return nil, fmt.Errorf("failed to convert FederatedTypeConfig %s: %w", ftcObj.GetName(), err)
+		}
+
+		federatedType := ftc.GetFederatedType()
+		info := resourceInfo(schemautil.APIResourceToGVR(&federatedType), ftc.GetNamespaced())
+		info.Federated = true
+		items, err := listObjects(ctx, dynamicClient, info)
+		if apierrors.IsNotFound(err) {
+			// The CRD of the federated type is not installed.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		archive.Resources = append(archive.Resources, Resources{ResourceInfo: info, Items: items})
+	}
+
+	for _, info := range versionResources {
+		items, err := listObjects(ctx, dynamicClient, info)
+		if err != nil {
+			return nil, err
+		}
+		archive.Resources = append(archive.Resources, Resources{ResourceInfo: info, Items: items})
+	}
+
+	clusters, err := listObjects(ctx, dynamicClient, resourceInfo(federatedClustersResource, false))
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := clusterSecrets(ctx, dynamicClient, fedSystemNamespace, clusters)
+	if err != nil {
+		return nil, err
+	}
+	archive.Resources = append(archive.Resources,
+		Resources{ResourceInfo: resourceInfo(secretsResource, true), Items: secrets},
+		Resources{ResourceInfo: resourceInfo(federatedClustersResource, false), Items: clusters},
+	)
+
+	return archive, nil
+}
+
+// listObjects lists the objects of a resource in all namespaces. Objects being deleted are skipped.
+func listObjects(
+	ctx context.Context,
+	dynamicClient dynamic.Interface,
+	info ResourceInfo,
+) ([]*unstructured.Unstructured, error) {
+	list, err := dynamicClient.Resource(info.GroupVersionResource()).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", info.String(), err)
+	}
+
+	items := make([]*unstructured.Unstructured, 0, len(list.Items))
+	for i := range list.Items {
+		item := &list.Items[i]
+		if item.GetDeletionTimestamp() != nil {
+			continue
+		}
+		item.SetManagedFields(nil)
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// clusterSecrets returns the secrets referenced by the given FederatedClusters.
+func clusterSecrets(
+	ctx context.Context,
+	dynamicClient dynamic.Interface,
+	fedSystemNamespace string,
+	clusters []*unstructured.Unstructured,
+) ([]*unstructured.Unstructured, error) {
+	secretNames := sets.New[string]()
+	for _, clusterObj := range clusters {
+		cluster := &fedcorev1a1.FederatedCluster{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(clusterObj.Object, cluster); err != nil {
+			return nil, fmt.Errorf("failed to convert FederatedCluster %s: %w", clusterObj.GetName(), err)
+		}
+
+		if cluster.Spec.SecretRef.Name != "" {
+			secretNames.Insert(cluster.Spec.SecretRef.Name)
+		}
+		if proxy := cluster.Spec.Proxy; proxy != nil && proxy.HeadersSecretRef != nil {
+			secretNames.Insert(proxy.HeadersSecretRef.Name)
+		}
+	}
+
+	secrets := make([]*unstructured.Unstructured, 0, secretNames.Len())
+	for _, name := range sets.List(secretNames) {
+		secret, err := dynamicClient.Resource(secretsResource).Namespace(fedSystemNamespace).Get(
+			ctx,
+			name,
+			metav1.GetOptions{},
+		)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret %s/%s: %w", fedSystemNamespace, name, err)
+		}
+		secret.SetManagedFields(nil)
+		secrets = append(secrets, secret)
+	}
+	return secrets, nil
+}
@@ -0,0 +1,398 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/pendingcontrollers"
+	schemautil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/schema"
+)
+
+// Restorer restores archives to a KubeAdmiral control plane.
+//
+// Federated objects are restored with their pending controllers replaced by common.RestorePendingController, which
+// holds back all controllers of the objects, including the sync controller, until Resume is called. This prevents
+// objects in member clusters from being updated or deleted while the control plane is partially restored.
+type Restorer struct {
+	dynamicClient dynamic.Interface
+	restMapper    meta.RESTMapper
+	out           io.Writer
+
+	// Timeout is the maximum time to wait for the resources of federated types to be served.
+	Timeout time.Duration
+}
+
+func NewRestorer(dynamicClient dynamic.Interface, restMapper meta.RESTMapper, out io.Writer) *Restorer {
+	return &Restorer{
+		dynamicClient: dynamicClient,
+		restMapper:    restMapper,
+		out:           out,
+		Timeout:       2 * time.Minute,
+	}
+}
+
+// Restore creates the objects in the archive. Objects that already exist are left unchanged.
+func (r *Restorer) Restore(ctx context.Context, archive *Archive) error {
+	for i := range archive.Resources {
+		resources := &archive.Resources[i]
+
+		if err := r.waitForResource(resources.GroupVersionResource()); err != nil {
+			return err
+		}
+
+		created, existing := 0, 0
+		for _, item := range resources.Items {
+			obj := prepareForRestore(item)
+			if resources.Federated {
+				if err := pauseFederatedObject(obj); err != nil {
+					return fmt.Errorf("failed to pause %s %s: %w", resources.String(), objectKey(obj), err)
+				}
+			}
+
+			ok, err := r.restoreObject(ctx, &resources.ResourceInfo, obj)
+			if err != nil {
+				return fmt.Errorf("failed to restore %s %s: %w", resources.String(), objectKey(obj), err)
+			}
+			if ok {
+				created++
+			} else {
+				existing++
+			}
+		}
+
+		fmt.Fprintf(r.out, "%s: %d restored, %d already exist\n", resources.String(), created, existing)
+	}
+
+	return nil
+}
+
+// restoreObject creates the object and restores its status. It returns false if the object already exists.
+func (r *Restorer) restoreObject(ctx context.Context, info *ResourceInfo, obj *unstructured.Unstructured) (bool, error) {
+	if info.Namespaced {
+		if err := r.ensureNamespace(ctx, obj.GetNamespace()); err != nil {
+			return false, err
+		}
+	}
+
+	if err := r.resolveOwnerReferences(ctx, obj); err != nil {
+		return false, err
+	}
+
+	var resourceClient dynamic.ResourceInterface = r.dynamicClient.Resource(info.GroupVersionResource())
+	if info.Namespaced {
+		resourceClient = r.dynamicClient.Resource(info.GroupVersionResource()).Namespace(obj.GetNamespace())
+	}
+
+	created, err := resourceClient.Create(ctx, obj, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// The status of FederatedClusters is recomputed from the clusters, and the cluster controller must observe the
+	// clusters becoming ready.
+	status, hasStatus := obj.Object[common.StatusField]
+	if !hasStatus || info.GroupVersionResource() == federatedClustersResource {
+		return true, nil
+	}
+
+	created.Object[common.StatusField] = status
+	if _, err := resourceClient.UpdateStatus(ctx, created, metav1.UpdateOptions{}); err != nil &&
+		!apierrors.IsNotFound(err) {
+		// NotFound is returned if the resource has no status subresource, in which case the status was created
+		// together with the object.
+		return true, fmt.Errorf("failed to restore status: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *Restorer) ensureNamespace(ctx context.Context, namespace string) error {
+	namespaceClient := r.dynamicClient.Resource(namespacesResource)
+
+	_, err := namespaceClient.Get(ctx, namespace, metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+	}
+
+	namespaceObj := &unstructured.Unstructured{}
+	namespaceObj.SetAPIVersion(corev1.SchemeGroupVersion.String())
+	namespaceObj.SetKind(common.NamespaceKind)
+	namespaceObj.SetName(namespace)
+	if _, err := namespaceClient.Create(ctx, namespaceObj, metav1.CreateOptions{}); err != nil &&
+		!apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create namespace %s: %w", namespace, err)
+	}
+	return nil
+}
+
+// resolveOwnerReferences updates the UIDs of the owners of the object to those of the owners in the control plane,
+// and removes the references to owners that do not exist. Otherwise the garbage collector would delete the object
+// because the owners in the backup no longer exist.
+func (r *Restorer) resolveOwnerReferences(ctx context.Context, obj *unstructured.Unstructured) error {
+	ownerReferences := obj.GetOwnerReferences()
+	if len(ownerReferences) == 0 {
+		return nil
+	}
+
+	resolved := make([]metav1.OwnerReference, 0, len(ownerReferences))
+	for _, ownerReference := range ownerReferences {
+		gv, err := schema.ParseGroupVersion(ownerReference.APIVersion)
+		if err != nil {
+			return fmt.Errorf("invalid owner reference %s: %w", ownerReference.Name, err)
+		}
+
+		mapping, err := r.restMapper.RESTMapping(gv.WithKind(ownerReference.Kind).GroupKind(), gv.Version)
+		if meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get mapping of %s: %w", ownerReference.Kind, err)
+		}
+
+		var owner *unstructured.Unstructured
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			owner, err = r.dynamicClient.Resource(mapping.Resource).Namespace(obj.GetNamespace()).Get(
+				ctx,
+				ownerReference.Name,
+				metav1.GetOptions{},
+			)
+		} else {
+			owner, err = r.dynamicClient.Resource(mapping.Resource).Get(ctx, ownerReference.Name, metav1.GetOptions{})
+		}
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get owner %s %s: %w", ownerReference.Kind, ownerReference.Name, err)
+		}
+
+		ownerReference.UID = owner.GetUID()
+		resolved = append(resolved, ownerReference)
+	}
+
+	obj.SetOwnerReferences(resolved)
+	return nil
+}
+
+// waitForResource waits for the resource to be served, e.g. for the CRD of a federated type to be created by the
+// FederatedTypeConfig controller.
+func (r *Restorer) waitForResource(gvr schema.GroupVersionResource) error {
+	err := wait.PollImmediate(time.Second, r.Timeout, func() (bool, error) {
+		_, err := r.restMapper.KindFor(gvr)
+		if err == nil {
+			return true, nil
+		}
+		if !meta.IsNoMatchError(err) {
+			return false, err
+		}
+		if resettable, ok := r.restMapper.(meta.ResettableRESTMapper); ok {
+			resettable.Reset()
+		}
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to wait for %s to be served: %w", gvr.GroupResource().String(), err)
+	}
+	return nil
+}
+
+// WaitForClusters waits for the FederatedClusters in the archive to become ready.
+func (r *Restorer) WaitForClusters(ctx context.Context, archive *Archive, timeout time.Duration) error {
+	var clusterNames []string
+	for i := range archive.Resources {
+		if archive.Resources[i].GroupVersionResource() != federatedClustersResource {
+			continue
+		}
+		for _, item := range archive.Resources[i].Items {
+			clusterNames = append(clusterNames, item.GetName())
+		}
+	}
+
+	clusterClient := r.dynamicClient.Resource(federatedClustersResource)
+	for _, name := range clusterNames {
+		fmt.Fprintf(r.out, "Waiting for cluster %s to become ready\n", name)
+		err := wait.PollImmediate(time.Second, timeout, func() (bool, error) {
+			clusterObj, err := clusterClient.Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			cluster := &fedcorev1a1.FederatedCluster{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(clusterObj.Object, cluster); err != nil {
+				return false, err
+			}
+			return util.IsClusterReady(&cluster.Status), nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to wait for cluster %s to become ready: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Resume releases the federated objects paused by Restore by restoring their pending controllers.
+func (r *Restorer) Resume(ctx context.Context) error {
+	ftcList, err := r.dynamicClient.Resource(federatedTypeConfigsResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list FederatedTypeConfigs: %w", err)
+	}
+
+	for i := range ftcList.Items {
+		ftc := &fedcorev1a1.FederatedTypeConfig{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(ftcList.Items[i].Object, ftc); err != nil {
+			return fmt.Errorf("failed to convert FederatedTypeConfig %s: %w", ftcList.Items[i].GetName(), err)
+		}
+
+		federatedType := ftc.GetFederatedType()
+		resourceClient := r.dynamicClient.Resource(schemautil.APIResourceToGVR(&federatedType))
+		objects, err := resourceClient.List(ctx, metav1.ListOptions{})
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list %s: %w", federatedType.Kind, err)
+		}
+
+		resumed := 0
+		for j := range objects.Items {
+			obj := &objects.Items[j]
+
+			ok, err := resumeFederatedObject(obj, ftc)
+			if err != nil {
+				return fmt.Errorf("failed to resume %s %s: %w", federatedType.Kind, objectKey(obj), err)
+			}
+			if !ok {
+				continue
+			}
+
+			if _, err := resourceClient.Namespace(obj.GetNamespace()).Update(ctx, obj, metav1.UpdateOptions{}); err != nil {
+				return fmt.Errorf("failed to resume %s %s: %w", federatedType.Kind, objectKey(obj), err)
+			}
+			resumed++
+		}
+
+		if resumed > 0 {
+			fmt.Fprintf(r.out, "%s: %d resumed\n", federatedType.Kind, resumed)
+		}
+	}
+
+	return nil
+}
+
+// prepareForRestore returns a copy of the object without the fields set by the apiserver.
+func prepareForRestore(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	obj = obj.DeepCopy()
+	obj.SetUID("")
+	obj.SetResourceVersion("")
+	obj.SetGeneration(0)
+	obj.SetCreationTimestamp(metav1.Time{})
+	obj.SetDeletionTimestamp(nil)
+	obj.SetDeletionGracePeriodSeconds(nil)
+	obj.SetManagedFields(nil)
+	obj.SetSelfLink("")
+	return obj
+}
+
+// pauseFederatedObject replaces the pending controllers of a federated object with common.RestorePendingController
+// and records them in an annotation.
+func pauseFederatedObject(obj *unstructured.Unstructured) error {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	pending, exists := annotations[pendingcontrollers.PendingControllersAnnotation]
+	if !exists {
+		pending = "[]"
+	}
+	annotations[common.RestoredPendingControllersAnnotation] = pending
+	obj.SetAnnotations(annotations)
+
+	_, err := pendingcontrollers.SetPendingControllers(
+		obj,
+		pendingcontrollers.PendingControllers{{common.RestorePendingController}},
+	)
+	return err
+}
+
+// resumeFederatedObject restores the pending controllers of a federated object paused by pauseFederatedObject. If
+// the object has already been scheduled, the scheduler is instructed to adopt its restored placements. It returns
+// false if the object is not paused.
+func resumeFederatedObject(obj *unstructured.Unstructured, ftc *fedcorev1a1.FederatedTypeConfig) (bool, error) {
+	annotations := obj.GetAnnotations()
+	pending, exists := annotations[common.RestoredPendingControllersAnnotation]
+	if !exists {
+		return false, nil
+	}
+
+	var pendingControllers pendingcontrollers.PendingControllers
+	if err := json.Unmarshal([]byte(pending), &pendingControllers); err != nil {
+		return false, fmt.Errorf("failed to unmarshal restored pending controllers: %w", err)
+	}
+
+	delete(annotations, common.RestoredPendingControllersAnnotation)
+	if hasController(ftc.GetControllers(), scheduler.PrefixedGlobalSchedulerName) &&
+		!hasController(pendingControllers, scheduler.PrefixedGlobalSchedulerName) {
+		annotations[common.RestoredAnnotation] = common.AnnotationValueTrue
+	}
+	obj.SetAnnotations(annotations)
+
+	if _, err := pendingcontrollers.SetPendingControllers(obj, pendingControllers); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func hasController(controllers [][]string, name string) bool {
+	for _, step := range controllers {
+		for _, controller := range step {
+			if controller == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func objectKey(obj *unstructured.Unstructured) string {
+	return common.NewQualifiedName(obj).String()
+}
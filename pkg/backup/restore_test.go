@@ -0,0 +1,251 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/pendingcontrollers"
+)
+
+var federatedDeploymentsResource = schema.GroupVersionResource{
+	Group:    "types.kubeadmiral.io",
+	Version:  "v1alpha1",
+	Resource: "federateddeployments",
+}
+
+func newFederatedDeployment(annotations map[string]string, ownerReferences ...metav1.OwnerReference) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("types.kubeadmiral.io/v1alpha1")
+	obj.SetKind("FederatedDeployment")
+	obj.SetNamespace("default")
+	obj.SetName("web")
+	obj.SetUID("old-uid")
+	obj.SetResourceVersion("10")
+	obj.SetGeneration(3)
+	obj.SetAnnotations(annotations)
+	obj.SetOwnerReferences(ownerReferences)
+	return obj
+}
+
+func newFederatedDeploymentTypeConfig(controllers [][]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": fedcorev1a1.SchemeGroupVersion.String(),
+		"kind":       "FederatedTypeConfig",
+		"metadata": map[string]interface{}{
+			"name": "deployments.apps",
+		},
+		"spec": map[string]interface{}{
+			"targetType": map[string]interface{}{
+				"group":      "apps",
+				"version":    "v1",
+				"kind":       "Deployment",
+				"pluralName": "deployments",
+				"scope":      "Namespaced",
+			},
+			"federatedType": map[string]interface{}{
+				"group":      "types.kubeadmiral.io",
+				"version":    "v1alpha1",
+				"kind":       "FederatedDeployment",
+				"pluralName": "federateddeployments",
+				"scope":      "Namespaced",
+			},
+		},
+	}}
+	if controllers != nil {
+		_ = unstructured.SetNestedField(obj.Object, toInterfaceSlice(controllers), "spec", "controllers")
+	}
+	return obj
+}
+
+func toInterfaceSlice(controllers [][]string) []interface{} {
+	ret := make([]interface{}, 0, len(controllers))
+	for _, step := range controllers {
+		s := make([]interface{}, 0, len(step))
+		for _, controller := range step {
+			s = append(s, controller)
+		}
+		ret = append(ret, s)
+	}
+	return ret
+}
+
+func newRESTMapper() meta.RESTMapper {
+	restMapper := meta.NewDefaultRESTMapper(nil)
+	restMapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+	restMapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	restMapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+	restMapper.Add(
+		schema.GroupVersionKind{Group: "types.kubeadmiral.io", Version: "v1alpha1", Kind: "FederatedDeployment"},
+		meta.RESTScopeNamespace,
+	)
+	return restMapper
+}
+
+func newDynamicClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			federatedTypeConfigsResource: "FederatedTypeConfigList",
+			federatedDeploymentsResource: "FederatedDeploymentList",
+		},
+		objects...,
+	)
+}
+
+func TestRestore(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	source := &unstructured.Unstructured{}
+	source.SetAPIVersion("apps/v1")
+	source.SetKind("Deployment")
+	source.SetNamespace("default")
+	source.SetName("web")
+	source.SetUID("new-uid")
+
+	dynamicClient := newDynamicClient(source)
+	restorer := NewRestorer(dynamicClient, newRESTMapper(), io.Discard)
+
+	fedObject := newFederatedDeployment(
+		map[string]string{
+			pendingcontrollers.PendingControllersAnnotation: `[["kubeadmiral.io/overridepolicy-controller"]]`,
+		},
+		metav1.OwnerReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: "old-source-uid"},
+		metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "missing", UID: "old-configmap-uid"},
+	)
+	archive := &Archive{
+		Metadata: Metadata{Version: ArchiveVersion},
+		Resources: []Resources{{
+			ResourceInfo: ResourceInfo{
+				Group:      federatedDeploymentsResource.Group,
+				Version:    federatedDeploymentsResource.Version,
+				Resource:   federatedDeploymentsResource.Resource,
+				Namespaced: true,
+				Federated:  true,
+			},
+			Items: []*unstructured.Unstructured{fedObject},
+		}},
+	}
+
+	g.Expect(restorer.Restore(context.TODO(), archive)).To(gomega.Succeed())
+
+	_, err := dynamicClient.Resource(namespacesResource).Get(context.TODO(), "default", metav1.GetOptions{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	restored, err := dynamicClient.Resource(federatedDeploymentsResource).
+		Namespace("default").
+		Get(context.TODO(), "web", metav1.GetOptions{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(restored.GetUID()).NotTo(gomega.Equal(fedObject.GetUID()))
+	g.Expect(restored.GetOwnerReferences()).To(gomega.Equal([]metav1.OwnerReference{
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: "new-uid"},
+	}))
+	g.Expect(restored.GetAnnotations()).To(gomega.Equal(map[string]string{
+		pendingcontrollers.PendingControllersAnnotation: `[["kubeadmiral.io/restore"]]`,
+		common.RestoredPendingControllersAnnotation:     `[["kubeadmiral.io/overridepolicy-controller"]]`,
+	}))
+
+	// Restoring again leaves existing objects unchanged.
+	g.Expect(restorer.Restore(context.TODO(), archive)).To(gomega.Succeed())
+}
+
+func TestResume(t *testing.T) {
+	schedulerControllers := [][]string{{scheduler.PrefixedGlobalSchedulerName}, {"kubeadmiral.io/overridepolicy-controller"}}
+
+	testCases := map[string]struct {
+		controllers         [][]string
+		annotations         map[string]string
+		expectedAnnotations map[string]string
+	}{
+		"scheduled object adopts restored placements": {
+			controllers: schedulerControllers,
+			annotations: map[string]string{
+				pendingcontrollers.PendingControllersAnnotation: `[["kubeadmiral.io/restore"]]`,
+				common.RestoredPendingControllersAnnotation:     `[]`,
+			},
+			expectedAnnotations: map[string]string{
+				pendingcontrollers.PendingControllersAnnotation: `[]`,
+				common.RestoredAnnotation:                       common.AnnotationValueTrue,
+			},
+		},
+		"unscheduled object is scheduled": {
+			controllers: schedulerControllers,
+			annotations: map[string]string{
+				pendingcontrollers.PendingControllersAnnotation: `[["kubeadmiral.io/restore"]]`,
+				common.RestoredPendingControllersAnnotation:     `[["kubeadmiral.io/global-scheduler"]]`,
+			},
+			expectedAnnotations: map[string]string{
+				pendingcontrollers.PendingControllersAnnotation: `[["kubeadmiral.io/global-scheduler"]]`,
+			},
+		},
+		"object of type without scheduler": {
+			controllers: nil,
+			annotations: map[string]string{
+				pendingcontrollers.PendingControllersAnnotation: `[["kubeadmiral.io/restore"]]`,
+				common.RestoredPendingControllersAnnotation:     `[]`,
+			},
+			expectedAnnotations: map[string]string{
+				pendingcontrollers.PendingControllersAnnotation: `[]`,
+			},
+		},
+		"object not paused by restore": {
+			controllers: schedulerControllers,
+			annotations: map[string]string{
+				pendingcontrollers.PendingControllersAnnotation: `[]`,
+			},
+			expectedAnnotations: map[string]string{
+				pendingcontrollers.PendingControllersAnnotation: `[]`,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			dynamicClient := newDynamicClient(
+				newFederatedDeploymentTypeConfig(tc.controllers),
+				newFederatedDeployment(tc.annotations),
+			)
+			restorer := NewRestorer(dynamicClient, newRESTMapper(), io.Discard)
+
+			g.Expect(restorer.Resume(context.TODO())).To(gomega.Succeed())
+
+			resumed, err := dynamicClient.Resource(federatedDeploymentsResource).
+				Namespace("default").
+				Get(context.TODO(), "web", metav1.GetOptions{})
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(resumed.GetAnnotations()).To(gomega.Equal(tc.expectedAnnotations))
+		})
+	}
+}
@@ -47,7 +47,7 @@ type Client interface {
 	Delete(ctx context.Context, obj client.Object, namespace, name string, opts ...client.DeleteOption) error
 	List(ctx context.Context, obj client.ObjectList, namespace string) error
 	UpdateStatus(ctx context.Context, obj client.Object) error
-	Patch(ctx context.Context, obj client.Object, patch client.Patch) error
+	Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error
 	Rollback(ctx context.Context, obj client.Object, toRevision int64) error
 	DeleteHistory(ctx context.Context, obj client.Object) error
 
@@ -117,8 +117,8 @@ func (c *genericClient) UpdateStatus(ctx context.Context, obj client.Object) err
 	return c.client.Status().Update(ctx, obj)
 }
 
-func (c *genericClient) Patch(ctx context.Context, obj client.Object, patch client.Patch) error {
-	return c.client.Patch(ctx, obj, patch)
+func (c *genericClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return c.client.Patch(ctx, obj, patch, opts...)
 }
 
 // Rollback rollbacks federated Object such as FederatedDeployment
@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheme
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/apis/config"
+)
+
+func TestDecode(t *testing.T) {
+	tests := map[string]struct {
+		data        string
+		expectedErr bool
+		verify      func(g *gomega.WithT, cfg *config.KubeAdmiralControllerManagerConfiguration)
+	}{
+		"empty config is defaulted": {
+			data: `
+apiVersion: controllermanager.config.kubeadmiral.io/v1alpha1
+kind: KubeAdmiralControllerManagerConfiguration
+`,
+			verify: func(g *gomega.WithT, cfg *config.KubeAdmiralControllerManagerConfiguration) {
+				g.Expect(cfg.Generic.Port).To(gomega.Equal(int32(11257)))
+				g.Expect(cfg.Generic.Controllers).To(gomega.Equal([]string{"*"}))
+				g.Expect(cfg.Generic.WorkerCount).To(gomega.Equal(int32(1)))
+				g.Expect(cfg.LeaderElection.ResourceName).To(gomega.Equal("federation-controller-manager"))
+				g.Expect(cfg.FederatedClusterController.TokenRotationPeriod.Duration).To(gomega.Equal(24 * time.Hour))
+				g.Expect(cfg.FederatedClusterController.FlapDetectionThreshold).To(gomega.Equal(int32(3)))
+				g.Expect(cfg.MonitorController.OutOfSyncRecheckDelay.Duration).To(gomega.Equal(5 * time.Second))
+				g.Expect(cfg.Scheduler.SupplyLimitProportion).To(gomega.Equal(1.4))
+			},
+		},
+		"set fields are kept": {
+			data: `
+apiVersion: controllermanager.config.kubeadmiral.io/v1alpha1
+kind: KubeAdmiralControllerManagerConfiguration
+generic:
+  controllers: ["*", "globaldns"]
+  workerCount: 8
+leaderElection:
+  leaderElect: true
+  leaseDuration: 30s
+federatedClusterController:
+  tokenRotationPeriod: 0s
+  flapDetectionThreshold: 0
+scheduler:
+  supplyLimitProportion: 2
+`,
+			verify: func(g *gomega.WithT, cfg *config.KubeAdmiralControllerManagerConfiguration) {
+				g.Expect(cfg.Generic.Controllers).To(gomega.Equal([]string{"*", "globaldns"}))
+				g.Expect(cfg.Generic.WorkerCount).To(gomega.Equal(int32(8)))
+				g.Expect(cfg.LeaderElection.LeaderElect).To(gomega.BeTrue())
+				g.Expect(cfg.LeaderElection.LeaseDuration.Duration).To(gomega.Equal(30 * time.Second))
+				g.Expect(cfg.LeaderElection.RenewDeadline.Duration).To(gomega.Equal(10 * time.Second))
+				g.Expect(cfg.FederatedClusterController.TokenRotationPeriod.Duration).To(gomega.BeZero())
+				g.Expect(cfg.FederatedClusterController.FlapDetectionThreshold).To(gomega.BeZero())
+				g.Expect(cfg.Scheduler.SupplyLimitProportion).To(gomega.Equal(2.0))
+			},
+		},
+		"unknown field is rejected": {
+			data: `
+apiVersion: controllermanager.config.kubeadmiral.io/v1alpha1
+kind: KubeAdmiralControllerManagerConfiguration
+generic:
+  workers: 8
+`,
+			expectedErr: true,
+		},
+		"unknown version is rejected": {
+			data: `
+apiVersion: controllermanager.config.kubeadmiral.io/v1
+kind: KubeAdmiralControllerManagerConfiguration
+`,
+			expectedErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			obj, err := runtime.Decode(Codecs.UniversalDecoder(), []byte(test.data))
+			if test.expectedErr {
+				g.Expect(err).To(gomega.HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(obj).To(gomega.BeAssignableToTypeOf(&config.KubeAdmiralControllerManagerConfiguration{}))
+			test.verify(g, obj.(*config.KubeAdmiralControllerManagerConfiguration))
+		})
+	}
+}
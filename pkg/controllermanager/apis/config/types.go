@@ -0,0 +1,297 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KubeAdmiralControllerManagerConfiguration configures the KubeAdmiral controller manager.
+type KubeAdmiralControllerManagerConfiguration struct {
+	metav1.TypeMeta
+
+	// Generic contains the settings shared by all controllers.
+	Generic GenericControllerManagerConfiguration
+	// LeaderElection configures the leader election among the replicas of the controller manager.
+	LeaderElection LeaderElectionConfiguration
+	// Sharding configures the sharding of federated objects among the replicas of the controller manager.
+	Sharding ShardingConfiguration
+	// Metrics configures the metrics of the controllers.
+	Metrics MetricsConfiguration
+	// ControllerTuning configures the workers and member cluster clients of individual controllers.
+	ControllerTuning ControllerTuningConfiguration
+
+	// FederatedClusterController contains the settings of the cluster controller.
+	FederatedClusterController FederatedClusterControllerConfiguration
+	// TypeConfigController contains the settings of the typeconfig controller.
+	TypeConfigController TypeConfigControllerConfiguration
+	// FederateController contains the settings of the federate controller.
+	FederateController FederateControllerConfiguration
+	// SyncController contains the settings of the sync controller.
+	SyncController SyncControllerConfiguration
+	// NamespaceAutoPropagationController contains the settings of the namespace auto-propagation controller.
+	NamespaceAutoPropagationController NamespaceAutoPropagationControllerConfiguration
+	// MonitorController contains the settings of the monitor controller.
+	MonitorController MonitorControllerConfiguration
+	// Scheduler contains the settings of the scheduler.
+	Scheduler SchedulerConfiguration
+	// GlobalDNSController contains the settings of the global DNS controller.
+	GlobalDNSController GlobalDNSControllerConfiguration
+}
+
+// GenericControllerManagerConfiguration contains the settings shared by all controllers.
+type GenericControllerManagerConfiguration struct {
+	// Port is the port on which health checks are served.
+	Port int32
+	// ClientConnection configures the clients for the host cluster.
+	ClientConnection ClientConnectionConfiguration
+	// Controllers is the list of controllers to enable. '*' enables all on-by-default controllers, 'foo' enables the
+	// controller named 'foo' and '-foo' disables the controller named 'foo'.
+	Controllers []string
+	// WorkerCount is the number of workers of each controller unless it is overridden by ControllerTuningConfigFile.
+	WorkerCount int32
+	// ControllerTuningConfigFile is the path of a file containing the worker counts, rate limits and member cluster
+	// client QPS of controllers in the format of ControllerTuning, which is reloaded when it changes. It must not be
+	// set together with ControllerTuning.
+	ControllerTuningConfigFile string
+	// ClusterAvailableDelay is the delay before objects are reconciled again after a member cluster becomes available.
+	ClusterAvailableDelay metav1.Duration
+	// ClusterUnavailableDelay is the delay before objects are reconciled again after a member cluster becomes
+	// unavailable.
+	ClusterUnavailableDelay metav1.Duration
+	// MaxPodListers is the maximum number of concurrent pod listing requests to member clusters. A non-positive number
+	// means unlimited.
+	MaxPodListers int64
+	// EnablePodPruning prunes the pods cached by the pod informers of member clusters to reduce memory usage. It
+	// disables the propagation of pods.
+	EnablePodPruning bool
+	// EnableProfiling enables the pprof server on port 6060.
+	EnableProfiling bool
+}
+
+// ClientConnectionConfiguration configures the clients for the host cluster.
+type ClientConnectionConfiguration struct {
+	// Kubeconfig is the path of the kubeconfig of the host cluster. The in-cluster config is used if it is empty.
+	Kubeconfig string
+	// QPS is the maximum number of requests per second of each client. It is also the default for clients of member
+	// clusters.
+	QPS float32
+	// Burst is the maximum number of requests exceeding QPS at once of each client.
+	Burst int32
+}
+
+// LeaderElectionConfiguration configures the leader election among the replicas of the controller manager.
+type LeaderElectionConfiguration struct {
+	// LeaderElect enables leader election, which ensures that only one replica runs the controllers.
+	LeaderElect bool
+	// ResourceName is the name of the Lease used for leader election in the fed system namespace.
+	ResourceName string
+	// LeaseDuration is the duration that non-leader replicas wait after the last renewal of the Lease before they try to
+	// acquire leadership.
+	LeaseDuration metav1.Duration
+	// RenewDeadline is the duration that the leader retries renewing the Lease before it gives up leadership.
+	RenewDeadline metav1.Duration
+	// RetryPeriod is the interval between attempts to acquire or renew the Lease.
+	RetryPeriod metav1.Duration
+}
+
+// ShardingConfiguration configures the sharding of federated objects among the replicas of the controller manager.
+type ShardingConfiguration struct {
+	// Enabled enables sharding. It requires leader election.
+	Enabled bool
+	// BucketCount is the number of hash buckets that the keys of federated objects are distributed into. It must be the
+	// same for all replicas.
+	BucketCount int32
+}
+
+// MetricsConfiguration configures the metrics of the controllers.
+type MetricsConfiguration struct {
+	// Env is the environment recorded in the prefix of metric names.
+	Env string
+	// LogMetrics logs all emitted metrics.
+	LogMetrics bool
+}
+
+// ControllerTuningConfiguration configures the workers and member cluster clients of controllers. Settings of a
+// controller for a FederatedTypeConfig take precedence over settings of the controller, which take precedence over the
+// defaults. Unset fields fall back to the next level, and eventually to Generic.
+type ControllerTuningConfiguration struct {
+	// Defaults apply to all controllers.
+	Defaults ControllerTuning
+	// Controllers contains the settings of controllers by controller name.
+	Controllers map[string]ControllerTuning
+	// FederatedTypeConfigs contains the settings of controllers by FederatedTypeConfig name and controller name. They
+	// only apply to controllers that run for each FederatedTypeConfig.
+	FederatedTypeConfigs map[string]map[string]ControllerTuning
+}
+
+// ControllerTuning contains the settings of a controller.
+type ControllerTuning struct {
+	// WorkerCount is the number of objects that the controller reconciles concurrently.
+	WorkerCount *int32
+	// RateLimiter limits the rate of reconciliations of the controller.
+	RateLimiter *RateLimiterConfiguration
+	// ClusterClient limits the rate of requests from the controller to each member cluster.
+	ClusterClient *ClusterClientConfiguration
+}
+
+// RateLimiterConfiguration configures the queue of a controller.
+type RateLimiterConfiguration struct {
+	// QPS is the maximum number of reconciliations per second. Reconciliations are not rate limited if QPS is 0.
+	QPS *float32
+	// Burst is the maximum number of reconciliations that may exceed QPS at once.
+	Burst *int32
+	// InitialBackoff is the delay before an object is reconciled again after its first failed reconciliation.
+	InitialBackoff *metav1.Duration
+	// MaxBackoff is the maximum delay before an object is reconciled again after repeated failed reconciliations.
+	MaxBackoff *metav1.Duration
+}
+
+// ClusterClientConfiguration configures the rate limit of the clients of a controller for member clusters.
+type ClusterClientConfiguration struct {
+	// QPS is the maximum number of requests per second.
+	QPS *float32
+	// Burst is the maximum number of requests that may exceed QPS at once.
+	Burst *int32
+}
+
+// FederatedClusterControllerConfiguration contains the settings of the cluster controller.
+type FederatedClusterControllerConfiguration struct {
+	// JoinTimeout is the maximum amount of time to wait for a new cluster to join the federation.
+	JoinTimeout metav1.Duration
+	// TokenRotationPeriod is the interval at which the service account tokens of member clusters are rotated. Tokens
+	// are not rotated if it is 0.
+	TokenRotationPeriod metav1.Duration
+	// FlapDetectionWindow is the period in which changes of the readiness of a member cluster are counted.
+	FlapDetectionWindow metav1.Duration
+	// FlapDetectionThreshold is the number of changes of the readiness of a member cluster within the flap detection
+	// window at which the cluster is marked unstable. Flap detection is disabled if it is 0.
+	FlapDetectionThreshold int32
+	// HealthCheck contains the default settings of the health checks of member clusters.
+	HealthCheck ClusterHealthCheckConfiguration
+	// AgentStatusTimeout is the time after which a pull-mode member cluster is marked as not ready if its agent has not
+	// reported its status.
+	AgentStatusTimeout metav1.Duration
+	// CredentialProviders restricts the credential providers that member clusters may use.
+	CredentialProviders ClusterCredentialProviderConfiguration
+}
+
+// ClusterCredentialProviderConfiguration restricts the credential providers that member clusters may use. Exec and
+// token file providers run commands and read files on the host of the controller manager, so they are rejected unless
+// allowed here.
+type ClusterCredentialProviderConfiguration struct {
+	// AllowedExecCommands are the commands that exec credential providers may run.
+	AllowedExecCommands []string
+	// AllowedTokenFileDirectories are the absolute paths of the directories that token file credential providers may
+	// read from.
+	AllowedTokenFileDirectories []string
+}
+
+// ClusterHealthCheckConfiguration contains the default settings of the health checks of member clusters.
+type ClusterHealthCheckConfiguration struct {
+	// Period is the interval between health checks.
+	Period metav1.Duration
+	// Path is the health endpoint of the apiservers of member clusters.
+	Path string
+	// Timeout is the timeout of health check requests.
+	Timeout metav1.Duration
+	// SuccessThreshold is the number of consecutive successful health checks after which a cluster becomes ready.
+	SuccessThreshold int32
+	// FailureThreshold is the number of consecutive failed health checks after which a cluster becomes not ready.
+	FailureThreshold int32
+}
+
+// TypeConfigControllerConfiguration contains the settings of the typeconfig controller.
+type TypeConfigControllerConfiguration struct {
+	// CreateCRDsForFTCs generates the CRDs of federated types automatically.
+	CreateCRDsForFTCs bool
+}
+
+// FederateControllerConfiguration contains the settings of the federate controller.
+type FederateControllerConfiguration struct {
+	// MetadataPropagation contains the global rules for classifying the labels and annotations of source objects.
+	MetadataPropagation *fedcorev1a1.MetadataPropagation
+}
+
+// SyncControllerConfiguration contains the settings of the sync controller.
+type SyncControllerConfiguration struct {
+	// PausePropagation pauses the propagation of all federated objects to member clusters.
+	PausePropagation bool
+}
+
+// NamespaceAutoPropagationControllerConfiguration contains the settings of the namespace auto-propagation controller.
+type NamespaceAutoPropagationControllerConfiguration struct {
+	// ExcludeRegexp is a regular expression matching the namespaces that are not propagated automatically.
+	ExcludeRegexp string
+}
+
+// MonitorControllerConfiguration contains the settings of the monitor controller.
+type MonitorControllerConfiguration struct {
+	// OutOfSyncRecheckDelay is the delay before an object whose status is out of sync is checked again.
+	OutOfSyncRecheckDelay metav1.Duration
+}
+
+// SchedulerConfiguration contains the settings of the scheduler.
+type SchedulerConfiguration struct {
+	// SupplyLimitProportion limits the share of replicas that the ClusterCapacityWeight plugin assigns to a cluster
+	// to this multiple of its share if replicas were distributed evenly.
+	SupplyLimitProportion float64
+	// WebhookTimeout is the timeout of requests to scheduler plugin webhooks whose configuration does not set one.
+	WebhookTimeout metav1.Duration
+}
+
+// GlobalDNSControllerConfiguration contains the settings of the global DNS controller.
+type GlobalDNSControllerConfiguration struct {
+	// Provider is the DNS provider used to publish records. One of rfc2136 and coredns-etcd.
+	Provider string
+	// RFC2136 configures the rfc2136 provider.
+	RFC2136 RFC2136ProviderConfiguration
+	// CoreDNSEtcd configures the coredns-etcd provider.
+	CoreDNSEtcd CoreDNSEtcdProviderConfiguration
+}
+
+// RFC2136ProviderConfiguration configures the DNS provider that sends RFC 2136 dynamic updates.
+type RFC2136ProviderConfiguration struct {
+	// Server is the address of the DNS server in the form host:port.
+	Server string
+	// Zone is the zone in which records are updated.
+	Zone string
+	// TSIGKeyName is the name of the TSIG key used to sign updates. Updates are not signed if it is empty.
+	TSIGKeyName string
+	// TSIGAlgorithm is the algorithm of the TSIG key. One of hmac-sha1, hmac-sha256 and hmac-sha512.
+	TSIGAlgorithm string
+	// TSIGSecretFile is the path of the file containing the base64-encoded secret of the TSIG key.
+	TSIGSecretFile string
+}
+
+// CoreDNSEtcdProviderConfiguration configures the DNS provider that writes records for the CoreDNS etcd plugin.
+type CoreDNSEtcdProviderConfiguration struct {
+	// Endpoint is the URL of the etcd server.
+	Endpoint string
+	// Prefix is the path prefix configured in the CoreDNS etcd plugin.
+	Prefix string
+	// CAFile is the path of the CA bundle used to verify the certificate of the etcd server.
+	CAFile string
+	// CertFile is the path of the client certificate presented to the etcd server.
+	CertFile string
+	// KeyFile is the path of the private key of the client certificate.
+	KeyFile string
+}
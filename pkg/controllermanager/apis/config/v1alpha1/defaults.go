@@ -0,0 +1,126 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+const (
+	DefaultPort                  = 11257
+	DefaultLeaderElectionName    = "federation-controller-manager"
+	DefaultSupplyLimitProportion = 1.4
+)
+
+// SetDefaults_KubeAdmiralControllerManagerConfiguration sets the defaults of unset fields.
+//
+//nolint:revive,stylecheck
+func SetDefaults_KubeAdmiralControllerManagerConfiguration(obj *KubeAdmiralControllerManagerConfiguration) {
+	setGenericDefaults(&obj.Generic)
+	setLeaderElectionDefaults(&obj.LeaderElection)
+
+	if obj.Sharding.BucketCount == 0 {
+		obj.Sharding.BucketCount = 64
+	}
+
+	setFederatedClusterControllerDefaults(&obj.FederatedClusterController)
+
+	setDurationDefault(&obj.MonitorController.OutOfSyncRecheckDelay, 5*time.Second)
+
+	if obj.Scheduler.SupplyLimitProportion == 0 {
+		obj.Scheduler.SupplyLimitProportion = DefaultSupplyLimitProportion
+	}
+	setDurationDefault(&obj.Scheduler.WebhookTimeout, 5*time.Second)
+
+	if obj.GlobalDNSController.RFC2136.TSIGAlgorithm == "" {
+		obj.GlobalDNSController.RFC2136.TSIGAlgorithm = "hmac-sha256"
+	}
+	if obj.GlobalDNSController.CoreDNSEtcd.Prefix == "" {
+		obj.GlobalDNSController.CoreDNSEtcd.Prefix = "/skydns"
+	}
+}
+
+// SetDefaults_ControllerTuningConfiguration sets the defaults of unset fields. Only the defaults are defaulted, since
+// unset fields of controllers fall back to them.
+//
+//nolint:revive,stylecheck
+func SetDefaults_ControllerTuningConfiguration(obj *ControllerTuningConfiguration) {
+	if rateLimiter := obj.Defaults.RateLimiter; rateLimiter != nil && rateLimiter.QPS != nil && rateLimiter.Burst == nil {
+		rateLimiter.Burst = pointer.Int32(1)
+	}
+}
+
+func setGenericDefaults(obj *GenericControllerManagerConfiguration) {
+	if obj.Port == 0 {
+		obj.Port = DefaultPort
+	}
+	if obj.ClientConnection.QPS == 0 {
+		obj.ClientConnection.QPS = 500
+	}
+	if obj.ClientConnection.Burst == 0 {
+		obj.ClientConnection.Burst = 1000
+	}
+	if obj.Controllers == nil {
+		obj.Controllers = []string{"*"}
+	}
+	if obj.WorkerCount == 0 {
+		obj.WorkerCount = 1
+	}
+	setDurationDefault(&obj.ClusterAvailableDelay, 20*time.Second)
+	setDurationDefault(&obj.ClusterUnavailableDelay, 60*time.Second)
+}
+
+func setLeaderElectionDefaults(obj *LeaderElectionConfiguration) {
+	if obj.ResourceName == "" {
+		obj.ResourceName = DefaultLeaderElectionName
+	}
+	setDurationDefault(&obj.LeaseDuration, 15*time.Second)
+	setDurationDefault(&obj.RenewDeadline, 10*time.Second)
+	setDurationDefault(&obj.RetryPeriod, 5*time.Second)
+}
+
+func setFederatedClusterControllerDefaults(obj *FederatedClusterControllerConfiguration) {
+	setDurationDefault(&obj.JoinTimeout, 10*time.Minute)
+	if obj.TokenRotationPeriod == nil {
+		obj.TokenRotationPeriod = &metav1.Duration{Duration: 24 * time.Hour}
+	}
+	setDurationDefault(&obj.FlapDetectionWindow, 10*time.Minute)
+	if obj.FlapDetectionThreshold == nil {
+		obj.FlapDetectionThreshold = pointer.Int32(3)
+	}
+	setDurationDefault(&obj.HealthCheck.Period, 30*time.Second)
+	if obj.HealthCheck.Path == "" {
+		obj.HealthCheck.Path = "/healthz"
+	}
+	setDurationDefault(&obj.HealthCheck.Timeout, 30*time.Second)
+	if obj.HealthCheck.SuccessThreshold == 0 {
+		obj.HealthCheck.SuccessThreshold = 1
+	}
+	if obj.HealthCheck.FailureThreshold == 0 {
+		obj.HealthCheck.FailureThreshold = 1
+	}
+	setDurationDefault(&obj.AgentStatusTimeout, 90*time.Second)
+}
+
+func setDurationDefault(d *metav1.Duration, defaultValue time.Duration) {
+	if d.Duration == 0 {
+		d.Duration = defaultValue
+	}
+}
@@ -0,0 +1,23 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// +k8s:deepcopy-gen=package
+// +k8s:conversion-gen=github.com/kubewharf/kubeadmiral/pkg/controllermanager/apis/config
+// +k8s:defaulter-gen=TypeMeta
+// +groupName=controllermanager.config.kubeadmiral.io
+
+// Package v1alpha1 contains the v1alpha1 version of the component configuration of the KubeAdmiral controller manager.
+package v1alpha1 // import "github.com/kubewharf/kubeadmiral/pkg/controllermanager/apis/config/v1alpha1"
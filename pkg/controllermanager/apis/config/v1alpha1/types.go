@@ -0,0 +1,311 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KubeAdmiralControllerManagerConfiguration configures the KubeAdmiral controller manager.
+type KubeAdmiralControllerManagerConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Generic contains the settings shared by all controllers.
+	Generic GenericControllerManagerConfiguration `json:"generic"`
+	// LeaderElection configures the leader election among the replicas of the controller manager.
+	LeaderElection LeaderElectionConfiguration `json:"leaderElection"`
+	// Sharding configures the sharding of federated objects among the replicas of the controller manager.
+	Sharding ShardingConfiguration `json:"sharding"`
+	// Metrics configures the metrics of the controllers.
+	Metrics MetricsConfiguration `json:"metrics"`
+	// ControllerTuning configures the workers and member cluster clients of individual controllers.
+	ControllerTuning ControllerTuningConfiguration `json:"controllerTuning"`
+
+	// FederatedClusterController contains the settings of the cluster controller.
+	FederatedClusterController FederatedClusterControllerConfiguration `json:"federatedClusterController"`
+	// TypeConfigController contains the settings of the typeconfig controller.
+	TypeConfigController TypeConfigControllerConfiguration `json:"typeConfigController"`
+	// FederateController contains the settings of the federate controller.
+	FederateController FederateControllerConfiguration `json:"federateController"`
+	// SyncController contains the settings of the sync controller.
+	SyncController SyncControllerConfiguration `json:"syncController"`
+	// NamespaceAutoPropagationController contains the settings of the namespace auto-propagation controller.
+	NamespaceAutoPropagationController NamespaceAutoPropagationControllerConfiguration `json:"namespaceAutoPropagationController"`
+	// MonitorController contains the settings of the monitor controller.
+	MonitorController MonitorControllerConfiguration `json:"monitorController"`
+	// Scheduler contains the settings of the scheduler.
+	Scheduler SchedulerConfiguration `json:"scheduler"`
+	// GlobalDNSController contains the settings of the global DNS controller.
+	GlobalDNSController GlobalDNSControllerConfiguration `json:"globalDNSController"`
+}
+
+// GenericControllerManagerConfiguration contains the settings shared by all controllers.
+type GenericControllerManagerConfiguration struct {
+	// Port is the port on which health checks are served. Defaults to 11257.
+	Port int32 `json:"port,omitempty"`
+	// ClientConnection configures the clients for the host cluster.
+	ClientConnection ClientConnectionConfiguration `json:"clientConnection"`
+	// Controllers is the list of controllers to enable. '*' enables all on-by-default controllers, 'foo' enables the
+	// controller named 'foo' and '-foo' disables the controller named 'foo'. Defaults to ['*'].
+	Controllers []string `json:"controllers,omitempty"`
+	// WorkerCount is the number of workers of each controller unless it is overridden by controllerTuningConfigFile.
+	// Defaults to 1.
+	WorkerCount int32 `json:"workerCount,omitempty"`
+	// ControllerTuningConfigFile is the path of a file containing the worker counts, rate limits and member cluster
+	// client QPS of controllers in the format of controllerTuning, which is reloaded when it changes. It must not be
+	// set together with controllerTuning.
+	ControllerTuningConfigFile string `json:"controllerTuningConfigFile,omitempty"`
+	// ClusterAvailableDelay is the delay before objects are reconciled again after a member cluster becomes available.
+	// Defaults to 20s.
+	ClusterAvailableDelay metav1.Duration `json:"clusterAvailableDelay,omitempty"`
+	// ClusterUnavailableDelay is the delay before objects are reconciled again after a member cluster becomes
+	// unavailable. Defaults to 60s.
+	ClusterUnavailableDelay metav1.Duration `json:"clusterUnavailableDelay,omitempty"`
+	// MaxPodListers is the maximum number of concurrent pod listing requests to member clusters. A non-positive number
+	// means unlimited.
+	MaxPodListers int64 `json:"maxPodListers,omitempty"`
+	// EnablePodPruning prunes the pods cached by the pod informers of member clusters to reduce memory usage. It
+	// disables the propagation of pods.
+	EnablePodPruning bool `json:"enablePodPruning,omitempty"`
+	// EnableProfiling enables the pprof server on port 6060.
+	EnableProfiling bool `json:"enableProfiling,omitempty"`
+}
+
+// ClientConnectionConfiguration configures the clients for the host cluster.
+type ClientConnectionConfiguration struct {
+	// Kubeconfig is the path of the kubeconfig of the host cluster. The in-cluster config is used if it is empty.
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	// QPS is the maximum number of requests per second of each client. It is also the default for clients of member
+	// clusters. Defaults to 500.
+	QPS float32 `json:"qps,omitempty"`
+	// Burst is the maximum number of requests exceeding QPS at once of each client. Defaults to 1000.
+	Burst int32 `json:"burst,omitempty"`
+}
+
+// LeaderElectionConfiguration configures the leader election among the replicas of the controller manager.
+type LeaderElectionConfiguration struct {
+	// LeaderElect enables leader election, which ensures that only one replica runs the controllers.
+	LeaderElect bool `json:"leaderElect,omitempty"`
+	// ResourceName is the name of the Lease used for leader election in the fed system namespace. Defaults to
+	// federation-controller-manager.
+	ResourceName string `json:"resourceName,omitempty"`
+	// LeaseDuration is the duration that non-leader replicas wait after the last renewal of the Lease before they try to
+	// acquire leadership. Defaults to 15s.
+	LeaseDuration metav1.Duration `json:"leaseDuration,omitempty"`
+	// RenewDeadline is the duration that the leader retries renewing the Lease before it gives up leadership. Defaults
+	// to 10s.
+	RenewDeadline metav1.Duration `json:"renewDeadline,omitempty"`
+	// RetryPeriod is the interval between attempts to acquire or renew the Lease. Defaults to 5s.
+	RetryPeriod metav1.Duration `json:"retryPeriod,omitempty"`
+}
+
+// ShardingConfiguration configures the sharding of federated objects among the replicas of the controller manager.
+type ShardingConfiguration struct {
+	// Enabled enables sharding. It requires leader election.
+	Enabled bool `json:"enabled,omitempty"`
+	// BucketCount is the number of hash buckets that the keys of federated objects are distributed into. It must be the
+	// same for all replicas. Defaults to 64.
+	BucketCount int32 `json:"bucketCount,omitempty"`
+}
+
+// MetricsConfiguration configures the metrics of the controllers.
+type MetricsConfiguration struct {
+	// Env is the environment recorded in the prefix of metric names, which are prefixed with
+	// "kube-federation-manager.<env>.".
+	Env string `json:"env,omitempty"`
+	// LogMetrics logs all emitted metrics. Defaults to false.
+	LogMetrics bool `json:"logMetrics,omitempty"`
+}
+
+// ControllerTuningConfiguration configures the workers and member cluster clients of controllers. Settings of a
+// controller for a FederatedTypeConfig take precedence over settings of the controller, which take precedence over the
+// defaults. Unset fields fall back to the next level, and eventually to generic.workerCount and
+// generic.clientConnection.
+type ControllerTuningConfiguration struct {
+	// Defaults apply to all controllers.
+	Defaults ControllerTuning `json:"defaults,omitempty"`
+	// Controllers contains the settings of controllers by controller name.
+	Controllers map[string]ControllerTuning `json:"controllers,omitempty"`
+	// FederatedTypeConfigs contains the settings of controllers by FederatedTypeConfig name and controller name. They
+	// only apply to controllers that run for each FederatedTypeConfig.
+	FederatedTypeConfigs map[string]map[string]ControllerTuning `json:"federatedTypeConfigs,omitempty"`
+}
+
+// ControllerTuning contains the settings of a controller.
+type ControllerTuning struct {
+	// WorkerCount is the number of objects that the controller reconciles concurrently.
+	WorkerCount *int32 `json:"workerCount,omitempty"`
+	// RateLimiter limits the rate of reconciliations of the controller.
+	RateLimiter *RateLimiterConfiguration `json:"rateLimiter,omitempty"`
+	// ClusterClient limits the rate of requests from the controller to each member cluster.
+	ClusterClient *ClusterClientConfiguration `json:"clusterClient,omitempty"`
+}
+
+// RateLimiterConfiguration configures the queue of a controller.
+type RateLimiterConfiguration struct {
+	// QPS is the maximum number of reconciliations per second. Reconciliations are not rate limited if QPS is 0.
+	QPS *float32 `json:"qps,omitempty"`
+	// Burst is the maximum number of reconciliations that may exceed QPS at once. Defaults to 1 in defaults if qps is
+	// set there.
+	Burst *int32 `json:"burst,omitempty"`
+	// InitialBackoff is the delay before an object is reconciled again after its first failed reconciliation.
+	InitialBackoff *metav1.Duration `json:"initialBackoff,omitempty"`
+	// MaxBackoff is the maximum delay before an object is reconciled again after repeated failed reconciliations.
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+}
+
+// ClusterClientConfiguration configures the rate limit of the clients of a controller for member clusters.
+type ClusterClientConfiguration struct {
+	// QPS is the maximum number of requests per second.
+	QPS *float32 `json:"qps,omitempty"`
+	// Burst is the maximum number of requests that may exceed QPS at once.
+	Burst *int32 `json:"burst,omitempty"`
+}
+
+// FederatedClusterControllerConfiguration contains the settings of the cluster controller.
+type FederatedClusterControllerConfiguration struct {
+	// JoinTimeout is the maximum amount of time to wait for a new cluster to join the federation. Defaults to 10m.
+	JoinTimeout metav1.Duration `json:"joinTimeout,omitempty"`
+	// TokenRotationPeriod is the interval at which the service account tokens of member clusters are rotated. Tokens
+	// are not rotated if it is 0. Defaults to 24h.
+	TokenRotationPeriod *metav1.Duration `json:"tokenRotationPeriod,omitempty"`
+	// FlapDetectionWindow is the period in which changes of the readiness of a member cluster are counted. Defaults to
+	// 10m.
+	FlapDetectionWindow metav1.Duration `json:"flapDetectionWindow,omitempty"`
+	// FlapDetectionThreshold is the number of changes of the readiness of a member cluster within the flap detection
+	// window at which the cluster is marked unstable. Flap detection is disabled if it is 0. Defaults to 3.
+	FlapDetectionThreshold *int32 `json:"flapDetectionThreshold,omitempty"`
+	// HealthCheck contains the default settings of the health checks of member clusters.
+	HealthCheck ClusterHealthCheckConfiguration `json:"healthCheck"`
+	// AgentStatusTimeout is the time after which a pull-mode member cluster is marked as not ready if its agent has not
+	// reported its status. Defaults to 90s.
+	AgentStatusTimeout metav1.Duration `json:"agentStatusTimeout,omitempty"`
+	// CredentialProviders restricts the credential providers that member clusters may use.
+	CredentialProviders ClusterCredentialProviderConfiguration `json:"credentialProviders"`
+}
+
+// ClusterCredentialProviderConfiguration restricts the credential providers that member clusters may use. Exec and
+// token file providers run commands and read files on the host of the controller manager, so they are rejected unless
+// allowed here. Neither is allowed by default.
+type ClusterCredentialProviderConfiguration struct {
+	// AllowedExecCommands are the commands that exec credential providers may run. A command must match exactly.
+	AllowedExecCommands []string `json:"allowedExecCommands,omitempty"`
+	// AllowedTokenFileDirectories are the absolute paths of the directories that token file credential providers may
+	// read from.
+	AllowedTokenFileDirectories []string `json:"allowedTokenFileDirectories,omitempty"`
+}
+
+// ClusterHealthCheckConfiguration contains the default settings of the health checks of member clusters.
+type ClusterHealthCheckConfiguration struct {
+	// Period is the interval between health checks. Defaults to 30s.
+	Period metav1.Duration `json:"period,omitempty"`
+	// Path is the health endpoint of the apiservers of member clusters. Defaults to /healthz.
+	Path string `json:"path,omitempty"`
+	// Timeout is the timeout of health check requests. Defaults to 30s.
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+	// SuccessThreshold is the number of consecutive successful health checks after which a cluster becomes ready.
+	// Defaults to 1.
+	SuccessThreshold int32 `json:"successThreshold,omitempty"`
+	// FailureThreshold is the number of consecutive failed health checks after which a cluster becomes not ready.
+	// Defaults to 1.
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+}
+
+// TypeConfigControllerConfiguration contains the settings of the typeconfig controller.
+type TypeConfigControllerConfiguration struct {
+	// CreateCRDsForFTCs generates the CRDs of federated types automatically.
+	CreateCRDsForFTCs bool `json:"createCRDsForFTCs,omitempty"`
+}
+
+// FederateControllerConfiguration contains the settings of the federate controller.
+type FederateControllerConfiguration struct {
+	// MetadataPropagation contains the global rules for classifying the labels and annotations of source objects.
+	MetadataPropagation *fedcorev1a1.MetadataPropagation `json:"metadataPropagation,omitempty"`
+}
+
+// SyncControllerConfiguration contains the settings of the sync controller.
+type SyncControllerConfiguration struct {
+	// PausePropagation pauses the propagation of all federated objects to member clusters.
+	PausePropagation bool `json:"pausePropagation,omitempty"`
+}
+
+// NamespaceAutoPropagationControllerConfiguration contains the settings of the namespace auto-propagation controller.
+type NamespaceAutoPropagationControllerConfiguration struct {
+	// ExcludeRegexp is a regular expression matching the namespaces that are not propagated automatically.
+	ExcludeRegexp string `json:"excludeRegexp,omitempty"`
+}
+
+// MonitorControllerConfiguration contains the settings of the monitor controller.
+type MonitorControllerConfiguration struct {
+	// OutOfSyncRecheckDelay is the delay before an object whose status is out of sync is checked again. Defaults to 5s.
+	OutOfSyncRecheckDelay metav1.Duration `json:"outOfSyncRecheckDelay,omitempty"`
+}
+
+// SchedulerConfiguration contains the settings of the scheduler.
+type SchedulerConfiguration struct {
+	// SupplyLimitProportion limits the share of replicas that the ClusterCapacityWeight plugin assigns to a cluster
+	// to this multiple of its share if replicas were distributed evenly. Defaults to 1.4.
+	SupplyLimitProportion float64 `json:"supplyLimitProportion,omitempty"`
+	// WebhookTimeout is the timeout of requests to scheduler plugin webhooks whose configuration does not set one.
+	// Defaults to 5s.
+	WebhookTimeout metav1.Duration `json:"webhookTimeout,omitempty"`
+}
+
+// GlobalDNSControllerConfiguration contains the settings of the global DNS controller.
+type GlobalDNSControllerConfiguration struct {
+	// Provider is the DNS provider used to publish records. One of rfc2136 and coredns-etcd.
+	Provider string `json:"provider,omitempty"`
+	// RFC2136 configures the rfc2136 provider.
+	RFC2136 RFC2136ProviderConfiguration `json:"rfc2136"`
+	// CoreDNSEtcd configures the coredns-etcd provider.
+	CoreDNSEtcd CoreDNSEtcdProviderConfiguration `json:"coreDNSEtcd"`
+}
+
+// RFC2136ProviderConfiguration configures the DNS provider that sends RFC 2136 dynamic updates.
+type RFC2136ProviderConfiguration struct {
+	// Server is the address of the DNS server in the form host:port.
+	Server string `json:"server,omitempty"`
+	// Zone is the zone in which records are updated.
+	Zone string `json:"zone,omitempty"`
+	// TSIGKeyName is the name of the TSIG key used to sign updates. Updates are not signed if it is empty.
+	TSIGKeyName string `json:"tsigKeyName,omitempty"`
+	// TSIGAlgorithm is the algorithm of the TSIG key. One of hmac-sha1, hmac-sha256 and hmac-sha512. Defaults to
+	// hmac-sha256.
+	TSIGAlgorithm string `json:"tsigAlgorithm,omitempty"`
+	// TSIGSecretFile is the path of the file containing the base64-encoded secret of the TSIG key.
+	TSIGSecretFile string `json:"tsigSecretFile,omitempty"`
+}
+
+// CoreDNSEtcdProviderConfiguration configures the DNS provider that writes records for the CoreDNS etcd plugin.
+type CoreDNSEtcdProviderConfiguration struct {
+	// Endpoint is the URL of the etcd server.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Prefix is the path prefix configured in the CoreDNS etcd plugin. Defaults to /skydns.
+	Prefix string `json:"prefix,omitempty"`
+	// CAFile is the path of the CA bundle used to verify the certificate of the etcd server. The system roots are
+	// used if it is empty.
+	CAFile string `json:"caFile,omitempty"`
+	// CertFile is the path of the client certificate presented to the etcd server. It must be set together with
+	// keyFile.
+	CertFile string `json:"certFile,omitempty"`
+	// KeyFile is the path of the private key of the client certificate.
+	KeyFile string `json:"keyFile,omitempty"`
+}
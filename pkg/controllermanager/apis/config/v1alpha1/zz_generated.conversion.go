@@ -0,0 +1,888 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+// Code generated by conversion-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	unsafe "unsafe"
+
+	corev1alpha1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	config "github.com/kubewharf/kubeadmiral/pkg/controllermanager/apis/config"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	conversion "k8s.io/apimachinery/pkg/conversion"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+func init() {
+	localSchemeBuilder.Register(RegisterConversions)
+}
+
+// RegisterConversions adds conversion functions to the given scheme.
+// Public to allow building arbitrary schemes.
+func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*ClientConnectionConfiguration)(nil), (*config.ClientConnectionConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ClientConnectionConfiguration_To_config_ClientConnectionConfiguration(a.(*ClientConnectionConfiguration), b.(*config.ClientConnectionConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ClientConnectionConfiguration)(nil), (*ClientConnectionConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ClientConnectionConfiguration_To_v1alpha1_ClientConnectionConfiguration(a.(*config.ClientConnectionConfiguration), b.(*ClientConnectionConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ClusterClientConfiguration)(nil), (*config.ClusterClientConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ClusterClientConfiguration_To_config_ClusterClientConfiguration(a.(*ClusterClientConfiguration), b.(*config.ClusterClientConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ClusterClientConfiguration)(nil), (*ClusterClientConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ClusterClientConfiguration_To_v1alpha1_ClusterClientConfiguration(a.(*config.ClusterClientConfiguration), b.(*ClusterClientConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ClusterCredentialProviderConfiguration)(nil), (*config.ClusterCredentialProviderConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ClusterCredentialProviderConfiguration_To_config_ClusterCredentialProviderConfiguration(a.(*ClusterCredentialProviderConfiguration), b.(*config.ClusterCredentialProviderConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ClusterCredentialProviderConfiguration)(nil), (*ClusterCredentialProviderConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ClusterCredentialProviderConfiguration_To_v1alpha1_ClusterCredentialProviderConfiguration(a.(*config.ClusterCredentialProviderConfiguration), b.(*ClusterCredentialProviderConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ClusterHealthCheckConfiguration)(nil), (*config.ClusterHealthCheckConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ClusterHealthCheckConfiguration_To_config_ClusterHealthCheckConfiguration(a.(*ClusterHealthCheckConfiguration), b.(*config.ClusterHealthCheckConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ClusterHealthCheckConfiguration)(nil), (*ClusterHealthCheckConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ClusterHealthCheckConfiguration_To_v1alpha1_ClusterHealthCheckConfiguration(a.(*config.ClusterHealthCheckConfiguration), b.(*ClusterHealthCheckConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ControllerTuning)(nil), (*config.ControllerTuning)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ControllerTuning_To_config_ControllerTuning(a.(*ControllerTuning), b.(*config.ControllerTuning), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ControllerTuning)(nil), (*ControllerTuning)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ControllerTuning_To_v1alpha1_ControllerTuning(a.(*config.ControllerTuning), b.(*ControllerTuning), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ControllerTuningConfiguration)(nil), (*config.ControllerTuningConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ControllerTuningConfiguration_To_config_ControllerTuningConfiguration(a.(*ControllerTuningConfiguration), b.(*config.ControllerTuningConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ControllerTuningConfiguration)(nil), (*ControllerTuningConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ControllerTuningConfiguration_To_v1alpha1_ControllerTuningConfiguration(a.(*config.ControllerTuningConfiguration), b.(*ControllerTuningConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*CoreDNSEtcdProviderConfiguration)(nil), (*config.CoreDNSEtcdProviderConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_CoreDNSEtcdProviderConfiguration_To_config_CoreDNSEtcdProviderConfiguration(a.(*CoreDNSEtcdProviderConfiguration), b.(*config.CoreDNSEtcdProviderConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.CoreDNSEtcdProviderConfiguration)(nil), (*CoreDNSEtcdProviderConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_CoreDNSEtcdProviderConfiguration_To_v1alpha1_CoreDNSEtcdProviderConfiguration(a.(*config.CoreDNSEtcdProviderConfiguration), b.(*CoreDNSEtcdProviderConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FederateControllerConfiguration)(nil), (*config.FederateControllerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_FederateControllerConfiguration_To_config_FederateControllerConfiguration(a.(*FederateControllerConfiguration), b.(*config.FederateControllerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.FederateControllerConfiguration)(nil), (*FederateControllerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_FederateControllerConfiguration_To_v1alpha1_FederateControllerConfiguration(a.(*config.FederateControllerConfiguration), b.(*FederateControllerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*FederatedClusterControllerConfiguration)(nil), (*config.FederatedClusterControllerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_FederatedClusterControllerConfiguration_To_config_FederatedClusterControllerConfiguration(a.(*FederatedClusterControllerConfiguration), b.(*config.FederatedClusterControllerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.FederatedClusterControllerConfiguration)(nil), (*FederatedClusterControllerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_FederatedClusterControllerConfiguration_To_v1alpha1_FederatedClusterControllerConfiguration(a.(*config.FederatedClusterControllerConfiguration), b.(*FederatedClusterControllerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*GenericControllerManagerConfiguration)(nil), (*config.GenericControllerManagerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_GenericControllerManagerConfiguration_To_config_GenericControllerManagerConfiguration(a.(*GenericControllerManagerConfiguration), b.(*config.GenericControllerManagerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.GenericControllerManagerConfiguration)(nil), (*GenericControllerManagerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_GenericControllerManagerConfiguration_To_v1alpha1_GenericControllerManagerConfiguration(a.(*config.GenericControllerManagerConfiguration), b.(*GenericControllerManagerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*GlobalDNSControllerConfiguration)(nil), (*config.GlobalDNSControllerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_GlobalDNSControllerConfiguration_To_config_GlobalDNSControllerConfiguration(a.(*GlobalDNSControllerConfiguration), b.(*config.GlobalDNSControllerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.GlobalDNSControllerConfiguration)(nil), (*GlobalDNSControllerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_GlobalDNSControllerConfiguration_To_v1alpha1_GlobalDNSControllerConfiguration(a.(*config.GlobalDNSControllerConfiguration), b.(*GlobalDNSControllerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*KubeAdmiralControllerManagerConfiguration)(nil), (*config.KubeAdmiralControllerManagerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_KubeAdmiralControllerManagerConfiguration_To_config_KubeAdmiralControllerManagerConfiguration(a.(*KubeAdmiralControllerManagerConfiguration), b.(*config.KubeAdmiralControllerManagerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.KubeAdmiralControllerManagerConfiguration)(nil), (*KubeAdmiralControllerManagerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_KubeAdmiralControllerManagerConfiguration_To_v1alpha1_KubeAdmiralControllerManagerConfiguration(a.(*config.KubeAdmiralControllerManagerConfiguration), b.(*KubeAdmiralControllerManagerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*LeaderElectionConfiguration)(nil), (*config.LeaderElectionConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_LeaderElectionConfiguration_To_config_LeaderElectionConfiguration(a.(*LeaderElectionConfiguration), b.(*config.LeaderElectionConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.LeaderElectionConfiguration)(nil), (*LeaderElectionConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_LeaderElectionConfiguration_To_v1alpha1_LeaderElectionConfiguration(a.(*config.LeaderElectionConfiguration), b.(*LeaderElectionConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*MetricsConfiguration)(nil), (*config.MetricsConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_MetricsConfiguration_To_config_MetricsConfiguration(a.(*MetricsConfiguration), b.(*config.MetricsConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.MetricsConfiguration)(nil), (*MetricsConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_MetricsConfiguration_To_v1alpha1_MetricsConfiguration(a.(*config.MetricsConfiguration), b.(*MetricsConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*MonitorControllerConfiguration)(nil), (*config.MonitorControllerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_MonitorControllerConfiguration_To_config_MonitorControllerConfiguration(a.(*MonitorControllerConfiguration), b.(*config.MonitorControllerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.MonitorControllerConfiguration)(nil), (*MonitorControllerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_MonitorControllerConfiguration_To_v1alpha1_MonitorControllerConfiguration(a.(*config.MonitorControllerConfiguration), b.(*MonitorControllerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NamespaceAutoPropagationControllerConfiguration)(nil), (*config.NamespaceAutoPropagationControllerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NamespaceAutoPropagationControllerConfiguration_To_config_NamespaceAutoPropagationControllerConfiguration(a.(*NamespaceAutoPropagationControllerConfiguration), b.(*config.NamespaceAutoPropagationControllerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.NamespaceAutoPropagationControllerConfiguration)(nil), (*NamespaceAutoPropagationControllerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_NamespaceAutoPropagationControllerConfiguration_To_v1alpha1_NamespaceAutoPropagationControllerConfiguration(a.(*config.NamespaceAutoPropagationControllerConfiguration), b.(*NamespaceAutoPropagationControllerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RFC2136ProviderConfiguration)(nil), (*config.RFC2136ProviderConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RFC2136ProviderConfiguration_To_config_RFC2136ProviderConfiguration(a.(*RFC2136ProviderConfiguration), b.(*config.RFC2136ProviderConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.RFC2136ProviderConfiguration)(nil), (*RFC2136ProviderConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_RFC2136ProviderConfiguration_To_v1alpha1_RFC2136ProviderConfiguration(a.(*config.RFC2136ProviderConfiguration), b.(*RFC2136ProviderConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*RateLimiterConfiguration)(nil), (*config.RateLimiterConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RateLimiterConfiguration_To_config_RateLimiterConfiguration(a.(*RateLimiterConfiguration), b.(*config.RateLimiterConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.RateLimiterConfiguration)(nil), (*RateLimiterConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_RateLimiterConfiguration_To_v1alpha1_RateLimiterConfiguration(a.(*config.RateLimiterConfiguration), b.(*RateLimiterConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SchedulerConfiguration)(nil), (*config.SchedulerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SchedulerConfiguration_To_config_SchedulerConfiguration(a.(*SchedulerConfiguration), b.(*config.SchedulerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.SchedulerConfiguration)(nil), (*SchedulerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_SchedulerConfiguration_To_v1alpha1_SchedulerConfiguration(a.(*config.SchedulerConfiguration), b.(*SchedulerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*ShardingConfiguration)(nil), (*config.ShardingConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ShardingConfiguration_To_config_ShardingConfiguration(a.(*ShardingConfiguration), b.(*config.ShardingConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.ShardingConfiguration)(nil), (*ShardingConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_ShardingConfiguration_To_v1alpha1_ShardingConfiguration(a.(*config.ShardingConfiguration), b.(*ShardingConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*SyncControllerConfiguration)(nil), (*config.SyncControllerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SyncControllerConfiguration_To_config_SyncControllerConfiguration(a.(*SyncControllerConfiguration), b.(*config.SyncControllerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.SyncControllerConfiguration)(nil), (*SyncControllerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_SyncControllerConfiguration_To_v1alpha1_SyncControllerConfiguration(a.(*config.SyncControllerConfiguration), b.(*SyncControllerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*TypeConfigControllerConfiguration)(nil), (*config.TypeConfigControllerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_TypeConfigControllerConfiguration_To_config_TypeConfigControllerConfiguration(a.(*TypeConfigControllerConfiguration), b.(*config.TypeConfigControllerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.TypeConfigControllerConfiguration)(nil), (*TypeConfigControllerConfiguration)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_TypeConfigControllerConfiguration_To_v1alpha1_TypeConfigControllerConfiguration(a.(*config.TypeConfigControllerConfiguration), b.(*TypeConfigControllerConfiguration), scope)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1alpha1_ClientConnectionConfiguration_To_config_ClientConnectionConfiguration(in *ClientConnectionConfiguration, out *config.ClientConnectionConfiguration, s conversion.Scope) error {
+	out.Kubeconfig = in.Kubeconfig
+	out.QPS = in.QPS
+	out.Burst = in.Burst
+	return nil
+}
+
+// Convert_v1alpha1_ClientConnectionConfiguration_To_config_ClientConnectionConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_ClientConnectionConfiguration_To_config_ClientConnectionConfiguration(in *ClientConnectionConfiguration, out *config.ClientConnectionConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ClientConnectionConfiguration_To_config_ClientConnectionConfiguration(in, out, s)
+}
+
+func autoConvert_config_ClientConnectionConfiguration_To_v1alpha1_ClientConnectionConfiguration(in *config.ClientConnectionConfiguration, out *ClientConnectionConfiguration, s conversion.Scope) error {
+	out.Kubeconfig = in.Kubeconfig
+	out.QPS = in.QPS
+	out.Burst = in.Burst
+	return nil
+}
+
+// Convert_config_ClientConnectionConfiguration_To_v1alpha1_ClientConnectionConfiguration is an autogenerated conversion function.
+func Convert_config_ClientConnectionConfiguration_To_v1alpha1_ClientConnectionConfiguration(in *config.ClientConnectionConfiguration, out *ClientConnectionConfiguration, s conversion.Scope) error {
+	return autoConvert_config_ClientConnectionConfiguration_To_v1alpha1_ClientConnectionConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_ClusterClientConfiguration_To_config_ClusterClientConfiguration(in *ClusterClientConfiguration, out *config.ClusterClientConfiguration, s conversion.Scope) error {
+	out.QPS = (*float32)(unsafe.Pointer(in.QPS))
+	out.Burst = (*int32)(unsafe.Pointer(in.Burst))
+	return nil
+}
+
+// Convert_v1alpha1_ClusterClientConfiguration_To_config_ClusterClientConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_ClusterClientConfiguration_To_config_ClusterClientConfiguration(in *ClusterClientConfiguration, out *config.ClusterClientConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ClusterClientConfiguration_To_config_ClusterClientConfiguration(in, out, s)
+}
+
+func autoConvert_config_ClusterClientConfiguration_To_v1alpha1_ClusterClientConfiguration(in *config.ClusterClientConfiguration, out *ClusterClientConfiguration, s conversion.Scope) error {
+	out.QPS = (*float32)(unsafe.Pointer(in.QPS))
+	out.Burst = (*int32)(unsafe.Pointer(in.Burst))
+	return nil
+}
+
+// Convert_config_ClusterClientConfiguration_To_v1alpha1_ClusterClientConfiguration is an autogenerated conversion function.
+func Convert_config_ClusterClientConfiguration_To_v1alpha1_ClusterClientConfiguration(in *config.ClusterClientConfiguration, out *ClusterClientConfiguration, s conversion.Scope) error {
+	return autoConvert_config_ClusterClientConfiguration_To_v1alpha1_ClusterClientConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_ClusterCredentialProviderConfiguration_To_config_ClusterCredentialProviderConfiguration(in *ClusterCredentialProviderConfiguration, out *config.ClusterCredentialProviderConfiguration, s conversion.Scope) error {
+	out.AllowedExecCommands = *(*[]string)(unsafe.Pointer(&in.AllowedExecCommands))
+	out.AllowedTokenFileDirectories = *(*[]string)(unsafe.Pointer(&in.AllowedTokenFileDirectories))
+	return nil
+}
+
+// Convert_v1alpha1_ClusterCredentialProviderConfiguration_To_config_ClusterCredentialProviderConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_ClusterCredentialProviderConfiguration_To_config_ClusterCredentialProviderConfiguration(in *ClusterCredentialProviderConfiguration, out *config.ClusterCredentialProviderConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ClusterCredentialProviderConfiguration_To_config_ClusterCredentialProviderConfiguration(in, out, s)
+}
+
+func autoConvert_config_ClusterCredentialProviderConfiguration_To_v1alpha1_ClusterCredentialProviderConfiguration(in *config.ClusterCredentialProviderConfiguration, out *ClusterCredentialProviderConfiguration, s conversion.Scope) error {
+	out.AllowedExecCommands = *(*[]string)(unsafe.Pointer(&in.AllowedExecCommands))
+	out.AllowedTokenFileDirectories = *(*[]string)(unsafe.Pointer(&in.AllowedTokenFileDirectories))
+	return nil
+}
+
+// Convert_config_ClusterCredentialProviderConfiguration_To_v1alpha1_ClusterCredentialProviderConfiguration is an autogenerated conversion function.
+func Convert_config_ClusterCredentialProviderConfiguration_To_v1alpha1_ClusterCredentialProviderConfiguration(in *config.ClusterCredentialProviderConfiguration, out *ClusterCredentialProviderConfiguration, s conversion.Scope) error {
+	return autoConvert_config_ClusterCredentialProviderConfiguration_To_v1alpha1_ClusterCredentialProviderConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_ClusterHealthCheckConfiguration_To_config_ClusterHealthCheckConfiguration(in *ClusterHealthCheckConfiguration, out *config.ClusterHealthCheckConfiguration, s conversion.Scope) error {
+	out.Period = in.Period
+	out.Path = in.Path
+	out.Timeout = in.Timeout
+	out.SuccessThreshold = in.SuccessThreshold
+	out.FailureThreshold = in.FailureThreshold
+	return nil
+}
+
+// Convert_v1alpha1_ClusterHealthCheckConfiguration_To_config_ClusterHealthCheckConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_ClusterHealthCheckConfiguration_To_config_ClusterHealthCheckConfiguration(in *ClusterHealthCheckConfiguration, out *config.ClusterHealthCheckConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ClusterHealthCheckConfiguration_To_config_ClusterHealthCheckConfiguration(in, out, s)
+}
+
+func autoConvert_config_ClusterHealthCheckConfiguration_To_v1alpha1_ClusterHealthCheckConfiguration(in *config.ClusterHealthCheckConfiguration, out *ClusterHealthCheckConfiguration, s conversion.Scope) error {
+	out.Period = in.Period
+	out.Path = in.Path
+	out.Timeout = in.Timeout
+	out.SuccessThreshold = in.SuccessThreshold
+	out.FailureThreshold = in.FailureThreshold
+	return nil
+}
+
+// Convert_config_ClusterHealthCheckConfiguration_To_v1alpha1_ClusterHealthCheckConfiguration is an autogenerated conversion function.
+func Convert_config_ClusterHealthCheckConfiguration_To_v1alpha1_ClusterHealthCheckConfiguration(in *config.ClusterHealthCheckConfiguration, out *ClusterHealthCheckConfiguration, s conversion.Scope) error {
+	return autoConvert_config_ClusterHealthCheckConfiguration_To_v1alpha1_ClusterHealthCheckConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_ControllerTuning_To_config_ControllerTuning(in *ControllerTuning, out *config.ControllerTuning, s conversion.Scope) error {
+	out.WorkerCount = (*int32)(unsafe.Pointer(in.WorkerCount))
+	out.RateLimiter = (*config.RateLimiterConfiguration)(unsafe.Pointer(in.RateLimiter))
+	out.ClusterClient = (*config.ClusterClientConfiguration)(unsafe.Pointer(in.ClusterClient))
+	return nil
+}
+
+// Convert_v1alpha1_ControllerTuning_To_config_ControllerTuning is an autogenerated conversion function.
+func Convert_v1alpha1_ControllerTuning_To_config_ControllerTuning(in *ControllerTuning, out *config.ControllerTuning, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ControllerTuning_To_config_ControllerTuning(in, out, s)
+}
+
+func autoConvert_config_ControllerTuning_To_v1alpha1_ControllerTuning(in *config.ControllerTuning, out *ControllerTuning, s conversion.Scope) error {
+	out.WorkerCount = (*int32)(unsafe.Pointer(in.WorkerCount))
+	out.RateLimiter = (*RateLimiterConfiguration)(unsafe.Pointer(in.RateLimiter))
+	out.ClusterClient = (*ClusterClientConfiguration)(unsafe.Pointer(in.ClusterClient))
+	return nil
+}
+
+// Convert_config_ControllerTuning_To_v1alpha1_ControllerTuning is an autogenerated conversion function.
+func Convert_config_ControllerTuning_To_v1alpha1_ControllerTuning(in *config.ControllerTuning, out *ControllerTuning, s conversion.Scope) error {
+	return autoConvert_config_ControllerTuning_To_v1alpha1_ControllerTuning(in, out, s)
+}
+
+func autoConvert_v1alpha1_ControllerTuningConfiguration_To_config_ControllerTuningConfiguration(in *ControllerTuningConfiguration, out *config.ControllerTuningConfiguration, s conversion.Scope) error {
+	if err := Convert_v1alpha1_ControllerTuning_To_config_ControllerTuning(&in.Defaults, &out.Defaults, s); err != nil {
+		return err
+	}
+	out.Controllers = *(*map[string]config.ControllerTuning)(unsafe.Pointer(&in.Controllers))
+	out.FederatedTypeConfigs = *(*map[string]map[string]config.ControllerTuning)(unsafe.Pointer(&in.FederatedTypeConfigs))
+	return nil
+}
+
+// Convert_v1alpha1_ControllerTuningConfiguration_To_config_ControllerTuningConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_ControllerTuningConfiguration_To_config_ControllerTuningConfiguration(in *ControllerTuningConfiguration, out *config.ControllerTuningConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ControllerTuningConfiguration_To_config_ControllerTuningConfiguration(in, out, s)
+}
+
+func autoConvert_config_ControllerTuningConfiguration_To_v1alpha1_ControllerTuningConfiguration(in *config.ControllerTuningConfiguration, out *ControllerTuningConfiguration, s conversion.Scope) error {
+	if err := Convert_config_ControllerTuning_To_v1alpha1_ControllerTuning(&in.Defaults, &out.Defaults, s); err != nil {
+		return err
+	}
+	out.Controllers = *(*map[string]ControllerTuning)(unsafe.Pointer(&in.Controllers))
+	out.FederatedTypeConfigs = *(*map[string]map[string]ControllerTuning)(unsafe.Pointer(&in.FederatedTypeConfigs))
+	return nil
+}
+
+// Convert_config_ControllerTuningConfiguration_To_v1alpha1_ControllerTuningConfiguration is an autogenerated conversion function.
+func Convert_config_ControllerTuningConfiguration_To_v1alpha1_ControllerTuningConfiguration(in *config.ControllerTuningConfiguration, out *ControllerTuningConfiguration, s conversion.Scope) error {
+	return autoConvert_config_ControllerTuningConfiguration_To_v1alpha1_ControllerTuningConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_CoreDNSEtcdProviderConfiguration_To_config_CoreDNSEtcdProviderConfiguration(in *CoreDNSEtcdProviderConfiguration, out *config.CoreDNSEtcdProviderConfiguration, s conversion.Scope) error {
+	out.Endpoint = in.Endpoint
+	out.Prefix = in.Prefix
+	out.CAFile = in.CAFile
+	out.CertFile = in.CertFile
+	out.KeyFile = in.KeyFile
+	return nil
+}
+
+// Convert_v1alpha1_CoreDNSEtcdProviderConfiguration_To_config_CoreDNSEtcdProviderConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_CoreDNSEtcdProviderConfiguration_To_config_CoreDNSEtcdProviderConfiguration(in *CoreDNSEtcdProviderConfiguration, out *config.CoreDNSEtcdProviderConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_CoreDNSEtcdProviderConfiguration_To_config_CoreDNSEtcdProviderConfiguration(in, out, s)
+}
+
+func autoConvert_config_CoreDNSEtcdProviderConfiguration_To_v1alpha1_CoreDNSEtcdProviderConfiguration(in *config.CoreDNSEtcdProviderConfiguration, out *CoreDNSEtcdProviderConfiguration, s conversion.Scope) error {
+	out.Endpoint = in.Endpoint
+	out.Prefix = in.Prefix
+	out.CAFile = in.CAFile
+	out.CertFile = in.CertFile
+	out.KeyFile = in.KeyFile
+	return nil
+}
+
+// Convert_config_CoreDNSEtcdProviderConfiguration_To_v1alpha1_CoreDNSEtcdProviderConfiguration is an autogenerated conversion function.
+func Convert_config_CoreDNSEtcdProviderConfiguration_To_v1alpha1_CoreDNSEtcdProviderConfiguration(in *config.CoreDNSEtcdProviderConfiguration, out *CoreDNSEtcdProviderConfiguration, s conversion.Scope) error {
+	return autoConvert_config_CoreDNSEtcdProviderConfiguration_To_v1alpha1_CoreDNSEtcdProviderConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_FederateControllerConfiguration_To_config_FederateControllerConfiguration(in *FederateControllerConfiguration, out *config.FederateControllerConfiguration, s conversion.Scope) error {
+	out.MetadataPropagation = (*corev1alpha1.MetadataPropagation)(unsafe.Pointer(in.MetadataPropagation))
+	return nil
+}
+
+// Convert_v1alpha1_FederateControllerConfiguration_To_config_FederateControllerConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_FederateControllerConfiguration_To_config_FederateControllerConfiguration(in *FederateControllerConfiguration, out *config.FederateControllerConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_FederateControllerConfiguration_To_config_FederateControllerConfiguration(in, out, s)
+}
+
+func autoConvert_config_FederateControllerConfiguration_To_v1alpha1_FederateControllerConfiguration(in *config.FederateControllerConfiguration, out *FederateControllerConfiguration, s conversion.Scope) error {
+	out.MetadataPropagation = (*corev1alpha1.MetadataPropagation)(unsafe.Pointer(in.MetadataPropagation))
+	return nil
+}
+
+// Convert_config_FederateControllerConfiguration_To_v1alpha1_FederateControllerConfiguration is an autogenerated conversion function.
+func Convert_config_FederateControllerConfiguration_To_v1alpha1_FederateControllerConfiguration(in *config.FederateControllerConfiguration, out *FederateControllerConfiguration, s conversion.Scope) error {
+	return autoConvert_config_FederateControllerConfiguration_To_v1alpha1_FederateControllerConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_FederatedClusterControllerConfiguration_To_config_FederatedClusterControllerConfiguration(in *FederatedClusterControllerConfiguration, out *config.FederatedClusterControllerConfiguration, s conversion.Scope) error {
+	out.JoinTimeout = in.JoinTimeout
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.TokenRotationPeriod, &out.TokenRotationPeriod, s); err != nil {
+		return err
+	}
+	out.FlapDetectionWindow = in.FlapDetectionWindow
+	if err := v1.Convert_Pointer_int32_To_int32(&in.FlapDetectionThreshold, &out.FlapDetectionThreshold, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_ClusterHealthCheckConfiguration_To_config_ClusterHealthCheckConfiguration(&in.HealthCheck, &out.HealthCheck, s); err != nil {
+		return err
+	}
+	out.AgentStatusTimeout = in.AgentStatusTimeout
+	if err := Convert_v1alpha1_ClusterCredentialProviderConfiguration_To_config_ClusterCredentialProviderConfiguration(&in.CredentialProviders, &out.CredentialProviders, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha1_FederatedClusterControllerConfiguration_To_config_FederatedClusterControllerConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_FederatedClusterControllerConfiguration_To_config_FederatedClusterControllerConfiguration(in *FederatedClusterControllerConfiguration, out *config.FederatedClusterControllerConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_FederatedClusterControllerConfiguration_To_config_FederatedClusterControllerConfiguration(in, out, s)
+}
+
+func autoConvert_config_FederatedClusterControllerConfiguration_To_v1alpha1_FederatedClusterControllerConfiguration(in *config.FederatedClusterControllerConfiguration, out *FederatedClusterControllerConfiguration, s conversion.Scope) error {
+	out.JoinTimeout = in.JoinTimeout
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.TokenRotationPeriod, &out.TokenRotationPeriod, s); err != nil {
+		return err
+	}
+	out.FlapDetectionWindow = in.FlapDetectionWindow
+	if err := v1.Convert_int32_To_Pointer_int32(&in.FlapDetectionThreshold, &out.FlapDetectionThreshold, s); err != nil {
+		return err
+	}
+	if err := Convert_config_ClusterHealthCheckConfiguration_To_v1alpha1_ClusterHealthCheckConfiguration(&in.HealthCheck, &out.HealthCheck, s); err != nil {
+		return err
+	}
+	out.AgentStatusTimeout = in.AgentStatusTimeout
+	if err := Convert_config_ClusterCredentialProviderConfiguration_To_v1alpha1_ClusterCredentialProviderConfiguration(&in.CredentialProviders, &out.CredentialProviders, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_FederatedClusterControllerConfiguration_To_v1alpha1_FederatedClusterControllerConfiguration is an autogenerated conversion function.
+func Convert_config_FederatedClusterControllerConfiguration_To_v1alpha1_FederatedClusterControllerConfiguration(in *config.FederatedClusterControllerConfiguration, out *FederatedClusterControllerConfiguration, s conversion.Scope) error {
+	return autoConvert_config_FederatedClusterControllerConfiguration_To_v1alpha1_FederatedClusterControllerConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_GenericControllerManagerConfiguration_To_config_GenericControllerManagerConfiguration(in *GenericControllerManagerConfiguration, out *config.GenericControllerManagerConfiguration, s conversion.Scope) error {
+	out.Port = in.Port
+	if err := Convert_v1alpha1_ClientConnectionConfiguration_To_config_ClientConnectionConfiguration(&in.ClientConnection, &out.ClientConnection, s); err != nil {
+		return err
+	}
+	out.Controllers = *(*[]string)(unsafe.Pointer(&in.Controllers))
+	out.WorkerCount = in.WorkerCount
+	out.ControllerTuningConfigFile = in.ControllerTuningConfigFile
+	out.ClusterAvailableDelay = in.ClusterAvailableDelay
+	out.ClusterUnavailableDelay = in.ClusterUnavailableDelay
+	out.MaxPodListers = in.MaxPodListers
+	out.EnablePodPruning = in.EnablePodPruning
+	out.EnableProfiling = in.EnableProfiling
+	return nil
+}
+
+// Convert_v1alpha1_GenericControllerManagerConfiguration_To_config_GenericControllerManagerConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_GenericControllerManagerConfiguration_To_config_GenericControllerManagerConfiguration(in *GenericControllerManagerConfiguration, out *config.GenericControllerManagerConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_GenericControllerManagerConfiguration_To_config_GenericControllerManagerConfiguration(in, out, s)
+}
+
+func autoConvert_config_GenericControllerManagerConfiguration_To_v1alpha1_GenericControllerManagerConfiguration(in *config.GenericControllerManagerConfiguration, out *GenericControllerManagerConfiguration, s conversion.Scope) error {
+	out.Port = in.Port
+	if err := Convert_config_ClientConnectionConfiguration_To_v1alpha1_ClientConnectionConfiguration(&in.ClientConnection, &out.ClientConnection, s); err != nil {
+		return err
+	}
+	out.Controllers = *(*[]string)(unsafe.Pointer(&in.Controllers))
+	out.WorkerCount = in.WorkerCount
+	out.ControllerTuningConfigFile = in.ControllerTuningConfigFile
+	out.ClusterAvailableDelay = in.ClusterAvailableDelay
+	out.ClusterUnavailableDelay = in.ClusterUnavailableDelay
+	out.MaxPodListers = in.MaxPodListers
+	out.EnablePodPruning = in.EnablePodPruning
+	out.EnableProfiling = in.EnableProfiling
+	return nil
+}
+
+// Convert_config_GenericControllerManagerConfiguration_To_v1alpha1_GenericControllerManagerConfiguration is an autogenerated conversion function.
+func Convert_config_GenericControllerManagerConfiguration_To_v1alpha1_GenericControllerManagerConfiguration(in *config.GenericControllerManagerConfiguration, out *GenericControllerManagerConfiguration, s conversion.Scope) error {
+	return autoConvert_config_GenericControllerManagerConfiguration_To_v1alpha1_GenericControllerManagerConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_GlobalDNSControllerConfiguration_To_config_GlobalDNSControllerConfiguration(in *GlobalDNSControllerConfiguration, out *config.GlobalDNSControllerConfiguration, s conversion.Scope) error {
+	out.Provider = in.Provider
+	if err := Convert_v1alpha1_RFC2136ProviderConfiguration_To_config_RFC2136ProviderConfiguration(&in.RFC2136, &out.RFC2136, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_CoreDNSEtcdProviderConfiguration_To_config_CoreDNSEtcdProviderConfiguration(&in.CoreDNSEtcd, &out.CoreDNSEtcd, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha1_GlobalDNSControllerConfiguration_To_config_GlobalDNSControllerConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_GlobalDNSControllerConfiguration_To_config_GlobalDNSControllerConfiguration(in *GlobalDNSControllerConfiguration, out *config.GlobalDNSControllerConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_GlobalDNSControllerConfiguration_To_config_GlobalDNSControllerConfiguration(in, out, s)
+}
+
+func autoConvert_config_GlobalDNSControllerConfiguration_To_v1alpha1_GlobalDNSControllerConfiguration(in *config.GlobalDNSControllerConfiguration, out *GlobalDNSControllerConfiguration, s conversion.Scope) error {
+	out.Provider = in.Provider
+	if err := Convert_config_RFC2136ProviderConfiguration_To_v1alpha1_RFC2136ProviderConfiguration(&in.RFC2136, &out.RFC2136, s); err != nil {
+		return err
+	}
+	if err := Convert_config_CoreDNSEtcdProviderConfiguration_To_v1alpha1_CoreDNSEtcdProviderConfiguration(&in.CoreDNSEtcd, &out.CoreDNSEtcd, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_GlobalDNSControllerConfiguration_To_v1alpha1_GlobalDNSControllerConfiguration is an autogenerated conversion function.
+func Convert_config_GlobalDNSControllerConfiguration_To_v1alpha1_GlobalDNSControllerConfiguration(in *config.GlobalDNSControllerConfiguration, out *GlobalDNSControllerConfiguration, s conversion.Scope) error {
+	return autoConvert_config_GlobalDNSControllerConfiguration_To_v1alpha1_GlobalDNSControllerConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_KubeAdmiralControllerManagerConfiguration_To_config_KubeAdmiralControllerManagerConfiguration(in *KubeAdmiralControllerManagerConfiguration, out *config.KubeAdmiralControllerManagerConfiguration, s conversion.Scope) error {
+	if err := Convert_v1alpha1_GenericControllerManagerConfiguration_To_config_GenericControllerManagerConfiguration(&in.Generic, &out.Generic, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_LeaderElectionConfiguration_To_config_LeaderElectionConfiguration(&in.LeaderElection, &out.LeaderElection, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_ShardingConfiguration_To_config_ShardingConfiguration(&in.Sharding, &out.Sharding, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_MetricsConfiguration_To_config_MetricsConfiguration(&in.Metrics, &out.Metrics, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_ControllerTuningConfiguration_To_config_ControllerTuningConfiguration(&in.ControllerTuning, &out.ControllerTuning, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_FederatedClusterControllerConfiguration_To_config_FederatedClusterControllerConfiguration(&in.FederatedClusterController, &out.FederatedClusterController, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_TypeConfigControllerConfiguration_To_config_TypeConfigControllerConfiguration(&in.TypeConfigController, &out.TypeConfigController, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_FederateControllerConfiguration_To_config_FederateControllerConfiguration(&in.FederateController, &out.FederateController, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_SyncControllerConfiguration_To_config_SyncControllerConfiguration(&in.SyncController, &out.SyncController, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_NamespaceAutoPropagationControllerConfiguration_To_config_NamespaceAutoPropagationControllerConfiguration(&in.NamespaceAutoPropagationController, &out.NamespaceAutoPropagationController, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_MonitorControllerConfiguration_To_config_MonitorControllerConfiguration(&in.MonitorController, &out.MonitorController, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_SchedulerConfiguration_To_config_SchedulerConfiguration(&in.Scheduler, &out.Scheduler, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_GlobalDNSControllerConfiguration_To_config_GlobalDNSControllerConfiguration(&in.GlobalDNSController, &out.GlobalDNSController, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha1_KubeAdmiralControllerManagerConfiguration_To_config_KubeAdmiralControllerManagerConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_KubeAdmiralControllerManagerConfiguration_To_config_KubeAdmiralControllerManagerConfiguration(in *KubeAdmiralControllerManagerConfiguration, out *config.KubeAdmiralControllerManagerConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_KubeAdmiralControllerManagerConfiguration_To_config_KubeAdmiralControllerManagerConfiguration(in, out, s)
+}
+
+func autoConvert_config_KubeAdmiralControllerManagerConfiguration_To_v1alpha1_KubeAdmiralControllerManagerConfiguration(in *config.KubeAdmiralControllerManagerConfiguration, out *KubeAdmiralControllerManagerConfiguration, s conversion.Scope) error {
+	if err := Convert_config_GenericControllerManagerConfiguration_To_v1alpha1_GenericControllerManagerConfiguration(&in.Generic, &out.Generic, s); err != nil {
+		return err
+	}
+	if err := Convert_config_LeaderElectionConfiguration_To_v1alpha1_LeaderElectionConfiguration(&in.LeaderElection, &out.LeaderElection, s); err != nil {
+		return err
+	}
+	if err := Convert_config_ShardingConfiguration_To_v1alpha1_ShardingConfiguration(&in.Sharding, &out.Sharding, s); err != nil {
+		return err
+	}
+	if err := Convert_config_MetricsConfiguration_To_v1alpha1_MetricsConfiguration(&in.Metrics, &out.Metrics, s); err != nil {
+		return err
+	}
+	if err := Convert_config_ControllerTuningConfiguration_To_v1alpha1_ControllerTuningConfiguration(&in.ControllerTuning, &out.ControllerTuning, s); err != nil {
+		return err
+	}
+	if err := Convert_config_FederatedClusterControllerConfiguration_To_v1alpha1_FederatedClusterControllerConfiguration(&in.FederatedClusterController, &out.FederatedClusterController, s); err != nil {
+		return err
+	}
+	if err := Convert_config_TypeConfigControllerConfiguration_To_v1alpha1_TypeConfigControllerConfiguration(&in.TypeConfigController, &out.TypeConfigController, s); err != nil {
+		return err
+	}
+	if err := Convert_config_FederateControllerConfiguration_To_v1alpha1_FederateControllerConfiguration(&in.FederateController, &out.FederateController, s); err != nil {
+		return err
+	}
+	if err := Convert_config_SyncControllerConfiguration_To_v1alpha1_SyncControllerConfiguration(&in.SyncController, &out.SyncController, s); err != nil {
+		return err
+	}
+	if err := Convert_config_NamespaceAutoPropagationControllerConfiguration_To_v1alpha1_NamespaceAutoPropagationControllerConfiguration(&in.NamespaceAutoPropagationController, &out.NamespaceAutoPropagationController, s); err != nil {
+		return err
+	}
+	if err := Convert_config_MonitorControllerConfiguration_To_v1alpha1_MonitorControllerConfiguration(&in.MonitorController, &out.MonitorController, s); err != nil {
+		return err
+	}
+	if err := Convert_config_SchedulerConfiguration_To_v1alpha1_SchedulerConfiguration(&in.Scheduler, &out.Scheduler, s); err != nil {
+		return err
+	}
+	if err := Convert_config_GlobalDNSControllerConfiguration_To_v1alpha1_GlobalDNSControllerConfiguration(&in.GlobalDNSController, &out.GlobalDNSController, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_config_KubeAdmiralControllerManagerConfiguration_To_v1alpha1_KubeAdmiralControllerManagerConfiguration is an autogenerated conversion function.
+func Convert_config_KubeAdmiralControllerManagerConfiguration_To_v1alpha1_KubeAdmiralControllerManagerConfiguration(in *config.KubeAdmiralControllerManagerConfiguration, out *KubeAdmiralControllerManagerConfiguration, s conversion.Scope) error {
+	return autoConvert_config_KubeAdmiralControllerManagerConfiguration_To_v1alpha1_KubeAdmiralControllerManagerConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_LeaderElectionConfiguration_To_config_LeaderElectionConfiguration(in *LeaderElectionConfiguration, out *config.LeaderElectionConfiguration, s conversion.Scope) error {
+	out.LeaderElect = in.LeaderElect
+	out.ResourceName = in.ResourceName
+	out.LeaseDuration = in.LeaseDuration
+	out.RenewDeadline = in.RenewDeadline
+	out.RetryPeriod = in.RetryPeriod
+	return nil
+}
+
+// Convert_v1alpha1_LeaderElectionConfiguration_To_config_LeaderElectionConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_LeaderElectionConfiguration_To_config_LeaderElectionConfiguration(in *LeaderElectionConfiguration, out *config.LeaderElectionConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_LeaderElectionConfiguration_To_config_LeaderElectionConfiguration(in, out, s)
+}
+
+func autoConvert_config_LeaderElectionConfiguration_To_v1alpha1_LeaderElectionConfiguration(in *config.LeaderElectionConfiguration, out *LeaderElectionConfiguration, s conversion.Scope) error {
+	out.LeaderElect = in.LeaderElect
+	out.ResourceName = in.ResourceName
+	out.LeaseDuration = in.LeaseDuration
+	out.RenewDeadline = in.RenewDeadline
+	out.RetryPeriod = in.RetryPeriod
+	return nil
+}
+
+// Convert_config_LeaderElectionConfiguration_To_v1alpha1_LeaderElectionConfiguration is an autogenerated conversion function.
+func Convert_config_LeaderElectionConfiguration_To_v1alpha1_LeaderElectionConfiguration(in *config.LeaderElectionConfiguration, out *LeaderElectionConfiguration, s conversion.Scope) error {
+	return autoConvert_config_LeaderElectionConfiguration_To_v1alpha1_LeaderElectionConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_MetricsConfiguration_To_config_MetricsConfiguration(in *MetricsConfiguration, out *config.MetricsConfiguration, s conversion.Scope) error {
+	out.Env = in.Env
+	out.LogMetrics = in.LogMetrics
+	return nil
+}
+
+// Convert_v1alpha1_MetricsConfiguration_To_config_MetricsConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_MetricsConfiguration_To_config_MetricsConfiguration(in *MetricsConfiguration, out *config.MetricsConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_MetricsConfiguration_To_config_MetricsConfiguration(in, out, s)
+}
+
+func autoConvert_config_MetricsConfiguration_To_v1alpha1_MetricsConfiguration(in *config.MetricsConfiguration, out *MetricsConfiguration, s conversion.Scope) error {
+	out.Env = in.Env
+	out.LogMetrics = in.LogMetrics
+	return nil
+}
+
+// Convert_config_MetricsConfiguration_To_v1alpha1_MetricsConfiguration is an autogenerated conversion function.
+func Convert_config_MetricsConfiguration_To_v1alpha1_MetricsConfiguration(in *config.MetricsConfiguration, out *MetricsConfiguration, s conversion.Scope) error {
+	return autoConvert_config_MetricsConfiguration_To_v1alpha1_MetricsConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_MonitorControllerConfiguration_To_config_MonitorControllerConfiguration(in *MonitorControllerConfiguration, out *config.MonitorControllerConfiguration, s conversion.Scope) error {
+	out.OutOfSyncRecheckDelay = in.OutOfSyncRecheckDelay
+	return nil
+}
+
+// Convert_v1alpha1_MonitorControllerConfiguration_To_config_MonitorControllerConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_MonitorControllerConfiguration_To_config_MonitorControllerConfiguration(in *MonitorControllerConfiguration, out *config.MonitorControllerConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_MonitorControllerConfiguration_To_config_MonitorControllerConfiguration(in, out, s)
+}
+
+func autoConvert_config_MonitorControllerConfiguration_To_v1alpha1_MonitorControllerConfiguration(in *config.MonitorControllerConfiguration, out *MonitorControllerConfiguration, s conversion.Scope) error {
+	out.OutOfSyncRecheckDelay = in.OutOfSyncRecheckDelay
+	return nil
+}
+
+// Convert_config_MonitorControllerConfiguration_To_v1alpha1_MonitorControllerConfiguration is an autogenerated conversion function.
+func Convert_config_MonitorControllerConfiguration_To_v1alpha1_MonitorControllerConfiguration(in *config.MonitorControllerConfiguration, out *MonitorControllerConfiguration, s conversion.Scope) error {
+	return autoConvert_config_MonitorControllerConfiguration_To_v1alpha1_MonitorControllerConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_NamespaceAutoPropagationControllerConfiguration_To_config_NamespaceAutoPropagationControllerConfiguration(in *NamespaceAutoPropagationControllerConfiguration, out *config.NamespaceAutoPropagationControllerConfiguration, s conversion.Scope) error {
+	out.ExcludeRegexp = in.ExcludeRegexp
+	return nil
+}
+
+// Convert_v1alpha1_NamespaceAutoPropagationControllerConfiguration_To_config_NamespaceAutoPropagationControllerConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_NamespaceAutoPropagationControllerConfiguration_To_config_NamespaceAutoPropagationControllerConfiguration(in *NamespaceAutoPropagationControllerConfiguration, out *config.NamespaceAutoPropagationControllerConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NamespaceAutoPropagationControllerConfiguration_To_config_NamespaceAutoPropagationControllerConfiguration(in, out, s)
+}
+
+func autoConvert_config_NamespaceAutoPropagationControllerConfiguration_To_v1alpha1_NamespaceAutoPropagationControllerConfiguration(in *config.NamespaceAutoPropagationControllerConfiguration, out *NamespaceAutoPropagationControllerConfiguration, s conversion.Scope) error {
+	out.ExcludeRegexp = in.ExcludeRegexp
+	return nil
+}
+
+// Convert_config_NamespaceAutoPropagationControllerConfiguration_To_v1alpha1_NamespaceAutoPropagationControllerConfiguration is an autogenerated conversion function.
+func Convert_config_NamespaceAutoPropagationControllerConfiguration_To_v1alpha1_NamespaceAutoPropagationControllerConfiguration(in *config.NamespaceAutoPropagationControllerConfiguration, out *NamespaceAutoPropagationControllerConfiguration, s conversion.Scope) error {
+	return autoConvert_config_NamespaceAutoPropagationControllerConfiguration_To_v1alpha1_NamespaceAutoPropagationControllerConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_RFC2136ProviderConfiguration_To_config_RFC2136ProviderConfiguration(in *RFC2136ProviderConfiguration, out *config.RFC2136ProviderConfiguration, s conversion.Scope) error {
+	out.Server = in.Server
+	out.Zone = in.Zone
+	out.TSIGKeyName = in.TSIGKeyName
+	out.TSIGAlgorithm = in.TSIGAlgorithm
+	out.TSIGSecretFile = in.TSIGSecretFile
+	return nil
+}
+
+// Convert_v1alpha1_RFC2136ProviderConfiguration_To_config_RFC2136ProviderConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_RFC2136ProviderConfiguration_To_config_RFC2136ProviderConfiguration(in *RFC2136ProviderConfiguration, out *config.RFC2136ProviderConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RFC2136ProviderConfiguration_To_config_RFC2136ProviderConfiguration(in, out, s)
+}
+
+func autoConvert_config_RFC2136ProviderConfiguration_To_v1alpha1_RFC2136ProviderConfiguration(in *config.RFC2136ProviderConfiguration, out *RFC2136ProviderConfiguration, s conversion.Scope) error {
+	out.Server = in.Server
+	out.Zone = in.Zone
+	out.TSIGKeyName = in.TSIGKeyName
+	out.TSIGAlgorithm = in.TSIGAlgorithm
+	out.TSIGSecretFile = in.TSIGSecretFile
+	return nil
+}
+
+// Convert_config_RFC2136ProviderConfiguration_To_v1alpha1_RFC2136ProviderConfiguration is an autogenerated conversion function.
+func Convert_config_RFC2136ProviderConfiguration_To_v1alpha1_RFC2136ProviderConfiguration(in *config.RFC2136ProviderConfiguration, out *RFC2136ProviderConfiguration, s conversion.Scope) error {
+	return autoConvert_config_RFC2136ProviderConfiguration_To_v1alpha1_RFC2136ProviderConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_RateLimiterConfiguration_To_config_RateLimiterConfiguration(in *RateLimiterConfiguration, out *config.RateLimiterConfiguration, s conversion.Scope) error {
+	out.QPS = (*float32)(unsafe.Pointer(in.QPS))
+	out.Burst = (*int32)(unsafe.Pointer(in.Burst))
+	out.InitialBackoff = (*v1.Duration)(unsafe.Pointer(in.InitialBackoff))
+	out.MaxBackoff = (*v1.Duration)(unsafe.Pointer(in.MaxBackoff))
+	return nil
+}
+
+// Convert_v1alpha1_RateLimiterConfiguration_To_config_RateLimiterConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_RateLimiterConfiguration_To_config_RateLimiterConfiguration(in *RateLimiterConfiguration, out *config.RateLimiterConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RateLimiterConfiguration_To_config_RateLimiterConfiguration(in, out, s)
+}
+
+func autoConvert_config_RateLimiterConfiguration_To_v1alpha1_RateLimiterConfiguration(in *config.RateLimiterConfiguration, out *RateLimiterConfiguration, s conversion.Scope) error {
+	out.QPS = (*float32)(unsafe.Pointer(in.QPS))
+	out.Burst = (*int32)(unsafe.Pointer(in.Burst))
+	out.InitialBackoff = (*v1.Duration)(unsafe.Pointer(in.InitialBackoff))
+	out.MaxBackoff = (*v1.Duration)(unsafe.Pointer(in.MaxBackoff))
+	return nil
+}
+
+// Convert_config_RateLimiterConfiguration_To_v1alpha1_RateLimiterConfiguration is an autogenerated conversion function.
+func Convert_config_RateLimiterConfiguration_To_v1alpha1_RateLimiterConfiguration(in *config.RateLimiterConfiguration, out *RateLimiterConfiguration, s conversion.Scope) error {
+	return autoConvert_config_RateLimiterConfiguration_To_v1alpha1_RateLimiterConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_SchedulerConfiguration_To_config_SchedulerConfiguration(in *SchedulerConfiguration, out *config.SchedulerConfiguration, s conversion.Scope) error {
+	out.SupplyLimitProportion = in.SupplyLimitProportion
+	out.WebhookTimeout = in.WebhookTimeout
+	return nil
+}
+
+// Convert_v1alpha1_SchedulerConfiguration_To_config_SchedulerConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_SchedulerConfiguration_To_config_SchedulerConfiguration(in *SchedulerConfiguration, out *config.SchedulerConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SchedulerConfiguration_To_config_SchedulerConfiguration(in, out, s)
+}
+
+func autoConvert_config_SchedulerConfiguration_To_v1alpha1_SchedulerConfiguration(in *config.SchedulerConfiguration, out *SchedulerConfiguration, s conversion.Scope) error {
+	out.SupplyLimitProportion = in.SupplyLimitProportion
+	out.WebhookTimeout = in.WebhookTimeout
+	return nil
+}
+
+// Convert_config_SchedulerConfiguration_To_v1alpha1_SchedulerConfiguration is an autogenerated conversion function.
+func Convert_config_SchedulerConfiguration_To_v1alpha1_SchedulerConfiguration(in *config.SchedulerConfiguration, out *SchedulerConfiguration, s conversion.Scope) error {
+	return autoConvert_config_SchedulerConfiguration_To_v1alpha1_SchedulerConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_ShardingConfiguration_To_config_ShardingConfiguration(in *ShardingConfiguration, out *config.ShardingConfiguration, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.BucketCount = in.BucketCount
+	return nil
+}
+
+// Convert_v1alpha1_ShardingConfiguration_To_config_ShardingConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_ShardingConfiguration_To_config_ShardingConfiguration(in *ShardingConfiguration, out *config.ShardingConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ShardingConfiguration_To_config_ShardingConfiguration(in, out, s)
+}
+
+func autoConvert_config_ShardingConfiguration_To_v1alpha1_ShardingConfiguration(in *config.ShardingConfiguration, out *ShardingConfiguration, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.BucketCount = in.BucketCount
+	return nil
+}
+
+// Convert_config_ShardingConfiguration_To_v1alpha1_ShardingConfiguration is an autogenerated conversion function.
+func Convert_config_ShardingConfiguration_To_v1alpha1_ShardingConfiguration(in *config.ShardingConfiguration, out *ShardingConfiguration, s conversion.Scope) error {
+	return autoConvert_config_ShardingConfiguration_To_v1alpha1_ShardingConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_SyncControllerConfiguration_To_config_SyncControllerConfiguration(in *SyncControllerConfiguration, out *config.SyncControllerConfiguration, s conversion.Scope) error {
+	out.PausePropagation = in.PausePropagation
+	return nil
+}
+
+// Convert_v1alpha1_SyncControllerConfiguration_To_config_SyncControllerConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_SyncControllerConfiguration_To_config_SyncControllerConfiguration(in *SyncControllerConfiguration, out *config.SyncControllerConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SyncControllerConfiguration_To_config_SyncControllerConfiguration(in, out, s)
+}
+
+func autoConvert_config_SyncControllerConfiguration_To_v1alpha1_SyncControllerConfiguration(in *config.SyncControllerConfiguration, out *SyncControllerConfiguration, s conversion.Scope) error {
+	out.PausePropagation = in.PausePropagation
+	return nil
+}
+
+// Convert_config_SyncControllerConfiguration_To_v1alpha1_SyncControllerConfiguration is an autogenerated conversion function.
+func Convert_config_SyncControllerConfiguration_To_v1alpha1_SyncControllerConfiguration(in *config.SyncControllerConfiguration, out *SyncControllerConfiguration, s conversion.Scope) error {
+	return autoConvert_config_SyncControllerConfiguration_To_v1alpha1_SyncControllerConfiguration(in, out, s)
+}
+
+func autoConvert_v1alpha1_TypeConfigControllerConfiguration_To_config_TypeConfigControllerConfiguration(in *TypeConfigControllerConfiguration, out *config.TypeConfigControllerConfiguration, s conversion.Scope) error {
+	out.CreateCRDsForFTCs = in.CreateCRDsForFTCs
+	return nil
+}
+
+// Convert_v1alpha1_TypeConfigControllerConfiguration_To_config_TypeConfigControllerConfiguration is an autogenerated conversion function.
+func Convert_v1alpha1_TypeConfigControllerConfiguration_To_config_TypeConfigControllerConfiguration(in *TypeConfigControllerConfiguration, out *config.TypeConfigControllerConfiguration, s conversion.Scope) error {
+	return autoConvert_v1alpha1_TypeConfigControllerConfiguration_To_config_TypeConfigControllerConfiguration(in, out, s)
+}
+
+func autoConvert_config_TypeConfigControllerConfiguration_To_v1alpha1_TypeConfigControllerConfiguration(in *config.TypeConfigControllerConfiguration, out *TypeConfigControllerConfiguration, s conversion.Scope) error {
+	out.CreateCRDsForFTCs = in.CreateCRDsForFTCs
+	return nil
+}
+
+// Convert_config_TypeConfigControllerConfiguration_To_v1alpha1_TypeConfigControllerConfiguration is an autogenerated conversion function.
+func Convert_config_TypeConfigControllerConfiguration_To_v1alpha1_TypeConfigControllerConfiguration(in *config.TypeConfigControllerConfiguration, out *TypeConfigControllerConfiguration, s conversion.Scope) error {
+	return autoConvert_config_TypeConfigControllerConfiguration_To_v1alpha1_TypeConfigControllerConfiguration(in, out, s)
+}
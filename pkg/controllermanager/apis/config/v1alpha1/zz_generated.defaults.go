@@ -0,0 +1,25 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+// Code generated by defaulter-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// RegisterDefaults adds defaulters functions to the given scheme.
+// Public to allow building arbitrary schemes.
+// All generated defaulters are covering - they call all nested defaulters.
+func RegisterDefaults(scheme *runtime.Scheme) error {
+	scheme.AddTypeDefaultingFunc(&KubeAdmiralControllerManagerConfiguration{}, func(obj interface{}) {
+		SetObjectDefaults_KubeAdmiralControllerManagerConfiguration(obj.(*KubeAdmiralControllerManagerConfiguration))
+	})
+	return nil
+}
+
+func SetObjectDefaults_KubeAdmiralControllerManagerConfiguration(in *KubeAdmiralControllerManagerConfiguration) {
+	SetDefaults_KubeAdmiralControllerManagerConfiguration(in)
+	SetDefaults_ControllerTuningConfiguration(&in.ControllerTuning)
+}
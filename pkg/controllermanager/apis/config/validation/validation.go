@@ -0,0 +1,316 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/apis/config"
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/tuning"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/federatedcluster"
+)
+
+var globalDNSProviders = []string{"rfc2136", "coredns-etcd"}
+
+// ValidateKubeAdmiralControllerManagerConfiguration validates the component configuration of the controller manager.
+func ValidateKubeAdmiralControllerManagerConfiguration(
+	cfg *config.KubeAdmiralControllerManagerConfiguration,
+) field.ErrorList {
+	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, validateGeneric(&cfg.Generic, field.NewPath("generic"))...)
+	allErrs = append(allErrs, validateLeaderElection(&cfg.LeaderElection, field.NewPath("leaderElection"))...)
+	allErrs = append(allErrs, validateControllerTuning(
+		&cfg.ControllerTuning,
+		cfg.Generic.ControllerTuningConfigFile,
+		field.NewPath("controllerTuning"),
+	)...)
+
+	shardingPath := field.NewPath("sharding")
+	if cfg.Sharding.Enabled && !cfg.LeaderElection.LeaderElect {
+		allErrs = append(allErrs, field.Invalid(shardingPath.Child("enabled"), true, "requires leader election"))
+	}
+	if cfg.Sharding.BucketCount <= 0 {
+		allErrs = append(allErrs, field.Invalid(shardingPath.Child("bucketCount"), cfg.Sharding.BucketCount, "must be positive"))
+	}
+
+	allErrs = append(allErrs, validateFederatedClusterController(
+		&cfg.FederatedClusterController,
+		field.NewPath("federatedClusterController"),
+	)...)
+
+	if expr := cfg.NamespaceAutoPropagationController.ExcludeRegexp; expr != "" {
+		if _, err := regexp.Compile(expr); err != nil {
+			allErrs = append(allErrs, field.Invalid(
+				field.NewPath("namespaceAutoPropagationController", "excludeRegexp"),
+				expr,
+				err.Error(),
+			))
+		}
+	}
+
+	monitorPath := field.NewPath("monitorController")
+	if cfg.MonitorController.OutOfSyncRecheckDelay.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(
+			monitorPath.Child("outOfSyncRecheckDelay"),
+			cfg.MonitorController.OutOfSyncRecheckDelay.Duration.String(),
+			"must be positive",
+		))
+	}
+
+	schedulerPath := field.NewPath("scheduler")
+	if cfg.Scheduler.SupplyLimitProportion < 1 {
+		allErrs = append(allErrs, field.Invalid(
+			schedulerPath.Child("supplyLimitProportion"),
+			cfg.Scheduler.SupplyLimitProportion,
+			"must not be less than 1",
+		))
+	}
+	if cfg.Scheduler.WebhookTimeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(
+			schedulerPath.Child("webhookTimeout"),
+			cfg.Scheduler.WebhookTimeout.Duration.String(),
+			"must be positive",
+		))
+	}
+
+	if provider := cfg.GlobalDNSController.Provider; provider != "" {
+		found := false
+		for _, p := range globalDNSProviders {
+			found = found || p == provider
+		}
+		if !found {
+			allErrs = append(allErrs, field.NotSupported(
+				field.NewPath("globalDNSController", "provider"),
+				provider,
+				globalDNSProviders,
+			))
+		}
+	}
+	if etcd := cfg.GlobalDNSController.CoreDNSEtcd; (etcd.CertFile == "") != (etcd.KeyFile == "") {
+		allErrs = append(allErrs, field.Invalid(
+			field.NewPath("globalDNSController", "coreDNSEtcd", "certFile"),
+			etcd.CertFile,
+			"certFile and keyFile must be set together",
+		))
+	}
+
+	return allErrs
+}
+
+func validateGeneric(cfg *config.GenericControllerManagerConfiguration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if cfg.Port <= 0 || cfg.Port > 65535 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("port"), cfg.Port, "must be between 1 and 65535"))
+	}
+	if cfg.ClientConnection.QPS <= 0 {
+		allErrs = append(allErrs, field.Invalid(
+			fldPath.Child("clientConnection", "qps"),
+			cfg.ClientConnection.QPS,
+			"must be positive",
+		))
+	}
+	if cfg.ClientConnection.Burst <= 0 {
+		allErrs = append(allErrs, field.Invalid(
+			fldPath.Child("clientConnection", "burst"),
+			cfg.ClientConnection.Burst,
+			"must be positive",
+		))
+	}
+	for i, controller := range cfg.Controllers {
+		if controller == "" || controller == "-" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("controllers").Index(i), controller, "must not be empty"))
+		}
+	}
+	if cfg.WorkerCount <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("workerCount"), cfg.WorkerCount, "must be positive"))
+	}
+	if cfg.ClusterAvailableDelay.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(
+			fldPath.Child("clusterAvailableDelay"),
+			cfg.ClusterAvailableDelay.Duration.String(),
+			"must not be negative",
+		))
+	}
+	if cfg.ClusterUnavailableDelay.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(
+			fldPath.Child("clusterUnavailableDelay"),
+			cfg.ClusterUnavailableDelay.Duration.String(),
+			"must not be negative",
+		))
+	}
+	return allErrs
+}
+
+func validateControllerTuning(
+	cfg *config.ControllerTuningConfiguration,
+	configFile string,
+	fldPath *field.Path,
+) field.ErrorList {
+	allErrs := field.ErrorList{}
+	isEmpty := cfg.Defaults == (config.ControllerTuning{}) && len(cfg.Controllers) == 0 && len(cfg.FederatedTypeConfigs) == 0
+	if isEmpty {
+		return allErrs
+	}
+	if configFile != "" {
+		allErrs = append(allErrs, field.Forbidden(
+			fldPath,
+			"must not be set together with generic.controllerTuningConfigFile",
+		))
+	}
+	// Names of controllers and FederatedTypeConfigs are only known at runtime, so they are checked when the
+	// configuration is loaded.
+	if err := tuning.ValidateSettings(cfg); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, "", err.Error()))
+	}
+	return allErrs
+}
+
+func validateLeaderElection(cfg *config.LeaderElectionConfiguration, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if !cfg.LeaderElect {
+		return allErrs
+	}
+	if cfg.ResourceName == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("resourceName"), ""))
+	}
+	if cfg.LeaseDuration.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(
+			fldPath.Child("leaseDuration"),
+			cfg.LeaseDuration.Duration.String(),
+			"must be positive",
+		))
+	}
+	if cfg.RenewDeadline.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(
+			fldPath.Child("renewDeadline"),
+			cfg.RenewDeadline.Duration.String(),
+			"must be positive",
+		))
+	}
+	if cfg.RetryPeriod.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(
+			fldPath.Child("retryPeriod"),
+			cfg.RetryPeriod.Duration.String(),
+			"must be positive",
+		))
+	}
+	if cfg.LeaseDuration.Duration <= cfg.RenewDeadline.Duration {
+		allErrs = append(allErrs, field.Invalid(
+			fldPath.Child("leaseDuration"),
+			cfg.LeaseDuration.Duration.String(),
+			"must be greater than renewDeadline",
+		))
+	}
+	return allErrs
+}
+
+func validateFederatedClusterController(
+	cfg *config.FederatedClusterControllerConfiguration,
+	fldPath *field.Path,
+) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if cfg.JoinTimeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(
+			fldPath.Child("joinTimeout"),
+			cfg.JoinTimeout.Duration.String(),
+			"must be positive",
+		))
+	}
+	if cfg.TokenRotationPeriod.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(
+			fldPath.Child("tokenRotationPeriod"),
+			cfg.TokenRotationPeriod.Duration.String(),
+			"must not be negative",
+		))
+	}
+	if cfg.FlapDetectionThreshold < 0 || cfg.FlapDetectionThreshold > federatedcluster.ClusterHealthTransitionHistoryLimit {
+		allErrs = append(allErrs, field.Invalid(
+			fldPath.Child("flapDetectionThreshold"),
+			cfg.FlapDetectionThreshold,
+			fmt.Sprintf("must be between 0 and %d", federatedcluster.ClusterHealthTransitionHistoryLimit),
+		))
+	}
+
+	healthCheckPath := fldPath.Child("healthCheck")
+	if cfg.HealthCheck.Period.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(
+			healthCheckPath.Child("period"),
+			cfg.HealthCheck.Period.Duration.String(),
+			"must be positive",
+		))
+	}
+	if !strings.HasPrefix(cfg.HealthCheck.Path, "/") {
+		allErrs = append(allErrs, field.Invalid(healthCheckPath.Child("path"), cfg.HealthCheck.Path, "must start with /"))
+	}
+	if cfg.HealthCheck.Timeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(
+			healthCheckPath.Child("timeout"),
+			cfg.HealthCheck.Timeout.Duration.String(),
+			"must be positive",
+		))
+	}
+	if cfg.HealthCheck.SuccessThreshold < 1 ||
+		cfg.HealthCheck.SuccessThreshold > federatedcluster.ClusterHealthProbeHistoryLimit {
+		allErrs = append(allErrs, field.Invalid(
+			healthCheckPath.Child("successThreshold"),
+			cfg.HealthCheck.SuccessThreshold,
+			fmt.Sprintf("must be between 1 and %d", federatedcluster.ClusterHealthProbeHistoryLimit),
+		))
+	}
+	if cfg.HealthCheck.FailureThreshold < 1 ||
+		cfg.HealthCheck.FailureThreshold > federatedcluster.ClusterHealthProbeHistoryLimit {
+		allErrs = append(allErrs, field.Invalid(
+			healthCheckPath.Child("failureThreshold"),
+			cfg.HealthCheck.FailureThreshold,
+			fmt.Sprintf("must be between 1 and %d", federatedcluster.ClusterHealthProbeHistoryLimit),
+		))
+	}
+
+	if cfg.AgentStatusTimeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(
+			fldPath.Child("agentStatusTimeout"),
+			cfg.AgentStatusTimeout.Duration.String(),
+			"must be positive",
+		))
+	}
+
+	credentialProvidersPath := fldPath.Child("credentialProviders")
+	for i, command := range cfg.CredentialProviders.AllowedExecCommands {
+		if command == "" {
+			allErrs = append(allErrs, field.Invalid(
+				credentialProvidersPath.Child("allowedExecCommands").Index(i),
+				command,
+				"must not be empty",
+			))
+		}
+	}
+	for i, dir := range cfg.CredentialProviders.AllowedTokenFileDirectories {
+		if !filepath.IsAbs(dir) {
+			allErrs = append(allErrs, field.Invalid(
+				credentialProvidersPath.Child("allowedTokenFileDirectories").Index(i),
+				dir,
+				"must be an absolute path",
+			))
+		}
+	}
+	return allErrs
+}
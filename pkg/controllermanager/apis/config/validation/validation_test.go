@@ -0,0 +1,164 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/apis/config"
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/apis/config/scheme"
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/apis/config/v1alpha1"
+)
+
+func defaultConfig(g *gomega.WithT) *config.KubeAdmiralControllerManagerConfiguration {
+	versioned := &v1alpha1.KubeAdmiralControllerManagerConfiguration{}
+	scheme.Scheme.Default(versioned)
+	cfg := &config.KubeAdmiralControllerManagerConfiguration{}
+	g.Expect(scheme.Scheme.Convert(versioned, cfg, nil)).To(gomega.Succeed())
+	return cfg
+}
+
+func TestValidateKubeAdmiralControllerManagerConfiguration(t *testing.T) {
+	tests := map[string]struct {
+		mutate         func(cfg *config.KubeAdmiralControllerManagerConfiguration)
+		expectedFields []string
+	}{
+		"defaults are valid": {
+			mutate: func(cfg *config.KubeAdmiralControllerManagerConfiguration) {},
+		},
+		"sharding requires leader election": {
+			mutate: func(cfg *config.KubeAdmiralControllerManagerConfiguration) {
+				cfg.Sharding.Enabled = true
+			},
+			expectedFields: []string{"sharding.enabled"},
+		},
+		"sharding with leader election is valid": {
+			mutate: func(cfg *config.KubeAdmiralControllerManagerConfiguration) {
+				cfg.Sharding.Enabled = true
+				cfg.LeaderElection.LeaderElect = true
+			},
+		},
+		"lease duration must exceed renew deadline": {
+			mutate: func(cfg *config.KubeAdmiralControllerManagerConfiguration) {
+				cfg.LeaderElection.LeaderElect = true
+				cfg.LeaderElection.LeaseDuration.Duration = 10 * time.Second
+			},
+			expectedFields: []string{"leaderElection.leaseDuration"},
+		},
+		"invalid generic settings": {
+			mutate: func(cfg *config.KubeAdmiralControllerManagerConfiguration) {
+				cfg.Generic.Port = 0
+				cfg.Generic.WorkerCount = 0
+				cfg.Generic.Controllers = []string{"*", "-"}
+			},
+			expectedFields: []string{"generic.port", "generic.controllers[1]", "generic.workerCount"},
+		},
+		"invalid cluster health check": {
+			mutate: func(cfg *config.KubeAdmiralControllerManagerConfiguration) {
+				cfg.FederatedClusterController.FlapDetectionThreshold = 11
+				cfg.FederatedClusterController.HealthCheck.Path = "healthz"
+				cfg.FederatedClusterController.HealthCheck.SuccessThreshold = 0
+			},
+			expectedFields: []string{
+				"federatedClusterController.flapDetectionThreshold",
+				"federatedClusterController.healthCheck.path",
+				"federatedClusterController.healthCheck.successThreshold",
+			},
+		},
+		"invalid credential provider allowlists": {
+			mutate: func(cfg *config.KubeAdmiralControllerManagerConfiguration) {
+				cfg.FederatedClusterController.CredentialProviders.AllowedExecCommands = []string{"/usr/bin/get-token", ""}
+				cfg.FederatedClusterController.CredentialProviders.AllowedTokenFileDirectories = []string{"tokens"}
+			},
+			expectedFields: []string{
+				"federatedClusterController.credentialProviders.allowedExecCommands[1]",
+				"federatedClusterController.credentialProviders.allowedTokenFileDirectories[0]",
+			},
+		},
+		"invalid exclude regexp": {
+			mutate: func(cfg *config.KubeAdmiralControllerManagerConfiguration) {
+				cfg.NamespaceAutoPropagationController.ExcludeRegexp = "("
+			},
+			expectedFields: []string{"namespaceAutoPropagationController.excludeRegexp"},
+		},
+		"invalid scheduler settings": {
+			mutate: func(cfg *config.KubeAdmiralControllerManagerConfiguration) {
+				cfg.Scheduler.SupplyLimitProportion = 0.5
+				cfg.Scheduler.WebhookTimeout.Duration = 0
+			},
+			expectedFields: []string{"scheduler.supplyLimitProportion", "scheduler.webhookTimeout"},
+		},
+		"unknown DNS provider": {
+			mutate: func(cfg *config.KubeAdmiralControllerManagerConfiguration) {
+				cfg.GlobalDNSController.Provider = "route53"
+			},
+			expectedFields: []string{"globalDNSController.provider"},
+		},
+		"etcd client certificate without key": {
+			mutate: func(cfg *config.KubeAdmiralControllerManagerConfiguration) {
+				cfg.GlobalDNSController.CoreDNSEtcd.CertFile = "/etc/kubeadmiral/etcd/client.crt"
+			},
+			expectedFields: []string{"globalDNSController.coreDNSEtcd.certFile"},
+		},
+		"valid controller tuning": {
+			mutate: func(cfg *config.KubeAdmiralControllerManagerConfiguration) {
+				cfg.ControllerTuning.Defaults.WorkerCount = pointer.Int32(4)
+				cfg.ControllerTuning.Controllers = map[string]config.ControllerTuning{
+					"scheduler": {RateLimiter: &config.RateLimiterConfiguration{QPS: pointer.Float32(50), Burst: pointer.Int32(100)}},
+				}
+			},
+		},
+		"invalid controller tuning": {
+			mutate: func(cfg *config.KubeAdmiralControllerManagerConfiguration) {
+				cfg.ControllerTuning.Controllers = map[string]config.ControllerTuning{
+					"scheduler": {RateLimiter: &config.RateLimiterConfiguration{
+						InitialBackoff: &metav1.Duration{Duration: time.Minute},
+						MaxBackoff:     &metav1.Duration{Duration: time.Second},
+					}},
+				}
+			},
+			expectedFields: []string{"controllerTuning"},
+		},
+		"controller tuning together with a tuning file": {
+			mutate: func(cfg *config.KubeAdmiralControllerManagerConfiguration) {
+				cfg.Generic.ControllerTuningConfigFile = "/etc/kubeadmiral/controller-tuning.yaml"
+				cfg.ControllerTuning.Defaults.WorkerCount = pointer.Int32(4)
+			},
+			expectedFields: []string{"controllerTuning"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			cfg := defaultConfig(g)
+			test.mutate(cfg)
+
+			fields := []string{}
+			for _, err := range ValidateKubeAdmiralControllerManagerConfiguration(cfg) {
+				fields = append(fields, err.Field)
+			}
+			g.Expect(fields).To(gomega.ConsistOf(test.expectedFields))
+		})
+	}
+}
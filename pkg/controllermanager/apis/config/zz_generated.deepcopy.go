@@ -0,0 +1,494 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+// Code generated by deepcopy-gen. DO NOT EDIT.
+
+package config
+
+import (
+	v1alpha1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientConnectionConfiguration) DeepCopyInto(out *ClientConnectionConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientConnectionConfiguration.
+func (in *ClientConnectionConfiguration) DeepCopy() *ClientConnectionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientConnectionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterClientConfiguration) DeepCopyInto(out *ClusterClientConfiguration) {
+	*out = *in
+	if in.QPS != nil {
+		in, out := &in.QPS, &out.QPS
+		*out = new(float32)
+		**out = **in
+	}
+	if in.Burst != nil {
+		in, out := &in.Burst, &out.Burst
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClientConfiguration.
+func (in *ClusterClientConfiguration) DeepCopy() *ClusterClientConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterClientConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCredentialProviderConfiguration) DeepCopyInto(out *ClusterCredentialProviderConfiguration) {
+	*out = *in
+	if in.AllowedExecCommands != nil {
+		in, out := &in.AllowedExecCommands, &out.AllowedExecCommands
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedTokenFileDirectories != nil {
+		in, out := &in.AllowedTokenFileDirectories, &out.AllowedTokenFileDirectories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCredentialProviderConfiguration.
+func (in *ClusterCredentialProviderConfiguration) DeepCopy() *ClusterCredentialProviderConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCredentialProviderConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHealthCheckConfiguration) DeepCopyInto(out *ClusterHealthCheckConfiguration) {
+	*out = *in
+	out.Period = in.Period
+	out.Timeout = in.Timeout
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHealthCheckConfiguration.
+func (in *ClusterHealthCheckConfiguration) DeepCopy() *ClusterHealthCheckConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHealthCheckConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControllerTuning) DeepCopyInto(out *ControllerTuning) {
+	*out = *in
+	if in.WorkerCount != nil {
+		in, out := &in.WorkerCount, &out.WorkerCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RateLimiter != nil {
+		in, out := &in.RateLimiter, &out.RateLimiter
+		*out = new(RateLimiterConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterClient != nil {
+		in, out := &in.ClusterClient, &out.ClusterClient
+		*out = new(ClusterClientConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerTuning.
+func (in *ControllerTuning) DeepCopy() *ControllerTuning {
+	if in == nil {
+		return nil
+	}
+	out := new(ControllerTuning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControllerTuningConfiguration) DeepCopyInto(out *ControllerTuningConfiguration) {
+	*out = *in
+	in.Defaults.DeepCopyInto(&out.Defaults)
+	if in.Controllers != nil {
+		in, out := &in.Controllers, &out.Controllers
+		*out = make(map[string]ControllerTuning, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.FederatedTypeConfigs != nil {
+		in, out := &in.FederatedTypeConfigs, &out.FederatedTypeConfigs
+		*out = make(map[string]map[string]ControllerTuning, len(*in))
+		for key, val := range *in {
+			var outVal map[string]ControllerTuning
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make(map[string]ControllerTuning, len(*in))
+				for key, val := range *in {
+					(*out)[key] = *val.DeepCopy()
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerTuningConfiguration.
+func (in *ControllerTuningConfiguration) DeepCopy() *ControllerTuningConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ControllerTuningConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CoreDNSEtcdProviderConfiguration) DeepCopyInto(out *CoreDNSEtcdProviderConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoreDNSEtcdProviderConfiguration.
+func (in *CoreDNSEtcdProviderConfiguration) DeepCopy() *CoreDNSEtcdProviderConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(CoreDNSEtcdProviderConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederateControllerConfiguration) DeepCopyInto(out *FederateControllerConfiguration) {
+	*out = *in
+	if in.MetadataPropagation != nil {
+		in, out := &in.MetadataPropagation, &out.MetadataPropagation
+		*out = new(v1alpha1.MetadataPropagation)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederateControllerConfiguration.
+func (in *FederateControllerConfiguration) DeepCopy() *FederateControllerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(FederateControllerConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FederatedClusterControllerConfiguration) DeepCopyInto(out *FederatedClusterControllerConfiguration) {
+	*out = *in
+	out.JoinTimeout = in.JoinTimeout
+	out.TokenRotationPeriod = in.TokenRotationPeriod
+	out.FlapDetectionWindow = in.FlapDetectionWindow
+	out.HealthCheck = in.HealthCheck
+	out.AgentStatusTimeout = in.AgentStatusTimeout
+	in.CredentialProviders.DeepCopyInto(&out.CredentialProviders)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FederatedClusterControllerConfiguration.
+func (in *FederatedClusterControllerConfiguration) DeepCopy() *FederatedClusterControllerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(FederatedClusterControllerConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GenericControllerManagerConfiguration) DeepCopyInto(out *GenericControllerManagerConfiguration) {
+	*out = *in
+	out.ClientConnection = in.ClientConnection
+	if in.Controllers != nil {
+		in, out := &in.Controllers, &out.Controllers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.ClusterAvailableDelay = in.ClusterAvailableDelay
+	out.ClusterUnavailableDelay = in.ClusterUnavailableDelay
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GenericControllerManagerConfiguration.
+func (in *GenericControllerManagerConfiguration) DeepCopy() *GenericControllerManagerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(GenericControllerManagerConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalDNSControllerConfiguration) DeepCopyInto(out *GlobalDNSControllerConfiguration) {
+	*out = *in
+	out.RFC2136 = in.RFC2136
+	out.CoreDNSEtcd = in.CoreDNSEtcd
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalDNSControllerConfiguration.
+func (in *GlobalDNSControllerConfiguration) DeepCopy() *GlobalDNSControllerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalDNSControllerConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeAdmiralControllerManagerConfiguration) DeepCopyInto(out *KubeAdmiralControllerManagerConfiguration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.Generic.DeepCopyInto(&out.Generic)
+	out.LeaderElection = in.LeaderElection
+	out.Sharding = in.Sharding
+	out.Metrics = in.Metrics
+	in.ControllerTuning.DeepCopyInto(&out.ControllerTuning)
+	in.FederatedClusterController.DeepCopyInto(&out.FederatedClusterController)
+	out.TypeConfigController = in.TypeConfigController
+	in.FederateController.DeepCopyInto(&out.FederateController)
+	out.SyncController = in.SyncController
+	out.NamespaceAutoPropagationController = in.NamespaceAutoPropagationController
+	out.MonitorController = in.MonitorController
+	out.Scheduler = in.Scheduler
+	out.GlobalDNSController = in.GlobalDNSController
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeAdmiralControllerManagerConfiguration.
+func (in *KubeAdmiralControllerManagerConfiguration) DeepCopy() *KubeAdmiralControllerManagerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeAdmiralControllerManagerConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *KubeAdmiralControllerManagerConfiguration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LeaderElectionConfiguration) DeepCopyInto(out *LeaderElectionConfiguration) {
+	*out = *in
+	out.LeaseDuration = in.LeaseDuration
+	out.RenewDeadline = in.RenewDeadline
+	out.RetryPeriod = in.RetryPeriod
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LeaderElectionConfiguration.
+func (in *LeaderElectionConfiguration) DeepCopy() *LeaderElectionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(LeaderElectionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsConfiguration) DeepCopyInto(out *MetricsConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsConfiguration.
+func (in *MetricsConfiguration) DeepCopy() *MetricsConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitorControllerConfiguration) DeepCopyInto(out *MonitorControllerConfiguration) {
+	*out = *in
+	out.OutOfSyncRecheckDelay = in.OutOfSyncRecheckDelay
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitorControllerConfiguration.
+func (in *MonitorControllerConfiguration) DeepCopy() *MonitorControllerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitorControllerConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceAutoPropagationControllerConfiguration) DeepCopyInto(out *NamespaceAutoPropagationControllerConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceAutoPropagationControllerConfiguration.
+func (in *NamespaceAutoPropagationControllerConfiguration) DeepCopy() *NamespaceAutoPropagationControllerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceAutoPropagationControllerConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RFC2136ProviderConfiguration) DeepCopyInto(out *RFC2136ProviderConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RFC2136ProviderConfiguration.
+func (in *RFC2136ProviderConfiguration) DeepCopy() *RFC2136ProviderConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(RFC2136ProviderConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimiterConfiguration) DeepCopyInto(out *RateLimiterConfiguration) {
+	*out = *in
+	if in.QPS != nil {
+		in, out := &in.QPS, &out.QPS
+		*out = new(float32)
+		**out = **in
+	}
+	if in.Burst != nil {
+		in, out := &in.Burst, &out.Burst
+		*out = new(int32)
+		**out = **in
+	}
+	if in.InitialBackoff != nil {
+		in, out := &in.InitialBackoff, &out.InitialBackoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimiterConfiguration.
+func (in *RateLimiterConfiguration) DeepCopy() *RateLimiterConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimiterConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerConfiguration) DeepCopyInto(out *SchedulerConfiguration) {
+	*out = *in
+	out.WebhookTimeout = in.WebhookTimeout
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerConfiguration.
+func (in *SchedulerConfiguration) DeepCopy() *SchedulerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ShardingConfiguration) DeepCopyInto(out *ShardingConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ShardingConfiguration.
+func (in *ShardingConfiguration) DeepCopy() *ShardingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ShardingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncControllerConfiguration) DeepCopyInto(out *SyncControllerConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncControllerConfiguration.
+func (in *SyncControllerConfiguration) DeepCopy() *SyncControllerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncControllerConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TypeConfigControllerConfiguration) DeepCopyInto(out *TypeConfigControllerConfiguration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TypeConfigControllerConfiguration.
+func (in *TypeConfigControllerConfiguration) DeepCopy() *TypeConfigControllerConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(TypeConfigControllerConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -37,11 +37,30 @@ import (
 	"k8s.io/klog/v2"
 )
 
+// Timing contains the durations of leader election.
+type Timing struct {
+	// LeaseDuration is the duration that non-leader candidates wait after the last renewal of the lease before they
+	// try to acquire leadership.
+	LeaseDuration time.Duration
+	// RenewDeadline is the duration that the leader retries renewing the lease before it gives up leadership.
+	RenewDeadline time.Duration
+	// RetryPeriod is the interval between attempts to acquire or renew the lease.
+	RetryPeriod time.Duration
+}
+
+// DefaultTiming is the timing used if it is not configured.
+var DefaultTiming = Timing{
+	LeaseDuration: 15 * time.Second,
+	RenewDeadline: 10 * time.Second,
+	RetryPeriod:   5 * time.Second,
+}
+
 func NewFederationLeaderElector(
 	config *rest.Config,
 	fnRunManager func(context.Context),
 	fedNamespace string,
 	component string,
+	timing Timing,
 	healthzAdaptor *leaderelection.HealthzAdaptor,
 ) (*leaderelection.LeaderElector, error) {
 	leaderElectionClient := kubernetes.NewForConfigOrDie(config)
@@ -74,9 +93,9 @@ func NewFederationLeaderElector(
 
 	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
 		Lock:          rl,
-		LeaseDuration: 15 * time.Second,
-		RenewDeadline: 10 * time.Second,
-		RetryPeriod:   5 * time.Second,
+		LeaseDuration: timing.LeaseDuration,
+		RenewDeadline: timing.RenewDeadline,
+		RetryPeriod:   timing.RetryPeriod,
 		WatchDog:      healthzAdaptor,
 		Callbacks: leaderelection.LeaderCallbacks{
 			OnStartedLeading: func(ctx context.Context) {
@@ -0,0 +1,65 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"hash/fnv"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// AssignBuckets distributes bucketCount buckets among the given members with rendezvous hashing: each bucket is
+// assigned to the member with the highest hash of the member and the bucket. When a member joins or leaves, only the
+// buckets it gains or loses move between members.
+func AssignBuckets(members []string, bucketCount int) map[string]sets.Set[int] {
+	assignment := make(map[string]sets.Set[int], len(members))
+	for _, member := range members {
+		assignment[member] = sets.New[int]()
+	}
+	if len(members) == 0 {
+		return assignment
+	}
+
+	for bucket := 0; bucket < bucketCount; bucket++ {
+		var owner string
+		var ownerScore uint64
+		for _, member := range members {
+			score := rendezvousScore(member, bucket)
+			if owner == "" || score > ownerScore || (score == ownerScore && member < owner) {
+				owner, ownerScore = member, score
+			}
+		}
+		assignment[owner].Insert(bucket)
+	}
+
+	return assignment
+}
+
+func rendezvousScore(member string, bucket int) uint64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(member))
+	_, _ = hash.Write([]byte{0})
+	_, _ = hash.Write([]byte(strconv.Itoa(bucket)))
+
+	// FNV hashes of similar inputs are correlated, so the hash is mixed with the finalizer of splitmix64 to spread
+	// buckets evenly among members.
+	z := hash.Sum64()
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
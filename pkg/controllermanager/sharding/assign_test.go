@@ -0,0 +1,71 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestAssignBuckets(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	const bucketCount = 256
+	members := []string{"a", "b", "c", "d"}
+	assignment := AssignBuckets(members, bucketCount)
+
+	all := sets.New[int]()
+	for _, member := range members {
+		buckets := assignment[member]
+		g.Expect(all.Intersection(buckets).Len()).To(gomega.BeZero(), "buckets must not be assigned twice")
+		all = all.Union(buckets)
+
+		// Rendezvous hashing does not balance perfectly, but each member should get a fair share.
+		g.Expect(buckets.Len()).To(gomega.BeNumerically(">", bucketCount/len(members)/2), "member %s", member)
+	}
+	g.Expect(all.Len()).To(gomega.Equal(bucketCount))
+
+	// Buckets only move from the member that leaves.
+	remaining := AssignBuckets([]string{"a", "c", "d"}, bucketCount)
+	for _, member := range []string{"a", "c", "d"} {
+		g.Expect(remaining[member].IsSuperset(assignment[member])).To(gomega.BeTrue(), "member %s", member)
+	}
+
+	// The assignment does not depend on the order of members.
+	g.Expect(AssignBuckets([]string{"d", "c", "b", "a"}, bucketCount)).To(gomega.Equal(assignment))
+
+	g.Expect(AssignBuckets(nil, bucketCount)).To(gomega.BeEmpty())
+}
+
+func TestAssignBucketsBalance(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	const bucketCount = 64
+	for memberCount := 1; memberCount <= 8; memberCount++ {
+		members := make([]string, memberCount)
+		for i := range members {
+			members[i] = fmt.Sprintf("kubeadmiral-controller-manager-%d", i)
+		}
+
+		for member, buckets := range AssignBuckets(members, bucketCount) {
+			g.Expect(buckets.Len()).To(gomega.BeNumerically(">", 0), "member %s of %d", member, memberCount)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/pointer"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+// CoordinatorConfig configures the membership of a replica in a group of sharded controller manager replicas.
+type CoordinatorConfig struct {
+	// Namespace is the namespace of the Leases of the group.
+	Namespace string
+	// Group is the name of the group, which is shared by all its replicas.
+	Group string
+	// Identity is the holder identity recorded in the Lease of the replica.
+	Identity string
+	// BucketCount is the number of buckets that keys are hashed into. It must be the same for all replicas.
+	BucketCount int
+
+	// LeaseDuration is the duration after its last renewal that the Lease of a replica is considered expired.
+	LeaseDuration time.Duration
+	// RenewDeadline is the duration after its last successful renewal that a replica releases all its buckets.
+	RenewDeadline time.Duration
+	// RetryPeriod is the interval between renewals of the Lease of the replica.
+	RetryPeriod time.Duration
+}
+
+// Coordinator assigns buckets to the replicas of a group. Each replica maintains its own Lease, and the buckets are
+// distributed among the replicas with unexpired Leases. Buckets are released as soon as the replica observes that they
+// were assigned to another replica, but are only acquired after the assignment has been stable for a lease duration,
+// so that the previous owner has stopped processing them.
+type Coordinator struct {
+	client    coordinationv1client.LeasesGetter
+	config    CoordinatorConfig
+	leaseName string
+	clock     clock.Clock
+
+	lastRenewTime time.Time
+	desired       sets.Set[int]
+	desiredSince  time.Time
+	current       *Shard
+}
+
+// NewCoordinator returns a coordinator for a new member of the group.
+func NewCoordinator(client coordinationv1client.LeasesGetter, config CoordinatorConfig) (*Coordinator, error) {
+	if config.BucketCount <= 0 {
+		return nil, fmt.Errorf("bucket count must be positive")
+	}
+	if config.RenewDeadline >= config.LeaseDuration {
+		return nil, fmt.Errorf("renew deadline must be less than lease duration")
+	}
+	if config.RetryPeriod >= config.RenewDeadline {
+		return nil, fmt.Errorf("retry period must be less than renew deadline")
+	}
+
+	return &Coordinator{
+		client:    client,
+		config:    config,
+		leaseName: fmt.Sprintf("%s-%s", config.Group, uuid.NewUUID()),
+		clock:     clock.RealClock{},
+		desired:   sets.New[int](),
+		current:   NewShard(config.BucketCount, sets.New[int]()),
+	}, nil
+}
+
+// Run maintains the Lease of the replica until ctx is done, and calls run with the shard owned by the replica whenever
+// it changes. The context passed to run is cancelled before the shard changes again, and Run waits for run to return
+// before calling it with the next shard. run is not called while the replica owns no buckets.
+func (c *Coordinator) Run(ctx context.Context, run func(ctx context.Context, shard *Shard)) {
+	logger := klog.FromContext(ctx).WithValues("lease", c.leaseName)
+	logger.Info("Starting shard coordinator")
+	defer logger.Info("Stopping shard coordinator")
+
+	stop := func() {}
+	defer func() { stop() }()
+
+	for {
+		if shard := c.sync(ctx); !shard.Equal(c.current) {
+			logger.Info("Shard changed", "previous", c.current.String(), "current", shard.String())
+			stop()
+			stop = func() {}
+			c.current = shard
+
+			if !shard.IsEmpty() {
+				stop = startShard(ctx, shard, run)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			stop()
+			stop = func() {}
+			c.release()
+			return
+		case <-c.clock.After(c.config.RetryPeriod):
+		}
+	}
+}
+
+// startShard calls run in a new goroutine and returns a function that stops it.
+func startShard(ctx context.Context, shard *Shard, run func(ctx context.Context, shard *Shard)) (stop func()) {
+	shardCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		run(shardCtx, shard)
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// sync renews the Lease of the replica and returns the shard that the replica should own.
+func (c *Coordinator) sync(ctx context.Context) *Shard {
+	logger := klog.FromContext(ctx)
+	now := c.clock.Now()
+
+	if err := c.renew(ctx, now); err != nil {
+		logger.Error(err, "Failed to renew shard lease")
+		if now.Sub(c.lastRenewTime) >= c.config.RenewDeadline {
+			// Other replicas will take over our buckets once our lease expires, so we have to wait for them to
+			// release the buckets again after we renew our lease.
+			c.desired = sets.New[int]()
+			return NewShard(c.config.BucketCount, sets.New[int]())
+		}
+		return c.current
+	}
+	c.lastRenewTime = now
+
+	members, err := c.listMembers(ctx, now)
+	if err != nil {
+		logger.Error(err, "Failed to list shard leases")
+		return c.current
+	}
+
+	desired := AssignBuckets(members, c.config.BucketCount)[c.leaseName]
+	if !desired.Equal(c.desired) {
+		c.desired = desired
+		c.desiredSince = now
+	}
+
+	if now.Sub(c.desiredSince) >= c.config.LeaseDuration {
+		return NewShard(c.config.BucketCount, desired)
+	}
+	return NewShard(c.config.BucketCount, c.current.buckets.Intersection(desired))
+}
+
+func (c *Coordinator) renew(ctx context.Context, now time.Time) error {
+	renewTime := metav1.NewMicroTime(now)
+	leases := c.client.Leases(c.config.Namespace)
+
+	lease, err := leases.Get(ctx, c.leaseName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      c.leaseName,
+				Namespace: c.config.Namespace,
+				Labels:    map[string]string{common.ShardGroupLabel: c.config.Group},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       pointer.String(c.config.Identity),
+				LeaseDurationSeconds: pointer.Int32(int32(c.config.LeaseDuration / time.Second)),
+				AcquireTime:          &renewTime,
+				RenewTime:            &renewTime,
+			},
+		}
+		_, err = leases.Create(ctx, lease, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	lease.Spec.RenewTime = &renewTime
+	_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+// listMembers returns the names of the unexpired Leases of the group. Leases that have been expired for longer than a
+// lease duration belong to replicas that are gone, since every replica creates a new Lease when it starts, and are
+// deleted.
+func (c *Coordinator) listMembers(ctx context.Context, now time.Time) ([]string, error) {
+	leases := c.client.Leases(c.config.Namespace)
+	leaseList, err := leases.List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{common.ShardGroupLabel: c.config.Group}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	members := []string{c.leaseName}
+	for i := range leaseList.Items {
+		lease := &leaseList.Items[i]
+		if lease.Name == c.leaseName || lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+			continue
+		}
+		expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+		if now.Before(expiry) {
+			members = append(members, lease.Name)
+			continue
+		}
+
+		if now.Sub(expiry) >= c.config.LeaseDuration {
+			// The precondition prevents deleting a Lease that was renewed after it was listed.
+			err := leases.Delete(ctx, lease.Name, metav1.DeleteOptions{
+				Preconditions: &metav1.Preconditions{ResourceVersion: &lease.ResourceVersion},
+			})
+			if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+				klog.FromContext(ctx).Error(err, "Failed to delete expired shard lease", "lease", lease.Name)
+			}
+		}
+	}
+	return members, nil
+}
+
+// release deletes the Lease of the replica so that other replicas take over its buckets without waiting for the Lease
+// to expire.
+func (c *Coordinator) release() {
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.RetryPeriod)
+	defer cancel()
+
+	err := c.client.Leases(c.config.Namespace).Delete(ctx, c.leaseName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		klog.Errorf("Failed to delete shard lease %s: %v", c.leaseName, err)
+	}
+}
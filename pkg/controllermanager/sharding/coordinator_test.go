@@ -0,0 +1,208 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+const testBucketCount = 32
+
+func newTestCoordinator(
+	g *gomega.WithT,
+	client *fake.Clientset,
+	clock *clocktesting.FakeClock,
+	identity string,
+) *Coordinator {
+	coordinator, err := NewCoordinator(client.CoordinationV1(), CoordinatorConfig{
+		Namespace:     "kube-admiral-system",
+		Group:         "controller-manager",
+		Identity:      identity,
+		BucketCount:   testBucketCount,
+		LeaseDuration: 15 * time.Second,
+		RenewDeadline: 10 * time.Second,
+		RetryPeriod:   5 * time.Second,
+	})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	coordinator.clock = clock
+	return coordinator
+}
+
+func syncShard(c *Coordinator) *Shard {
+	c.current = c.sync(context.Background())
+	return c.current
+}
+
+func TestCoordinatorRebalance(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctx := context.Background()
+
+	client := fake.NewSimpleClientset()
+	clock := clocktesting.NewFakeClock(time.Now())
+	a := newTestCoordinator(g, client, clock, "a")
+	b := newTestCoordinator(g, client, clock, "b")
+
+	// A new replica waits for a lease duration before acquiring buckets.
+	g.Expect(syncShard(a).IsEmpty()).To(gomega.BeTrue())
+	clock.Step(15 * time.Second)
+	g.Expect(syncShard(a).Buckets()).To(gomega.HaveLen(testBucketCount))
+
+	leases, err := client.CoordinationV1().Leases("kube-admiral-system").List(ctx, metav1.ListOptions{})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(leases.Items).To(gomega.HaveLen(1))
+	g.Expect(*leases.Items[0].Spec.HolderIdentity).To(gomega.Equal("a"))
+
+	// When a replica joins, the existing replica releases buckets immediately and the new replica acquires them
+	// after a lease duration.
+	g.Expect(syncShard(b).IsEmpty()).To(gomega.BeTrue())
+	clock.Step(5 * time.Second)
+	shardA := syncShard(a)
+	g.Expect(shardA.IsEmpty()).To(gomega.BeFalse())
+	g.Expect(shardA.Buckets()).ToNot(gomega.HaveLen(testBucketCount))
+	g.Expect(syncShard(b).IsEmpty()).To(gomega.BeTrue())
+
+	clock.Step(10 * time.Second)
+	syncShard(a)
+	shardB := syncShard(b)
+	g.Expect(shardA.Equal(a.current)).To(gomega.BeTrue())
+	g.Expect(sets.New(shardA.Buckets()...).Intersection(sets.New(shardB.Buckets()...)).Len()).To(gomega.BeZero())
+	g.Expect(len(shardA.Buckets()) + len(shardB.Buckets())).To(gomega.Equal(testBucketCount))
+
+	// When a replica leaves, its buckets are acquired by the remaining replica after a lease duration.
+	b.release()
+	syncShard(a)
+	g.Expect(a.current.Equal(shardA)).To(gomega.BeTrue())
+	clock.Step(15 * time.Second)
+	g.Expect(syncShard(a).Buckets()).To(gomega.HaveLen(testBucketCount))
+}
+
+func TestCoordinatorExpiredLease(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	client := fake.NewSimpleClientset()
+	clock := clocktesting.NewFakeClock(time.Now())
+	a := newTestCoordinator(g, client, clock, "a")
+	b := newTestCoordinator(g, client, clock, "b")
+
+	syncShard(a)
+	syncShard(b)
+	clock.Step(15 * time.Second)
+	syncShard(a)
+	syncShard(b)
+	g.Expect(a.current.IsEmpty()).To(gomega.BeFalse())
+	g.Expect(b.current.IsEmpty()).To(gomega.BeFalse())
+
+	// b stops renewing its lease, so a treats it as gone once the lease has expired.
+	clock.Step(10 * time.Second)
+	syncShard(a)
+	g.Expect(a.desired.Len()).ToNot(gomega.Equal(testBucketCount))
+	clock.Step(5 * time.Second)
+	syncShard(a)
+	g.Expect(a.desired.Len()).To(gomega.Equal(testBucketCount))
+	leases, err := client.CoordinationV1().Leases("kube-admiral-system").List(context.Background(), metav1.ListOptions{})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(leases.Items).To(gomega.HaveLen(2))
+
+	// The lease of b is deleted once it has been expired for a lease duration.
+	clock.Step(15 * time.Second)
+	g.Expect(syncShard(a).Buckets()).To(gomega.HaveLen(testBucketCount))
+
+	leases, err = client.CoordinationV1().Leases("kube-admiral-system").List(context.Background(), metav1.ListOptions{})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(leases.Items).To(gomega.HaveLen(1))
+	g.Expect(leases.Items[0].Name).To(gomega.Equal(a.leaseName))
+}
+
+func TestCoordinatorRenewFailure(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	client := fake.NewSimpleClientset()
+	clock := clocktesting.NewFakeClock(time.Now())
+	a := newTestCoordinator(g, client, clock, "a")
+
+	syncShard(a)
+	clock.Step(15 * time.Second)
+	g.Expect(syncShard(a).Buckets()).To(gomega.HaveLen(testBucketCount))
+
+	failing := true
+	client.PrependReactor("update", "leases", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if failing {
+			return true, nil, errors.New("connection refused")
+		}
+		return false, nil, nil
+	})
+
+	// Buckets are kept until the renew deadline has passed.
+	clock.Step(5 * time.Second)
+	g.Expect(syncShard(a).Buckets()).To(gomega.HaveLen(testBucketCount))
+	clock.Step(5 * time.Second)
+	g.Expect(syncShard(a).IsEmpty()).To(gomega.BeTrue())
+
+	// After renewing again, buckets are only acquired after a lease duration since other replicas may have taken
+	// them over in the meantime.
+	failing = false
+	clock.Step(5 * time.Second)
+	g.Expect(syncShard(a).IsEmpty()).To(gomega.BeTrue())
+	clock.Step(15 * time.Second)
+	g.Expect(syncShard(a).Buckets()).To(gomega.HaveLen(testBucketCount))
+}
+
+func TestCoordinatorRun(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	client := fake.NewSimpleClientset()
+	clock := clocktesting.NewFakeClock(time.Now())
+	a := newTestCoordinator(g, client, clock, "a")
+	a.config.LeaseDuration = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	shards := make(chan *Shard, 1)
+	stopped := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.Run(ctx, func(ctx context.Context, shard *Shard) {
+			shards <- shard
+			<-ctx.Done()
+			close(stopped)
+		})
+	}()
+
+	g.Eventually(shards).Should(gomega.Receive(gomega.WithTransform(
+		func(shard *Shard) int { return len(shard.Buckets()) },
+		gomega.Equal(testBucketCount),
+	)))
+
+	cancel()
+	g.Eventually(stopped).Should(gomega.BeClosed())
+	g.Eventually(done).Should(gomega.BeClosed())
+
+	leases, err := client.CoordinationV1().Leases("kube-admiral-system").List(context.Background(), metav1.ListOptions{})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(leases.Items).To(gomega.BeEmpty())
+}
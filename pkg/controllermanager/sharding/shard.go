@@ -0,0 +1,157 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+// Bucket returns the hash bucket of the given key among bucketCount buckets.
+func Bucket(qualifiedName common.QualifiedName, bucketCount int) int {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(qualifiedName.String()))
+	return int(hash.Sum32() % uint32(bucketCount))
+}
+
+// Shard is the set of hash buckets of federated object keys owned by a controller manager replica. A nil Shard owns
+// all keys, which is the case if sharding is disabled. Shards are immutable.
+type Shard struct {
+	bucketCount int
+	buckets     sets.Set[int]
+}
+
+// NewShard returns a shard that owns the given buckets among bucketCount buckets.
+func NewShard(bucketCount int, buckets sets.Set[int]) *Shard {
+	return &Shard{
+		bucketCount: bucketCount,
+		buckets:     buckets.Clone(),
+	}
+}
+
+// BucketCount returns the total number of buckets.
+func (s *Shard) BucketCount() int {
+	return s.bucketCount
+}
+
+// Buckets returns the sorted buckets owned by the shard.
+func (s *Shard) Buckets() []int {
+	buckets := s.buckets.UnsortedList()
+	sort.Ints(buckets)
+	return buckets
+}
+
+// IsEmpty returns true if the shard owns no buckets.
+func (s *Shard) IsEmpty() bool {
+	return s != nil && s.buckets.Len() == 0
+}
+
+// Equal returns true if both shards own the same buckets.
+func (s *Shard) Equal(other *Shard) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+	return s.bucketCount == other.bucketCount && s.buckets.Equal(other.buckets)
+}
+
+// Owns returns true if the shard owns the given key.
+func (s *Shard) Owns(qualifiedName common.QualifiedName) bool {
+	if s == nil {
+		return true
+	}
+	return s.buckets.Has(Bucket(qualifiedName, s.bucketCount))
+}
+
+// LabelSelector returns the label selector that matches the federated objects owned by the shard.
+func (s *Shard) LabelSelector() string {
+	if s == nil {
+		return ""
+	}
+	return bucketSelector(selection.In, s.Buckets())
+}
+
+// UnassignedLabelSelector returns the label selector that matches the federated objects that are not in any bucket,
+// i.e. objects without a bucket label and objects whose bucket label is not less than the number of buckets, e.g.
+// because the number of buckets was lowered.
+func (s *Shard) UnassignedLabelSelector() string {
+	if s == nil {
+		return ""
+	}
+
+	buckets := make([]int, s.bucketCount)
+	for i := range buckets {
+		buckets[i] = i
+	}
+	return bucketSelector(selection.NotIn, buckets)
+}
+
+func bucketSelector(operator selection.Operator, buckets []int) string {
+	values := make([]string, 0, len(buckets))
+	for _, bucket := range buckets {
+		values = append(values, strconv.Itoa(bucket))
+	}
+	return fmt.Sprintf("%s %s (%s)", common.ShardBucketLabel, operator, strings.Join(values, ","))
+}
+
+// TweakListOptions restricts list and watch requests to the federated objects owned by the shard. It is meant to be
+// passed to informer factories.
+func (s *Shard) TweakListOptions(options *metav1.ListOptions) {
+	selector := s.LabelSelector()
+	if selector == "" {
+		return
+	}
+	if options.LabelSelector != "" {
+		selector = options.LabelSelector + "," + selector
+	}
+	options.LabelSelector = selector
+}
+
+// EnsureBucketLabel sets the bucket label of a federated object and returns true if the label was changed. It is a
+// no-op for a nil Shard.
+func (s *Shard) EnsureBucketLabel(obj metav1.Object) bool {
+	if s == nil {
+		return false
+	}
+
+	value := strconv.Itoa(Bucket(common.QualifiedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}, s.bucketCount))
+	labels := obj.GetLabels()
+	if labels[common.ShardBucketLabel] == value {
+		return false
+	}
+	if labels == nil {
+		labels = make(map[string]string, 1)
+	}
+	labels[common.ShardBucketLabel] = value
+	obj.SetLabels(labels)
+	return true
+}
+
+func (s *Shard) String() string {
+	if s == nil {
+		return "all"
+	}
+	return fmt.Sprintf("%v/%d", s.Buckets(), s.bucketCount)
+}
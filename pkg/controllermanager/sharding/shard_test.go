@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+func TestShardOwns(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	const bucketCount = 8
+	even := NewShard(bucketCount, sets.New(0, 2, 4, 6))
+	odd := NewShard(bucketCount, sets.New(1, 3, 5, 7))
+
+	for i := 0; i < 100; i++ {
+		key := common.QualifiedName{Namespace: "default", Name: string(rune('a'+i%26)) + string(rune('0'+i/26))}
+		g.Expect(even.Owns(key)).ToNot(gomega.Equal(odd.Owns(key)), "key %s must be owned by exactly one shard", key)
+		g.Expect(Bucket(key, bucketCount)).To(gomega.Equal(Bucket(key, bucketCount)))
+	}
+
+	var all *Shard
+	g.Expect(all.Owns(common.QualifiedName{Name: "foo"})).To(gomega.BeTrue())
+	g.Expect(all.LabelSelector()).To(gomega.BeEmpty())
+}
+
+func TestShardLabelSelector(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	const bucketCount = 16
+	shard := NewShard(bucketCount, sets.New(10, 2, 7))
+	g.Expect(shard.LabelSelector()).To(gomega.Equal(common.ShardBucketLabel + " in (2,7,10)"))
+
+	selector, err := labels.Parse(shard.LabelSelector())
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+
+	for i := 0; i < 200; i++ {
+		obj := &unstructured.Unstructured{}
+		obj.SetNamespace("ns")
+		obj.SetName(string(rune('a'+i%26)) + string(rune('a'+i/26)))
+		g.Expect(shard.EnsureBucketLabel(obj)).To(gomega.BeTrue())
+		g.Expect(shard.EnsureBucketLabel(obj)).To(gomega.BeFalse())
+
+		key := common.QualifiedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+		g.Expect(selector.Matches(labels.Set(obj.GetLabels()))).To(gomega.Equal(shard.Owns(key)))
+	}
+
+	options := metav1.ListOptions{LabelSelector: "foo=bar"}
+	shard.TweakListOptions(&options)
+	g.Expect(options.LabelSelector).To(gomega.Equal("foo=bar," + common.ShardBucketLabel + " in (2,7,10)"))
+}
+
+func TestShardUnassignedLabelSelector(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	shard := NewShard(4, sets.New(1))
+	g.Expect(shard.UnassignedLabelSelector()).To(gomega.Equal(common.ShardBucketLabel + " notin (0,1,2,3)"))
+
+	selector, err := labels.Parse(shard.UnassignedLabelSelector())
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(selector.Matches(labels.Set{})).To(gomega.BeTrue())
+	g.Expect(selector.Matches(labels.Set{common.ShardBucketLabel: "4"})).To(gomega.BeTrue())
+	g.Expect(selector.Matches(labels.Set{common.ShardBucketLabel: "3"})).To(gomega.BeFalse())
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tuning
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/apis/config"
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/apis/config/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/worker"
+)
+
+// Defaults contains the settings that apply if they are not set in the ControllerTuningConfiguration.
+type Defaults struct {
+	WorkerCount        int32
+	ClusterClientQPS   float32
+	ClusterClientBurst int32
+}
+
+// Provider resolves the settings of controllers from a ControllerTuningConfiguration that may be reloaded at runtime.
+type Provider struct {
+	defaults Defaults
+	names    Names
+
+	lock   sync.RWMutex
+	config *config.ControllerTuningConfiguration
+	// data is the content of the file that config was loaded from.
+	data []byte
+	// changed is closed and replaced whenever config is updated.
+	changed chan struct{}
+}
+
+// NewProvider returns a Provider with an empty ControllerTuningConfiguration. Configurations loaded by the Provider may
+// only use the given names.
+func NewProvider(defaults Defaults, names Names) *Provider {
+	return &Provider{
+		defaults: defaults,
+		names:    names,
+		config:   &config.ControllerTuningConfiguration{},
+		changed:  make(chan struct{}),
+	}
+}
+
+// Parse parses, defaults and validates a ControllerTuningConfiguration in the v1alpha1 format in YAML or JSON, which is
+// the format of the controllerTuning field of the component configuration.
+func Parse(data []byte, names Names) (*config.ControllerTuningConfiguration, error) {
+	versioned := &v1alpha1.ControllerTuningConfiguration{}
+	if err := yaml.UnmarshalStrict(data, versioned); err != nil {
+		return nil, fmt.Errorf("failed to parse controller configuration: %w", err)
+	}
+	v1alpha1.SetDefaults_ControllerTuningConfiguration(versioned)
+
+	cfg := &config.ControllerTuningConfiguration{}
+	if err := v1alpha1.Convert_v1alpha1_ControllerTuningConfiguration_To_config_ControllerTuningConfiguration(
+		versioned,
+		cfg,
+		nil,
+	); err != nil {
+		return nil, fmt.Errorf("failed to convert controller configuration: %w", err)
+	}
+	if err := Validate(cfg, names); err != nil {
+		return nil, fmt.Errorf("invalid controller configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// LoadFile replaces the ControllerTuningConfiguration with the one in the file at path.
+func (p *Provider) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read controller configuration: %w", err)
+	}
+
+	p.lock.RLock()
+	unchanged := p.data != nil && bytes.Equal(data, p.data)
+	p.lock.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	cfg, err := Parse(data, p.names)
+	if err != nil {
+		return err
+	}
+
+	p.lock.Lock()
+	p.data = data
+	p.lock.Unlock()
+	p.Update(cfg)
+	return nil
+}
+
+// Load validates the given ControllerTuningConfiguration and replaces the current one with it.
+func (p *Provider) Load(cfg *config.ControllerTuningConfiguration) error {
+	if err := Validate(cfg, p.names); err != nil {
+		return fmt.Errorf("invalid controller configuration: %w", err)
+	}
+	p.Update(cfg)
+	return nil
+}
+
+// Watch reloads the ControllerTuningConfiguration from the file at path every period until ctx is done. If the file
+// cannot be loaded, the error is logged and the previous configuration is kept.
+func (p *Provider) Watch(ctx context.Context, path string, period time.Duration) {
+	logger := klog.FromContext(ctx).WithValues("path", path)
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := p.LoadFile(path); err != nil {
+			logger.Error(err, "Failed to reload controller configuration")
+		}
+	}, period)
+}
+
+// Update replaces the ControllerTuningConfiguration. The cfg must be valid.
+func (p *Provider) Update(cfg *config.ControllerTuningConfiguration) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.config = cfg
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
+
+// Resolve returns the merged settings of the named controller for the named FederatedTypeConfig, which is empty for
+// controllers that do not run for each FederatedTypeConfig.
+func (p *Provider) Resolve(controller, ftc string) config.ControllerTuning {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	defaults := p.defaults
+	resolved := config.ControllerTuning{
+		WorkerCount: &defaults.WorkerCount,
+		ClusterClient: &config.ClusterClientConfiguration{
+			QPS:   &defaults.ClusterClientQPS,
+			Burst: &defaults.ClusterClientBurst,
+		},
+	}
+	resolved = merge(resolved, p.config.Defaults)
+	resolved = merge(resolved, p.config.Controllers[controller])
+	if ftc != "" {
+		resolved = merge(resolved, p.config.FederatedTypeConfigs[ftc][controller])
+	}
+	return resolved
+}
+
+// Worker returns the worker config of the named controller for the named FederatedTypeConfig, which reflects changes of
+// the ControllerTuningConfiguration.
+func (p *Provider) Worker(controller, ftc string) worker.ConfigSource {
+	return &workerConfigSource{provider: p, controller: controller, ftc: ftc}
+}
+
+// ClusterClient returns the QPS and burst of the clients of the named controller for member clusters. Changes of the
+// ControllerTuningConfiguration only apply to clients created afterwards.
+func (p *Provider) ClusterClient(controller, ftc string) (qps float32, burst int) {
+	client := p.Resolve(controller, ftc).ClusterClient
+	return *client.QPS, int(*client.Burst)
+}
+
+func (p *Provider) changedChan() <-chan struct{} {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.changed
+}
+
+type workerConfigSource struct {
+	provider   *Provider
+	controller string
+	ftc        string
+}
+
+func (s *workerConfigSource) Config() worker.Config {
+	resolved := s.provider.Resolve(s.controller, s.ftc)
+
+	cfg := worker.Config{WorkerCount: int(*resolved.WorkerCount)}
+	if rateLimiter := resolved.RateLimiter; rateLimiter != nil {
+		if rateLimiter.QPS != nil {
+			cfg.QPS = *rateLimiter.QPS
+		}
+		if rateLimiter.Burst != nil {
+			cfg.Burst = int(*rateLimiter.Burst)
+		}
+		if rateLimiter.InitialBackoff != nil {
+			cfg.InitialBackoff = rateLimiter.InitialBackoff.Duration
+		}
+		if rateLimiter.MaxBackoff != nil {
+			cfg.MaxBackoff = rateLimiter.MaxBackoff.Duration
+		}
+	}
+	return cfg
+}
+
+func (s *workerConfigSource) Changed() <-chan struct{} {
+	return s.provider.changedChan()
+}
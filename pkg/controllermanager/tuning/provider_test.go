@@ -0,0 +1,256 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tuning
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/pointer"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/apis/config"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/worker"
+)
+
+var testDefaults = Defaults{WorkerCount: 1, ClusterClientQPS: 500, ClusterClientBurst: 1000}
+
+var testNames = Names{
+	Controllers:                    sets.New("cluster"),
+	FederatedTypeConfigControllers: sets.New("scheduler", "status", "sync"),
+	FederatedTypeConfigs: func() (sets.Set[string], error) {
+		return sets.New("deployments.apps", "secrets"), nil
+	},
+}
+
+const testConfig = `
+defaults:
+  workerCount: 2
+  rateLimiter:
+    maxBackoff: 2m
+controllers:
+  sync:
+    workerCount: 8
+    rateLimiter:
+      qps: 50
+      burst: 100
+    clusterClient:
+      qps: 20
+  scheduler:
+    workerCount: 4
+federatedTypeConfigs:
+  deployments.apps:
+    sync:
+      workerCount: 16
+      rateLimiter:
+        initialBackoff: 1s
+      clusterClient:
+        burst: 40
+`
+
+func TestResolve(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	cfg, err := Parse([]byte(testConfig), testNames)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	p := NewProvider(testDefaults, testNames)
+	p.Update(cfg)
+
+	tests := map[string]struct {
+		controller    string
+		ftc           string
+		expected      worker.Config
+		expectedQPS   float32
+		expectedBurst int
+	}{
+		"controller without settings uses the defaults": {
+			controller:    "cluster",
+			expected:      worker.Config{WorkerCount: 2, MaxBackoff: 2 * time.Minute},
+			expectedQPS:   500,
+			expectedBurst: 1000,
+		},
+		"controller settings override the defaults": {
+			controller:    "sync",
+			ftc:           "secrets",
+			expected:      worker.Config{WorkerCount: 8, QPS: 50, Burst: 100, MaxBackoff: 2 * time.Minute},
+			expectedQPS:   20,
+			expectedBurst: 1000,
+		},
+		"ftc settings override the controller settings": {
+			controller: "sync",
+			ftc:        "deployments.apps",
+			expected: worker.Config{
+				WorkerCount:    16,
+				QPS:            50,
+				Burst:          100,
+				InitialBackoff: time.Second,
+				MaxBackoff:     2 * time.Minute,
+			},
+			expectedQPS:   20,
+			expectedBurst: 40,
+		},
+		"ftc settings of other controllers do not apply": {
+			controller:    "scheduler",
+			ftc:           "deployments.apps",
+			expected:      worker.Config{WorkerCount: 4, MaxBackoff: 2 * time.Minute},
+			expectedQPS:   500,
+			expectedBurst: 1000,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			g.Expect(p.Worker(test.controller, test.ftc).Config()).To(gomega.Equal(test.expected))
+
+			qps, burst := p.ClusterClient(test.controller, test.ftc)
+			g.Expect(qps).To(gomega.Equal(test.expectedQPS))
+			g.Expect(burst).To(gomega.Equal(test.expectedBurst))
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		config        string
+		expectedError string
+	}{
+		"empty config is valid": {
+			config: "",
+		},
+		"unknown fields are rejected": {
+			config:        "controllers: {sync: {workers: 2}}",
+			expectedError: "unknown field",
+		},
+		"worker count must be positive": {
+			config:        "controllers: {sync: {workerCount: 0}}",
+			expectedError: "controllers[sync]: workerCount must be positive",
+		},
+		"rate limiter qps must not be negative": {
+			config:        "defaults: {rateLimiter: {qps: -1}}",
+			expectedError: "defaults: rateLimiter.qps must not be negative",
+		},
+		"initial backoff must not exceed max backoff": {
+			config:        "federatedTypeConfigs: {secrets: {sync: {rateLimiter: {initialBackoff: 2m, maxBackoff: 1m}}}}",
+			expectedError: "federatedTypeConfigs[secrets][sync]: rateLimiter.initialBackoff must not exceed",
+		},
+		"resolved initial backoff must not exceed resolved max backoff": {
+			config:        "defaults: {rateLimiter: {maxBackoff: 1m}}\ncontrollers: {sync: {rateLimiter: {initialBackoff: 2m}}}",
+			expectedError: "controllers[sync]: rateLimiter.initialBackoff must not exceed rateLimiter.maxBackoff (2m0s > 1m0s)",
+		},
+		"resolved backoffs of ftc settings are validated": {
+			config: "controllers: {sync: {rateLimiter: {maxBackoff: 1m}}}\n" +
+				"federatedTypeConfigs: {secrets: {sync: {rateLimiter: {initialBackoff: 2m}}}}",
+			expectedError: "federatedTypeConfigs[secrets][sync]: rateLimiter.initialBackoff must not exceed",
+		},
+		"unknown controllers are rejected": {
+			config:        "controllers: {synk: {workerCount: 2}}",
+			expectedError: "controllers[synk]: unknown controller",
+		},
+		"unknown ftcs are rejected": {
+			config:        "federatedTypeConfigs: {deployment.apps: {sync: {workerCount: 2}}}",
+			expectedError: "federatedTypeConfigs[deployment.apps]: unknown FederatedTypeConfig",
+		},
+		"controllers that do not run for each ftc are rejected in ftc settings": {
+			config:        "federatedTypeConfigs: {secrets: {cluster: {workerCount: 2}}}",
+			expectedError: "federatedTypeConfigs[secrets][cluster]: unknown controller",
+		},
+		"cluster client qps must be positive": {
+			config:        "controllers: {status: {clusterClient: {qps: 0}}}",
+			expectedError: "controllers[status]: clusterClient.qps must be positive",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			_, err := Parse([]byte(test.config), testNames)
+			if test.expectedError == "" {
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+			} else {
+				g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring(test.expectedError)))
+			}
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	g.Expect(os.WriteFile(path, []byte("controllers: {sync: {workerCount: 3}}"), 0o600)).To(gomega.Succeed())
+
+	p := NewProvider(testDefaults, testNames)
+	source := p.Worker("sync", "")
+	g.Expect(source.Config().WorkerCount).To(gomega.Equal(1))
+
+	changed := source.Changed()
+	g.Expect(p.LoadFile(path)).To(gomega.Succeed())
+	g.Expect(changed).To(gomega.BeClosed())
+	g.Expect(source.Config().WorkerCount).To(gomega.Equal(3))
+
+	// reloading an unchanged file does not notify workers
+	changed = source.Changed()
+	g.Expect(p.LoadFile(path)).To(gomega.Succeed())
+	g.Expect(changed).NotTo(gomega.BeClosed())
+
+	// an invalid file keeps the previous configuration
+	g.Expect(os.WriteFile(path, []byte("controllers: {sync: {workerCount: -1}}"), 0o600)).To(gomega.Succeed())
+	g.Expect(p.LoadFile(path)).NotTo(gomega.Succeed())
+	g.Expect(changed).NotTo(gomega.BeClosed())
+	g.Expect(source.Config().WorkerCount).To(gomega.Equal(3))
+}
+
+func TestLoad(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	p := NewProvider(testDefaults, testNames)
+	g.Expect(p.Load(&config.ControllerTuningConfiguration{
+		Defaults: config.ControllerTuning{
+			RateLimiter: &config.RateLimiterConfiguration{MaxBackoff: &metav1.Duration{Duration: 2 * time.Minute}},
+		},
+		FederatedTypeConfigs: map[string]map[string]config.ControllerTuning{
+			"deployments.apps": {"sync": {WorkerCount: pointer.Int32(16)}},
+		},
+	})).To(gomega.Succeed())
+	g.Expect(p.Worker("sync", "deployments.apps").Config().WorkerCount).To(gomega.Equal(16))
+	g.Expect(p.Worker("sync", "secrets").Config().WorkerCount).To(gomega.Equal(1))
+	g.Expect(p.Worker("sync", "secrets").Config().MaxBackoff).To(gomega.Equal(2 * time.Minute))
+
+	// names are validated when the configuration is loaded
+	g.Expect(p.Load(&config.ControllerTuningConfiguration{
+		Controllers: map[string]config.ControllerTuning{"unknown": {WorkerCount: pointer.Int32(2)}},
+	})).NotTo(gomega.Succeed())
+	g.Expect(p.Worker("sync", "deployments.apps").Config().WorkerCount).To(gomega.Equal(16))
+}
+
+func TestParseDefaultsBurst(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	cfg, err := Parse([]byte("defaults: {rateLimiter: {qps: 10}}\ncontrollers: {sync: {rateLimiter: {qps: 20}}}"), testNames)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	p := NewProvider(testDefaults, testNames)
+	p.Update(cfg)
+	g.Expect(p.Worker("sync", "").Config()).To(gomega.Equal(worker.Config{WorkerCount: 1, QPS: 20, Burst: 1}))
+}
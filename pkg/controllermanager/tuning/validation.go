@@ -0,0 +1,192 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tuning
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/apis/config"
+)
+
+// Names contains the names that may be used in a ControllerTuningConfiguration.
+type Names struct {
+	// Controllers are the names of the controllers that do not run for each FederatedTypeConfig.
+	Controllers sets.Set[string]
+	// FederatedTypeConfigControllers are the names of the controllers that run for each FederatedTypeConfig.
+	FederatedTypeConfigControllers sets.Set[string]
+	// FederatedTypeConfigs returns the names of the existing FederatedTypeConfigs.
+	FederatedTypeConfigs func() (sets.Set[string], error)
+}
+
+// Validate returns an error if the configuration is invalid or uses names that are not in names.
+func Validate(cfg *config.ControllerTuningConfiguration, names Names) error {
+	if err := ValidateSettings(cfg); err != nil {
+		return err
+	}
+
+	for name := range cfg.Controllers {
+		if !names.Controllers.Has(name) && !names.FederatedTypeConfigControllers.Has(name) {
+			return fmt.Errorf("controllers[%s]: unknown controller", name)
+		}
+	}
+
+	if len(cfg.FederatedTypeConfigs) == 0 {
+		return nil
+	}
+	ftcNames, err := names.FederatedTypeConfigs()
+	if err != nil {
+		return fmt.Errorf("failed to list FederatedTypeConfigs: %w", err)
+	}
+	for ftcName, controllers := range cfg.FederatedTypeConfigs {
+		if !ftcNames.Has(ftcName) {
+			return fmt.Errorf("federatedTypeConfigs[%s]: unknown FederatedTypeConfig", ftcName)
+		}
+		for name := range controllers {
+			if !names.FederatedTypeConfigControllers.Has(name) {
+				return fmt.Errorf("federatedTypeConfigs[%s][%s]: unknown controller for FederatedTypeConfigs", ftcName, name)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateSettings returns an error if the settings of the configuration are invalid. Unlike Validate, it does not
+// check the names of controllers and FederatedTypeConfigs.
+func ValidateSettings(cfg *config.ControllerTuningConfiguration) error {
+	if err := validateController(cfg.Defaults); err != nil {
+		return fmt.Errorf("defaults: %w", err)
+	}
+	if err := validateBackoff(cfg.Defaults); err != nil {
+		return fmt.Errorf("defaults: %w", err)
+	}
+
+	for name, controller := range cfg.Controllers {
+		if err := validateController(controller); err != nil {
+			return fmt.Errorf("controllers[%s]: %w", name, err)
+		}
+		if err := validateBackoff(merge(cfg.Defaults, controller)); err != nil {
+			return fmt.Errorf("controllers[%s]: %w", name, err)
+		}
+	}
+
+	for ftcName, controllers := range cfg.FederatedTypeConfigs {
+		for name, controller := range controllers {
+			if err := validateController(controller); err != nil {
+				return fmt.Errorf("federatedTypeConfigs[%s][%s]: %w", ftcName, name, err)
+			}
+			resolved := merge(merge(cfg.Defaults, cfg.Controllers[name]), controller)
+			if err := validateBackoff(resolved); err != nil {
+				return fmt.Errorf("federatedTypeConfigs[%s][%s]: %w", ftcName, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateController(c config.ControllerTuning) error {
+	if c.WorkerCount != nil && *c.WorkerCount < 1 {
+		return fmt.Errorf("workerCount must be positive")
+	}
+
+	if rateLimiter := c.RateLimiter; rateLimiter != nil {
+		if rateLimiter.QPS != nil && *rateLimiter.QPS < 0 {
+			return fmt.Errorf("rateLimiter.qps must not be negative")
+		}
+		if rateLimiter.Burst != nil && *rateLimiter.Burst < 1 {
+			return fmt.Errorf("rateLimiter.burst must be positive")
+		}
+		if rateLimiter.InitialBackoff != nil && rateLimiter.InitialBackoff.Duration <= 0 {
+			return fmt.Errorf("rateLimiter.initialBackoff must be positive")
+		}
+		if rateLimiter.MaxBackoff != nil && rateLimiter.MaxBackoff.Duration <= 0 {
+			return fmt.Errorf("rateLimiter.maxBackoff must be positive")
+		}
+	}
+
+	if client := c.ClusterClient; client != nil {
+		if client.QPS != nil && *client.QPS <= 0 {
+			return fmt.Errorf("clusterClient.qps must be positive")
+		}
+		if client.Burst != nil && *client.Burst < 1 {
+			return fmt.Errorf("clusterClient.burst must be positive")
+		}
+	}
+
+	return nil
+}
+
+// validateBackoff returns an error if the initial backoff exceeds the maximum backoff. It is meant to be called on the
+// settings that a controller resolves to, since the backoffs may be set at different levels.
+func validateBackoff(c config.ControllerTuning) error {
+	rateLimiter := c.RateLimiter
+	if rateLimiter == nil || rateLimiter.InitialBackoff == nil || rateLimiter.MaxBackoff == nil {
+		return nil
+	}
+	if rateLimiter.InitialBackoff.Duration > rateLimiter.MaxBackoff.Duration {
+		return fmt.Errorf(
+			"rateLimiter.initialBackoff must not exceed rateLimiter.maxBackoff (%s > %s)",
+			rateLimiter.InitialBackoff.Duration,
+			rateLimiter.MaxBackoff.Duration,
+		)
+	}
+	return nil
+}
+
+// merge returns a copy of c with the fields that are set in override replaced.
+func merge(c, override config.ControllerTuning) config.ControllerTuning {
+	if override.WorkerCount != nil {
+		c.WorkerCount = override.WorkerCount
+	}
+
+	if override.RateLimiter != nil {
+		rateLimiter := config.RateLimiterConfiguration{}
+		if c.RateLimiter != nil {
+			rateLimiter = *c.RateLimiter
+		}
+		if override.RateLimiter.QPS != nil {
+			rateLimiter.QPS = override.RateLimiter.QPS
+		}
+		if override.RateLimiter.Burst != nil {
+			rateLimiter.Burst = override.RateLimiter.Burst
+		}
+		if override.RateLimiter.InitialBackoff != nil {
+			rateLimiter.InitialBackoff = override.RateLimiter.InitialBackoff
+		}
+		if override.RateLimiter.MaxBackoff != nil {
+			rateLimiter.MaxBackoff = override.RateLimiter.MaxBackoff
+		}
+		c.RateLimiter = &rateLimiter
+	}
+
+	if override.ClusterClient != nil {
+		client := config.ClusterClientConfiguration{}
+		if c.ClusterClient != nil {
+			client = *c.ClusterClient
+		}
+		if override.ClusterClient.QPS != nil {
+			client.QPS = override.ClusterClient.QPS
+		}
+		if override.ClusterClient.Burst != nil {
+			client.Burst = override.ClusterClient.Burst
+		}
+		c.ClusterClient = &client
+	}
+
+	return c
+}
@@ -50,6 +50,9 @@ import (
 
 const (
 	EventReasonAutoMigrationInfoUpdated = "AutoMigrationInfoUpdated"
+
+	// TuningName is the name of the controller in the tuning configuration of the controller manager.
+	TuningName = "automigration"
 )
 
 /*
@@ -107,10 +110,10 @@ func NewAutoMigrationController(
 		eventRecorder: eventsink.NewDefederatingRecorderMux(kubeClient, controllerName, 6),
 	}
 
-	c.worker = worker.NewReconcileWorker(
+	c.worker = worker.NewConfigurableReconcileWorker(
 		c.reconcile,
 		worker.WorkerTiming{},
-		controllerConfig.WorkerCount,
+		controllerConfig.WorkerConfig(TuningName, typeConfig.Name),
 		controllerConfig.Metrics,
 		delayingdeliver.NewMetricTags("auto-migration-worker", c.typeConfig.GetFederatedType().Kind),
 	)
@@ -133,7 +136,7 @@ func NewAutoMigrationController(
 	c.federatedInformer, err = util.NewFederatedInformer(
 		controllerConfig,
 		genericFedClient,
-		controllerConfig.KubeConfig,
+		controllerConfig.ClusterRestConfig(controllerConfig.KubeConfig, TuningName, typeConfig.Name),
 		&targetType,
 		func(o pkgruntime.Object) {
 			// enqueue with a delay to simulate a rudimentary rate limiter
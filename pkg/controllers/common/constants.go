@@ -128,10 +128,45 @@ const (
 	// DisableFollowingAnnotation indicates whether follower scheduling should be disabled for the follower object.
 	DisableFollowingAnnotation = DefaultPrefix + "disable-following"
 
+	// DriftPolicyAnnotation indicates the drift policy of the federated object. It is set by the scheduler from the
+	// propagation policy and absent if drifted fields are corrected.
+	DriftPolicyAnnotation = InternalPrefix + "drift-policy"
+
 	// When a pod remains unschedulable beyond this threshold, it becomes eligible for automatic migration.
 	PodUnschedulableThresholdAnnotation = InternalPrefix + "pod-unschedulable-threshold"
 	// AutoMigrationInfoAnnotation contains auto migration information.
 	AutoMigrationInfoAnnotation = DefaultPrefix + "auto-migration-info"
+	// FederatedHPAAnnotation indicates that a HorizontalPodAutoscaler in the host cluster scales its target globally
+	// across member clusters instead of being propagated to them.
+	FederatedHPAAnnotation = DefaultPrefix + "federated-hpa"
+	// ServiceExportAnnotation indicates that a Service in the host cluster is exported, i.e. the endpoints of the
+	// Service in all member clusters are imported into every member cluster.
+	ServiceExportAnnotation = DefaultPrefix + "service-export"
+	// SourceClusterAnnotation records the member cluster the endpoints of an imported EndpointSlice originate from.
+	SourceClusterAnnotation = DefaultPrefix + "source-cluster"
+	// GlobalDNSNameAnnotation specifies the DNS name under which the load balancer addresses of a Service or Ingress
+	// in all member clusters are published.
+	GlobalDNSNameAnnotation = DefaultPrefix + "global-dns-name"
+	// GlobalDNSTTLAnnotation specifies the TTL in seconds of the DNS records published for a Service or Ingress.
+	GlobalDNSTTLAnnotation = DefaultPrefix + "global-dns-ttl"
+	// GlobalDNSPublishedNameAnnotation records the DNS name under which the records of a Service or Ingress were
+	// published, so that the records can be deleted after the name is changed or removed.
+	GlobalDNSPublishedNameAnnotation = InternalPrefix + "global-dns-published-name"
+	// TokenRotatedAtAnnotation records the time at which the service account token of a FederatedCluster was last
+	// rotated. Changes to the annotation cause the clients of the cluster to be rebuilt with the new token.
+	TokenRotatedAtAnnotation = DefaultPrefix + "service-account-token-rotated-at"
+	// RestoredPendingControllersAnnotation records the pending controllers of a federated object restored from a
+	// backup. Until the restore is resumed, the pending controllers of the object are replaced by
+	// RestorePendingController to hold back all controllers, including the sync controller.
+	RestoredPendingControllersAnnotation = InternalPrefix + "restored-pending-controllers"
+	// RestoredAnnotation indicates that the placements of a federated object were restored from a backup. The
+	// scheduler adopts the placements instead of rescheduling the object, and removes the annotation.
+	RestoredAnnotation = InternalPrefix + "restored"
+	// RestorePendingController is the placeholder pending controller of federated objects restored from a backup.
+	RestorePendingController = DefaultPrefix + "restore"
+	// PausedAnnotation pauses the propagation of a federated object if set to "true". The sync controller stops
+	// creating, updating and deleting the object in member clusters until the annotation is removed.
+	PausedAnnotation = DefaultPrefix + "paused"
 	// ObservedAnnotationKeysAnnotation contains annotation keys observed in the last reconcile.
 	// It will be in the format of `a,b|c,d`, where `a` and `b` are the keys that are synced
 	// from source annotations to federated object annotations.
@@ -144,6 +179,36 @@ const (
 	TemplateGeneratorMergePatchAnnotation = FederateControllerPrefix + "template-generator-merge-patch"
 )
 
+// PausedLabel pauses the propagation of all federated objects in a namespace if set to "true" on the namespace.
+const PausedLabel = DefaultPrefix + "paused"
+
+// PropagationPauseConfigMapName is the name of the ConfigMap in the fed system namespace that pauses the propagation
+// of all federated objects if its PropagationPausedKey is "true".
+const (
+	PropagationPauseConfigMapName = "propagation-pause"
+	PropagationPausedKey          = "paused"
+)
+
+// ShardBucketLabel is the hash bucket of the key of a federated object. If sharding is enabled, each controller
+// manager replica only watches the federated objects of the buckets it owns.
+const ShardBucketLabel = DefaultPrefix + "shard-bucket"
+
+// ShardGroupLabel identifies the group of controller manager replicas that a shard Lease belongs to.
+const ShardGroupLabel = DefaultPrefix + "shard-group"
+
+// PullClusterPrefix prefixes the label and the finalizer of federated objects placed in a pull-mode cluster, e.g.
+// pull.kubeadmiral.io/<cluster>. The sync controller sets the label so that the agent of the cluster only watches these
+// objects, and the agent sets the finalizer so that the objects are removed from the cluster before they are deleted.
+const PullClusterPrefix = "pull." + DefaultPrefix
+
+// AgentCascadingDeleteCompletedAnnotation is set on a terminating pull-mode FederatedCluster by the agent of the
+// cluster once all objects managed by KubeAdmiral are deleted from the cluster.
+const AgentCascadingDeleteCompletedAnnotation = InternalPrefix + "agent-cascading-delete-completed"
+
+// ImportedServiceLabel identifies the exported Service that a Service or EndpointSlice in a member cluster is derived
+// from.
+const ImportedServiceLabel = DefaultPrefix + "imported-service"
+
 // PropagatedAnnotationKeys and PropagatedLabelKeys are used to store the keys of annotations and labels that are present
 // on the resource to propagate. By persisting these, we can tell whether an annotation/label is deleted from the propagated
 // and prevent accidental retention.
@@ -157,9 +222,15 @@ const (
 const (
 	ClusterClientCertificateKey    = "client-certificate-data"
 	ClusterClientKeyKey            = "client-key-data"
+	ClusterBearerTokenKey          = "bearer-token-data"
 	ClusterCertificateAuthorityKey = "certificate-authority-data"
 	ClusterServiceAccountTokenKey  = "service-account-token-data"
 	ClusterServiceAccountCAKey     = "service-account-ca-data"
+
+	// The following keys are used by the OIDC credential provider.
+	ClusterOIDCRefreshTokenKey = "oidc-refresh-token"
+	ClusterOIDCClientSecretKey = "oidc-client-secret"
+	ClusterOIDCIDTokenKey      = "oidc-id-token"
 )
 
 var DeploymentGVR = schema.GroupVersionResource{
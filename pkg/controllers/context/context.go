@@ -27,9 +27,15 @@ import (
 	kubeclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
 	fedclient "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned"
 	fedinformers "github.com/kubewharf/kubeadmiral/pkg/client/informers/externalversions"
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/sharding"
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/tuning"
+	dnsprovider "github.com/kubewharf/kubeadmiral/pkg/controllers/globaldns/provider"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/federatedclient"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/worker"
 	"github.com/kubewharf/kubeadmiral/pkg/stats"
 )
 
@@ -43,6 +49,9 @@ type Context struct {
 
 	RestConfig      *rest.Config
 	ComponentConfig *ComponentConfig
+	// Tuning provides the worker and member cluster client settings of the controllers. WorkerCount is used for all
+	// controllers if it is nil.
+	Tuning *tuning.Provider
 
 	Metrics stats.Metrics
 
@@ -53,6 +62,13 @@ type Context struct {
 	DynamicInformerFactory dynamicinformer.DynamicSharedInformerFactory
 	FedInformerFactory     fedinformers.SharedInformerFactory
 
+	// FederatedObjectInformerFactory creates the informers of federated objects. If sharding is enabled, they only
+	// list and watch the federated objects of Shard. Otherwise, it is the same as DynamicInformerFactory, which is never
+	// limited to a shard, e.g. for looking up the placements of the objects referenced by workload affinity.
+	FederatedObjectInformerFactory dynamicinformer.DynamicSharedInformerFactory
+	// Shard is the shard of federated objects owned by the controller manager, or nil if sharding is disabled.
+	Shard *sharding.Shard
+
 	FederatedClientFactory federatedclient.FederatedClientFactory
 }
 
@@ -66,14 +82,52 @@ func (c *Context) StartFactories(ctx context.Context) {
 	if c.FedInformerFactory != nil {
 		c.FedInformerFactory.Start(ctx.Done())
 	}
+	if c.FederatedObjectInformerFactory != nil {
+		c.FederatedObjectInformerFactory.Start(ctx.Done())
+	}
 
 	if c.FederatedClientFactory != nil {
 		c.FederatedClientFactory.Start(ctx)
 	}
 }
 
+// WorkerConfig returns the worker config of the named controller for the named FederatedTypeConfig, which is empty for
+// controllers that do not run for each FederatedTypeConfig.
+func (c *Context) WorkerConfig(controller, ftc string) worker.ConfigSource {
+	if c.Tuning == nil {
+		return worker.StaticConfig(worker.Config{WorkerCount: c.WorkerCount})
+	}
+	return c.Tuning.Worker(controller, ftc)
+}
+
 type ComponentConfig struct {
 	NSAutoPropExcludeRegexp              *regexp.Regexp
 	FederatedTypeConfigCreateCRDsForFTCs bool
 	ClusterJoinTimeout                   time.Duration
+	ClusterTokenRotationPeriod           time.Duration
+	ClusterFlapDetectionWindow           time.Duration
+	ClusterFlapDetectionThreshold        int
+	ClusterHealthCheckPeriod             time.Duration
+	ClusterHealthCheckPath               string
+	ClusterHealthCheckTimeout            time.Duration
+	ClusterHealthCheckSuccessThreshold   int
+	ClusterHealthCheckFailureThreshold   int
+	ClusterAgentStatusTimeout            time.Duration
+	// ClusterCredentialProviderPolicy restricts the credential providers used to access member clusters.
+	ClusterCredentialProviderPolicy *util.CredentialProviderPolicy
+	// GlobalDNSProvider is nil if no DNS provider is configured.
+	GlobalDNSProvider dnsprovider.Provider
+	// FederateMetadataPropagation is nil if no global rules are configured.
+	FederateMetadataPropagation *fedcorev1a1.MetadataPropagation
+	// PausePropagation pauses the propagation of all federated objects regardless of the propagation-pause ConfigMap.
+	PausePropagation bool
+	// MonitorOutOfSyncRecheckDelay is the delay before the monitor controller checks an object whose status is out of
+	// sync again.
+	MonitorOutOfSyncRecheckDelay time.Duration
+	// SchedulerSupplyLimitProportion limits the share of replicas that the ClusterCapacityWeight plugin assigns to a
+	// cluster.
+	SchedulerSupplyLimitProportion float64
+	// SchedulerWebhookTimeout is the timeout of requests to scheduler webhook plugins whose configuration does not set
+	// one.
+	SchedulerWebhookTimeout time.Duration
 }
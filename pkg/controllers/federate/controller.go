@@ -38,6 +38,7 @@ import (
 	"k8s.io/klog/v2"
 
 	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/sharding"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/annotation"
@@ -70,8 +71,10 @@ const (
 // FederateController federates objects of source type to objects of federated type
 type FederateController struct {
 	typeConfig         *fedcorev1a1.FederatedTypeConfig
+	classifier         *metadataClassifier
 	name               string
 	fedSystemNamespace string
+	shard              *sharding.Shard
 
 	federatedObjectClient dynamicclient.NamespaceableResourceInterface
 	federatedObjectLister cache.GenericLister
@@ -99,24 +102,34 @@ func NewFederateController(
 	federatedObjectInformer informers.GenericInformer,
 	sourceObjectInformer informers.GenericInformer,
 	metrics stats.Metrics,
-	workerCount int,
+	workerConfig worker.ConfigSource,
 	fedSystemNamespace string,
+	metadataPropagation *fedcorev1a1.MetadataPropagation,
+	shard *sharding.Shard,
 ) (*FederateController, error) {
 	controllerName := fmt.Sprintf("%s-federate-controller", typeConfig.GetFederatedType().Name)
 	logger := klog.LoggerWithValues(klog.Background(), "controller", FederateControllerName, "ftc", typeConfig.Name)
 
+	// Rules in the FederatedTypeConfig take precedence over the global rules.
+	classifier, err := newMetadataClassifier(typeConfig.Spec.MetadataPropagation, metadataPropagation)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata propagation rules: %w", err)
+	}
+
 	c := &FederateController{
 		typeConfig:         typeConfig,
+		classifier:         classifier,
 		name:               controllerName,
 		fedSystemNamespace: fedSystemNamespace,
+		shard:              shard,
 		metrics:            metrics,
 		logger:             logger,
 	}
 
-	c.worker = worker.NewReconcileWorker(
+	c.worker = worker.NewConfigurableReconcileWorker(
 		c.reconcile,
 		worker.WorkerTiming{},
-		workerCount,
+		workerConfig,
 		metrics,
 		delayingdeliver.NewMetricTags("federate-controller-worker", c.typeConfig.GetFederatedType().Kind),
 	)
@@ -201,6 +214,11 @@ func (c *FederateController) reconcile(qualifiedName common.QualifiedName) (stat
 		logger.WithValues("duration", time.Since(startTime), "status", status.String()).V(3).Info("Finished reconcile")
 	}()
 
+	if !c.shard.Owns(qualifiedName) {
+		logger.V(3).Info("Object is owned by another shard, skip federating")
+		return worker.StatusAllOK
+	}
+
 	sourceObject, err := c.sourceObjectFromStore(qualifiedName)
 	if err != nil && apierrors.IsNotFound(err) {
 		logger.V(3).Info(fmt.Sprintf("No source object for %s found, skip federating", qualifiedName.String()))
@@ -245,6 +263,20 @@ func (c *FederateController) reconcile(qualifiedName common.QualifiedName) (stat
 		}
 	}
 
+	if sourceObject.GetAnnotations()[common.FederatedHPAAnnotation] == common.AnnotationValueTrue {
+		// Federated HPAs are evaluated in the host cluster by the federated HPA controller. Their federated objects are
+		// removed so that HPAs in member clusters do not fight with the global replica count.
+		logger.V(3).Info("Federated HPA annotation found, skip federating")
+		if err := c.handleTerminatingSourceObject(ctx, sourceObject, fedObject); err != nil {
+			logger.Error(err, "Failed to remove federated object of federated HPA")
+			if apierrors.IsConflict(err) {
+				return worker.StatusConflict
+			}
+			return worker.StatusError
+		}
+		return worker.StatusAllOK
+	}
+
 	if sourceObject, err = c.ensureFinalizer(ctx, sourceObject); err != nil {
 		logger.Error(err, "Failed to ensure finalizer on source object")
 		if apierrors.IsConflict(err) {
@@ -430,7 +462,7 @@ func (c *FederateController) handleCreateFederatedObject(ctx context.Context, so
 	logger := klog.FromContext(ctx)
 
 	logger.V(2).Info("Generating federated object from source object")
-	fedObject, err := newFederatedObjectForSourceObject(c.typeConfig, sourceObject)
+	fedObject, err := newFederatedObjectForSourceObject(c.typeConfig, c.classifier, sourceObject)
 	if err != nil {
 		return fmt.Errorf("failed to generate federated object from source object: %w", err)
 	}
@@ -438,6 +470,7 @@ func (c *FederateController) handleCreateFederatedObject(ctx context.Context, so
 	if _, err = pendingcontrollers.SetPendingControllers(fedObject, c.typeConfig.GetControllers()); err != nil {
 		return fmt.Errorf("failed to set pending controllers on federated object: %w", err)
 	}
+	c.shard.EnsureBucketLabel(fedObject)
 
 	logger.V(1).Info("Creating federated object")
 	if _, err = c.federatedObjectClient.Namespace(fedObject.GetNamespace()).Create(
@@ -458,10 +491,13 @@ func (c *FederateController) handleExistingFederatedObject(
 	logger := klog.FromContext(ctx)
 
 	logger.V(3).Info("Checking if federated object needs update")
-	needsUpdate, err := updateFederatedObjectForSourceObject(fedObject, c.typeConfig, sourceObject)
+	needsUpdate, err := updateFederatedObjectForSourceObject(fedObject, c.typeConfig, c.classifier, sourceObject)
 	if err != nil {
 		return false, fmt.Errorf("failed to check if federated object needs update: %w", err)
 	}
+	if c.shard.EnsureBucketLabel(fedObject) {
+		needsUpdate = true
+	}
 	if !needsUpdate {
 		logger.V(3).Info("No updates required to the federated object")
 		return false, nil
@@ -0,0 +1,216 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+// metadataClassifier decides whether the labels and annotations of source objects are copied to the federated
+// object, to the template or to neither.
+type metadataClassifier struct {
+	// Rules in descending order of precedence.
+	labelRules      []*metadataRules
+	annotationRules []*metadataRules
+}
+
+// newMetadataClassifier creates a classifier from the given propagation rules in descending order of precedence.
+// Nil rules are skipped.
+func newMetadataClassifier(propagations ...*fedcorev1a1.MetadataPropagation) (*metadataClassifier, error) {
+	classifier := &metadataClassifier{}
+
+	for _, propagation := range propagations {
+		if propagation == nil {
+			continue
+		}
+
+		if propagation.Labels != nil {
+			rules, err := newMetadataRules(propagation.Labels)
+			if err != nil {
+				return nil, fmt.Errorf("invalid label rules: %w", err)
+			}
+			classifier.labelRules = append(classifier.labelRules, rules)
+		}
+
+		if propagation.Annotations != nil {
+			rules, err := newMetadataRules(propagation.Annotations)
+			if err != nil {
+				return nil, fmt.Errorf("invalid annotation rules: %w", err)
+			}
+			classifier.annotationRules = append(classifier.annotationRules, rules)
+		}
+	}
+
+	return classifier, nil
+}
+
+// Splits annotations from a source object into federated annotations and template annotations.
+func (c *metadataClassifier) classifyAnnotations(annotations map[string]string) (
+	federatedAnnotations map[string]string,
+	templateAnnotations map[string]string,
+) {
+	federatedAnnotations, templateAnnotations = classifyStringMap(annotations, c.classifyAnnotation)
+	federatedAnnotations[common.FederatedObjectAnnotation] = "1"
+	return federatedAnnotations, templateAnnotations
+}
+
+func (c *metadataClassifier) classifyAnnotation(annotation string) (federated, template bool) {
+	if ignoredAnnotationSet.Has(annotation) {
+		return false, false
+	}
+
+	if federatedAnnotationSet.Has(annotation) {
+		return true, false
+	}
+
+	return classifyByRules(c.annotationRules, annotation)
+}
+
+func (c *metadataClassifier) classifyLabels(labels map[string]string) (
+	federatedLabels map[string]string,
+	templateLabels map[string]string,
+) {
+	return classifyStringMap(labels, c.classifyLabel)
+}
+
+func (c *metadataClassifier) classifyLabel(labelKey string) (federated, template bool) {
+	if ignoredLabelSet.Has(labelKey) {
+		return false, false
+	}
+
+	if federatedLabelSet.Has(labelKey) {
+		return true, false
+	}
+
+	return classifyByRules(c.labelRules, labelKey)
+}
+
+func classifyByRules(rulesList []*metadataRules, key string) (federated, template bool) {
+	for _, rules := range rulesList {
+		if federated, template, matched := rules.classify(key); matched {
+			return federated, template
+		}
+	}
+
+	return false, true
+}
+
+type metadataRules struct {
+	federatedOnly *keyMatcher
+	templateOnly  *keyMatcher
+	ignored       *keyMatcher
+}
+
+func newMetadataRules(classification *fedcorev1a1.MetadataClassification) (*metadataRules, error) {
+	var err error
+	rules := &metadataRules{}
+
+	if rules.federatedOnly, err = newKeyMatcher(classification.FederatedOnly); err != nil {
+		return nil, fmt.Errorf("invalid federatedOnly matcher: %w", err)
+	}
+	if rules.templateOnly, err = newKeyMatcher(classification.TemplateOnly); err != nil {
+		return nil, fmt.Errorf("invalid templateOnly matcher: %w", err)
+	}
+	if rules.ignored, err = newKeyMatcher(classification.Ignored); err != nil {
+		return nil, fmt.Errorf("invalid ignored matcher: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (r *metadataRules) classify(key string) (federated, template, matched bool) {
+	switch {
+	case r.ignored.matches(key):
+		return false, false, true
+	case r.federatedOnly.matches(key):
+		return true, false, true
+	case r.templateOnly.matches(key):
+		return false, true, true
+	default:
+		return false, false, false
+	}
+}
+
+type keyMatcher struct {
+	include *keySelector
+	exclude *keySelector
+}
+
+func newKeyMatcher(matcher *fedcorev1a1.KeyMatcher) (*keyMatcher, error) {
+	if matcher == nil {
+		return nil, nil
+	}
+
+	include, err := newKeySelector(&matcher.Include)
+	if err != nil {
+		return nil, fmt.Errorf("invalid include selector: %w", err)
+	}
+	exclude, err := newKeySelector(&matcher.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exclude selector: %w", err)
+	}
+
+	return &keyMatcher{include: include, exclude: exclude}, nil
+}
+
+func (m *keyMatcher) matches(key string) bool {
+	if m == nil {
+		return false
+	}
+
+	return m.include.matches(key) && !m.exclude.matches(key)
+}
+
+type keySelector struct {
+	prefixes []string
+	regexps  []*regexp.Regexp
+}
+
+func newKeySelector(selector *fedcorev1a1.KeySelector) (*keySelector, error) {
+	result := &keySelector{prefixes: selector.Prefixes}
+
+	for _, expr := range selector.Regexps {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, err
+		}
+		result.regexps = append(result.regexps, re)
+	}
+
+	return result, nil
+}
+
+func (s *keySelector) matches(key string) bool {
+	for _, prefix := range s.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	for _, re := range s.regexps {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+
+	return false
+}
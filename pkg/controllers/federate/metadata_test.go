@@ -0,0 +1,146 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federate
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler"
+)
+
+func TestMetadataClassifier(t *testing.T) {
+	global := &fedcorev1a1.MetadataPropagation{
+		Labels: &fedcorev1a1.MetadataClassification{
+			Ignored: &fedcorev1a1.KeyMatcher{
+				Include: fedcorev1a1.KeySelector{Prefixes: []string{"argocd.argoproj.io/"}},
+			},
+		},
+		Annotations: &fedcorev1a1.MetadataClassification{
+			FederatedOnly: &fedcorev1a1.KeyMatcher{
+				Include: fedcorev1a1.KeySelector{Regexps: []string{`^team\.example\.com/`}},
+				Exclude: fedcorev1a1.KeySelector{Prefixes: []string{"team.example.com/owner"}},
+			},
+			Ignored: &fedcorev1a1.KeyMatcher{
+				Include: fedcorev1a1.KeySelector{
+					Prefixes: []string{"kubectl.kubernetes.io/last-applied-configuration", "team.example.com/secret"},
+				},
+			},
+		},
+	}
+	ftc := &fedcorev1a1.MetadataPropagation{
+		Labels: &fedcorev1a1.MetadataClassification{
+			TemplateOnly: &fedcorev1a1.KeyMatcher{
+				Include: fedcorev1a1.KeySelector{Prefixes: []string{"argocd.argoproj.io/instance"}},
+			},
+		},
+	}
+
+	type classification struct {
+		federated bool
+		template  bool
+	}
+
+	testCases := map[string]struct {
+		propagations        []*fedcorev1a1.MetadataPropagation
+		expectedLabels      map[string]classification
+		expectedAnnotations map[string]classification
+	}{
+		"no rules": {
+			expectedLabels: map[string]classification{
+				"app":                                {template: true},
+				scheduler.PropagationPolicyNameLabel: {federated: true},
+			},
+			expectedAnnotations: map[string]classification{
+				"kubectl.kubernetes.io/last-applied-configuration": {template: true},
+				scheduler.SchedulingModeAnnotation:                 {federated: true},
+				RetainReplicasAnnotation:                           {},
+			},
+		},
+		"global rules": {
+			propagations: []*fedcorev1a1.MetadataPropagation{global},
+			expectedLabels: map[string]classification{
+				"app":                          {template: true},
+				"argocd.argoproj.io/instance":  {},
+				"argocd.argoproj.io/something": {},
+			},
+			expectedAnnotations: map[string]classification{
+				"kubectl.kubernetes.io/last-applied-configuration": {},
+				"team.example.com/name":                            {federated: true},
+				"team.example.com/owner":                           {template: true},
+				"team.example.com/secret":                          {},
+				"prefix.team.example.com/name":                     {template: true},
+			},
+		},
+		"ftc rules take precedence over global rules": {
+			propagations: []*fedcorev1a1.MetadataPropagation{ftc, global},
+			expectedLabels: map[string]classification{
+				"argocd.argoproj.io/instance":  {template: true},
+				"argocd.argoproj.io/something": {},
+			},
+		},
+		"reserved keys cannot be reclassified": {
+			propagations: []*fedcorev1a1.MetadataPropagation{{
+				Labels: &fedcorev1a1.MetadataClassification{
+					Ignored: &fedcorev1a1.KeyMatcher{Include: fedcorev1a1.KeySelector{Regexps: []string{".*"}}},
+				},
+				Annotations: &fedcorev1a1.MetadataClassification{
+					TemplateOnly: &fedcorev1a1.KeyMatcher{Include: fedcorev1a1.KeySelector{Regexps: []string{".*"}}},
+				},
+			}},
+			expectedLabels: map[string]classification{
+				"app":                                {},
+				scheduler.PropagationPolicyNameLabel: {federated: true},
+			},
+			expectedAnnotations: map[string]classification{
+				scheduler.SchedulingModeAnnotation: {federated: true},
+				RetainReplicasAnnotation:           {},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			classifier, err := newMetadataClassifier(tc.propagations...)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			for key, expected := range tc.expectedLabels {
+				federated, template := classifier.classifyLabel(key)
+				g.Expect(classification{federated, template}).To(gomega.Equal(expected), "label %s", key)
+			}
+			for key, expected := range tc.expectedAnnotations {
+				federated, template := classifier.classifyAnnotation(key)
+				g.Expect(classification{federated, template}).To(gomega.Equal(expected), "annotation %s", key)
+			}
+		})
+	}
+}
+
+func TestMetadataClassifierInvalidRegexp(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	_, err := newMetadataClassifier(&fedcorev1a1.MetadataPropagation{
+		Annotations: &fedcorev1a1.MetadataClassification{
+			Ignored: &fedcorev1a1.KeyMatcher{Include: fedcorev1a1.KeySelector{Regexps: []string{"("}}},
+		},
+	})
+	g.Expect(err).To(gomega.HaveOccurred())
+}
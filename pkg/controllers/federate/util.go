@@ -61,6 +61,7 @@ func templateForSourceObject(sourceObj *unstructured.Unstructured, annotations,
 
 func newFederatedObjectForSourceObject(
 	typeConfig *fedcorev1a1.FederatedTypeConfig,
+	classifier *metadataClassifier,
 	sourceObj *unstructured.Unstructured,
 ) (*unstructured.Unstructured, error) {
 	fedType := typeConfig.GetFederatedType()
@@ -75,12 +76,12 @@ func newFederatedObjectForSourceObject(
 		[]metav1.OwnerReference{*metav1.NewControllerRef(sourceObj, sourceObj.GroupVersionKind())},
 	)
 
-	federatedLabels, templateLabels := classifyLabels(sourceObj.GetLabels())
+	federatedLabels, templateLabels := classifier.classifyLabels(sourceObj.GetLabels())
 	fedObj.SetLabels(federatedLabels)
 
 	observedLabelKeys := generateObservedKeys(sourceObj.GetLabels(), federatedLabels)
 
-	federatedAnnotations, templateAnnotations := classifyAnnotations(sourceObj.GetAnnotations())
+	federatedAnnotations, templateAnnotations := classifier.classifyAnnotations(sourceObj.GetAnnotations())
 	if federatedAnnotations == nil {
 		federatedAnnotations = make(map[string]string)
 	}
@@ -121,6 +122,7 @@ func newFederatedObjectForSourceObject(
 func updateFederatedObjectForSourceObject(
 	fedObject *unstructured.Unstructured,
 	typeConfig *fedcorev1a1.FederatedTypeConfig,
+	classifier *metadataClassifier,
 	sourceObject *unstructured.Unstructured,
 ) (bool, error) {
 	isUpdated := false
@@ -133,11 +135,15 @@ func updateFederatedObjectForSourceObject(
 		isUpdated = true
 	}
 
-	federatedAnnotations, templateAnnotations := classifyAnnotations(sourceObject.GetAnnotations())
+	federatedAnnotations, templateAnnotations := classifier.classifyAnnotations(sourceObject.GetAnnotations())
 
 	observedAnnotationKeys := generateObservedKeys(sourceObject.GetAnnotations(), federatedAnnotations)
 
-	federatedLabels, templateLabels := classifyLabels(sourceObject.GetLabels())
+	federatedLabels, templateLabels := classifier.classifyLabels(sourceObject.GetLabels())
+	// The shard bucket label is not derived from the source object, so it is kept as is.
+	if bucket, exists := fedObject.GetLabels()[common.ShardBucketLabel]; exists {
+		federatedLabels[common.ShardBucketLabel] = bucket
+	}
 	if !equality.Semantic.DeepEqual(federatedLabels, fedObject.GetLabels()) {
 		fedObject.SetLabels(federatedLabels)
 		isUpdated = true
@@ -173,7 +179,7 @@ func updateFederatedObjectForSourceObject(
 		federatedAnnotations,
 		fedObject.GetAnnotations(),
 		func(key string) bool {
-			federated, _ := classifyAnnotation(key)
+			federated, _ := classifier.classifyAnnotation(key)
 			return federated
 		},
 	)
@@ -231,6 +237,7 @@ var (
 		scheduler.FollowsObjectAnnotation,
 		common.FollowersAnnotation,
 		common.DisableFollowingAnnotation,
+		common.PausedAnnotation,
 	)
 
 	// TODO: Do we need to specify the internal annotations here?
@@ -244,6 +251,8 @@ var (
 		util.ConflictResolutionInternalAnnotation,
 		util.OrphanManagedResourcesInternalAnnotation,
 		common.EnableFollowerSchedulingAnnotation,
+		common.DriftPolicyAnnotation,
+		common.GlobalDNSPublishedNameAnnotation,
 	)
 
 	federatedLabelSet = sets.New(
@@ -252,6 +261,11 @@ var (
 		override.OverridePolicyNameLabel,
 		override.ClusterOverridePolicyNameLabel,
 	)
+
+	// List of labels that should be ignored on the source object
+	ignoredLabelSet = sets.New(
+		common.ShardBucketLabel,
+	)
 )
 
 func classifyStringMap(
@@ -274,43 +288,6 @@ func classifyStringMap(
 	return federatedMap, templateMap
 }
 
-// Splits annotations from a source object into federated annotations and template annotations.
-func classifyAnnotations(annotations map[string]string) (
-	federatedAnnotations map[string]string,
-	templateAnnotations map[string]string,
-) {
-	federatedAnnotations, templateAnnotations = classifyStringMap(annotations, classifyAnnotation)
-	federatedAnnotations[common.FederatedObjectAnnotation] = "1"
-	return federatedAnnotations, templateAnnotations
-}
-
-func classifyAnnotation(annotation string) (federated, template bool) {
-	if ignoredAnnotationSet.Has(annotation) {
-		return false, false
-	}
-
-	if federatedAnnotationSet.Has(annotation) {
-		return true, false
-	} else {
-		return false, true
-	}
-}
-
-func classifyLabels(labels map[string]string) (
-	federatedLabels map[string]string,
-	templateLabels map[string]string,
-) {
-	return classifyStringMap(labels, classifyLabel)
-}
-
-func classifyLabel(labelKey string) (federated, template bool) {
-	if federatedLabelSet.Has(labelKey) {
-		return true, false
-	} else {
-		return false, true
-	}
-}
-
 func generateObservedKeys(sourceMap map[string]string, federatedMap map[string]string) string {
 	if len(sourceMap) == 0 {
 		return ""
@@ -41,6 +41,7 @@ import (
 	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
 	fedclient "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
 )
 
 const (
@@ -88,6 +89,8 @@ func handleNotJoinedCluster(
 	eventRecorder record.EventRecorder,
 	fedSystemNamespace string,
 	clusterJoinTimeout time.Duration,
+	clusterRoleRules []rbacv1.PolicyRule,
+	credentialProviderPolicy *util.CredentialProviderPolicy,
 ) (c *fedcorev1a1.FederatedCluster, condition *fedcorev1a1.ClusterCondition, joinPerformed *bool, err error) {
 	logger := klog.FromContext(ctx).WithValues("process", "cluster-join")
 	ctx = klog.NewContext(ctx, logger)
@@ -116,7 +119,7 @@ func handleNotJoinedCluster(
 
 	// 2. The remaining steps require a cluster kube client, attempt to create one
 
-	_, clusterKubeClient, err := getClusterClient(ctx, kubeClient, fedSystemNamespace, cluster)
+	_, clusterKubeClient, err := getClusterClient(ctx, kubeClient, fedSystemNamespace, cluster, credentialProviderPolicy)
 	if err != nil {
 		logger.Error(err, "Failed to create cluster client")
 		msg := fmt.Sprintf("Failed to create cluster client: %v", err.Error())
@@ -207,7 +210,15 @@ func handleNotJoinedCluster(
 
 	if cluster.Spec.UseServiceAccountToken {
 		logger.V(2).Info("Get and save cluster token")
-		err = getAndSaveClusterToken(ctx, cluster, kubeClient, clusterKubeClient, fedSystemNamespace, memberFedNamespace)
+		err = getAndSaveClusterToken(
+			ctx,
+			cluster,
+			kubeClient,
+			clusterKubeClient,
+			fedSystemNamespace,
+			memberFedNamespace,
+			clusterRoleRules,
+		)
 
 		if err != nil {
 			msg := fmt.Sprintf("Failed to get and save cluster token: %v", err.Error())
@@ -245,11 +256,19 @@ func getAndSaveClusterToken(
 	clusterKubeClient kubeclient.Interface,
 	fedSystemNamespace string,
 	memberSystemNamespace *corev1.Namespace,
+	clusterRoleRules []rbacv1.PolicyRule,
 ) error {
 	logger := klog.FromContext(ctx)
 
 	logger.V(2).Info("Creating authorized service account")
-	saTokenSecretName, err := createAuthorizedServiceAccount(ctx, clusterKubeClient, memberSystemNamespace, cluster.Name, false)
+	saTokenSecretName, err := createAuthorizedServiceAccount(
+		ctx,
+		clusterKubeClient,
+		memberSystemNamespace,
+		cluster.Name,
+		clusterRoleRules,
+		false,
+	)
 	if err != nil {
 		return err
 	}
@@ -288,6 +307,7 @@ func createAuthorizedServiceAccount(
 	clusterKubeClient kubeclient.Interface,
 	memberSystemNamespace *corev1.Namespace,
 	clusterName string,
+	clusterRoleRules []rbacv1.PolicyRule,
 	errorOnExisting bool,
 ) (string, error) {
 	logger := klog.FromContext(ctx).WithValues("member-service-account-name", MemberServiceAccountName)
@@ -317,10 +337,22 @@ func createAuthorizedServiceAccount(
 
 	// 3. create rbac
 	logger.V(1).Info("Creating RBAC for service account")
-	err = createClusterRoleAndBinding(ctx, clusterKubeClient, memberSystemNamespace, MemberServiceAccountName, clusterName, errorOnExisting)
+	err = createClusterRoleAndBinding(
+		ctx,
+		clusterKubeClient,
+		memberSystemNamespace,
+		MemberServiceAccountName,
+		clusterName,
+		clusterRoleRules,
+		errorOnExisting,
+	)
 	if err != nil {
 		return "", fmt.Errorf("error creating cluster role and binding for service account %s: %w", MemberServiceAccountName, err)
 	}
+	err = ensureMemberSystemNamespaceRole(ctx, clusterKubeClient, memberSystemNamespace.Name, MemberServiceAccountName)
+	if err != nil {
+		return "", fmt.Errorf("error creating role and binding for service account %s: %w", MemberServiceAccountName, err)
+	}
 
 	return saTokenSecretName, nil
 }
@@ -414,18 +446,19 @@ func bindingSubjects(saName, namespace string) []rbacv1.Subject {
 	}
 }
 
-// createClusterRoleAndBinding creates an RBAC cluster role and
-// binding that allows the service account identified by saName to
-// access all resources in all namespaces in the cluster associated
-// with clientset.
+// createClusterRoleAndBinding creates an RBAC cluster role with the
+// given rules and binding that allows the service account identified
+// by saName to access the resources required by the control plane in
+// the cluster associated with clientset.
 func createClusterRoleAndBinding(
 	ctx context.Context,
 	clientset kubeclient.Interface,
 	namespace *corev1.Namespace,
 	saName, clusterName string,
+	rules []rbacv1.PolicyRule,
 	errorOnExisting bool,
 ) error {
-	roleName := fmt.Sprintf("kubeadmiral-controller-manager:%s", saName)
+	roleName := memberClusterRoleName(saName)
 	namespaceOwnerReference := *metav1.NewControllerRef(namespace, schema.GroupVersionKind{Version: "v1", Kind: "Namespace"})
 
 	role := &rbacv1.ClusterRole{
@@ -433,17 +466,7 @@ func createClusterRoleAndBinding(
 			Name:            roleName,
 			OwnerReferences: []metav1.OwnerReference{namespaceOwnerReference},
 		},
-		Rules: []rbacv1.PolicyRule{
-			{
-				Verbs:     []string{rbacv1.VerbAll},
-				APIGroups: []string{rbacv1.APIGroupAll},
-				Resources: []string{rbacv1.ResourceAll},
-			},
-			{
-				NonResourceURLs: []string{rbacv1.NonResourceAll},
-				Verbs:           []string{"get"},
-			},
-		},
+		Rules: rules,
 	}
 
 	existingRole, err := clientset.RbacV1().ClusterRoles().Get(ctx, roleName, metav1.GetOptions{})
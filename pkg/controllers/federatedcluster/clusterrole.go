@@ -0,0 +1,291 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedcluster
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/worker"
+)
+
+// memberClusterReadOnlyRules are the rules required by the control plane to collect the status of a member cluster
+// and its pods, regardless of the federated types.
+var memberClusterReadOnlyRules = []rbacv1.PolicyRule{
+	{
+		Verbs:     []string{"get", "list", "watch"},
+		APIGroups: []string{""},
+		Resources: []string{"nodes", "pods"},
+	},
+	{
+		// used to collect the latest replicasets of deployments
+		Verbs:     []string{"get", "list", "watch"},
+		APIGroups: []string{"apps"},
+		Resources: []string{"replicasets"},
+	},
+	{
+		// used by the federated HPA controller
+		Verbs:     []string{"get", "list"},
+		APIGroups: []string{"metrics.k8s.io"},
+		Resources: []string{"pods"},
+	},
+	{
+		// used by the global DNS controller to collect the load balancer addresses of ingresses
+		Verbs:     []string{"get", "list", "watch"},
+		APIGroups: []string{"networking.k8s.io"},
+		Resources: []string{"ingresses"},
+	},
+	{
+		// used for health checks and API resource discovery
+		Verbs:           []string{"get"},
+		NonResourceURLs: []string{rbacv1.NonResourceAll},
+	},
+}
+
+// memberClusterServiceImportRules are the rules required by the MCS controller to import exported Services into a
+// member cluster.
+var memberClusterServiceImportRules = []rbacv1.PolicyRule{
+	{
+		Verbs:     []string{"get", "list", "watch", "create", "update", "delete"},
+		APIGroups: []string{""},
+		Resources: []string{"services"},
+	},
+	{
+		Verbs:     []string{"get", "list", "watch", "create", "update", "delete"},
+		APIGroups: []string{"discovery.k8s.io"},
+		Resources: []string{"endpointslices"},
+	},
+}
+
+// memberSystemNamespaceRules are the rules of the role granted to the service account of a member cluster in the
+// system namespace of the member cluster. Unlike the rules of the cluster role, they do not depend on the
+// FederatedTypeConfigs.
+var memberSystemNamespaceRules = []rbacv1.PolicyRule{
+	{
+		// used by token rotation to mint and revoke the token secrets of the service account
+		Verbs:     []string{"get", "list", "create", "delete"},
+		APIGroups: []string{""},
+		Resources: []string{"secrets"},
+	},
+}
+
+// memberClusterTargetVerbs are the verbs required by the control plane to manage the target objects of a federated
+// type in a member cluster.
+var memberClusterTargetVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+
+func memberClusterRoleName(saName string) string {
+	return fmt.Sprintf("kubeadmiral-controller-manager:%s", saName)
+}
+
+// getMemberClusterRoleRules returns the least-privilege rules of the cluster role granted to the service account of a
+// member cluster. The role allows managing the target types of the given FederatedTypeConfigs, in addition to the
+// read-only access required for status collection and the access required for importing multi-cluster Services.
+func getMemberClusterRoleRules(typeConfigs []*fedcorev1a1.FederatedTypeConfig) []rbacv1.PolicyRule {
+	resourcesByGroup := map[string]sets.Set[string]{}
+	for _, typeConfig := range typeConfigs {
+		targetType := typeConfig.GetTargetType()
+		if resourcesByGroup[targetType.Group] == nil {
+			resourcesByGroup[targetType.Group] = sets.New[string]()
+		}
+		resourcesByGroup[targetType.Group].Insert(targetType.Name)
+	}
+
+	groups := make([]string, 0, len(resourcesByGroup))
+	for group := range resourcesByGroup {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	rules := make(
+		[]rbacv1.PolicyRule,
+		0,
+		len(groups)+len(memberClusterReadOnlyRules)+len(memberClusterServiceImportRules),
+	)
+	for _, group := range groups {
+		rules = append(rules, rbacv1.PolicyRule{
+			Verbs:     memberClusterTargetVerbs,
+			APIGroups: []string{group},
+			Resources: sets.List(resourcesByGroup[group]),
+		})
+	}
+	rules = append(rules, memberClusterReadOnlyRules...)
+	return append(rules, memberClusterServiceImportRules...)
+}
+
+// ensureMemberSystemNamespaceRole creates or updates the role and role binding that grant memberSystemNamespaceRules to
+// the service account in the system namespace of a member cluster.
+func ensureMemberSystemNamespaceRole(ctx context.Context, clientset kubeclient.Interface, namespace, saName string) error {
+	roleName := memberClusterRoleName(saName)
+
+	role, err := clientset.RbacV1().Roles(namespace).Get(ctx, roleName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		role = &rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{Name: roleName, Namespace: namespace},
+			Rules:      memberSystemNamespaceRules,
+		}
+		if _, err := clientset.RbacV1().Roles(namespace).Create(ctx, role, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create role %s/%s: %w", namespace, roleName, err)
+		}
+	case err != nil:
+		return fmt.Errorf("failed to get role %s/%s: %w", namespace, roleName, err)
+	case !reflect.DeepEqual(role.Rules, memberSystemNamespaceRules):
+		role.Rules = memberSystemNamespaceRules
+		if _, err := clientset.RbacV1().Roles(namespace).Update(ctx, role, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update role %s/%s: %w", namespace, roleName, err)
+		}
+	}
+
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: roleName, Namespace: namespace},
+		Subjects:   bindingSubjects(saName, namespace),
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     roleName,
+		},
+	}
+	existingBinding, err := clientset.RbacV1().RoleBindings(namespace).Get(ctx, roleName, metav1.GetOptions{})
+	switch {
+	case err != nil && !apierrors.IsNotFound(err):
+		return fmt.Errorf("failed to get role binding %s/%s: %w", namespace, roleName, err)
+	case err == nil && reflect.DeepEqual(existingBinding.RoleRef, binding.RoleRef):
+		if reflect.DeepEqual(existingBinding.Subjects, binding.Subjects) {
+			return nil
+		}
+		existingBinding.Subjects = binding.Subjects
+		if _, err := clientset.RbacV1().RoleBindings(namespace).Update(ctx, existingBinding, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update role binding %s/%s: %w", namespace, roleName, err)
+		}
+		return nil
+	case err == nil:
+		// The roleRef cannot be updated, therefore the binding must be recreated.
+		err := clientset.RbacV1().RoleBindings(namespace).Delete(ctx, roleName, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete role binding %s/%s: %w", namespace, roleName, err)
+		}
+	}
+	if _, err := clientset.RbacV1().RoleBindings(namespace).Create(ctx, binding, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create role binding %s/%s: %w", namespace, roleName, err)
+	}
+	return nil
+}
+
+func (c *FederatedClusterController) getMemberClusterRoleRules() ([]rbacv1.PolicyRule, error) {
+	typeConfigs, err := c.typeConfigLister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list FederatedTypeConfigs: %w", err)
+	}
+	return getMemberClusterRoleRules(typeConfigs), nil
+}
+
+// syncClusterRole keeps the cluster role of the service account in a joined member cluster up to date with the
+// FederatedTypeConfigs, and ensures the role of the service account in the system namespace of the member cluster.
+func (c *FederatedClusterController) syncClusterRole(qualifiedName common.QualifiedName) (status worker.Result) {
+	logger := c.logger.WithValues("control-loop", "cluster-role-sync", "object", qualifiedName.String())
+	ctx := klog.NewContext(context.TODO(), logger)
+	startTime := time.Now()
+
+	logger.V(3).Info("Starting cluster role sync")
+	defer func() {
+		logger.WithValues("duration", time.Since(startTime), "status", status.String()).V(3).Info("Finished cluster role sync")
+	}()
+
+	cluster, err := c.clusterLister.Get(qualifiedName.Name)
+	if err != nil && apierrors.IsNotFound(err) {
+		return worker.StatusAllOK
+	}
+	if err != nil {
+		logger.Error(err, "Failed to get cluster from store")
+		return worker.StatusError
+	}
+
+	// The cluster role is only created if the control plane accesses the cluster with the member service account.
+	if !cluster.Spec.UseServiceAccountToken ||
+		util.IsPullModeCluster(cluster) ||
+		cluster.GetDeletionTimestamp() != nil ||
+		!cluster.Status.JoinPerformed ||
+		!util.IsClusterJoined(&cluster.Status) {
+		return worker.StatusAllOK
+	}
+
+	rules, err := c.getMemberClusterRoleRules()
+	if err != nil {
+		logger.Error(err, "Failed to compute cluster role rules")
+		return worker.StatusError
+	}
+
+	_, clusterKubeClient, err := getClusterClient(ctx, c.kubeClient, c.fedSystemNamespace, cluster, c.credentialProviderPolicy)
+	if err != nil {
+		logger.Error(err, "Failed to get cluster client")
+		return worker.StatusError
+	}
+
+	if err := ensureMemberSystemNamespaceRole(ctx, clusterKubeClient, c.fedSystemNamespace, MemberServiceAccountName); err != nil {
+		logger.Error(err, "Failed to sync role in system namespace")
+		return worker.StatusError
+	}
+
+	roleName := memberClusterRoleName(MemberServiceAccountName)
+	role, err := clusterKubeClient.RbacV1().ClusterRoles().Get(ctx, roleName, metav1.GetOptions{})
+	if err != nil {
+		logger.Error(err, "Failed to get cluster role")
+		return worker.StatusError
+	}
+	if reflect.DeepEqual(role.Rules, rules) {
+		return worker.StatusAllOK
+	}
+
+	logger.V(2).Info("Updating cluster role")
+	role.Rules = rules
+	if _, err := clusterKubeClient.RbacV1().ClusterRoles().Update(ctx, role, metav1.UpdateOptions{}); err != nil {
+		if apierrors.IsConflict(err) {
+			return worker.StatusConflict
+		}
+		logger.Error(err, "Failed to update cluster role")
+		return worker.StatusError
+	}
+	return worker.StatusAllOK
+}
+
+func (c *FederatedClusterController) enqueueAllClusterRoles() {
+	clusters, err := c.clusterLister.List(labels.Everything())
+	if err != nil {
+		c.logger.Error(err, "Failed to enqueue all clusters for cluster role sync")
+		return
+	}
+
+	for _, cluster := range clusters {
+		if util.IsClusterJoined(&cluster.Status) {
+			c.clusterRoleWorker.EnqueueObject(cluster)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedcluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+func newTypeConfig(group, pluralName string) *fedcorev1a1.FederatedTypeConfig {
+	return &fedcorev1a1.FederatedTypeConfig{
+		Spec: fedcorev1a1.FederatedTypeConfigSpec{
+			TargetType: fedcorev1a1.APIResource{Group: group, PluralName: pluralName},
+		},
+	}
+}
+
+func TestGetMemberClusterRoleRules(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	rules := getMemberClusterRoleRules([]*fedcorev1a1.FederatedTypeConfig{
+		newTypeConfig("apps", "statefulsets"),
+		newTypeConfig("", "configmaps"),
+		newTypeConfig("apps", "deployments"),
+		newTypeConfig("", "secrets"),
+	})
+
+	expected := []rbacv1.PolicyRule{
+		{
+			Verbs:     memberClusterTargetVerbs,
+			APIGroups: []string{""},
+			Resources: []string{"configmaps", "secrets"},
+		},
+		{
+			Verbs:     memberClusterTargetVerbs,
+			APIGroups: []string{"apps"},
+			Resources: []string{"deployments", "statefulsets"},
+		},
+	}
+	expected = append(expected, memberClusterReadOnlyRules...)
+	expected = append(expected, memberClusterServiceImportRules...)
+	g.Expect(rules).To(gomega.Equal(expected))
+
+	g.Expect(getMemberClusterRoleRules(nil)).To(gomega.Equal(
+		append(append([]rbacv1.PolicyRule{}, memberClusterReadOnlyRules...), memberClusterServiceImportRules...),
+	))
+	for _, rule := range rules {
+		g.Expect(rule.Resources).NotTo(gomega.ContainElement(rbacv1.ResourceAll))
+		g.Expect(rule.Verbs).NotTo(gomega.ContainElement(rbacv1.VerbAll))
+	}
+}
+
+func TestEnsureMemberSystemNamespaceRole(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctx := context.Background()
+	const namespace = "kube-admiral-system"
+	roleName := memberClusterRoleName(MemberServiceAccountName)
+
+	// token rotation must work with any set of FederatedTypeConfigs, including none
+	for _, rule := range getMemberClusterRoleRules(nil) {
+		g.Expect(rule.Resources).NotTo(gomega.ContainElement("secrets"))
+	}
+	g.Expect(memberSystemNamespaceRules).To(gomega.ContainElement(rbacv1.PolicyRule{
+		Verbs:     []string{"get", "list", "create", "delete"},
+		APIGroups: []string{""},
+		Resources: []string{"secrets"},
+	}))
+
+	client := fake.NewSimpleClientset(&rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: roleName, Namespace: namespace},
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "view"},
+	})
+	g.Expect(ensureMemberSystemNamespaceRole(ctx, client, namespace, MemberServiceAccountName)).To(gomega.Succeed())
+
+	role, err := client.RbacV1().Roles(namespace).Get(ctx, roleName, metav1.GetOptions{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(role.Rules).To(gomega.Equal(memberSystemNamespaceRules))
+
+	binding, err := client.RbacV1().RoleBindings(namespace).Get(ctx, roleName, metav1.GetOptions{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(binding.RoleRef).To(gomega.Equal(rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: roleName}))
+	g.Expect(binding.Subjects).To(gomega.Equal(bindingSubjects(MemberServiceAccountName, namespace)))
+
+	// modified rules are restored
+	role.Rules = nil
+	_, err = client.RbacV1().Roles(namespace).Update(ctx, role, metav1.UpdateOptions{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(ensureMemberSystemNamespaceRole(ctx, client, namespace, MemberServiceAccountName)).To(gomega.Succeed())
+	role, err = client.RbacV1().Roles(namespace).Get(ctx, roleName, metav1.GetOptions{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(role.Rules).To(gomega.Equal(memberSystemNamespaceRules))
+}
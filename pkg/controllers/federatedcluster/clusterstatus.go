@@ -33,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/informers"
+	kubeclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
@@ -55,6 +56,12 @@ const (
 	ClusterAPIDiscoveryFailedReason          = "ClusterAPIDiscoveryFailed"
 	ClusterAPIDiscoveryFailedMessageTemplate = "Failed to discover cluster API resources: %v"
 
+	ClusterAgentNotReportingReason  = "ClusterAgentNotReporting"
+	ClusterAgentNotReportingMessage = "Cluster agent has stopped reporting cluster status"
+
+	ClusterJoinedByAgentReason  = "ClusterJoinedByAgent"
+	ClusterJoinedByAgentMessage = "cluster has joined the federation through its agent"
+
 	ClusterReachableReason    = "ClusterReachable"
 	ClusterReachableMsg       = "Cluster is reachable"
 	ClusterNotReachableReason = "ClusterNotReachable"
@@ -66,9 +73,9 @@ func collectIndividualClusterStatus(
 	cluster *fedcorev1a1.FederatedCluster,
 	fedClient fedclient.Interface,
 	federatedClient federatedclient.FederatedClientFactory,
+	typeConfigs []*fedcorev1a1.FederatedTypeConfig,
+	healthCheckConfig *ClusterHealthCheckConfig,
 ) error {
-	logger := klog.FromContext(ctx)
-
 	clusterKubeClient, exists, err := federatedClient.KubeClientsetForCluster(cluster.Name)
 	if !exists {
 		return fmt.Errorf("federated client is not yet up to date")
@@ -84,12 +91,59 @@ func collectIndividualClusterStatus(
 		return fmt.Errorf("failed to get federated kube informer factory: %w", err)
 	}
 
-	discoveryClient := clusterKubeClient.Discovery()
+	clusterDynamicClient, exists, err := federatedClient.DynamicClientsetForCluster(cluster.Name)
+	if !exists {
+		return fmt.Errorf("federated client is not yet up to date")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get federated dynamic client: %w", err)
+	}
+
 	cluster = cluster.DeepCopy()
+	CollectClusterStatus(
+		ctx,
+		&cluster.Status,
+		clusterKubeClient,
+		clusterKubeInformer,
+		healthCheckConfig.ProbeConfigForCluster(cluster),
+	)
+	if err := CollectClusterMaintenanceStatus(
+		ctx, cluster, &cluster.Status, clusterDynamicClient, typeConfigs,
+	); err != nil {
+		klog.FromContext(ctx).Error(err, "Failed to collect cluster maintenance status")
+	}
+	updateClusterStability(&cluster.Status, healthCheckConfig.FlapDetection, time.Now())
+
+	if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		latestCluster, err := fedClient.CoreV1alpha1().FederatedClusters().Get(context.TODO(), cluster.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		cluster.Status.DeepCopyInto(&latestCluster.Status)
+		_, err = fedClient.CoreV1alpha1().FederatedClusters().UpdateStatus(context.TODO(), latestCluster, metav1.UpdateOptions{})
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to update cluster status: %w", err)
+	}
+
+	return nil
+}
+
+// CollectClusterStatus collects the health, resources and API resources of a member cluster into the given status.
+// It is shared by the federated cluster controller and the agent of pull-mode clusters.
+func CollectClusterStatus(
+	ctx context.Context,
+	clusterStatus *fedcorev1a1.FederatedClusterStatus,
+	clusterKubeClient kubeclient.Interface,
+	clusterKubeInformer informers.SharedInformerFactory,
+	probeConfig ClusterHealthProbeConfig,
+) {
+	logger := klog.FromContext(ctx)
 
+	discoveryClient := clusterKubeClient.Discovery()
 	conditionTime := metav1.Now()
 
-	offlineStatus, readyStatus := checkReadyByHealthz(ctx, discoveryClient)
+	readyStatus, latency := checkReadyByHealthz(ctx, discoveryClient, probeConfig.Path, probeConfig.Timeout)
 	var readyReason, readyMessage string
 	switch readyStatus {
 	case corev1.ConditionTrue:
@@ -105,33 +159,83 @@ func collectIndividualClusterStatus(
 
 	// we skip updating cluster resources and api resources if cluster is not ready
 	if readyStatus == corev1.ConditionTrue {
-		if err := updateClusterResources(ctx, &cluster.Status, clusterKubeInformer); err != nil {
+		if err := updateClusterResources(ctx, clusterStatus, clusterKubeInformer); err != nil {
 			logger.Error(err, "Failed to update cluster resources")
 			readyStatus = corev1.ConditionFalse
 			readyReason = ClusterResourceCollectionFailedReason
 			readyMessage = fmt.Sprintf(ClusterResourceCollectionFailedMessageTemplate, err.Error())
-		} else if err := updateClusterAPIResources(ctx, &cluster.Status, discoveryClient); err != nil {
+		} else if err := updateClusterAPIResources(ctx, clusterStatus, discoveryClient); err != nil {
 			logger.Error(err, "Failed to update cluster api resources")
 			readyStatus = corev1.ConditionFalse
 			readyReason = ClusterAPIDiscoveryFailedReason
 			readyMessage = fmt.Sprintf(ClusterAPIDiscoveryFailedMessageTemplate, err.Error())
+		} else if reason, message := runExtraHealthChecks(clusterStatus, probeConfig); len(reason) > 0 {
+			readyStatus = corev1.ConditionFalse
+			readyReason = reason
+			readyMessage = message
 		}
 	}
 
-	offlineCondition := getNewClusterOfflineCondition(offlineStatus, conditionTime)
-	setClusterCondition(&cluster.Status, &offlineCondition)
-	readyCondition := getNewClusterReadyCondition(readyStatus, readyReason, readyMessage, conditionTime)
-	setClusterCondition(&cluster.Status, &readyCondition)
+	applyClusterHealthProbe(clusterStatus, fedcorev1a1.ClusterHealthProbe{
+		Time:               conditionTime,
+		Status:             readyStatus,
+		Reason:             readyReason,
+		Message:            readyMessage,
+		HealthCheckLatency: &metav1.Duration{Duration: latency},
+	}, probeConfig.SuccessThreshold, probeConfig.FailureThreshold)
+}
 
-	if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-		latestCluster, err := fedClient.CoreV1alpha1().FederatedClusters().Get(context.TODO(), cluster.Name, metav1.GetOptions{})
-		if err != nil {
-			return err
-		}
-		cluster.Status.DeepCopyInto(&latestCluster.Status)
-		_, err = fedClient.CoreV1alpha1().FederatedClusters().UpdateStatus(context.TODO(), latestCluster, metav1.UpdateOptions{})
-		return err
-	}); err != nil {
+// SetClusterJoinedByAgent marks a pull-mode cluster as joined. Pull-mode clusters are joined by their agents instead
+// of the federated cluster controller.
+func SetClusterJoinedByAgent(clusterStatus *fedcorev1a1.FederatedClusterStatus) {
+	if joined, _ := isClusterJoined(clusterStatus); joined {
+		return
+	}
+
+	conditionTime := metav1.Now()
+	setClusterCondition(clusterStatus, &fedcorev1a1.ClusterCondition{
+		Type:               fedcorev1a1.ClusterJoined,
+		Status:             corev1.ConditionTrue,
+		Reason:             ClusterJoinedByAgentReason,
+		Message:            ClusterJoinedByAgentMessage,
+		LastProbeTime:      conditionTime,
+		LastTransitionTime: conditionTime,
+	})
+}
+
+// updatePullClusterStatus marks a pull-mode cluster as not ready if its agent has not reported the cluster's status
+// within the timeout.
+func updatePullClusterStatus(
+	ctx context.Context,
+	cluster *fedcorev1a1.FederatedCluster,
+	fedClient fedclient.Interface,
+	agentStatusTimeout time.Duration,
+) error {
+	readyCond := getClusterCondition(&cluster.Status, fedcorev1a1.ClusterReady)
+	if readyCond == nil || readyCond.Status == corev1.ConditionUnknown ||
+		time.Since(readyCond.LastProbeTime.Time) < agentStatusTimeout {
+		return nil
+	}
+
+	klog.FromContext(ctx).V(1).Info("Cluster agent stopped reporting status, marking cluster as not ready")
+
+	cluster = cluster.DeepCopy()
+	conditionTime := metav1.Now()
+	// The agent applies the thresholds to its own probes, the timeout takes effect immediately.
+	applyClusterHealthProbe(&cluster.Status, fedcorev1a1.ClusterHealthProbe{
+		Time:    conditionTime,
+		Status:  corev1.ConditionUnknown,
+		Reason:  ClusterAgentNotReportingReason,
+		Message: ClusterAgentNotReportingMessage,
+	}, 1, 1)
+	// Keep the last probe time so that the time of the last report from the agent is preserved.
+	readyCondition := getClusterCondition(&cluster.Status, fedcorev1a1.ClusterReady)
+	readyCondition.LastProbeTime = readyCond.LastProbeTime
+	setClusterCondition(&cluster.Status, readyCondition)
+
+	if _, err := fedClient.CoreV1alpha1().FederatedClusters().UpdateStatus(
+		ctx, cluster, metav1.UpdateOptions{},
+	); err != nil {
 		return fmt.Errorf("failed to update cluster status: %w", err)
 	}
 
@@ -141,13 +245,17 @@ func collectIndividualClusterStatus(
 func checkReadyByHealthz(
 	ctx context.Context,
 	clusterDiscoveryClient discovery.DiscoveryInterface,
-) (offline, ready corev1.ConditionStatus) {
+	path string,
+	timeout time.Duration,
+) (ready corev1.ConditionStatus, latency time.Duration) {
 	logger := klog.FromContext(ctx)
 
-	body, err := clusterDiscoveryClient.RESTClient().Get().AbsPath("/healthz").Timeout(30 * time.Second).Do(ctx).Raw()
+	startTime := time.Now()
+	body, err := clusterDiscoveryClient.RESTClient().Get().AbsPath(path).Timeout(timeout).Do(ctx).Raw()
+	latency = time.Since(startTime)
 	if err != nil {
 		logger.Error(err, "Cluster health check failed")
-		return corev1.ConditionTrue, corev1.ConditionUnknown
+		return corev1.ConditionUnknown, latency
 	}
 
 	var clusterReadyStatus corev1.ConditionStatus
@@ -156,7 +264,7 @@ func checkReadyByHealthz(
 	} else {
 		clusterReadyStatus = corev1.ConditionFalse
 	}
-	return corev1.ConditionFalse, clusterReadyStatus
+	return clusterReadyStatus, latency
 }
 
 func updateClusterResources(
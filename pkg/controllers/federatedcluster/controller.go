@@ -30,6 +30,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -65,7 +66,22 @@ const (
 
 // ClusterHealthCheckConfig defines the configurable parameters for cluster health check
 type ClusterHealthCheckConfig struct {
+	// Period is the default interval between health checks of a cluster.
 	Period time.Duration
+	// AgentStatusTimeout is the time after which a pull-mode cluster is considered not ready if its agent has not
+	// reported the cluster's status.
+	AgentStatusTimeout time.Duration
+	// FlapDetection defines when a cluster whose readiness changes frequently is marked unstable.
+	FlapDetection FlapDetectionConfig
+
+	// Path is the default health endpoint of the clusters' apiservers.
+	Path string
+	// Timeout is the default timeout of health check requests.
+	Timeout time.Duration
+	// SuccessThreshold is the default number of consecutive successful probes after which a cluster becomes ready.
+	SuccessThreshold int
+	// FailureThreshold is the default number of consecutive failed probes after which a cluster becomes not ready.
+	FailureThreshold int
 }
 
 // FederatedClusterController reconciles a FederatedCluster object
@@ -73,13 +89,17 @@ type FederatedClusterController struct {
 	client     fedclient.Interface
 	kubeClient kubeclient.Interface
 
-	clusterLister   fedcorev1a1listers.FederatedClusterLister
-	clusterSynced   cache.InformerSynced
-	federatedClient federatedclient.FederatedClientFactory
+	clusterLister    fedcorev1a1listers.FederatedClusterLister
+	clusterSynced    cache.InformerSynced
+	typeConfigLister fedcorev1a1listers.FederatedTypeConfigLister
+	typeConfigSynced cache.InformerSynced
+	federatedClient  federatedclient.FederatedClientFactory
 
 	fedSystemNamespace       string
 	clusterHealthCheckConfig *ClusterHealthCheckConfig
 	clusterJoinTimeout       time.Duration
+	tokenRotationConfig      *TokenRotationConfig
+	credentialProviderPolicy *util.CredentialProviderPolicy
 
 	eventRecorder record.EventRecorder
 	metrics       stats.Metrics
@@ -87,33 +107,47 @@ type FederatedClusterController struct {
 
 	worker              worker.ReconcileWorker
 	statusCollectWorker worker.ReconcileWorker
+	tokenRotationWorker worker.ReconcileWorker
+	clusterRoleWorker   worker.ReconcileWorker
 }
 
 func NewFederatedClusterController(
 	client fedclient.Interface,
 	kubeClient kubeclient.Interface,
 	informer fedcorev1a1informers.FederatedClusterInformer,
+	typeConfigInformer fedcorev1a1informers.FederatedTypeConfigInformer,
 	federatedClient federatedclient.FederatedClientFactory,
 	metrics stats.Metrics,
 	fedsystemNamespace string,
 	restConfig *rest.Config,
-	workerCount int,
+	workerConfig worker.ConfigSource,
 	clusterJoinTimeout time.Duration,
+	tokenRotationPeriod time.Duration,
+	healthCheckConfig *ClusterHealthCheckConfig,
+	credentialProviderPolicy *util.CredentialProviderPolicy,
 ) (*FederatedClusterController, error) {
+	if healthCheckConfig == nil {
+		healthCheckConfig = DefaultClusterHealthCheckConfig()
+	}
+
 	c := &FederatedClusterController{
-		client:             client,
-		kubeClient:         kubeClient,
-		clusterLister:      informer.Lister(),
-		clusterSynced:      informer.Informer().HasSynced,
-		federatedClient:    federatedClient,
-		fedSystemNamespace: fedsystemNamespace,
-		clusterHealthCheckConfig: &ClusterHealthCheckConfig{
-			// TODO: make health check period configurable
-			Period: time.Second * 30,
+		client:                   client,
+		kubeClient:               kubeClient,
+		clusterLister:            informer.Lister(),
+		clusterSynced:            informer.Informer().HasSynced,
+		typeConfigLister:         typeConfigInformer.Lister(),
+		typeConfigSynced:         typeConfigInformer.Informer().HasSynced,
+		federatedClient:          federatedClient,
+		fedSystemNamespace:       fedsystemNamespace,
+		clusterHealthCheckConfig: healthCheckConfig,
+		clusterJoinTimeout:       clusterJoinTimeout,
+		tokenRotationConfig: &TokenRotationConfig{
+			Period:          tokenRotationPeriod,
+			RevocationDelay: time.Minute * 5,
 		},
-		clusterJoinTimeout: clusterJoinTimeout,
-		metrics:            metrics,
-		logger:             klog.LoggerWithValues(klog.Background(), "controller", ControllerName),
+		credentialProviderPolicy: credentialProviderPolicy,
+		metrics:                  metrics,
+		logger:                   klog.LoggerWithValues(klog.Background(), "controller", ControllerName),
 	}
 
 	broadcaster := record.NewBroadcaster()
@@ -126,25 +160,41 @@ func NewFederatedClusterController(
 		corev1.EventSource{Component: ("federatedcluster-controller")},
 	)
 
-	c.worker = worker.NewReconcileWorker(
+	c.worker = worker.NewConfigurableReconcileWorker(
 		c.reconcile,
 		worker.WorkerTiming{},
-		workerCount,
+		workerConfig,
 		metrics,
 		delayingdeliver.NewMetricTags("federatedcluster-worker", "FederatedCluster"),
 	)
 
-	c.statusCollectWorker = worker.NewReconcileWorker(
+	c.statusCollectWorker = worker.NewConfigurableReconcileWorker(
 		c.collectClusterStatus,
 		worker.WorkerTiming{
 			Interval:       50 * time.Millisecond,
 			InitialBackoff: 50 * time.Millisecond,
 		},
-		workerCount,
+		workerConfig,
 		metrics,
 		delayingdeliver.NewMetricTags("federatedcluster-status-collect-worker", "FederatedCluster"),
 	)
 
+	c.tokenRotationWorker = worker.NewConfigurableReconcileWorker(
+		c.rotateClusterToken,
+		worker.WorkerTiming{},
+		workerConfig,
+		metrics,
+		delayingdeliver.NewMetricTags("federatedcluster-token-rotation-worker", "FederatedCluster"),
+	)
+
+	c.clusterRoleWorker = worker.NewConfigurableReconcileWorker(
+		c.syncClusterRole,
+		worker.WorkerTiming{},
+		workerConfig,
+		metrics,
+		delayingdeliver.NewMetricTags("federatedcluster-cluster-role-worker", "FederatedCluster"),
+	)
+
 	informer.Informer().
 		AddEventHandler(util.NewTriggerOnGenerationAndMetadataChanges(c.worker.EnqueueObject,
 			func(oldMeta, newMeta metav1.Object) bool {
@@ -155,11 +205,16 @@ func NewFederatedClusterController(
 				return false
 			}))
 
+	// keep the cluster roles of member clusters up to date with the federated types
+	typeConfigInformer.Informer().AddEventHandler(util.NewTriggerOnAllChanges(func(_ pkgruntime.Object) {
+		c.enqueueAllClusterRoles()
+	}))
+
 	return c, nil
 }
 
 func (c *FederatedClusterController) IsControllerReady() bool {
-	return c.clusterSynced()
+	return c.clusterSynced() && c.typeConfigSynced()
 }
 
 func (c *FederatedClusterController) Run(ctx context.Context) {
@@ -168,12 +223,17 @@ func (c *FederatedClusterController) Run(ctx context.Context) {
 	c.logger.Info("Starting controller")
 	defer c.logger.Info("Stopping controller")
 
-	if !cache.WaitForNamedCacheSync("federated-controller", ctx.Done(), c.clusterSynced) {
+	if !cache.WaitForNamedCacheSync("federated-controller", ctx.Done(), c.clusterSynced, c.typeConfigSynced) {
 		return
 	}
 
 	c.worker.Run(ctx.Done())
 	c.statusCollectWorker.Run(ctx.Done())
+	c.tokenRotationWorker.Run(ctx.Done())
+	c.clusterRoleWorker.Run(ctx.Done())
+
+	// ensure the cluster roles of member clusters joined before the FederatedTypeConfigs were last changed are up to date
+	c.enqueueAllClusterRoles()
 
 	// periodically enqueue all clusters to trigger status collection
 	go wait.Until(c.enqueueAllJoinedClusters, c.clusterHealthCheckConfig.Period, ctx.Done())
@@ -213,6 +273,7 @@ func (c *FederatedClusterController) reconcile(qualifiedName common.QualifiedNam
 			c.kubeClient,
 			c.eventRecorder,
 			c.fedSystemNamespace,
+			c.credentialProviderPolicy,
 		)
 		if err != nil {
 			if apierrors.IsConflict(err) {
@@ -237,6 +298,17 @@ func (c *FederatedClusterController) reconcile(qualifiedName common.QualifiedNam
 		return worker.StatusAllOK
 	}
 
+	if util.IsPullModeCluster(cluster) {
+		// pull-mode clusters are joined by their agents
+		return worker.StatusAllOK
+	}
+
+	clusterRoleRules, err := c.getMemberClusterRoleRules()
+	if err != nil {
+		logger.Error(err, "Failed to compute cluster role rules")
+		return worker.StatusError
+	}
+
 	// not joined yet and not failed, so we try to join
 	logger.V(2).Info("Handle unjoined cluster")
 	cluster, newCondition, newJoinPerformed, err := handleNotJoinedCluster(
@@ -247,6 +319,8 @@ func (c *FederatedClusterController) reconcile(qualifiedName common.QualifiedNam
 		c.eventRecorder,
 		c.fedSystemNamespace,
 		c.clusterJoinTimeout,
+		clusterRoleRules,
+		c.credentialProviderPolicy,
 	)
 
 	needsUpdate := false
@@ -319,13 +393,46 @@ func (c *FederatedClusterController) collectClusterStatus(qualifiedName common.Q
 	}
 
 	cluster = cluster.DeepCopy()
-	if shouldCollectClusterStatus(cluster, c.clusterHealthCheckConfig.Period) {
-		if err := collectIndividualClusterStatus(ctx, cluster, c.client, c.federatedClient); err != nil {
+	if util.IsPullModeCluster(cluster) {
+		// the status of pull-mode clusters is reported by their agents, we only need to detect missing reports
+		if err := updatePullClusterStatus(ctx, cluster, c.client, c.clusterHealthCheckConfig.AgentStatusTimeout); err != nil {
+			if apierrors.IsConflict(err) {
+				return worker.StatusConflict
+			}
+			logger.Error(err, "Failed to update pull-mode cluster status")
+			return worker.StatusError
+		}
+		if err := updatePullClusterStability(ctx, cluster, c.client, c.clusterHealthCheckConfig.FlapDetection); err != nil {
+			logger.Error(err, "Failed to update pull-mode cluster stability")
+			return worker.StatusError
+		}
+		return worker.StatusAllOK
+	}
+
+	period := c.clusterHealthCheckConfig.PeriodForCluster(cluster)
+	if shouldCollectClusterStatus(cluster, period) {
+		typeConfigs, err := c.typeConfigLister.List(labels.Everything())
+		if err != nil {
+			logger.Error(err, "Failed to list FederatedTypeConfigs")
+			return worker.StatusError
+		}
+		if err := collectIndividualClusterStatus(
+			ctx,
+			cluster,
+			c.client,
+			c.federatedClient,
+			typeConfigs,
+			c.clusterHealthCheckConfig,
+		); err != nil {
 			logger.Error(err, "Failed to collect cluster status")
 			return worker.StatusError
 		}
 	}
 
+	if period != c.clusterHealthCheckConfig.Period {
+		// clusters are only enqueued periodically at the default period
+		return worker.Result{Success: true, RequeueAfter: &period}
+	}
 	return worker.StatusAllOK
 }
 
@@ -358,6 +465,7 @@ func handleTerminatingCluster(
 	kubeClient kubeclient.Interface,
 	eventRecorder record.EventRecorder,
 	fedSystemNamespace string,
+	credentialProviderPolicy *util.CredentialProviderPolicy,
 ) error {
 	finalizers := sets.New(cluster.GetFinalizers()...)
 	if !finalizers.Has(FinalizerFederatedClusterController) {
@@ -379,7 +487,13 @@ func handleTerminatingCluster(
 
 	// Only perform clean-up if we made any effectual changes to the cluster during join.
 	if cluster.Status.JoinPerformed {
-		clusterSecret, clusterKubeClient, err := getClusterClient(ctx, kubeClient, fedSystemNamespace, cluster)
+		clusterSecret, clusterKubeClient, err := getClusterClient(
+			ctx,
+			kubeClient,
+			fedSystemNamespace,
+			cluster,
+			credentialProviderPolicy,
+		)
 		if err != nil {
 			eventRecorder.Eventf(
 				cluster,
@@ -450,6 +564,7 @@ func getClusterClient(
 	hostClient kubeclient.Interface,
 	fedSystemNamespace string,
 	cluster *fedcorev1a1.FederatedCluster,
+	credentialProviderPolicy *util.CredentialProviderPolicy,
 ) (*corev1.Secret, kubeclient.Interface, error) {
 	restConfig := &rest.Config{Host: cluster.Spec.APIEndpoint}
 
@@ -463,10 +578,25 @@ func getClusterClient(
 		return nil, nil, fmt.Errorf("failed to get cluster secret: %w", err)
 	}
 
-	if err := util.PopulateAuthDetailsFromSecret(restConfig, cluster.Spec.Insecure, clusterSecret, false); err != nil {
+	if err := util.PopulateAuthDetails(
+		restConfig,
+		cluster,
+		clusterSecret,
+		false,
+		credentialProviderPolicy,
+		util.NewOIDCTokenPersister(hostClient, fedSystemNamespace, clusterSecretName),
+	); err != nil {
 		return nil, nil, fmt.Errorf("cluster secret malformed: %w", err)
 	}
 
+	proxyHeadersSecret, err := util.GetProxyHeadersSecret(ctx, hostClient, fedSystemNamespace, cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := util.PopulateProxyDetails(restConfig, cluster.Spec.Proxy, proxyHeadersSecret); err != nil {
+		return nil, nil, fmt.Errorf("failed to configure proxy: %w", err)
+	}
+
 	clusterClient, err := kubeclient.NewForConfig(restConfig)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create cluster kube clientset: %w", err)
@@ -484,6 +614,9 @@ func (c *FederatedClusterController) enqueueAllJoinedClusters() {
 	for _, cluster := range clusters {
 		if util.IsClusterJoined(&cluster.Status) {
 			c.statusCollectWorker.EnqueueObject(cluster)
+			if c.tokenRotationConfig.Period > 0 {
+				c.tokenRotationWorker.EnqueueObject(cluster)
+			}
 		}
 	}
 }
@@ -0,0 +1,192 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedcluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedclient "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned"
+)
+
+const (
+	// ClusterHealthProbeHistoryLimit is the number of health probes kept in the health history of a cluster.
+	ClusterHealthProbeHistoryLimit = 10
+	// ClusterHealthTransitionHistoryLimit is the number of transitions kept in the health history of a cluster.
+	ClusterHealthTransitionHistoryLimit = 10
+
+	ClusterFlappingReason          = "ClusterFlapping"
+	ClusterFlappingMessageTemplate = "Cluster readiness changed %d times in the last %s"
+
+	ClusterStableReason  = "ClusterStable"
+	ClusterStableMessage = "Cluster readiness is stable"
+)
+
+// FlapDetectionConfig defines when a cluster is considered unstable.
+type FlapDetectionConfig struct {
+	// Window is the period in which transitions of the Ready condition are counted.
+	Window time.Duration
+	// Threshold is the number of transitions within the window at which the cluster is considered unstable.
+	// Flap detection is disabled if it is 0.
+	Threshold int
+}
+
+// applyClusterHealthProbe adds the probe to the health history of the cluster and updates its Ready and Offline
+// conditions. The Ready condition only switches between ready and not ready once the number of consecutive successful
+// or failed probes reaches the success or failure threshold respectively. A transition is recorded in the health
+// history whenever the status of the Ready condition changes.
+func applyClusterHealthProbe(
+	clusterStatus *fedcorev1a1.FederatedClusterStatus,
+	probe fedcorev1a1.ClusterHealthProbe,
+	successThreshold, failureThreshold int,
+) {
+	if clusterStatus.Health == nil {
+		clusterStatus.Health = &fedcorev1a1.ClusterHealth{}
+	}
+	health := clusterStatus.Health
+
+	health.Probes = prependWithLimit(health.Probes, probe, ClusterHealthProbeHistoryLimit)
+
+	status, reason, message := probe.Status, probe.Reason, probe.Message
+	oldCondition := getClusterCondition(clusterStatus, fedcorev1a1.ClusterReady)
+	if oldCondition != nil {
+		probeSucceeded := probe.Status == corev1.ConditionTrue
+		threshold := failureThreshold
+		if probeSucceeded {
+			threshold = successThreshold
+		}
+		if (oldCondition.Status == corev1.ConditionTrue) != probeSucceeded &&
+			countConsecutiveProbes(health.Probes, probeSucceeded) < threshold {
+			status, reason, message = oldCondition.Status, oldCondition.Reason, oldCondition.Message
+		}
+
+		if oldCondition.Status != status {
+			health.Transitions = prependWithLimit(health.Transitions, fedcorev1a1.ClusterHealthTransition{
+				Time:   probe.Time,
+				From:   oldCondition.Status,
+				To:     status,
+				Reason: reason,
+			}, ClusterHealthTransitionHistoryLimit)
+		}
+	}
+
+	offlineStatus := corev1.ConditionFalse
+	if status == corev1.ConditionUnknown {
+		offlineStatus = corev1.ConditionTrue
+	}
+	offlineCondition := getNewClusterOfflineCondition(offlineStatus, probe.Time)
+	updateClusterCondition(clusterStatus, &offlineCondition)
+	readyCondition := getNewClusterReadyCondition(status, reason, message, probe.Time)
+	updateClusterCondition(clusterStatus, &readyCondition)
+}
+
+// countConsecutiveProbes returns the number of most recent probes that all succeeded or all failed.
+func countConsecutiveProbes(probes []fedcorev1a1.ClusterHealthProbe, succeeded bool) int {
+	count := 0
+	for _, probe := range probes {
+		if (probe.Status == corev1.ConditionTrue) != succeeded {
+			break
+		}
+		count++
+	}
+	return count
+}
+
+func prependWithLimit[T any](items []T, item T, limit int) []T {
+	result := make([]T, 0, limit)
+	result = append(result, item)
+	for i := 0; i < len(items) && len(result) < limit; i++ {
+		result = append(result, items[i])
+	}
+	return result
+}
+
+// updateClusterStability sets the Unstable condition of the cluster according to the number of recent transitions of
+// its Ready condition. It returns true if the condition was changed.
+func updateClusterStability(
+	clusterStatus *fedcorev1a1.FederatedClusterStatus,
+	config FlapDetectionConfig,
+	now time.Time,
+) bool {
+	if config.Threshold <= 0 || clusterStatus.Health == nil {
+		return false
+	}
+
+	recentTransitions := 0
+	for _, transition := range clusterStatus.Health.Transitions {
+		if now.Sub(transition.Time.Time) <= config.Window {
+			recentTransitions++
+		}
+	}
+
+	newCondition := &fedcorev1a1.ClusterCondition{
+		Type:               fedcorev1a1.ClusterUnstable,
+		Status:             corev1.ConditionFalse,
+		Reason:             ClusterStableReason,
+		Message:            ClusterStableMessage,
+		LastProbeTime:      metav1.NewTime(now),
+		LastTransitionTime: metav1.NewTime(now),
+	}
+	if recentTransitions >= config.Threshold {
+		newCondition.Status = corev1.ConditionTrue
+		newCondition.Reason = ClusterFlappingReason
+		newCondition.Message = fmt.Sprintf(ClusterFlappingMessageTemplate, recentTransitions, config.Window)
+	}
+
+	oldCondition := getClusterCondition(clusterStatus, fedcorev1a1.ClusterUnstable)
+	if oldCondition != nil && oldCondition.Status == newCondition.Status &&
+		oldCondition.Reason == newCondition.Reason && oldCondition.Message == newCondition.Message {
+		return false
+	}
+	updateClusterCondition(clusterStatus, newCondition)
+	return true
+}
+
+// updatePullClusterStability updates the Unstable condition of a pull-mode cluster, whose health history is recorded
+// by its agent.
+func updatePullClusterStability(
+	ctx context.Context,
+	cluster *fedcorev1a1.FederatedCluster,
+	fedClient fedclient.Interface,
+	config FlapDetectionConfig,
+) error {
+	if !updateClusterStability(cluster.Status.DeepCopy(), config, time.Now()) {
+		return nil
+	}
+
+	if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		latestCluster, err := fedClient.CoreV1alpha1().FederatedClusters().Get(ctx, cluster.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if !updateClusterStability(&latestCluster.Status, config, time.Now()) {
+			return nil
+		}
+		_, err = fedClient.CoreV1alpha1().FederatedClusters().UpdateStatus(ctx, latestCluster, metav1.UpdateOptions{})
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to update cluster stability: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedcluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+func probeCluster(status *fedcorev1a1.FederatedClusterStatus, readyStatus corev1.ConditionStatus, probeTime time.Time) {
+	probeClusterWithThresholds(status, readyStatus, probeTime, 1, 1)
+}
+
+func probeClusterWithThresholds(
+	status *fedcorev1a1.FederatedClusterStatus,
+	readyStatus corev1.ConditionStatus,
+	probeTime time.Time,
+	successThreshold, failureThreshold int,
+) {
+	applyClusterHealthProbe(status, fedcorev1a1.ClusterHealthProbe{
+		Time:   metav1.NewTime(probeTime),
+		Status: readyStatus,
+		Reason: string(readyStatus),
+	}, successThreshold, failureThreshold)
+}
+
+func TestApplyClusterHealthProbe(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	status := &fedcorev1a1.FederatedClusterStatus{}
+	start := time.Now()
+
+	probeCluster(status, corev1.ConditionTrue, start)
+	g.Expect(status.Health.Probes).To(gomega.HaveLen(1))
+	g.Expect(status.Health.Transitions).To(gomega.BeEmpty())
+
+	probeCluster(status, corev1.ConditionTrue, start.Add(time.Second))
+	g.Expect(status.Health.Transitions).To(gomega.BeEmpty())
+	readyCondition := getClusterCondition(status, fedcorev1a1.ClusterReady)
+	g.Expect(readyCondition.LastTransitionTime.Time).To(gomega.BeTemporally("==", start))
+	g.Expect(readyCondition.LastProbeTime.Time).To(gomega.BeTemporally("==", start.Add(time.Second)))
+
+	probeCluster(status, corev1.ConditionUnknown, start.Add(2*time.Second))
+	g.Expect(status.Health.Transitions).To(gomega.HaveLen(1))
+	g.Expect(status.Health.Transitions[0].From).To(gomega.Equal(corev1.ConditionTrue))
+	g.Expect(status.Health.Transitions[0].To).To(gomega.Equal(corev1.ConditionUnknown))
+	g.Expect(getClusterCondition(status, fedcorev1a1.ClusterOffline).Status).To(gomega.Equal(corev1.ConditionTrue))
+
+	for i := 0; i < 2*ClusterHealthProbeHistoryLimit; i++ {
+		probeCluster(status, corev1.ConditionTrue, start.Add(time.Duration(3+i)*time.Second))
+	}
+	g.Expect(status.Health.Probes).To(gomega.HaveLen(ClusterHealthProbeHistoryLimit))
+	g.Expect(status.Health.Probes[0].Time.Time).To(gomega.BeTemporally("==", start.Add(22*time.Second)))
+	g.Expect(status.Health.Transitions).To(gomega.HaveLen(2))
+}
+
+func TestApplyClusterHealthProbeThresholds(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	status := &fedcorev1a1.FederatedClusterStatus{}
+	start := time.Now()
+	readyStatus := func() corev1.ConditionStatus {
+		return getClusterCondition(status, fedcorev1a1.ClusterReady).Status
+	}
+
+	// the first probe always takes effect
+	probeClusterWithThresholds(status, corev1.ConditionTrue, start, 2, 3)
+	g.Expect(readyStatus()).To(gomega.Equal(corev1.ConditionTrue))
+
+	probeClusterWithThresholds(status, corev1.ConditionFalse, start.Add(time.Second), 2, 3)
+	probeClusterWithThresholds(status, corev1.ConditionUnknown, start.Add(2*time.Second), 2, 3)
+	g.Expect(readyStatus()).To(gomega.Equal(corev1.ConditionTrue))
+	g.Expect(getClusterCondition(status, fedcorev1a1.ClusterOffline).Status).To(gomega.Equal(corev1.ConditionFalse))
+	g.Expect(status.Health.Probes).To(gomega.HaveLen(3))
+	g.Expect(status.Health.Transitions).To(gomega.BeEmpty())
+
+	probeClusterWithThresholds(status, corev1.ConditionFalse, start.Add(3*time.Second), 2, 3)
+	g.Expect(readyStatus()).To(gomega.Equal(corev1.ConditionFalse))
+	g.Expect(status.Health.Transitions).To(gomega.HaveLen(1))
+
+	// switching between failure statuses is not subject to the thresholds
+	probeClusterWithThresholds(status, corev1.ConditionUnknown, start.Add(4*time.Second), 2, 3)
+	g.Expect(readyStatus()).To(gomega.Equal(corev1.ConditionUnknown))
+
+	// a failed probe resets the consecutive successes
+	probeClusterWithThresholds(status, corev1.ConditionTrue, start.Add(5*time.Second), 2, 3)
+	probeClusterWithThresholds(status, corev1.ConditionFalse, start.Add(6*time.Second), 2, 3)
+	probeClusterWithThresholds(status, corev1.ConditionTrue, start.Add(7*time.Second), 2, 3)
+	g.Expect(readyStatus()).To(gomega.Equal(corev1.ConditionFalse))
+
+	probeClusterWithThresholds(status, corev1.ConditionTrue, start.Add(8*time.Second), 2, 3)
+	g.Expect(readyStatus()).To(gomega.Equal(corev1.ConditionTrue))
+	readyCondition := getClusterCondition(status, fedcorev1a1.ClusterReady)
+	g.Expect(readyCondition.LastTransitionTime.Time).To(gomega.BeTemporally("==", start.Add(8*time.Second)))
+}
+
+func TestRunExtraHealthChecks(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	schedulableNodes := int64(2)
+	status := &fedcorev1a1.FederatedClusterStatus{
+		Resources: fedcorev1a1.Resources{SchedulableNodes: &schedulableNodes},
+		APIResourceTypes: []fedcorev1a1.APIResource{
+			{Group: "apps", Version: "v1", Kind: "Deployment"},
+		},
+	}
+
+	reason, _ := runExtraHealthChecks(status, ClusterHealthProbeConfig{})
+	g.Expect(reason).To(gomega.BeEmpty())
+
+	minSchedulableNodes := int64(3)
+	reason, _ = runExtraHealthChecks(status, ClusterHealthProbeConfig{MinSchedulableNodes: &minSchedulableNodes})
+	g.Expect(reason).To(gomega.Equal(ClusterInsufficientSchedulableNodesReason))
+
+	reason, _ = runExtraHealthChecks(status, ClusterHealthProbeConfig{
+		RequiredAPIResources: []fedcorev1a1.RequiredAPIResource{{Group: "apps", Kind: "Deployment"}},
+	})
+	g.Expect(reason).To(gomega.BeEmpty())
+
+	reason, message := runExtraHealthChecks(status, ClusterHealthProbeConfig{
+		RequiredAPIResources: []fedcorev1a1.RequiredAPIResource{{Group: "apps", Version: "v1beta1", Kind: "Deployment"}},
+	})
+	g.Expect(reason).To(gomega.Equal(ClusterRequiredAPIResourceMissingReason))
+	g.Expect(message).To(gomega.ContainSubstring("apps/v1beta1/Deployment"))
+}
+
+func TestUpdateClusterStability(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	config := FlapDetectionConfig{Window: time.Minute, Threshold: 3}
+	status := &fedcorev1a1.FederatedClusterStatus{}
+	start := time.Now()
+
+	probeCluster(status, corev1.ConditionTrue, start)
+	g.Expect(updateClusterStability(status, config, start)).To(gomega.BeTrue())
+	g.Expect(getClusterCondition(status, fedcorev1a1.ClusterUnstable).Status).To(gomega.Equal(corev1.ConditionFalse))
+
+	probeCluster(status, corev1.ConditionFalse, start.Add(10*time.Second))
+	probeCluster(status, corev1.ConditionTrue, start.Add(20*time.Second))
+	g.Expect(updateClusterStability(status, config, start.Add(20*time.Second))).To(gomega.BeFalse())
+
+	probeCluster(status, corev1.ConditionFalse, start.Add(30*time.Second))
+	g.Expect(updateClusterStability(status, config, start.Add(30*time.Second))).To(gomega.BeTrue())
+	condition := getClusterCondition(status, fedcorev1a1.ClusterUnstable)
+	g.Expect(condition.Status).To(gomega.Equal(corev1.ConditionTrue))
+	g.Expect(condition.Reason).To(gomega.Equal(ClusterFlappingReason))
+
+	// the transitions age out of the window
+	g.Expect(updateClusterStability(status, config, start.Add(2*time.Minute))).To(gomega.BeTrue())
+	g.Expect(getClusterCondition(status, fedcorev1a1.ClusterUnstable).Status).To(gomega.Equal(corev1.ConditionFalse))
+
+	// flap detection is disabled
+	status = &fedcorev1a1.FederatedClusterStatus{}
+	probeCluster(status, corev1.ConditionTrue, start)
+	g.Expect(updateClusterStability(status, FlapDetectionConfig{}, start)).To(gomega.BeFalse())
+	g.Expect(getClusterCondition(status, fedcorev1a1.ClusterUnstable)).To(gomega.BeNil())
+}
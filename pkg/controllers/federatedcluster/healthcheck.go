@@ -0,0 +1,152 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedcluster
+
+import (
+	"fmt"
+	"time"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+const (
+	ClusterInsufficientSchedulableNodesReason          = "InsufficientSchedulableNodes"
+	ClusterInsufficientSchedulableNodesMessageTemplate = "Cluster has %d schedulable nodes, at least %d are required"
+
+	ClusterRequiredAPIResourceMissingReason          = "RequiredAPIResourceMissing"
+	ClusterRequiredAPIResourceMissingMessageTemplate = "Cluster does not serve required API resource %s"
+)
+
+// ClusterHealthProbeConfig defines how the health of a single cluster is probed.
+type ClusterHealthProbeConfig struct {
+	// Path of the health endpoint of the cluster's apiserver.
+	Path string
+	// Timeout of the request to the health endpoint.
+	Timeout time.Duration
+	// SuccessThreshold is the number of consecutive successful probes after which a cluster becomes ready.
+	SuccessThreshold int
+	// FailureThreshold is the number of consecutive failed probes after which a cluster becomes not ready.
+	FailureThreshold int
+	// MinSchedulableNodes fails the probe if the cluster has fewer schedulable nodes.
+	MinSchedulableNodes *int64
+	// RequiredAPIResources fails the probe if any of the API resources is not served by the cluster.
+	RequiredAPIResources []fedcorev1a1.RequiredAPIResource
+}
+
+// DefaultClusterHealthCheckConfig returns the health check configuration used if none is specified.
+func DefaultClusterHealthCheckConfig() *ClusterHealthCheckConfig {
+	return &ClusterHealthCheckConfig{
+		Period:             time.Second * 30,
+		AgentStatusTimeout: time.Second * 90,
+		Path:               "/healthz",
+		Timeout:            time.Second * 30,
+		SuccessThreshold:   1,
+		FailureThreshold:   1,
+	}
+}
+
+// PeriodForCluster returns the period between health checks of the cluster.
+func (c *ClusterHealthCheckConfig) PeriodForCluster(cluster *fedcorev1a1.FederatedCluster) time.Duration {
+	if healthCheck := cluster.Spec.HealthCheck; healthCheck != nil && healthCheck.Period != nil {
+		return healthCheck.Period.Duration
+	}
+	return c.Period
+}
+
+// ProbeConfigForCluster returns the probe configuration of the cluster, using the global configuration for the fields
+// not overridden by the cluster.
+func (c *ClusterHealthCheckConfig) ProbeConfigForCluster(cluster *fedcorev1a1.FederatedCluster) ClusterHealthProbeConfig {
+	config := ClusterHealthProbeConfig{
+		Path:             c.Path,
+		Timeout:          c.Timeout,
+		SuccessThreshold: c.SuccessThreshold,
+		FailureThreshold: c.FailureThreshold,
+	}
+
+	healthCheck := cluster.Spec.HealthCheck
+	if healthCheck == nil {
+		return config
+	}
+	if len(healthCheck.Path) > 0 {
+		config.Path = healthCheck.Path
+	}
+	if healthCheck.Timeout != nil {
+		config.Timeout = healthCheck.Timeout.Duration
+	}
+	if healthCheck.SuccessThreshold > 0 {
+		config.SuccessThreshold = int(healthCheck.SuccessThreshold)
+	}
+	if healthCheck.FailureThreshold > 0 {
+		config.FailureThreshold = int(healthCheck.FailureThreshold)
+	}
+	config.MinSchedulableNodes = healthCheck.MinSchedulableNodes
+	config.RequiredAPIResources = healthCheck.RequiredAPIResources
+	return config
+}
+
+// runExtraHealthChecks runs the optional health checks of the probe configuration against the collected status of the
+// cluster. It returns the reason and message of the first failed check, or empty strings if all checks passed.
+func runExtraHealthChecks(
+	clusterStatus *fedcorev1a1.FederatedClusterStatus,
+	config ClusterHealthProbeConfig,
+) (reason, message string) {
+	if config.MinSchedulableNodes != nil {
+		var schedulableNodes int64
+		if clusterStatus.Resources.SchedulableNodes != nil {
+			schedulableNodes = *clusterStatus.Resources.SchedulableNodes
+		}
+		if schedulableNodes < *config.MinSchedulableNodes {
+			return ClusterInsufficientSchedulableNodesReason, fmt.Sprintf(
+				ClusterInsufficientSchedulableNodesMessageTemplate,
+				schedulableNodes,
+				*config.MinSchedulableNodes,
+			)
+		}
+	}
+
+	for _, required := range config.RequiredAPIResources {
+		if !isAPIResourceServed(clusterStatus.APIResourceTypes, required) {
+			return ClusterRequiredAPIResourceMissingReason, fmt.Sprintf(
+				ClusterRequiredAPIResourceMissingMessageTemplate,
+				formatRequiredAPIResource(required),
+			)
+		}
+	}
+
+	return "", ""
+}
+
+func isAPIResourceServed(apiResources []fedcorev1a1.APIResource, required fedcorev1a1.RequiredAPIResource) bool {
+	for _, apiResource := range apiResources {
+		if apiResource.Group == required.Group && apiResource.Kind == required.Kind &&
+			(len(required.Version) == 0 || apiResource.Version == required.Version) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatRequiredAPIResource(required fedcorev1a1.RequiredAPIResource) string {
+	gvk := required.Kind
+	if len(required.Version) > 0 {
+		gvk = fmt.Sprintf("%s/%s", required.Version, gvk)
+	}
+	if len(required.Group) > 0 {
+		gvk = fmt.Sprintf("%s/%s", required.Group, gvk)
+	}
+	return gvk
+}
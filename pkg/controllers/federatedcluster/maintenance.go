@@ -0,0 +1,148 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedcluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	dynamicclient "k8s.io/client-go/dynamic"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/managedlabel"
+	schemautil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/schema"
+)
+
+const (
+	ClusterCordonedReason  = "Cordoned"
+	ClusterCordonedMessage = "Cluster is cordoned, no new objects will be placed in the cluster"
+
+	ClusterDrainingReason          = "Draining"
+	ClusterDrainingMessageTemplate = "Cluster is being drained, %d objects remaining: %s"
+
+	ClusterDrainedReason  = "Drained"
+	ClusterDrainedMessage = "Cluster is drained, no objects remaining"
+
+	ClusterNotUnderMaintenanceReason  = "NotUnderMaintenance"
+	ClusterNotUnderMaintenanceMessage = "Cluster is not under maintenance"
+)
+
+// CollectClusterMaintenanceStatus updates the maintenance condition of a member cluster. The progress of a drain is
+// measured by the number of objects of the given FederatedTypeConfigs still managed in the cluster. It is shared by
+// the federated cluster controller and the agent of pull-mode clusters.
+func CollectClusterMaintenanceStatus(
+	ctx context.Context,
+	cluster *fedcorev1a1.FederatedCluster,
+	clusterStatus *fedcorev1a1.FederatedClusterStatus,
+	clusterDynamicClient dynamicclient.Interface,
+	typeConfigs []*fedcorev1a1.FederatedTypeConfig,
+) error {
+	var status corev1.ConditionStatus
+	var reason, message string
+
+	switch {
+	case cluster.Spec.Maintenance == nil:
+		if oldCondition := getClusterCondition(clusterStatus, fedcorev1a1.ClusterUnderMaintenance); oldCondition == nil {
+			return nil
+		}
+		status, reason, message = corev1.ConditionFalse, ClusterNotUnderMaintenanceReason, ClusterNotUnderMaintenanceMessage
+	case cluster.Spec.Maintenance.Mode == fedcorev1a1.ClusterMaintenanceModeCordon:
+		status, reason, message = corev1.ConditionTrue, ClusterCordonedReason, ClusterCordonedMessage
+	case cluster.Spec.Maintenance.Mode == fedcorev1a1.ClusterMaintenanceModeDrain:
+		remaining, err := countManagedObjects(ctx, clusterDynamicClient, typeConfigs)
+		if err != nil {
+			return fmt.Errorf("failed to count remaining objects: %w", err)
+		}
+		status = corev1.ConditionTrue
+		if total := sumObjectCounts(remaining); total > 0 {
+			reason = ClusterDrainingReason
+			message = fmt.Sprintf(ClusterDrainingMessageTemplate, total, formatObjectCounts(remaining))
+		} else {
+			reason, message = ClusterDrainedReason, ClusterDrainedMessage
+		}
+	default:
+		return nil
+	}
+
+	conditionTime := metav1.Now()
+	newCondition := &fedcorev1a1.ClusterCondition{
+		Type:               fedcorev1a1.ClusterUnderMaintenance,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastProbeTime:      conditionTime,
+		LastTransitionTime: conditionTime,
+	}
+	updateClusterCondition(clusterStatus, newCondition)
+
+	return nil
+}
+
+// countManagedObjects counts the objects managed by KubeAdmiral in a member cluster for each target type.
+// Namespaces are not counted since they are kept in the cluster to hold other objects.
+func countManagedObjects(
+	ctx context.Context,
+	clusterDynamicClient dynamicclient.Interface,
+	typeConfigs []*fedcorev1a1.FederatedTypeConfig,
+) (map[string]int, error) {
+	selector := labels.SelectorFromSet(labels.Set{
+		managedlabel.ManagedByKubeAdmiralLabelKey: managedlabel.ManagedByKubeAdmiralLabelValue,
+	}).String()
+
+	counts := map[string]int{}
+	for _, typeConfig := range typeConfigs {
+		targetType := typeConfig.GetTargetType()
+		if !typeConfig.GetPropagationEnabled() || targetType.Kind == common.NamespaceKind {
+			continue
+		}
+
+		objects, err := clusterDynamicClient.Resource(schemautil.APIResourceToGVR(&targetType)).List(
+			ctx,
+			metav1.ListOptions{LabelSelector: selector, ResourceVersion: "0"},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", typeConfig.Name, err)
+		}
+		if len(objects.Items) > 0 {
+			counts[typeConfig.Name] = len(objects.Items)
+		}
+	}
+	return counts, nil
+}
+
+func sumObjectCounts(counts map[string]int) int {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	return total
+}
+
+func formatObjectCounts(counts map[string]int) string {
+	entries := make([]string, 0, len(counts))
+	for name, count := range counts {
+		entries = append(entries, fmt.Sprintf("%s=%d", name, count))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedcluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/managedlabel"
+)
+
+func newManagedObject(apiVersion, kind, namespace, name string, managed bool) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	if managed {
+		obj.SetLabels(map[string]string{
+			managedlabel.ManagedByKubeAdmiralLabelKey: managedlabel.ManagedByKubeAdmiralLabelValue,
+		})
+	}
+	return obj
+}
+
+func TestCollectClusterMaintenanceStatus(t *testing.T) {
+	typeConfigs := []*fedcorev1a1.FederatedTypeConfig{
+		{Spec: fedcorev1a1.FederatedTypeConfigSpec{TargetType: fedcorev1a1.APIResource{
+			Group: "apps", Version: "v1", Kind: "Deployment", PluralName: "deployments",
+		}}},
+		{Spec: fedcorev1a1.FederatedTypeConfigSpec{TargetType: fedcorev1a1.APIResource{
+			Version: "v1", Kind: "Namespace", PluralName: "namespaces",
+		}}},
+	}
+	typeConfigs[0].Name = "deployments.apps"
+	typeConfigs[1].Name = "namespaces"
+	listKinds := map[schema.GroupVersionResource]string{
+		{Group: "apps", Version: "v1", Resource: "deployments"}: "DeploymentList",
+		{Version: "v1", Resource: "namespaces"}:                 "NamespaceList",
+	}
+
+	tests := []struct {
+		name            string
+		maintenance     *fedcorev1a1.ClusterMaintenance
+		oldCondition    *fedcorev1a1.ClusterCondition
+		objects         []runtime.Object
+		expectCondition bool
+		expectedStatus  corev1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:            "no condition is added when the cluster is not under maintenance",
+			expectCondition: false,
+		},
+		{
+			name:        "condition is reset when the maintenance ends",
+			maintenance: nil,
+			oldCondition: &fedcorev1a1.ClusterCondition{
+				Type:   fedcorev1a1.ClusterUnderMaintenance,
+				Status: corev1.ConditionTrue,
+				Reason: ClusterDrainedReason,
+			},
+			expectCondition: true,
+			expectedStatus:  corev1.ConditionFalse,
+			expectedReason:  ClusterNotUnderMaintenanceReason,
+			expectedMessage: ClusterNotUnderMaintenanceMessage,
+		},
+		{
+			name:            "cordoned cluster",
+			maintenance:     &fedcorev1a1.ClusterMaintenance{Mode: fedcorev1a1.ClusterMaintenanceModeCordon},
+			expectCondition: true,
+			expectedStatus:  corev1.ConditionTrue,
+			expectedReason:  ClusterCordonedReason,
+			expectedMessage: ClusterCordonedMessage,
+		},
+		{
+			name:        "draining cluster reports remaining managed objects",
+			maintenance: &fedcorev1a1.ClusterMaintenance{Mode: fedcorev1a1.ClusterMaintenanceModeDrain},
+			objects: []runtime.Object{
+				newManagedObject("apps/v1", "Deployment", "default", "dp1", true),
+				newManagedObject("apps/v1", "Deployment", "default", "dp2", true),
+				newManagedObject("apps/v1", "Deployment", "default", "unmanaged", false),
+				newManagedObject("v1", "Namespace", "", "default", true),
+			},
+			expectCondition: true,
+			expectedStatus:  corev1.ConditionTrue,
+			expectedReason:  ClusterDrainingReason,
+			expectedMessage: "Cluster is being drained, 2 objects remaining: deployments.apps=2",
+		},
+		{
+			name:        "drained cluster",
+			maintenance: &fedcorev1a1.ClusterMaintenance{Mode: fedcorev1a1.ClusterMaintenanceModeDrain},
+			objects: []runtime.Object{
+				newManagedObject("apps/v1", "Deployment", "default", "unmanaged", false),
+				newManagedObject("v1", "Namespace", "", "default", true),
+			},
+			expectCondition: true,
+			expectedStatus:  corev1.ConditionTrue,
+			expectedReason:  ClusterDrainedReason,
+			expectedMessage: ClusterDrainedMessage,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			cluster := &fedcorev1a1.FederatedCluster{}
+			cluster.Spec.Maintenance = test.maintenance
+			if test.oldCondition != nil {
+				cluster.Status.Conditions = []fedcorev1a1.ClusterCondition{*test.oldCondition}
+			}
+			client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, test.objects...)
+
+			err := CollectClusterMaintenanceStatus(context.TODO(), cluster, &cluster.Status, client, typeConfigs)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			condition := getClusterCondition(&cluster.Status, fedcorev1a1.ClusterUnderMaintenance)
+			if !test.expectCondition {
+				g.Expect(condition).To(gomega.BeNil())
+				return
+			}
+			g.Expect(condition).NotTo(gomega.BeNil())
+			g.Expect(condition.Status).To(gomega.Equal(test.expectedStatus))
+			g.Expect(condition.Reason).To(gomega.Equal(test.expectedReason))
+			g.Expect(condition.Message).To(gomega.Equal(test.expectedMessage))
+		})
+	}
+}
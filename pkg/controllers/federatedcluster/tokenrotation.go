@@ -0,0 +1,361 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedcluster
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/worker"
+)
+
+const (
+	serviceAccountNameAnnotation = "kubernetes.io/service-account.name"
+)
+
+const (
+	TokenNotRotatedReason          = "TokenNotRotated"
+	TokenNotRotatedMessageTemplate = "Service account token has not been rotated since the cluster joined, next rotation at %s"
+
+	TokenRotatedReason          = "TokenRotated"
+	TokenRotatedMessageTemplate = "Service account token was rotated at %s, next rotation at %s"
+
+	OldTokenRevocationPendingReason          = "OldTokenRevocationPending"
+	OldTokenRevocationPendingMessageTemplate = "Service account token was rotated at %s, the old token will be revoked at %s, next rotation at %s"
+
+	TokenRotationFailedReason          = "TokenRotationFailed"
+	TokenRotationFailedMessageTemplate = "Failed to rotate service account token, will retry: %v"
+)
+
+const (
+	EventReasonTokenRotated        = "TokenRotated"
+	EventReasonTokenRotationFailed = "TokenRotationFailed"
+)
+
+// TokenRotationConfig defines the configurable parameters for service account token rotation.
+type TokenRotationConfig struct {
+	// Period is the interval at which service account tokens are rotated. Rotation is disabled if Period is 0.
+	Period time.Duration
+	// RevocationDelay is the time to wait after a rotation before the old token is revoked, so that all clients of
+	// the cluster have switched to the new token.
+	RevocationDelay time.Duration
+}
+
+// shouldRotateToken returns true if the service account token of the cluster is managed by the controller.
+func shouldRotateToken(cluster *fedcorev1a1.FederatedCluster) bool {
+	return cluster.Spec.UseServiceAccountToken &&
+		cluster.Spec.CredentialProvider == nil &&
+		!util.IsPullModeCluster(cluster) &&
+		cluster.GetDeletionTimestamp() == nil &&
+		cluster.Status.JoinPerformed &&
+		util.IsClusterJoined(&cluster.Status)
+}
+
+// getTokenRotatedAt returns the time at which the service account token of the cluster was last rotated, and false
+// if it has not been rotated since the cluster joined.
+func getTokenRotatedAt(cluster *fedcorev1a1.FederatedCluster) (time.Time, bool) {
+	value, exists := cluster.GetAnnotations()[common.TokenRotatedAtAnnotation]
+	if !exists {
+		return time.Time{}, false
+	}
+	rotatedAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return rotatedAt, true
+}
+
+// getNextTokenRotationTime returns the time at which the service account token of the cluster should be rotated next.
+func getNextTokenRotationTime(cluster *fedcorev1a1.FederatedCluster, period time.Duration) time.Time {
+	if rotatedAt, ok := getTokenRotatedAt(cluster); ok {
+		return rotatedAt.Add(period)
+	}
+	if joinedCondition := getClusterCondition(&cluster.Status, fedcorev1a1.ClusterJoined); joinedCondition != nil {
+		return joinedCondition.LastTransitionTime.Add(period)
+	}
+	return time.Now()
+}
+
+func (c *FederatedClusterController) rotateClusterToken(qualifiedName common.QualifiedName) (status worker.Result) {
+	logger := c.logger.WithValues("control-loop", "token-rotation", "object", qualifiedName.String())
+	ctx := klog.NewContext(context.TODO(), logger)
+	startTime := time.Now()
+
+	logger.V(3).Info("Starting token rotation check")
+	defer func() {
+		logger.WithValues("duration", time.Since(startTime), "status", status.String()).V(3).Info("Finished token rotation check")
+	}()
+
+	cluster, err := c.clusterLister.Get(qualifiedName.Name)
+	if err != nil && apierrors.IsNotFound(err) {
+		return worker.StatusAllOK
+	}
+	if err != nil {
+		logger.Error(err, "Failed to get cluster from store")
+		return worker.StatusError
+	}
+	cluster = cluster.DeepCopy()
+
+	if !shouldRotateToken(cluster) {
+		return worker.StatusAllOK
+	}
+
+	now := time.Now()
+	period := c.tokenRotationConfig.Period
+	rotatedAt, rotated := getTokenRotatedAt(cluster)
+	nextRotation := getNextTokenRotationTime(cluster, period)
+	condition := getClusterCondition(&cluster.Status, fedcorev1a1.ClusterTokenRotated)
+
+	// 1. Revoke the old tokens once the clients have switched to the new token.
+	if rotated && condition != nil && condition.Reason == OldTokenRevocationPendingReason {
+		revokeAt := rotatedAt.Add(c.tokenRotationConfig.RevocationDelay)
+		if now.Before(revokeAt) {
+			return worker.Result{Success: true, RequeueAfter: durationPtr(revokeAt.Sub(now))}
+		}
+
+		logger.V(2).Info("Revoking old service account tokens")
+		if err := c.revokeOldTokens(ctx, cluster); err != nil {
+			// the condition is left unchanged so that the revocation is retried
+			logger.Error(err, "Failed to revoke old service account tokens")
+			c.eventRecorder.Eventf(cluster, corev1.EventTypeWarning, EventReasonTokenRotationFailed,
+				"Failed to revoke old service account tokens: %v", err)
+			return worker.StatusError
+		}
+		return c.updateTokenRotatedCondition(ctx, cluster, corev1.ConditionTrue, TokenRotatedReason,
+			fmt.Sprintf(TokenRotatedMessageTemplate, formatTime(rotatedAt), formatTime(nextRotation)))
+	}
+
+	// 2. Rotate the token if it is due.
+	if !now.Before(nextRotation) {
+		logger.V(2).Info("Rotating service account token")
+		rotatedAt, err := c.mintAndSaveToken(ctx, cluster)
+		if err != nil {
+			return c.handleTokenRotationError(ctx, cluster, err)
+		}
+		c.eventRecorder.Eventf(cluster, corev1.EventTypeNormal, EventReasonTokenRotated, "Service account token rotated")
+
+		revokeAt := rotatedAt.Add(c.tokenRotationConfig.RevocationDelay)
+		result := c.updateTokenRotatedCondition(ctx, cluster, corev1.ConditionTrue, OldTokenRevocationPendingReason,
+			fmt.Sprintf(
+				OldTokenRevocationPendingMessageTemplate,
+				formatTime(rotatedAt),
+				formatTime(revokeAt),
+				formatTime(rotatedAt.Add(period)),
+			))
+		if result.Success {
+			result.RequeueAfter = durationPtr(c.tokenRotationConfig.RevocationDelay)
+		}
+		return result
+	}
+
+	// 3. Show the next rotation time for clusters that have not been rotated yet.
+	if condition == nil {
+		return c.updateTokenRotatedCondition(ctx, cluster, corev1.ConditionFalse, TokenNotRotatedReason,
+			fmt.Sprintf(TokenNotRotatedMessageTemplate, formatTime(nextRotation)))
+	}
+
+	return worker.StatusAllOK
+}
+
+// mintAndSaveToken creates a new service account token in the member cluster and saves it in the cluster secret.
+// The rotation time is recorded in the cluster's annotations, which causes the clients of the cluster to be rebuilt.
+func (c *FederatedClusterController) mintAndSaveToken(
+	ctx context.Context,
+	cluster *fedcorev1a1.FederatedCluster,
+) (time.Time, error) {
+	_, clusterKubeClient, err := getClusterClient(ctx, c.kubeClient, c.fedSystemNamespace, cluster, c.credentialProviderPolicy)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get cluster client: %w", err)
+	}
+
+	tokenSecret, err := clusterKubeClient.CoreV1().Secrets(c.fedSystemNamespace).Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: MemberServiceAccountName + "-",
+			Namespace:    c.fedSystemNamespace,
+			Annotations: map[string]string{
+				serviceAccountNameAnnotation: MemberServiceAccountName,
+			},
+		},
+		Type: corev1.SecretTypeServiceAccountToken,
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to create service account token secret: %w", err)
+	}
+
+	token, ca, err := getServiceAccountToken(ctx, clusterKubeClient, c.fedSystemNamespace, tokenSecret.Name)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		secret, err := c.kubeClient.CoreV1().Secrets(c.fedSystemNamespace).Get(ctx, cluster.Spec.SecretRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[ServiceAccountTokenKey] = token
+		secret.Data[ServiceAccountCAKey] = ca
+		_, err = c.kubeClient.CoreV1().Secrets(c.fedSystemNamespace).Update(ctx, secret, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to update cluster secret: %w", err)
+	}
+
+	rotatedAt := time.Now().UTC().Truncate(time.Second)
+	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		latest, err := c.client.CoreV1alpha1().FederatedClusters().Get(ctx, cluster.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		annotations := latest.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[common.TokenRotatedAtAnnotation] = rotatedAt.Format(time.RFC3339)
+		latest.SetAnnotations(annotations)
+		updated, err := c.client.CoreV1alpha1().FederatedClusters().Update(ctx, latest, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		*cluster = *updated
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to record token rotation time: %w", err)
+	}
+
+	return rotatedAt, nil
+}
+
+// revokeOldTokens deletes the token secrets of the member service account that do not hold the token in the cluster
+// secret, which invalidates their tokens.
+func (c *FederatedClusterController) revokeOldTokens(ctx context.Context, cluster *fedcorev1a1.FederatedCluster) error {
+	clusterSecret, clusterKubeClient, err := getClusterClient(ctx, c.kubeClient, c.fedSystemNamespace, cluster, c.credentialProviderPolicy)
+	if err != nil {
+		return fmt.Errorf("failed to get cluster client: %w", err)
+	}
+	currentToken, exists := clusterSecret.Data[ServiceAccountTokenKey]
+	if !exists {
+		return fmt.Errorf("%q data is missing from cluster secret", ServiceAccountTokenKey)
+	}
+
+	return revokeServiceAccountTokens(ctx, clusterKubeClient, c.fedSystemNamespace, currentToken)
+}
+
+// revokeServiceAccountTokens deletes the token secrets of the member service account except the one holding
+// currentToken.
+func revokeServiceAccountTokens(
+	ctx context.Context,
+	clusterKubeClient kubeclient.Interface,
+	namespace string,
+	currentToken []byte,
+) error {
+	secrets, err := clusterKubeClient.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("type", string(corev1.SecretTypeServiceAccountToken)).String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if secret.Annotations[serviceAccountNameAnnotation] != MemberServiceAccountName {
+			continue
+		}
+		if bytes.Equal(secret.Data[corev1.ServiceAccountTokenKey], currentToken) {
+			continue
+		}
+		err := clusterKubeClient.CoreV1().Secrets(namespace).Delete(ctx, secret.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete service account token secret %s: %w", secret.Name, err)
+		}
+	}
+	return nil
+}
+
+func (c *FederatedClusterController) handleTokenRotationError(
+	ctx context.Context,
+	cluster *fedcorev1a1.FederatedCluster,
+	err error,
+) worker.Result {
+	klog.FromContext(ctx).Error(err, "Failed to rotate service account token")
+	c.eventRecorder.Eventf(cluster, corev1.EventTypeWarning, EventReasonTokenRotationFailed, err.Error())
+
+	c.updateTokenRotatedCondition(ctx, cluster, corev1.ConditionFalse, TokenRotationFailedReason,
+		fmt.Sprintf(TokenRotationFailedMessageTemplate, err))
+	return worker.StatusError
+}
+
+func (c *FederatedClusterController) updateTokenRotatedCondition(
+	ctx context.Context,
+	cluster *fedcorev1a1.FederatedCluster,
+	status corev1.ConditionStatus,
+	reason, message string,
+) worker.Result {
+	currentTime := metav1.Now()
+	newCondition := &fedcorev1a1.ClusterCondition{
+		Type:               fedcorev1a1.ClusterTokenRotated,
+		Status:             status,
+		LastProbeTime:      currentTime,
+		LastTransitionTime: currentTime,
+		Reason:             reason,
+		Message:            message,
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		latest, err := c.client.CoreV1alpha1().FederatedClusters().Get(ctx, cluster.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if oldCondition := getClusterCondition(&latest.Status, fedcorev1a1.ClusterTokenRotated); oldCondition != nil &&
+			oldCondition.Status == newCondition.Status {
+			newCondition.LastTransitionTime = oldCondition.LastTransitionTime
+		}
+		setClusterCondition(&latest.Status, newCondition)
+		_, err = c.client.CoreV1alpha1().FederatedClusters().UpdateStatus(ctx, latest, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		klog.FromContext(ctx).Error(err, "Failed to update token rotation condition")
+		return worker.StatusError
+	}
+	return worker.StatusAllOK
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
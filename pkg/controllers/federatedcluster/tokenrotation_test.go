@@ -0,0 +1,103 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedcluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+func TestGetNextTokenRotationTime(t *testing.T) {
+	joinedAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	period := 24 * time.Hour
+
+	testCases := map[string]struct {
+		annotations map[string]string
+		expected    time.Time
+	}{
+		"never rotated": {
+			expected: joinedAt.Add(period),
+		},
+		"rotated": {
+			annotations: map[string]string{common.TokenRotatedAtAnnotation: "2023-01-05T12:00:00Z"},
+			expected:    time.Date(2023, 1, 6, 12, 0, 0, 0, time.UTC),
+		},
+		"invalid rotation time": {
+			annotations: map[string]string{common.TokenRotatedAtAnnotation: "invalid"},
+			expected:    joinedAt.Add(period),
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			cluster := &fedcorev1a1.FederatedCluster{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations},
+				Status: fedcorev1a1.FederatedClusterStatus{
+					Conditions: []fedcorev1a1.ClusterCondition{{
+						Type:               fedcorev1a1.ClusterJoined,
+						Status:             corev1.ConditionTrue,
+						LastTransitionTime: metav1.NewTime(joinedAt),
+					}},
+				},
+			}
+			g.Expect(getNextTokenRotationTime(cluster, period).Equal(tc.expected)).To(gomega.BeTrue())
+		})
+	}
+}
+
+func TestRevokeServiceAccountTokens(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	newTokenSecret := func(name, saName, token string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "kube-admiral-system",
+				Annotations: map[string]string{serviceAccountNameAnnotation: saName},
+			},
+			Type: corev1.SecretTypeServiceAccountToken,
+			Data: map[string][]byte{corev1.ServiceAccountTokenKey: []byte(token)},
+		}
+	}
+	client := fake.NewSimpleClientset(
+		newTokenSecret(MemberServiceAccountName, MemberServiceAccountName, "old"),
+		newTokenSecret(MemberServiceAccountName+"-abcde", MemberServiceAccountName, "current"),
+		newTokenSecret("other", "other", "other"),
+	)
+
+	err := revokeServiceAccountTokens(context.Background(), client, "kube-admiral-system", []byte("current"))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	secrets, err := client.CoreV1().Secrets("kube-admiral-system").List(context.Background(), metav1.ListOptions{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	names := []string{}
+	for _, secret := range secrets.Items {
+		names = append(names, secret.Name)
+	}
+	g.Expect(names).To(gomega.ConsistOf(MemberServiceAccountName+"-abcde", "other"))
+}
@@ -50,6 +50,19 @@ func setClusterCondition(
 	status.Conditions = append(status.Conditions, *newCondition)
 }
 
+// updateClusterCondition sets the condition in the status, keeping the last transition time of the existing condition
+// if its status is unchanged.
+func updateClusterCondition(
+	status *fedcorev1a1.FederatedClusterStatus,
+	newCondition *fedcorev1a1.ClusterCondition,
+) {
+	if oldCondition := getClusterCondition(status, newCondition.Type); oldCondition != nil &&
+		oldCondition.Status == newCondition.Status {
+		newCondition.LastTransitionTime = oldCondition.LastTransitionTime
+	}
+	setClusterCondition(status, newCondition)
+}
+
 func getNewClusterOfflineCondition(
 	status corev1.ConditionStatus,
 	conditionTime metav1.Time,
@@ -0,0 +1,478 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedhpa
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	autoscalingv2informers "k8s.io/client-go/informers/autoscaling/v2"
+	"k8s.io/client-go/kubernetes"
+	autoscalingv2listers "k8s.io/client-go/listers/autoscaling/v2"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedcorev1a1informers "github.com/kubewharf/kubeadmiral/pkg/client/informers/externalversions/core/v1alpha1"
+	fedcorev1a1listers "github.com/kubewharf/kubeadmiral/pkg/client/listers/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/delayingdeliver"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/eventsink"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/federatedclient"
+	schemautil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/schema"
+	utilunstructured "github.com/kubewharf/kubeadmiral/pkg/controllers/util/unstructured"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/worker"
+	"github.com/kubewharf/kubeadmiral/pkg/stats"
+)
+
+const (
+	ControllerName = "federated-hpa-controller"
+
+	// The interval at which federated HPAs are evaluated, same as the default of the kube-controller-manager.
+	syncPeriod = 15 * time.Second
+)
+
+const (
+	EventReasonSuccessfulRescale     = "SuccessfulRescale"
+	EventReasonFailedRescale         = "FailedRescale"
+	EventReasonFailedGetScale        = "FailedGetScale"
+	EventReasonFailedComputeReplicas = "FailedComputeMetricsReplicas"
+)
+
+// Controller evaluates the HorizontalPodAutoscalers in the host cluster that are annotated with
+// common.FederatedHPAAnnotation. The metrics of the target's pods are collected from all member clusters the target is
+// placed in, and the resulting global replica count is written to the source object in the host cluster. The
+// scheduler then redistributes the replicas across the member clusters.
+type Controller struct {
+	name string
+
+	hpaLister        autoscalingv2listers.HorizontalPodAutoscalerLister
+	hpaSynced        cache.InformerSynced
+	typeConfigLister fedcorev1a1listers.FederatedTypeConfigLister
+	typeConfigSynced cache.InformerSynced
+
+	kubeClient      kubernetes.Interface
+	dynamicClient   dynamic.Interface
+	federatedClient federatedclient.FederatedClientFactory
+
+	// recommendations contains the recent recommendations of each federated HPA, used for stabilization
+	recommendationsLock sync.Mutex
+	recommendations     map[string][]timestampedRecommendation
+
+	worker worker.ReconcileWorker
+
+	eventRecorder record.EventRecorder
+	metrics       stats.Metrics
+	logger        klog.Logger
+}
+
+// IsControllerReady implements controllermanager.Controller
+func (c *Controller) IsControllerReady() bool {
+	return c.HasSynced()
+}
+
+func NewFederatedHPAController(
+	kubeClient kubernetes.Interface,
+	dynamicClient dynamic.Interface,
+	hpaInformer autoscalingv2informers.HorizontalPodAutoscalerInformer,
+	typeConfigInformer fedcorev1a1informers.FederatedTypeConfigInformer,
+	federatedClient federatedclient.FederatedClientFactory,
+	metrics stats.Metrics,
+	workerConfig worker.ConfigSource,
+) (*Controller, error) {
+	c := &Controller{
+		name:             ControllerName,
+		hpaLister:        hpaInformer.Lister(),
+		hpaSynced:        hpaInformer.Informer().HasSynced,
+		typeConfigLister: typeConfigInformer.Lister(),
+		typeConfigSynced: typeConfigInformer.Informer().HasSynced,
+		kubeClient:       kubeClient,
+		dynamicClient:    dynamicClient,
+		federatedClient:  federatedClient,
+		recommendations:  map[string][]timestampedRecommendation{},
+		eventRecorder:    eventsink.NewDefederatingRecorderMux(kubeClient, ControllerName, 4),
+		metrics:          metrics,
+		logger:           klog.LoggerWithValues(klog.Background(), "controller", ControllerName),
+	}
+
+	c.worker = worker.NewConfigurableReconcileWorker(
+		c.reconcile,
+		worker.WorkerTiming{},
+		workerConfig,
+		metrics,
+		delayingdeliver.NewMetricTags("federated-hpa-worker", "HorizontalPodAutoscaler"),
+	)
+
+	hpaInformer.Informer().AddEventHandler(util.NewTriggerOnAllChanges(c.worker.EnqueueObject))
+
+	return c, nil
+}
+
+func (c *Controller) Run(ctx context.Context) {
+	c.logger.Info("Starting controller")
+	defer c.logger.Info("Stopping controller")
+
+	if !cache.WaitForNamedCacheSync(c.name, ctx.Done(), c.HasSynced) {
+		return
+	}
+	c.worker.Run(ctx.Done())
+
+	<-ctx.Done()
+}
+
+func (c *Controller) HasSynced() bool {
+	return c.hpaSynced() && c.typeConfigSynced()
+}
+
+func isFederatedHPA(hpa *autoscalingv2.HorizontalPodAutoscaler) bool {
+	return hpa.GetAnnotations()[common.FederatedHPAAnnotation] == common.AnnotationValueTrue
+}
+
+func (c *Controller) reconcile(qualifiedName common.QualifiedName) (status worker.Result) {
+	key := qualifiedName.String()
+	keyedLogger := c.logger.WithValues("control-loop", "reconcile", "object", key)
+	ctx := klog.NewContext(context.TODO(), keyedLogger)
+
+	startTime := time.Now()
+	c.metrics.Rate("federated-hpa.throughput", 1)
+	keyedLogger.V(3).Info("Start reconcile")
+	defer func() {
+		c.metrics.Duration(fmt.Sprintf("%s.latency", c.name), startTime)
+		keyedLogger.V(3).Info("Finished reconcile", "duration", time.Since(startTime), "status", status.String())
+	}()
+
+	hpa, err := c.hpaLister.HorizontalPodAutoscalers(qualifiedName.Namespace).Get(qualifiedName.Name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		keyedLogger.Error(err, "Failed to get HorizontalPodAutoscaler from store")
+		return worker.StatusError
+	}
+	if apierrors.IsNotFound(err) || !isFederatedHPA(hpa) || hpa.GetDeletionTimestamp() != nil {
+		c.forgetRecommendations(key)
+		return worker.StatusAllOK
+	}
+
+	hpa = hpa.DeepCopy()
+	oldStatus := hpa.Status.DeepCopy()
+
+	if err := c.reconcileReplicas(ctx, hpa); err != nil {
+		keyedLogger.Error(err, "Failed to reconcile replicas")
+	}
+
+	hpa.Status.ObservedGeneration = &hpa.Generation
+	if !equality.Semantic.DeepEqual(oldStatus, &hpa.Status) {
+		keyedLogger.V(1).Info("Updating HorizontalPodAutoscaler status")
+		if _, err := c.kubeClient.AutoscalingV2().HorizontalPodAutoscalers(hpa.Namespace).UpdateStatus(
+			ctx,
+			hpa,
+			metav1.UpdateOptions{},
+		); err != nil {
+			keyedLogger.Error(err, "Failed to update HorizontalPodAutoscaler status")
+			if apierrors.IsConflict(err) {
+				return worker.StatusConflict
+			}
+			return worker.StatusError
+		}
+	}
+
+	delay := syncPeriod
+	return worker.Result{Success: true, RequeueAfter: &delay}
+}
+
+// reconcileReplicas computes the desired replicas of the HPA's target from the metrics of all member clusters and
+// scales the target if necessary. The status of the HPA is updated in place.
+func (c *Controller) reconcileReplicas(ctx context.Context, hpa *autoscalingv2.HorizontalPodAutoscaler) error {
+	keyedLogger := klog.FromContext(ctx)
+
+	typeConfig, err := c.typeConfigForScaleTarget(hpa.Spec.ScaleTargetRef)
+	if err != nil {
+		c.setFailedCondition(hpa, autoscalingv2.AbleToScale, EventReasonFailedGetScale, err)
+		return err
+	}
+
+	sourceType := typeConfig.GetSourceType()
+	sourceClient := c.dynamicClient.Resource(schemautil.APIResourceToGVR(sourceType)).Namespace(hpa.Namespace)
+	sourceObject, err := sourceClient.Get(ctx, hpa.Spec.ScaleTargetRef.Name, metav1.GetOptions{})
+	if err != nil {
+		err = fmt.Errorf("failed to get scale target: %w", err)
+		c.setFailedCondition(hpa, autoscalingv2.AbleToScale, EventReasonFailedGetScale, err)
+		return err
+	}
+
+	replicasPath := typeConfig.Spec.PathDefinition.ReplicasSpec
+	replicas, err := utilunstructured.GetInt64FromPath(sourceObject, replicasPath, nil)
+	if err != nil || replicas == nil {
+		err = fmt.Errorf("failed to get replicas of scale target from %s: %v", replicasPath, err)
+		c.setFailedCondition(hpa, autoscalingv2.AbleToScale, EventReasonFailedGetScale, err)
+		return err
+	}
+	currentReplicas := int32(*replicas)
+	hpa.Status.CurrentReplicas = currentReplicas
+	setCondition(hpa, autoscalingv2.AbleToScale, corev1.ConditionTrue, "SucceededGetScale",
+		"the federated HPA controller was able to get the target's current scale")
+
+	if currentReplicas == 0 && (hpa.Spec.MinReplicas == nil || *hpa.Spec.MinReplicas != 0) {
+		// same as the kube-controller-manager, autoscaling is disabled if the target is scaled to zero
+		hpa.Status.DesiredReplicas = 0
+		setCondition(hpa, autoscalingv2.ScalingActive, corev1.ConditionFalse, "ScalingDisabled",
+			"scaling is disabled since the replica count of the target is zero")
+		return nil
+	}
+
+	metricsReplicas, metricStatuses, err := c.computeReplicasForMetrics(ctx, hpa, typeConfig, sourceObject, currentReplicas)
+	if err != nil {
+		c.setFailedCondition(hpa, autoscalingv2.ScalingActive, EventReasonFailedComputeReplicas, err)
+		return err
+	}
+	hpa.Status.CurrentMetrics = metricStatuses
+	setCondition(hpa, autoscalingv2.ScalingActive, corev1.ConditionTrue, "ValidMetricFound",
+		"the federated HPA was able to successfully calculate a replica count from the metrics of all member clusters")
+
+	desiredReplicas := c.stabilize(hpa, currentReplicas, metricsReplicas)
+	if clamped := clampReplicas(hpa, desiredReplicas); clamped != desiredReplicas {
+		setCondition(hpa, autoscalingv2.ScalingLimited, corev1.ConditionTrue, "TooFewOrTooManyReplicas",
+			fmt.Sprintf("the desired replica count %d is out of the allowed range", desiredReplicas))
+		desiredReplicas = clamped
+	} else {
+		setCondition(hpa, autoscalingv2.ScalingLimited, corev1.ConditionFalse, "DesiredWithinRange",
+			"the desired count is within the acceptable range")
+	}
+	hpa.Status.DesiredReplicas = desiredReplicas
+
+	if desiredReplicas == currentReplicas {
+		keyedLogger.V(3).Info("No scaling required", "replicas", currentReplicas)
+		return nil
+	}
+
+	keyedLogger.V(1).Info("Scaling target", "current-replicas", currentReplicas, "desired-replicas", desiredReplicas)
+	desired := int64(desiredReplicas)
+	if err := utilunstructured.SetInt64FromPath(sourceObject, replicasPath, &desired, nil); err != nil {
+		return fmt.Errorf("failed to set replicas of scale target: %w", err)
+	}
+	if _, err := sourceClient.Update(ctx, sourceObject, metav1.UpdateOptions{}); err != nil {
+		err = fmt.Errorf("failed to update scale target: %w", err)
+		c.setFailedCondition(hpa, autoscalingv2.AbleToScale, EventReasonFailedRescale, err)
+		return err
+	}
+
+	c.eventRecorder.Eventf(
+		hpa,
+		corev1.EventTypeNormal,
+		EventReasonSuccessfulRescale,
+		"New size: %d; reason: metrics of all member clusters",
+		desiredReplicas,
+	)
+	now := metav1.Now()
+	hpa.Status.LastScaleTime = &now
+	hpa.Status.CurrentReplicas = desiredReplicas
+
+	return nil
+}
+
+// computeReplicasForMetrics collects the pod metrics of the scale target from all member clusters it is placed in, and
+// returns the highest replica count computed from the HPA's metrics.
+func (c *Controller) computeReplicasForMetrics(
+	ctx context.Context,
+	hpa *autoscalingv2.HorizontalPodAutoscaler,
+	typeConfig *fedcorev1a1.FederatedTypeConfig,
+	sourceObject metav1.Object,
+	currentReplicas int32,
+) (int32, []autoscalingv2.MetricStatus, error) {
+	keyedLogger := klog.FromContext(ctx)
+
+	federatedType := typeConfig.GetFederatedType()
+	fedObject, err := c.dynamicClient.Resource(schemautil.APIResourceToGVR(&federatedType)).
+		Namespace(sourceObject.GetNamespace()).
+		Get(ctx, sourceObject.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get federated object: %w", err)
+	}
+	placements, err := util.UnmarshalGenericPlacements(fedObject)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get placements: %w", err)
+	}
+
+	labelSelector, err := utilunstructured.GetLabelSelectorFromPath(
+		fedObject,
+		typeConfig.Spec.PathDefinition.LabelSelector,
+		common.TemplatePath,
+	)
+	if err != nil || labelSelector == nil {
+		return 0, nil, fmt.Errorf("failed to get label selector of scale target: %v", err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid label selector of scale target: %w", err)
+	}
+
+	clusterNames := make([]string, 0, len(placements.ClusterNameUnion()))
+	for clusterName := range placements.ClusterNameUnion() {
+		clusterNames = append(clusterNames, clusterName)
+	}
+	sort.Strings(clusterNames)
+
+	var podMetrics []podMetric
+	for _, clusterName := range clusterNames {
+		clusterPodMetrics, err := c.getPodMetricsFromCluster(ctx, clusterName, sourceObject.GetNamespace(), selector)
+		if err != nil {
+			// the global replica count cannot be computed reliably without the metrics of all clusters
+			return 0, nil, fmt.Errorf("failed to get pod metrics from cluster %s: %w", clusterName, err)
+		}
+		keyedLogger.V(3).Info("Collected pod metrics", "cluster", clusterName, "pods", len(clusterPodMetrics))
+		podMetrics = append(podMetrics, clusterPodMetrics...)
+	}
+
+	replicas := int32(0)
+	statuses := make([]autoscalingv2.MetricStatus, 0, len(hpa.Spec.Metrics))
+	for i := range hpa.Spec.Metrics {
+		metric := &hpa.Spec.Metrics[i]
+		if metric.Type != autoscalingv2.ResourceMetricSourceType || metric.Resource == nil {
+			return 0, nil, fmt.Errorf("unsupported metric source type %s", metric.Type)
+		}
+
+		metricReplicas, status, err := computeReplicasForResourceMetric(currentReplicas, metric.Resource, podMetrics)
+		if err != nil {
+			return 0, nil, err
+		}
+		statuses = append(statuses, *status)
+		if metricReplicas > replicas {
+			replicas = metricReplicas
+		}
+	}
+	if len(statuses) == 0 {
+		return 0, nil, fmt.Errorf("no metrics specified")
+	}
+
+	return replicas, statuses, nil
+}
+
+// typeConfigForScaleTarget returns the FederatedTypeConfig whose source type is the kind of the scale target.
+func (c *Controller) typeConfigForScaleTarget(
+	ref autoscalingv2.CrossVersionObjectReference,
+) (*fedcorev1a1.FederatedTypeConfig, error) {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid apiVersion of scale target: %w", err)
+	}
+
+	typeConfigs, err := c.typeConfigLister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list federated type configs: %w", err)
+	}
+
+	for _, typeConfig := range typeConfigs {
+		sourceType := typeConfig.GetSourceType()
+		if sourceType == nil || sourceType.Group != gv.Group || sourceType.Kind != ref.Kind {
+			continue
+		}
+		pathDefinition := typeConfig.Spec.PathDefinition
+		if pathDefinition.ReplicasSpec == "" || pathDefinition.LabelSelector == "" {
+			return nil, fmt.Errorf("federated type config %s does not support scaling", typeConfig.Name)
+		}
+		return typeConfig, nil
+	}
+
+	return nil, fmt.Errorf("no federated type config found for %s %s", ref.APIVersion, ref.Kind)
+}
+
+// stabilize records the recommendation computed from the metrics and returns the recommendation stabilized by the
+// HPA's behavior.
+func (c *Controller) stabilize(
+	hpa *autoscalingv2.HorizontalPodAutoscaler,
+	currentReplicas, recommendation int32,
+) int32 {
+	key := common.NewQualifiedName(hpa).String()
+	now := time.Now()
+	scaleUpWindow, scaleDownWindow := stabilizationWindows(hpa)
+	maxWindow := scaleUpWindow
+	if scaleDownWindow > maxWindow {
+		maxWindow = scaleDownWindow
+	}
+
+	c.recommendationsLock.Lock()
+	defer c.recommendationsLock.Unlock()
+
+	recommendations := c.recommendations[key]
+	stabilized := stabilizeRecommendation(currentReplicas, recommendation, recommendations, scaleUpWindow, scaleDownWindow, now)
+
+	retained := make([]timestampedRecommendation, 0, len(recommendations)+1)
+	for _, rec := range recommendations {
+		if rec.timestamp.After(now.Add(-maxWindow)) {
+			retained = append(retained, rec)
+		}
+	}
+	c.recommendations[key] = append(retained, timestampedRecommendation{recommendation: recommendation, timestamp: now})
+
+	return stabilized
+}
+
+func (c *Controller) forgetRecommendations(key string) {
+	c.recommendationsLock.Lock()
+	defer c.recommendationsLock.Unlock()
+	delete(c.recommendations, key)
+}
+
+func (c *Controller) setFailedCondition(
+	hpa *autoscalingv2.HorizontalPodAutoscaler,
+	conditionType autoscalingv2.HorizontalPodAutoscalerConditionType,
+	reason string,
+	err error,
+) {
+	c.eventRecorder.Event(hpa, corev1.EventTypeWarning, reason, err.Error())
+	setCondition(hpa, conditionType, corev1.ConditionFalse, reason, err.Error())
+}
+
+func setCondition(
+	hpa *autoscalingv2.HorizontalPodAutoscaler,
+	conditionType autoscalingv2.HorizontalPodAutoscalerConditionType,
+	status corev1.ConditionStatus,
+	reason, message string,
+) {
+	for i := range hpa.Status.Conditions {
+		condition := &hpa.Status.Conditions[i]
+		if condition.Type != conditionType {
+			continue
+		}
+		if condition.Status != status {
+			condition.LastTransitionTime = metav1.Now()
+		}
+		condition.Status = status
+		condition.Reason = reason
+		condition.Message = message
+		return
+	}
+
+	hpa.Status.Conditions = append(hpa.Status.Conditions, autoscalingv2.HorizontalPodAutoscalerCondition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	})
+}
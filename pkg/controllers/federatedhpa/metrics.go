@@ -0,0 +1,148 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedhpa
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var podMetricsGVR = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+
+// getPodMetricsFromCluster returns the metrics of the running pods matching the selector in a member cluster. The
+// usage is read from the cluster's resource metrics API and the requests from the pods' containers.
+func (c *Controller) getPodMetricsFromCluster(
+	ctx context.Context,
+	clusterName, namespace string,
+	selector labels.Selector,
+) ([]podMetric, error) {
+	kubeClient, exists, err := c.federatedClient.KubeClientsetForCluster(clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kube client: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("cluster is not joined")
+	}
+	dynamicClient, _, err := c.federatedClient.DynamicClientsetForCluster(clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dynamic client: %w", err)
+	}
+
+	listOptions := metav1.ListOptions{LabelSelector: selector.String(), ResourceVersion: "0"}
+
+	pods, err := kubeClient.CoreV1().Pods(namespace).List(ctx, listOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	metrics, err := dynamicClient.Resource(podMetricsGVR).Namespace(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pod metrics: %w", err)
+	}
+
+	usages := make(map[string]corev1.ResourceList, len(metrics.Items))
+	for i := range metrics.Items {
+		usage, err := podUsageFromMetrics(&metrics.Items[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse metrics of pod %s: %w", metrics.Items[i].GetName(), err)
+		}
+		usages[metrics.Items[i].GetName()] = usage
+	}
+
+	ret := make([]podMetric, 0, len(pods.Items))
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		usage, exists := usages[pod.Name]
+		if !exists {
+			continue
+		}
+		ret = append(ret, podMetric{usage: usage, requests: podRequests(pod)})
+	}
+
+	return ret, nil
+}
+
+// podUsageFromMetrics returns the total usage of all containers in a PodMetrics object.
+func podUsageFromMetrics(metrics *unstructured.Unstructured) (corev1.ResourceList, error) {
+	containers, _, err := unstructured.NestedSlice(metrics.Object, "containers")
+	if err != nil {
+		return nil, err
+	}
+
+	usage := corev1.ResourceList{}
+	for _, container := range containers {
+		containerMap, ok := container.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid container metrics")
+		}
+		containerUsage, _, err := unstructured.NestedStringMap(containerMap, "usage")
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range containerUsage {
+			quantity, err := resource.ParseQuantity(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid usage of %s: %w", name, err)
+			}
+			total := usage[corev1.ResourceName(name)]
+			total.Add(quantity)
+			usage[corev1.ResourceName(name)] = total
+		}
+	}
+
+	return usage, nil
+}
+
+// podRequests returns the total requests of all containers of a pod. A resource is omitted if any container does not
+// request it.
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		for name := range container.Resources.Requests {
+			requests[name] = resource.Quantity{}
+		}
+	}
+
+	for name := range requests {
+		total := resource.Quantity{}
+		for _, container := range pod.Spec.Containers {
+			quantity, exists := container.Resources.Requests[name]
+			if !exists {
+				delete(requests, name)
+				break
+			}
+			total.Add(quantity)
+		}
+		if _, exists := requests[name]; exists {
+			requests[name] = total
+		}
+	}
+
+	return requests
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedhpa
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// The tolerance of the usage ratio within which no scaling is performed, same as the default of the
+	// kube-controller-manager.
+	defaultTolerance = 0.1
+
+	defaultScaleUpStabilizationWindow   = 0 * time.Second
+	defaultScaleDownStabilizationWindow = 300 * time.Second
+)
+
+// podMetric is the resource usage and requests of a running pod in a member cluster.
+type podMetric struct {
+	usage    corev1.ResourceList
+	requests corev1.ResourceList
+}
+
+// timestampedRecommendation is a replica recommendation computed from the metrics at a point in time.
+type timestampedRecommendation struct {
+	recommendation int32
+	timestamp      time.Time
+}
+
+// computeReplicasForResourceMetric computes the number of replicas required to bring the average usage of the given
+// resource across all pods of all member clusters to the target of the metric.
+func computeReplicasForResourceMetric(
+	currentReplicas int32,
+	metric *autoscalingv2.ResourceMetricSource,
+	podMetrics []podMetric,
+) (int32, *autoscalingv2.MetricStatus, error) {
+	if len(podMetrics) == 0 {
+		return 0, nil, fmt.Errorf("no metrics returned for resource %s", metric.Name)
+	}
+
+	totalUsage := int64(0)
+	totalRequests := int64(0)
+	missingRequests := false
+	for _, pod := range podMetrics {
+		usage := pod.usage[metric.Name]
+		totalUsage += usage.MilliValue()
+
+		request, exists := pod.requests[metric.Name]
+		if !exists {
+			missingRequests = true
+			continue
+		}
+		totalRequests += request.MilliValue()
+	}
+
+	status := &autoscalingv2.MetricStatus{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricStatus{
+			Name: metric.Name,
+		},
+	}
+
+	var usageRatio float64
+	averageUsage := totalUsage / int64(len(podMetrics))
+	status.Resource.Current.AverageValue = resource.NewMilliQuantity(averageUsage, resource.DecimalSI)
+
+	switch {
+	case metric.Target.AverageUtilization != nil:
+		if missingRequests || totalRequests == 0 {
+			return 0, nil, fmt.Errorf("missing request for resource %s", metric.Name)
+		}
+		utilization := int32(totalUsage * 100 / totalRequests)
+		status.Resource.Current.AverageUtilization = &utilization
+		usageRatio = float64(utilization) / float64(*metric.Target.AverageUtilization)
+	case metric.Target.AverageValue != nil:
+		usageRatio = float64(averageUsage) / float64(metric.Target.AverageValue.MilliValue())
+	default:
+		return 0, nil, fmt.Errorf("unsupported target for resource %s", metric.Name)
+	}
+
+	if math.Abs(1.0-usageRatio) <= defaultTolerance {
+		return currentReplicas, status, nil
+	}
+
+	return int32(math.Ceil(usageRatio * float64(len(podMetrics)))), status, nil
+}
+
+// stabilizeRecommendation limits the rate of scaling using the recommendations within the stabilization windows.
+// Scaling up is limited to the lowest recommendation within the scale up window, and scaling down is limited to the
+// highest recommendation within the scale down window.
+func stabilizeRecommendation(
+	currentReplicas, recommendation int32,
+	recommendations []timestampedRecommendation,
+	scaleUpWindow, scaleDownWindow time.Duration,
+	now time.Time,
+) int32 {
+	upRecommendation := recommendation
+	downRecommendation := recommendation
+	for _, rec := range recommendations {
+		if rec.timestamp.After(now.Add(-scaleUpWindow)) && rec.recommendation < upRecommendation {
+			upRecommendation = rec.recommendation
+		}
+		if rec.timestamp.After(now.Add(-scaleDownWindow)) && rec.recommendation > downRecommendation {
+			downRecommendation = rec.recommendation
+		}
+	}
+
+	stabilized := currentReplicas
+	if stabilized < upRecommendation {
+		stabilized = upRecommendation
+	}
+	if stabilized > downRecommendation {
+		stabilized = downRecommendation
+	}
+	return stabilized
+}
+
+// stabilizationWindows returns the scale up and scale down stabilization windows configured in the HPA's behavior.
+func stabilizationWindows(hpa *autoscalingv2.HorizontalPodAutoscaler) (time.Duration, time.Duration) {
+	scaleUpWindow := defaultScaleUpStabilizationWindow
+	scaleDownWindow := defaultScaleDownStabilizationWindow
+	if behavior := hpa.Spec.Behavior; behavior != nil {
+		if behavior.ScaleUp != nil && behavior.ScaleUp.StabilizationWindowSeconds != nil {
+			scaleUpWindow = time.Duration(*behavior.ScaleUp.StabilizationWindowSeconds) * time.Second
+		}
+		if behavior.ScaleDown != nil && behavior.ScaleDown.StabilizationWindowSeconds != nil {
+			scaleDownWindow = time.Duration(*behavior.ScaleDown.StabilizationWindowSeconds) * time.Second
+		}
+	}
+	return scaleUpWindow, scaleDownWindow
+}
+
+// clampReplicas limits the replicas to the min and max replicas of the HPA.
+func clampReplicas(hpa *autoscalingv2.HorizontalPodAutoscaler, replicas int32) int32 {
+	minReplicas := int32(1)
+	if hpa.Spec.MinReplicas != nil {
+		minReplicas = *hpa.Spec.MinReplicas
+	}
+	if replicas < minReplicas {
+		return minReplicas
+	}
+	if replicas > hpa.Spec.MaxReplicas {
+		return hpa.Spec.MaxReplicas
+	}
+	return replicas
+}
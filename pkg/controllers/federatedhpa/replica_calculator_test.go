@@ -0,0 +1,223 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedhpa
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+)
+
+func newPodMetric(cpuUsage, cpuRequest string) podMetric {
+	metric := podMetric{
+		usage:    corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpuUsage)},
+		requests: corev1.ResourceList{},
+	}
+	if cpuRequest != "" {
+		metric.requests[corev1.ResourceCPU] = resource.MustParse(cpuRequest)
+	}
+	return metric
+}
+
+func TestComputeReplicasForResourceMetric(t *testing.T) {
+	utilizationTarget := &autoscalingv2.ResourceMetricSource{
+		Name:   corev1.ResourceCPU,
+		Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: pointer.Int32(50)},
+	}
+	averageValueTarget := &autoscalingv2.ResourceMetricSource{
+		Name: corev1.ResourceCPU,
+		Target: autoscalingv2.MetricTarget{
+			Type:         autoscalingv2.AverageValueMetricType,
+			AverageValue: resource.NewMilliQuantity(200, resource.DecimalSI),
+		},
+	}
+
+	testCases := map[string]struct {
+		currentReplicas int32
+		metric          *autoscalingv2.ResourceMetricSource
+		podMetrics      []podMetric
+		expected        int32
+		expectedErr     bool
+	}{
+		"scale up by utilization across clusters": {
+			currentReplicas: 4,
+			metric:          utilizationTarget,
+			podMetrics: []podMetric{
+				newPodMetric("1", "1"),
+				newPodMetric("1", "1"),
+				newPodMetric("500m", "1"),
+				newPodMetric("500m", "1"),
+			},
+			expected: 6,
+		},
+		"scale down by utilization": {
+			currentReplicas: 4,
+			metric:          utilizationTarget,
+			podMetrics: []podMetric{
+				newPodMetric("100m", "1"),
+				newPodMetric("100m", "1"),
+				newPodMetric("100m", "1"),
+				newPodMetric("100m", "1"),
+			},
+			expected: 1,
+		},
+		"no scaling within tolerance": {
+			currentReplicas: 4,
+			metric:          utilizationTarget,
+			podMetrics: []podMetric{
+				newPodMetric("550m", "1"),
+				newPodMetric("500m", "1"),
+			},
+			expected: 4,
+		},
+		"scale up by average value": {
+			currentReplicas: 2,
+			metric:          averageValueTarget,
+			podMetrics: []podMetric{
+				newPodMetric("400m", ""),
+				newPodMetric("400m", ""),
+			},
+			expected: 4,
+		},
+		"missing requests for utilization": {
+			currentReplicas: 2,
+			metric:          utilizationTarget,
+			podMetrics: []podMetric{
+				newPodMetric("400m", "1"),
+				newPodMetric("400m", ""),
+			},
+			expectedErr: true,
+		},
+		"no pod metrics": {
+			currentReplicas: 2,
+			metric:          utilizationTarget,
+			expectedErr:     true,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			replicas, status, err := computeReplicasForResourceMetric(tc.currentReplicas, tc.metric, tc.podMetrics)
+			if tc.expectedErr {
+				g.Expect(err).To(gomega.HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(replicas).To(gomega.Equal(tc.expected))
+			g.Expect(status.Resource.Name).To(gomega.Equal(corev1.ResourceCPU))
+		})
+	}
+}
+
+func TestStabilizeRecommendation(t *testing.T) {
+	now := time.Now()
+	recommendations := []timestampedRecommendation{
+		{recommendation: 10, timestamp: now.Add(-10 * time.Minute)},
+		{recommendation: 8, timestamp: now.Add(-2 * time.Minute)},
+		{recommendation: 6, timestamp: now.Add(-30 * time.Second)},
+	}
+
+	testCases := map[string]struct {
+		currentReplicas int32
+		recommendation  int32
+		scaleUpWindow   time.Duration
+		scaleDownWindow time.Duration
+		expected        int32
+	}{
+		"scale down is limited by recent recommendations": {
+			currentReplicas: 10,
+			recommendation:  4,
+			scaleUpWindow:   0,
+			scaleDownWindow: 5 * time.Minute,
+			expected:        8,
+		},
+		"scale down without window": {
+			currentReplicas: 10,
+			recommendation:  4,
+			scaleUpWindow:   0,
+			scaleDownWindow: 0,
+			expected:        4,
+		},
+		"scale up is immediate without window": {
+			currentReplicas: 5,
+			recommendation:  12,
+			scaleUpWindow:   0,
+			scaleDownWindow: 5 * time.Minute,
+			expected:        12,
+		},
+		"scale up is limited by recent recommendations": {
+			currentReplicas: 5,
+			recommendation:  12,
+			scaleUpWindow:   time.Minute,
+			scaleDownWindow: 5 * time.Minute,
+			expected:        6,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			stabilized := stabilizeRecommendation(
+				tc.currentReplicas,
+				tc.recommendation,
+				recommendations,
+				tc.scaleUpWindow,
+				tc.scaleDownWindow,
+				now,
+			)
+			g.Expect(stabilized).To(gomega.Equal(tc.expected))
+		})
+	}
+}
+
+func TestPodUsageAndRequests(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	metrics := &unstructured.Unstructured{Object: map[string]interface{}{
+		"containers": []interface{}{
+			map[string]interface{}{"name": "a", "usage": map[string]interface{}{"cpu": "100m", "memory": "10Mi"}},
+			map[string]interface{}{"name": "b", "usage": map[string]interface{}{"cpu": "200m"}},
+		},
+	}}
+	usage, err := podUsageFromMetrics(metrics)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	cpu := usage[corev1.ResourceCPU]
+	g.Expect(cpu.MilliValue()).To(gomega.Equal(int64(300)))
+
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{
+		{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("100m"),
+			corev1.ResourceMemory: resource.MustParse("10Mi"),
+		}}},
+		{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("200m"),
+		}}},
+	}}}
+	requests := podRequests(pod)
+	g.Expect(requests).To(gomega.HaveLen(1))
+	cpu = requests[corev1.ResourceCPU]
+	g.Expect(cpu.MilliValue()).To(gomega.Equal(int64(300)))
+}
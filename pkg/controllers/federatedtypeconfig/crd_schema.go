@@ -48,6 +48,10 @@ openAPIV3Schema:
 					items:
 						type: object
 						properties:
+							driftedFields:
+								type: array
+								items:
+									type: string
 							generation:
 								type: integer
 							name:
@@ -137,6 +137,13 @@ func NewController(
 		stopChannels:           make(map[string]chan struct{}),
 	}
 
+	// The sync controllers are started after the informer factories, so the informers they share must be
+	// registered beforehand for the factories to start them.
+	kubeInformerFactory.Core().V1().Namespaces().Informer()
+	kubeInformerFactory.Core().V1().ConfigMaps().Informer()
+	fedInformerFactory.Core().V1alpha1().PropagatedVersions().Informer()
+	fedInformerFactory.Core().V1alpha1().ClusterPropagatedVersions().Informer()
+
 	c.worker = worker.NewReconcileWorker(c.reconcile, worker.WorkerTiming{}, 1, config.Metrics,
 		delayingdeliver.NewMetricTags("typeconfig-worker", "FederatedTypeConfig"))
 
@@ -231,7 +238,9 @@ func (c *Controller) reconcile(qualifiedName common.QualifiedName) worker.Result
 	syncEnabled := typeConfig.GetPropagationEnabled()
 	statusEnabled := typeConfig.GetStatusEnabled()
 	statusAggregationEnabled := typeConfig.GetStatusAggregationEnabled()
-	policyRcEnabled := typeConfig.GetPolicyRcEnabled()
+	// The policyrc controller counts the references to policies across all federated objects, so it is run by the
+	// leader instead of the shards if sharding is enabled.
+	policyRcEnabled := typeConfig.GetPolicyRcEnabled() && c.controllerConfig.Shard == nil
 	controllers := sets.New[string]()
 	for _, controllerGroup := range typeConfig.GetControllers() {
 		for _, controller := range controllerGroup {
@@ -678,7 +687,10 @@ func (c *Controller) startSyncController(tc *fedcorev1a1.FederatedTypeConfig) er
 		ftc,
 		fedNamespaceAPIResource,
 		c.controllerRevisionStore,
-		c.controllerRevisionController)
+		c.controllerRevisionController,
+		c.kubeInformerFactory,
+		c.fedInformerFactory,
+	)
 	if err != nil {
 		close(stopChan)
 		return errors.Wrapf(err, "Error starting sync controller for %q", kind)
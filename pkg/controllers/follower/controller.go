@@ -137,7 +137,7 @@ func NewFollowerController(
 	fedClient fedclient.Interface,
 	informerFactory dynamicinformer.DynamicSharedInformerFactory,
 	metrics stats.Metrics,
-	workerCount int,
+	workerConfig worker.ConfigSource,
 ) (*Controller, error) {
 	c := &Controller{
 		name:                       ControllerName,
@@ -170,12 +170,12 @@ func NewFollowerController(
 			informer:    informerFactory.ForResource(federatedGVR),
 			client:      dynamicClient.Resource(federatedGVR),
 		}
-		handles.worker = worker.NewReconcileWorker(
+		handles.worker = worker.NewConfigurableReconcileWorker(
 			func(qualifiedName common.QualifiedName) worker.Result {
 				return reconcile(handles, qualifiedName)
 			},
 			worker.WorkerTiming{},
-			workerCount,
+			workerConfig,
 			c.metrics,
 			delayingdeliver.NewMetricTags("follower-controller-worker", handles.name),
 		)
@@ -0,0 +1,495 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package globaldns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	kubeinformer "k8s.io/client-go/informers"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	networkingv1informers "k8s.io/client-go/informers/networking/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	networkingv1listers "k8s.io/client-go/listers/networking/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedcorev1a1informers "github.com/kubewharf/kubeadmiral/pkg/client/informers/externalversions/core/v1alpha1"
+	fedcorev1a1listers "github.com/kubewharf/kubeadmiral/pkg/client/listers/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/globaldns/provider"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/delayingdeliver"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/federatedclient"
+	finalizersutil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/finalizers"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/worker"
+	"github.com/kubewharf/kubeadmiral/pkg/stats"
+)
+
+const (
+	ControllerName = "global-dns-controller"
+
+	FinalizerGlobalDNSController = common.DefaultPrefix + "global-dns-controller"
+
+	// DefaultTTL is the TTL of the published records if none is specified.
+	DefaultTTL = 60
+
+	// The interval at which the records are republished, in case they were modified in the DNS backend.
+	resyncPeriod = 5 * time.Minute
+	// The delay before republishing an object while the informers of some member clusters are not yet synced.
+	informerSyncRetryDelay = 5 * time.Second
+)
+
+// Controller publishes the load balancer addresses of a Service or Ingress in all member clusters under a global DNS
+// name. Services and Ingresses in the host cluster that are annotated with common.GlobalDNSNameAnnotation are
+// published. The addresses are weighted according to the health of their clusters, and the addresses in clusters that
+// are not ready are omitted.
+//
+// Pull-mode clusters are not supported as they are not reachable from the control plane.
+type Controller struct {
+	name string
+
+	serviceSynced cache.InformerSynced
+	ingressSynced cache.InformerSynced
+	clusterLister fedcorev1a1listers.FederatedClusterLister
+	clusterSynced cache.InformerSynced
+
+	federatedClient federatedclient.FederatedClientFactory
+	provider        provider.Provider
+
+	services  *resourceType
+	ingresses *resourceType
+
+	// memberClustersLock guards memberClusters
+	memberClustersLock sync.RWMutex
+	// memberClusters contains the informers of the member clusters reachable through the federated client factory
+	memberClusters map[string]*memberCluster
+
+	serviceWorker worker.ReconcileWorker
+	ingressWorker worker.ReconcileWorker
+
+	metrics stats.Metrics
+	logger  klog.Logger
+}
+
+type memberCluster struct {
+	informerFactory kubeinformer.SharedInformerFactory
+	serviceLister   corev1listers.ServiceLister
+	serviceSynced   cache.InformerSynced
+	ingressLister   networkingv1listers.IngressLister
+	ingressSynced   cache.InformerSynced
+}
+
+func (m *memberCluster) hasSynced() bool {
+	return m.serviceSynced() && m.ingressSynced()
+}
+
+// IsControllerReady implements controllermanager.Controller
+func (c *Controller) IsControllerReady() bool {
+	return c.HasSynced()
+}
+
+func NewGlobalDNSController(
+	kubeClient kubernetes.Interface,
+	serviceInformer corev1informers.ServiceInformer,
+	ingressInformer networkingv1informers.IngressInformer,
+	clusterInformer fedcorev1a1informers.FederatedClusterInformer,
+	federatedClient federatedclient.FederatedClientFactory,
+	dnsProvider provider.Provider,
+	metrics stats.Metrics,
+	workerConfig worker.ConfigSource,
+) (*Controller, error) {
+	if dnsProvider == nil {
+		return nil, fmt.Errorf("no DNS provider is configured")
+	}
+
+	c := &Controller{
+		name:            ControllerName,
+		serviceSynced:   serviceInformer.Informer().HasSynced,
+		ingressSynced:   ingressInformer.Informer().HasSynced,
+		clusterLister:   clusterInformer.Lister(),
+		clusterSynced:   clusterInformer.Informer().HasSynced,
+		federatedClient: federatedClient,
+		provider:        dnsProvider,
+		services:        newServiceResourceType(kubeClient, serviceInformer.Lister()),
+		ingresses:       newIngressResourceType(kubeClient, ingressInformer.Lister()),
+		memberClusters:  map[string]*memberCluster{},
+		metrics:         metrics,
+		logger:          klog.LoggerWithValues(klog.Background(), "controller", ControllerName),
+	}
+
+	c.serviceWorker = worker.NewConfigurableReconcileWorker(
+		func(qualifiedName common.QualifiedName) worker.Result {
+			return c.reconcile(c.services, qualifiedName)
+		},
+		worker.WorkerTiming{},
+		workerConfig,
+		metrics,
+		delayingdeliver.NewMetricTags("global-dns-worker", "Service"),
+	)
+	c.ingressWorker = worker.NewConfigurableReconcileWorker(
+		func(qualifiedName common.QualifiedName) worker.Result {
+			return c.reconcile(c.ingresses, qualifiedName)
+		},
+		worker.WorkerTiming{},
+		workerConfig,
+		metrics,
+		delayingdeliver.NewMetricTags("global-dns-worker", "Ingress"),
+	)
+
+	serviceInformer.Informer().AddEventHandler(util.NewTriggerOnAllChanges(c.serviceWorker.EnqueueObject))
+	ingressInformer.Informer().AddEventHandler(util.NewTriggerOnAllChanges(c.ingressWorker.EnqueueObject))
+	clusterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldCluster := oldObj.(*fedcorev1a1.FederatedCluster)
+			newCluster := newObj.(*fedcorev1a1.FederatedCluster)
+			if clusterWeight(oldCluster) != clusterWeight(newCluster) {
+				c.enqueuePublishedObjects()
+			}
+		},
+	})
+	federatedClient.AddClientUpdateHandler(c.handleClientUpdate)
+
+	return c, nil
+}
+
+func (c *Controller) Run(ctx context.Context) {
+	c.logger.Info("Starting controller")
+	defer c.logger.Info("Stopping controller")
+
+	if !cache.WaitForNamedCacheSync(c.name, ctx.Done(), c.HasSynced) {
+		return
+	}
+	c.serviceWorker.Run(ctx.Done())
+	c.ingressWorker.Run(ctx.Done())
+
+	<-ctx.Done()
+}
+
+func (c *Controller) HasSynced() bool {
+	return c.serviceSynced() && c.ingressSynced() && c.clusterSynced()
+}
+
+// handleClientUpdate registers the event handlers of the controller with the informers of a member cluster whenever
+// the clients of the cluster are rebuilt.
+func (c *Controller) handleClientUpdate(cluster string, factory federatedclient.FederatedClientFactory) {
+	c.memberClustersLock.Lock()
+	defer c.memberClustersLock.Unlock()
+
+	informerFactory, exists, err := factory.KubeSharedInformerFactoryForCluster(cluster)
+	if err != nil || !exists {
+		if _, ok := c.memberClusters[cluster]; ok {
+			delete(c.memberClusters, cluster)
+			c.enqueuePublishedObjects()
+		}
+		return
+	}
+	if existing := c.memberClusters[cluster]; existing != nil && existing.informerFactory == informerFactory {
+		return
+	}
+
+	serviceInformer := informerFactory.Core().V1().Services()
+	serviceInformer.Informer().AddEventHandler(util.NewTriggerOnAllChanges(func(obj pkgruntime.Object) {
+		c.enqueueIfPublished(c.services, c.serviceWorker, obj)
+	}))
+	ingressInformer := informerFactory.Networking().V1().Ingresses()
+	ingressInformer.Informer().AddEventHandler(util.NewTriggerOnAllChanges(func(obj pkgruntime.Object) {
+		c.enqueueIfPublished(c.ingresses, c.ingressWorker, obj)
+	}))
+
+	c.memberClusters[cluster] = &memberCluster{
+		informerFactory: informerFactory,
+		serviceLister:   serviceInformer.Lister(),
+		serviceSynced:   serviceInformer.Informer().HasSynced,
+		ingressLister:   ingressInformer.Lister(),
+		ingressSynced:   ingressInformer.Informer().HasSynced,
+	}
+	c.enqueuePublishedObjects()
+}
+
+func (c *Controller) getMemberClusters() map[string]*memberCluster {
+	c.memberClustersLock.RLock()
+	defer c.memberClustersLock.RUnlock()
+
+	clusters := make(map[string]*memberCluster, len(c.memberClusters))
+	for name, cluster := range c.memberClusters {
+		clusters[name] = cluster
+	}
+	return clusters
+}
+
+// enqueueIfPublished enqueues the object in the host cluster corresponding to an object in a member cluster if it is
+// published.
+func (c *Controller) enqueueIfPublished(
+	resourceType *resourceType,
+	reconcileWorker worker.ReconcileWorker,
+	memberObj pkgruntime.Object,
+) {
+	metaObj, ok := memberObj.(metav1.Object)
+	if !ok {
+		return
+	}
+	qualifiedName := common.QualifiedName{Namespace: metaObj.GetNamespace(), Name: metaObj.GetName()}
+	if obj, err := resourceType.get(qualifiedName); err == nil && isPublished(obj) {
+		reconcileWorker.Enqueue(qualifiedName)
+	}
+}
+
+func (c *Controller) enqueuePublishedObjects() {
+	for resourceType, reconcileWorker := range map[*resourceType]worker.ReconcileWorker{
+		c.services:  c.serviceWorker,
+		c.ingresses: c.ingressWorker,
+	} {
+		objs, err := resourceType.list()
+		if err != nil {
+			c.logger.Error(err, "Failed to list objects", "kind", resourceType.kind)
+			continue
+		}
+		for _, obj := range objs {
+			if isPublished(obj) {
+				reconcileWorker.EnqueueObject(obj)
+			}
+		}
+	}
+}
+
+// isPublished returns true if records are or should be published for the object.
+func isPublished(obj object) bool {
+	annotations := obj.GetAnnotations()
+	return annotations[common.GlobalDNSNameAnnotation] != "" || annotations[common.GlobalDNSPublishedNameAnnotation] != ""
+}
+
+// getTTL returns the TTL of the records of the object.
+func getTTL(obj object) (int64, error) {
+	value, ok := obj.GetAnnotations()[common.GlobalDNSTTLAnnotation]
+	if !ok {
+		return DefaultTTL, nil
+	}
+	ttl, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || ttl <= 0 {
+		return 0, fmt.Errorf("invalid TTL %q", value)
+	}
+	return ttl, nil
+}
+
+func (c *Controller) reconcile(resourceType *resourceType, qualifiedName common.QualifiedName) (status worker.Result) {
+	key := qualifiedName.String()
+	keyedLogger := c.logger.WithValues("control-loop", "reconcile", "kind", resourceType.kind, "object", key)
+	ctx := klog.NewContext(context.TODO(), keyedLogger)
+
+	startTime := time.Now()
+	c.metrics.Rate("global-dns.throughput", 1)
+	keyedLogger.V(3).Info("Start reconcile")
+	defer func() {
+		c.metrics.Duration(fmt.Sprintf("%s.latency", c.name), startTime)
+		keyedLogger.V(3).Info("Finished reconcile", "duration", time.Since(startTime), "status", status.String())
+	}()
+
+	obj, err := resourceType.get(qualifiedName)
+	if apierrors.IsNotFound(err) {
+		return worker.StatusAllOK
+	}
+	if err != nil {
+		keyedLogger.Error(err, "Failed to get object from store")
+		return worker.StatusError
+	}
+	obj = obj.DeepCopyObject().(object)
+
+	name := obj.GetAnnotations()[common.GlobalDNSNameAnnotation]
+	publishedName := obj.GetAnnotations()[common.GlobalDNSPublishedNameAnnotation]
+	owner := recordOwner(resourceType, qualifiedName)
+
+	if obj.GetDeletionTimestamp() != nil || name == "" {
+		return c.unpublish(ctx, resourceType, obj, publishedName, owner)
+	}
+
+	ttl, err := getTTL(obj)
+	if err != nil {
+		keyedLogger.Error(err, "Failed to get TTL")
+		return worker.StatusAllOK
+	}
+
+	if publishedName != "" && publishedName != name {
+		keyedLogger.V(1).Info("Deleting records of previous name", "name", publishedName)
+		if err := c.provider.DeleteRecord(ctx, publishedName, owner); err != nil {
+			keyedLogger.Error(err, "Failed to delete records")
+			return worker.StatusError
+		}
+	}
+
+	// record the published name before publishing so that the records are deleted if the object is deleted
+	updated, err := finalizersutil.AddFinalizers(obj, sets.NewString(FinalizerGlobalDNSController))
+	if err != nil {
+		keyedLogger.Error(err, "Failed to add finalizer")
+		return worker.StatusError
+	}
+	if publishedName != name {
+		annotations := obj.GetAnnotations()
+		annotations[common.GlobalDNSPublishedNameAnnotation] = name
+		obj.SetAnnotations(annotations)
+		updated = true
+	}
+	if updated {
+		if err := resourceType.update(ctx, obj); err != nil {
+			keyedLogger.Error(err, "Failed to update object")
+			if apierrors.IsConflict(err) {
+				return worker.StatusConflict
+			}
+			return worker.StatusError
+		}
+	}
+
+	addressesByCluster, complete := c.collectLoadBalancerAddresses(ctx, resourceType, qualifiedName)
+
+	weights := map[string]int32{}
+	for cluster := range addressesByCluster {
+		fedCluster, err := c.clusterLister.Get(cluster)
+		if err != nil && !apierrors.IsNotFound(err) {
+			keyedLogger.Error(err, "Failed to get cluster from store")
+			return worker.StatusError
+		}
+		if err == nil {
+			weights[cluster] = clusterWeight(fedCluster)
+		}
+	}
+
+	record := &provider.Record{Name: name, TTL: ttl, Targets: buildTargets(addressesByCluster, weights), Owner: owner}
+	if len(record.Targets) == 0 {
+		keyedLogger.V(2).Info("No load balancer addresses found, deleting records", "name", name)
+		err = c.provider.DeleteRecord(ctx, name, owner)
+	} else {
+		keyedLogger.V(2).Info("Publishing records", "name", name, "targets", record.Targets)
+		err = c.provider.EnsureRecord(ctx, record)
+	}
+	if errors.Is(err, provider.ErrRecordConflict) {
+		// retrying is pointless until the other owner releases the name
+		keyedLogger.Error(err, "Failed to publish records", "name", name)
+		delay := resyncPeriod
+		return worker.Result{Success: true, RequeueAfter: &delay}
+	}
+	if err != nil {
+		keyedLogger.Error(err, "Failed to publish records")
+		return worker.StatusError
+	}
+
+	delay := resyncPeriod
+	if !complete {
+		delay = informerSyncRetryDelay
+	}
+	return worker.Result{Success: true, RequeueAfter: &delay}
+}
+
+// unpublish deletes the published records of the object and removes its finalizer.
+func (c *Controller) unpublish(
+	ctx context.Context,
+	resourceType *resourceType,
+	obj object,
+	publishedName string,
+	owner string,
+) worker.Result {
+	keyedLogger := klog.FromContext(ctx)
+
+	if publishedName != "" {
+		keyedLogger.V(1).Info("Deleting records", "name", publishedName)
+		if err := c.provider.DeleteRecord(ctx, publishedName, owner); err != nil {
+			keyedLogger.Error(err, "Failed to delete records")
+			return worker.StatusError
+		}
+	}
+
+	updated, err := finalizersutil.RemoveFinalizers(obj, sets.NewString(FinalizerGlobalDNSController))
+	if err != nil {
+		keyedLogger.Error(err, "Failed to remove finalizer")
+		return worker.StatusError
+	}
+	if publishedName != "" {
+		annotations := obj.GetAnnotations()
+		delete(annotations, common.GlobalDNSPublishedNameAnnotation)
+		obj.SetAnnotations(annotations)
+		updated = true
+	}
+	if updated {
+		if err := resourceType.update(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			keyedLogger.Error(err, "Failed to update object")
+			if apierrors.IsConflict(err) {
+				return worker.StatusConflict
+			}
+			return worker.StatusError
+		}
+	}
+
+	return worker.StatusAllOK
+}
+
+// recordOwner returns the owner of the records published for the object.
+func recordOwner(resourceType *resourceType, qualifiedName common.QualifiedName) string {
+	return fmt.Sprintf("%s/%s", resourceType.kind, qualifiedName.String())
+}
+
+// collectLoadBalancerAddresses returns the load balancer addresses of the object in each member cluster. Clusters
+// that are not ready or whose informers have not synced are skipped, so that an unreachable cluster does not block
+// publishing the addresses in the other clusters. It returns false if the informers of some ready clusters have not
+// synced yet, in which case the object should be retried shortly.
+func (c *Controller) collectLoadBalancerAddresses(
+	ctx context.Context,
+	resourceType *resourceType,
+	qualifiedName common.QualifiedName,
+) (map[string][]string, bool) {
+	keyedLogger := klog.FromContext(ctx)
+
+	result := map[string][]string{}
+	complete := true
+	for clusterName, cluster := range c.getMemberClusters() {
+		fedCluster, err := c.clusterLister.Get(clusterName)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				keyedLogger.Error(err, "Failed to get cluster from store", "cluster", clusterName)
+			}
+			continue
+		}
+		if !util.IsClusterReady(&fedCluster.Status) {
+			continue
+		}
+		if !cluster.hasSynced() {
+			keyedLogger.V(3).Info("Skipping cluster whose informers have not synced", "cluster", clusterName)
+			complete = false
+			continue
+		}
+
+		addresses, err := resourceType.loadBalancerAddresses(cluster, qualifiedName)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				keyedLogger.Error(err, "Failed to get object from member cluster", "cluster", clusterName)
+			}
+			continue
+		}
+		if len(addresses) > 0 {
+			result[clusterName] = addresses
+		}
+	}
+	return result, complete
+}
@@ -0,0 +1,289 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package corednsetcd implements a DNS provider that maintains records in the etcd backend of the CoreDNS etcd plugin.
+// Records are stored in the SkyDNS message format through the JSON gateway of the etcd v3 API.
+package corednsetcd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/globaldns/provider"
+)
+
+const (
+	// DefaultPrefix is the default path prefix of the CoreDNS etcd plugin.
+	DefaultPrefix = "/skydns"
+
+	// recordKeyPrefix distinguishes the keys managed by the provider from other keys of the same name.
+	recordKeyPrefix = "kubeadmiral-"
+)
+
+// Config is the configuration of the CoreDNS etcd provider.
+type Config struct {
+	// Endpoint is the URL of the etcd server, e.g. https://127.0.0.1:2379.
+	Endpoint string
+	// Prefix is the path prefix configured in the CoreDNS etcd plugin.
+	Prefix string
+	// Timeout is the timeout of each request to etcd.
+	Timeout time.Duration
+	// CAFile is the path of the CA bundle used to verify the certificate of the etcd server. The system roots are
+	// used if it is empty.
+	CAFile string
+	// CertFile and KeyFile are the paths of the client certificate and its private key presented to the etcd server
+	// for mutual TLS.
+	CertFile string
+	KeyFile  string
+}
+
+// skyDNSMessage is the value of a record in the SkyDNS format read by the CoreDNS etcd plugin.
+type skyDNSMessage struct {
+	Host   string `json:"host"`
+	TTL    uint32 `json:"ttl,omitempty"`
+	Weight int32  `json:"weight,omitempty"`
+}
+
+type corednsEtcdProvider struct {
+	endpoint string
+	prefix   string
+	client   *http.Client
+}
+
+var _ provider.Provider = &corednsEtcdProvider{}
+
+// NewProvider returns a provider that writes records to the etcd server.
+func NewProvider(config Config) (provider.Provider, error) {
+	if config.Endpoint == "" {
+		return nil, fmt.Errorf("etcd endpoint must be specified")
+	}
+
+	p := &corednsEtcdProvider{
+		endpoint: strings.TrimSuffix(config.Endpoint, "/"),
+		prefix:   strings.TrimSuffix(config.Prefix, "/"),
+		client:   &http.Client{Timeout: config.Timeout},
+	}
+	if p.prefix == "" {
+		p.prefix = DefaultPrefix
+	}
+	if p.client.Timeout <= 0 {
+		p.client.Timeout = 10 * time.Second
+	}
+
+	tlsConfig, err := buildTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		p.client.Transport = transport
+	}
+	return p, nil
+}
+
+// buildTLSConfig returns the TLS configuration of the client, or nil if no CA bundle or client certificate is
+// configured.
+func buildTLSConfig(config Config) (*tls.Config, error) {
+	if config.CAFile == "" && config.CertFile == "" && config.KeyFile == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.CAFile != "" {
+		data, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read etcd CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in etcd CA file %s", config.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if config.CertFile != "" || config.KeyFile != "" {
+		if config.CertFile == "" || config.KeyFile == "" {
+			return nil, fmt.Errorf("etcd client certificate and key must be specified together")
+		}
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load etcd client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// keyPrefix returns the prefix of the keys of the records of the name, e.g. /skydns/com/example/www/kubeadmiral- for
+// www.example.com.
+func (p *corednsEtcdProvider) keyPrefix(name string) string {
+	labels := strings.Split(strings.TrimSuffix(strings.ToLower(name), "."), ".")
+	for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+		labels[i], labels[j] = labels[j], labels[i]
+	}
+	return fmt.Sprintf("%s/%s/%s", p.prefix, strings.Join(labels, "/"), recordKeyPrefix)
+}
+
+func (p *corednsEtcdProvider) EnsureRecord(ctx context.Context, record *provider.Record) error {
+	keyPrefix := p.keyPrefix(record.Name)
+
+	desired := map[string]string{}
+	for i, target := range record.Targets {
+		value, err := json.Marshal(skyDNSMessage{Host: target.Address, TTL: uint32(record.TTL), Weight: target.Weight})
+		if err != nil {
+			return err
+		}
+		desired[fmt.Sprintf("%s%d", keyPrefix, i)] = string(value)
+	}
+
+	existingKeys, err := p.listKeys(ctx, keyPrefix)
+	if err != nil {
+		return err
+	}
+
+	// etcd rejects transactions that delete and put the same key, so only stale keys are deleted
+	ops := []txnOp{}
+	for _, key := range existingKeys {
+		if _, ok := desired[key]; !ok {
+			ops = append(ops, txnOp{RequestDeleteRange: &rangeRequest{Key: encode(key)}})
+		}
+	}
+	for key, value := range desired {
+		ops = append(ops, txnOp{RequestPut: &putRequest{Key: encode(key), Value: encode(value)}})
+	}
+
+	return p.post(ctx, "/v3/kv/txn", txnRequest{Success: ops}, nil)
+}
+
+// DeleteRecord implements provider.Provider. The provider does not track the owners of records.
+func (p *corednsEtcdProvider) DeleteRecord(ctx context.Context, name, _ string) error {
+	keyPrefix := p.keyPrefix(name)
+	return p.post(ctx, "/v3/kv/deleterange", rangeRequest{
+		Key:      encode(keyPrefix),
+		RangeEnd: encode(prefixEnd(keyPrefix)),
+	}, nil)
+}
+
+func (p *corednsEtcdProvider) listKeys(ctx context.Context, keyPrefix string) ([]string, error) {
+	resp := rangeResponse{}
+	if err := p.post(ctx, "/v3/kv/range", rangeRequest{
+		Key:      encode(keyPrefix),
+		RangeEnd: encode(prefixEnd(keyPrefix)),
+		KeysOnly: true,
+	}, &resp); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(resp.KVs))
+	for _, kv := range resp.KVs {
+		key, err := base64.StdEncoding.DecodeString(kv.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode key: %w", err)
+		}
+		keys = append(keys, string(key))
+	}
+	return keys, nil
+}
+
+func (p *corednsEtcdProvider) post(ctx context.Context, path string, body interface{}, result interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to etcd failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response from etcd: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request to etcd failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+	if result != nil {
+		if err := json.Unmarshal(respBody, result); err != nil {
+			return fmt.Errorf("failed to decode response from etcd: %w", err)
+		}
+	}
+	return nil
+}
+
+// The following types are the JSON representations of the etcd v3 API used by the provider.
+
+type rangeRequest struct {
+	Key      string `json:"key"`
+	RangeEnd string `json:"range_end,omitempty"`
+	KeysOnly bool   `json:"keys_only,omitempty"`
+}
+
+type rangeResponse struct {
+	KVs []keyValue `json:"kvs,omitempty"`
+}
+
+type keyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+}
+
+type putRequest struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type txnOp struct {
+	RequestPut         *putRequest   `json:"request_put,omitempty"`
+	RequestDeleteRange *rangeRequest `json:"request_delete_range,omitempty"`
+}
+
+type txnRequest struct {
+	Success []txnOp `json:"success"`
+}
+
+func encode(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+// prefixEnd returns the end of the range of keys with the prefix.
+func prefixEnd(prefix string) string {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+	return "\x00"
+}
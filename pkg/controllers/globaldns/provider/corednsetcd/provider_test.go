@@ -0,0 +1,247 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package corednsetcd
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/globaldns/provider"
+)
+
+func decode(s string) string {
+	b, _ := base64.StdEncoding.DecodeString(s)
+	return string(b)
+}
+
+// fakeEtcd implements the subset of the etcd v3 JSON gateway used by the provider.
+type fakeEtcd struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func (f *fakeEtcd) inRange(key string, req rangeRequest) bool {
+	if req.RangeEnd == "" {
+		return key == decode(req.Key)
+	}
+	return key >= decode(req.Key) && key < decode(req.RangeEnd)
+}
+
+func (f *fakeEtcd) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/v3/kv/range":
+		req := rangeRequest{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		resp := rangeResponse{}
+		for key := range f.data {
+			if f.inRange(key, req) {
+				resp.KVs = append(resp.KVs, keyValue{Key: encode(key)})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	case "/v3/kv/deleterange":
+		req := rangeRequest{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		for key := range f.data {
+			if f.inRange(key, req) {
+				delete(f.data, key)
+			}
+		}
+		_, _ = w.Write([]byte("{}"))
+	case "/v3/kv/txn":
+		req := txnRequest{}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		touched := map[string]bool{}
+		for _, op := range req.Success {
+			var key string
+			if op.RequestPut != nil {
+				key = decode(op.RequestPut.Key)
+			} else {
+				key = decode(op.RequestDeleteRange.Key)
+			}
+			if touched[key] {
+				http.Error(w, "duplicate key given in txn request", http.StatusBadRequest)
+				return
+			}
+			touched[key] = true
+		}
+		for _, op := range req.Success {
+			if op.RequestPut != nil {
+				f.data[decode(op.RequestPut.Key)] = decode(op.RequestPut.Value)
+			} else {
+				delete(f.data, decode(op.RequestDeleteRange.Key))
+			}
+		}
+		_, _ = w.Write([]byte("{}"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeEtcd) keys() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	keys := []string{}
+	for key := range f.data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestProvider(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	etcd := &fakeEtcd{data: map[string]string{
+		"/skydns/com/example/www/other":             `{"host":"10.0.0.100"}`,
+		"/skydns/com/example/www/api/kubeadmiral-0": `{"host":"10.0.0.200"}`,
+	}}
+	server := httptest.NewServer(etcd)
+	defer server.Close()
+
+	p, err := NewProvider(Config{Endpoint: server.URL})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	ctx := context.Background()
+
+	record := &provider.Record{
+		Name: "www.example.com",
+		TTL:  30,
+		Targets: []provider.Target{
+			{Address: "10.0.0.1", Weight: 100},
+			{Address: "10.0.0.2", Weight: 10},
+		},
+	}
+	g.Expect(p.EnsureRecord(ctx, record)).To(gomega.Succeed())
+	g.Expect(etcd.keys()).To(gomega.ConsistOf(
+		"/skydns/com/example/www/other",
+		"/skydns/com/example/www/api/kubeadmiral-0",
+		"/skydns/com/example/www/kubeadmiral-0",
+		"/skydns/com/example/www/kubeadmiral-1",
+	))
+
+	msg := skyDNSMessage{}
+	g.Expect(json.Unmarshal([]byte(etcd.data["/skydns/com/example/www/kubeadmiral-1"]), &msg)).To(gomega.Succeed())
+	g.Expect(msg).To(gomega.Equal(skyDNSMessage{Host: "10.0.0.2", TTL: 30, Weight: 10}))
+
+	record.Targets = record.Targets[1:]
+	g.Expect(p.EnsureRecord(ctx, record)).To(gomega.Succeed())
+	g.Expect(etcd.keys()).To(gomega.ContainElement("/skydns/com/example/www/kubeadmiral-0"))
+	g.Expect(etcd.keys()).NotTo(gomega.ContainElement("/skydns/com/example/www/kubeadmiral-1"))
+
+	g.Expect(p.DeleteRecord(ctx, "www.example.com", "")).To(gomega.Succeed())
+	for _, key := range etcd.keys() {
+		g.Expect(strings.HasPrefix(key, "/skydns/com/example/www/kubeadmiral-")).To(gomega.BeFalse())
+	}
+	g.Expect(etcd.keys()).To(gomega.HaveLen(2))
+}
+
+// writeClientCertAndKey writes a self-signed client certificate and its key to dir and returns their paths along with
+// the parsed certificate.
+func writeClientCertAndKey(g *gomega.WithT, dir string) (string, string, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kubeadmiral"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	certFile := filepath.Join(dir, "client.crt")
+	keyFile := filepath.Join(dir, "client.key")
+	g.Expect(os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)).
+		To(gomega.Succeed())
+	g.Expect(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600)).
+		To(gomega.Succeed())
+	return certFile, keyFile, cert
+}
+
+func TestProviderMutualTLS(t *testing.T) {
+	g := gomega.NewWithT(t)
+	dir := t.TempDir()
+
+	certFile, keyFile, clientCert := writeClientCertAndKey(g, dir)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+
+	etcd := &fakeEtcd{data: map[string]string{}}
+	server := httptest.NewUnstartedServer(etcd)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs, MinVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := filepath.Join(dir, "ca.crt")
+	serverCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	g.Expect(os.WriteFile(caFile, serverCert, 0o600)).To(gomega.Succeed())
+
+	ctx := context.Background()
+	record := &provider.Record{Name: "www.example.com", Targets: []provider.Target{{Address: "10.0.0.1"}}}
+
+	p, err := NewProvider(Config{Endpoint: server.URL, CAFile: caFile, CertFile: certFile, KeyFile: keyFile})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(p.EnsureRecord(ctx, record)).To(gomega.Succeed())
+	g.Expect(etcd.keys()).To(gomega.ConsistOf("/skydns/com/example/www/kubeadmiral-0"))
+
+	p, err = NewProvider(Config{Endpoint: server.URL, CAFile: caFile})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(p.EnsureRecord(ctx, record)).NotTo(gomega.Succeed())
+
+	_, err = NewProvider(Config{Endpoint: server.URL, CertFile: certFile})
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestPrefixEnd(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	g.Expect(prefixEnd("/skydns/a-")).To(gomega.Equal("/skydns/a."))
+	g.Expect(prefixEnd("a\xff")).To(gomega.Equal("b"))
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package provider defines the interface of the DNS providers used by the global DNS controller.
+package provider
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// ErrRecordConflict is returned by providers that track the owners of records if the name is owned by another owner.
+var ErrRecordConflict = errors.New("DNS name is owned by another object")
+
+// Target is an address that a DNS name resolves to.
+type Target struct {
+	// Address is either an IP address or a hostname.
+	Address string
+	// Weight is the relative weight of the target. Providers that do not support weighted records only publish the
+	// targets with the highest weight.
+	Weight int32
+}
+
+// IsIP returns true if the address of the target is an IP address.
+func (t Target) IsIP() bool {
+	return net.ParseIP(t.Address) != nil
+}
+
+// Record is the set of targets of a DNS name.
+type Record struct {
+	// Name is the fully qualified DNS name, without the trailing dot.
+	Name string
+	// TTL is the time to live of the records in seconds.
+	TTL int64
+	// Targets are the addresses the name resolves to, sorted by address.
+	Targets []Target
+	// Owner identifies the object that publishes the record.
+	Owner string
+}
+
+// Provider maintains DNS records in a DNS backend.
+type Provider interface {
+	// EnsureRecord replaces all records of the name with the targets of the record. Providers that track the owners
+	// of records return ErrRecordConflict if the name is owned by another owner.
+	EnsureRecord(ctx context.Context, record *Record) error
+	// DeleteRecord deletes all records of the name. Providers that track the owners of records leave the records of
+	// other owners untouched.
+	DeleteRecord(ctx context.Context, name, owner string) error
+}
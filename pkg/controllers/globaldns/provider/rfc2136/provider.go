@@ -0,0 +1,308 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rfc2136 implements a DNS provider that maintains records through RFC 2136 dynamic updates, optionally
+// authenticated with TSIG.
+//
+// The owner of each name is recorded in a TXT record of the name prefixed with ownerLabel, and updates are made
+// conditional on this record with the prerequisites of RFC 2136, so that records of other owners are never modified.
+// The TXT record is kept under a separate name since a name with a CNAME record may not have other records.
+package rfc2136
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/globaldns/provider"
+)
+
+// TSIG algorithms supported by the provider.
+const (
+	HmacSHA1   = "hmac-sha1"
+	HmacSHA256 = "hmac-sha256"
+	HmacSHA512 = "hmac-sha512"
+)
+
+const (
+	// ownerLabel is prepended to a name to get the name of the TXT record containing its owner.
+	ownerLabel = "_kubeadmiral-owner"
+	// ownerHeritage marks the TXT records managed by the provider.
+	ownerHeritage = "heritage=kubeadmiral"
+
+	tsigFudge = 300
+	// tsigMaxMACLength is the length of the longest MAC of the supported algorithms, which is not included in the
+	// length of an update before it is sent.
+	tsigMaxMACLength = 64
+)
+
+var tsigAlgorithms = map[string]string{
+	HmacSHA1:   dns.HmacSHA1,
+	HmacSHA256: dns.HmacSHA256,
+	HmacSHA512: dns.HmacSHA512,
+}
+
+// errPrerequisiteFailed is returned by sendUpdate if the prerequisites of the update are not satisfied.
+var errPrerequisiteFailed = errors.New("prerequisite failed")
+
+// Config is the configuration of the RFC 2136 provider.
+type Config struct {
+	// Server is the address of the DNS server in the form host:port.
+	Server string
+	// Zone is the zone in which records are updated. All record names must be within the zone.
+	Zone string
+	// TSIGKeyName is the name of the TSIG key. Updates are not signed if it is empty.
+	TSIGKeyName string
+	// TSIGAlgorithm is the algorithm of the TSIG key.
+	TSIGAlgorithm string
+	// TSIGSecret is the secret of the TSIG key.
+	TSIGSecret []byte
+	// Timeout is the timeout of each update.
+	Timeout time.Duration
+}
+
+type tsigKey struct {
+	name      string
+	algorithm string
+	secret    string
+}
+
+type rfc2136Provider struct {
+	server  string
+	zone    string
+	key     *tsigKey
+	timeout time.Duration
+}
+
+var _ provider.Provider = &rfc2136Provider{}
+
+// NewProvider returns a provider that sends dynamic updates to the DNS server.
+func NewProvider(config Config) (provider.Provider, error) {
+	if _, _, err := net.SplitHostPort(config.Server); err != nil {
+		return nil, fmt.Errorf("invalid DNS server address %q: %w", config.Server, err)
+	}
+	if config.Zone == "" {
+		return nil, fmt.Errorf("zone must be specified")
+	}
+
+	p := &rfc2136Provider{
+		server:  config.Server,
+		zone:    dns.CanonicalName(config.Zone),
+		timeout: config.Timeout,
+	}
+	if p.timeout <= 0 {
+		p.timeout = 10 * time.Second
+	}
+	if config.TSIGKeyName != "" {
+		algorithm := config.TSIGAlgorithm
+		if algorithm == "" {
+			algorithm = HmacSHA256
+		}
+		if _, ok := tsigAlgorithms[algorithm]; !ok {
+			return nil, fmt.Errorf("unsupported TSIG algorithm %q", algorithm)
+		}
+		if len(config.TSIGSecret) == 0 {
+			return nil, fmt.Errorf("TSIG secret must be specified")
+		}
+		p.key = &tsigKey{
+			name:      dns.CanonicalName(config.TSIGKeyName),
+			algorithm: tsigAlgorithms[algorithm],
+			secret:    base64.StdEncoding.EncodeToString(config.TSIGSecret),
+		}
+	}
+
+	return p, nil
+}
+
+func (p *rfc2136Provider) EnsureRecord(ctx context.Context, record *provider.Record) error {
+	name, err := p.fqdn(record.Name)
+	if err != nil {
+		return err
+	}
+	owner := ownerRecord(name, record.Owner, uint32(record.TTL))
+
+	var addresses []dns.RR
+	for _, target := range selectTargets(record.Targets) {
+		addresses = append(addresses, addressRecord(name, uint32(record.TTL), target.Address))
+	}
+
+	// replace the records if they are owned by the same owner
+	update := p.newUpdate()
+	update.Used([]dns.RR{ownerRecord(name, record.Owner, 0)})
+	update.RemoveRRset(append(addressRRsets(name), owner))
+	update.Insert(append(addresses, owner))
+	err = p.sendUpdate(ctx, update)
+	if !errors.Is(err, errPrerequisiteFailed) {
+		return err
+	}
+
+	// otherwise, claim the name if it has neither an owner nor address records
+	update = p.newUpdate()
+	update.RRsetNotUsed(append(addressRRsets(name), owner))
+	update.Insert(append(addresses, owner))
+	err = p.sendUpdate(ctx, update)
+	if errors.Is(err, errPrerequisiteFailed) {
+		return fmt.Errorf("%w: %s", provider.ErrRecordConflict, strings.TrimSuffix(name, "."))
+	}
+	return err
+}
+
+func (p *rfc2136Provider) DeleteRecord(ctx context.Context, name, owner string) error {
+	name, err := p.fqdn(name)
+	if err != nil {
+		return err
+	}
+
+	update := p.newUpdate()
+	update.Used([]dns.RR{ownerRecord(name, owner, 0)})
+	update.RemoveRRset(append(addressRRsets(name), ownerRecord(name, owner, 0)))
+	err = p.sendUpdate(ctx, update)
+	if errors.Is(err, errPrerequisiteFailed) {
+		// the records do not exist or are owned by others
+		return nil
+	}
+	return err
+}
+
+// fqdn returns the canonical fully qualified form of the name, or an error if it is not in the zone.
+func (p *rfc2136Provider) fqdn(name string) (string, error) {
+	name = dns.CanonicalName(name)
+	if !dns.IsSubDomain(p.zone, name) {
+		return "", fmt.Errorf("name %q is not in zone %q", strings.TrimSuffix(name, "."), strings.TrimSuffix(p.zone, "."))
+	}
+	return name, nil
+}
+
+func (p *rfc2136Provider) newUpdate() *dns.Msg {
+	update := &dns.Msg{}
+	update.SetUpdate(p.zone)
+	return update
+}
+
+// ownerRecord returns the TXT record that records the owner of the name.
+func ownerRecord(name, owner string, ttl uint32) dns.RR {
+	return &dns.TXT{
+		Hdr: dns.RR_Header{Name: ownerLabel + "." + name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: ttl},
+		Txt: []string{fmt.Sprintf("%s,owner=%s", ownerHeritage, owner)},
+	}
+}
+
+// addressRRsets returns placeholders of the RRsets of the name managed by the provider.
+func addressRRsets(name string) []dns.RR {
+	rrsets := []dns.RR{}
+	for _, rrType := range []uint16{dns.TypeA, dns.TypeAAAA, dns.TypeCNAME} {
+		rrsets = append(rrsets, &dns.ANY{Hdr: dns.RR_Header{Name: name, Rrtype: rrType, Class: dns.ClassINET}})
+	}
+	return rrsets
+}
+
+// addressRecord returns the A, AAAA or CNAME record of the target.
+func addressRecord(name string, ttl uint32, target string) dns.RR {
+	hdr := dns.RR_Header{Name: name, Class: dns.ClassINET, Ttl: ttl}
+	ip := net.ParseIP(target)
+	switch {
+	case ip == nil:
+		hdr.Rrtype = dns.TypeCNAME
+		return &dns.CNAME{Hdr: hdr, Target: dns.CanonicalName(target)}
+	case ip.To4() != nil:
+		hdr.Rrtype = dns.TypeA
+		return &dns.A{Hdr: hdr, A: ip.To4()}
+	default:
+		hdr.Rrtype = dns.TypeAAAA
+		return &dns.AAAA{Hdr: hdr, AAAA: ip}
+	}
+}
+
+// selectTargets returns the targets to publish. Address records do not carry weights, so only the IP targets with
+// the highest weight are published, e.g. the targets in unstable clusters are omitted while healthier clusters are
+// available. Since a name may only have a single CNAME record, hostname targets are only used if there are no IP
+// targets, in which case the hostname with the highest weight is published.
+func selectTargets(targets []provider.Target) []provider.Target {
+	var ips []provider.Target
+	var hostname *provider.Target
+	for i, target := range targets {
+		switch {
+		case !target.IsIP():
+			if hostname == nil || target.Weight > hostname.Weight {
+				hostname = &targets[i]
+			}
+		case len(ips) == 0 || target.Weight > ips[0].Weight:
+			ips = []provider.Target{target}
+		case target.Weight == ips[0].Weight:
+			ips = append(ips, target)
+		}
+	}
+
+	if len(ips) > 0 || hostname == nil {
+		return ips
+	}
+	return []provider.Target{*hostname}
+}
+
+// sendUpdate sends the update to the server. The update is sent over UDP unless it is too large, and is retried over
+// TCP if the response is truncated. If the update is signed, the response must carry a valid signature.
+func (p *rfc2136Provider) sendUpdate(ctx context.Context, update *dns.Msg) error {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	client := &dns.Client{Timeout: p.timeout}
+	if p.key != nil {
+		client.TsigSecret = map[string]string{p.key.name: p.key.secret}
+	}
+	sign := func() {
+		// the TSIG record is removed from the update when it is sent, so it is added again before each exchange
+		if p.key != nil && update.IsTsig() == nil {
+			update.SetTsig(p.key.name, p.key.algorithm, tsigFudge, time.Now().Unix())
+		}
+	}
+	exchange := func(network string) (*dns.Msg, error) {
+		sign()
+		client.Net = network
+		resp, _, err := client.ExchangeContext(ctx, update, p.server)
+		return resp, err
+	}
+
+	sign()
+	network := "udp"
+	if update.Len()+tsigMaxMACLength > dns.MinMsgSize {
+		network = "tcp"
+	}
+	resp, err := exchange(network)
+	if err == nil && resp.Truncated && network == "udp" {
+		resp, err = exchange("tcp")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to send update to %s: %w", p.server, err)
+	}
+	if p.key != nil && resp.IsTsig() == nil {
+		return fmt.Errorf("response from %s is not signed", p.server)
+	}
+
+	switch resp.Rcode {
+	case dns.RcodeSuccess:
+		return nil
+	case dns.RcodeNXRrset, dns.RcodeYXRrset:
+		return fmt.Errorf("%w: %s", errPrerequisiteFailed, dns.RcodeToString[resp.Rcode])
+	default:
+		return fmt.Errorf("update rejected by %s: %s", p.server, dns.RcodeToString[resp.Rcode])
+	}
+}
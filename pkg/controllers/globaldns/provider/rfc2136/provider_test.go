@@ -0,0 +1,359 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rfc2136
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/onsi/gomega"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/globaldns/provider"
+)
+
+var testSecret = []byte("secret")
+
+// fakeServer is a DNS server that implements the subset of RFC 2136 used by the provider.
+type fakeServer struct {
+	mu      sync.Mutex
+	records map[string][]dns.RR
+
+	// truncate makes the server reply to updates over UDP with truncated responses.
+	truncate bool
+	// unsigned makes the server reply to signed updates without signing the responses.
+	unsigned bool
+	// rcode is the rcode of the responses if set.
+	rcode int
+
+	updates []string
+}
+
+func rrsetKey(name string, rrType uint16) string {
+	return dns.CanonicalName(name) + "/" + dns.TypeToString[rrType]
+}
+
+func newFakeServer(records ...dns.RR) *fakeServer {
+	s := &fakeServer{records: map[string][]dns.RR{}}
+	for _, rr := range records {
+		key := rrsetKey(rr.Header().Name, rr.Header().Rrtype)
+		s.records[key] = append(s.records[key], rr)
+	}
+	return s
+}
+
+// start starts the server listening on both UDP and TCP and returns its address.
+func (s *fakeServer) start(t *testing.T) string {
+	t.Helper()
+	g := gomega.NewWithT(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	packetConn, err := net.ListenPacket("udp", listener.Addr().String())
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	tsigSecret := map[string]string{"kubeadmiral.": base64.StdEncoding.EncodeToString(testSecret)}
+	acceptAll := func(dns.Header) dns.MsgAcceptAction { return dns.MsgAccept }
+	servers := []*dns.Server{
+		{Listener: listener, Handler: s, TsigSecret: tsigSecret, MsgAcceptFunc: acceptAll},
+		{PacketConn: packetConn, Handler: s, TsigSecret: tsigSecret, MsgAcceptFunc: acceptAll},
+	}
+	for _, server := range servers {
+		server := server
+		go func() { _ = server.ActivateAndServe() }()
+		t.Cleanup(func() { _ = server.Shutdown() })
+	}
+
+	return listener.Addr().String()
+}
+
+func (s *fakeServer) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp := &dns.Msg{}
+	resp.SetReply(req)
+	if tsig := req.IsTsig(); tsig != nil {
+		if w.TsigStatus() != nil {
+			resp.Rcode = dns.RcodeNotAuth
+		} else if !s.unsigned {
+			resp.SetTsig(tsig.Hdr.Name, tsig.Algorithm, tsig.Fudge, time.Now().Unix())
+		}
+	}
+
+	switch {
+	case resp.Rcode != dns.RcodeSuccess:
+	case s.truncate && w.LocalAddr().Network() == "udp":
+		resp.Truncated = true
+	case s.rcode != dns.RcodeSuccess:
+		resp.Rcode = s.rcode
+	default:
+		resp.Rcode = s.update(req)
+		s.updates = append(s.updates, w.LocalAddr().Network())
+	}
+	_ = w.WriteMsg(resp)
+}
+
+// update applies the update if its prerequisites are satisfied and returns the rcode of the response.
+func (s *fakeServer) update(req *dns.Msg) int {
+	expected := map[string][]dns.RR{}
+	for _, rr := range req.Answer {
+		key := rrsetKey(rr.Header().Name, rr.Header().Rrtype)
+		switch rr.Header().Class {
+		case dns.ClassNONE:
+			if len(s.records[key]) > 0 {
+				return dns.RcodeYXRrset
+			}
+		case dns.ClassINET:
+			expected[key] = append(expected[key], rr)
+		}
+	}
+	for key, rrs := range expected {
+		if !equalRRsets(s.records[key], rrs) {
+			return dns.RcodeNXRrset
+		}
+	}
+
+	for _, rr := range req.Ns {
+		key := rrsetKey(rr.Header().Name, rr.Header().Rrtype)
+		switch rr.Header().Class {
+		case dns.ClassANY:
+			delete(s.records, key)
+		case dns.ClassINET:
+			s.records[key] = append(s.records[key], rr)
+		}
+	}
+	return dns.RcodeSuccess
+}
+
+func equalRRsets(a, b []dns.RR) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		found := false
+		for j := range b {
+			found = found || dns.IsDuplicate(a[i], b[j])
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// get returns the rdata of the records of the name and type.
+func (s *fakeServer) get(name string, rrType uint16) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values := []string{}
+	for _, rr := range s.records[rrsetKey(name, rrType)] {
+		values = append(values, strings.TrimPrefix(rr.String(), rr.Header().String()))
+	}
+	sort.Strings(values)
+	return values
+}
+
+// networks returns the networks over which the updates were received.
+func (s *fakeServer) networks() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.updates...)
+}
+
+func TestEnsureRecord(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	server := newFakeServer()
+	addr := server.start(t)
+	p, err := NewProvider(Config{Server: addr, Zone: "example.com.", TSIGKeyName: "kubeadmiral", TSIGSecret: testSecret})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	ctx := context.Background()
+
+	record := &provider.Record{
+		Name: "www.example.com",
+		TTL:  30,
+		Targets: []provider.Target{
+			{Address: "10.0.0.1", Weight: 100},
+			{Address: "10.0.0.2", Weight: 10},
+			{Address: "fd00::1", Weight: 100},
+			{Address: "lb.example.org", Weight: 100},
+		},
+		Owner: "Service/default/foo",
+	}
+	g.Expect(p.EnsureRecord(ctx, record)).To(gomega.Succeed())
+	g.Expect(server.get("www.example.com", dns.TypeA)).To(gomega.Equal([]string{"10.0.0.1"}))
+	g.Expect(server.get("www.example.com", dns.TypeAAAA)).To(gomega.Equal([]string{"fd00::1"}))
+	g.Expect(server.get("www.example.com", dns.TypeCNAME)).To(gomega.BeEmpty())
+	g.Expect(server.get("_kubeadmiral-owner.www.example.com", dns.TypeTXT)).
+		To(gomega.Equal([]string{`"heritage=kubeadmiral,owner=Service/default/foo"`}))
+
+	// the records are replaced by the same owner
+	record.Targets = []provider.Target{{Address: "a.example.org", Weight: 10}, {Address: "b.example.org", Weight: 100}}
+	g.Expect(p.EnsureRecord(ctx, record)).To(gomega.Succeed())
+	g.Expect(server.get("www.example.com", dns.TypeA)).To(gomega.BeEmpty())
+	g.Expect(server.get("www.example.com", dns.TypeAAAA)).To(gomega.BeEmpty())
+	g.Expect(server.get("www.example.com", dns.TypeCNAME)).To(gomega.Equal([]string{"b.example.org."}))
+
+	// other owners may neither update nor delete the records
+	other := &provider.Record{Name: "www.example.com", Targets: record.Targets[:1], Owner: "Ingress/default/bar"}
+	g.Expect(p.EnsureRecord(ctx, other)).To(gomega.MatchError(provider.ErrRecordConflict))
+	g.Expect(p.DeleteRecord(ctx, "www.example.com", other.Owner)).To(gomega.Succeed())
+	g.Expect(server.get("www.example.com", dns.TypeCNAME)).To(gomega.Equal([]string{"b.example.org."}))
+
+	g.Expect(p.DeleteRecord(ctx, "www.example.com", record.Owner)).To(gomega.Succeed())
+	g.Expect(server.get("www.example.com", dns.TypeCNAME)).To(gomega.BeEmpty())
+	g.Expect(server.get("_kubeadmiral-owner.www.example.com", dns.TypeTXT)).To(gomega.BeEmpty())
+
+	// the name is free to be claimed once it is released
+	g.Expect(p.EnsureRecord(ctx, other)).To(gomega.Succeed())
+
+	g.Expect(p.DeleteRecord(ctx, "www.example.org", record.Owner)).NotTo(gomega.Succeed())
+}
+
+func TestEnsureRecordRefusesUnownedRecords(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	existing, err := dns.NewRR("www.example.com. 60 IN A 10.0.0.100")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	server := newFakeServer(existing)
+	addr := server.start(t)
+	p, err := NewProvider(Config{Server: addr, Zone: "example.com"})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	err = p.EnsureRecord(context.Background(), &provider.Record{
+		Name:    "www.example.com",
+		Targets: []provider.Target{{Address: "10.0.0.1"}},
+		Owner:   "Service/default/foo",
+	})
+	g.Expect(err).To(gomega.MatchError(provider.ErrRecordConflict))
+	g.Expect(server.get("www.example.com", dns.TypeA)).To(gomega.Equal([]string{"10.0.0.100"}))
+}
+
+func TestTransport(t *testing.T) {
+	testCases := map[string]struct {
+		truncate         bool
+		expectedNetworks []string
+	}{
+		"small updates are sent over UDP": {
+			expectedNetworks: []string{"udp"},
+		},
+		"updates are retried over TCP if the response is truncated": {
+			truncate:         true,
+			expectedNetworks: []string{"tcp"},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			server := newFakeServer()
+			server.truncate = tc.truncate
+			addr := server.start(t)
+			p, err := NewProvider(Config{Server: addr, Zone: "example.com", TSIGKeyName: "kubeadmiral", TSIGSecret: testSecret})
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(p.DeleteRecord(context.Background(), "www.example.com", "Service/default/foo")).To(gomega.Succeed())
+			g.Expect(server.networks()).To(gomega.Equal(tc.expectedNetworks))
+		})
+	}
+}
+
+func TestUnsignedResponse(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	server := newFakeServer()
+	server.unsigned = true
+	addr := server.start(t)
+	p, err := NewProvider(Config{Server: addr, Zone: "example.com", TSIGKeyName: "kubeadmiral", TSIGSecret: testSecret})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	err = p.DeleteRecord(context.Background(), "www.example.com", "Service/default/foo")
+	g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("not signed")))
+}
+
+func TestBadSignature(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	addr := newFakeServer().start(t)
+	p, err := NewProvider(Config{Server: addr, Zone: "example.com", TSIGKeyName: "kubeadmiral", TSIGSecret: []byte("wrong")})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	g.Expect(p.DeleteRecord(context.Background(), "www.example.com", "Service/default/foo")).NotTo(gomega.Succeed())
+}
+
+func TestUpdateRejected(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	server := newFakeServer()
+	server.rcode = dns.RcodeRefused
+	addr := server.start(t)
+	p, err := NewProvider(Config{Server: addr, Zone: "example.com", Timeout: time.Second})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	err = p.DeleteRecord(context.Background(), "www.example.com", "Service/default/foo")
+	g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("REFUSED")))
+}
+
+func TestSelectTargets(t *testing.T) {
+	testCases := map[string]struct {
+		targets  []provider.Target
+		expected []string
+	}{
+		"IP targets with the highest weight": {
+			targets: []provider.Target{
+				{Address: "10.0.0.1", Weight: 10},
+				{Address: "10.0.0.2", Weight: 100},
+				{Address: "10.0.0.3", Weight: 100},
+				{Address: "lb.example.org", Weight: 100},
+			},
+			expected: []string{"10.0.0.2", "10.0.0.3"},
+		},
+		"hostname with the highest weight": {
+			targets: []provider.Target{
+				{Address: "a.example.org", Weight: 10},
+				{Address: "b.example.org", Weight: 100},
+			},
+			expected: []string{"b.example.org"},
+		},
+		"no targets": {
+			expected: []string{},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			addresses := []string{}
+			for _, target := range selectTargets(tc.targets) {
+				addresses = append(addresses, target.Address)
+			}
+			g.Expect(addresses).To(gomega.Equal(tc.expected))
+		})
+	}
+}
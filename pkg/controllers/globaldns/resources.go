@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package globaldns
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	networkingv1listers "k8s.io/client-go/listers/networking/v1"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+// object is a Service or Ingress.
+type object interface {
+	metav1.Object
+	pkgruntime.Object
+}
+
+// resourceType abstracts the access to the Services and Ingresses whose load balancer addresses are published.
+type resourceType struct {
+	kind string
+	// get returns the object from the host cluster's cache
+	get func(qualifiedName common.QualifiedName) (object, error)
+	// list returns all objects from the host cluster's cache
+	list func() ([]object, error)
+	// update updates the object in the host cluster
+	update func(ctx context.Context, obj object) error
+	// loadBalancerAddresses returns the load balancer addresses of the object in a member cluster
+	loadBalancerAddresses func(cluster *memberCluster, qualifiedName common.QualifiedName) ([]string, error)
+}
+
+func newServiceResourceType(kubeClient kubernetes.Interface, lister corev1listers.ServiceLister) *resourceType {
+	return &resourceType{
+		kind: "Service",
+		get: func(qualifiedName common.QualifiedName) (object, error) {
+			return lister.Services(qualifiedName.Namespace).Get(qualifiedName.Name)
+		},
+		list: func() ([]object, error) {
+			services, err := lister.List(labels.Everything())
+			if err != nil {
+				return nil, err
+			}
+			objs := make([]object, 0, len(services))
+			for _, service := range services {
+				objs = append(objs, service)
+			}
+			return objs, nil
+		},
+		update: func(ctx context.Context, obj object) error {
+			_, err := kubeClient.CoreV1().Services(obj.GetNamespace()).Update(
+				ctx, obj.(*corev1.Service), metav1.UpdateOptions{},
+			)
+			return err
+		},
+		loadBalancerAddresses: func(cluster *memberCluster, qualifiedName common.QualifiedName) ([]string, error) {
+			service, err := cluster.serviceLister.Services(qualifiedName.Namespace).Get(qualifiedName.Name)
+			if err != nil {
+				return nil, err
+			}
+			addresses := make([]string, 0, len(service.Status.LoadBalancer.Ingress))
+			for _, ingress := range service.Status.LoadBalancer.Ingress {
+				addresses = appendAddress(addresses, ingress.IP, ingress.Hostname)
+			}
+			return addresses, nil
+		},
+	}
+}
+
+func newIngressResourceType(kubeClient kubernetes.Interface, lister networkingv1listers.IngressLister) *resourceType {
+	return &resourceType{
+		kind: "Ingress",
+		get: func(qualifiedName common.QualifiedName) (object, error) {
+			return lister.Ingresses(qualifiedName.Namespace).Get(qualifiedName.Name)
+		},
+		list: func() ([]object, error) {
+			ingresses, err := lister.List(labels.Everything())
+			if err != nil {
+				return nil, err
+			}
+			objs := make([]object, 0, len(ingresses))
+			for _, ingress := range ingresses {
+				objs = append(objs, ingress)
+			}
+			return objs, nil
+		},
+		update: func(ctx context.Context, obj object) error {
+			_, err := kubeClient.NetworkingV1().Ingresses(obj.GetNamespace()).Update(
+				ctx, obj.(*networkingv1.Ingress), metav1.UpdateOptions{},
+			)
+			return err
+		},
+		loadBalancerAddresses: func(cluster *memberCluster, qualifiedName common.QualifiedName) ([]string, error) {
+			ingress, err := cluster.ingressLister.Ingresses(qualifiedName.Namespace).Get(qualifiedName.Name)
+			if err != nil {
+				return nil, err
+			}
+			addresses := make([]string, 0, len(ingress.Status.LoadBalancer.Ingress))
+			for _, lbIngress := range ingress.Status.LoadBalancer.Ingress {
+				addresses = appendAddress(addresses, lbIngress.IP, lbIngress.Hostname)
+			}
+			return addresses, nil
+		},
+	}
+}
+
+// appendAddress appends the IP of a load balancer ingress if set, or its hostname otherwise.
+func appendAddress(addresses []string, ip, hostname string) []string {
+	if ip != "" {
+		return append(addresses, ip)
+	}
+	if hostname != "" {
+		return append(addresses, hostname)
+	}
+	return addresses
+}
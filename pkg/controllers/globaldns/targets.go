@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package globaldns
+
+import (
+	"sort"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/globaldns/provider"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+)
+
+const (
+	// HealthyClusterWeight is the weight of the targets in ready and stable clusters.
+	HealthyClusterWeight = 100
+	// UnstableClusterWeight is the weight of the targets in ready clusters whose readiness changes frequently.
+	UnstableClusterWeight = 10
+)
+
+// clusterWeight returns the weight of the targets in the cluster according to its health. Clusters that are not
+// ready or being drained have a weight of 0.
+func clusterWeight(cluster *fedcorev1a1.FederatedCluster) int32 {
+	if cluster == nil || !util.IsClusterReady(&cluster.Status) || util.IsClusterDraining(cluster) {
+		return 0
+	}
+	if util.IsClusterUnstable(&cluster.Status) {
+		return UnstableClusterWeight
+	}
+	return HealthyClusterWeight
+}
+
+// buildTargets merges the load balancer addresses in each cluster into weighted targets. The targets in clusters with
+// a weight of 0 are omitted, unless no cluster has a positive weight, in which case all targets are published with
+// equal weight so that the name keeps resolving.
+func buildTargets(addressesByCluster map[string][]string, weights map[string]int32) []provider.Target {
+	healthyTargets := map[string]int32{}
+	allTargets := map[string]int32{}
+	for cluster, addresses := range addressesByCluster {
+		weight := weights[cluster]
+		for _, address := range addresses {
+			allTargets[address] = HealthyClusterWeight
+			if weight > 0 && weight > healthyTargets[address] {
+				healthyTargets[address] = weight
+			}
+		}
+	}
+
+	selected := healthyTargets
+	if len(selected) == 0 {
+		selected = allTargets
+	}
+
+	targets := make([]provider.Target, 0, len(selected))
+	for address, weight := range selected {
+		targets = append(targets, provider.Target{Address: address, Weight: weight})
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i].Address < targets[j].Address
+	})
+	return targets
+}
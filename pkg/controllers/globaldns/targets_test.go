@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package globaldns
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/globaldns/provider"
+)
+
+func newCluster(ready, unstable bool, maintenanceMode fedcorev1a1.ClusterMaintenanceMode) *fedcorev1a1.FederatedCluster {
+	cluster := &fedcorev1a1.FederatedCluster{}
+	readyStatus := corev1.ConditionFalse
+	if ready {
+		readyStatus = corev1.ConditionTrue
+	}
+	unstableStatus := corev1.ConditionFalse
+	if unstable {
+		unstableStatus = corev1.ConditionTrue
+	}
+	cluster.Status.Conditions = []fedcorev1a1.ClusterCondition{
+		{Type: fedcorev1a1.ClusterReady, Status: readyStatus},
+		{Type: fedcorev1a1.ClusterUnstable, Status: unstableStatus},
+	}
+	if maintenanceMode != "" {
+		cluster.Spec.Maintenance = &fedcorev1a1.ClusterMaintenance{Mode: maintenanceMode}
+	}
+	return cluster
+}
+
+func TestClusterWeight(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	g.Expect(clusterWeight(nil)).To(gomega.BeZero())
+	g.Expect(clusterWeight(newCluster(true, false, ""))).To(gomega.Equal(int32(HealthyClusterWeight)))
+	g.Expect(clusterWeight(newCluster(true, true, ""))).To(gomega.Equal(int32(UnstableClusterWeight)))
+	g.Expect(clusterWeight(newCluster(false, false, ""))).To(gomega.BeZero())
+	g.Expect(clusterWeight(newCluster(true, false, fedcorev1a1.ClusterMaintenanceModeCordon))).
+		To(gomega.Equal(int32(HealthyClusterWeight)))
+	g.Expect(clusterWeight(newCluster(true, false, fedcorev1a1.ClusterMaintenanceModeDrain))).To(gomega.BeZero())
+}
+
+func TestBuildTargets(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	addresses := map[string][]string{
+		"cluster-1": {"10.0.0.1"},
+		"cluster-2": {"10.0.0.2", "lb.example.com"},
+		"cluster-3": {"10.0.0.3"},
+	}
+
+	targets := buildTargets(addresses, map[string]int32{
+		"cluster-1": HealthyClusterWeight,
+		"cluster-2": UnstableClusterWeight,
+		"cluster-3": 0,
+	})
+	g.Expect(targets).To(gomega.Equal([]provider.Target{
+		{Address: "10.0.0.1", Weight: HealthyClusterWeight},
+		{Address: "10.0.0.2", Weight: UnstableClusterWeight},
+		{Address: "lb.example.com", Weight: UnstableClusterWeight},
+	}))
+
+	// all targets are published if no cluster is healthy
+	targets = buildTargets(addresses, map[string]int32{})
+	g.Expect(targets).To(gomega.HaveLen(4))
+	for _, target := range targets {
+		g.Expect(target.Weight).To(gomega.Equal(int32(HealthyClusterWeight)))
+	}
+
+	g.Expect(buildTargets(nil, nil)).To(gomega.BeEmpty())
+}
@@ -0,0 +1,451 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mcs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	kubeinformer "k8s.io/client-go/informers"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	discoveryv1listers "k8s.io/client-go/listers/discovery/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/delayingdeliver"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/federatedclient"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/worker"
+	"github.com/kubewharf/kubeadmiral/pkg/stats"
+)
+
+const (
+	ControllerName = "mcs-controller"
+
+	// The delay before retrying a Service while the informers of some member clusters are not yet synced.
+	informerSyncRetryDelay = 5 * time.Second
+)
+
+// Controller implements multi-cluster Services. The endpoints of each Service in the host cluster that is annotated
+// with common.ServiceExportAnnotation are collected from the EndpointSlices of the Service in all member clusters.
+// The endpoints are imported into every member cluster as the EndpointSlices of a derived Service without selector,
+// so that the pods backing the Service in any member cluster can be reached through the derived Service.
+//
+// Pull-mode clusters are not supported as they are not reachable from the control plane.
+type Controller struct {
+	name string
+
+	serviceLister corev1listers.ServiceLister
+	serviceSynced cache.InformerSynced
+
+	federatedClient federatedclient.FederatedClientFactory
+
+	// memberClustersLock guards memberClusters
+	memberClustersLock sync.RWMutex
+	// memberClusters contains the clients and informers of the member clusters reachable through the federated
+	// client factory
+	memberClusters map[string]*memberCluster
+
+	worker worker.ReconcileWorker
+
+	metrics stats.Metrics
+	logger  klog.Logger
+}
+
+type memberCluster struct {
+	informerFactory     kubeinformer.SharedInformerFactory
+	client              kubernetes.Interface
+	serviceLister       corev1listers.ServiceLister
+	serviceSynced       cache.InformerSynced
+	endpointSliceLister discoveryv1listers.EndpointSliceLister
+	endpointSliceSynced cache.InformerSynced
+}
+
+func (m *memberCluster) hasSynced() bool {
+	return m.serviceSynced() && m.endpointSliceSynced()
+}
+
+// IsControllerReady implements controllermanager.Controller
+func (c *Controller) IsControllerReady() bool {
+	return c.HasSynced()
+}
+
+func NewMCSController(
+	serviceInformer corev1informers.ServiceInformer,
+	federatedClient federatedclient.FederatedClientFactory,
+	metrics stats.Metrics,
+	workerConfig worker.ConfigSource,
+) (*Controller, error) {
+	c := &Controller{
+		name:            ControllerName,
+		serviceLister:   serviceInformer.Lister(),
+		serviceSynced:   serviceInformer.Informer().HasSynced,
+		federatedClient: federatedClient,
+		memberClusters:  map[string]*memberCluster{},
+		metrics:         metrics,
+		logger:          klog.LoggerWithValues(klog.Background(), "controller", ControllerName),
+	}
+
+	c.worker = worker.NewConfigurableReconcileWorker(
+		c.reconcile,
+		worker.WorkerTiming{},
+		workerConfig,
+		metrics,
+		delayingdeliver.NewMetricTags("mcs-worker", "Service"),
+	)
+
+	serviceInformer.Informer().AddEventHandler(util.NewTriggerOnAllChanges(c.worker.EnqueueObject))
+	federatedClient.AddClientUpdateHandler(c.handleClientUpdate)
+
+	return c, nil
+}
+
+func (c *Controller) Run(ctx context.Context) {
+	c.logger.Info("Starting controller")
+	defer c.logger.Info("Stopping controller")
+
+	if !cache.WaitForNamedCacheSync(c.name, ctx.Done(), c.HasSynced) {
+		return
+	}
+	c.worker.Run(ctx.Done())
+
+	<-ctx.Done()
+}
+
+func (c *Controller) HasSynced() bool {
+	return c.serviceSynced()
+}
+
+// handleClientUpdate registers the event handlers of the controller with the informers of a member cluster whenever
+// the clients of the cluster are rebuilt.
+func (c *Controller) handleClientUpdate(cluster string, factory federatedclient.FederatedClientFactory) {
+	c.memberClustersLock.Lock()
+	defer c.memberClustersLock.Unlock()
+
+	informerFactory, exists, err := factory.KubeSharedInformerFactoryForCluster(cluster)
+	if err != nil || !exists {
+		if _, ok := c.memberClusters[cluster]; ok {
+			delete(c.memberClusters, cluster)
+			c.enqueueExportedServices()
+		}
+		return
+	}
+	if existing := c.memberClusters[cluster]; existing != nil && existing.informerFactory == informerFactory {
+		return
+	}
+
+	client, _, err := factory.KubeClientsetForCluster(cluster)
+	if err != nil || client == nil {
+		return
+	}
+
+	serviceInformer := informerFactory.Core().V1().Services()
+	serviceInformer.Informer().AddEventHandler(util.NewTriggerOnAllChanges(c.enqueueExportedServiceForDerivedObject))
+	endpointSliceInformer := informerFactory.Discovery().V1().EndpointSlices()
+	endpointSliceInformer.Informer().AddEventHandler(util.NewTriggerOnAllChanges(c.enqueueServiceForEndpointSlice))
+
+	c.memberClusters[cluster] = &memberCluster{
+		informerFactory:     informerFactory,
+		client:              client,
+		serviceLister:       serviceInformer.Lister(),
+		serviceSynced:       serviceInformer.Informer().HasSynced,
+		endpointSliceLister: endpointSliceInformer.Lister(),
+		endpointSliceSynced: endpointSliceInformer.Informer().HasSynced,
+	}
+	c.enqueueExportedServices()
+}
+
+func (c *Controller) getMemberClusters() map[string]*memberCluster {
+	c.memberClustersLock.RLock()
+	defer c.memberClustersLock.RUnlock()
+
+	clusters := make(map[string]*memberCluster, len(c.memberClusters))
+	for name, cluster := range c.memberClusters {
+		clusters[name] = cluster
+	}
+	return clusters
+}
+
+func (c *Controller) enqueueExportedServices() {
+	services, err := c.serviceLister.List(labels.Everything())
+	if err != nil {
+		c.logger.Error(err, "Failed to list Services")
+		return
+	}
+	for _, service := range services {
+		if isExportedService(service) {
+			c.worker.EnqueueObject(service)
+		}
+	}
+}
+
+func (c *Controller) enqueueExportedServiceForDerivedObject(obj pkgruntime.Object) {
+	metaObj, ok := obj.(metav1.Object)
+	if !ok {
+		return
+	}
+	if name, ok := metaObj.GetLabels()[common.ImportedServiceLabel]; ok {
+		c.worker.Enqueue(common.QualifiedName{Namespace: metaObj.GetNamespace(), Name: name})
+	}
+}
+
+func (c *Controller) enqueueServiceForEndpointSlice(obj pkgruntime.Object) {
+	slice, ok := obj.(*discoveryv1.EndpointSlice)
+	if !ok {
+		return
+	}
+	if isDerivedEndpointSlice(slice) {
+		c.enqueueExportedServiceForDerivedObject(slice)
+		return
+	}
+
+	name, ok := slice.Labels[discoveryv1.LabelServiceName]
+	if !ok {
+		return
+	}
+	// only exported Services are interested in the endpoints of the member clusters
+	if service, err := c.serviceLister.Services(slice.Namespace).Get(name); err == nil && isExportedService(service) {
+		c.worker.Enqueue(common.QualifiedName{Namespace: slice.Namespace, Name: name})
+	}
+}
+
+func (c *Controller) reconcile(qualifiedName common.QualifiedName) (status worker.Result) {
+	key := qualifiedName.String()
+	keyedLogger := c.logger.WithValues("control-loop", "reconcile", "object", key)
+	ctx := klog.NewContext(context.TODO(), keyedLogger)
+
+	startTime := time.Now()
+	c.metrics.Rate("mcs.throughput", 1)
+	keyedLogger.V(3).Info("Start reconcile")
+	defer func() {
+		c.metrics.Duration(fmt.Sprintf("%s.latency", c.name), startTime)
+		keyedLogger.V(3).Info("Finished reconcile", "duration", time.Since(startTime), "status", status.String())
+	}()
+
+	service, err := c.serviceLister.Services(qualifiedName.Namespace).Get(qualifiedName.Name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		keyedLogger.Error(err, "Failed to get Service from store")
+		return worker.StatusError
+	}
+	exported := err == nil && isExportedService(service)
+
+	clusters := c.getMemberClusters()
+	unsyncedClusters := false
+	for _, cluster := range clusters {
+		if !cluster.hasSynced() {
+			unsyncedClusters = true
+		}
+	}
+
+	var errs []error
+	if exported {
+		sourceSlices := c.collectEndpointSlices(service, clusters)
+		for clusterName, cluster := range clusters {
+			if !cluster.hasSynced() {
+				continue
+			}
+			if err := c.importService(ctx, cluster, service, sourceSlices, clusters); err != nil {
+				errs = append(errs, fmt.Errorf("failed to import Service into cluster %s: %w", clusterName, err))
+			}
+		}
+	} else {
+		for clusterName, cluster := range clusters {
+			if !cluster.hasSynced() {
+				continue
+			}
+			if err := c.removeImportedService(ctx, cluster, qualifiedName); err != nil {
+				errs = append(errs, fmt.Errorf("failed to remove imported Service from cluster %s: %w", clusterName, err))
+			}
+		}
+	}
+
+	if err := utilerrors.NewAggregate(errs); err != nil {
+		keyedLogger.Error(err, "Failed to reconcile multi-cluster Service")
+		return worker.StatusError
+	}
+	if unsyncedClusters {
+		delay := informerSyncRetryDelay
+		return worker.Result{Success: true, RequeueAfter: &delay}
+	}
+	return worker.StatusAllOK
+}
+
+// collectEndpointSlices returns the EndpointSlices of the exported Service in each member cluster whose informers have
+// synced. Clusters whose informers have not synced are omitted.
+func (c *Controller) collectEndpointSlices(
+	service *corev1.Service,
+	clusters map[string]*memberCluster,
+) map[string][]*discoveryv1.EndpointSlice {
+	selector := labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: service.Name})
+
+	result := map[string][]*discoveryv1.EndpointSlice{}
+	for clusterName, cluster := range clusters {
+		if !cluster.hasSynced() {
+			continue
+		}
+
+		slices, err := cluster.endpointSliceLister.EndpointSlices(service.Namespace).List(selector)
+		if err != nil {
+			// listing from the cache never fails
+			continue
+		}
+		sourceSlices := []*discoveryv1.EndpointSlice{}
+		for _, slice := range slices {
+			if !isDerivedEndpointSlice(slice) {
+				sourceSlices = append(sourceSlices, slice)
+			}
+		}
+		result[clusterName] = sourceSlices
+	}
+	return result
+}
+
+// importService ensures that the derived Service and its EndpointSlices in the member cluster are up to date.
+func (c *Controller) importService(
+	ctx context.Context,
+	cluster *memberCluster,
+	exported *corev1.Service,
+	sourceSlices map[string][]*discoveryv1.EndpointSlice,
+	clusters map[string]*memberCluster,
+) error {
+	logger := klog.FromContext(ctx)
+
+	desiredService := newDerivedService(exported)
+	derivedService, err := cluster.serviceLister.Services(exported.Namespace).Get(desiredService.Name)
+	switch {
+	case apierrors.IsNotFound(err):
+		logger.V(1).Info("Creating derived Service", "service", desiredService.Name)
+		derivedService, err = cluster.client.CoreV1().Services(exported.Namespace).Create(
+			ctx, desiredService, metav1.CreateOptions{},
+		)
+		if apierrors.IsNotFound(err) {
+			// the namespace does not exist in the member cluster
+			logger.V(3).Info("Namespace not found, skipping import of Service")
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create derived Service: %w", err)
+		}
+	case err != nil:
+		return err
+	case !isDerivedService(derivedService, exported.Name):
+		return fmt.Errorf("Service %s already exists and is not derived from the exported Service", desiredService.Name)
+	default:
+		derivedService = derivedService.DeepCopy()
+		if updateDerivedService(derivedService, desiredService) {
+			logger.V(1).Info("Updating derived Service", "service", derivedService.Name)
+			if derivedService, err = cluster.client.CoreV1().Services(exported.Namespace).Update(
+				ctx, derivedService, metav1.UpdateOptions{},
+			); err != nil {
+				return fmt.Errorf("failed to update derived Service: %w", err)
+			}
+		}
+	}
+
+	desiredSlices := map[string]*discoveryv1.EndpointSlice{}
+	for sourceCluster, slices := range sourceSlices {
+		for name, slice := range newDerivedEndpointSlices(derivedService, exported.Name, sourceCluster, slices) {
+			desiredSlices[name] = slice
+		}
+	}
+
+	existingSlices, err := cluster.endpointSliceLister.EndpointSlices(exported.Namespace).List(
+		labels.SelectorFromSet(labels.Set{
+			discoveryv1.LabelManagedBy:  EndpointSliceManagedBy,
+			common.ImportedServiceLabel: exported.Name,
+		}),
+	)
+	if err != nil {
+		return err
+	}
+
+	sliceClient := cluster.client.DiscoveryV1().EndpointSlices(exported.Namespace)
+	for _, existing := range existingSlices {
+		desired, ok := desiredSlices[existing.Name]
+		if !ok {
+			sourceCluster := existing.Annotations[common.SourceClusterAnnotation]
+			if _, collected := sourceSlices[sourceCluster]; !collected && clusters[sourceCluster] != nil {
+				// keep the endpoints of source clusters whose informers have not synced yet
+				continue
+			}
+			logger.V(1).Info("Deleting derived EndpointSlice", "endpointSlice", existing.Name)
+			if err := sliceClient.Delete(ctx, existing.Name, metav1.DeleteOptions{}); err != nil &&
+				!apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete derived EndpointSlice: %w", err)
+			}
+			continue
+		}
+
+		delete(desiredSlices, existing.Name)
+		updated := existing.DeepCopy()
+		if updateDerivedEndpointSlice(updated, desired) {
+			logger.V(1).Info("Updating derived EndpointSlice", "endpointSlice", existing.Name)
+			if _, err := sliceClient.Update(ctx, updated, metav1.UpdateOptions{}); err != nil {
+				return fmt.Errorf("failed to update derived EndpointSlice: %w", err)
+			}
+		}
+	}
+
+	for _, desired := range desiredSlices {
+		logger.V(1).Info("Creating derived EndpointSlice", "endpointSlice", desired.Name)
+		if _, err := sliceClient.Create(ctx, desired, metav1.CreateOptions{}); err != nil &&
+			!apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create derived EndpointSlice: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// removeImportedService deletes the derived Service of a Service that is no longer exported from the member cluster.
+// Its EndpointSlices are garbage collected through their owner references.
+func (c *Controller) removeImportedService(
+	ctx context.Context,
+	cluster *memberCluster,
+	qualifiedName common.QualifiedName,
+) error {
+	derivedName := DerivedServiceName(qualifiedName.Name)
+	derivedService, err := cluster.serviceLister.Services(qualifiedName.Namespace).Get(derivedName)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !isDerivedService(derivedService, qualifiedName.Name) {
+		return nil
+	}
+
+	klog.FromContext(ctx).V(1).Info("Deleting derived Service", "service", derivedName)
+	err = cluster.client.CoreV1().Services(qualifiedName.Namespace).Delete(ctx, derivedName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete derived Service: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mcs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kubeinformer "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/worker"
+	"github.com/kubewharf/kubeadmiral/pkg/stats"
+)
+
+func newTestMemberCluster(ctx context.Context, client kubernetes.Interface) *memberCluster {
+	informerFactory := kubeinformer.NewSharedInformerFactory(client, 0)
+	serviceInformer := informerFactory.Core().V1().Services()
+	endpointSliceInformer := informerFactory.Discovery().V1().EndpointSlices()
+	cluster := &memberCluster{
+		informerFactory:     informerFactory,
+		client:              client,
+		serviceLister:       serviceInformer.Lister(),
+		serviceSynced:       serviceInformer.Informer().HasSynced,
+		endpointSliceLister: endpointSliceInformer.Lister(),
+		endpointSliceSynced: endpointSliceInformer.Informer().HasSynced,
+	}
+	informerFactory.Start(ctx.Done())
+	informerFactory.WaitForCacheSync(ctx.Done())
+	return cluster
+}
+
+func TestControllerImportsEndpointSlices(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	exported := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "foo",
+			Annotations: map[string]string{common.ServiceExportAnnotation: common.AnnotationValueTrue},
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Name: "http", Port: 80}},
+		},
+	}
+	hostInformerFactory := kubeinformer.NewSharedInformerFactory(fake.NewSimpleClientset(exported), 0)
+	serviceInformer := hostInformerFactory.Core().V1().Services()
+	serviceInformer.Informer()
+	hostInformerFactory.Start(ctx.Done())
+	hostInformerFactory.WaitForCacheSync(ctx.Done())
+
+	client1 := fake.NewSimpleClientset(
+		newEndpointSlice("foo-a", discoveryv1.AddressTypeIPv4, 8080, "10.0.0.1"),
+		newEndpointSlice("foo-b", discoveryv1.AddressTypeIPv4, 9090, "10.0.0.2"),
+	)
+	client2 := fake.NewSimpleClientset(
+		newEndpointSlice("foo-a", discoveryv1.AddressTypeIPv4, 8080, "10.1.0.1"),
+	)
+	clusters := map[string]*memberCluster{
+		"cluster-1": newTestMemberCluster(ctx, client1),
+		"cluster-2": newTestMemberCluster(ctx, client2),
+	}
+
+	c := &Controller{
+		name:           ControllerName,
+		serviceLister:  serviceInformer.Lister(),
+		serviceSynced:  serviceInformer.Informer().HasSynced,
+		memberClusters: clusters,
+		metrics:        stats.NewMock("test", ControllerName, false),
+		logger:         klog.Background(),
+	}
+	qualifiedName := common.QualifiedName{Namespace: "default", Name: "foo"}
+
+	// derivedPorts returns the ports of the derived EndpointSlices in the cluster keyed by endpoint address
+	derivedPorts := func(cluster *memberCluster) map[string]int32 {
+		slices, err := cluster.endpointSliceLister.List(labels.SelectorFromSet(labels.Set{
+			discoveryv1.LabelManagedBy: EndpointSliceManagedBy,
+		}))
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		ports := map[string]int32{}
+		for _, slice := range slices {
+			g.Expect(slice.Labels).To(gomega.HaveKeyWithValue(discoveryv1.LabelServiceName, "derived-foo"))
+			g.Expect(slice.Ports).To(gomega.HaveLen(1))
+			for _, endpoint := range slice.Endpoints {
+				ports[endpoint.Addresses[0]] = *slice.Ports[0].Port
+			}
+		}
+		return ports
+	}
+
+	g.Expect(c.reconcile(qualifiedName)).To(gomega.Equal(worker.StatusAllOK))
+	for _, cluster := range clusters {
+		cluster := cluster
+		g.Eventually(func() map[string]int32 { return derivedPorts(cluster) }).Should(gomega.Equal(map[string]int32{
+			"10.0.0.1": 8080,
+			"10.0.0.2": 9090,
+			"10.1.0.1": 8080,
+		}))
+		g.Eventually(func() error {
+			_, err := cluster.serviceLister.Services("default").Get("derived-foo")
+			return err
+		}).Should(gomega.Succeed())
+	}
+
+	// the derived slices of a removed source slice are deleted
+	g.Expect(client2.DiscoveryV1().EndpointSlices("default").Delete(ctx, "foo-a", metav1.DeleteOptions{})).
+		To(gomega.Succeed())
+	g.Eventually(func() error {
+		_, err := clusters["cluster-2"].endpointSliceLister.EndpointSlices("default").Get("foo-a")
+		return err
+	}).ShouldNot(gomega.Succeed())
+
+	g.Expect(c.reconcile(qualifiedName)).To(gomega.Equal(worker.StatusAllOK))
+	for _, cluster := range clusters {
+		cluster := cluster
+		g.Eventually(func() map[string]int32 { return derivedPorts(cluster) }).Should(gomega.Equal(map[string]int32{
+			"10.0.0.1": 8080,
+			"10.0.0.2": 9090,
+		}))
+	}
+
+	// the derived Service is deleted once the Service is no longer exported
+	unexported := exported.DeepCopy()
+	unexported.Annotations = nil
+	g.Expect(serviceInformer.Informer().GetStore().Update(unexported)).To(gomega.Succeed())
+	g.Expect(c.reconcile(qualifiedName)).To(gomega.Equal(worker.StatusAllOK))
+	for _, client := range []kubernetes.Interface{client1, client2} {
+		services, err := client.CoreV1().Services("default").List(ctx, metav1.ListOptions{})
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		g.Expect(services.Items).To(gomega.BeEmpty())
+	}
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mcs
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+const (
+	derivedServicePrefix = "derived-"
+
+	// EndpointSliceManagedBy is the value of the managed-by label of the EndpointSlices created by the controller.
+	EndpointSliceManagedBy = "mcs-controller.kubeadmiral.io"
+)
+
+// DerivedServiceName returns the name of the Service that imports the exported Service with the given name into the
+// member clusters.
+func DerivedServiceName(name string) string {
+	derivedName := derivedServicePrefix + name
+	if len(derivedName) <= validation.DNS1035LabelMaxLength {
+		return derivedName
+	}
+
+	hash := hashString(name)
+	return derivedName[:validation.DNS1035LabelMaxLength-len(hash)-1] + "-" + hash
+}
+
+func hashString(s string) string {
+	hasher := fnv.New32a()
+	_, _ = hasher.Write([]byte(s))
+	return fmt.Sprintf("%08x", hasher.Sum32())
+}
+
+func isExportedService(service *corev1.Service) bool {
+	return service.GetDeletionTimestamp() == nil &&
+		service.Spec.Type != corev1.ServiceTypeExternalName &&
+		service.GetAnnotations()[common.ServiceExportAnnotation] == common.AnnotationValueTrue
+}
+
+func isDerivedService(service *corev1.Service, exportedName string) bool {
+	return service.GetLabels()[common.ImportedServiceLabel] == exportedName
+}
+
+func isDerivedEndpointSlice(slice *discoveryv1.EndpointSlice) bool {
+	return slice.GetLabels()[discoveryv1.LabelManagedBy] == EndpointSliceManagedBy
+}
+
+// newDerivedService returns the Service that imports the exported Service into a member cluster. The derived Service
+// has no selector, its endpoints are provided by the EndpointSlices imported from all member clusters.
+func newDerivedService(exported *corev1.Service) *corev1.Service {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: exported.Namespace,
+			Name:      DerivedServiceName(exported.Name),
+			Labels: map[string]string{
+				common.ImportedServiceLabel: exported.Name,
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:            corev1.ServiceTypeClusterIP,
+			SessionAffinity: exported.Spec.SessionAffinity,
+		},
+	}
+	if exported.Spec.ClusterIP == corev1.ClusterIPNone {
+		service.Spec.ClusterIP = corev1.ClusterIPNone
+	}
+	if service.Spec.SessionAffinity == "" {
+		service.Spec.SessionAffinity = corev1.ServiceAffinityNone
+	}
+
+	for _, port := range exported.Spec.Ports {
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = corev1.ProtocolTCP
+		}
+		service.Spec.Ports = append(service.Spec.Ports, corev1.ServicePort{
+			Name:        port.Name,
+			Protocol:    protocol,
+			AppProtocol: port.AppProtocol,
+			Port:        port.Port,
+			TargetPort:  intstr.FromInt(int(port.Port)),
+		})
+	}
+
+	return service
+}
+
+// updateDerivedService updates the fields of the existing derived Service that are managed by the controller. It
+// returns true if the Service was changed.
+func updateDerivedService(existing, desired *corev1.Service) bool {
+	changed := false
+	if existing.Labels[common.ImportedServiceLabel] != desired.Labels[common.ImportedServiceLabel] {
+		if existing.Labels == nil {
+			existing.Labels = map[string]string{}
+		}
+		existing.Labels[common.ImportedServiceLabel] = desired.Labels[common.ImportedServiceLabel]
+		changed = true
+	}
+	if !equality.Semantic.DeepEqual(existing.Spec.Ports, desired.Spec.Ports) {
+		existing.Spec.Ports = desired.Spec.Ports
+		changed = true
+	}
+	if existing.Spec.SessionAffinity != desired.Spec.SessionAffinity {
+		existing.Spec.SessionAffinity = desired.Spec.SessionAffinity
+		existing.Spec.SessionAffinityConfig = nil
+		changed = true
+	}
+	return changed
+}
+
+// newDerivedEndpointSlices returns the EndpointSlices of the derived Service that import the EndpointSlices of the
+// exported Service in the source cluster. Each source EndpointSlice is imported as one derived EndpointSlice with the
+// same address type and ports, since the ports of an EndpointSlice apply to all of its endpoints and endpoints with
+// different ports cannot be merged. The returned EndpointSlices are keyed by name.
+func newDerivedEndpointSlices(
+	derivedService *corev1.Service,
+	exportedName string,
+	sourceCluster string,
+	sourceSlices []*discoveryv1.EndpointSlice,
+) map[string]*discoveryv1.EndpointSlice {
+	result := map[string]*discoveryv1.EndpointSlice{}
+	for _, sourceSlice := range sourceSlices {
+		endpoints := make([]discoveryv1.Endpoint, 0, len(sourceSlice.Endpoints))
+		for _, endpoint := range sourceSlice.Endpoints {
+			// node names and target refs refer to objects in the source cluster and topology hints are only valid
+			// within the source cluster
+			endpoints = append(endpoints, discoveryv1.Endpoint{
+				Addresses:  endpoint.Addresses,
+				Conditions: endpoint.Conditions,
+				Hostname:   endpoint.Hostname,
+				Zone:       endpoint.Zone,
+			})
+		}
+
+		slice := &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: derivedService.Namespace,
+				Name: fmt.Sprintf(
+					"%s-%s",
+					derivedService.Name,
+					hashString(fmt.Sprintf("%s/%s", sourceCluster, sourceSlice.Name)),
+				),
+				Labels: map[string]string{
+					discoveryv1.LabelServiceName: derivedService.Name,
+					discoveryv1.LabelManagedBy:   EndpointSliceManagedBy,
+					common.ImportedServiceLabel:  exportedName,
+				},
+				Annotations: map[string]string{
+					common.SourceClusterAnnotation: sourceCluster,
+				},
+			},
+			AddressType: sourceSlice.AddressType,
+			Endpoints:   endpoints,
+			Ports:       sourceSlice.Ports,
+		}
+		if len(derivedService.UID) > 0 {
+			slice.OwnerReferences = []metav1.OwnerReference{
+				*metav1.NewControllerRef(derivedService, corev1.SchemeGroupVersion.WithKind("Service")),
+			}
+		}
+		result[slice.Name] = slice
+	}
+
+	return result
+}
+
+// updateDerivedEndpointSlice updates the existing derived EndpointSlice to the desired one. It returns true if the
+// EndpointSlice was changed.
+func updateDerivedEndpointSlice(existing, desired *discoveryv1.EndpointSlice) bool {
+	if equality.Semantic.DeepEqual(existing.Labels, desired.Labels) &&
+		equality.Semantic.DeepEqual(existing.Annotations, desired.Annotations) &&
+		equality.Semantic.DeepEqual(existing.OwnerReferences, desired.OwnerReferences) &&
+		equality.Semantic.DeepEqual(existing.Endpoints, desired.Endpoints) &&
+		equality.Semantic.DeepEqual(existing.Ports, desired.Ports) {
+		return false
+	}
+
+	existing.Labels = desired.Labels
+	existing.Annotations = desired.Annotations
+	existing.OwnerReferences = desired.OwnerReferences
+	existing.Endpoints = desired.Endpoints
+	existing.Ports = desired.Ports
+	return true
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mcs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/pointer"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+func TestDerivedServiceName(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	g.Expect(DerivedServiceName("foo")).To(gomega.Equal("derived-foo"))
+
+	longName := strings.Repeat("a", validation.DNS1035LabelMaxLength)
+	derivedName := DerivedServiceName(longName)
+	g.Expect(derivedName).To(gomega.HaveLen(validation.DNS1035LabelMaxLength))
+	g.Expect(derivedName).To(gomega.HavePrefix("derived-"))
+	g.Expect(DerivedServiceName(longName + "b")).NotTo(gomega.Equal(derivedName))
+}
+
+func TestNewDerivedService(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	exported := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "foo",
+			Annotations: map[string]string{common.ServiceExportAnnotation: common.AnnotationValueTrue},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:      corev1.ServiceTypeLoadBalancer,
+			Selector:  map[string]string{"app": "foo"},
+			ClusterIP: "10.0.0.1",
+			Ports: []corev1.ServicePort{
+				{Name: "http", Port: 80, NodePort: 30080},
+			},
+		},
+	}
+	g.Expect(isExportedService(exported)).To(gomega.BeTrue())
+
+	derived := newDerivedService(exported)
+	g.Expect(derived.Name).To(gomega.Equal("derived-foo"))
+	g.Expect(isDerivedService(derived, "foo")).To(gomega.BeTrue())
+	g.Expect(derived.Spec.Type).To(gomega.Equal(corev1.ServiceTypeClusterIP))
+	g.Expect(derived.Spec.Selector).To(gomega.BeEmpty())
+	g.Expect(derived.Spec.ClusterIP).To(gomega.BeEmpty())
+	g.Expect(derived.Spec.Ports).To(gomega.HaveLen(1))
+	g.Expect(derived.Spec.Ports[0].Protocol).To(gomega.Equal(corev1.ProtocolTCP))
+	g.Expect(derived.Spec.Ports[0].NodePort).To(gomega.BeZero())
+
+	existing := derived.DeepCopy()
+	existing.Spec.ClusterIP = "10.1.0.1"
+	g.Expect(updateDerivedService(existing, derived)).To(gomega.BeFalse())
+
+	exported.Spec.Ports = append(exported.Spec.Ports, corev1.ServicePort{Name: "https", Port: 443})
+	g.Expect(updateDerivedService(existing, newDerivedService(exported))).To(gomega.BeTrue())
+	g.Expect(existing.Spec.Ports).To(gomega.HaveLen(2))
+	g.Expect(existing.Spec.ClusterIP).To(gomega.Equal("10.1.0.1"))
+
+	exported.Spec.ClusterIP = corev1.ClusterIPNone
+	g.Expect(newDerivedService(exported).Spec.ClusterIP).To(gomega.Equal(corev1.ClusterIPNone))
+}
+
+func newEndpointSlice(
+	name string,
+	addressType discoveryv1.AddressType,
+	port int32,
+	addresses ...string,
+) *discoveryv1.EndpointSlice {
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "foo"},
+		},
+		AddressType: addressType,
+		Ports: []discoveryv1.EndpointPort{
+			{Name: pointer.String("http"), Port: pointer.Int32(port)},
+		},
+	}
+	for _, address := range addresses {
+		slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{
+			Addresses:  []string{address},
+			Conditions: discoveryv1.EndpointConditions{Ready: pointer.Bool(true)},
+			NodeName:   pointer.String("node"),
+			TargetRef:  &corev1.ObjectReference{Kind: "Pod", Name: address},
+		})
+	}
+	return slice
+}
+
+func TestNewDerivedEndpointSlices(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	derivedService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "derived-foo", UID: "uid"},
+	}
+
+	sourceSlices := []*discoveryv1.EndpointSlice{
+		newEndpointSlice("foo-a", discoveryv1.AddressTypeIPv4, 8080, "10.0.0.1", "10.0.0.2"),
+		// endpoints whose pods expose a different target port during a rollout
+		newEndpointSlice("foo-b", discoveryv1.AddressTypeIPv4, 9090, "10.0.0.3"),
+		newEndpointSlice("foo-c", discoveryv1.AddressTypeIPv6, 8080, "fd00::1"),
+	}
+	slices := newDerivedEndpointSlices(derivedService, "foo", "cluster-1", sourceSlices)
+	g.Expect(slices).To(gomega.HaveLen(3))
+
+	slicesByAddress := map[string]*discoveryv1.EndpointSlice{}
+	for _, slice := range slices {
+		g.Expect(slice.Labels).To(gomega.HaveKeyWithValue(discoveryv1.LabelServiceName, "derived-foo"))
+		g.Expect(isDerivedEndpointSlice(slice)).To(gomega.BeTrue())
+		g.Expect(slice.Annotations).To(gomega.HaveKeyWithValue(common.SourceClusterAnnotation, "cluster-1"))
+		g.Expect(slice.OwnerReferences).To(gomega.HaveLen(1))
+		g.Expect(slice.Ports).To(gomega.HaveLen(1))
+		for _, endpoint := range slice.Endpoints {
+			g.Expect(endpoint.NodeName).To(gomega.BeNil())
+			g.Expect(endpoint.TargetRef).To(gomega.BeNil())
+			slicesByAddress[endpoint.Addresses[0]] = slice
+		}
+	}
+
+	// each derived slice keeps the ports of its source slice
+	g.Expect(slicesByAddress["10.0.0.1"]).To(gomega.BeIdenticalTo(slicesByAddress["10.0.0.2"]))
+	g.Expect(*slicesByAddress["10.0.0.1"].Ports[0].Port).To(gomega.Equal(int32(8080)))
+	g.Expect(*slicesByAddress["10.0.0.3"].Ports[0].Port).To(gomega.Equal(int32(9090)))
+	g.Expect(slicesByAddress["fd00::1"].AddressType).To(gomega.Equal(discoveryv1.AddressTypeIPv6))
+
+	// the names of the derived slices are stable
+	g.Expect(newDerivedEndpointSlices(derivedService, "foo", "cluster-1", sourceSlices)).To(gomega.Equal(slices))
+
+	// slices from different source clusters do not collide
+	otherSlices := newDerivedEndpointSlices(derivedService, "foo", "cluster-2", sourceSlices)
+	for name := range otherSlices {
+		g.Expect(slices).NotTo(gomega.HaveKey(name))
+	}
+
+	for _, slice := range slices {
+		existing := slice.DeepCopy()
+		g.Expect(updateDerivedEndpointSlice(existing, slice)).To(gomega.BeFalse())
+		existing.Endpoints = existing.Endpoints[1:]
+		g.Expect(updateDerivedEndpointSlice(existing, slice)).To(gomega.BeTrue())
+		g.Expect(existing.Endpoints).To(gomega.Equal(slice.Endpoints))
+	}
+}
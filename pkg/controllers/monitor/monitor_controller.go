@@ -47,6 +47,9 @@ const (
 	ReportInterval time.Duration = 1 * time.Minute
 
 	finalizer = "core." + common.DefaultPrefix + "monitor-controller"
+
+	// TuningName is the name of the controller in the tuning configuration of the controller manager.
+	TuningName = "monitor"
 )
 
 // The Monitor controller monitor the sync latency of resources defined in the
@@ -49,7 +49,9 @@ var (
 	ErrMissingObservedGenerationField = fmt.Errorf("missing observed generation")
 )
 
-var OutOfSyncRecheckDelay = 5 * time.Second
+// DefaultOutOfSyncRecheckDelay is used if the controller config does not set the delay before an object whose status
+// is out of sync is checked again.
+const DefaultOutOfSyncRecheckDelay = 5 * time.Second
 
 // MonitorSubController monitor the sync state of federated resources
 type MonitorSubController struct {
@@ -76,6 +78,8 @@ type MonitorSubController struct {
 	worker     worker.ReconcileWorker
 	typeConfig *fedcorev1a1.FederatedTypeConfig
 	meters     *sync.Map
+
+	outOfSyncRecheckDelay time.Duration
 }
 
 // StartMonitorSubController starts a new monitor controller to report metrics.
@@ -109,10 +113,14 @@ func newMonitorSubController(
 	rest.AddUserAgent(configCopy, userAgent)
 
 	m := &MonitorSubController{
-		name:       userAgent,
-		kind:       federatedTypeAPIResource.Kind,
-		typeConfig: typeConfig,
-		meters:     meters,
+		name:                  userAgent,
+		kind:                  federatedTypeAPIResource.Kind,
+		typeConfig:            typeConfig,
+		meters:                meters,
+		outOfSyncRecheckDelay: controllerConfig.OutOfSyncRecheckDelay,
+	}
+	if m.outOfSyncRecheckDelay == 0 {
+		m.outOfSyncRecheckDelay = DefaultOutOfSyncRecheckDelay
 	}
 
 	var err error
@@ -121,8 +129,9 @@ func newMonitorSubController(
 		return nil, err
 	}
 
-	m.worker = worker.NewReconcileWorker(m.reconcile, worker.WorkerTiming{}, controllerConfig.WorkerCount,
-		controllerConfig.Metrics, delayingdeliver.NewMetricTags("monitor-subcontroller", m.kind))
+	m.worker = worker.NewConfigurableReconcileWorker(m.reconcile, worker.WorkerTiming{},
+		controllerConfig.WorkerConfig(TuningName, typeConfig.Name), controllerConfig.Metrics,
+		delayingdeliver.NewMetricTags("monitor-subcontroller", m.kind))
 
 	m.federatedStore, m.federatedController = util.NewResourceInformer(m.federatedClient,
 		controllerConfig.TargetNamespace, m.worker.EnqueueObject, controllerConfig.Metrics)
@@ -144,7 +153,7 @@ func newMonitorSubController(
 		m.informer, err = util.NewFederatedInformer(
 			controllerConfig,
 			client,
-			configCopy,
+			controllerConfig.ClusterRestConfig(configCopy, TuningName, typeConfig.Name),
 			&targetAPIResource,
 			m.worker.EnqueueObject,
 			&util.ClusterLifecycleHandlerFuncs{},
@@ -234,7 +243,7 @@ func (m *MonitorSubController) reconcile(qualifiedName common.QualifiedName) wor
 				baseMeter.outOfSyncDuration,
 			)
 			if errors.Is(err, ErrStatusOutOfSync) {
-				return worker.Result{RequeueAfter: &OutOfSyncRecheckDelay}
+				return worker.Result{RequeueAfter: &m.outOfSyncRecheckDelay}
 			} else if errors.Is(err, ErrMissingObservedGenerationField) {
 				return worker.StatusAllOK
 			}
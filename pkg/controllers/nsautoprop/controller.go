@@ -58,6 +58,9 @@ const (
 	PrefixedNamespaceAutoPropagationControllerName = common.DefaultPrefix + NamespaceAutoPropagationControllerName
 	EventReasonNamespaceAutoPropagation            = "NamespaceAutoPropagation"
 	NoAutoPropagationAnnotation                    = common.DefaultPrefix + "no-auto-propagation"
+
+	// TuningName is the name of the controller in the tuning configuration of the controller manager.
+	TuningName = "nsautoprop"
 )
 
 /*
@@ -152,10 +155,10 @@ func newController(
 		fedNamespaceInformer:   dynamicInformerFactory.ForResource(fedNamespaceGVR),
 	}
 
-	c.worker = worker.NewReconcileWorker(
+	c.worker = worker.NewConfigurableReconcileWorker(
 		c.reconcile,
 		worker.WorkerTiming{},
-		controllerConfig.WorkerCount,
+		controllerConfig.WorkerConfig(TuningName, typeConfig.Name),
 		controllerConfig.Metrics,
 		delayingdeliver.NewMetricTags(userAgent, federatedNamespaceApiResource.Kind),
 	)
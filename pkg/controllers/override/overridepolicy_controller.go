@@ -52,6 +52,9 @@ const (
 
 	OverridePolicyNameLabel        = common.DefaultPrefix + "override-policy-name"
 	ClusterOverridePolicyNameLabel = common.DefaultPrefix + "cluster-override-policy-name"
+
+	// TuningName is the name of the controller in the tuning configuration of the controller manager.
+	TuningName = "overridepolicy"
 )
 
 var PrefixedControllerName = common.DefaultPrefix + ControllerName
@@ -132,17 +135,18 @@ func newController(
 		return nil, fmt.Errorf("NewResourceClient failed: %w", err)
 	}
 
-	c.worker = worker.NewReconcileWorker(
+	c.worker = worker.NewConfigurableReconcileWorker(
 		c.reconcile,
 		worker.WorkerTiming{},
-		controllerConfig.WorkerCount,
+		controllerConfig.WorkerConfig(TuningName, typeConfig.Name),
 		controllerConfig.Metrics,
 		delayingdeliver.NewMetricTags(c.name, federatedApiResource.Kind),
 	)
 	enqueueObj := c.worker.EnqueueObject
-	c.federatedStore, c.federatedController = util.NewResourceInformer(
+	c.federatedStore, c.federatedController = util.NewShardedResourceInformer(
 		c.federatedClient,
 		controllerConfig.TargetNamespace,
+		controllerConfig.Shard,
 		enqueueObj,
 		controllerConfig.Metrics,
 	)
@@ -42,6 +42,9 @@ import (
 
 const (
 	ControllerName = "policyrc-controller"
+
+	// TuningName is the name of the controller in the tuning configuration of the controller manager.
+	TuningName = "policyrc"
 )
 
 var PolicyrcControllerName = common.DefaultPrefix + "policyrc-controller"
@@ -77,7 +80,7 @@ type Controller struct {
 func StartController(controllerConfig *util.ControllerConfig,
 	stopChan <-chan struct{}, typeConfig *fedcorev1a1.FederatedTypeConfig,
 ) error {
-	controller, err := newController(controllerConfig, typeConfig)
+	controller, err := NewController(controllerConfig, typeConfig)
 	if err != nil {
 		return err
 	}
@@ -86,7 +89,8 @@ func StartController(controllerConfig *util.ControllerConfig,
 	return nil
 }
 
-func newController(controllerConfig *util.ControllerConfig,
+// NewController returns a new policyrc controller for the federated type.
+func NewController(controllerConfig *util.ControllerConfig,
 	typeConfig *fedcorev1a1.FederatedTypeConfig,
 ) (*Controller, error) {
 	federatedAPIResource := typeConfig.GetFederatedType()
@@ -110,21 +114,21 @@ func newController(controllerConfig *util.ControllerConfig,
 		delayingdeliver.NewMetricTags("policyrc-controller-count-worker", c.typeConfig.GetFederatedType().Kind),
 	)
 
-	c.persistPpWorker = worker.NewReconcileWorker(
+	c.persistPpWorker = worker.NewConfigurableReconcileWorker(
 		func(qualifiedName common.QualifiedName) worker.Result {
 			return c.reconcilePersist("propagation-policy", qualifiedName, c.pp.store, c.cpp.store, c.ppCounter)
 		},
 		worker.WorkerTiming{},
-		controllerConfig.WorkerCount,
+		controllerConfig.WorkerConfig(TuningName, typeConfig.Name),
 		controllerConfig.Metrics,
 		delayingdeliver.NewMetricTags("policyrc-controller-persist-worker", c.typeConfig.GetFederatedType().Kind),
 	)
-	c.persistOpWorker = worker.NewReconcileWorker(
+	c.persistOpWorker = worker.NewConfigurableReconcileWorker(
 		func(qualifiedName common.QualifiedName) worker.Result {
 			return c.reconcilePersist("override-policy", qualifiedName, c.op.store, c.cop.store, c.opCounter)
 		},
 		worker.WorkerTiming{},
-		controllerConfig.WorkerCount,
+		controllerConfig.WorkerConfig(TuningName, typeConfig.Name),
 		controllerConfig.Metrics,
 		delayingdeliver.NewMetricTags("policyrc-controller-persist-worker", c.typeConfig.GetFederatedType().Kind),
 	)
@@ -224,6 +228,10 @@ func (c *Controller) Run(stopChan <-chan struct{}) {
 	c.persistOpWorker.Run(stopChan)
 }
 
+func (c *Controller) IsControllerReady() bool {
+	return c.HasSynced()
+}
+
 func (c *Controller) HasSynced() bool {
 	return c.federated.controller.HasSynced()
 }
@@ -48,6 +48,10 @@ const (
 	EventReasonInvalidFollowsObject      = "InvalidFollowsObject"
 	EventReasonWebhookConfigurationError = "WebhookConfigurationError"
 	EventReasonWebhookRegistered         = "WebhookRegistered"
+	EventReasonPreemptFederatedObject    = "PreemptFederatedObject"
 
 	SchedulingTriggerHashAnnotation = common.DefaultPrefix + "scheduling-trigger-hash"
+
+	// Records the replicas of the annotated object preempted by federated objects with higher priorities.
+	PreemptionsAnnotation = common.DefaultPrefix + "preemptions"
 )
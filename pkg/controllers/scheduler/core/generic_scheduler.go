@@ -50,6 +50,8 @@ type ScheduleResult struct {
 	// The key is the name of the cluster and the value is the recommended number of replicas for it.
 	// If the value is nil, it means that there is no recommended number of replicas for the cluster (used in Duplicate scheduling mode).
 	SuggestedClusters map[string]*int64
+	// FeasibleClusters contains the names of the clusters that passed the filter plugins.
+	FeasibleClusters []string
 }
 
 func (result ScheduleResult) ClusterSet() map[string]struct{} {
@@ -100,6 +102,9 @@ func (g *genericScheduler) Schedule(
 	// we do not reschedule if sticky cluster is enabled
 	if schedulingUnit.StickyCluster && len(schedulingUnit.CurrentClusters) > 0 {
 		result.SuggestedClusters = schedulingUnit.CurrentClusters
+		for cluster := range schedulingUnit.CurrentClusters {
+			result.FeasibleClusters = append(result.FeasibleClusters, cluster)
+		}
 		return result, nil
 	}
 
@@ -112,6 +117,9 @@ func (g *genericScheduler) Schedule(
 	if len(feasibleClusters) == 0 {
 		return result, nil
 	}
+	for _, cluster := range feasibleClusters {
+		result.FeasibleClusters = append(result.FeasibleClusters, cluster.Name)
+	}
 
 	clusterScores, err := g.scoreClusters(ctx, fwk, schedulingUnit, feasibleClusters)
 	if err != nil {
@@ -128,8 +136,16 @@ func (g *genericScheduler) Schedule(
 
 	// we skip replica scheduling if mode is Duplicate
 	if schedulingUnit.SchedulingMode == fedcorev1a1.SchedulingModeDuplicate {
-		logger.V(3).Info("skip replica scheduling for Duplicate scheduling mode")
 		result.SuggestedClusters = make(map[string]*int64, len(selectedClusters))
+		if schedulingUnit.DuplicateWithScale && schedulingUnit.DesiredReplicas != nil {
+			for cluster, replicas := range scaleDuplicateReplicas(&schedulingUnit, selectedClusters) {
+				result.SuggestedClusters[cluster] = pointer.Int64(replicas)
+			}
+			logger.V(2).Info("Duplicate replicas scaled", "result", spew.Sprint(result.SuggestedClusters))
+			return result, nil
+		}
+
+		logger.V(3).Info("skip replica scheduling for Duplicate scheduling mode")
 		for _, cluster := range selectedClusters {
 			result.SuggestedClusters[cluster.Name] = nil
 		}
@@ -216,3 +232,46 @@ func (g *genericScheduler) replicaScheduling(
 	}
 	return clusterReplicasList, nil
 }
+
+// scaleDuplicateReplicas computes the replicas of each selected cluster in Duplicate scheduling mode with scaling.
+// A cluster with a percentage gets that percentage of the desired replicas, a cluster with a weight gets the desired
+// replicas scaled by its weight relative to the maximum weight among the selected clusters, and any other cluster gets
+// the desired replicas. Fractions are rounded up and the result is clamped to the cluster's min and max replicas.
+func scaleDuplicateReplicas(
+	schedulingUnit *framework.SchedulingUnit,
+	clusters []*fedcorev1a1.FederatedCluster,
+) map[string]int64 {
+	desired := *schedulingUnit.DesiredReplicas
+
+	maxWeight := int64(0)
+	for _, cluster := range clusters {
+		if weight, exists := schedulingUnit.Weights[cluster.Name]; exists && weight > maxWeight {
+			maxWeight = weight
+		}
+	}
+
+	result := make(map[string]int64, len(clusters))
+	for _, cluster := range clusters {
+		replicas := desired
+		if percentage, exists := schedulingUnit.Percentages[cluster.Name]; exists {
+			replicas = ceilDiv(desired*percentage, 100)
+		} else if weight, exists := schedulingUnit.Weights[cluster.Name]; exists && maxWeight > 0 {
+			replicas = ceilDiv(desired*weight, maxWeight)
+		}
+
+		if minReplicas, exists := schedulingUnit.MinReplicas[cluster.Name]; exists && replicas < minReplicas {
+			replicas = minReplicas
+		}
+		if maxReplicas, exists := schedulingUnit.MaxReplicas[cluster.Name]; exists && replicas > maxReplicas {
+			replicas = maxReplicas
+		}
+
+		result[cluster.Name] = replicas
+	}
+
+	return result
+}
+
+func ceilDiv(dividend, divisor int64) int64 {
+	return (dividend + divisor - 1) / divisor
+}
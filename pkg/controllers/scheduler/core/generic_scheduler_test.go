@@ -194,3 +194,78 @@ func TestSchedulingWithStickyCluster(t *testing.T) {
 		}
 	})
 }
+
+func TestScaleDuplicateReplicas(t *testing.T) {
+	clusters := []*fedcorev1a1.FederatedCluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster2"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster3"}},
+	}
+
+	testCases := map[string]struct {
+		schedulingUnit *framework.SchedulingUnit
+		expected       map[string]int64
+	}{
+		"no preferences duplicates desired replicas": {
+			schedulingUnit: &framework.SchedulingUnit{DesiredReplicas: pointer.Int64(10)},
+			expected:       map[string]int64{"cluster1": 10, "cluster2": 10, "cluster3": 10},
+		},
+		"weights scale relative to the maximum weight": {
+			schedulingUnit: &framework.SchedulingUnit{
+				DesiredReplicas: pointer.Int64(10),
+				Weights:         map[string]int64{"cluster1": 4, "cluster2": 2, "cluster3": 1},
+			},
+			expected: map[string]int64{"cluster1": 10, "cluster2": 5, "cluster3": 3},
+		},
+		"percentages take precedence over weights": {
+			schedulingUnit: &framework.SchedulingUnit{
+				DesiredReplicas: pointer.Int64(10),
+				Weights:         map[string]int64{"cluster1": 1, "cluster2": 1},
+				Percentages:     map[string]int64{"cluster1": 150, "cluster2": 25},
+			},
+			expected: map[string]int64{"cluster1": 15, "cluster2": 3, "cluster3": 10},
+		},
+		"replicas are clamped to min and max replicas": {
+			schedulingUnit: &framework.SchedulingUnit{
+				DesiredReplicas: pointer.Int64(10),
+				Percentages:     map[string]int64{"cluster1": 10, "cluster2": 200},
+				MinReplicas:     map[string]int64{"cluster1": 2},
+				MaxReplicas:     map[string]int64{"cluster2": 12, "cluster3": 5},
+			},
+			expected: map[string]int64{"cluster1": 2, "cluster2": 12, "cluster3": 5},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			result := scaleDuplicateReplicas(tc.schedulingUnit, clusters)
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Errorf("unexpected replicas, want %v but got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestSchedulingWithDuplicateWithScale(t *testing.T) {
+	clusters := []*fedcorev1a1.FederatedCluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster2"}},
+	}
+	scheduler := NewSchedulerAlgorithm()
+
+	schedulingUnit := &framework.SchedulingUnit{
+		DesiredReplicas:    pointer.Int64(10),
+		SchedulingMode:     fedcorev1a1.SchedulingModeDuplicate,
+		DuplicateWithScale: true,
+		Weights:            map[string]int64{"cluster1": 2, "cluster2": 1},
+	}
+	result, err := scheduler.Schedule(context.TODO(), getFramework(), *schedulingUnit, clusters)
+	if err != nil {
+		t.Errorf("unexpected error when scheduling: %v", err)
+	}
+	expected := map[string]*int64{"cluster1": pointer.Int64(10), "cluster2": pointer.Int64(5)}
+	if !reflect.DeepEqual(result.SuggestedClusters, expected) {
+		t.Errorf("unexpected scheduling result, want %v but got %v", expected, result.SuggestedClusters)
+	}
+}
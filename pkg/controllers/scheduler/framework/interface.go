@@ -85,4 +85,7 @@ type ReplicasPlugin interface {
 
 type Handle interface {
 	DynamicClient() dynamic.Interface
+	// SupplyLimitProportion returns the proportion used by replicas plugins to limit the share of replicas assigned to
+	// each cluster, or 0 if the plugins should use their default.
+	SupplyLimitProportion() float64
 }
@@ -0,0 +1,64 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustermaintenance
+
+import (
+	"context"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework/plugins/names"
+)
+
+// ClusterMaintenance filters out clusters in maintenance mode. Cordoned clusters only remain schedulable for the
+// scheduling units already placed in them, while draining clusters are not schedulable at all.
+type ClusterMaintenance struct{}
+
+func NewClusterMaintenance(_ framework.Handle) (framework.Plugin, error) {
+	return &ClusterMaintenance{}, nil
+}
+
+func (pl *ClusterMaintenance) Name() string {
+	return names.ClusterMaintenance
+}
+
+func (pl *ClusterMaintenance) Filter(
+	ctx context.Context,
+	su *framework.SchedulingUnit,
+	cluster *fedcorev1a1.FederatedCluster,
+) *framework.Result {
+	err := framework.PreCheck(ctx, su, cluster)
+	if err != nil {
+		return framework.NewResult(framework.Error, err.Error())
+	}
+
+	if cluster.Spec.Maintenance == nil {
+		return framework.NewResult(framework.Success)
+	}
+
+	switch cluster.Spec.Maintenance.Mode {
+	case fedcorev1a1.ClusterMaintenanceModeCordon:
+		if _, isClusterScheduled := su.CurrentClusters[cluster.Name]; isClusterScheduled {
+			return framework.NewResult(framework.Success)
+		}
+		return framework.NewResult(framework.Unschedulable, "cluster is cordoned")
+	case fedcorev1a1.ClusterMaintenanceModeDrain:
+		return framework.NewResult(framework.Unschedulable, "cluster is being drained")
+	default:
+		return framework.NewResult(framework.Success)
+	}
+}
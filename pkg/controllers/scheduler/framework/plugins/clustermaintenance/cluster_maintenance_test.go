@@ -0,0 +1,89 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clustermaintenance
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+)
+
+func makeCluster(clusterName string, mode fedcorev1a1.ClusterMaintenanceMode) *fedcorev1a1.FederatedCluster {
+	cluster := &fedcorev1a1.FederatedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterName,
+		},
+	}
+	if mode != "" {
+		cluster.Spec.Maintenance = &fedcorev1a1.ClusterMaintenance{Mode: mode}
+	}
+	return cluster
+}
+
+func TestClusterMaintenanceFilterPlugin(t *testing.T) {
+	tests := []struct {
+		name           string
+		su             *framework.SchedulingUnit
+		cluster        *fedcorev1a1.FederatedCluster
+		expectedResult *framework.Result
+	}{
+		{
+			"cluster should not be filtered when not in maintenance",
+			&framework.SchedulingUnit{},
+			makeCluster("cluster1", ""),
+			framework.NewResult(framework.Success),
+		},
+		{
+			"cordoned cluster should be filtered when not currently scheduled",
+			&framework.SchedulingUnit{
+				CurrentClusters: map[string]*int64{"cluster2": nil},
+			},
+			makeCluster("cluster1", fedcorev1a1.ClusterMaintenanceModeCordon),
+			framework.NewResult(framework.Unschedulable),
+		},
+		{
+			"cordoned cluster should not be filtered when currently scheduled",
+			&framework.SchedulingUnit{
+				CurrentClusters: map[string]*int64{"cluster1": nil},
+			},
+			makeCluster("cluster1", fedcorev1a1.ClusterMaintenanceModeCordon),
+			framework.NewResult(framework.Success),
+		},
+		{
+			"draining cluster should be filtered when currently scheduled",
+			&framework.SchedulingUnit{
+				CurrentClusters: map[string]*int64{"cluster1": nil},
+			},
+			makeCluster("cluster1", fedcorev1a1.ClusterMaintenanceModeDrain),
+			framework.NewResult(framework.Unschedulable),
+		},
+	}
+
+	p, _ := NewClusterMaintenance(nil)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := p.(framework.FilterPlugin).Filter(context.TODO(), test.su, test.cluster)
+			if result.IsSuccess() != test.expectedResult.IsSuccess() {
+				t.Errorf("result does not match: %v, want %v", result, test.expectedResult)
+			}
+		})
+	}
+}
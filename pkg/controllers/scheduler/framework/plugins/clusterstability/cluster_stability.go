@@ -0,0 +1,60 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterstability
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework/plugins/names"
+)
+
+// ClusterStability deprioritises clusters that have been marked unstable because their health has been flapping.
+type ClusterStability struct{}
+
+func NewClusterStability(_ framework.Handle) (framework.Plugin, error) {
+	return &ClusterStability{}, nil
+}
+
+func (pl *ClusterStability) Name() string {
+	return names.ClusterStability
+}
+
+func (pl *ClusterStability) Score(
+	ctx context.Context,
+	su *framework.SchedulingUnit,
+	cluster *fedcorev1a1.FederatedCluster,
+) (int64, *framework.Result) {
+	err := framework.PreCheck(ctx, su, cluster)
+	if err != nil {
+		return 0, framework.NewResult(framework.Error, err.Error())
+	}
+
+	for _, condition := range cluster.Status.Conditions {
+		if condition.Type == fedcorev1a1.ClusterUnstable && condition.Status == corev1.ConditionTrue {
+			return framework.MinClusterScore, framework.NewResult(framework.Success)
+		}
+	}
+	return framework.MaxClusterScore, framework.NewResult(framework.Success)
+}
+
+func (pl *ClusterStability) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterstability
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+)
+
+func makeCluster(clusterName string, unstable *corev1.ConditionStatus) *fedcorev1a1.FederatedCluster {
+	cluster := &fedcorev1a1.FederatedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterName,
+		},
+	}
+	if unstable != nil {
+		cluster.Status.Conditions = []fedcorev1a1.ClusterCondition{
+			{Type: fedcorev1a1.ClusterUnstable, Status: *unstable},
+		}
+	}
+	return cluster
+}
+
+func TestClusterStabilityScorePlugin(t *testing.T) {
+	conditionTrue, conditionFalse := corev1.ConditionTrue, corev1.ConditionFalse
+
+	tests := []struct {
+		name          string
+		cluster       *fedcorev1a1.FederatedCluster
+		expectedScore int64
+	}{
+		{
+			"cluster without unstable condition",
+			makeCluster("cluster1", nil),
+			framework.MaxClusterScore,
+		},
+		{
+			"stable cluster",
+			makeCluster("cluster1", &conditionFalse),
+			framework.MaxClusterScore,
+		},
+		{
+			"unstable cluster",
+			makeCluster("cluster1", &conditionTrue),
+			framework.MinClusterScore,
+		},
+	}
+
+	p, _ := NewClusterStability(nil)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			score, result := p.(framework.ScorePlugin).Score(context.TODO(), &framework.SchedulingUnit{}, test.cluster)
+			if !result.IsSuccess() {
+				t.Fatalf("unexpected result: %v", result)
+			}
+			if score != test.expectedScore {
+				t.Errorf("score does not match: %d, want %d", score, test.expectedScore)
+			}
+		})
+	}
+}
@@ -27,4 +27,7 @@ const (
 	ClusterResourcesMostAllocated      = "ClusterResourcesMostAllocated"
 	MaxCluster                         = "MaxCluster"
 	ClusterCapacityWeight              = "ClusterCapacityWeight"
+	WorkloadAffinity                   = "WorkloadAffinity"
+	ClusterMaintenance                 = "ClusterMaintenance"
+	ClusterStability                   = "ClusterStability"
 )
@@ -39,8 +39,9 @@ import (
 )
 
 const (
-	supplyLimitProportion         = 1.4
-	sumWeight             float64 = 1000
+	// DefaultSupplyLimitProportion is used if the framework handle does not specify a supply limit proportion.
+	DefaultSupplyLimitProportion         = 1.4
+	sumWeight                    float64 = 1000
 )
 
 const (
@@ -50,12 +51,18 @@ const (
 
 var ErrNoCPUResource = errors.New("no cpu resource")
 
-type ClusterCapacityWeight struct{}
+type ClusterCapacityWeight struct {
+	supplyLimitProportion float64
+}
 
 var _ framework.ReplicasPlugin = &ClusterCapacityWeight{}
 
 func NewClusterCapacityWeight(frameworkHandle framework.Handle) (framework.Plugin, error) {
-	return &ClusterCapacityWeight{}, nil
+	pl := &ClusterCapacityWeight{}
+	if frameworkHandle != nil {
+		pl.supplyLimitProportion = frameworkHandle.SupplyLimitProportion()
+	}
+	return pl, nil
 }
 
 func (pl *ClusterCapacityWeight) Name() string {
@@ -77,6 +84,10 @@ func (pl *ClusterCapacityWeight) ReplicaScheduling(
 			return clusterReplicasList, framework.NewResult(framework.Error)
 		}
 
+		supplyLimitProportion := pl.supplyLimitProportion
+		if supplyLimitProportion == 0 {
+			supplyLimitProportion = DefaultSupplyLimitProportion
+		}
 		weightLimit, err := CalcWeightLimit(clusters, supplyLimitProportion)
 		if err != nil {
 			return clusterReplicasList, framework.NewResult(
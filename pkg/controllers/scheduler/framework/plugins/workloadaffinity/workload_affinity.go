@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloadaffinity
+
+import (
+	"context"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework/plugins/names"
+)
+
+const (
+	// ErrReasonAffinityNotMatch is returned when a cluster does not host the objects required by an affinity term.
+	ErrReasonAffinityNotMatch = "cluster(s) didn't match workload affinity rules"
+	// ErrReasonAntiAffinityNotMatch is returned when a cluster hosts objects excluded by an anti-affinity term.
+	ErrReasonAntiAffinityNotMatch = "cluster(s) didn't match workload anti-affinity rules"
+)
+
+type WorkloadAffinity struct{}
+
+func NewWorkloadAffinity(_ framework.Handle) (framework.Plugin, error) {
+	return &WorkloadAffinity{}, nil
+}
+
+func (pl *WorkloadAffinity) Name() string {
+	return names.WorkloadAffinity
+}
+
+// Filter invoked at the filter extension point.
+func (pl *WorkloadAffinity) Filter(
+	ctx context.Context,
+	su *framework.SchedulingUnit,
+	cluster *fedcorev1a1.FederatedCluster,
+) *framework.Result {
+	err := framework.PreCheck(ctx, su, cluster)
+	if err != nil {
+		return framework.NewResult(framework.Error, err.Error())
+	}
+
+	if su.WorkloadAffinity == nil {
+		return framework.NewResult(framework.Success)
+	}
+
+	// A term that selects no placed objects cannot be satisfied by any cluster, which keeps the
+	// scheduling unit pending until the referenced objects are scheduled.
+	for i := range su.WorkloadAffinity.Affinity {
+		if !su.WorkloadAffinity.Affinity[i].HasCluster(cluster.Name) {
+			return framework.NewResult(framework.Unschedulable, ErrReasonAffinityNotMatch)
+		}
+	}
+
+	for i := range su.WorkloadAffinity.AntiAffinity {
+		if su.WorkloadAffinity.AntiAffinity[i].HasCluster(cluster.Name) {
+			return framework.NewResult(framework.Unschedulable, ErrReasonAntiAffinityNotMatch)
+		}
+	}
+
+	return framework.NewResult(framework.Success)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package workloadaffinity
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+)
+
+func makeCluster(clusterName string) *fedcorev1a1.FederatedCluster {
+	return &fedcorev1a1.FederatedCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterName,
+		},
+	}
+}
+
+func makeTerm(placements map[string][]string) framework.WorkloadAffinityTerm {
+	term := framework.WorkloadAffinityTerm{
+		Placements: make(map[string]sets.Set[string], len(placements)),
+	}
+	for key, clusters := range placements {
+		term.Placements[key] = sets.New(clusters...)
+	}
+	return term
+}
+
+func TestWorkloadAffinityFilterPlugin(t *testing.T) {
+	tests := []struct {
+		name           string
+		su             *framework.SchedulingUnit
+		cluster        *fedcorev1a1.FederatedCluster
+		expectedResult *framework.Result
+	}{
+		{
+			"cluster should not be filtered when workload affinity is nil",
+			&framework.SchedulingUnit{},
+			makeCluster("cluster1"),
+			framework.NewResult(framework.Success),
+		},
+		{
+			"cluster should not be filtered when it hosts objects selected by all affinity terms",
+			&framework.SchedulingUnit{
+				WorkloadAffinity: &framework.WorkloadAffinity{
+					Affinity: []framework.WorkloadAffinityTerm{
+						makeTerm(map[string][]string{"default/sts": {"cluster1", "cluster2"}}),
+						makeTerm(map[string][]string{"default/svc-a": {"cluster2"}, "default/svc-b": {"cluster1"}}),
+					},
+				},
+			},
+			makeCluster("cluster1"),
+			framework.NewResult(framework.Success),
+		},
+		{
+			"cluster should be filtered when it does not host objects selected by an affinity term",
+			&framework.SchedulingUnit{
+				WorkloadAffinity: &framework.WorkloadAffinity{
+					Affinity: []framework.WorkloadAffinityTerm{
+						makeTerm(map[string][]string{"default/sts": {"cluster1"}}),
+						makeTerm(map[string][]string{"default/svc": {"cluster2"}}),
+					},
+				},
+			},
+			makeCluster("cluster1"),
+			framework.NewResult(framework.Unschedulable),
+		},
+		{
+			"cluster should be filtered when an affinity term selects no objects",
+			&framework.SchedulingUnit{
+				WorkloadAffinity: &framework.WorkloadAffinity{
+					Affinity: []framework.WorkloadAffinityTerm{makeTerm(nil)},
+				},
+			},
+			makeCluster("cluster1"),
+			framework.NewResult(framework.Unschedulable),
+		},
+		{
+			"cluster should be filtered when it hosts objects selected by an anti-affinity term",
+			&framework.SchedulingUnit{
+				WorkloadAffinity: &framework.WorkloadAffinity{
+					AntiAffinity: []framework.WorkloadAffinityTerm{
+						makeTerm(map[string][]string{"default/svc": {"cluster2"}}),
+						makeTerm(map[string][]string{"default/dp": {"cluster1", "cluster3"}}),
+					},
+				},
+			},
+			makeCluster("cluster1"),
+			framework.NewResult(framework.Unschedulable),
+		},
+		{
+			"cluster should not be filtered when it hosts no objects selected by anti-affinity terms",
+			&framework.SchedulingUnit{
+				WorkloadAffinity: &framework.WorkloadAffinity{
+					AntiAffinity: []framework.WorkloadAffinityTerm{
+						makeTerm(map[string][]string{"default/svc": {"cluster2"}}),
+						makeTerm(nil),
+					},
+				},
+			},
+			makeCluster("cluster1"),
+			framework.NewResult(framework.Success),
+		},
+	}
+
+	p, _ := NewWorkloadAffinity(nil)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := p.(framework.FilterPlugin).Filter(context.TODO(), test.su, test.cluster)
+			if result.IsSuccess() != test.expectedResult.IsSuccess() {
+				t.Errorf("result does not match: %v, want %v", result, test.expectedResult)
+			}
+		})
+	}
+}
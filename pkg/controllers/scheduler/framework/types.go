@@ -26,6 +26,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
 )
@@ -53,19 +54,22 @@ type SchedulingUnit struct {
 	AutoMigration   *AutoMigrationSpec
 
 	// Controls the scheduling behavior
-	SchedulingMode  fedcorev1a1.SchedulingMode
-	StickyCluster   bool
-	AvoidDisruption bool
+	SchedulingMode     fedcorev1a1.SchedulingMode
+	DuplicateWithScale bool
+	StickyCluster      bool
+	AvoidDisruption    bool
 
 	// Used to filter/select clusters
-	ClusterSelector map[string]string
-	ClusterNames    map[string]struct{}
-	Affinity        *Affinity
-	Tolerations     []corev1.Toleration
-	MaxClusters     *int64
-	MinReplicas     map[string]int64
-	MaxReplicas     map[string]int64
-	Weights         map[string]int64
+	ClusterSelector  map[string]string
+	ClusterNames     map[string]struct{}
+	Affinity         *Affinity
+	Tolerations      []corev1.Toleration
+	WorkloadAffinity *WorkloadAffinity
+	MaxClusters      *int64
+	MinReplicas      map[string]int64
+	MaxReplicas      map[string]int64
+	Weights          map[string]int64
+	Percentages      map[string]int64
 }
 
 type AutoMigrationSpec struct {
@@ -85,6 +89,34 @@ type Affinity struct {
 	ClusterAffinity *ClusterAffinity `json:"clusterAffinity,omitempty"`
 }
 
+// WorkloadAffinity is a group of inter-workload affinity scheduling rules.
+type WorkloadAffinity struct {
+	// The scheduling unit will only be scheduled onto a cluster if, for every term,
+	// the cluster hosts at least one of the objects selected by the term.
+	Affinity []WorkloadAffinityTerm
+	// The scheduling unit will not be scheduled onto a cluster if the cluster
+	// hosts any of the objects selected by any of the terms.
+	AntiAffinity []WorkloadAffinityTerm
+}
+
+// WorkloadAffinityTerm is a workload affinity term resolved against the current placements of the objects it selects.
+type WorkloadAffinityTerm struct {
+	// Term is the original term from the propagation policy.
+	Term fedcorev1a1.WorkloadAffinityTerm
+	// Placements maps the key of each selected object to the clusters it is placed in.
+	Placements map[string]sets.Set[string]
+}
+
+// HasCluster returns true if any of the objects selected by the term is placed in the given cluster.
+func (t *WorkloadAffinityTerm) HasCluster(cluster string) bool {
+	for _, clusters := range t.Placements {
+		if clusters.Has(cluster) {
+			return true
+		}
+	}
+	return false
+}
+
 // ClusterAffinity is a group of node affinity scheduling rules.
 type ClusterAffinity struct {
 	// If the affinity requirements specified by this field are not met at
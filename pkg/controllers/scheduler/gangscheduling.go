@@ -0,0 +1,314 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/core"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/worker"
+)
+
+// The interval at which pending gangs are rescheduled. Changes to the free resources of clusters are not scheduling
+// triggers, so pending gangs have to be retried periodically.
+const gangPendingRequeueInterval = 30 * time.Second
+
+// getGangMinAvailable returns the minimum number of replicas that must be able to run for the scheduling unit to be
+// scheduled, or nil if gang scheduling is not enabled for the scheduling unit.
+func getGangMinAvailable(policy fedcorev1a1.GenericPropagationPolicy, su *framework.SchedulingUnit) *int64 {
+	gangScheduling := policy.GetSpec().GangScheduling
+	if gangScheduling == nil || su == nil ||
+		su.SchedulingMode != fedcorev1a1.SchedulingModeDivide || su.DesiredReplicas == nil {
+		return nil
+	}
+
+	minAvailable := *su.DesiredReplicas
+	if gangScheduling.MinAvailable != nil && *gangScheduling.MinAvailable < minAvailable {
+		minAvailable = *gangScheduling.MinAvailable
+	}
+	return &minAvailable
+}
+
+// schedulableReplicas returns the number of replicas in the scheduling result that can run in the selected clusters.
+// The replicas that can run in a cluster are limited by the capacity estimated by auto migration and the free resources
+// of the cluster, including the resources in released that will be freed by preemption.
+func (s *Scheduler) schedulableReplicas(
+	fedObject *unstructured.Unstructured,
+	su *framework.SchedulingUnit,
+	result *core.ScheduleResult,
+	clusters []*fedcorev1a1.FederatedCluster,
+	released map[string]*framework.Resource,
+) (int64, error) {
+	request, err := getReplicaResourceRequest(s.typeConfig, fedObject)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get resource request: %w", err)
+	}
+
+	var estimatedCapacity map[string]int64
+	if su.AutoMigration != nil && su.AutoMigration.Info != nil {
+		estimatedCapacity = su.AutoMigration.Info.EstimatedCapacity
+	}
+
+	clusterMap := make(map[string]*fedcorev1a1.FederatedCluster, len(clusters))
+	for _, cluster := range clusters {
+		clusterMap[cluster.Name] = cluster
+	}
+
+	schedulable := int64(0)
+	for clusterName, replicas := range result.SuggestedClusters {
+		if replicas == nil {
+			continue
+		}
+
+		capacity := *replicas
+		if ec, exists := estimatedCapacity[clusterName]; exists && ec >= 0 && ec < capacity {
+			capacity = ec
+		}
+
+		cluster := clusterMap[clusterName]
+		if cluster == nil || request == nil {
+			schedulable += capacity
+			continue
+		}
+
+		free := framework.NewResource(cluster.Status.Resources.Available)
+		if pending := released[clusterName]; pending != nil {
+			addResource(free, pending, 1)
+		}
+
+		if fit := replicasThatFit(free, request); fit != math.MaxInt64 {
+			if current := su.CurrentClusters[clusterName]; current != nil {
+				// the current replicas are already accounted for in the cluster's used resources
+				fit += *current
+			}
+			if fit < capacity {
+				capacity = fit
+			}
+		}
+
+		schedulable += capacity
+	}
+
+	return schedulable, nil
+}
+
+// gangLostCapacity returns whether fewer than minAvailable replicas can still run in the current placements of the
+// federated object and fewer than its current replicas, e.g. because a cluster is no longer feasible or auto migration
+// estimated a lower capacity. The placement of such a gang is released, since it cannot make progress. A gang whose
+// current replicas can all run keeps its placement while it is pending, e.g. when it is scaled up.
+func (s *Scheduler) gangLostCapacity(
+	fedObject *unstructured.Unstructured,
+	su *framework.SchedulingUnit,
+	result *core.ScheduleResult,
+	clusters []*fedcorev1a1.FederatedCluster,
+	released map[string]*framework.Resource,
+	minAvailable int64,
+) (bool, error) {
+	feasible := sets.New(result.FeasibleClusters...)
+	current := &core.ScheduleResult{SuggestedClusters: make(map[string]*int64, len(su.CurrentClusters))}
+	placed := int64(0)
+	for clusterName, replicas := range su.CurrentClusters {
+		if replicas == nil || *replicas <= 0 {
+			continue
+		}
+		placed += *replicas
+		if feasible.Has(clusterName) {
+			current.SuggestedClusters[clusterName] = replicas
+		}
+	}
+	if placed == 0 {
+		return false, nil
+	}
+
+	schedulable, err := s.schedulableReplicas(fedObject, su, current, clusters, released)
+	if err != nil {
+		return false, err
+	}
+	return schedulable < placed && schedulable < minAvailable, nil
+}
+
+// keepGangPending keeps the federated object pending if not enough replicas can run for its gang to be scheduled, and
+// retries scheduling periodically. The object stays in its current placements unless releasePlacement is set, in which
+// case its placements, replicas overrides and preemptions are removed.
+func (s *Scheduler) keepGangPending(
+	ctx context.Context,
+	fedObject *unstructured.Unstructured,
+	minAvailable, schedulable int64,
+	releasePlacement bool,
+) worker.Result {
+	keyedLogger := klog.FromContext(ctx)
+
+	if releasePlacement {
+		keyedLogger.V(2).Info("Gang lost capacity, releasing placement")
+		s.eventRecorder.Eventf(
+			fedObject,
+			corev1.EventTypeWarning,
+			EventReasonScheduleFederatedObject,
+			"gang scheduling: only %d of the minimum %d replicas can run in the current placement, releasing placement",
+			schedulable,
+			minAvailable,
+		)
+	} else {
+		keyedLogger.V(2).Info("Insufficient capacity for gang, keeping object pending")
+		s.eventRecorder.Eventf(
+			fedObject,
+			corev1.EventTypeWarning,
+			EventReasonScheduleFederatedObject,
+			"gang scheduling: only %d of the minimum %d replicas can run, keeping object pending",
+			schedulable,
+			minAvailable,
+		)
+	}
+
+	// The in-memory object contains the new scheduling trigger hash, which must not be persisted
+	// for the object to be rescheduled.
+	qualifiedName := common.NewQualifiedName(fedObject)
+	storeObject, err := s.federatedObjectFromStore(qualifiedName)
+	if err != nil {
+		keyedLogger.Error(err, "Failed to get object from store")
+		return worker.StatusError
+	}
+	storeObject = storeObject.DeepCopy()
+
+	if releasePlacement {
+		if err := s.releasePreemptions(ctx, qualifiedName, nil); err != nil {
+			keyedLogger.Error(err, "Failed to release preempted replicas")
+			return worker.StatusError
+		}
+
+		if storeObject, err = s.releaseGangPlacement(ctx, storeObject); err != nil {
+			keyedLogger.Error(err, "Failed to release placement")
+			if apierrors.IsConflict(err) {
+				return worker.StatusConflict
+			}
+			return worker.StatusError
+		}
+	}
+
+	if err := s.updateGangScheduledCondition(ctx, storeObject, &fedtypesv1a1.GenericCondition{
+		Type:   fedtypesv1a1.GangScheduledConditionType,
+		Status: corev1.ConditionFalse,
+		Reason: fedtypesv1a1.InsufficientCapacity,
+	}); err != nil {
+		keyedLogger.Error(err, "Failed to update gang scheduled condition")
+		if apierrors.IsConflict(err) {
+			return worker.StatusConflict
+		}
+		return worker.StatusError
+	}
+
+	delay := gangPendingRequeueInterval
+	return worker.Result{Success: true, RequeueAfter: &delay}
+}
+
+// releaseGangPlacement removes the placements and replicas overrides of the federated object and returns the updated
+// object.
+func (s *Scheduler) releaseGangPlacement(
+	ctx context.Context,
+	fedObject *unstructured.Unstructured,
+) (*unstructured.Unstructured, error) {
+	placementUpdated, err := util.SetPlacementClusterNames(fedObject, PrefixedGlobalSchedulerName, nil)
+	if err != nil {
+		return nil, err
+	}
+	overridesUpdated, err := UpdateReplicasOverride(s.typeConfig, fedObject, map[string]int64{})
+	if err != nil {
+		return nil, err
+	}
+	if !placementUpdated && !overridesUpdated {
+		return fedObject, nil
+	}
+
+	if _, err := s.updatePendingControllers(fedObject, true); err != nil {
+		return nil, fmt.Errorf("failed to update pending controllers: %w", err)
+	}
+	return s.federatedObjectClient.Namespace(fedObject.GetNamespace()).Update(ctx, fedObject, metav1.UpdateOptions{})
+}
+
+// updateGangScheduledCondition sets the GangScheduled condition of the federated object to the given condition, or
+// removes it if the given condition is nil. The object's status is only updated if the condition has changed.
+func (s *Scheduler) updateGangScheduledCondition(
+	ctx context.Context,
+	fedObject *unstructured.Unstructured,
+	condition *fedtypesv1a1.GenericCondition,
+) error {
+	resource := &fedtypesv1a1.GenericObjectWithStatus{}
+	if err := util.UnstructuredToInterface(fedObject, resource); err != nil {
+		return fmt.Errorf("failed to unmarshal status: %w", err)
+	}
+	if resource.Status == nil {
+		if condition == nil {
+			return nil
+		}
+		resource.Status = &fedtypesv1a1.GenericFederatedStatus{}
+	}
+
+	index := -1
+	for i, existing := range resource.Status.Conditions {
+		if existing.Type == fedtypesv1a1.GangScheduledConditionType {
+			index = i
+			break
+		}
+	}
+
+	switch {
+	case condition == nil && index == -1:
+		return nil
+	case condition == nil:
+		resource.Status.Conditions = append(resource.Status.Conditions[:index], resource.Status.Conditions[index+1:]...)
+	case index != -1 && resource.Status.Conditions[index].Status == condition.Status &&
+		resource.Status.Conditions[index].Reason == condition.Reason:
+		return nil
+	default:
+		now := time.Now().UTC().Format(time.RFC3339)
+		condition.LastUpdateTime = now
+		condition.LastTransitionTime = now
+		if index == -1 {
+			resource.Status.Conditions = append(resource.Status.Conditions, condition)
+		} else {
+			resource.Status.Conditions[index] = condition
+		}
+	}
+
+	status, err := util.InterfaceToUnstructured(resource.Status)
+	if err != nil {
+		return fmt.Errorf("failed to marshal status: %w", err)
+	}
+	fedObject.Object[common.StatusField] = status
+
+	_, err = s.federatedObjectClient.Namespace(fedObject.GetNamespace()).UpdateStatus(
+		ctx,
+		fedObject,
+		metav1.UpdateOptions{},
+	)
+	return err
+}
@@ -0,0 +1,354 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/core"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/pendingcontrollers"
+)
+
+func TestGetGangMinAvailable(t *testing.T) {
+	testCases := map[string]struct {
+		gangScheduling *fedcorev1a1.GangScheduling
+		su             *framework.SchedulingUnit
+		expected       *int64
+	}{
+		"gang scheduling disabled": {
+			su: &framework.SchedulingUnit{
+				SchedulingMode:  fedcorev1a1.SchedulingModeDivide,
+				DesiredReplicas: pointer.Int64(10),
+			},
+			expected: nil,
+		},
+		"gang scheduling is ignored in Duplicate mode": {
+			gangScheduling: &fedcorev1a1.GangScheduling{},
+			su: &framework.SchedulingUnit{
+				SchedulingMode: fedcorev1a1.SchedulingModeDuplicate,
+			},
+			expected: nil,
+		},
+		"minAvailable defaults to desired replicas": {
+			gangScheduling: &fedcorev1a1.GangScheduling{},
+			su: &framework.SchedulingUnit{
+				SchedulingMode:  fedcorev1a1.SchedulingModeDivide,
+				DesiredReplicas: pointer.Int64(10),
+			},
+			expected: pointer.Int64(10),
+		},
+		"minAvailable is used": {
+			gangScheduling: &fedcorev1a1.GangScheduling{MinAvailable: pointer.Int64(6)},
+			su: &framework.SchedulingUnit{
+				SchedulingMode:  fedcorev1a1.SchedulingModeDivide,
+				DesiredReplicas: pointer.Int64(10),
+			},
+			expected: pointer.Int64(6),
+		},
+		"minAvailable is capped by desired replicas": {
+			gangScheduling: &fedcorev1a1.GangScheduling{MinAvailable: pointer.Int64(20)},
+			su: &framework.SchedulingUnit{
+				SchedulingMode:  fedcorev1a1.SchedulingModeDivide,
+				DesiredReplicas: pointer.Int64(10),
+			},
+			expected: pointer.Int64(10),
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			policy := &fedcorev1a1.PropagationPolicy{
+				Spec: fedcorev1a1.PropagationPolicySpec{GangScheduling: tc.gangScheduling},
+			}
+			g.Expect(getGangMinAvailable(policy, tc.su)).To(gomega.Equal(tc.expected))
+		})
+	}
+}
+
+func TestSchedulableReplicas(t *testing.T) {
+	typeConfig := &fedcorev1a1.FederatedTypeConfig{
+		Spec: fedcorev1a1.FederatedTypeConfigSpec{
+			TargetType: fedcorev1a1.APIResource{
+				Group:   "apps",
+				Version: "v1",
+				Kind:    "Deployment",
+			},
+			PathDefinition: fedcorev1a1.PathDefinition{
+				ReplicasSpec: "spec.replicas",
+			},
+		},
+	}
+	s := &Scheduler{typeConfig: typeConfig}
+
+	fedObject := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"replicas": int64(10),
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{
+									"name": "worker",
+									"resources": map[string]interface{}{
+										"requests": map[string]interface{}{"cpu": "1"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}
+
+	newCluster := func(name string, availableCPU string) *fedcorev1a1.FederatedCluster {
+		return &fedcorev1a1.FederatedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: fedcorev1a1.FederatedClusterStatus{
+				Resources: fedcorev1a1.Resources{
+					Available: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(availableCPU)},
+				},
+			},
+		}
+	}
+
+	testCases := map[string]struct {
+		su       *framework.SchedulingUnit
+		clusters []*fedcorev1a1.FederatedCluster
+		result   map[string]*int64
+		released map[string]*framework.Resource
+		expected int64
+	}{
+		"all replicas fit": {
+			su:       &framework.SchedulingUnit{},
+			clusters: []*fedcorev1a1.FederatedCluster{newCluster("cluster1", "6"), newCluster("cluster2", "6")},
+			result:   map[string]*int64{"cluster1": pointer.Int64(5), "cluster2": pointer.Int64(5)},
+			expected: 10,
+		},
+		"replicas limited by free resources": {
+			su:       &framework.SchedulingUnit{},
+			clusters: []*fedcorev1a1.FederatedCluster{newCluster("cluster1", "6"), newCluster("cluster2", "2")},
+			result:   map[string]*int64{"cluster1": pointer.Int64(5), "cluster2": pointer.Int64(5)},
+			expected: 7,
+		},
+		"released resources are counted": {
+			su:       &framework.SchedulingUnit{},
+			clusters: []*fedcorev1a1.FederatedCluster{newCluster("cluster1", "6"), newCluster("cluster2", "2")},
+			result:   map[string]*int64{"cluster1": pointer.Int64(5), "cluster2": pointer.Int64(5)},
+			released: map[string]*framework.Resource{"cluster2": {MilliCPU: 2000}},
+			expected: 9,
+		},
+		"current replicas are not limited by free resources": {
+			su: &framework.SchedulingUnit{
+				CurrentClusters: map[string]*int64{"cluster2": pointer.Int64(4)},
+			},
+			clusters: []*fedcorev1a1.FederatedCluster{newCluster("cluster1", "6"), newCluster("cluster2", "0")},
+			result:   map[string]*int64{"cluster1": pointer.Int64(5), "cluster2": pointer.Int64(5)},
+			expected: 9,
+		},
+		"replicas limited by estimated capacity": {
+			su: &framework.SchedulingUnit{
+				AutoMigration: &framework.AutoMigrationSpec{
+					Info: &framework.AutoMigrationInfo{EstimatedCapacity: map[string]int64{"cluster1": 3}},
+				},
+			},
+			clusters: []*fedcorev1a1.FederatedCluster{newCluster("cluster1", "6"), newCluster("cluster2", "6")},
+			result:   map[string]*int64{"cluster1": pointer.Int64(5), "cluster2": pointer.Int64(5)},
+			expected: 8,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			schedulable, err := s.schedulableReplicas(
+				fedObject,
+				tc.su,
+				&core.ScheduleResult{SuggestedClusters: tc.result},
+				tc.clusters,
+				tc.released,
+			)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(schedulable).To(gomega.Equal(tc.expected))
+		})
+	}
+}
+
+func TestGangLostCapacity(t *testing.T) {
+	s := &Scheduler{typeConfig: &fedcorev1a1.FederatedTypeConfig{}}
+	fedObject := &unstructured.Unstructured{Object: map[string]interface{}{}}
+
+	testCases := map[string]struct {
+		su       *framework.SchedulingUnit
+		feasible []string
+		expected bool
+	}{
+		"gang without placement is kept pending": {
+			su:       &framework.SchedulingUnit{},
+			feasible: []string{"cluster1"},
+			expected: false,
+		},
+		"gang whose current replicas can all run keeps its placement": {
+			su: &framework.SchedulingUnit{
+				CurrentClusters: map[string]*int64{"cluster1": pointer.Int64(5), "cluster2": pointer.Int64(5)},
+			},
+			feasible: []string{"cluster1", "cluster2"},
+			expected: false,
+		},
+		"gang that lost a cluster below minAvailable is released": {
+			su: &framework.SchedulingUnit{
+				CurrentClusters: map[string]*int64{"cluster1": pointer.Int64(5), "cluster2": pointer.Int64(5)},
+			},
+			feasible: []string{"cluster1"},
+			expected: true,
+		},
+		"gang that lost estimated capacity below minAvailable is released": {
+			su: &framework.SchedulingUnit{
+				CurrentClusters: map[string]*int64{"cluster1": pointer.Int64(5), "cluster2": pointer.Int64(5)},
+				AutoMigration: &framework.AutoMigrationSpec{
+					Info: &framework.AutoMigrationInfo{EstimatedCapacity: map[string]int64{"cluster2": 2}},
+				},
+			},
+			feasible: []string{"cluster1", "cluster2"},
+			expected: true,
+		},
+		"gang that lost capacity above minAvailable keeps its placement": {
+			su: &framework.SchedulingUnit{
+				CurrentClusters: map[string]*int64{"cluster1": pointer.Int64(5), "cluster2": pointer.Int64(5)},
+				AutoMigration: &framework.AutoMigrationSpec{
+					Info: &framework.AutoMigrationInfo{EstimatedCapacity: map[string]int64{"cluster2": 4}},
+				},
+			},
+			feasible: []string{"cluster1", "cluster2"},
+			expected: false,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			lost, err := s.gangLostCapacity(
+				fedObject,
+				tc.su,
+				&core.ScheduleResult{FeasibleClusters: tc.feasible},
+				nil,
+				nil,
+				8,
+			)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(lost).To(gomega.Equal(tc.expected))
+		})
+	}
+}
+
+func TestKeepGangPendingReleasesPlacement(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	typeConfig := &fedcorev1a1.FederatedTypeConfig{
+		Spec: fedcorev1a1.FederatedTypeConfigSpec{
+			TargetType: fedcorev1a1.APIResource{
+				Group:   "apps",
+				Version: "v1",
+				Kind:    "Deployment",
+				Scope:   apiextv1beta1.NamespaceScoped,
+			},
+			PathDefinition: fedcorev1a1.PathDefinition{
+				ReplicasSpec: "spec.replicas",
+			},
+		},
+	}
+	gvr := schema.GroupVersionResource{Group: "types.kubeadmiral.io", Version: "v1alpha1", Resource: "federateddeployments"}
+
+	fedObject := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	fedObject.SetAPIVersion("types.kubeadmiral.io/v1alpha1")
+	fedObject.SetKind("FederatedDeployment")
+	fedObject.SetNamespace("default")
+	fedObject.SetName("gang")
+	_, err := pendingcontrollers.SetPendingControllers(fedObject, pendingcontrollers.PendingControllers{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	_, err = util.SetPlacementClusterNames(
+		fedObject,
+		PrefixedGlobalSchedulerName,
+		map[string]struct{}{"cluster1": {}, "cluster2": {}},
+	)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	_, err = UpdateReplicasOverride(typeConfig, fedObject, map[string]int64{"cluster1": 5, "cluster2": 5})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	g.Expect(indexer.Add(fedObject.DeepCopy())).To(gomega.Succeed())
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{gvr: "FederatedDeploymentList"},
+		fedObject.DeepCopy(),
+	)
+
+	s := &Scheduler{
+		typeConfig:                  typeConfig,
+		federatedObjectClient:       client.Resource(gvr),
+		federatedObjectLister:       cache.NewGenericLister(indexer, gvr.GroupResource()),
+		federatedObjectsByPreemptor: newObjectIndex(preemptorsOf),
+		eventRecorder:               record.NewFakeRecorder(10),
+	}
+
+	result := s.keepGangPending(context.TODO(), fedObject, 8, 5, true)
+	g.Expect(result.Success).To(gomega.BeTrue())
+	g.Expect(result.RequeueAfter).NotTo(gomega.BeNil())
+
+	updated, err := client.Resource(gvr).Namespace("default").Get(context.TODO(), "gang", metav1.GetOptions{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	placements, err := util.UnmarshalGenericPlacements(updated)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(placements.Spec.GetPlacementOrNil(PrefixedGlobalSchedulerName)).To(gomega.BeNil())
+
+	overrides, err := util.GetOverrides(updated, PrefixedGlobalSchedulerName)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(overrides).To(gomega.BeEmpty())
+
+	status := &fedtypesv1a1.GenericObjectWithStatus{}
+	g.Expect(util.UnstructuredToInterface(updated, status)).To(gomega.Succeed())
+	g.Expect(status.Status).NotTo(gomega.BeNil())
+	g.Expect(status.Status.Conditions).To(gomega.ContainElement(gomega.And(
+		gomega.HaveField("Type", fedtypesv1a1.GangScheduledConditionType),
+		gomega.HaveField("Status", corev1.ConditionFalse),
+		gomega.HaveField("Reason", fedtypesv1a1.InsufficientCapacity),
+	)))
+}
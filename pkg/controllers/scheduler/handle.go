@@ -25,14 +25,20 @@ import (
 
 func (s *Scheduler) buildFrameworkHandle() framework.Handle {
 	return &handle{
-		dynamicClient: s.dynamicClient,
+		dynamicClient:         s.dynamicClient,
+		supplyLimitProportion: s.config.SupplyLimitProportion,
 	}
 }
 
 type handle struct {
-	dynamicClient dynamic.Interface
+	dynamicClient         dynamic.Interface
+	supplyLimitProportion float64
 }
 
 func (f *handle) DynamicClient() dynamic.Interface {
 	return f.dynamicClient
 }
+
+func (f *handle) SupplyLimitProportion() float64 {
+	return f.supplyLimitProportion
+}
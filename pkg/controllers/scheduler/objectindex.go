@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+// objectIndex indexes the qualified names of federated objects by the values returned by its index function. It is
+// maintained from the events of the federated object informer because indexers cannot be added to a shared informer
+// that has already been started by another controller.
+type objectIndex struct {
+	indexFunc func(fedObject *unstructured.Unstructured) []string
+
+	lock    sync.RWMutex
+	objects map[string]sets.Set[common.QualifiedName]
+	values  map[common.QualifiedName][]string
+}
+
+func newObjectIndex(indexFunc func(fedObject *unstructured.Unstructured) []string) *objectIndex {
+	return &objectIndex{
+		indexFunc: indexFunc,
+		objects:   map[string]sets.Set[common.QualifiedName]{},
+		values:    map[common.QualifiedName][]string{},
+	}
+}
+
+// Get returns the qualified names of the objects indexed under the given value.
+func (i *objectIndex) Get(value string) []common.QualifiedName {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	return i.objects[value].UnsortedList()
+}
+
+func (i *objectIndex) update(fedObject *unstructured.Unstructured) {
+	key := common.NewQualifiedName(fedObject)
+	values := i.indexFunc(fedObject)
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	i.deleteLocked(key)
+	if len(values) == 0 {
+		return
+	}
+
+	i.values[key] = values
+	for _, value := range values {
+		if i.objects[value] == nil {
+			i.objects[value] = sets.New[common.QualifiedName]()
+		}
+		i.objects[value].Insert(key)
+	}
+}
+
+func (i *objectIndex) delete(key common.QualifiedName) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	i.deleteLocked(key)
+}
+
+func (i *objectIndex) deleteLocked(key common.QualifiedName) {
+	for _, value := range i.values[key] {
+		i.objects[value].Delete(key)
+		if i.objects[value].Len() == 0 {
+			delete(i.objects, value)
+		}
+	}
+	delete(i.values, key)
+}
+
+// EventHandler returns the event handler that keeps the index up to date with the federated object informer.
+func (i *objectIndex) EventHandler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if fedObject, ok := obj.(*unstructured.Unstructured); ok {
+				i.update(fedObject)
+			}
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			if fedObject, ok := newObj.(*unstructured.Unstructured); ok {
+				i.update(fedObject)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if deleted, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				i.delete(common.NewQualifiedFromString(deleted.Key))
+				return
+			}
+			if fedObject, ok := obj.(*unstructured.Unstructured); ok {
+				i.delete(common.NewQualifiedName(fedObject))
+			}
+		},
+	}
+}
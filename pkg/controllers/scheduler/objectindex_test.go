@@ -0,0 +1,67 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+func TestObjectIndex(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	newObject := func(name, policy string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetNamespace("default")
+		obj.SetName(name)
+		if policy != "" {
+			obj.SetLabels(map[string]string{PropagationPolicyNameLabel: policy})
+		}
+		return obj
+	}
+
+	index := newObjectIndex(func(fedObject *unstructured.Unstructured) []string {
+		if policyKey, found := MatchedPolicyKey(fedObject, true); found {
+			return []string{policyKey.String()}
+		}
+		return nil
+	})
+	handler := index.EventHandler()
+
+	handler.OnAdd(newObject("a", "pp1"))
+	handler.OnAdd(newObject("b", "pp1"))
+	handler.OnAdd(newObject("c", ""))
+	g.Expect(index.Get("default/pp1")).To(gomega.ConsistOf(
+		common.QualifiedName{Namespace: "default", Name: "a"},
+		common.QualifiedName{Namespace: "default", Name: "b"},
+	))
+
+	handler.OnUpdate(newObject("b", "pp1"), newObject("b", "pp2"))
+	g.Expect(index.Get("default/pp1")).To(gomega.ConsistOf(common.QualifiedName{Namespace: "default", Name: "a"}))
+	g.Expect(index.Get("default/pp2")).To(gomega.ConsistOf(common.QualifiedName{Namespace: "default", Name: "b"}))
+
+	handler.OnDelete(newObject("a", "pp1"))
+	handler.OnDelete(cache.DeletedFinalStateUnknown{Key: "default/b"})
+	g.Expect(index.Get("default/pp1")).To(gomega.BeEmpty())
+	g.Expect(index.Get("default/pp2")).To(gomega.BeEmpty())
+	g.Expect(index.values).To(gomega.BeEmpty())
+}
@@ -0,0 +1,820 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/core"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+)
+
+// Map from replicated workload type to the path of its pod spec, used to compute the resources requested by each replica.
+var replicaPodSpecPaths = map[schema.GroupKind]string{
+	{Group: appsv1.GroupName, Kind: common.DeploymentKind}:  "spec.template.spec",
+	{Group: appsv1.GroupName, Kind: common.StatefulSetKind}: "spec.template.spec",
+	{Group: batchv1.GroupName, Kind: common.JobKind}:        "spec.template.spec",
+}
+
+// preemption records that replicas of a federated object in a cluster were preempted by a federated object with a
+// higher priority. Preemptions are stored in the PreemptionsAnnotation of the preempted object and remain in effect
+// until the preemptor is deleted or no longer scheduled to the cluster.
+type preemption struct {
+	// Preemptor is the qualified name of the preempting federated object.
+	Preemptor string `json:"preemptor"`
+	// Cluster is the cluster in which the replicas were preempted.
+	Cluster string `json:"cluster"`
+	// Replicas is the number of preempted replicas.
+	Replicas int64 `json:"replicas"`
+	// MaxReplicas is the maximum number of replicas the preempted object can be scheduled to the cluster with.
+	MaxReplicas int64 `json:"maxReplicas"`
+}
+
+func getPreemptions(fedObject *unstructured.Unstructured) ([]preemption, error) {
+	value, exists := fedObject.GetAnnotations()[PreemptionsAnnotation]
+	if !exists {
+		return nil, nil
+	}
+
+	var preemptions []preemption
+	if err := json.Unmarshal([]byte(value), &preemptions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal preemptions annotation: %w", err)
+	}
+	return preemptions, nil
+}
+
+func setPreemptions(fedObject *unstructured.Unstructured, preemptions []preemption) error {
+	annotations := fedObject.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+
+	if len(preemptions) == 0 {
+		delete(annotations, PreemptionsAnnotation)
+		fedObject.SetAnnotations(annotations)
+		return nil
+	}
+
+	// sort the preemptions to ensure a deterministic scheduling trigger hash
+	sortPreemptions(preemptions)
+
+	value, err := json.Marshal(preemptions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal preemptions annotation: %w", err)
+	}
+	annotations[PreemptionsAnnotation] = string(value)
+	fedObject.SetAnnotations(annotations)
+	return nil
+}
+
+func sortPreemptions(preemptions []preemption) {
+	sort.Slice(preemptions, func(i, j int) bool {
+		if preemptions[i].Preemptor != preemptions[j].Preemptor {
+			return preemptions[i].Preemptor < preemptions[j].Preemptor
+		}
+		return preemptions[i].Cluster < preemptions[j].Cluster
+	})
+}
+
+// preemptorsOf returns the qualified names of the federated objects that preempted replicas of the federated object.
+func preemptorsOf(fedObject *unstructured.Unstructured) []string {
+	preemptions, err := getPreemptions(fedObject)
+	if err != nil || len(preemptions) == 0 {
+		return nil
+	}
+
+	preemptors := sets.New[string]()
+	for _, p := range preemptions {
+		preemptors.Insert(p.Preemptor)
+	}
+	return sets.List(preemptors)
+}
+
+// getMaxReplicasFromPreemptions returns the per-cluster replica limits imposed on the federated object by preemptions.
+func getMaxReplicasFromPreemptions(fedObject *unstructured.Unstructured) map[string]int64 {
+	preemptions, err := getPreemptions(fedObject)
+	if err != nil {
+		klog.Errorf(
+			"Invalid value for preemptions annotation (%s) on fed object %s: %v",
+			PreemptionsAnnotation,
+			fedObject.GetName(),
+			err,
+		)
+		return nil
+	}
+
+	maxReplicas := make(map[string]int64, len(preemptions))
+	for _, p := range preemptions {
+		if current, exists := maxReplicas[p.Cluster]; !exists || p.MaxReplicas < current {
+			maxReplicas[p.Cluster] = p.MaxReplicas
+		}
+	}
+	return maxReplicas
+}
+
+// priorityForPolicy returns the priority and preemption policy of federated objects using the given policy.
+func (s *Scheduler) priorityForPolicy(policy fedcorev1a1.GenericPropagationPolicy) (int32, corev1.PreemptionPolicy, error) {
+	if policy == nil || len(policy.GetSpec().PriorityClassName) == 0 {
+		return 0, corev1.PreemptLowerPriority, nil
+	}
+
+	priorityClass, err := s.priorityClassLister.Get(policy.GetSpec().PriorityClassName)
+	if err != nil {
+		return 0, "", err
+	}
+
+	preemptionPolicy := corev1.PreemptLowerPriority
+	if priorityClass.PreemptionPolicy != nil {
+		preemptionPolicy = *priorityClass.PreemptionPolicy
+	}
+	return priorityClass.Value, preemptionPolicy, nil
+}
+
+// priorityForObject returns the priority of the federated object with the given qualified name. It is used to order
+// the scheduler's queue and returns 0 if the priority cannot be determined.
+func (s *Scheduler) priorityForObject(qualifiedName common.QualifiedName) int64 {
+	fedObject, err := s.federatedObjectFromStore(qualifiedName)
+	if err != nil {
+		return 0
+	}
+
+	policy, err := s.matchedPolicyFromStore(fedObject)
+	if err != nil || policy == nil {
+		return 0
+	}
+
+	priority, _, err := s.priorityForPolicy(policy)
+	if err != nil {
+		return 0
+	}
+	return int64(priority)
+}
+
+func (s *Scheduler) matchedPolicyFromStore(fedObject *unstructured.Unstructured) (fedcorev1a1.GenericPropagationPolicy, error) {
+	policyKey, found := MatchedPolicyKey(fedObject, s.typeConfig.GetNamespaced())
+	if !found {
+		return nil, nil
+	}
+	return s.policyFromStore(policyKey)
+}
+
+// getReplicaResourceRequest returns the resources requested by each replica of the federated object. It returns nil if
+// the resource request cannot be determined for the federated object's type.
+func getReplicaResourceRequest(
+	typeConfig *fedcorev1a1.FederatedTypeConfig,
+	fedObject *unstructured.Unstructured,
+) (*framework.Resource, error) {
+	targetType := typeConfig.GetTargetType()
+	podSpecPath, exists := replicaPodSpecPaths[schema.GroupKind{Group: targetType.Group, Kind: targetType.Kind}]
+	if !exists {
+		return nil, nil
+	}
+
+	path := append(append([]string{}, common.TemplatePath...), strings.Split(podSpecPath, ".")...)
+	podSpecMap, found, err := unstructured.NestedMap(fedObject.Object, path...)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	pod := &corev1.Pod{}
+	if err := pkgruntime.DefaultUnstructuredConverter.FromUnstructured(podSpecMap, &pod.Spec); err != nil {
+		return nil, fmt.Errorf("failed to convert pod spec: %w", err)
+	}
+	return framework.GetResourceRequest(pod), nil
+}
+
+// replicasThatFit returns the number of replicas with the given resource request that fit in the given free resources.
+func replicasThatFit(free, request *framework.Resource) int64 {
+	fit := int64(math.MaxInt64)
+	fitResource := func(free, request int64) {
+		if request <= 0 {
+			return
+		}
+		if free < 0 {
+			free = 0
+		}
+		if replicas := free / request; replicas < fit {
+			fit = replicas
+		}
+	}
+
+	fitResource(free.MilliCPU, request.MilliCPU)
+	fitResource(free.Memory, request.Memory)
+	fitResource(free.EphemeralStorage, request.EphemeralStorage)
+	for name, quantity := range request.ScalarResources {
+		fitResource(free.ScalarResources[name], quantity)
+	}
+	return fit
+}
+
+func addResource(r *framework.Resource, other *framework.Resource, times int64) {
+	r.MilliCPU += other.MilliCPU * times
+	r.Memory += other.Memory * times
+	r.EphemeralStorage += other.EphemeralStorage * times
+	for name, quantity := range other.ScalarResources {
+		r.AddScalar(name, quantity*times)
+	}
+}
+
+// preemptionCandidate is a federated object with a lower priority whose replicas may be preempted.
+type preemptionCandidate struct {
+	fedObject *unstructured.Unstructured
+	priority  int32
+	request   *framework.Resource
+
+	currentClusters map[string]*int64
+	preemptions     []preemption
+	// the number of replicas that can still be preempted without violating the disruption budget
+	budget int64
+
+	modified bool
+}
+
+// availableReplicas returns the number of replicas in the cluster that have not been preempted yet.
+func (c *preemptionCandidate) availableReplicas(cluster string) int64 {
+	replicas := c.currentClusters[cluster]
+	if replicas == nil {
+		return 0
+	}
+
+	available := *replicas
+	for _, p := range c.preemptions {
+		if p.Cluster == cluster && p.MaxReplicas < available {
+			available = p.MaxReplicas
+		}
+	}
+	return available
+}
+
+// pendingReplicas returns the number of replicas in the cluster that were preempted by the preemptor but are still
+// scheduled to the cluster.
+func (c *preemptionCandidate) pendingReplicas(preemptor, cluster string) int64 {
+	replicas := c.currentClusters[cluster]
+	if replicas == nil {
+		return 0
+	}
+
+	for _, p := range c.preemptions {
+		if p.Preemptor == preemptor && p.Cluster == cluster && *replicas > p.MaxReplicas {
+			return *replicas - p.MaxReplicas
+		}
+	}
+	return 0
+}
+
+func (c *preemptionCandidate) preempt(preemptor, cluster string, replicas int64) {
+	maxReplicas := c.availableReplicas(cluster) - replicas
+
+	found := false
+	for i := range c.preemptions {
+		if c.preemptions[i].Preemptor == preemptor && c.preemptions[i].Cluster == cluster {
+			c.preemptions[i].Replicas += replicas
+			c.preemptions[i].MaxReplicas = maxReplicas
+			found = true
+			break
+		}
+	}
+	if !found {
+		c.preemptions = append(c.preemptions, preemption{
+			Preemptor:   preemptor,
+			Cluster:     cluster,
+			Replicas:    replicas,
+			MaxReplicas: maxReplicas,
+		})
+	}
+
+	c.budget -= replicas
+	c.modified = true
+}
+
+// plannedPreemption is a preemption of replicas of a candidate in a cluster that has been planned but not applied yet.
+type plannedPreemption struct {
+	candidate *preemptionCandidate
+	cluster   string
+	replicas  int64
+}
+
+// preemptionPlan contains the preemptions required for the scheduling result of a federated object.
+type preemptionPlan struct {
+	preemptor  string
+	candidates []*preemptionCandidate
+	planned    []plannedPreemption
+	// released contains the resources in each cluster that are used by replicas that were preempted earlier but are
+	// still scheduled, or that will be preempted when the plan is applied.
+	released map[string]*framework.Resource
+}
+
+// releasedResources returns the resources in each cluster that will be available to the preemptor once the replicas
+// preempted by it are released. It is nil-safe.
+func (p *preemptionPlan) releasedResources() map[string]*framework.Resource {
+	if p == nil {
+		return nil
+	}
+	return p.released
+}
+
+// planPreemptions plans the preemption of replicas of federated objects with lower priorities in the clusters selected
+// for a Divide mode federated object if its replicas do not fit in the free resources of any feasible placement. The
+// plan is not applied until applyPreemptions is called.
+func (s *Scheduler) planPreemptions(
+	ctx context.Context,
+	fedObject *unstructured.Unstructured,
+	policy fedcorev1a1.GenericPropagationPolicy,
+	schedulingUnit *framework.SchedulingUnit,
+	result *core.ScheduleResult,
+	clusters []*fedcorev1a1.FederatedCluster,
+) (*preemptionPlan, error) {
+	keyedLogger := klog.FromContext(ctx)
+
+	preemptor := common.NewQualifiedName(fedObject).String()
+	plan := &preemptionPlan{
+		preemptor: preemptor,
+		released:  s.pendingReleaseResources(preemptor),
+	}
+
+	priority, preemptionPolicy, err := s.priorityForPolicy(policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get priority class: %w", err)
+	}
+	if preemptionPolicy == corev1.PreemptNever {
+		return plan, nil
+	}
+
+	if schedulingUnit.SchedulingMode != fedcorev1a1.SchedulingModeDivide {
+		return plan, nil
+	}
+
+	request, err := getReplicaResourceRequest(s.typeConfig, fedObject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource request: %w", err)
+	}
+	if request == nil || replicasThatFit(&framework.Resource{}, request) == math.MaxInt64 {
+		// we cannot tell whether the replicas fit without a resource request
+		return plan, nil
+	}
+
+	clusterMap := make(map[string]*fedcorev1a1.FederatedCluster, len(clusters))
+	for _, cluster := range clusters {
+		clusterMap[cluster.Name] = cluster
+	}
+
+	if placementFitsWithoutPreemption(schedulingUnit, result, clusterMap, request, plan.released) {
+		keyedLogger.V(3).Info("Feasible placement without preemption exists, skipping preemption")
+		return plan, nil
+	}
+
+	clusterNames := make([]string, 0, len(result.SuggestedClusters))
+	for cluster := range result.SuggestedClusters {
+		clusterNames = append(clusterNames, cluster)
+	}
+	sort.Strings(clusterNames)
+
+	for _, clusterName := range clusterNames {
+		replicas, cluster := result.SuggestedClusters[clusterName], clusterMap[clusterName]
+		if replicas == nil || cluster == nil {
+			continue
+		}
+
+		additionalReplicas := *replicas
+		if current := schedulingUnit.CurrentClusters[clusterName]; current != nil {
+			additionalReplicas -= *current
+		}
+		if additionalReplicas <= 0 {
+			continue
+		}
+
+		free := framework.NewResource(cluster.Status.Resources.Available)
+		if plan.candidates == nil {
+			if plan.candidates, err = s.preemptionCandidates(fedObject, priority); err != nil {
+				return nil, err
+			}
+		}
+
+		// replicas preempted earlier that are still scheduled to the cluster will be released soon
+		if pending := plan.released[clusterName]; pending != nil {
+			addResource(free, pending, 1)
+		}
+
+		fit := replicasThatFit(free, request)
+		if fit >= additionalReplicas {
+			continue
+		}
+
+		preempted := make(map[*preemptionCandidate]int64)
+		freed := free.Clone()
+		for _, candidate := range plan.candidates {
+			for replicasThatFit(freed, request) < additionalReplicas &&
+				preempted[candidate] < candidate.budget &&
+				preempted[candidate] < candidate.availableReplicas(clusterName) {
+				addResource(freed, candidate.request, 1)
+				preempted[candidate]++
+			}
+		}
+
+		if replicasThatFit(freed, request) <= fit {
+			keyedLogger.V(2).Info("Unable to free resources by preemption", "cluster", clusterName)
+			continue
+		}
+
+		for _, candidate := range plan.candidates {
+			replicas := preempted[candidate]
+			if replicas == 0 {
+				continue
+			}
+
+			candidate.preempt(preemptor, clusterName, replicas)
+			plan.planned = append(plan.planned, plannedPreemption{
+				candidate: candidate,
+				cluster:   clusterName,
+				replicas:  replicas,
+			})
+			if plan.released[clusterName] == nil {
+				plan.released[clusterName] = &framework.Resource{}
+			}
+			addResource(plan.released[clusterName], candidate.request, replicas)
+		}
+	}
+
+	return plan, nil
+}
+
+// applyPreemptions persists the preemptions planned by planPreemptions in the preempted federated objects. The
+// preemptions of the preemptor are written to the latest version of each preempted object as absolute values rather
+// than increments, so that retrying after a partial failure, or with a plan computed from a stale cache, does not
+// preempt the same replicas twice. Events are only recorded for the objects that were updated.
+func (s *Scheduler) applyPreemptions(
+	ctx context.Context,
+	fedObject *unstructured.Unstructured,
+	plan *preemptionPlan,
+) error {
+	if plan == nil || len(plan.planned) == 0 {
+		return nil
+	}
+
+	keyedLogger := klog.FromContext(ctx)
+
+	for _, candidate := range plan.candidates {
+		if !candidate.modified {
+			continue
+		}
+
+		victim, err := s.federatedObjectClient.Namespace(candidate.fedObject.GetNamespace()).Get(
+			ctx, candidate.fedObject.GetName(), metav1.GetOptions{},
+		)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get preempted object: %w", err)
+		}
+
+		preemptions, err := getPreemptions(victim)
+		if err != nil {
+			return err
+		}
+		updated := mergePreemptions(preemptions, plan.preemptionsOf(candidate))
+		if equality.Semantic.DeepEqual(preemptions, updated) {
+			continue
+		}
+		if err := setPreemptions(victim, updated); err != nil {
+			return err
+		}
+
+		keyedLogger.V(1).Info("Preempting federated object", "victim", common.NewQualifiedName(victim).String())
+		if _, err := s.federatedObjectClient.Namespace(victim.GetNamespace()).Update(
+			ctx, victim, metav1.UpdateOptions{},
+		); err != nil {
+			return fmt.Errorf("failed to update preempted object: %w", err)
+		}
+
+		for _, p := range plan.planned {
+			if p.candidate != candidate {
+				continue
+			}
+			s.eventRecorder.Eventf(
+				fedObject,
+				corev1.EventTypeNormal,
+				EventReasonPreemptFederatedObject,
+				"preempting %d replicas of %s in cluster %s",
+				p.replicas,
+				common.NewQualifiedName(victim).String(),
+				p.cluster,
+			)
+			s.eventRecorder.Eventf(
+				victim,
+				corev1.EventTypeNormal,
+				EventReasonPreemptFederatedObject,
+				"%d replicas in cluster %s preempted by %s",
+				p.replicas,
+				p.cluster,
+				plan.preemptor,
+			)
+		}
+	}
+
+	return nil
+}
+
+// preemptionsOf returns the preemptions of the candidate by the preemptor in the clusters where the plan preempts its
+// replicas.
+func (p *preemptionPlan) preemptionsOf(candidate *preemptionCandidate) []preemption {
+	clusters := sets.New[string]()
+	for _, planned := range p.planned {
+		if planned.candidate == candidate {
+			clusters.Insert(planned.cluster)
+		}
+	}
+
+	preemptions := []preemption{}
+	for _, preemption := range candidate.preemptions {
+		if preemption.Preemptor == p.preemptor && clusters.Has(preemption.Cluster) {
+			preemptions = append(preemptions, preemption)
+		}
+	}
+	return preemptions
+}
+
+// mergePreemptions returns the current preemptions of a federated object with the entries for the same preemptor and
+// cluster replaced by the planned ones.
+func mergePreemptions(current, planned []preemption) []preemption {
+	merged := make([]preemption, 0, len(current)+len(planned))
+	merged = append(merged, planned...)
+
+	for _, p := range current {
+		found := false
+		for _, m := range merged {
+			if m.Preemptor == p.Preemptor && m.Cluster == p.Cluster {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, p)
+		}
+	}
+
+	sortPreemptions(merged)
+	return merged
+}
+
+// preemptionCandidates returns the Divide mode federated objects with priorities lower than the given priority in the
+// order of preference for preemption: objects with lower priorities first, then newer objects first. Only objects of
+// the same federated type as the preemptor are returned, since the replica resource requests and the preemptions of
+// other types are managed by the schedulers of their FederatedTypeConfigs.
+func (s *Scheduler) preemptionCandidates(
+	preemptor *unstructured.Unstructured,
+	priority int32,
+) ([]*preemptionCandidate, error) {
+	fedObjects, err := s.federatedObjectLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []*preemptionCandidate{}
+	for _, fedObject := range fedObjects {
+		fedObject := fedObject.(*unstructured.Unstructured)
+		if fedObject.GetDeletionTimestamp() != nil ||
+			fedObject.GroupVersionKind().GroupKind() != preemptor.GroupVersionKind().GroupKind() ||
+			(fedObject.GetNamespace() == preemptor.GetNamespace() && fedObject.GetName() == preemptor.GetName()) {
+			continue
+		}
+
+		policy, err := s.matchedPolicyFromStore(fedObject)
+		if err != nil || policy == nil {
+			continue
+		}
+
+		candidatePriority, _, err := s.priorityForPolicy(policy)
+		if err != nil || candidatePriority >= priority {
+			continue
+		}
+
+		schedulingUnit, err := schedulingUnitForFedObject(s.typeConfig, fedObject, policy)
+		if err != nil || schedulingUnit.SchedulingMode != fedcorev1a1.SchedulingModeDivide ||
+			schedulingUnit.DesiredReplicas == nil {
+			continue
+		}
+
+		request, err := getReplicaResourceRequest(s.typeConfig, fedObject)
+		if err != nil || request == nil {
+			continue
+		}
+
+		preemptions, err := getPreemptions(fedObject)
+		if err != nil {
+			continue
+		}
+
+		budget := int64(math.MaxInt64)
+		if disruptionBudget := policy.GetSpec().DisruptionBudget; disruptionBudget != nil &&
+			disruptionBudget.MaxPreemptedReplicas != nil {
+			maxPreempted, err := intstr.GetScaledValueFromIntOrPercent(
+				disruptionBudget.MaxPreemptedReplicas,
+				int(*schedulingUnit.DesiredReplicas),
+				false,
+			)
+			if err != nil {
+				continue
+			}
+			budget = int64(maxPreempted)
+			for _, p := range preemptions {
+				budget -= p.Replicas
+			}
+		}
+		if budget <= 0 {
+			continue
+		}
+
+		candidates = append(candidates, &preemptionCandidate{
+			fedObject:       fedObject.DeepCopy(),
+			priority:        candidatePriority,
+			request:         request,
+			currentClusters: schedulingUnit.CurrentClusters,
+			preemptions:     preemptions,
+			budget:          budget,
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].priority != candidates[j].priority {
+			return candidates[i].priority < candidates[j].priority
+		}
+		return candidates[i].fedObject.GetCreationTimestamp().Time.After(candidates[j].fedObject.GetCreationTimestamp().Time)
+	})
+
+	return candidates, nil
+}
+
+// releasePreemptions removes the preemptions made by the given preemptor in clusters other than the given clusters,
+// allowing the preempted objects to be rescheduled to these clusters.
+func (s *Scheduler) releasePreemptions(
+	ctx context.Context,
+	preemptor common.QualifiedName,
+	clusters map[string]struct{},
+) error {
+	keyedLogger := klog.FromContext(ctx)
+
+	for _, victim := range s.federatedObjectsByPreemptor.Get(preemptor.String()) {
+		fedObject, err := s.federatedObjectFromStore(victim)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+
+		preemptions, err := getPreemptions(fedObject)
+		if err != nil {
+			continue
+		}
+
+		remaining := make([]preemption, 0, len(preemptions))
+		for _, p := range preemptions {
+			if _, exists := clusters[p.Cluster]; p.Preemptor != preemptor.String() || exists {
+				remaining = append(remaining, p)
+			}
+		}
+		if len(remaining) == len(preemptions) {
+			continue
+		}
+
+		fedObject = fedObject.DeepCopy()
+		if err := setPreemptions(fedObject, remaining); err != nil {
+			return err
+		}
+
+		keyedLogger.V(1).Info("Releasing preempted replicas", "victim", victim.String())
+		if _, err := s.federatedObjectClient.Namespace(fedObject.GetNamespace()).Update(
+			ctx, fedObject, metav1.UpdateOptions{},
+		); err != nil {
+			return fmt.Errorf("failed to update preempted object: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// pendingReleaseResources returns the resources in each cluster that are used by replicas preempted by the given
+// preemptor which are still scheduled to the cluster and will be released soon.
+func (s *Scheduler) pendingReleaseResources(preemptor string) map[string]*framework.Resource {
+	pending := map[string]*framework.Resource{}
+
+	for _, victim := range s.federatedObjectsByPreemptor.Get(preemptor) {
+		fedObject, err := s.federatedObjectFromStore(victim)
+		if err != nil || fedObject.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		policy, err := s.matchedPolicyFromStore(fedObject)
+		if err != nil || policy == nil {
+			continue
+		}
+
+		schedulingUnit, err := schedulingUnitForFedObject(s.typeConfig, fedObject, policy)
+		if err != nil {
+			continue
+		}
+
+		request, err := getReplicaResourceRequest(s.typeConfig, fedObject)
+		if err != nil || request == nil {
+			continue
+		}
+
+		preemptions, err := getPreemptions(fedObject)
+		if err != nil {
+			continue
+		}
+
+		victimState := &preemptionCandidate{currentClusters: schedulingUnit.CurrentClusters, preemptions: preemptions}
+		for _, p := range preemptions {
+			if p.Preemptor != preemptor {
+				continue
+			}
+			if replicas := victimState.pendingReplicas(preemptor, p.Cluster); replicas > 0 {
+				if pending[p.Cluster] == nil {
+					pending[p.Cluster] = &framework.Resource{}
+				}
+				addResource(pending[p.Cluster], request, replicas)
+			}
+		}
+	}
+
+	return pending
+}
+
+// placementFitsWithoutPreemption returns whether the desired replicas of the scheduling unit fit in the feasible
+// clusters of the scheduling result without preempting other federated objects. The replicas already scheduled to a
+// cluster and the resources pending release are counted towards its capacity.
+func placementFitsWithoutPreemption(
+	schedulingUnit *framework.SchedulingUnit,
+	result *core.ScheduleResult,
+	clusters map[string]*fedcorev1a1.FederatedCluster,
+	request *framework.Resource,
+	pendingRelease map[string]*framework.Resource,
+) bool {
+	if schedulingUnit.DesiredReplicas == nil {
+		return true
+	}
+
+	fit := int64(0)
+	for _, clusterName := range result.FeasibleClusters {
+		cluster := clusters[clusterName]
+		if cluster == nil {
+			continue
+		}
+
+		free := framework.NewResource(cluster.Status.Resources.Available)
+		if pending := pendingRelease[clusterName]; pending != nil {
+			addResource(free, pending, 1)
+		}
+
+		clusterFit := replicasThatFit(free, request)
+		if clusterFit == math.MaxInt64 {
+			return true
+		}
+		if current := schedulingUnit.CurrentClusters[clusterName]; current != nil {
+			clusterFit += *current
+		}
+
+		if fit += clusterFit; fit >= *schedulingUnit.DesiredReplicas {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/core"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+)
+
+func TestReplicasThatFit(t *testing.T) {
+	testCases := map[string]struct {
+		free     framework.Resource
+		request  framework.Resource
+		expected int64
+	}{
+		"empty request fits unbounded replicas": {
+			free:     framework.Resource{MilliCPU: 1000},
+			request:  framework.Resource{},
+			expected: math.MaxInt64,
+		},
+		"fit is bounded by cpu": {
+			free:     framework.Resource{MilliCPU: 1000, Memory: 1000},
+			request:  framework.Resource{MilliCPU: 300, Memory: 100},
+			expected: 3,
+		},
+		"fit is bounded by memory": {
+			free:     framework.Resource{MilliCPU: 1000, Memory: 150},
+			request:  framework.Resource{MilliCPU: 300, Memory: 100},
+			expected: 1,
+		},
+		"fit is bounded by scalar resources": {
+			free: framework.Resource{
+				MilliCPU:        1000,
+				ScalarResources: map[corev1.ResourceName]int64{"nvidia.com/gpu": 1},
+			},
+			request: framework.Resource{
+				MilliCPU:        100,
+				ScalarResources: map[corev1.ResourceName]int64{"nvidia.com/gpu": 2},
+			},
+			expected: 0,
+		},
+		"negative free resources fit no replicas": {
+			free:     framework.Resource{MilliCPU: -100},
+			request:  framework.Resource{MilliCPU: 100},
+			expected: 0,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			g.Expect(replicasThatFit(&tc.free, &tc.request)).To(gomega.Equal(tc.expected))
+		})
+	}
+}
+
+func TestPreemptionCandidate(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	candidate := &preemptionCandidate{
+		currentClusters: map[string]*int64{
+			"cluster1": pointer.Int64(5),
+			"cluster2": pointer.Int64(3),
+		},
+		budget: 4,
+	}
+
+	g.Expect(candidate.availableReplicas("cluster1")).To(gomega.Equal(int64(5)))
+	g.Expect(candidate.availableReplicas("cluster3")).To(gomega.Equal(int64(0)))
+
+	candidate.preempt("default/high", "cluster1", 2)
+	g.Expect(candidate.budget).To(gomega.Equal(int64(2)))
+	g.Expect(candidate.availableReplicas("cluster1")).To(gomega.Equal(int64(3)))
+	g.Expect(candidate.pendingReplicas("default/high", "cluster1")).To(gomega.Equal(int64(2)))
+	g.Expect(candidate.pendingReplicas("default/other", "cluster1")).To(gomega.Equal(int64(0)))
+
+	// preempting again from the same preemptor and cluster updates the existing preemption
+	candidate.preempt("default/high", "cluster1", 1)
+	g.Expect(candidate.preemptions).To(gomega.Equal([]preemption{
+		{Preemptor: "default/high", Cluster: "cluster1", Replicas: 3, MaxReplicas: 2},
+	}))
+
+	fedObject := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	g.Expect(setPreemptions(fedObject, candidate.preemptions)).To(gomega.Succeed())
+	g.Expect(getMaxReplicasFromPreemptions(fedObject)).To(gomega.Equal(map[string]int64{"cluster1": 2}))
+
+	g.Expect(setPreemptions(fedObject, nil)).To(gomega.Succeed())
+	g.Expect(fedObject.GetAnnotations()).NotTo(gomega.HaveKey(PreemptionsAnnotation))
+}
+
+func TestApplyPreemptionsIsIdempotent(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	gvr := schema.GroupVersionResource{Group: "types.kubeadmiral.io", Version: "v1alpha1", Resource: "federateddeployments"}
+	victim := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	victim.SetAPIVersion("types.kubeadmiral.io/v1alpha1")
+	victim.SetKind("FederatedDeployment")
+	victim.SetNamespace("default")
+	victim.SetName("low")
+	g.Expect(setPreemptions(victim, []preemption{
+		{Preemptor: "default/other", Cluster: "cluster1", Replicas: 1, MaxReplicas: 4},
+		{Preemptor: "default/high", Cluster: "cluster2", Replicas: 1, MaxReplicas: 2},
+	})).To(gomega.Succeed())
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{gvr: "FederatedDeploymentList"},
+		victim.DeepCopy(),
+	)
+	recorder := record.NewFakeRecorder(10)
+	s := &Scheduler{federatedObjectClient: client.Resource(gvr), eventRecorder: recorder}
+
+	preemptor := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	preemptor.SetNamespace("default")
+	preemptor.SetName("high")
+
+	// newPlan plans the preemption of 2 replicas in cluster1 from the cached state of the victim, which does not
+	// include the preemption once it has been applied
+	newPlan := func() *preemptionPlan {
+		candidate := &preemptionCandidate{
+			fedObject:       victim.DeepCopy(),
+			currentClusters: map[string]*int64{"cluster1": pointer.Int64(5), "cluster2": pointer.Int64(3)},
+			budget:          math.MaxInt64,
+		}
+		candidate.preemptions, _ = getPreemptions(victim)
+		candidate.preempt("default/high", "cluster1", 2)
+		return &preemptionPlan{
+			preemptor:  "default/high",
+			candidates: []*preemptionCandidate{candidate},
+			planned:    []plannedPreemption{{candidate: candidate, cluster: "cluster1", replicas: 2}},
+		}
+	}
+
+	expected := []preemption{
+		{Preemptor: "default/high", Cluster: "cluster1", Replicas: 2, MaxReplicas: 2},
+		{Preemptor: "default/high", Cluster: "cluster2", Replicas: 1, MaxReplicas: 2},
+		{Preemptor: "default/other", Cluster: "cluster1", Replicas: 1, MaxReplicas: 4},
+	}
+
+	for i := 0; i < 2; i++ {
+		g.Expect(s.applyPreemptions(context.TODO(), preemptor, newPlan())).To(gomega.Succeed())
+
+		updated, err := s.federatedObjectClient.Namespace("default").Get(context.TODO(), "low", metav1.GetOptions{})
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		g.Expect(getPreemptions(updated)).To(gomega.Equal(expected))
+	}
+
+	// events are only recorded when the preemption is applied for the first time
+	g.Expect(recorder.Events).To(gomega.HaveLen(2))
+}
+
+func TestPreemptorsOf(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	fedObject := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	g.Expect(preemptorsOf(fedObject)).To(gomega.BeEmpty())
+
+	g.Expect(setPreemptions(fedObject, []preemption{
+		{Preemptor: "default/b", Cluster: "cluster1", Replicas: 1},
+		{Preemptor: "default/a", Cluster: "cluster1", Replicas: 1},
+		{Preemptor: "default/a", Cluster: "cluster2", Replicas: 1},
+	})).To(gomega.Succeed())
+	g.Expect(preemptorsOf(fedObject)).To(gomega.Equal([]string{"default/a", "default/b"}))
+}
+
+func TestPlacementFitsWithoutPreemption(t *testing.T) {
+	newCluster := func(name string, availableCPU string) *fedcorev1a1.FederatedCluster {
+		return &fedcorev1a1.FederatedCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: fedcorev1a1.FederatedClusterStatus{
+				Resources: fedcorev1a1.Resources{
+					Available: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(availableCPU)},
+				},
+			},
+		}
+	}
+	clusters := map[string]*fedcorev1a1.FederatedCluster{
+		"cluster1": newCluster("cluster1", "2"),
+		"cluster2": newCluster("cluster2", "3"),
+		"cluster3": newCluster("cluster3", "10"),
+	}
+	request := &framework.Resource{MilliCPU: 1000}
+
+	testCases := map[string]struct {
+		desiredReplicas  int64
+		currentClusters  map[string]*int64
+		feasibleClusters []string
+		pendingRelease   map[string]*framework.Resource
+		expected         bool
+	}{
+		"replicas fit in the free resources of feasible clusters": {
+			desiredReplicas:  5,
+			feasibleClusters: []string{"cluster1", "cluster2"},
+			expected:         true,
+		},
+		"replicas do not fit in the free resources of feasible clusters": {
+			desiredReplicas:  6,
+			feasibleClusters: []string{"cluster1", "cluster2"},
+			expected:         false,
+		},
+		"infeasible clusters are not counted": {
+			desiredReplicas:  6,
+			feasibleClusters: []string{"cluster1"},
+			expected:         false,
+		},
+		"current replicas are counted": {
+			desiredReplicas:  6,
+			currentClusters:  map[string]*int64{"cluster1": pointer.Int64(1)},
+			feasibleClusters: []string{"cluster1", "cluster2"},
+			expected:         true,
+		},
+		"resources pending release are counted": {
+			desiredReplicas:  6,
+			feasibleClusters: []string{"cluster1", "cluster2"},
+			pendingRelease:   map[string]*framework.Resource{"cluster2": {MilliCPU: 1000}},
+			expected:         true,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			su := &framework.SchedulingUnit{
+				DesiredReplicas: pointer.Int64(tc.desiredReplicas),
+				CurrentClusters: tc.currentClusters,
+			}
+			result := &core.ScheduleResult{FeasibleClusters: tc.feasibleClusters}
+			g.Expect(placementFitsWithoutPreemption(su, result, clusters, request, tc.pendingRelease)).
+				To(gomega.Equal(tc.expected))
+		})
+	}
+}
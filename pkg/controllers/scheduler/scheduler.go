@@ -20,6 +20,7 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"sync"
@@ -33,19 +34,24 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
 	dynamicclient "k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
+	schedulingv1informers "k8s.io/client-go/informers/scheduling/v1"
 	kubeclient "k8s.io/client-go/kubernetes"
+	schedulingv1listers "k8s.io/client-go/listers/scheduling/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
 
 	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
 	fedclient "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned"
 	fedcorev1a1informers "github.com/kubewharf/kubeadmiral/pkg/client/informers/externalversions/core/v1alpha1"
 	fedcorev1a1listers "github.com/kubewharf/kubeadmiral/pkg/client/listers/core/v1alpha1"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/core"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
 	annotationutil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/annotation"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/delayingdeliver"
@@ -61,9 +67,20 @@ type ClusterWeight struct {
 	Weight  int64
 }
 
+// Config contains the settings of the scheduler that are not reloaded at runtime.
+type Config struct {
+	// SupplyLimitProportion limits the share of replicas that the ClusterCapacityWeight plugin assigns to a cluster.
+	// The default of the plugin is used if it is 0.
+	SupplyLimitProportion float64
+	// WebhookTimeout is the timeout of requests to webhook plugins whose configuration does not set one. Defaults to
+	// 5 seconds if it is 0.
+	WebhookTimeout time.Duration
+}
+
 type Scheduler struct {
 	typeConfig *fedcorev1a1.FederatedTypeConfig
 	name       string
+	config     Config
 
 	fedClient     fedclient.Interface
 	dynamicClient dynamicclient.Interface
@@ -83,6 +100,27 @@ type Scheduler struct {
 	schedulingProfileLister fedcorev1a1listers.SchedulingProfileLister
 	schedulingProfileSynced cache.InformerSynced
 
+	typeConfigLister fedcorev1a1listers.FederatedTypeConfigLister
+	typeConfigSynced cache.InformerSynced
+
+	priorityClassLister schedulingv1listers.PriorityClassLister
+	priorityClassSynced cache.InformerSynced
+
+	// federatedObjectsByPolicy indexes federated objects by the key of their matched policy
+	federatedObjectsByPolicy *objectIndex
+	// federatedObjectsByPreemptor indexes federated objects by the qualified names of the objects that preempted them
+	federatedObjectsByPreemptor *objectIndex
+
+	// used to look up the placements of objects referenced by workload affinity terms, the informers are started by
+	// Run whenever workloadAffinityInformerAdded is signaled. The factory is shared with the schedulers of other
+	// FederatedTypeConfigs and is not limited to the shard of the controller manager, since affinity must see the
+	// placements of all objects.
+	workloadAffinityInformerFactory dynamicinformer.DynamicSharedInformerFactory
+	workloadAffinityInformerAdded   chan struct{}
+	// workloadAffinityHandlers maps the GVRs of the informers used for workload affinity to the registrations of the
+	// event handlers of the scheduler, which are removed when the scheduler stops.
+	workloadAffinityHandlers sync.Map
+
 	webhookConfigurationSynced cache.InformerSynced
 	webhookPlugins             sync.Map
 
@@ -106,31 +144,37 @@ func NewScheduler(
 	fedClient fedclient.Interface,
 	dynamicClient dynamicclient.Interface,
 	federatedObjectInformer informers.GenericInformer,
+	workloadAffinityInformerFactory dynamicinformer.DynamicSharedInformerFactory,
 	propagationPolicyInformer fedcorev1a1informers.PropagationPolicyInformer,
 	clusterPropagationPolicyInformer fedcorev1a1informers.ClusterPropagationPolicyInformer,
 	clusterInformer fedcorev1a1informers.FederatedClusterInformer,
 	schedulingProfileInformer fedcorev1a1informers.SchedulingProfileInformer,
 	webhookConfigurationInformer fedcorev1a1informers.SchedulerPluginWebhookConfigurationInformer,
+	typeConfigInformer fedcorev1a1informers.FederatedTypeConfigInformer,
+	priorityClassInformer schedulingv1informers.PriorityClassInformer,
 	metrics stats.Metrics,
-	workerCount int,
+	workerConfig worker.ConfigSource,
+	config Config,
 ) (*Scheduler, error) {
 	schedulerName := fmt.Sprintf("%s-scheduler", typeConfig.GetFederatedType().Name)
 
 	s := &Scheduler{
 		typeConfig:    typeConfig,
 		name:          schedulerName,
+		config:        config,
 		fedClient:     fedClient,
 		dynamicClient: dynamicClient,
 		metrics:       metrics,
 		logger:        logger.WithValues("controller", GlobalSchedulerName, "ftc", typeConfig.Name),
 	}
 
-	s.worker = worker.NewReconcileWorker(
+	s.worker = worker.NewConfigurablePriorityReconcileWorker(
 		s.reconcile,
 		worker.WorkerTiming{},
-		workerCount,
+		workerConfig,
 		metrics,
 		delayingdeliver.NewMetricTags("scheduler-worker", s.typeConfig.GetFederatedType().Kind),
+		s.priorityForObject,
 	)
 	s.eventRecorder = eventsink.NewDefederatingRecorderMux(kubeClient, s.name, 6)
 
@@ -141,6 +185,17 @@ func NewScheduler(
 	s.federatedObjectSynced = federatedObjectInformer.Informer().HasSynced
 	federatedObjectInformer.Informer().AddEventHandler(util.NewTriggerOnAllChanges(s.worker.EnqueueObject))
 
+	s.federatedObjectsByPolicy = newObjectIndex(func(fedObject *unstructured.Unstructured) []string {
+		if policyKey, found := MatchedPolicyKey(fedObject, s.typeConfig.GetNamespaced()); found {
+			return []string{policyKey.String()}
+		}
+		return nil
+	})
+	federatedObjectInformer.Informer().AddEventHandler(s.federatedObjectsByPolicy.EventHandler())
+
+	s.federatedObjectsByPreemptor = newObjectIndex(preemptorsOf)
+	federatedObjectInformer.Informer().AddEventHandler(s.federatedObjectsByPreemptor.EventHandler())
+
 	// only required if namespaced
 	if s.typeConfig.GetNamespaced() {
 		s.propagationPolicyLister = propagationPolicyInformer.Lister()
@@ -170,6 +225,7 @@ func NewScheduler(
 			oldCluster, newCluster := oldUntyped.(*fedcorev1a1.FederatedCluster), newUntyped.(*fedcorev1a1.FederatedCluster)
 			if !equality.Semantic.DeepEqual(oldCluster.Labels, newCluster.Labels) ||
 				!equality.Semantic.DeepEqual(oldCluster.Spec.Taints, newCluster.Spec.Taints) ||
+				!equality.Semantic.DeepEqual(oldCluster.Spec.Maintenance, newCluster.Spec.Maintenance) ||
 				!equality.Semantic.DeepEqual(oldCluster.Status.APIResourceTypes, newCluster.Status.APIResourceTypes) {
 				s.enqueueFederatedObjectsForCluster(newCluster)
 			}
@@ -179,6 +235,14 @@ func NewScheduler(
 	s.schedulingProfileLister = schedulingProfileInformer.Lister()
 	s.schedulingProfileSynced = schedulingProfileInformer.Informer().HasSynced
 
+	s.typeConfigLister = typeConfigInformer.Lister()
+	s.typeConfigSynced = typeConfigInformer.Informer().HasSynced
+	s.workloadAffinityInformerFactory = workloadAffinityInformerFactory
+	s.workloadAffinityInformerAdded = make(chan struct{}, 1)
+
+	s.priorityClassLister = priorityClassInformer.Lister()
+	s.priorityClassSynced = priorityClassInformer.Informer().HasSynced
+
 	s.webhookConfigurationSynced = webhookConfigurationInformer.Informer().HasSynced
 	webhookConfigurationInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
@@ -217,6 +281,8 @@ func (s *Scheduler) HasSynced() bool {
 		s.clusterSynced,
 		s.schedulingProfileSynced,
 		s.webhookConfigurationSynced,
+		s.typeConfigSynced,
+		s.priorityClassSynced,
 	}
 	if s.typeConfig.GetNamespaced() {
 		cachesSynced = append(cachesSynced, s.propagationPolicySynced)
@@ -239,6 +305,8 @@ func (s *Scheduler) Run(ctx context.Context) {
 		return
 	}
 
+	go s.runWorkloadAffinityInformers(ctx)
+
 	s.worker.Run(ctx.Done())
 	<-ctx.Done()
 }
@@ -262,12 +330,16 @@ func (s *Scheduler) reconcile(qualifiedName common.QualifiedName) (status worker
 	}
 	if apierrors.IsNotFound(err) || fedObject.GetDeletionTimestamp() != nil {
 		keyedLogger.V(3).Info("Observed object deletion")
+		if err := s.releasePreemptions(ctx, qualifiedName, nil); err != nil {
+			keyedLogger.Error(err, "Failed to release preempted replicas")
+			return worker.StatusError
+		}
 		return worker.StatusAllOK
 	}
 
 	fedObject = fedObject.DeepCopy()
 
-	policy, clusters, schedulingProfile, earlyReturnResult := s.prepareToSchedule(ctx, fedObject)
+	policy, clusters, schedulingProfile, workloadAffinity, earlyReturnResult := s.prepareToSchedule(ctx, fedObject)
 	if earlyReturnResult != nil {
 		return *earlyReturnResult
 	}
@@ -280,7 +352,14 @@ func (s *Scheduler) reconcile(qualifiedName common.QualifiedName) (status worker
 	}
 
 	ctx = klog.NewContext(ctx, keyedLogger)
-	result, earlyReturnWorkerResult := s.schedule(ctx, fedObject, policy, schedulingProfile, clusters)
+	schedulingUnit, result, earlyReturnWorkerResult := s.schedule(
+		ctx,
+		fedObject,
+		policy,
+		schedulingProfile,
+		workloadAffinity,
+		clusters,
+	)
 	if earlyReturnWorkerResult != nil {
 		return *earlyReturnWorkerResult
 	}
@@ -288,13 +367,64 @@ func (s *Scheduler) reconcile(qualifiedName common.QualifiedName) (status worker
 	keyedLogger = keyedLogger.WithValues("result", result.String())
 	keyedLogger.V(2).Info("Scheduling result obtained")
 
+	var preemptions *preemptionPlan
+	if policy != nil {
+		var err error
+		if preemptions, err = s.planPreemptions(ctx, fedObject, policy, schedulingUnit, result, clusters); err != nil {
+			keyedLogger.Error(err, "Failed to plan preemption of federated objects with lower priorities")
+			s.eventRecorder.Eventf(
+				fedObject,
+				corev1.EventTypeWarning,
+				EventReasonPreemptFederatedObject,
+				"failed to preempt federated objects with lower priorities: %v",
+				err,
+			)
+			return worker.StatusError
+		}
+	}
+
 	auxInfo := &auxiliarySchedulingInformation{
 		enableFollowerScheduling: false,
 		unschedulableThreshold:   nil,
+		gangScheduling:           false,
 	}
 	if policy != nil {
 		spec := policy.GetSpec()
 
+		if minAvailable := getGangMinAvailable(policy, schedulingUnit); minAvailable != nil {
+			auxInfo.gangScheduling = true
+			schedulable, err := s.schedulableReplicas(
+				fedObject,
+				schedulingUnit,
+				result,
+				clusters,
+				preemptions.releasedResources(),
+			)
+			if err != nil {
+				keyedLogger.Error(err, "Failed to compute schedulable replicas")
+				return worker.StatusError
+			}
+
+			keyedLogger = keyedLogger.WithValues("minAvailable", *minAvailable, "schedulableReplicas", schedulable)
+			if schedulable < *minAvailable {
+				lostCapacity, err := s.gangLostCapacity(
+					fedObject,
+					schedulingUnit,
+					result,
+					clusters,
+					preemptions.releasedResources(),
+					*minAvailable,
+				)
+				if err != nil {
+					keyedLogger.Error(err, "Failed to compute schedulable replicas")
+					return worker.StatusError
+				}
+
+				ctx = klog.NewContext(ctx, keyedLogger)
+				return s.keepGangPending(ctx, fedObject, *minAvailable, schedulable, lostCapacity)
+			}
+		}
+
 		auxInfo.enableFollowerScheduling = !spec.DisableFollowerScheduling
 		keyedLogger = keyedLogger.WithValues("enableFollowerScheduling", auxInfo.enableFollowerScheduling)
 
@@ -302,6 +432,26 @@ func (s *Scheduler) reconcile(qualifiedName common.QualifiedName) (status worker
 			auxInfo.unschedulableThreshold = pointer.Duration(autoMigration.Trigger.PodUnschedulableDuration.Duration)
 			keyedLogger = keyedLogger.WithValues("unschedulableThreshold", auxInfo.unschedulableThreshold.String())
 		}
+
+		auxInfo.driftPolicy = spec.DriftPolicy
+	}
+
+	// Preemptions are only applied once the object can be admitted, so that objects kept pending by gang scheduling do
+	// not evict replicas of other objects.
+	if err := s.releasePreemptions(ctx, qualifiedName, result.ClusterSet()); err != nil {
+		keyedLogger.Error(err, "Failed to release preempted replicas")
+		return worker.StatusError
+	}
+	if err := s.applyPreemptions(ctx, fedObject, preemptions); err != nil {
+		keyedLogger.Error(err, "Failed to preempt federated objects with lower priorities")
+		s.eventRecorder.Eventf(
+			fedObject,
+			corev1.EventTypeWarning,
+			EventReasonPreemptFederatedObject,
+			"failed to preempt federated objects with lower priorities: %v",
+			err,
+		)
+		return worker.StatusError
 	}
 
 	ctx = klog.NewContext(ctx, keyedLogger)
@@ -315,6 +465,7 @@ func (s *Scheduler) prepareToSchedule(
 	fedcorev1a1.GenericPropagationPolicy,
 	[]*fedcorev1a1.FederatedCluster,
 	*fedcorev1a1.SchedulingProfile,
+	*framework.WorkloadAffinity,
 	*worker.Result,
 ) {
 	keyedLogger := klog.FromContext(ctx)
@@ -323,10 +474,10 @@ func (s *Scheduler) prepareToSchedule(
 
 	if ok, err := pendingcontrollers.ControllerDependenciesFulfilled(fedObject, PrefixedGlobalSchedulerName); err != nil {
 		keyedLogger.Error(err, "Failed to check controller dependencies")
-		return nil, nil, nil, &worker.StatusError
+		return nil, nil, nil, nil, &worker.StatusError
 	} else if !ok {
 		keyedLogger.V(3).Info("Controller dependencies not fulfilled")
-		return nil, nil, nil, &worker.StatusAllOK
+		return nil, nil, nil, nil, &worker.StatusAllOK
 	}
 
 	// check whether to skip scheduling
@@ -334,7 +485,7 @@ func (s *Scheduler) prepareToSchedule(
 	allClusters, err := s.clusterLister.List(labels.Everything())
 	if err != nil {
 		keyedLogger.Error(err, "Failed to get clusters from store")
-		return nil, nil, nil, &worker.StatusError
+		return nil, nil, nil, nil, &worker.StatusError
 	}
 	clusters := make([]*fedcorev1a1.FederatedCluster, 0)
 	for _, cluster := range allClusters {
@@ -363,9 +514,9 @@ func (s *Scheduler) prepareToSchedule(
 					"object propagation policy %s not found",
 					policyKey.String(),
 				)
-				return nil, nil, nil, &worker.StatusAllOK
+				return nil, nil, nil, nil, &worker.StatusAllOK
 			}
-			return nil, nil, nil, &worker.StatusError
+			return nil, nil, nil, nil, &worker.StatusError
 		}
 
 		profileName := policy.GetSpec().SchedulingProfile
@@ -383,28 +534,55 @@ func (s *Scheduler) prepareToSchedule(
 				)
 
 				if apierrors.IsNotFound(err) {
-					return nil, nil, nil, &worker.StatusAllOK
+					return nil, nil, nil, nil, &worker.StatusAllOK
 				}
 
-				return nil, nil, nil, &worker.StatusError
+				return nil, nil, nil, nil, &worker.StatusError
 			}
 		}
 	}
 
-	triggerHash, err := s.computeSchedulingTriggerHash(fedObject, policy, clusters)
+	workloadAffinity, err := s.resolveWorkloadAffinity(fedObject, policy)
+	if err != nil {
+		keyedLogger.Error(err, "Failed to resolve workload affinity")
+		s.eventRecorder.Eventf(
+			fedObject,
+			corev1.EventTypeWarning,
+			EventReasonScheduleFederatedObject,
+			"failed to schedule object: %v",
+			fmt.Errorf("failed to resolve workload affinity: %w", err),
+		)
+		if errors.Is(err, errWorkloadAffinityTypeNotFound) {
+			// do not retry since the object will be reenqueued after the policy is subsequently updated
+			return nil, nil, nil, nil, &worker.StatusAllOK
+		}
+		return nil, nil, nil, nil, &worker.StatusError
+	}
+
+	triggerHash, err := s.computeSchedulingTriggerHash(fedObject, policy, clusters, workloadAffinity)
 	if err != nil {
 		keyedLogger.Error(err, "Failed to compute scheduling trigger hash")
-		return nil, nil, nil, &worker.StatusError
+		return nil, nil, nil, nil, &worker.StatusError
 	}
 
 	triggersChanged, err := annotationutil.AddAnnotation(fedObject, SchedulingTriggerHashAnnotation, triggerHash)
 	if err != nil {
 		keyedLogger.Error(err, "Failed to update scheduling trigger hash")
-		return nil, nil, nil, &worker.StatusError
+		return nil, nil, nil, nil, &worker.StatusError
 	}
 
 	shouldSkipScheduling := false
-	if !triggersChanged {
+	restored := false
+	if _, exists := fedObject.GetAnnotations()[common.RestoredAnnotation]; exists {
+		// the object was restored from a backup together with its placements, adopt them to avoid rescheduling
+		shouldSkipScheduling = true
+		restored = true
+		if _, err := annotationutil.RemoveAnnotation(fedObject, common.RestoredAnnotation); err != nil {
+			keyedLogger.Error(err, "Failed to remove restored annotation")
+			return nil, nil, nil, nil, &worker.StatusError
+		}
+		keyedLogger.V(2).Info("Restored annotation found, adopt restored placements")
+	} else if !triggersChanged {
 		// scheduling triggers have not changed, skip scheduling
 		shouldSkipScheduling = true
 		keyedLogger.V(3).Info("Scheduling triggers not changed, skip scheduling")
@@ -423,23 +601,23 @@ func (s *Scheduler) prepareToSchedule(
 	if shouldSkipScheduling {
 		if updated, err := s.updatePendingControllers(fedObject, false); err != nil {
 			keyedLogger.Error(err, "Failed to update pending controllers")
-			return nil, nil, nil, &worker.StatusError
-		} else if updated {
+			return nil, nil, nil, nil, &worker.StatusError
+		} else if updated || restored {
 			if _, err := s.federatedObjectClient.Namespace(fedObject.GetNamespace()).Update(
 				ctx, fedObject, metav1.UpdateOptions{},
 			); err != nil {
 				keyedLogger.Error(err, "Failed to update pending controllers")
 				if apierrors.IsConflict(err) {
-					return nil, nil, nil, &worker.StatusConflict
+					return nil, nil, nil, nil, &worker.StatusConflict
 				}
-				return nil, nil, nil, &worker.StatusError
+				return nil, nil, nil, nil, &worker.StatusError
 			}
 		}
 
-		return nil, nil, nil, &worker.StatusAllOK
+		return nil, nil, nil, nil, &worker.StatusAllOK
 	}
 
-	return policy, clusters, schedulingProfile, nil
+	return policy, clusters, schedulingProfile, workloadAffinity, nil
 }
 
 func (s *Scheduler) schedule(
@@ -447,8 +625,9 @@ func (s *Scheduler) schedule(
 	fedObject *unstructured.Unstructured,
 	policy fedcorev1a1.GenericPropagationPolicy,
 	schedulingProfile *fedcorev1a1.SchedulingProfile,
+	workloadAffinity *framework.WorkloadAffinity,
 	clusters []*fedcorev1a1.FederatedCluster,
-) (*core.ScheduleResult, *worker.Result) {
+) (*framework.SchedulingUnit, *core.ScheduleResult, *worker.Result) {
 	keyedLogger := klog.FromContext(ctx)
 
 	if policy == nil {
@@ -461,7 +640,7 @@ func (s *Scheduler) schedule(
 			"no scheduling policy specified, will schedule object to no clusters",
 		)
 
-		return &core.ScheduleResult{
+		return nil, &core.ScheduleResult{
 			SuggestedClusters: make(map[string]*int64),
 		}, nil
 	}
@@ -486,8 +665,9 @@ func (s *Scheduler) schedule(
 			"failed to schedule object: %v",
 			fmt.Errorf("failed to get scheduling unit: %w", err),
 		)
-		return nil, &worker.StatusError
+		return nil, nil, &worker.StatusError
 	}
+	schedulingUnit.WorkloadAffinity = workloadAffinity
 
 	framework, err := s.createFramework(schedulingProfile, s.buildFrameworkHandle())
 	if err != nil {
@@ -500,7 +680,7 @@ func (s *Scheduler) schedule(
 			fmt.Errorf("failed to construct scheduling profile: %w", err),
 		)
 
-		return nil, &worker.StatusError
+		return nil, nil, &worker.StatusError
 	}
 
 	ctx = klog.NewContext(ctx, keyedLogger)
@@ -514,10 +694,10 @@ func (s *Scheduler) schedule(
 			"failed to schedule object: %v",
 			fmt.Errorf("failed to compute scheduling result: %w", err),
 		)
-		return nil, &worker.StatusError
+		return nil, nil, &worker.StatusError
 	}
 
-	return &result, nil
+	return schedulingUnit, &result, nil
 }
 
 func (s *Scheduler) persistSchedulingResult(
@@ -556,11 +736,12 @@ func (s *Scheduler) persistSchedulingResult(
 	// We always update the federated object because the fact that scheduling even occurred minimally implies that the
 	// scheduling trigger hash must have changed.
 	keyedLogger.V(1).Info("Updating federated object")
-	if _, err := s.federatedObjectClient.Namespace(fedObject.GetNamespace()).Update(
+	updatedObject, err := s.federatedObjectClient.Namespace(fedObject.GetNamespace()).Update(
 		ctx,
 		fedObject,
 		metav1.UpdateOptions{},
-	); err != nil {
+	)
+	if err != nil {
 		keyedLogger.Error(err, "Failed to update federated object")
 		if apierrors.IsConflict(err) {
 			return worker.StatusConflict
@@ -584,6 +765,22 @@ func (s *Scheduler) persistSchedulingResult(
 		result.String(),
 	)
 
+	var gangScheduledCondition *fedtypesv1a1.GenericCondition
+	if auxInfo.gangScheduling {
+		gangScheduledCondition = &fedtypesv1a1.GenericCondition{
+			Type:   fedtypesv1a1.GangScheduledConditionType,
+			Status: corev1.ConditionTrue,
+			Reason: fedtypesv1a1.AggregateSuccess,
+		}
+	}
+	if err := s.updateGangScheduledCondition(ctx, updatedObject, gangScheduledCondition); err != nil {
+		keyedLogger.Error(err, "Failed to update gang scheduled condition")
+		if apierrors.IsConflict(err) {
+			return worker.StatusConflict
+		}
+		return worker.StatusError
+	}
+
 	return worker.StatusAllOK
 }
 
@@ -598,8 +795,11 @@ func (s *Scheduler) federatedObjectFromStore(qualifiedName common.QualifiedName)
 	} else {
 		obj, err = s.federatedObjectLister.Get(qualifiedName.Name)
 	}
+	if err != nil {
+		return nil, err
+	}
 
-	return obj.(*unstructured.Unstructured), err
+	return obj.(*unstructured.Unstructured), nil
 }
 
 // policyFromStore uses the given qualified name to retrieve a policy from the scheduler's policy listers.
@@ -625,6 +825,8 @@ func (s *Scheduler) updatePendingControllers(fedObject *unstructured.Unstructure
 type auxiliarySchedulingInformation struct {
 	enableFollowerScheduling bool
 	unschedulableThreshold   *time.Duration
+	gangScheduling           bool
+	driftPolicy              fedcorev1a1.DriftPolicy
 }
 
 // applySchedulingResult updates the federated object with the scheduling result and the enableFollowerScheduling annotation, it returns a
@@ -686,6 +888,16 @@ func (s *Scheduler) applySchedulingResult(
 		}
 	}
 
+	if auxInfo.driftPolicy == "" || auxInfo.driftPolicy == fedcorev1a1.DriftPolicyCorrect {
+		if _, ok := annotations[common.DriftPolicyAnnotation]; ok {
+			delete(annotations, common.DriftPolicyAnnotation)
+			annotationsModified = true
+		}
+	} else if annotations[common.DriftPolicyAnnotation] != string(auxInfo.driftPolicy) {
+		annotations[common.DriftPolicyAnnotation] = string(auxInfo.driftPolicy)
+		annotationsModified = true
+	}
+
 	if annotationsModified {
 		fedObject.SetAnnotations(annotations)
 		objectModified = true
@@ -47,7 +47,7 @@ However, not all changes should trigger a scheduling, we limit our scheduling tr
 
 Federated object changes:
 1. object creation
-2. object scheduling annotation updates
+2. object scheduling annotation updates (including preemptions by other objects)
 3. object replica count change
 4. object resource request change
 
@@ -55,11 +55,16 @@ Propagation policy changes:
 1. policy creation
 2. generation change (spec update)
 
+Changes to objects referenced by the policy's workload affinity terms:
+1. placement changes
+2. label changes
+
 Cluster changes:
 1. cluster creation
 2. cluster labels change
 3. cluster taints change
 4. cluster apiresource changes
+5. cluster maintenance mode changes
 
 Simply checking for these triggers in the event handlers is insufficient. This is because when the controller restarts, all objects will be
 "created" again, causing mass rescheduling for all objects. Thus, we hash the scheduling triggers and write it into the federated object's
@@ -95,18 +100,25 @@ type schedulingTriggers struct {
 	PolicyName       string `json:"policyName"`
 	PolicyGeneration int64  `json:"policyGeneration"`
 
+	// the placements of the objects referenced by the policy's workload affinity terms
+	WorkloadAffinity *framework.WorkloadAffinity `json:"workloadAffinity,omitempty"`
+
 	// a map from each cluster to its labels
 	ClusterLabels []keyValue[string, []keyValue[string, string]] `json:"clusterLabels"`
 	// a map from each cluster to its taints
 	ClusterTaints []keyValue[string, []corev1.Taint] `json:"clusterTaints"`
 	// a map from each cluster to its apiresources
 	ClusterAPIResourceTypes []keyValue[string, []fedcorev1a1.APIResource] `json:"clusterAPIResourceTypes"`
+	// a map from each cluster in maintenance mode to its maintenance mode
+	// NOTE: omitted if no cluster is in maintenance mode to preserve the hashes computed before it was introduced
+	ClusterMaintenanceModes []keyValue[string, fedcorev1a1.ClusterMaintenanceMode] `json:"clusterMaintenanceModes,omitempty"`
 }
 
 func (s *Scheduler) computeSchedulingTriggerHash(
 	fedObject *unstructured.Unstructured,
 	policy fedcorev1a1.GenericPropagationPolicy,
 	clusters []*fedcorev1a1.FederatedCluster,
+	workloadAffinity *framework.WorkloadAffinity,
 ) (string, error) {
 	trigger := &schedulingTriggers{}
 
@@ -128,10 +140,12 @@ func (s *Scheduler) computeSchedulingTriggerHash(
 			}
 		}
 	}
+	trigger.WorkloadAffinity = workloadAffinity
 
 	trigger.ClusterLabels = getClusterLabels(clusters)
 	trigger.ClusterTaints = getClusterTaints(clusters)
 	trigger.ClusterAPIResourceTypes = getClusterAPIResourceTypes(clusters)
+	trigger.ClusterMaintenanceModes = getClusterMaintenanceModes(clusters)
 
 	triggerBytes, err := json.Marshal(trigger)
 	if err != nil {
@@ -156,6 +170,7 @@ var knownSchedulingAnnotations = sets.New(
 	AffinityAnnotations,
 	MaxClustersAnnotations,
 	FollowsObjectAnnotation,
+	PreemptionsAnnotation,
 )
 
 func getSchedulingAnnotations(fedObject *unstructured.Unstructured) []keyValue[string, string] {
@@ -261,6 +276,16 @@ func getClusterAPIResourceTypes(clusters []*fedcorev1a1.FederatedCluster) []keyV
 	return sortMap(ret)
 }
 
+func getClusterMaintenanceModes(clusters []*fedcorev1a1.FederatedCluster) []keyValue[string, fedcorev1a1.ClusterMaintenanceMode] {
+	ret := make(map[string]fedcorev1a1.ClusterMaintenanceMode)
+	for _, cluster := range clusters {
+		if mode := util.GetClusterMaintenanceMode(cluster); mode != "" {
+			ret[cluster.Name] = mode
+		}
+	}
+	return sortMap(ret)
+}
+
 // enqueueFederatedObjectsForPolicy enqueues federated objects which match the policy
 func (s *Scheduler) enqueueFederatedObjectsForPolicy(policy pkgruntime.Object) {
 	policyAccessor, ok := policy.(fedcorev1a1.GenericPropagationPolicy)
@@ -56,7 +56,10 @@ func schedulingUnitForFedObject(
 		// TODO remove this check in favor of a DivideIfPossible mode
 		schedulingMode = fedcorev1a1.SchedulingModeDuplicate
 	}
-	if schedulingMode == fedcorev1a1.SchedulingModeDivide {
+	duplicateWithScale := schedulingMode == fedcorev1a1.SchedulingModeDuplicate &&
+		policy.GetSpec().DuplicateWithScale &&
+		typeConfig.Spec.PathDefinition.ReplicasSpec != ""
+	if schedulingMode == fedcorev1a1.SchedulingModeDivide || duplicateWithScale {
 		value, err := utilunstructured.GetInt64FromPath(
 			fedObject,
 			typeConfig.Spec.PathDefinition.ReplicasSpec,
@@ -107,6 +110,7 @@ func schedulingUnitForFedObject(
 	}
 
 	schedulingUnit.SchedulingMode = schedulingMode
+	schedulingUnit.DuplicateWithScale = duplicateWithScale
 
 	schedulingUnit.StickyCluster = getIsStickyClusterFromPolicy(policy)
 	stickyClusterOverride, exists := getIsStickyClusterFromObject(fedObject)
@@ -138,12 +142,28 @@ func schedulingUnitForFedObject(
 		schedulingUnit.MaxReplicas = maxReplicasOverride
 	}
 
+	// replicas preempted by objects with higher priorities cannot be scheduled back to the cluster
+	for cluster, maxReplicas := range getMaxReplicasFromPreemptions(fedObject) {
+		if current, exists := schedulingUnit.MaxReplicas[cluster]; !exists || maxReplicas < current {
+			if schedulingUnit.MaxReplicas == nil {
+				schedulingUnit.MaxReplicas = make(map[string]int64)
+			}
+			schedulingUnit.MaxReplicas[cluster] = maxReplicas
+		}
+	}
+
 	schedulingUnit.Weights = getWeightsFromPolicy(policy)
 	weightsOverride, exists := getWeightsFromObject(fedObject)
 	if exists {
 		schedulingUnit.Weights = weightsOverride
 	}
 
+	schedulingUnit.Percentages = getPercentagesFromPolicy(policy)
+	percentagesOverride, exists := getPercentagesFromObject(fedObject)
+	if exists {
+		schedulingUnit.Percentages = percentagesOverride
+	}
+
 	schedulingUnit.Affinity = getAffinityFromPolicy(policy)
 	affinityOverride, exists := getAffinityFromObject(fedObject)
 	if exists {
@@ -510,6 +530,72 @@ func getWeightsFromObject(object *unstructured.Unstructured) (map[string]int64,
 	return weights, true
 }
 
+func getPercentagesFromPolicy(policy fedcorev1a1.GenericPropagationPolicy) map[string]int64 {
+	if policy.GetSpec().Placements == nil {
+		return nil
+	}
+
+	var percentages map[string]int64
+	for _, placement := range policy.GetSpec().Placements {
+		if placement.Preferences.Percentage != nil {
+			if percentages == nil {
+				percentages = map[string]int64{}
+			}
+			percentages[placement.Cluster] = *placement.Preferences.Percentage
+		}
+	}
+
+	return percentages
+}
+
+func getPercentagesFromObject(object *unstructured.Unstructured) (map[string]int64, bool) {
+	annotations := object.GetAnnotations()
+	if annotations == nil {
+		return nil, false
+	}
+
+	annotation, exists := annotations[PlacementsAnnotations]
+	if !exists {
+		return nil, false
+	}
+
+	var placements []fedcorev1a1.Placement
+	err := json.Unmarshal([]byte(annotation), &placements)
+	if err != nil {
+		klog.Errorf(
+			"Failed to unmarshal placements annotation (%s) on fed object %s with err %s",
+			PlacementsAnnotations,
+			object.GetName(),
+			err,
+		)
+		return nil, false
+	}
+
+	var percentages map[string]int64
+	for _, placement := range placements {
+		if placement.Preferences.Percentage != nil {
+			if percentages == nil {
+				percentages = map[string]int64{}
+			}
+			percentages[placement.Cluster] = *placement.Preferences.Percentage
+		}
+	}
+
+	// we need to do additional validation vs getting from policy which relies on CRD validation by apiserver
+	for _, percentage := range percentages {
+		if percentage < 0 {
+			klog.Errorf(
+				"Invalid value for placements annotation (%s) on fed object %s: negative percentage found",
+				PlacementsAnnotations,
+				object.GetName(),
+			)
+			return nil, false
+		}
+	}
+
+	return percentages, true
+}
+
 func getMinReplicasFromPolicy(policy fedcorev1a1.GenericPropagationPolicy) map[string]int64 {
 	if policy.GetSpec().Placements == nil {
 		return nil
@@ -39,6 +39,10 @@ var SchedulerSupportedPayloadVersions = sets.New(
 	schedwebhookv1a1.PayloadVersion,
 )
 
+// defaultWebhookTimeout is the timeout of webhook requests if neither the webhook configuration nor the scheduler
+// config sets one.
+const defaultWebhookTimeout = 5 * time.Second
+
 func (s *Scheduler) cacheWebhookPlugin(config *fedcorev1a1.SchedulerPluginWebhookConfiguration) {
 	logger := s.logger.WithValues("origin", "webhookEventHandler", "name", config.Name)
 	logger.V(1).Info("Initializing webhook plugin")
@@ -81,7 +85,10 @@ func (s *Scheduler) cacheWebhookPlugin(config *fedcorev1a1.SchedulerPluginWebhoo
 
 	timeout := config.Spec.HTTPTimeout.Duration
 	if timeout == 0 {
-		timeout = 5 * time.Second
+		timeout = s.config.WebhookTimeout
+	}
+	if timeout == 0 {
+		timeout = defaultWebhookTimeout
 	}
 	client := &http.Client{
 		Transport: transport,
@@ -42,6 +42,7 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	dynamicFake "k8s.io/client-go/dynamic/fake"
+	kubeinformers "k8s.io/client-go/informers"
 	kubeFake "k8s.io/client-go/kubernetes/fake"
 	clienttesting "k8s.io/client-go/testing"
 	"k8s.io/klog/v2/ktesting"
@@ -55,6 +56,7 @@ import (
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/pendingcontrollers"
 	schemautil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/schema"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/worker"
 	"github.com/kubewharf/kubeadmiral/pkg/stats"
 )
 
@@ -255,6 +257,7 @@ func doTest(t *testing.T, clientTLS *fedcorev1a1.WebhookTLSConfig, serverTLS *tl
 	}
 
 	kubeClient := kubeFake.NewSimpleClientset()
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeClient, 0)
 
 	scheme := runtime.NewScheme()
 	scheme.AddKnownTypeWithName(
@@ -287,22 +290,28 @@ func doTest(t *testing.T, clientTLS *fedcorev1a1.WebhookTLSConfig, serverTLS *tl
 		ktesting.NewLogger(t, ktesting.NewConfig(ktesting.Verbosity(3))),
 		typeConfig, kubeClient, fedClient, dynamicClient,
 		dynInformerFactory.ForResource(gvr),
+		dynInformerFactory,
 		fedInformerFactory.Core().V1alpha1().PropagationPolicies(),
 		fedInformerFactory.Core().V1alpha1().ClusterPropagationPolicies(),
 		fedInformerFactory.Core().V1alpha1().FederatedClusters(),
 		fedInformerFactory.Core().V1alpha1().SchedulingProfiles(),
 		fedInformerFactory.Core().V1alpha1().SchedulerPluginWebhookConfigurations(),
+		fedInformerFactory.Core().V1alpha1().FederatedTypeConfigs(),
+		kubeInformerFactory.Scheduling().V1().PriorityClasses(),
 		stats.NewMock("test", "kube-admiral", false),
-		1,
+		worker.StaticConfig(worker.Config{WorkerCount: 1}),
+		Config{},
 	)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 
 	ctx := context.Background()
+	kubeInformerFactory.Start(ctx.Done())
 	dynInformerFactory.Start(ctx.Done())
 	fedInformerFactory.Start(ctx.Done())
 
 	go scheduler.Run(ctx)
 
+	kubeInformerFactory.WaitForCacheSync(ctx.Done())
 	dynInformerFactory.WaitForCacheSync(ctx.Done())
 	fedInformerFactory.WaitForCacheSync(ctx.Done())
 
@@ -0,0 +1,269 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/scheduler/framework"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	schemautil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/schema"
+)
+
+// errWorkloadAffinityTypeNotFound is returned when a workload affinity term references a type
+// that is not federated by any FederatedTypeConfig.
+var errWorkloadAffinityTypeNotFound = errors.New("no federated type config found for workload affinity term")
+
+// resolveWorkloadAffinity resolves the workload affinity terms of the policy against the current placements of the
+// federated objects selected by each term. It returns nil if the policy does not specify any workload affinity.
+func (s *Scheduler) resolveWorkloadAffinity(
+	fedObject *unstructured.Unstructured,
+	policy fedcorev1a1.GenericPropagationPolicy,
+) (*framework.WorkloadAffinity, error) {
+	if policy == nil || policy.GetSpec().WorkloadAffinity == nil {
+		return nil, nil
+	}
+
+	spec := policy.GetSpec().WorkloadAffinity
+	if len(spec.Affinity) == 0 && len(spec.AntiAffinity) == 0 {
+		return nil, nil
+	}
+
+	result := &framework.WorkloadAffinity{}
+	for _, term := range spec.Affinity {
+		resolved, err := s.resolveWorkloadAffinityTerm(fedObject, term)
+		if err != nil {
+			return nil, err
+		}
+		result.Affinity = append(result.Affinity, resolved)
+	}
+	for _, term := range spec.AntiAffinity {
+		resolved, err := s.resolveWorkloadAffinityTerm(fedObject, term)
+		if err != nil {
+			return nil, err
+		}
+		result.AntiAffinity = append(result.AntiAffinity, resolved)
+	}
+
+	return result, nil
+}
+
+func (s *Scheduler) resolveWorkloadAffinityTerm(
+	fedObject *unstructured.Unstructured,
+	term fedcorev1a1.WorkloadAffinityTerm,
+) (framework.WorkloadAffinityTerm, error) {
+	resolved := framework.WorkloadAffinityTerm{
+		Term:       term,
+		Placements: map[string]sets.Set[string]{},
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&term.LabelSelector)
+	if err != nil {
+		return resolved, fmt.Errorf("invalid label selector in workload affinity term: %w", err)
+	}
+
+	groupKind := schema.GroupKind{Group: term.Group, Kind: term.Kind}
+	typeConfig, err := s.typeConfigForGroupKind(groupKind)
+	if err != nil {
+		return resolved, err
+	}
+
+	informer, err := s.workloadAffinityInformer(typeConfig)
+	if err != nil {
+		return resolved, err
+	}
+
+	var objects []pkgruntime.Object
+	if typeConfig.GetNamespaced() {
+		namespaces := term.Namespaces
+		if len(namespaces) == 0 {
+			namespaces = []string{fedObject.GetNamespace()}
+		}
+		for _, namespace := range namespaces {
+			namespacedObjects, err := informer.Lister().ByNamespace(namespace).List(labels.Everything())
+			if err != nil {
+				return resolved, err
+			}
+			objects = append(objects, namespacedObjects...)
+		}
+	} else {
+		if objects, err = informer.Lister().List(labels.Everything()); err != nil {
+			return resolved, err
+		}
+	}
+
+	isSameType := typeConfig.Name == s.typeConfig.Name
+	for _, object := range objects {
+		object := object.(*unstructured.Unstructured)
+		if object.GetDeletionTimestamp() != nil {
+			continue
+		}
+		// an object never satisfies or violates its own workload affinity
+		if isSameType && object.GetNamespace() == fedObject.GetNamespace() && object.GetName() == fedObject.GetName() {
+			continue
+		}
+
+		template, err := getTemplate(object)
+		if err != nil {
+			continue
+		}
+		if !selector.Matches(labels.Set(template.GetLabels())) {
+			continue
+		}
+
+		placements, err := util.UnmarshalGenericPlacements(object)
+		if err != nil {
+			return resolved, fmt.Errorf("failed to unmarshal placements of %s: %w", common.NewQualifiedName(object), err)
+		}
+		clusters := placements.ClusterNameUnion()
+		if len(clusters) == 0 {
+			continue
+		}
+		resolved.Placements[common.NewQualifiedName(object).String()] = sets.KeySet(clusters)
+	}
+
+	return resolved, nil
+}
+
+func (s *Scheduler) typeConfigForGroupKind(groupKind schema.GroupKind) (*fedcorev1a1.FederatedTypeConfig, error) {
+	typeConfigs, err := s.typeConfigLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, typeConfig := range typeConfigs {
+		targetType := typeConfig.GetTargetType()
+		if targetType.Group == groupKind.Group && targetType.Kind == groupKind.Kind {
+			return typeConfig, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", errWorkloadAffinityTypeNotFound, groupKind.String())
+}
+
+// workloadAffinityInformer returns the informer for the federated type of the given type config. The scheduler
+// additionally watches the informer so that objects with workload affinity are rescheduled when the placements of
+// the objects they reference change.
+func (s *Scheduler) workloadAffinityInformer(
+	typeConfig *fedcorev1a1.FederatedTypeConfig,
+) (informers.GenericInformer, error) {
+	federatedType := typeConfig.GetFederatedType()
+	gvr := schemautil.APIResourceToGVR(&federatedType)
+	informer := s.workloadAffinityInformerFactory.ForResource(gvr)
+
+	if _, loaded := s.workloadAffinityHandlers.LoadOrStore(gvr, nil); !loaded {
+		registration, err := informer.Informer().AddEventHandler(util.NewTriggerOnGenerationChanges(
+			func(_ pkgruntime.Object) { s.enqueueFederatedObjectsWithWorkloadAffinity() },
+		))
+		if err != nil {
+			s.workloadAffinityHandlers.Delete(gvr)
+			return nil, fmt.Errorf("failed to watch %s for workload affinity: %w", gvr.String(), err)
+		}
+		s.workloadAffinityHandlers.Store(gvr, registration)
+
+		// ask Run to start the new informer
+		select {
+		case s.workloadAffinityInformerAdded <- struct{}{}:
+		default:
+		}
+	}
+
+	if !informer.Informer().HasSynced() {
+		return nil, fmt.Errorf("informer for %s is not synced", gvr.String())
+	}
+
+	return informer, nil
+}
+
+// runWorkloadAffinityInformers starts the informers requested by workloadAffinityInformer until ctx is done, and then
+// removes the event handlers of the scheduler from them.
+func (s *Scheduler) runWorkloadAffinityInformers(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			s.removeWorkloadAffinityHandlers()
+			return
+		case <-s.workloadAffinityInformerAdded:
+			// Starting the factory is a no-op for informers that are already running. The informers are shared with
+			// other controllers and cannot be restarted, so they must outlive the scheduler.
+			s.workloadAffinityInformerFactory.Start(context.TODO().Done())
+		}
+	}
+}
+
+func (s *Scheduler) removeWorkloadAffinityHandlers() {
+	s.workloadAffinityHandlers.Range(func(key, value any) bool {
+		gvr := key.(schema.GroupVersionResource)
+		if registration, ok := value.(cache.ResourceEventHandlerRegistration); ok {
+			informer := s.workloadAffinityInformerFactory.ForResource(gvr).Informer()
+			if err := informer.RemoveEventHandler(registration); err != nil {
+				s.logger.Error(err, "Failed to remove workload affinity event handler", "gvr", gvr.String())
+			}
+		}
+		s.workloadAffinityHandlers.Delete(gvr)
+		return true
+	})
+}
+
+// enqueueFederatedObjectsWithWorkloadAffinity enqueues federated objects whose policy specifies workload affinity
+func (s *Scheduler) enqueueFederatedObjectsWithWorkloadAffinity() {
+	var policies []fedcorev1a1.GenericPropagationPolicy
+
+	clusterPolicies, err := s.clusterPropagationPolicyLister.List(labels.Everything())
+	if err != nil {
+		s.logger.Error(err, "Failed to enqueue federated objects with workload affinity")
+		return
+	}
+	for _, policy := range clusterPolicies {
+		policies = append(policies, policy)
+	}
+
+	if s.typeConfig.GetNamespaced() {
+		namespacedPolicies, err := s.propagationPolicyLister.List(labels.Everything())
+		if err != nil {
+			s.logger.Error(err, "Failed to enqueue federated objects with workload affinity")
+			return
+		}
+		for _, policy := range namespacedPolicies {
+			policies = append(policies, policy)
+		}
+	}
+
+	for _, policy := range policies {
+		if policy.GetSpec().WorkloadAffinity == nil {
+			continue
+		}
+
+		policyKey := common.QualifiedName{Namespace: policy.GetNamespace(), Name: policy.GetName()}
+		for _, qualifiedName := range s.federatedObjectsByPolicy.Get(policyKey.String()) {
+			s.worker.Enqueue(qualifiedName)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shardlabeler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicclient "k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/sharding"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/delayingdeliver"
+	schemautil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/schema"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/worker"
+	"github.com/kubewharf/kubeadmiral/pkg/stats"
+)
+
+const ShardLabelerControllerName = "shard-labeler"
+
+// Controller sets the shard bucket label of federated objects. The federate controller creates federated objects with
+// the label, but objects created otherwise or before sharding was enabled have to be labeled before any shard can
+// watch them. Each shard labels the objects it owns that are not in any bucket, i.e. unlabeled objects and objects
+// whose bucket no longer exists after the number of buckets was lowered, and relabels the objects in its buckets whose
+// label does not match their key, e.g. after the number of buckets was changed, so that they move to the shard that
+// owns them.
+type Controller struct {
+	name  string
+	shard *sharding.Shard
+
+	federatedObjectClient dynamicclient.NamespaceableResourceInterface
+	// Informer for the federated objects in the buckets of the shard
+	federatedObjectInformer informers.GenericInformer
+	// Informer for the federated objects that are not in any bucket
+	unassignedObjectInformer informers.GenericInformer
+
+	worker worker.ReconcileWorker
+
+	metrics stats.Metrics
+	logger  klog.Logger
+}
+
+func (c *Controller) IsControllerReady() bool {
+	return c.HasSynced()
+}
+
+func NewShardLabelerController(
+	typeConfig *fedcorev1a1.FederatedTypeConfig,
+	dynamicClient dynamicclient.Interface,
+	federatedObjectInformer informers.GenericInformer,
+	shard *sharding.Shard,
+	metrics stats.Metrics,
+	workerConfig worker.ConfigSource,
+) (*Controller, error) {
+	if shard == nil {
+		return nil, fmt.Errorf("%s requires sharding to be enabled", ShardLabelerControllerName)
+	}
+
+	federatedAPIResource := typeConfig.GetFederatedType()
+	federatedGVR := schemautil.APIResourceToGVR(&federatedAPIResource)
+
+	c := &Controller{
+		name:                    fmt.Sprintf("%s-%s", federatedAPIResource.Name, ShardLabelerControllerName),
+		shard:                   shard,
+		federatedObjectClient:   dynamicClient.Resource(federatedGVR),
+		federatedObjectInformer: federatedObjectInformer,
+		metrics:                 metrics,
+		logger: klog.LoggerWithValues(klog.Background(), "controller", ShardLabelerControllerName,
+			"ftc", typeConfig.Name),
+	}
+
+	c.worker = worker.NewConfigurableReconcileWorker(
+		c.reconcile,
+		worker.WorkerTiming{},
+		workerConfig,
+		metrics,
+		delayingdeliver.NewMetricTags("shard-labeler-worker", federatedAPIResource.Kind),
+	)
+
+	c.unassignedObjectInformer = dynamicinformer.NewFilteredDynamicInformer(
+		dynamicClient,
+		federatedGVR,
+		metav1.NamespaceAll,
+		util.NoResyncPeriod,
+		cache.Indexers{},
+		func(options *metav1.ListOptions) {
+			options.LabelSelector = shard.UnassignedLabelSelector()
+		},
+	)
+
+	handler := util.NewTriggerOnAllChanges(c.worker.EnqueueObject)
+	federatedObjectInformer.Informer().AddEventHandler(handler)
+	c.unassignedObjectInformer.Informer().AddEventHandler(handler)
+
+	return c, nil
+}
+
+func (c *Controller) Run(ctx context.Context) {
+	c.logger.Info("Starting controller")
+	defer c.logger.Info("Stopping controller")
+
+	go c.unassignedObjectInformer.Informer().Run(ctx.Done())
+
+	if !cache.WaitForNamedCacheSync(c.name, ctx.Done(), c.HasSynced) {
+		return
+	}
+
+	c.worker.Run(ctx.Done())
+	<-ctx.Done()
+}
+
+func (c *Controller) HasSynced() bool {
+	return c.federatedObjectInformer.Informer().HasSynced() && c.unassignedObjectInformer.Informer().HasSynced()
+}
+
+func (c *Controller) reconcile(qualifiedName common.QualifiedName) (status worker.Result) {
+	_ = c.metrics.Rate("shard-labeler.throughput", 1)
+	logger := c.logger.WithValues("object", qualifiedName.String())
+	ctx := klog.NewContext(context.TODO(), logger)
+	startTime := time.Now()
+
+	logger.V(3).Info("Start reconcile")
+	defer func() {
+		c.metrics.Duration(fmt.Sprintf("%s.latency", c.name), startTime)
+		logger.WithValues("duration", time.Since(startTime), "status", status.String()).V(3).Info("Finished reconcile")
+	}()
+
+	fedObject, err := util.UnstructuredFromStore(c.federatedObjectInformer.Informer().GetStore(), qualifiedName.String())
+	if err != nil {
+		logger.Error(err, "Failed to get federated object from store")
+		return worker.StatusError
+	}
+	if fedObject == nil {
+		// Objects that are not in any bucket are only labeled by the shard that owns them, while objects in the
+		// buckets of the shard are relabeled regardless of their owner.
+		if !c.shard.Owns(qualifiedName) {
+			return worker.StatusAllOK
+		}
+
+		fedObject, err = util.UnstructuredFromStore(c.unassignedObjectInformer.Informer().GetStore(), qualifiedName.String())
+		if err != nil {
+			logger.Error(err, "Failed to get unassigned federated object from store")
+			return worker.StatusError
+		}
+		if fedObject == nil {
+			return worker.StatusAllOK
+		}
+	}
+
+	fedObject = fedObject.DeepCopy()
+	if !c.shard.EnsureBucketLabel(fedObject) {
+		return worker.StatusAllOK
+	}
+
+	if err := c.patchBucketLabel(ctx, fedObject); err != nil && !apierrors.IsNotFound(err) {
+		logger.Error(err, "Failed to set shard bucket label")
+		return worker.StatusError
+	}
+
+	return worker.StatusAllOK
+}
+
+func (c *Controller) patchBucketLabel(ctx context.Context, fedObject *unstructured.Unstructured) error {
+	bucket := fedObject.GetLabels()[common.ShardBucketLabel]
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]string{common.ShardBucketLabel: bucket},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	klog.FromContext(ctx).V(1).Info("Setting shard bucket label", "bucket", bucket)
+	_, err = c.federatedObjectClient.Namespace(fedObject.GetNamespace()).Patch(
+		ctx,
+		fedObject.GetName(),
+		types.MergePatchType,
+		patch,
+		metav1.PatchOptions{},
+	)
+	return err
+}
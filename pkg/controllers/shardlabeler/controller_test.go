@@ -0,0 +1,169 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shardlabeler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/utils/pointer"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/sharding"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/worker"
+	"github.com/kubewharf/kubeadmiral/pkg/stats"
+)
+
+const bucketCount = 4
+
+var federatedConfigMapGVR = schema.GroupVersionResource{
+	Group:    "types.kubeadmiral.io",
+	Version:  "v1alpha1",
+	Resource: "federatedconfigmaps",
+}
+
+func newFederatedConfigMap(name string, bucket *int) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("types.kubeadmiral.io/v1alpha1")
+	obj.SetKind("FederatedConfigMap")
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	if bucket != nil {
+		obj.SetLabels(map[string]string{common.ShardBucketLabel: strconv.Itoa(*bucket)})
+	}
+	return obj
+}
+
+// nameInBucket returns a name whose key in the default namespace hashes into a bucket that matches the given
+// predicate.
+func nameInBucket(prefix string, matches func(bucket int) bool) (string, int) {
+	for i := 0; ; i++ {
+		name := fmt.Sprintf("%s-%d", prefix, i)
+		bucket := sharding.Bucket(common.QualifiedName{Namespace: "default", Name: name}, bucketCount)
+		if matches(bucket) {
+			return name, bucket
+		}
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	shard := sharding.NewShard(bucketCount, sets.New(0, 1))
+	owned := func(bucket int) bool { return bucket < 2 }
+	notOwned := func(bucket int) bool { return bucket >= 2 }
+
+	ownedName, ownedBucket := nameInBucket("owned", owned)
+	notOwnedName, notOwnedBucket := nameInBucket("not-owned", notOwned)
+
+	tests := map[string]struct {
+		object         *unstructured.Unstructured
+		inShardStore   bool
+		expectedBucket *int
+	}{
+		"unlabeled object owned by the shard is labeled": {
+			object:         newFederatedConfigMap(ownedName, nil),
+			expectedBucket: &ownedBucket,
+		},
+		"unlabeled object owned by another shard is not labeled": {
+			object: newFederatedConfigMap(notOwnedName, nil),
+		},
+		"object in a bucket that no longer exists owned by the shard is relabeled": {
+			object:         newFederatedConfigMap(ownedName, pointer.Int(bucketCount)),
+			expectedBucket: &ownedBucket,
+		},
+		"object with a stale label in the buckets of the shard is relabeled": {
+			object:         newFederatedConfigMap(notOwnedName, &ownedBucket),
+			inShardStore:   true,
+			expectedBucket: &notOwnedBucket,
+		},
+		"object with a correct label is not patched": {
+			object:       newFederatedConfigMap(ownedName, &ownedBucket),
+			inShardStore: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+				runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{federatedConfigMapGVR: "FederatedConfigMapList"},
+				test.object.DeepCopy(),
+			)
+			typeConfig := &fedcorev1a1.FederatedTypeConfig{
+				Spec: fedcorev1a1.FederatedTypeConfigSpec{
+					FederatedType: fedcorev1a1.APIResource{
+						Group:      federatedConfigMapGVR.Group,
+						Version:    federatedConfigMapGVR.Version,
+						Kind:       "FederatedConfigMap",
+						PluralName: federatedConfigMapGVR.Resource,
+						Scope:      "Namespaced",
+					},
+				},
+			}
+			federatedObjectInformer := dynamicinformer.NewDynamicSharedInformerFactory(client, 0).
+				ForResource(federatedConfigMapGVR)
+
+			controller, err := NewShardLabelerController(
+				typeConfig,
+				client,
+				federatedObjectInformer,
+				shard,
+				stats.NewMock("test", "kubeadmiral-controller-manager", false),
+				worker.StaticConfig(worker.Config{WorkerCount: 1}),
+			)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			store := controller.unassignedObjectInformer.Informer().GetStore()
+			if test.inShardStore {
+				store = federatedObjectInformer.Informer().GetStore()
+			}
+			g.Expect(store.Add(test.object)).To(gomega.Succeed())
+			client.ClearActions()
+
+			result := controller.reconcile(common.NewQualifiedName(test.object))
+			g.Expect(result).To(gomega.Equal(worker.StatusAllOK))
+
+			if test.expectedBucket == nil {
+				g.Expect(client.Actions()).To(gomega.BeEmpty())
+				return
+			}
+
+			g.Expect(client.Actions()).To(gomega.HaveLen(1))
+			g.Expect(client.Actions()[0].GetVerb()).To(gomega.Equal("patch"))
+
+			obj, err := client.Resource(federatedConfigMapGVR).Namespace("default").
+				Get(context.TODO(), test.object.GetName(), metav1.GetOptions{})
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(obj.GetLabels()).To(gomega.HaveKeyWithValue(
+				common.ShardBucketLabel,
+				strconv.Itoa(*test.expectedBucket),
+			))
+		})
+	}
+}
@@ -38,6 +38,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	kubeclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
@@ -46,6 +47,7 @@ import (
 
 	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
 	genericclient "github.com/kubewharf/kubeadmiral/pkg/client/generic"
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/sharding"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/annotation"
@@ -59,6 +61,9 @@ import (
 const (
 	StatusControllerName = "status-controller"
 	allClustersKey       = "ALL_CLUSTERS"
+
+	// TuningName is the name of the controller in the tuning configuration of the controller manager.
+	TuningName = "status"
 )
 
 const (
@@ -99,6 +104,7 @@ type StatusController struct {
 	statusClient util.ResourceClient
 
 	fedNamespace  string
+	shard         *sharding.Shard
 	metrics       stats.Metrics
 	logger        klog.Logger
 	eventRecorder record.EventRecorder
@@ -164,15 +170,16 @@ func newStatusController(
 		client:                        client,
 		statusClient:                  statusClient,
 		fedNamespace:                  controllerConfig.FedSystemNamespace,
+		shard:                         controllerConfig.Shard,
 		metrics:                       controllerConfig.Metrics,
 		logger:                        logger,
 		eventRecorder:                 eventsink.NewDefederatingRecorderMux(kubeClient, StatusControllerName, 6),
 	}
 
-	s.worker = worker.NewReconcileWorker(
+	s.worker = worker.NewConfigurableReconcileWorker(
 		s.reconcile,
 		worker.WorkerTiming{},
-		controllerConfig.WorkerCount,
+		controllerConfig.WorkerConfig(TuningName, typeConfig.Name),
 		controllerConfig.Metrics,
 		delayingdeliver.NewMetricTags("status-worker", typeConfig.GetTargetType().Kind),
 	)
@@ -185,9 +192,10 @@ func newStatusController(
 
 	targetNamespace := controllerConfig.TargetNamespace
 
-	s.federatedStore, s.federatedController = util.NewResourceInformer(
+	s.federatedStore, s.federatedController = util.NewShardedResourceInformer(
 		federatedTypeClient,
 		targetNamespace,
+		controllerConfig.Shard,
 		enqueueObj,
 		controllerConfig.Metrics,
 	)
@@ -205,7 +213,7 @@ func newStatusController(
 	s.informer, err = util.NewFederatedInformer(
 		controllerConfig,
 		client,
-		configCopy,
+		controllerConfig.ClusterRestConfig(configCopy, TuningName, typeConfig.Name),
 		&targetAPIResource,
 		func(obj pkgruntime.Object) {
 			qualifiedName := common.NewQualifiedName(obj)
@@ -296,6 +304,10 @@ func (s *StatusController) reconcile(qualifiedName common.QualifiedName) (reconc
 	keyedLogger := s.logger.WithValues("object", key)
 	ctx := klog.NewContext(context.TODO(), keyedLogger)
 
+	if !s.shard.Owns(qualifiedName) {
+		return worker.StatusAllOK
+	}
+
 	s.metrics.Rate("status.throughput", 1)
 	keyedLogger.V(3).Info("Starting reconcile")
 	startTime := time.Now()
@@ -335,6 +347,14 @@ func (s *StatusController) reconcile(qualifiedName common.QualifiedName) (reconc
 		return worker.StatusError
 	}
 
+	if existingStatus != nil {
+		clusterStatus, err = s.preservePullClusterStatuses(existingStatus, clusterStatus)
+		if err != nil {
+			keyedLogger.Error(err, "Failed to preserve status of pull-mode clusters")
+			return worker.StatusError
+		}
+	}
+
 	var rsDigestsAnnotation string
 	if targetIsDeployment {
 		latestReplicasetDigests, err := s.latestReplicasetDigests(ctx, clusterNames, qualifiedName)
@@ -487,6 +507,41 @@ func (s *StatusController) clusterNames() ([]string, error) {
 	return clusterNames, nil
 }
 
+// preservePullClusterStatuses adds the existing statuses of joined pull-mode clusters to the given cluster statuses.
+// The statuses of pull-mode clusters are reported by their agents instead of being collected by the controller.
+func (s *StatusController) preservePullClusterStatuses(
+	existingStatus *unstructured.Unstructured,
+	clusterStatus []util.ResourceClusterStatus,
+) ([]util.ResourceClusterStatus, error) {
+	pullClusters, err := s.informer.GetJoinedPullClusters()
+	if err != nil {
+		return nil, err
+	}
+	if len(pullClusters) == 0 {
+		return clusterStatus, nil
+	}
+
+	pullClusterNames := sets.New[string]()
+	for _, cluster := range pullClusters {
+		pullClusterNames.Insert(cluster.Name)
+	}
+
+	existingClusterStatus, err := util.ClusterStatusesFromObject(existingStatus)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range existingClusterStatus {
+		if pullClusterNames.Has(status.ClusterName) {
+			clusterStatus = append(clusterStatus, status)
+		}
+	}
+
+	sort.Slice(clusterStatus, func(i, j int) bool {
+		return clusterStatus[i].ClusterName < clusterStatus[j].ClusterName
+	})
+	return clusterStatus, nil
+}
+
 // clusterStatuses returns the resource status in member cluster.
 func (s *StatusController) clusterStatuses(
 	ctx context.Context,
@@ -523,30 +578,8 @@ func (s *StatusController) clusterStatuses(
 
 		collectedFields := map[string]interface{}{}
 		failedFields := []string{}
-
 		if s.typeConfig.Spec.StatusCollection != nil {
-			for _, field := range s.typeConfig.Spec.StatusCollection.Fields {
-				fieldVal, found, err := unstructured.NestedFieldCopy(
-					clusterObj.Object,
-					strings.Split(field, ".")...)
-				if err != nil || !found {
-					keyedLogger.WithValues("status-field", field, "cluster-name", clusterName).
-						Error(err, "Failed to get status field value")
-					if err != nil {
-						failedFields = append(failedFields, fmt.Sprintf("%s: %s", field, err.Error()))
-					} else {
-						failedFields = append(failedFields, fmt.Sprintf("%s: not found", field))
-					}
-					continue
-				}
-
-				err = unstructured.SetNestedField(collectedFields, fieldVal, strings.Split(field, ".")...)
-				if err != nil {
-					keyedLogger.WithValues("status-field", field, "cluster-name", clusterName).
-						Error(err, "Failed to set status field value")
-					continue
-				}
-			}
+			collectedFields, failedFields = util.CollectStatusFields(clusterObj, s.typeConfig.Spec.StatusCollection.Fields)
 		}
 
 		resourceClusterStatus.CollectedFields = collectedFields
@@ -37,6 +37,7 @@ import (
 
 	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
 	genericclient "github.com/kubewharf/kubeadmiral/pkg/client/generic"
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/sharding"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/statusaggregator/plugins"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
@@ -51,6 +52,9 @@ import (
 const (
 	ControllerName = "status-aggregator-controller"
 
+	// TuningName is the name of the controller in the tuning configuration of the controller manager.
+	TuningName = "statusaggregator"
+
 	allClustersKey = "ALL_CLUSTERS"
 
 	EventReasonUpdateSourceObjectStatus     = "UpdateSourceObjectStatus"
@@ -76,6 +80,9 @@ type StatusAggregator struct {
 	// Client for source type
 	sourceClient util.ResourceClient
 
+	// The shard of federated objects owned by the aggregator. Nil if sharding is disabled.
+	shard *sharding.Shard
+
 	// Informer for resources in member clusters
 	informer util.FederatedInformer
 	// For triggering reconciliation of all target resources. This is
@@ -151,19 +158,21 @@ func newStatusAggregator(controllerConfig *util.ControllerConfig,
 	a.clusterAvailableDelay = controllerConfig.ClusterAvailableDelay
 	a.clusterUnavailableDelay = controllerConfig.ClusterUnavailableDelay
 	a.objectEnqueueDelay = 10 * time.Second
+	a.shard = controllerConfig.Shard
 
-	a.worker = worker.NewReconcileWorker(
+	a.worker = worker.NewConfigurableReconcileWorker(
 		a.reconcile,
 		worker.WorkerTiming{},
-		controllerConfig.WorkerCount,
+		controllerConfig.WorkerConfig(TuningName, typeConfig.Name),
 		controllerConfig.Metrics,
 		delayingdeliver.NewMetricTags("statusaggregator-worker", typeConfig.GetTargetType().Kind),
 	)
 	enqueueObj := a.worker.EnqueueObject
 	targetNamespace := controllerConfig.TargetNamespace
-	a.federatedStore, a.federatedController = util.NewResourceInformer(
+	a.federatedStore, a.federatedController = util.NewShardedResourceInformer(
 		a.federatedClient,
 		targetNamespace,
+		controllerConfig.Shard,
 		enqueueObj,
 		controllerConfig.Metrics,
 	)
@@ -176,7 +185,7 @@ func newStatusAggregator(controllerConfig *util.ControllerConfig,
 	a.informer, err = util.NewFederatedInformer(
 		controllerConfig,
 		genericclient.NewForConfigOrDie(configWithUserAgent),
-		configWithUserAgent,
+		controllerConfig.ClusterRestConfig(configWithUserAgent, TuningName, typeConfig.Name),
 		&targetAPIResource,
 		func(obj pkgruntime.Object) {
 			qualifiedName := common.NewQualifiedName(obj)
@@ -252,6 +261,10 @@ func (a *StatusAggregator) reconcile(qualifiedName common.QualifiedName) (status
 	logger := a.logger.WithValues("object", key)
 	ctx := klog.NewContext(context.TODO(), logger)
 
+	if !a.shard.Owns(qualifiedName) {
+		return worker.StatusAllOK
+	}
+
 	a.metrics.Rate("status-aggregator.throughput", 1)
 	logger.V(3).Info("Starting to reconcile")
 	startTime := time.Now()
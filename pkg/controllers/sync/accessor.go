@@ -30,6 +30,8 @@ import (
 
 	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
 	genericclient "github.com/kubewharf/kubeadmiral/pkg/client/generic"
+	fedinformers "github.com/kubewharf/kubeadmiral/pkg/client/informers/externalversions"
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/sharding"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/sync/version"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
@@ -63,6 +65,9 @@ type resourceAccessor struct {
 	fedNamespaceStore      cache.Store
 	fedNamespaceController cache.Controller
 
+	// The shard of federated resources in federatedStore. Nil if sharding is disabled.
+	shard *sharding.Shard
+
 	// Manages propagated versions
 	versionManager *version.VersionManager
 
@@ -78,11 +83,13 @@ func NewFederatedResourceAccessor(
 	typeConfig *fedcorev1a1.FederatedTypeConfig,
 	fedNamespaceAPIResource *metav1.APIResource,
 	client genericclient.Client,
+	fedInformerFactory fedinformers.SharedInformerFactory,
 	enqueueObj func(pkgruntime.Object),
 	eventRecorder record.EventRecorder,
 ) (FederatedResourceAccessor, error) {
 	a := &resourceAccessor{
 		limitedScope:            controllerConfig.LimitedScope(),
+		shard:                   controllerConfig.Shard,
 		typeConfig:              typeConfig,
 		fedNamespace:            controllerConfig.FedSystemNamespace,
 		fedNamespaceAPIResource: fedNamespaceAPIResource,
@@ -97,9 +104,10 @@ func NewFederatedResourceAccessor(
 	if err != nil {
 		return nil, err
 	}
-	a.federatedStore, a.federatedController = util.NewResourceInformer(
+	a.federatedStore, a.federatedController = util.NewShardedResourceInformer(
 		federatedTypeClient,
 		targetNamespace,
+		controllerConfig.Shard,
 		enqueueObj,
 		controllerConfig.Metrics,
 	)
@@ -136,9 +144,16 @@ func NewFederatedResourceAccessor(
 		)
 	}
 
+	var versionInformer cache.SharedIndexInformer
+	if typeConfig.GetNamespaced() {
+		versionInformer = fedInformerFactory.Core().V1alpha1().PropagatedVersions().Informer()
+	} else {
+		versionInformer = fedInformerFactory.Core().V1alpha1().ClusterPropagatedVersions().Informer()
+	}
 	a.versionManager = version.NewVersionManager(
 		logger,
 		client,
+		versionInformer,
 		typeConfig.GetNamespaced(),
 		typeConfig.GetFederatedType().Kind,
 		typeConfig.GetTargetType().Kind,
@@ -189,6 +204,12 @@ func (a *resourceAccessor) FederatedResource(eventSource common.QualifiedName) (
 		Name:      eventSource.Name,
 	}
 
+	if !a.shard.Owns(federatedName) {
+		// The federated resource is reconciled by another shard. It must not be treated as an orphan since it is
+		// missing from the store.
+		return nil, false, nil
+	}
+
 	key := federatedName.String()
 
 	resource, err := util.ObjFromCache(a.federatedStore, kind, key)
@@ -38,6 +38,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
 	kubeclient "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
@@ -49,6 +50,7 @@ import (
 	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
 	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
 	genericclient "github.com/kubewharf/kubeadmiral/pkg/client/generic"
+	fedinformers "github.com/kubewharf/kubeadmiral/pkg/client/informers/externalversions"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/sync/dispatch"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/sync/status"
@@ -71,6 +73,9 @@ const (
 	EventReasonWaitForCascadingDelete      = "WaitForCascadingDelete"
 	EventReasonWaitForCascadingDeleteError = "WaitForCascadingDeleteError"
 	SyncControllerName                     = "sync-controller"
+
+	// TuningName is the name of the controller in the tuning configuration of the controller manager.
+	TuningName = "sync"
 )
 
 const (
@@ -110,11 +115,14 @@ type SyncController struct {
 	recheckAfterDispatchDelay     time.Duration
 	ensureDeletionRecheckDelay    time.Duration
 	cascadingDeletionRecheckDelay time.Duration
+	pausedRecheckDelay            time.Duration
 
 	typeConfig *fedcorev1a1.FederatedTypeConfig
 
 	fedAccessor FederatedResourceAccessor
 
+	pauseChecker *pauseChecker
+
 	hostClusterClient genericclient.Client
 
 	controllerHistory history.Interface
@@ -142,6 +150,8 @@ func StartSyncController(
 	fedNamespaceAPIResource *metav1.APIResource,
 	controllerRevisionStore cache.Store,
 	controllerRevisionController cache.Controller,
+	kubeInformerFactory informers.SharedInformerFactory,
+	fedInformerFactory fedinformers.SharedInformerFactory,
 ) error {
 	controller, err := newSyncController(
 		controllerConfig,
@@ -149,6 +159,8 @@ func StartSyncController(
 		fedNamespaceAPIResource,
 		controllerRevisionStore,
 		controllerRevisionController,
+		kubeInformerFactory,
+		fedInformerFactory,
 	)
 	if err != nil {
 		return err
@@ -168,6 +180,8 @@ func newSyncController(
 	fedNamespaceAPIResource *metav1.APIResource,
 	controllerRevisionStore cache.Store,
 	controllerRevisionController cache.Controller,
+	kubeInformerFactory informers.SharedInformerFactory,
+	fedInformerFactory fedinformers.SharedInformerFactory,
 ) (*SyncController, error) {
 	federatedTypeAPIResource := typeConfig.GetFederatedType()
 	userAgent := fmt.Sprintf("%s-federate-sync-controller", strings.ToLower(federatedTypeAPIResource.Kind))
@@ -190,6 +204,7 @@ func newSyncController(
 		recheckAfterDispatchDelay:     time.Second * 10,
 		ensureDeletionRecheckDelay:    time.Second * 5,
 		cascadingDeletionRecheckDelay: time.Second * 10,
+		pausedRecheckDelay:            time.Second * 30,
 		eventRecorder:                 recorder,
 		typeConfig:                    typeConfig,
 		hostClusterClient:             client,
@@ -215,10 +230,10 @@ func newSyncController(
 	}
 	s.cascadingDeleteFinalizer = fmt.Sprintf("%s-%s-%d", FinalizerCascadingDeletePrefix, ftcNameTruncated, hash.Sum32())
 
-	s.worker = worker.NewReconcileWorker(
+	s.worker = worker.NewConfigurableReconcileWorker(
 		s.reconcile,
 		worker.WorkerTiming{},
-		controllerConfig.WorkerCount,
+		controllerConfig.WorkerConfig(TuningName, typeConfig.Name),
 		controllerConfig.Metrics,
 		deliverutil.NewMetricTags("sync-worker", typeConfig.GetTargetType().Kind),
 	)
@@ -235,7 +250,7 @@ func newSyncController(
 	s.informer, err = util.NewFederatedInformer(
 		controllerConfig,
 		client,
-		configCopy,
+		controllerConfig.ClusterRestConfig(configCopy, TuningName, typeConfig.Name),
 		&targetAPIResource,
 		func(obj pkgruntime.Object) {
 			qualifiedName := common.NewQualifiedName(obj)
@@ -260,11 +275,31 @@ func newSyncController(
 
 	s.fedAccessor, err = NewFederatedResourceAccessor(
 		logger, controllerConfig, typeConfig, fedNamespaceAPIResource,
-		client, s.worker.EnqueueObject, recorder)
+		client, fedInformerFactory, s.worker.EnqueueObject, recorder)
 	if err != nil {
 		return nil, err
 	}
 
+	s.pauseChecker = newPauseChecker(
+		logger,
+		controllerConfig,
+		kubeInformerFactory.Core().V1().Namespaces(),
+		kubeInformerFactory.Core().V1().ConfigMaps(),
+		func(namespace string) {
+			s.fedAccessor.VisitFederatedResources(func(obj interface{}) {
+				qualifiedName := common.NewQualifiedName(obj.(pkgruntime.Object))
+				if s.pauseNamespace(qualifiedName) == namespace {
+					s.worker.Enqueue(qualifiedName)
+				}
+			})
+		},
+		func() {
+			s.fedAccessor.VisitFederatedResources(func(obj interface{}) {
+				s.worker.EnqueueObject(obj.(pkgruntime.Object))
+			})
+		},
+	)
+
 	if typeConfig.GetRevisionHistoryEnabled() {
 		s.controllerHistory = history.NewHistory(kubeClient, controllerRevisionStore)
 		s.revListerSynced = controllerRevisionController.HasSynced
@@ -282,10 +317,12 @@ func (s *SyncController) minimizeLatency() {
 	s.recheckAfterDispatchDelay = 2 * time.Second
 	s.ensureDeletionRecheckDelay = 2 * time.Second
 	s.cascadingDeletionRecheckDelay = 3 * time.Second
+	s.pausedRecheckDelay = 2 * time.Second
 }
 
 func (s *SyncController) Run(stopChan <-chan struct{}) {
 	s.fedAccessor.Run(stopChan)
+	s.pauseChecker.Run(stopChan)
 	s.informer.Start()
 	s.clusterDeliverer.StartWithHandler(func(_ *deliverutil.DelayingDelivererItem) {
 		s.reconcileOnClusterChange()
@@ -321,6 +358,11 @@ func (s *SyncController) HasSynced() bool {
 		return false
 	}
 
+	if !s.pauseChecker.HasSynced() {
+		s.logger.V(3).Info("Pause informers not synced")
+		return false
+	}
+
 	if s.typeConfig.GetRevisionHistoryEnabled() && !s.revListerSynced() {
 		s.logger.V(3).Info("ControllerRevision list not synced")
 		return false
@@ -348,6 +390,12 @@ func (s *SyncController) reconcile(qualifiedName common.QualifiedName) (status w
 		return worker.StatusError
 	}
 	if possibleOrphan {
+		if reason := s.pauseChecker.namespacePausedReason(s.pauseNamespace(qualifiedName)); reason != fedtypesv1a1.AggregateSuccess {
+			// Orphans are not enqueued when propagation is resumed, so they are rechecked periodically.
+			keyedLogger.WithValues("reason", reason).V(2).Info("Propagation paused, skip removing the managed label")
+			return worker.Result{RequeueAfter: &s.pausedRecheckDelay}
+		}
+
 		apiResource := s.typeConfig.GetTargetType()
 		gvk := schemautil.APIResourceToGVK(&apiResource)
 		keyedLogger.WithValues("label", managedlabel.ManagedByKubeAdmiralLabelKey).
@@ -373,6 +421,16 @@ func (s *SyncController) reconcile(qualifiedName common.QualifiedName) (status w
 		keyedLogger.WithValues("duration", time.Since(startTime), "status", status).V(3).Info("Finished reconciling")
 	}()
 
+	// Objects are neither created, updated nor deleted in member clusters while propagation is paused, including when
+	// the federated object is deleted.
+	if reason := s.pauseChecker.pausedReason(
+		fedResource.Object(),
+		s.pauseNamespace(fedResource.TargetName()),
+	); reason != fedtypesv1a1.AggregateSuccess {
+		keyedLogger.WithValues("reason", reason).V(2).Info("Propagation paused, skip syncing to member clusters")
+		return s.setPausedStatus(ctx, fedResource, reason)
+	}
+
 	if fedResource.Object().GetDeletionTimestamp() != nil {
 		return s.ensureDeletion(ctx, fedResource)
 	}
@@ -443,6 +501,32 @@ func (s *SyncController) syncToClusters(ctx context.Context, fedResource Federat
 		return s.setFederatedStatus(ctx, fedResource, collisionCount, fedtypesv1a1.ComputePlacementFailed, nil)
 	}
 
+	// Objects in pull-mode clusters are synced by the agents of the clusters, which also report their status.
+	pullClusters, err := s.informer.GetJoinedPullClusters()
+	if err != nil {
+		fedResource.RecordError(
+			string(fedtypesv1a1.ClusterRetrievalFailed),
+			errors.Wrap(err, "Failed to retrieve list of pull-mode clusters"),
+		)
+		return s.setFederatedStatus(ctx, fedResource, collisionCount, fedtypesv1a1.ClusterRetrievalFailed, nil)
+	}
+	selectedPullClusterNames, err := fedResource.ComputePlacement(pullClusters)
+	if err != nil {
+		fedResource.RecordError(
+			string(fedtypesv1a1.ComputePlacementFailed),
+			errors.Wrap(err, "Failed to compute placement"),
+		)
+		return s.setFederatedStatus(ctx, fedResource, collisionCount, fedtypesv1a1.ComputePlacementFailed, nil)
+	}
+	if err := s.ensurePullClusterKeys(ctx, fedResource, selectedPullClusterNames); err != nil {
+		if apierrors.IsConflict(err) {
+			return worker.StatusConflict
+		}
+		keyedLogger.Error(err, "Failed to ensure pull-mode cluster labels")
+		fedResource.RecordError("EnsurePullClusterLabelsError", errors.Wrap(err, "Failed to ensure pull-mode cluster labels"))
+		return worker.StatusError
+	}
+
 	keyedLogger.WithValues("clusters", strings.Join(selectedClusterNames.List(), ",")).
 		V(2).Info("Ensuring target object in clusters")
 
@@ -454,6 +538,8 @@ func (s *SyncController) syncToClusters(ctx context.Context, fedResource Federat
 		s.metrics,
 	)
 
+	drainChecker := s.newDrainChecker(fedResource, selectedClusterNames, selectedPullClusterNames)
+
 	shouldRecheckAfterDispatch := false
 	for _, cluster := range clusters {
 		clusterName := cluster.Name
@@ -504,6 +590,26 @@ func (s *SyncController) syncToClusters(ctx context.Context, fedResource Federat
 				// lagging behind and sees a terminating cluster.
 				continue
 			}
+			if util.IsClusterDraining(cluster) && !isCascadingDeletionTriggered {
+				// Keep the object in the draining cluster until it is placed in and its replicas are available in
+				// other clusters.
+				state, err := drainChecker.check(ctx)
+				if err != nil {
+					shouldRecheckAfterDispatch = true
+					dispatcher.RecordClusterError(fedtypesv1a1.DrainCheckFailed, clusterName, err)
+					continue
+				}
+				if state == drainBlocked {
+					// The object is rechecked when its placement changes.
+					dispatcher.RecordStatus(clusterName, fedtypesv1a1.DrainBlocked)
+					continue
+				}
+				if state == drainWaiting {
+					shouldRecheckAfterDispatch = true
+					dispatcher.RecordStatus(clusterName, fedtypesv1a1.WaitingForDrain)
+					continue
+				}
+			}
 
 			// We only respect orphaning behavior during cascading deletion, but not while migrating between clusters.
 			s.deleteFromCluster(ctx, dispatcher, clusterName, fedResource, clusterObj, isCascadingDeletionTriggered)
@@ -574,6 +680,7 @@ func (s *SyncController) syncToClusters(ctx context.Context, fedResource Federat
 	}
 
 	collectedStatus := dispatcher.CollectedStatus()
+	collectedStatus.AgentClusters = selectedPullClusterNames.List()
 	if reconcileStatus := s.setFederatedStatus(
 		ctx,
 		fedResource,
@@ -720,6 +827,51 @@ func (s *SyncController) setFederatedStatus(ctx context.Context, fedResource Fed
 	return worker.StatusAllOK
 }
 
+// setPausedStatus reports that the propagation of the federated object is paused for the given reason.
+func (s *SyncController) setPausedStatus(
+	ctx context.Context,
+	fedResource FederatedResource,
+	reason fedtypesv1a1.AggregateReason,
+) worker.Result {
+	obj := fedResource.Object()
+	keyedLogger := klog.FromContext(ctx)
+
+	err := wait.PollImmediate(1*time.Second, 5*time.Second, func() (bool, error) {
+		if updateRequired, err := status.SetPausedCondition(obj, reason); err != nil {
+			return false, errors.Wrapf(err, "failed to set the paused condition")
+		} else if !updateRequired {
+			return true, nil
+		}
+
+		err := s.hostClusterClient.UpdateStatus(context.TODO(), obj)
+		if err == nil {
+			return true, nil
+		}
+		if apierrors.IsConflict(err) {
+			err := s.hostClusterClient.Get(context.TODO(), obj, obj.GetNamespace(), obj.GetName())
+			if err != nil {
+				return false, errors.Wrapf(err, "failed to retrieve resource")
+			}
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to update resource")
+	})
+	if err != nil {
+		keyedLogger.Error(err, "Failed to set paused condition")
+		return worker.StatusError
+	}
+
+	return worker.StatusAllOK
+}
+
+// pauseNamespace returns the namespace whose paused label pauses the propagation of the named target object.
+func (s *SyncController) pauseNamespace(targetName common.QualifiedName) string {
+	if s.typeConfig.GetTargetType().Kind == common.NamespaceKind {
+		return targetName.Name
+	}
+	return targetName.Namespace
+}
+
 func (s *SyncController) ensureDeletion(ctx context.Context, fedResource FederatedResource) worker.Result {
 	fedResource.DeleteVersions()
 
@@ -1122,6 +1274,11 @@ func (s *SyncController) reconcileCluster(qualifiedName common.QualifiedName) wo
 		return worker.StatusAllOK
 	}
 
+	if util.IsPullModeCluster(cluster) {
+		// the objects in pull-mode clusters are deleted by their agents
+		return s.reconcileTerminatingPullCluster(ctx, cluster)
+	}
+
 	if !util.IsClusterJoined(&cluster.Status) || !util.IsCascadingDeleteEnabled(cluster) {
 		// cascading-delete is not required, remove cascading-delete finalizer immediately
 		err := s.removeClusterFinalizer(ctx, cluster)
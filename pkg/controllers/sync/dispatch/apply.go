@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubewharf/kubeadmiral/pkg/client/generic"
+)
+
+// FieldManager is the field manager with which target objects are server-side applied in member clusters.
+const FieldManager = "kubeadmiral"
+
+// applyObject server-side applies obj, which must only contain the fields owned by KubeAdmiral. Ownership of
+// conflicting fields is only forced if the object has not been applied by KubeAdmiral before, i.e. when it is being
+// created or adopted, or when its type has just been switched to server-side apply. Afterwards, conflicts with other
+// field managers are returned as errors.
+func applyObject(ctx context.Context, client generic.Client, obj, clusterObj *unstructured.Unstructured) error {
+	obj.SetResourceVersion("")
+	obj.SetManagedFields(nil)
+
+	opts := []runtimeclient.PatchOption{runtimeclient.FieldOwner(FieldManager)}
+	if clusterObj == nil || !isAppliedByFieldManager(clusterObj) {
+		opts = append(opts, runtimeclient.ForceOwnership)
+	}
+	return client.Patch(ctx, obj, runtimeclient.Apply, opts...)
+}
+
+// createObjectByApply creates obj with a server-side apply. Like a create, it fails with an AlreadyExists error if
+// the object exists.
+func createObjectByApply(ctx context.Context, client generic.Client, obj *unstructured.Unstructured) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GroupVersionKind())
+	err := client.Get(ctx, existing, obj.GetNamespace(), obj.GetName())
+	if err == nil {
+		gvk := obj.GroupVersionKind()
+		return apierrors.NewAlreadyExists(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, obj.GetName())
+	}
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+	return applyObject(ctx, client, obj, nil)
+}
+
+func isAppliedByFieldManager(obj *unstructured.Unstructured) bool {
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Manager == FieldManager && entry.Operation == metav1.ManagedFieldsOperationApply {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kubewharf/kubeadmiral/pkg/client/generic"
+)
+
+type fakeApplyClient struct {
+	generic.Client
+
+	exists  bool
+	patches []runtimeclient.Patch
+	options []*runtimeclient.PatchOptions
+}
+
+func (c *fakeApplyClient) Get(ctx context.Context, obj runtimeclient.Object, namespace, name string) error {
+	if !c.exists {
+		return apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, name)
+	}
+	return nil
+}
+
+func (c *fakeApplyClient) Patch(
+	ctx context.Context,
+	obj runtimeclient.Object,
+	patch runtimeclient.Patch,
+	opts ...runtimeclient.PatchOption,
+) error {
+	options := &runtimeclient.PatchOptions{}
+	options.ApplyOptions(opts)
+	c.patches = append(c.patches, patch)
+	c.options = append(c.options, options)
+	return nil
+}
+
+func newConfigMap(managedFields ...metav1.ManagedFieldsEntry) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace("default")
+	obj.SetName("test")
+	obj.SetResourceVersion("1")
+	obj.SetManagedFields(managedFields)
+	return obj
+}
+
+func TestApplyObject(t *testing.T) {
+	testCases := map[string]struct {
+		clusterObj    *unstructured.Unstructured
+		expectedForce bool
+	}{
+		"ownership is forced for new objects": {
+			clusterObj:    nil,
+			expectedForce: true,
+		},
+		"ownership is forced for objects not yet applied by kubeadmiral": {
+			clusterObj: newConfigMap(
+				metav1.ManagedFieldsEntry{Manager: FieldManager, Operation: metav1.ManagedFieldsOperationUpdate},
+				metav1.ManagedFieldsEntry{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationApply},
+			),
+			expectedForce: true,
+		},
+		"ownership is not forced for objects applied by kubeadmiral": {
+			clusterObj: newConfigMap(
+				metav1.ManagedFieldsEntry{Manager: FieldManager, Operation: metav1.ManagedFieldsOperationApply},
+			),
+			expectedForce: false,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			client := &fakeApplyClient{}
+			obj := newConfigMap(metav1.ManagedFieldsEntry{Manager: FieldManager})
+			g.Expect(applyObject(context.TODO(), client, obj, testCase.clusterObj)).To(gomega.Succeed())
+
+			g.Expect(obj.GetResourceVersion()).To(gomega.BeEmpty())
+			g.Expect(obj.GetManagedFields()).To(gomega.BeEmpty())
+			g.Expect(client.patches).To(gomega.Equal([]runtimeclient.Patch{runtimeclient.Apply}))
+			g.Expect(client.options[0].FieldManager).To(gomega.Equal(FieldManager))
+			if testCase.expectedForce {
+				g.Expect(client.options[0].Force).To(gomega.HaveValue(gomega.BeTrue()))
+			} else {
+				g.Expect(client.options[0].Force).To(gomega.BeNil())
+			}
+		})
+	}
+}
+
+func TestCreateObjectByApply(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	client := &fakeApplyClient{exists: true}
+	err := createObjectByApply(context.TODO(), client, newConfigMap())
+	g.Expect(apierrors.IsAlreadyExists(err)).To(gomega.BeTrue())
+	g.Expect(client.patches).To(gomega.BeEmpty())
+
+	client = &fakeApplyClient{}
+	g.Expect(createObjectByApply(context.TODO(), client, newConfigMap())).To(gomega.Succeed())
+	g.Expect(client.patches).To(gomega.HaveLen(1))
+	g.Expect(client.options[0].Force).To(gomega.HaveValue(gomega.BeTrue()))
+}
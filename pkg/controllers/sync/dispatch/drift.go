@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+// driftPolicyForObject returns the drift policy of the federated object set by the scheduler.
+func driftPolicyForObject(fedObject *unstructured.Unstructured) fedcorev1a1.DriftPolicy {
+	if policy := fedObject.GetAnnotations()[common.DriftPolicyAnnotation]; policy != "" {
+		return fedcorev1a1.DriftPolicy(policy)
+	}
+	return fedcorev1a1.DriftPolicyCorrect
+}
+
+// driftedFields returns the sorted paths of the fields in desiredObj whose values differ from clusterObj. Fields that
+// are only present in clusterObj, e.g. fields defaulted or set by controllers in the member cluster, are ignored, as
+// are the status and the metadata other than labels and annotations.
+func driftedFields(desiredObj, clusterObj *unstructured.Unstructured) []string {
+	var fields []string
+	for key, desired := range desiredObj.Object {
+		switch key {
+		case common.StatusField:
+			continue
+		case common.MetadataField:
+			desiredMetadata, _ := desired.(map[string]interface{})
+			clusterMetadata, _ := clusterObj.Object[common.MetadataField].(map[string]interface{})
+			for _, metadataKey := range []string{"labels", "annotations"} {
+				desiredValue, ok := desiredMetadata[metadataKey]
+				if !ok {
+					continue
+				}
+				path := common.MetadataField + "." + metadataKey
+				fields = appendDriftedFields(fields, path, desiredValue, clusterMetadata[metadataKey])
+			}
+		default:
+			fields = appendDriftedFields(fields, key, desired, clusterObj.Object[key])
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func appendDriftedFields(fields []string, path string, desired, actual interface{}) []string {
+	switch desired := desired.(type) {
+	case map[string]interface{}:
+		actual, ok := actual.(map[string]interface{})
+		if !ok {
+			return append(fields, path)
+		}
+		for key, value := range desired {
+			fields = appendDriftedFields(fields, fieldPath(path, key), value, actual[key])
+		}
+		return fields
+	case []interface{}:
+		actual, ok := actual.([]interface{})
+		if !ok || len(actual) != len(desired) {
+			return append(fields, path)
+		}
+		for i := range desired {
+			fields = appendDriftedFields(fields, fmt.Sprintf("%s[%d]", path, i), desired[i], actual[i])
+		}
+		return fields
+	default:
+		if !valuesEqual(desired, actual) {
+			return append(fields, path)
+		}
+		return fields
+	}
+}
+
+func fieldPath(path, key string) string {
+	if strings.ContainsAny(key, "./") {
+		return fmt.Sprintf("%s[%s]", path, key)
+	}
+	return path + "." + key
+}
+
+// valuesEqual compares scalar values, treating numbers of different types as equal if their values are equal.
+func valuesEqual(a, b interface{}) bool {
+	if x, ok := toFloat64(a); ok {
+		y, ok := toFloat64(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat64(value interface{}) (float64, bool) {
+	switch value := value.(type) {
+	case int64:
+		return float64(value), true
+	case int32:
+		return float64(value), true
+	case int:
+		return float64(value), true
+	case float64:
+		return value, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+func newDeployment(replicas int64, image string, labels map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "app", "image": image},
+					},
+				},
+			},
+		},
+	}}
+	obj.SetNamespace("default")
+	obj.SetName("test")
+	obj.SetLabels(labels)
+	return obj
+}
+
+func TestDriftedFields(t *testing.T) {
+	testCases := map[string]struct {
+		desiredObj     *unstructured.Unstructured
+		clusterObj     func() *unstructured.Unstructured
+		expectedFields []string
+	}{
+		"no drift": {
+			desiredObj: newDeployment(1, "nginx:1", map[string]string{"app": "test"}),
+			clusterObj: func() *unstructured.Unstructured {
+				return newDeployment(1, "nginx:1", map[string]string{"app": "test"})
+			},
+			expectedFields: nil,
+		},
+		"fields set in the cluster are ignored": {
+			desiredObj: newDeployment(1, "nginx:1", map[string]string{"app": "test"}),
+			clusterObj: func() *unstructured.Unstructured {
+				obj := newDeployment(1, "nginx:1", map[string]string{"app": "test", "extra": "label"})
+				obj.SetResourceVersion("10")
+				obj.SetGeneration(2)
+				_ = unstructured.SetNestedField(obj.Object, "RollingUpdate", "spec", "strategy", "type")
+				_ = unstructured.SetNestedField(obj.Object, int64(1), "status", "replicas")
+				containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+				containers[0].(map[string]interface{})["imagePullPolicy"] = "IfNotPresent"
+				_ = unstructured.SetNestedSlice(obj.Object, containers, "spec", "template", "spec", "containers")
+				return obj
+			},
+			expectedFields: nil,
+		},
+		"numbers of different types are compared by value": {
+			desiredObj: newDeployment(3, "nginx:1", nil),
+			clusterObj: func() *unstructured.Unstructured {
+				obj := newDeployment(0, "nginx:1", nil)
+				_ = unstructured.SetNestedField(obj.Object, float64(3), "spec", "replicas")
+				return obj
+			},
+			expectedFields: nil,
+		},
+		"changed fields are reported": {
+			desiredObj: newDeployment(3, "nginx:1", map[string]string{"app.kubernetes.io/name": "test"}),
+			clusterObj: func() *unstructured.Unstructured {
+				return newDeployment(5, "nginx:2", map[string]string{"app.kubernetes.io/name": "other"})
+			},
+			expectedFields: []string{
+				"metadata.labels[app.kubernetes.io/name]",
+				"spec.replicas",
+				"spec.template.spec.containers[0].image",
+			},
+		},
+		"removed list items are reported": {
+			desiredObj: newDeployment(1, "nginx:1", nil),
+			clusterObj: func() *unstructured.Unstructured {
+				obj := newDeployment(1, "nginx:1", nil)
+				_ = unstructured.SetNestedSlice(obj.Object, []interface{}{}, "spec", "template", "spec", "containers")
+				return obj
+			},
+			expectedFields: []string{"spec.template.spec.containers"},
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			g.Expect(driftedFields(testCase.desiredObj, testCase.clusterObj())).To(gomega.Equal(testCase.expectedFields))
+		})
+	}
+}
+
+func TestDriftPolicyForObject(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	fedObject := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	g.Expect(driftPolicyForObject(fedObject)).To(gomega.Equal(fedcorev1a1.DriftPolicyCorrect))
+
+	fedObject.SetAnnotations(map[string]string{common.DriftPolicyAnnotation: string(fedcorev1a1.DriftPolicyReport)})
+	g.Expect(driftPolicyForObject(fedObject)).To(gomega.Equal(fedcorev1a1.DriftPolicyReport))
+}
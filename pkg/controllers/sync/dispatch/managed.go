@@ -95,6 +95,7 @@ type managedDispatcherImpl struct {
 	fedResource           FederatedResourceForDispatch
 	versionMap            map[string]string
 	statusMap             status.PropagationStatusMap
+	driftMap              map[string][]string
 	skipAdoptingResources bool
 
 	// Track when resource updates are performed to allow indicating
@@ -115,6 +116,7 @@ func NewManagedDispatcher(
 		fedResource:           fedResource,
 		versionMap:            make(map[string]string),
 		statusMap:             make(status.PropagationStatusMap),
+		driftMap:              make(map[string][]string),
 		skipAdoptingResources: skipAdoptingResources,
 		metrics:               metrics,
 	}
@@ -351,7 +353,11 @@ func (d *managedDispatcherImpl) Create(ctx context.Context, clusterName string)
 		defer cancel()
 
 		keyedLogger.V(1).Info("Creating target object in cluster")
-		err = client.Create(ctxWithTimeout, obj)
+		if d.fedResource.TypeConfig().GetServerSideApplyEnabled() {
+			err = createObjectByApply(ctxWithTimeout, client, obj)
+		} else {
+			err = client.Create(ctxWithTimeout, obj)
+		}
 		if err == nil {
 			version := util.ObjectVersion(obj)
 			d.recordVersion(clusterName, version)
@@ -427,10 +433,21 @@ func (d *managedDispatcherImpl) Update(ctx context.Context, clusterName string,
 
 		recordPropagatedLabelsAndAnnotations(obj)
 
-		err = RetainOrMergeClusterFields(d.fedResource.TargetGVK(), obj, clusterObj, d.fedResource.Object())
-		if err != nil {
-			wrappedErr := errors.Wrapf(err, "failed to retain fields")
-			return d.recordOperationError(ctx, fedtypesv1a1.FieldRetentionFailed, clusterName, op, wrappedErr)
+		// With server-side apply, fields set by controllers in member clusters are not included in the applied
+		// object and are left untouched, so they need not be retained.
+		serverSideApply := d.fedResource.TypeConfig().GetServerSideApplyEnabled()
+		if !serverSideApply {
+			err = RetainOrMergeClusterFields(
+				d.fedResource.TargetGVK(),
+				obj,
+				clusterObj,
+				d.fedResource.Object(),
+				d.fedResource.TypeConfig().Spec.RetainFields,
+			)
+			if err != nil {
+				wrappedErr := errors.Wrapf(err, "failed to retain fields")
+				return d.recordOperationError(ctx, fedtypesv1a1.FieldRetentionFailed, clusterName, op, wrappedErr)
+			}
 		}
 
 		err = retainReplicas(obj, clusterObj, d.fedResource.Object(), d.fedResource.TypeConfig())
@@ -459,11 +476,27 @@ func (d *managedDispatcherImpl) Update(ctx context.Context, clusterName string,
 			return true
 		}
 
+		// The recorded version is only kept while the desired object is unchanged, so if the object needs an
+		// update despite having a recorded version, it has been changed in the cluster.
+		if version != "" {
+			if fields := driftedFields(obj, clusterObj); len(fields) > 0 && !d.handleDrift(clusterName, fields) {
+				return true
+			}
+		}
+
 		// Only record an event if the resource is not current
 		d.recordEvent(clusterName, op, "Updating")
 
-		keyedLogger.V(1).Info("Updating target object in cluster")
-		err = client.Update(ctx, obj)
+		if serverSideApply {
+			keyedLogger.V(1).Info("Applying target object in cluster")
+			err = applyObject(ctx, client, obj, clusterObj)
+			if apierrors.IsConflict(err) {
+				return d.recordOperationError(ctx, fedtypesv1a1.ApplyConflict, clusterName, op, err)
+			}
+		} else {
+			keyedLogger.V(1).Info("Updating target object in cluster")
+			err = client.Update(ctx, obj)
+		}
 		if err != nil {
 			return d.recordOperationError(ctx, fedtypesv1a1.UpdateFailed, clusterName, op, err)
 		}
@@ -474,6 +507,30 @@ func (d *managedDispatcherImpl) Update(ctx context.Context, clusterName string,
 	})
 }
 
+// handleDrift records the drifted fields of the object in the cluster and returns whether they should be corrected
+// according to the drift policy of the federated object.
+func (d *managedDispatcherImpl) handleDrift(clusterName string, fields []string) bool {
+	d.Lock()
+	d.driftMap[clusterName] = fields
+	d.Unlock()
+
+	targetName := d.unmanagedDispatcher.targetNameForCluster(clusterName)
+	if driftPolicyForObject(d.fedResource.Object()) == fedcorev1a1.DriftPolicyReport {
+		d.fedResource.RecordError(string(fedtypesv1a1.Drifted), errors.Errorf(
+			"%s %q in cluster %q has drifted from the desired state in fields %s",
+			d.fedResource.TargetKind(), targetName, clusterName, strings.Join(fields, ", "),
+		))
+		d.RecordStatus(clusterName, fedtypesv1a1.Drifted)
+		return false
+	}
+
+	d.fedResource.RecordError(string(fedtypesv1a1.Drifted), errors.Errorf(
+		"Correcting drifted fields %s of %s %q in cluster %q",
+		strings.Join(fields, ", "), d.fedResource.TargetKind(), targetName, clusterName,
+	))
+	return true
+}
+
 func (d *managedDispatcherImpl) Delete(ctx context.Context, clusterName string, clusterObj *unstructured.Unstructured) {
 	d.RecordStatus(clusterName, fedtypesv1a1.DeletionTimedOut)
 
@@ -513,7 +570,13 @@ func (d *managedDispatcherImpl) PatchAndKeepTemplate(
 
 		recordPropagatedLabelsAndAnnotations(obj)
 
-		err = RetainOrMergeClusterFields(d.fedResource.TargetGVK(), obj, clusterObj, d.fedResource.Object())
+		err = RetainOrMergeClusterFields(
+			d.fedResource.TargetGVK(),
+			obj,
+			clusterObj,
+			d.fedResource.Object(),
+			d.fedResource.TypeConfig().Spec.RetainFields,
+		)
 		if err != nil {
 			wrappedErr := errors.Wrapf(err, "failed to retain fields")
 			return d.recordOperationError(ctx, fedtypesv1a1.FieldRetentionFailed, clusterName, op, wrappedErr)
@@ -646,9 +709,14 @@ func (d *managedDispatcherImpl) CollectedStatus() status.CollectedPropagationSta
 	for key, value := range d.statusMap {
 		statusMap[key] = value
 	}
+	driftMap := make(map[string][]string, len(d.driftMap))
+	for key, value := range d.driftMap {
+		driftMap[key] = value
+	}
 	return status.CollectedPropagationStatus{
 		StatusMap:        statusMap,
 		GenerationMap:    util.ConvertVersionMapToGenerationMap(d.versionMap),
+		DriftMap:         driftMap,
 		ResourcesUpdated: d.resourcesUpdated,
 	}
 }
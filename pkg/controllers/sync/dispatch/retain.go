@@ -45,10 +45,12 @@ const (
 )
 
 // RetainOrMergeClusterFields updates the desired object with values retained
-// from the cluster object.
+// from the cluster object, including the given fields configured in the
+// FederatedTypeConfig.
 func RetainOrMergeClusterFields(
 	targetGvk schema.GroupVersionKind,
 	desiredObj, clusterObj, fedObj *unstructured.Unstructured,
+	fields []fedcorev1a1.RetainField,
 ) error {
 	// Pass the same ResourceVersion as in the cluster object for update operation, otherwise operation will fail.
 	desiredObj.SetResourceVersion(clusterObj.GetResourceVersion())
@@ -93,7 +95,7 @@ func RetainOrMergeClusterFields(
 		}
 	}
 
-	return nil
+	return retainFields(desiredObj, clusterObj, fields)
 }
 
 func recordPropagatedLabelsAndAnnotations(obj *unstructured.Unstructured) {
@@ -0,0 +1,197 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+// retainPathSegment is a field name in the path of a RetainField.
+type retainPathSegment struct {
+	field string
+	// list indicates that the field is a list whose items are matched by listKeys, or by their indices if listKeys
+	// is empty.
+	list     bool
+	listKeys []string
+}
+
+// parseRetainPath parses the path of a RetainField, e.g. `spec.ports[name,protocol].nodePort`.
+func parseRetainPath(path string) ([]retainPathSegment, error) {
+	if path == "" {
+		return nil, fmt.Errorf("empty path")
+	}
+
+	var segments []retainPathSegment
+	for _, part := range strings.Split(path, ".") {
+		segment := retainPathSegment{field: part}
+		if i := strings.IndexByte(part, '['); i >= 0 {
+			if !strings.HasSuffix(part, "]") {
+				return nil, fmt.Errorf("invalid path %q: unterminated list selector in %q", path, part)
+			}
+			segment.field = part[:i]
+			segment.list = true
+			if keys := part[i+1 : len(part)-1]; keys != "*" {
+				for _, key := range strings.Split(keys, ",") {
+					if key = strings.TrimSpace(key); key == "" {
+						return nil, fmt.Errorf("invalid path %q: empty list key in %q", path, part)
+					}
+					segment.listKeys = append(segment.listKeys, key)
+				}
+			}
+		}
+		if segment.field == "" {
+			return nil, fmt.Errorf("invalid path %q: empty field name", path)
+		}
+		segments = append(segments, segment)
+	}
+	return segments, nil
+}
+
+// retainFields updates the desired object with the values of the given fields in the cluster object.
+func retainFields(desiredObj, clusterObj *unstructured.Unstructured, fields []fedcorev1a1.RetainField) error {
+	for _, field := range fields {
+		segments, err := parseRetainPath(field.Path)
+		if err != nil {
+			return err
+		}
+		retainFieldValue(desiredObj.Object, clusterObj.Object, segments)
+	}
+	return nil
+}
+
+func retainFieldValue(desired, cluster map[string]interface{}, segments []retainPathSegment) {
+	segment := segments[0]
+	last := len(segments) == 1
+
+	clusterValue, ok := cluster[segment.field]
+	if !ok {
+		return
+	}
+
+	if last && (!segment.list || len(segment.listKeys) == 0) {
+		desired[segment.field] = mergeRetainedValue(desired[segment.field], clusterValue)
+		return
+	}
+
+	if !segment.list {
+		clusterMap, ok := clusterValue.(map[string]interface{})
+		if !ok {
+			return
+		}
+		desiredValue, exists := desired[segment.field]
+		desiredMap, ok := desiredValue.(map[string]interface{})
+		if !ok {
+			if exists && desiredValue != nil {
+				// the desired value is not a map and takes precedence
+				return
+			}
+			desiredMap = map[string]interface{}{}
+			desired[segment.field] = desiredMap
+		}
+		retainFieldValue(desiredMap, clusterMap, segments[1:])
+		return
+	}
+
+	clusterList, ok := clusterValue.([]interface{})
+	if !ok {
+		return
+	}
+	desiredList, _ := desired[segment.field].([]interface{})
+
+	matched := make([]bool, len(clusterList))
+	for i, desiredItem := range desiredList {
+		j := matchListItem(desiredItem, i, clusterList, segment.listKeys)
+		if j < 0 {
+			continue
+		}
+		matched[j] = true
+		if last {
+			continue
+		}
+		desiredMap, ok := desiredItem.(map[string]interface{})
+		clusterMap, ok2 := clusterList[j].(map[string]interface{})
+		if ok && ok2 {
+			retainFieldValue(desiredMap, clusterMap, segments[1:])
+		}
+	}
+
+	if last {
+		// merge keyed lists by appending the unmatched items of the cluster object
+		for j, clusterItem := range clusterList {
+			if !matched[j] {
+				desiredList = append(desiredList, runtime.DeepCopyJSONValue(clusterItem))
+			}
+		}
+		desired[segment.field] = desiredList
+	}
+}
+
+// matchListItem returns the index of the item in clusterList that matches the desired item at index i by the given
+// keys, or by index if keys is empty. Returns -1 if no item matches.
+func matchListItem(desiredItem interface{}, i int, clusterList []interface{}, keys []string) int {
+	if len(keys) == 0 {
+		if i < len(clusterList) {
+			return i
+		}
+		return -1
+	}
+
+	desiredMap, ok := desiredItem.(map[string]interface{})
+	if !ok {
+		return -1
+	}
+	for j, clusterItem := range clusterList {
+		clusterMap, ok := clusterItem.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		match := true
+		for _, key := range keys {
+			if !reflect.DeepEqual(desiredMap[key], clusterMap[key]) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return j
+		}
+	}
+	return -1
+}
+
+// mergeRetainedValue returns the value to retain from the cluster object. Maps are merged with the desired entries
+// taking precedence, other values are replaced by the cluster value.
+func mergeRetainedValue(desired, cluster interface{}) interface{} {
+	desiredMap, ok := desired.(map[string]interface{})
+	clusterMap, ok2 := cluster.(map[string]interface{})
+	if !ok || !ok2 {
+		return runtime.DeepCopyJSONValue(cluster)
+	}
+
+	merged := runtime.DeepCopyJSONValue(clusterMap).(map[string]interface{})
+	for key, value := range desiredMap {
+		merged[key] = value
+	}
+	return merged
+}
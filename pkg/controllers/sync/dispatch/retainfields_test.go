@@ -0,0 +1,168 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatch
+
+import (
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+func TestRetainFields(t *testing.T) {
+	testCases := map[string]struct {
+		path     string
+		desired  map[string]interface{}
+		cluster  map[string]interface{}
+		expected map[string]interface{}
+	}{
+		"scalar is retained": {
+			path:     "spec.clusterIP",
+			desired:  map[string]interface{}{"spec": map[string]interface{}{"type": "ClusterIP"}},
+			cluster:  map[string]interface{}{"spec": map[string]interface{}{"clusterIP": "10.0.0.1"}},
+			expected: map[string]interface{}{"spec": map[string]interface{}{"type": "ClusterIP", "clusterIP": "10.0.0.1"}},
+		},
+		"missing field in cluster is not retained": {
+			path:     "spec.clusterIP",
+			desired:  map[string]interface{}{"spec": map[string]interface{}{"clusterIP": "None"}},
+			cluster:  map[string]interface{}{"spec": map[string]interface{}{}},
+			expected: map[string]interface{}{"spec": map[string]interface{}{"clusterIP": "None"}},
+		},
+		"missing parents in desired object are created": {
+			path:     "spec.secretName.value",
+			desired:  map[string]interface{}{},
+			cluster:  map[string]interface{}{"spec": map[string]interface{}{"secretName": map[string]interface{}{"value": "a"}}},
+			expected: map[string]interface{}{"spec": map[string]interface{}{"secretName": map[string]interface{}{"value": "a"}}},
+		},
+		"maps are merged": {
+			path: "spec.selector",
+			desired: map[string]interface{}{"spec": map[string]interface{}{
+				"selector": map[string]interface{}{"app": "desired", "tier": "web"},
+			}},
+			cluster: map[string]interface{}{"spec": map[string]interface{}{
+				"selector": map[string]interface{}{"app": "cluster", "controller-uid": "123"},
+			}},
+			expected: map[string]interface{}{"spec": map[string]interface{}{
+				"selector": map[string]interface{}{"app": "desired", "tier": "web", "controller-uid": "123"},
+			}},
+		},
+		"fields of keyed list items are retained": {
+			path: "spec.ports[name,protocol].nodePort",
+			desired: map[string]interface{}{"spec": map[string]interface{}{"ports": []interface{}{
+				map[string]interface{}{"name": "http", "protocol": "TCP", "port": int64(80)},
+				map[string]interface{}{"name": "dns", "protocol": "UDP", "port": int64(53)},
+			}}},
+			cluster: map[string]interface{}{"spec": map[string]interface{}{"ports": []interface{}{
+				map[string]interface{}{"name": "dns", "protocol": "UDP", "port": int64(53), "nodePort": int64(30053)},
+				map[string]interface{}{"name": "http", "protocol": "TCP", "port": int64(80), "nodePort": int64(30080)},
+			}}},
+			expected: map[string]interface{}{"spec": map[string]interface{}{"ports": []interface{}{
+				map[string]interface{}{"name": "http", "protocol": "TCP", "port": int64(80), "nodePort": int64(30080)},
+				map[string]interface{}{"name": "dns", "protocol": "UDP", "port": int64(53), "nodePort": int64(30053)},
+			}}},
+		},
+		"keyed lists are merged": {
+			path: "spec.containers[name]",
+			desired: map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{
+				map[string]interface{}{"name": "app", "image": "app:2"},
+			}}},
+			cluster: map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{
+				map[string]interface{}{"name": "app", "image": "app:1"},
+				map[string]interface{}{"name": "istio-proxy", "image": "proxyv2"},
+			}}},
+			expected: map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{
+				map[string]interface{}{"name": "app", "image": "app:2"},
+				map[string]interface{}{"name": "istio-proxy", "image": "proxyv2"},
+			}}},
+		},
+		"list items are matched by index": {
+			path: "spec.volumes[*].secret",
+			desired: map[string]interface{}{"spec": map[string]interface{}{"volumes": []interface{}{
+				map[string]interface{}{"name": "a"},
+				map[string]interface{}{"name": "b"},
+			}}},
+			cluster: map[string]interface{}{"spec": map[string]interface{}{"volumes": []interface{}{
+				map[string]interface{}{"name": "a", "secret": "s"},
+			}}},
+			expected: map[string]interface{}{"spec": map[string]interface{}{"volumes": []interface{}{
+				map[string]interface{}{"name": "a", "secret": "s"},
+				map[string]interface{}{"name": "b"},
+			}}},
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+
+			desiredObj := &unstructured.Unstructured{Object: testCase.desired}
+			clusterObj := &unstructured.Unstructured{Object: testCase.cluster}
+			err := retainFields(desiredObj, clusterObj, []fedcorev1a1.RetainField{{Path: testCase.path}})
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(desiredObj.Object).To(gomega.Equal(testCase.expected))
+		})
+	}
+}
+
+func TestParseRetainPath(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	segments, err := parseRetainPath("spec.ports[name, protocol].nodePort")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(segments).To(gomega.Equal([]retainPathSegment{
+		{field: "spec"},
+		{field: "ports", list: true, listKeys: []string{"name", "protocol"}},
+		{field: "nodePort"},
+	}))
+
+	for _, path := range []string{"", "spec..clusterIP", "spec.ports[name", "spec.ports[name,]", "[name]"} {
+		_, err := parseRetainPath(path)
+		g.Expect(err).To(gomega.HaveOccurred(), path)
+	}
+}
+
+// TestRetainFieldPathPattern verifies that the CRD of FederatedTypeConfigs rejects paths that cannot be parsed, so that
+// malformed paths are reported when the FederatedTypeConfig is created instead of failing every update.
+func TestRetainFieldPathPattern(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	data, err := os.ReadFile("../../../../config/crds/core.kubeadmiral.io_federatedtypeconfigs.yaml")
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	g.Expect(yaml.UnmarshalStrict(data, crd)).To(gomega.Succeed())
+	g.Expect(crd.Spec.Versions).To(gomega.HaveLen(1))
+
+	schema := crd.Spec.Versions[0].Schema.OpenAPIV3Schema
+	path := schema.Properties["spec"].Properties["retainFields"].Items.Schema.Properties["path"]
+	g.Expect(path.Pattern).NotTo(gomega.BeEmpty())
+	pattern := regexp.MustCompile(path.Pattern)
+
+	for _, path := range []string{"spec.clusterIP", "spec.ports[name, protocol].nodePort", "spec.ports[*]", "metadata"} {
+		g.Expect(pattern.MatchString(path)).To(gomega.BeTrue(), path)
+		_, err := parseRetainPath(path)
+		g.Expect(err).NotTo(gomega.HaveOccurred(), path)
+	}
+	for _, path := range []string{"", "spec..clusterIP", "spec.ports[name", "spec.ports[name,]", "[name]", "spec.ports[]"} {
+		g.Expect(pattern.MatchString(path)).To(gomega.BeFalse(), path)
+	}
+}
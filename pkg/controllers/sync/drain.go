@@ -0,0 +1,140 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	utilunstructured "github.com/kubewharf/kubeadmiral/pkg/controllers/util/unstructured"
+)
+
+// drainState is the result of checking whether an object can be removed from draining clusters.
+type drainState int
+
+const (
+	// drainAllowed means the object can be removed from the draining clusters.
+	drainAllowed drainState = iota
+	// drainWaiting means the object is kept in the draining clusters until its replicas in the selected clusters are
+	// available.
+	drainWaiting
+	// drainBlocked means the object is kept in the draining clusters because it is not placed in any other cluster.
+	drainBlocked
+)
+
+// drainChecker determines whether an object can be removed from draining clusters. To avoid disruptions, an object
+// is only removed from a draining cluster once it is placed in another cluster, and an object with replicas only once
+// its replicas in the selected clusters are available. PodDisruptionBudgets in the draining clusters are not checked,
+// since removing the object deletes all of its pods at once rather than evicting them.
+type drainChecker struct {
+	controller               *SyncController
+	fedResource              FederatedResource
+	selectedClusterNames     sets.String
+	selectedPullClusterNames sets.String
+
+	checked bool
+	state   drainState
+	err     error
+}
+
+func (s *SyncController) newDrainChecker(
+	fedResource FederatedResource,
+	selectedClusterNames sets.String,
+	selectedPullClusterNames sets.String,
+) *drainChecker {
+	return &drainChecker{
+		controller:               s,
+		fedResource:              fedResource,
+		selectedClusterNames:     selectedClusterNames,
+		selectedPullClusterNames: selectedPullClusterNames,
+	}
+}
+
+// check returns whether the object can be removed from the draining clusters. The result is computed once and shared
+// by all the draining clusters of the object.
+func (c *drainChecker) check(ctx context.Context) (drainState, error) {
+	if !c.checked {
+		c.state, c.err = c.computeState(ctx)
+		c.checked = true
+	}
+	return c.state, c.err
+}
+
+func (c *drainChecker) computeState(ctx context.Context) (drainState, error) {
+	// Draining clusters are never selected, so the object would be removed from the federation entirely if it has no
+	// other placement, e.g. if the draining cluster is the only cluster matching its policy.
+	if c.selectedClusterNames.Len() == 0 && c.selectedPullClusterNames.Len() == 0 {
+		return drainBlocked, nil
+	}
+
+	available, err := c.replicasAvailableInSelectedClusters(ctx)
+	if err != nil || !available {
+		return drainWaiting, err
+	}
+	return drainAllowed, nil
+}
+
+// replicasAvailableInSelectedClusters returns true if the replicas of the object are available in the selected push
+// clusters. The sync controller does not observe the objects in pull-mode clusters, so their replicas are not waited
+// for.
+func (c *drainChecker) replicasAvailableInSelectedClusters(ctx context.Context) (bool, error) {
+	typeConfig := c.controller.typeConfig
+	pathDefinition := typeConfig.Spec.PathDefinition
+	if len(pathDefinition.ReplicasSpec) == 0 || len(pathDefinition.AvailableReplicasStatus) == 0 {
+		// there are no replicas to wait for
+		return true, nil
+	}
+
+	for _, clusterName := range c.selectedClusterNames.List() {
+		// The desired replicas are taken from the federated object since the cached cluster object might not yet
+		// reflect the replicas moved to the cluster.
+		desiredReplicas, _, err := c.fedResource.ReplicasOverrideForCluster(clusterName)
+		if err != nil {
+			return false, err
+		}
+		if desiredReplicas == 0 {
+			continue
+		}
+
+		clusterObj, _, err := util.GetClusterObject(
+			ctx,
+			c.controller.informer,
+			clusterName,
+			c.fedResource.TargetName(),
+			typeConfig.GetTargetType(),
+		)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to get cluster object from %s", clusterName)
+		}
+		if clusterObj == nil {
+			return false, nil
+		}
+
+		availableReplicas, err := utilunstructured.GetInt64FromPath(clusterObj, pathDefinition.AvailableReplicasStatus, nil)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to get available replicas in %s", clusterName)
+		}
+		if availableReplicas == nil || *availableReplicas < int64(desiredReplicas) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+)
+
+func TestDrainCheckerWithoutReplicas(t *testing.T) {
+	controller := &SyncController{typeConfig: &fedcorev1a1.FederatedTypeConfig{}}
+
+	testCases := map[string]struct {
+		selectedClusters     sets.String
+		selectedPullClusters sets.String
+		expectedState        drainState
+	}{
+		"not placed in any other cluster": {
+			selectedClusters:     sets.NewString(),
+			selectedPullClusters: sets.NewString(),
+			expectedState:        drainBlocked,
+		},
+		"placed in another push cluster": {
+			selectedClusters:     sets.NewString("cluster-2"),
+			selectedPullClusters: sets.NewString(),
+			expectedState:        drainAllowed,
+		},
+		"placed in another pull cluster": {
+			selectedClusters:     sets.NewString(),
+			selectedPullClusters: sets.NewString("cluster-3"),
+			expectedState:        drainAllowed,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			checker := controller.newDrainChecker(nil, tc.selectedClusters, tc.selectedPullClusters)
+			state, err := checker.check(context.Background())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(state).To(gomega.Equal(tc.expectedState))
+		})
+	}
+}
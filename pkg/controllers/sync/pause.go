@@ -0,0 +1,203 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+)
+
+// pauseChecker determines whether the propagation of federated objects is paused, which is the case if any of the
+// following holds:
+//   - The federated object has the paused annotation.
+//   - The namespace of the federated object has the paused label.
+//   - The propagation-pause ConfigMap pauses all federated objects, or the controller manager was started with
+//     propagation paused.
+type pauseChecker struct {
+	globallyPaused bool
+
+	// The namespace of the propagation-pause ConfigMap.
+	fedSystemNamespace string
+
+	// The shared informer for namespaces. Nil for a namespace-scoped control plane, which may not list namespaces.
+	namespaceInformer cache.SharedIndexInformer
+	namespaceLister   corev1listers.NamespaceLister
+
+	// The shared informer for ConfigMaps, which contains the propagation-pause ConfigMap.
+	configMapInformer cache.SharedIndexInformer
+	configMapLister   corev1listers.ConfigMapLister
+
+	enqueueNamespace func(namespace string)
+	enqueueAll       func()
+
+	logger klog.Logger
+}
+
+func newPauseChecker(
+	logger klog.Logger,
+	controllerConfig *util.ControllerConfig,
+	namespaceInformer corev1informers.NamespaceInformer,
+	configMapInformer corev1informers.ConfigMapInformer,
+	enqueueNamespace func(namespace string),
+	enqueueAll func(),
+) *pauseChecker {
+	c := &pauseChecker{
+		globallyPaused:     controllerConfig.PausePropagation,
+		fedSystemNamespace: controllerConfig.FedSystemNamespace,
+		configMapInformer:  configMapInformer.Informer(),
+		configMapLister:    configMapInformer.Lister(),
+		enqueueNamespace:   enqueueNamespace,
+		enqueueAll:         enqueueAll,
+		logger:             logger.WithValues("origin", "pause-checker"),
+	}
+
+	if !controllerConfig.LimitedScope() {
+		c.namespaceInformer = namespaceInformer.Informer()
+		c.namespaceLister = namespaceInformer.Lister()
+	}
+
+	return c
+}
+
+// Run registers the event handlers of the pause checker with the shared informers, which are started by the
+// controller manager, and removes them once the stop channel is closed.
+func (c *pauseChecker) Run(stopChan <-chan struct{}) {
+	var removeEventHandlers []func()
+	addEventHandler := func(informer cache.SharedIndexInformer, handler cache.ResourceEventHandler) {
+		registration, err := informer.AddEventHandler(handler)
+		if err != nil {
+			c.logger.Error(err, "Failed to add event handler")
+			return
+		}
+		removeEventHandlers = append(removeEventHandlers, func() {
+			if err := informer.RemoveEventHandler(registration); err != nil {
+				c.logger.Error(err, "Failed to remove event handler")
+			}
+		})
+	}
+
+	if c.namespaceInformer != nil {
+		addEventHandler(c.namespaceInformer, cache.FilteringResourceEventHandler{
+			FilterFunc: func(obj interface{}) bool {
+				namespace, ok := unwrapTombstone(obj).(*corev1.Namespace)
+				return ok && isNamespacePaused(namespace)
+			},
+			Handler: util.NewTriggerOnAllChanges(func(obj runtime.Object) {
+				c.enqueueNamespace(common.NewQualifiedName(obj).Name)
+			}),
+		})
+	}
+	addEventHandler(c.configMapInformer, cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			configMap, ok := unwrapTombstone(obj).(*corev1.ConfigMap)
+			return ok && configMap.Namespace == c.fedSystemNamespace &&
+				configMap.Name == common.PropagationPauseConfigMapName
+		},
+		Handler: util.NewTriggerOnAllChanges(func(_ runtime.Object) {
+			c.enqueueAll()
+		}),
+	})
+
+	go func() {
+		<-stopChan
+		for _, removeEventHandler := range removeEventHandlers {
+			removeEventHandler()
+		}
+	}()
+}
+
+func (c *pauseChecker) HasSynced() bool {
+	if c.namespaceInformer != nil && !c.namespaceInformer.HasSynced() {
+		return false
+	}
+	return c.configMapInformer.HasSynced()
+}
+
+// pausedReason returns the reason for which the propagation of the federated object is paused, or
+// fedtypesv1a1.AggregateSuccess if it is not paused. namespace is the namespace of the target object, which is the
+// object itself for namespaces.
+func (c *pauseChecker) pausedReason(fedObject *unstructured.Unstructured, namespace string) fedtypesv1a1.AggregateReason {
+	if fedObject.GetAnnotations()[common.PausedAnnotation] == common.AnnotationValueTrue {
+		return fedtypesv1a1.ObjectPaused
+	}
+	return c.namespacePausedReason(namespace)
+}
+
+// namespacePausedReason returns the reason for which the propagation of the objects in the namespace is paused, or
+// fedtypesv1a1.AggregateSuccess if it is not paused.
+func (c *pauseChecker) namespacePausedReason(namespace string) fedtypesv1a1.AggregateReason {
+	if c.globallyPaused {
+		return fedtypesv1a1.FederationPaused
+	}
+
+	configMap, err := c.configMapLister.ConfigMaps(c.fedSystemNamespace).Get(common.PropagationPauseConfigMapName)
+	if err == nil && configMap.Data[common.PropagationPausedKey] == common.AnnotationValueTrue {
+		return fedtypesv1a1.FederationPaused
+	}
+
+	if c.namespaceLister != nil && namespace != "" {
+		if ns, err := c.namespaceLister.Get(namespace); err == nil && isNamespacePaused(ns) {
+			return fedtypesv1a1.NamespacePaused
+		}
+	}
+
+	return fedtypesv1a1.AggregateSuccess
+}
+
+// unwrapTombstone returns the last known state of the object if obj is the tombstone of a deleted object.
+func unwrapTombstone(obj interface{}) interface{} {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		return tombstone.Obj
+	}
+	return obj
+}
+
+func isNamespacePaused(namespace *corev1.Namespace) bool {
+	return namespace.Labels[common.PausedLabel] == common.AnnotationValueTrue
+}
+
+// IsPropagationPaused returns true if the propagation of the federated object is paused, as indicated by its paused
+// annotation or by the Paused condition set by the sync controller. It is used by the agents of pull-mode clusters,
+// which do not observe the namespaces and the propagation-pause ConfigMap of the host cluster.
+func IsPropagationPaused(fedObject *unstructured.Unstructured) (bool, error) {
+	if fedObject.GetAnnotations()[common.PausedAnnotation] == common.AnnotationValueTrue {
+		return true, nil
+	}
+
+	resource := &fedtypesv1a1.GenericObjectWithStatus{}
+	if err := util.UnstructuredToInterface(fedObject, resource); err != nil {
+		return false, err
+	}
+	if resource.Status == nil {
+		return false, nil
+	}
+	for _, condition := range resource.Status.Conditions {
+		if condition.Type == fedtypesv1a1.PausedConditionType {
+			return condition.Status == corev1.ConditionTrue, nil
+		}
+	}
+	return false, nil
+}
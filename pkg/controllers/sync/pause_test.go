@@ -0,0 +1,169 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+func TestPausedReason(t *testing.T) {
+	pausedNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "paused",
+			Labels: map[string]string{common.PausedLabel: common.AnnotationValueTrue},
+		},
+	}
+	pauseConfigMap := func(paused string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: common.DefaultFedSystemNamespace,
+				Name:      common.PropagationPauseConfigMapName,
+			},
+			Data: map[string]string{common.PropagationPausedKey: paused},
+		}
+	}
+
+	testCases := map[string]struct {
+		globallyPaused bool
+		configMap      *corev1.ConfigMap
+		annotations    map[string]string
+		namespace      string
+		expectedReason fedtypesv1a1.AggregateReason
+	}{
+		"not paused": {
+			namespace:      "default",
+			expectedReason: fedtypesv1a1.AggregateSuccess,
+		},
+		"paused by annotation": {
+			annotations:    map[string]string{common.PausedAnnotation: common.AnnotationValueTrue},
+			namespace:      "default",
+			expectedReason: fedtypesv1a1.ObjectPaused,
+		},
+		"annotation not set to true": {
+			annotations:    map[string]string{common.PausedAnnotation: common.AnnotationValueFalse},
+			namespace:      "default",
+			expectedReason: fedtypesv1a1.AggregateSuccess,
+		},
+		"paused by namespace": {
+			namespace:      "paused",
+			expectedReason: fedtypesv1a1.NamespacePaused,
+		},
+		"paused by ConfigMap": {
+			configMap:      pauseConfigMap(common.AnnotationValueTrue),
+			namespace:      "paused",
+			expectedReason: fedtypesv1a1.FederationPaused,
+		},
+		"ConfigMap not set to true": {
+			configMap:      pauseConfigMap(common.AnnotationValueFalse),
+			namespace:      "default",
+			expectedReason: fedtypesv1a1.AggregateSuccess,
+		},
+		"paused by flag": {
+			globallyPaused: true,
+			namespace:      "default",
+			expectedReason: fedtypesv1a1.FederationPaused,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			namespaceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			configMapIndexer := cache.NewIndexer(
+				cache.MetaNamespaceKeyFunc,
+				cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+			)
+			c := &pauseChecker{
+				globallyPaused:     tc.globallyPaused,
+				fedSystemNamespace: common.DefaultFedSystemNamespace,
+				namespaceLister:    corev1listers.NewNamespaceLister(namespaceIndexer),
+				configMapLister:    corev1listers.NewConfigMapLister(configMapIndexer),
+			}
+			if err := namespaceIndexer.Add(pausedNamespace); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tc.configMap != nil {
+				if err := configMapIndexer.Add(tc.configMap); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			}
+
+			fedObject := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			fedObject.SetAnnotations(tc.annotations)
+
+			if reason := c.pausedReason(fedObject, tc.namespace); reason != tc.expectedReason {
+				t.Fatalf("Expected reason %q, got %q", tc.expectedReason, reason)
+			}
+		})
+	}
+}
+
+func TestIsPropagationPaused(t *testing.T) {
+	testCases := map[string]struct {
+		annotations    map[string]string
+		conditions     []interface{}
+		expectedPaused bool
+	}{
+		"no annotation or condition": {},
+		"paused annotation": {
+			annotations:    map[string]string{common.PausedAnnotation: common.AnnotationValueTrue},
+			expectedPaused: true,
+		},
+		"paused condition": {
+			conditions: []interface{}{
+				map[string]interface{}{"type": "Propagation", "status": "True"},
+				map[string]interface{}{"type": "Paused", "status": "True", "reason": "NamespacePaused"},
+			},
+			expectedPaused: true,
+		},
+		"resumed condition": {
+			conditions: []interface{}{
+				map[string]interface{}{"type": "Paused", "status": "False"},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			fedObject := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			fedObject.SetAPIVersion("types.kubeadmiral.io/v1alpha1")
+			fedObject.SetKind("FederatedDeployment")
+			fedObject.SetAnnotations(tc.annotations)
+			if tc.conditions != nil {
+				fedObject.Object[common.StatusField] = map[string]interface{}{"conditions": tc.conditions}
+			}
+
+			paused, err := IsPropagationPaused(fedObject)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if paused != tc.expectedPaused {
+				t.Fatalf("Expected paused to be %v, got %v", tc.expectedPaused, paused)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/worker"
+)
+
+// ensurePullClusterKeys labels the federated object with the pull-mode clusters it is placed in, so that the agents of
+// these clusters watch it. The labels and agent finalizers of the pull-mode clusters it is no longer placed in are
+// removed, which causes their agents to delete the object from the clusters.
+func (s *SyncController) ensurePullClusterKeys(
+	ctx context.Context,
+	fedResource FederatedResource,
+	selectedPullClusters sets.String,
+) error {
+	obj := fedResource.Object()
+
+	desired := sets.NewString()
+	for clusterName := range selectedPullClusters {
+		desired.Insert(util.PullClusterKey(clusterName))
+	}
+	if !setPullClusterKeys(obj, desired) {
+		return nil
+	}
+
+	klog.FromContext(ctx).WithValues("clusters", strings.Join(selectedPullClusters.List(), ",")).
+		V(1).Info("Updating pull-mode cluster labels of federated object")
+	return s.hostClusterClient.Update(ctx, obj)
+}
+
+// setPullClusterKeys sets the pull-mode cluster labels of the object to the desired keys and removes the finalizers of
+// the agents of other pull-mode clusters. Returns true if the object is changed.
+func setPullClusterKeys(obj *unstructured.Unstructured, desired sets.String) bool {
+	changed := false
+
+	labels := obj.GetLabels()
+	for key := range labels {
+		if strings.HasPrefix(key, common.PullClusterPrefix) && !desired.Has(key) {
+			delete(labels, key)
+			changed = true
+		}
+	}
+	for key := range desired {
+		if _, exists := labels[key]; !exists {
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels[key] = ""
+			changed = true
+		}
+	}
+
+	finalizers := obj.GetFinalizers()
+	remainingFinalizers := make([]string, 0, len(finalizers))
+	for _, finalizer := range finalizers {
+		if strings.HasPrefix(finalizer, common.PullClusterPrefix) && !desired.Has(finalizer) {
+			changed = true
+			continue
+		}
+		remainingFinalizers = append(remainingFinalizers, finalizer)
+	}
+
+	if changed {
+		obj.SetLabels(labels)
+		obj.SetFinalizers(remainingFinalizers)
+	}
+	return changed
+}
+
+// reconcileTerminatingPullCluster waits for the agent of a terminating pull-mode cluster to delete the objects in the
+// cluster if cascading delete is enabled, and then releases the federated objects from the cluster before removing
+// the cascading-delete finalizer.
+func (s *SyncController) reconcileTerminatingPullCluster(
+	ctx context.Context,
+	cluster *fedcorev1a1.FederatedCluster,
+) worker.Result {
+	logger := klog.FromContext(ctx)
+
+	if util.IsClusterJoined(&cluster.Status) && util.IsCascadingDeleteEnabled(cluster) {
+		if _, completed := cluster.GetAnnotations()[common.AgentCascadingDeleteCompletedAnnotation]; !completed {
+			s.eventRecorder.Eventf(
+				cluster,
+				corev1.EventTypeNormal,
+				EventReasonWaitForCascadingDelete,
+				"waiting for the agent to delete %s from the cluster",
+				s.typeConfig.GetTargetType().Name,
+			)
+			return worker.Result{RequeueAfter: &s.cascadingDeletionRecheckDelay}
+		}
+	}
+
+	if err := s.releaseFromPullCluster(ctx, cluster.Name); err != nil {
+		logger.Error(err, "Failed to release federated objects from pull-mode cluster")
+		return worker.StatusError
+	}
+
+	if err := s.removeClusterFinalizer(ctx, cluster); err != nil {
+		logger.Error(err, "Failed to remove cluster finalizer")
+		return worker.StatusError
+	}
+	return worker.StatusAllOK
+}
+
+// releaseFromPullCluster removes the label and the agent finalizer of the pull-mode cluster from all federated objects,
+// so that the objects are not held back by an agent that is going away.
+func (s *SyncController) releaseFromPullCluster(ctx context.Context, clusterName string) error {
+	key := util.PullClusterKey(clusterName)
+
+	var objects []*unstructured.Unstructured
+	s.fedAccessor.VisitFederatedResources(func(obj interface{}) {
+		fedObject := obj.(*unstructured.Unstructured)
+		_, labeled := fedObject.GetLabels()[key]
+		if labeled || sets.NewString(fedObject.GetFinalizers()...).Has(key) {
+			objects = append(objects, fedObject)
+		}
+	})
+
+	for _, fedObject := range objects {
+		qualifiedName := common.NewQualifiedName(fedObject)
+		if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(fedObject.GroupVersionKind())
+			if err := s.hostClusterClient.Get(ctx, obj, qualifiedName.Namespace, qualifiedName.Name); err != nil {
+				return err
+			}
+
+			labels := obj.GetLabels()
+			_, labeled := labels[key]
+			delete(labels, key)
+			obj.SetLabels(labels)
+
+			finalizers := sets.NewString(obj.GetFinalizers()...)
+			if !labeled && !finalizers.Has(key) {
+				return nil
+			}
+			obj.SetFinalizers(removeString(obj.GetFinalizers(), key))
+			return s.hostClusterClient.Update(ctx, obj)
+		}); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to release %s from cluster %s", qualifiedName, clusterName)
+		}
+	}
+	return nil
+}
+
+func removeString(values []string, value string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
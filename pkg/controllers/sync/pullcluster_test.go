@@ -0,0 +1,75 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestSetPullClusterKeys(t *testing.T) {
+	newObject := func(labels map[string]string, finalizers ...string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		obj.SetLabels(labels)
+		obj.SetFinalizers(finalizers)
+		return obj
+	}
+
+	testCases := map[string]struct {
+		obj             *unstructured.Unstructured
+		desired         sets.String
+		expectedChanged bool
+		expectedObj     *unstructured.Unstructured
+	}{
+		"labels are added for new pull clusters": {
+			obj:             newObject(map[string]string{"app": "foo"}),
+			desired:         sets.NewString("pull.kubeadmiral.io/a"),
+			expectedChanged: true,
+			expectedObj:     newObject(map[string]string{"app": "foo", "pull.kubeadmiral.io/a": ""}),
+		},
+		"labels and finalizers of removed pull clusters are removed": {
+			obj: newObject(
+				map[string]string{"pull.kubeadmiral.io/a": "", "pull.kubeadmiral.io/b": ""},
+				"kubeadmiral.io/sync-controller", "pull.kubeadmiral.io/a", "pull.kubeadmiral.io/b",
+			),
+			desired:         sets.NewString("pull.kubeadmiral.io/a"),
+			expectedChanged: true,
+			expectedObj: newObject(
+				map[string]string{"pull.kubeadmiral.io/a": ""},
+				"kubeadmiral.io/sync-controller", "pull.kubeadmiral.io/a",
+			),
+		},
+		"unchanged object": {
+			obj:         newObject(map[string]string{"pull.kubeadmiral.io/a": ""}, "pull.kubeadmiral.io/a"),
+			desired:     sets.NewString("pull.kubeadmiral.io/a"),
+			expectedObj: newObject(map[string]string{"pull.kubeadmiral.io/a": ""}, "pull.kubeadmiral.io/a"),
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			g := gomega.NewWithT(t)
+			g.Expect(setPullClusterKeys(tc.obj, tc.desired)).To(gomega.Equal(tc.expectedChanged))
+			g.Expect(tc.obj.GetLabels()).To(gomega.Equal(tc.expectedObj.GetLabels()))
+			g.Expect(tc.obj.GetFinalizers()).To(gomega.ConsistOf(tc.expectedObj.GetFinalizers()))
+		})
+	}
+}
@@ -82,6 +82,30 @@ type federatedResource struct {
 	eventRecorder     record.EventRecorder
 }
 
+// NewFederatedResourceForDispatch returns a federated resource for dispatching the given federated object to member
+// clusters outside of the sync controller, e.g. by the agent of a pull-mode cluster. Instead of being tracked by a
+// version manager, the versions propagated to member clusters are looked up in the given version map.
+func NewFederatedResourceForDispatch(
+	typeConfig *fedcorev1a1.FederatedTypeConfig,
+	fedObject *unstructured.Unstructured,
+	versionMap map[string]string,
+	eventRecorder record.EventRecorder,
+) dispatch.FederatedResourceForDispatch {
+	if versionMap == nil {
+		versionMap = map[string]string{}
+	}
+	name := common.NewQualifiedName(fedObject)
+	return &federatedResource{
+		typeConfig:        typeConfig,
+		targetName:        name,
+		federatedKind:     typeConfig.GetFederatedType().Kind,
+		federatedName:     name,
+		federatedResource: fedObject,
+		versionMap:        versionMap,
+		eventRecorder:     eventRecorder,
+	}
+}
+
 func (r *federatedResource) FederatedName() common.QualifiedName {
 	return r.federatedName
 }
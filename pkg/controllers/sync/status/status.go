@@ -28,6 +28,7 @@ import (
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
@@ -38,9 +39,15 @@ import (
 type PropagationStatusMap map[string]fedtypesv1a1.PropagationStatus
 
 type CollectedPropagationStatus struct {
-	StatusMap        PropagationStatusMap
-	GenerationMap    map[string]int64
+	StatusMap     PropagationStatusMap
+	GenerationMap map[string]int64
+	// DriftMap contains the drifted fields of the objects in the clusters in which drift was detected.
+	DriftMap         map[string][]string
 	ResourcesUpdated bool
+
+	// AgentClusters are the selected pull-mode clusters. Their status is reported by their agents, so it is
+	// preserved from the existing status of the federated object instead of being collected.
+	AgentClusters []string
 }
 
 // SetFederatedStatus sets the conditions and clusters fields of the
@@ -61,24 +68,159 @@ func SetFederatedStatus(
 		resource.Status = &fedtypesv1a1.GenericFederatedStatus{}
 	}
 
+	if len(collectedStatus.AgentClusters) > 0 {
+		collectedStatus = preserveAgentClusters(resource.Status, collectedStatus)
+	}
+
 	changed := update(resource.Status, fedObject.GetGeneration(), collisionCount, reason, collectedStatus)
+	// The object is synced, so its propagation is no longer paused.
+	changed = setPausedCondition(resource.Status, fedtypesv1a1.AggregateSuccess) || changed
 
 	if !changed {
 		return false, nil
 	}
 
+	if err := setStatusField(fedObject, resource); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SetPausedCondition sets the Paused condition of the federated resource's object map to reflect the given reason,
+// which is AggregateSuccess (the empty reason) if propagation is not paused. Returns a boolean indication of whether
+// status should be written to the API.
+func SetPausedCondition(fedObject *unstructured.Unstructured, reason fedtypesv1a1.AggregateReason) (bool, error) {
+	resource := &fedtypesv1a1.GenericObjectWithStatus{}
+	err := util.UnstructuredToInterface(fedObject, resource)
+	if err != nil {
+		return false, errors.Wrapf(err, "Failed to unmarshall to generic resource")
+	}
+	if resource.Status == nil {
+		resource.Status = &fedtypesv1a1.GenericFederatedStatus{}
+	}
+
+	if !setPausedCondition(resource.Status, reason) {
+		return false, nil
+	}
+
+	if err := setStatusField(fedObject, resource); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SetClusterPropagationStatus sets the propagation status of a single cluster in the clusters field of the federated
+// resource's object map and updates the Propagation condition accordingly. It is used by the agents of pull-mode
+// clusters to report the status of their own cluster. Returns a boolean indication of whether status should be
+// written to the API.
+func SetClusterPropagationStatus(
+	fedObject *unstructured.Unstructured,
+	clusterName string,
+	propStatus fedtypesv1a1.PropagationStatus,
+	generation int64,
+	driftedFields []string,
+) (bool, error) {
+	resource := &fedtypesv1a1.GenericObjectWithStatus{}
+	err := util.UnstructuredToInterface(fedObject, resource)
+	if err != nil {
+		return false, errors.Wrapf(err, "Failed to unmarshall to generic resource")
+	}
+	if resource.Status == nil {
+		resource.Status = &fedtypesv1a1.GenericFederatedStatus{}
+	}
+
+	statusMap := make(PropagationStatusMap, len(resource.Status.Clusters)+1)
+	generationMap := make(map[string]int64, len(resource.Status.Clusters)+1)
+	driftMap := make(map[string][]string, len(resource.Status.Clusters)+1)
+	for _, cluster := range resource.Status.Clusters {
+		statusMap[cluster.Name] = cluster.Status
+		generationMap[cluster.Name] = cluster.Generation
+		driftMap[cluster.Name] = cluster.DriftedFields
+	}
+	statusMap[clusterName] = propStatus
+	generationMap[clusterName] = generation
+	driftMap[clusterName] = driftedFields
+
+	if !setClusters(resource.Status, statusMap, generationMap, driftMap) {
+		return false, nil
+	}
+
+	// Only re-evaluate the condition if the last reason was derived from the cluster statuses.
+	var reason fedtypesv1a1.AggregateReason
+	for _, condition := range resource.Status.Conditions {
+		if condition.Type == fedtypesv1a1.PropagationConditionType {
+			reason = condition.Reason
+		}
+	}
+	if reason == fedtypesv1a1.AggregateSuccess || reason == fedtypesv1a1.CheckClusters {
+		reason = fedtypesv1a1.AggregateSuccess
+		for _, value := range statusMap {
+			if value != fedtypesv1a1.ClusterPropagationOK {
+				reason = fedtypesv1a1.CheckClusters
+				break
+			}
+		}
+	}
+	setPropagationCondition(resource.Status, reason, true)
+
+	if err := setStatusField(fedObject, resource); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// preserveAgentClusters returns a copy of the collected status with the existing statuses of the agent clusters.
+// Agent clusters without an existing status are marked as waiting for their agents.
+func preserveAgentClusters(
+	s *fedtypesv1a1.GenericFederatedStatus,
+	collectedStatus CollectedPropagationStatus,
+) CollectedPropagationStatus {
+	statusMap := make(PropagationStatusMap, len(collectedStatus.StatusMap)+len(collectedStatus.AgentClusters))
+	for clusterName, status := range collectedStatus.StatusMap {
+		statusMap[clusterName] = status
+	}
+	generationMap := make(map[string]int64, len(collectedStatus.GenerationMap)+len(collectedStatus.AgentClusters))
+	for clusterName, generation := range collectedStatus.GenerationMap {
+		generationMap[clusterName] = generation
+	}
+	driftMap := make(map[string][]string, len(collectedStatus.DriftMap)+len(collectedStatus.AgentClusters))
+	for clusterName, fields := range collectedStatus.DriftMap {
+		driftMap[clusterName] = fields
+	}
+
+	existing := make(map[string]fedtypesv1a1.GenericClusterStatus, len(s.Clusters))
+	for _, cluster := range s.Clusters {
+		existing[cluster.Name] = cluster
+	}
+	for _, clusterName := range collectedStatus.AgentClusters {
+		if cluster, ok := existing[clusterName]; ok {
+			statusMap[clusterName] = cluster.Status
+			generationMap[clusterName] = cluster.Generation
+			driftMap[clusterName] = cluster.DriftedFields
+		} else {
+			statusMap[clusterName] = fedtypesv1a1.WaitingForAgent
+			generationMap[clusterName] = 0
+		}
+	}
+
+	collectedStatus.StatusMap = statusMap
+	collectedStatus.GenerationMap = generationMap
+	collectedStatus.DriftMap = driftMap
+	return collectedStatus
+}
+
+func setStatusField(fedObject *unstructured.Unstructured, resource *fedtypesv1a1.GenericObjectWithStatus) error {
 	resourceJSON, err := json.Marshal(resource)
 	if err != nil {
-		return false, errors.Wrapf(err, "Failed to marshall generic status to json")
+		return errors.Wrapf(err, "Failed to marshall generic status to json")
 	}
 	resourceObj := &unstructured.Unstructured{}
 	err = resourceObj.UnmarshalJSON(resourceJSON)
 	if err != nil {
-		return false, errors.Wrapf(err, "Failed to marshall generic resource json to unstructured")
+		return errors.Wrapf(err, "Failed to marshall generic resource json to unstructured")
 	}
 	fedObject.Object[common.StatusField] = resourceObj.Object[common.StatusField]
-
-	return true, nil
+	return nil
 }
 
 // update ensures that the status reflects the given generation, reason
@@ -112,7 +254,12 @@ func update(
 		}
 	}
 
-	clustersChanged := setClusters(s, collectedStatus.StatusMap, collectedStatus.GenerationMap)
+	clustersChanged := setClusters(
+		s,
+		collectedStatus.StatusMap,
+		collectedStatus.GenerationMap,
+		collectedStatus.DriftMap,
+	)
 
 	// Indicate that changes were propagated if either status.clusters
 	// was changed or if existing resources were updated (which could
@@ -126,14 +273,15 @@ func update(
 }
 
 // setClusters sets the status.clusters slice from a propagation status
-// map and generation map. Returns a boolean indication of whether the
-// status.clusters was modified.
+// map, generation map and drift map. Returns a boolean indication of
+// whether the status.clusters was modified.
 func setClusters(
 	s *fedtypesv1a1.GenericFederatedStatus,
 	statusMap PropagationStatusMap,
 	generationMap map[string]int64,
+	driftMap map[string][]string,
 ) bool {
-	if !clustersDiffers(s, statusMap, generationMap) {
+	if !clustersDiffers(s, statusMap, generationMap, driftMap) {
 		return false
 	}
 	s.Clusters = []fedtypesv1a1.GenericClusterStatus{}
@@ -146,20 +294,22 @@ func setClusters(
 	for _, clusterName := range clusterNames {
 		status := statusMap[clusterName]
 		s.Clusters = append(s.Clusters, fedtypesv1a1.GenericClusterStatus{
-			Name:       clusterName,
-			Status:     status,
-			Generation: generationMap[clusterName],
+			Name:          clusterName,
+			Status:        status,
+			Generation:    generationMap[clusterName],
+			DriftedFields: driftMap[clusterName],
 		})
 	}
 	return true
 }
 
 // clustersDiffers checks whether `status.clusters` differs from the
-// given status map and generation map.
+// given status map, generation map and drift map.
 func clustersDiffers(
 	s *fedtypesv1a1.GenericFederatedStatus,
 	statusMap PropagationStatusMap,
 	generationMap map[string]int64,
+	driftMap map[string][]string,
 ) bool {
 	if len(s.Clusters) != len(statusMap) {
 		return true
@@ -174,6 +324,9 @@ func clustersDiffers(
 		if generationMap[status.Name] != status.Generation {
 			return true
 		}
+		if !equality.Semantic.DeepEqual(driftMap[status.Name], status.DriftedFields) {
+			return true
+		}
 	}
 	return false
 }
@@ -227,3 +380,43 @@ func setPropagationCondition(s *fedtypesv1a1.GenericFederatedStatus, reason fedt
 
 	return updateRequired
 }
+
+// setPausedCondition ensures that the Paused condition reflects the given reason. The status of the condition is
+// True unless the reason is AggregateSuccess, which is the empty reason. The condition is only added once the object
+// is paused. Returns a boolean indication of whether the condition was modified.
+func setPausedCondition(s *fedtypesv1a1.GenericFederatedStatus, reason fedtypesv1a1.AggregateReason) bool {
+	paused := reason != fedtypesv1a1.AggregateSuccess
+
+	var pausedCondition *fedtypesv1a1.GenericCondition
+	for _, condition := range s.Conditions {
+		if condition.Type == fedtypesv1a1.PausedConditionType {
+			pausedCondition = condition
+			break
+		}
+	}
+
+	if pausedCondition == nil {
+		if !paused {
+			return false
+		}
+		pausedCondition = &fedtypesv1a1.GenericCondition{
+			Type: fedtypesv1a1.PausedConditionType,
+		}
+		s.Conditions = append(s.Conditions, pausedCondition)
+	}
+
+	newStatus := corev1.ConditionFalse
+	if paused {
+		newStatus = corev1.ConditionTrue
+	}
+	if pausedCondition.Status == newStatus && pausedCondition.Reason == reason {
+		return false
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	pausedCondition.Status = newStatus
+	pausedCondition.Reason = reason
+	pausedCondition.LastTransitionTime = now
+	pausedCondition.LastUpdateTime = now
+	return true
+}
@@ -21,11 +21,14 @@ are Copyright 2023 The KubeAdmiral Authors.
 package status
 
 import (
+	"reflect"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	fedtypesv1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/types/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
 )
 
 func TestGenericPropagationStatusUpdateChanged(t *testing.T) {
@@ -34,6 +37,7 @@ func TestGenericPropagationStatusUpdateChanged(t *testing.T) {
 		collisionCount   *int32
 		reason           fedtypesv1a1.AggregateReason
 		statusMap        PropagationStatusMap
+		driftMap         map[string][]string
 		resourcesUpdated bool
 		expectedChanged  bool
 	}{
@@ -49,6 +53,13 @@ func TestGenericPropagationStatusUpdateChanged(t *testing.T) {
 			resourcesUpdated: true,
 			expectedChanged:  true,
 		},
+		"Drifted fields indicate changed": {
+			statusMap: PropagationStatusMap{
+				"cluster1": fedtypesv1a1.ClusterPropagationOK,
+			},
+			driftMap:        map[string][]string{"cluster1": {"spec.replicas"}},
+			expectedChanged: true,
+		},
 		"Change in clusters indicates changed": {
 			expectedChanged: true,
 		},
@@ -81,6 +92,7 @@ func TestGenericPropagationStatusUpdateChanged(t *testing.T) {
 				StatusMap:        tc.statusMap,
 				ResourcesUpdated: tc.resourcesUpdated,
 				GenerationMap:    map[string]int64{"cluster1": tc.generation},
+				DriftMap:         tc.driftMap,
 			}
 			changed := update(propStatus, tc.generation, tc.collisionCount, tc.reason, collectedStatus)
 			if tc.expectedChanged != changed {
@@ -89,3 +101,224 @@ func TestGenericPropagationStatusUpdateChanged(t *testing.T) {
 		})
 	}
 }
+
+func TestPreserveAgentClusters(t *testing.T) {
+	existingStatus := &fedtypesv1a1.GenericFederatedStatus{
+		Clusters: []fedtypesv1a1.GenericClusterStatus{
+			{Name: "cluster1", Status: fedtypesv1a1.ClusterPropagationOK, Generation: 1},
+			{Name: "agent1", Status: fedtypesv1a1.ClusterPropagationOK, Generation: 3},
+		},
+	}
+	collectedStatus := CollectedPropagationStatus{
+		StatusMap:     PropagationStatusMap{"cluster1": fedtypesv1a1.ClusterPropagationOK},
+		GenerationMap: map[string]int64{"cluster1": 2},
+		AgentClusters: []string{"agent1", "agent2"},
+	}
+
+	preserved := preserveAgentClusters(existingStatus, collectedStatus)
+
+	expectedStatusMap := PropagationStatusMap{
+		"cluster1": fedtypesv1a1.ClusterPropagationOK,
+		"agent1":   fedtypesv1a1.ClusterPropagationOK,
+		"agent2":   fedtypesv1a1.WaitingForAgent,
+	}
+	expectedGenerationMap := map[string]int64{"cluster1": 2, "agent1": 3, "agent2": 0}
+	if !reflect.DeepEqual(preserved.StatusMap, expectedStatusMap) {
+		t.Fatalf("Expected status map %v, got %v", expectedStatusMap, preserved.StatusMap)
+	}
+	if !reflect.DeepEqual(preserved.GenerationMap, expectedGenerationMap) {
+		t.Fatalf("Expected generation map %v, got %v", expectedGenerationMap, preserved.GenerationMap)
+	}
+	if len(collectedStatus.StatusMap) != 1 {
+		t.Fatalf("Expected collected status to be unmodified, got %v", collectedStatus.StatusMap)
+	}
+}
+
+func TestSetClusterPropagationStatus(t *testing.T) {
+	testCases := map[string]struct {
+		reason          fedtypesv1a1.AggregateReason
+		propStatus      fedtypesv1a1.PropagationStatus
+		generation      int64
+		expectedChanged bool
+		expectedReason  fedtypesv1a1.AggregateReason
+	}{
+		"Unchanged cluster status indicates unchanged": {
+			reason:         fedtypesv1a1.CheckClusters,
+			propStatus:     fedtypesv1a1.WaitingForAgent,
+			expectedReason: fedtypesv1a1.CheckClusters,
+		},
+		"Successful propagation to last cluster indicates success": {
+			reason:          fedtypesv1a1.CheckClusters,
+			propStatus:      fedtypesv1a1.ClusterPropagationOK,
+			generation:      1,
+			expectedChanged: true,
+			expectedReason:  fedtypesv1a1.AggregateSuccess,
+		},
+		"Failed propagation indicates check clusters": {
+			reason:          fedtypesv1a1.AggregateSuccess,
+			propStatus:      fedtypesv1a1.ApplyOverridesFailed,
+			expectedChanged: true,
+			expectedReason:  fedtypesv1a1.CheckClusters,
+		},
+		"Other reasons are preserved": {
+			reason:          fedtypesv1a1.NamespaceNotFederated,
+			propStatus:      fedtypesv1a1.ClusterPropagationOK,
+			generation:      1,
+			expectedChanged: true,
+			expectedReason:  fedtypesv1a1.NamespaceNotFederated,
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			resource := &fedtypesv1a1.GenericObjectWithStatus{
+				TypeMeta: metav1.TypeMeta{APIVersion: "types.kubeadmiral.io/v1alpha1", Kind: "FederatedDeployment"},
+				Status: &fedtypesv1a1.GenericFederatedStatus{
+					Clusters: []fedtypesv1a1.GenericClusterStatus{
+						{Name: "cluster1", Status: fedtypesv1a1.ClusterPropagationOK, Generation: 1},
+						{Name: "agent1", Status: fedtypesv1a1.WaitingForAgent},
+					},
+					Conditions: []*fedtypesv1a1.GenericCondition{
+						{
+							Type:   fedtypesv1a1.PropagationConditionType,
+							Status: corev1.ConditionFalse,
+							Reason: tc.reason,
+						},
+					},
+				},
+			}
+			fedObject, err := util.GetUnstructured(resource)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			changed, err := SetClusterPropagationStatus(fedObject, "agent1", tc.propStatus, tc.generation, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tc.expectedChanged != changed {
+				t.Fatalf("Expected changed to be %v, got %v", tc.expectedChanged, changed)
+			}
+			if !changed {
+				return
+			}
+
+			updated := &fedtypesv1a1.GenericObjectWithStatus{}
+			if err := util.UnstructuredToInterface(fedObject, updated); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if reason := updated.Status.Conditions[0].Reason; reason != tc.expectedReason {
+				t.Fatalf("Expected reason %v, got %v", tc.expectedReason, reason)
+			}
+			for _, cluster := range updated.Status.Clusters {
+				if cluster.Name == "agent1" && (cluster.Status != tc.propStatus || cluster.Generation != tc.generation) {
+					t.Fatalf("Expected status %v/%d for agent1, got %v/%d", tc.propStatus, tc.generation, cluster.Status, cluster.Generation)
+				}
+			}
+		})
+	}
+}
+
+func TestSetPausedCondition(t *testing.T) {
+	testCases := map[string]struct {
+		existingCondition *fedtypesv1a1.GenericCondition
+		reason            fedtypesv1a1.AggregateReason
+		expectedChanged   bool
+		expectedCondition *fedtypesv1a1.GenericCondition
+	}{
+		"Unpaused object without condition indicates unchanged": {
+			reason: fedtypesv1a1.AggregateSuccess,
+		},
+		"Empty reason does not pause the object": {
+			reason: "",
+		},
+		"Paused object without condition indicates changed": {
+			reason:          fedtypesv1a1.NamespacePaused,
+			expectedChanged: true,
+			expectedCondition: &fedtypesv1a1.GenericCondition{
+				Type:   fedtypesv1a1.PausedConditionType,
+				Status: corev1.ConditionTrue,
+				Reason: fedtypesv1a1.NamespacePaused,
+			},
+		},
+		"Unchanged reason indicates unchanged": {
+			existingCondition: &fedtypesv1a1.GenericCondition{
+				Type:   fedtypesv1a1.PausedConditionType,
+				Status: corev1.ConditionTrue,
+				Reason: fedtypesv1a1.ObjectPaused,
+			},
+			reason: fedtypesv1a1.ObjectPaused,
+			expectedCondition: &fedtypesv1a1.GenericCondition{
+				Type:   fedtypesv1a1.PausedConditionType,
+				Status: corev1.ConditionTrue,
+				Reason: fedtypesv1a1.ObjectPaused,
+			},
+		},
+		"Resumed object indicates changed": {
+			existingCondition: &fedtypesv1a1.GenericCondition{
+				Type:   fedtypesv1a1.PausedConditionType,
+				Status: corev1.ConditionTrue,
+				Reason: fedtypesv1a1.FederationPaused,
+			},
+			reason:          fedtypesv1a1.AggregateSuccess,
+			expectedChanged: true,
+			expectedCondition: &fedtypesv1a1.GenericCondition{
+				Type:   fedtypesv1a1.PausedConditionType,
+				Status: corev1.ConditionFalse,
+			},
+		},
+	}
+	for testName, tc := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			resource := &fedtypesv1a1.GenericObjectWithStatus{
+				TypeMeta: metav1.TypeMeta{APIVersion: "types.kubeadmiral.io/v1alpha1", Kind: "FederatedDeployment"},
+				Status: &fedtypesv1a1.GenericFederatedStatus{
+					Conditions: []*fedtypesv1a1.GenericCondition{
+						{
+							Type:   fedtypesv1a1.PropagationConditionType,
+							Status: corev1.ConditionTrue,
+						},
+					},
+				},
+			}
+			if tc.existingCondition != nil {
+				resource.Status.Conditions = append(resource.Status.Conditions, tc.existingCondition)
+			}
+			fedObject, err := util.GetUnstructured(resource)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			changed, err := SetPausedCondition(fedObject, tc.reason)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tc.expectedChanged != changed {
+				t.Fatalf("Expected changed to be %v, got %v", tc.expectedChanged, changed)
+			}
+
+			updated := &fedtypesv1a1.GenericObjectWithStatus{}
+			if err := util.UnstructuredToInterface(fedObject, updated); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var pausedCondition *fedtypesv1a1.GenericCondition
+			for _, condition := range updated.Status.Conditions {
+				if condition.Type == fedtypesv1a1.PausedConditionType {
+					pausedCondition = condition
+				}
+			}
+			if tc.expectedCondition == nil {
+				if pausedCondition != nil {
+					t.Fatalf("Expected no paused condition, got %v", pausedCondition)
+				}
+				return
+			}
+			if pausedCondition == nil {
+				t.Fatalf("Expected paused condition, got none")
+			}
+			if pausedCondition.Status != tc.expectedCondition.Status ||
+				pausedCondition.Reason != tc.expectedCondition.Reason {
+				t.Fatalf("Expected paused condition %v, got %v", tc.expectedCondition, pausedCondition)
+			}
+		})
+	}
+}
@@ -35,6 +35,7 @@ import (
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -71,12 +72,17 @@ type VersionManager struct {
 
 	client generic.Client
 
+	// The shared informer for the version type, used to load versions written by other processes, e.g. when they
+	// are restored from a backup.
+	informer cache.SharedIndexInformer
+
 	logger klog.Logger
 }
 
 func NewVersionManager(
 	logger klog.Logger,
 	client generic.Client,
+	informer cache.SharedIndexInformer,
 	namespaced bool,
 	federatedKind, targetKind, namespace string,
 ) *VersionManager {
@@ -88,22 +94,40 @@ func NewVersionManager(
 		adapter:       NewVersionAdapter(namespaced),
 		versions:      make(map[string]runtimeclient.Object),
 		client:        client,
+		informer:      informer,
 	}
 
 	return v
 }
 
-// Sync retrieves propagated versions from the api and loads it into
-// memory.
+// Sync loads the propagated versions from the informer into memory and
+// keeps loading versions created by other processes until the stop
+// channel is closed.
 func (m *VersionManager) Sync(stopChan <-chan struct{}) {
-	versionList, ok := m.list(stopChan)
-	if !ok {
+	registration, err := m.informer.AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: m.isManagedVersion,
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc: m.loadVersion,
+		},
+	})
+	if err != nil {
+		m.logger.WithValues("federated-kind", m.federatedKind).
+			Error(err, "Failed to add the event handler for propagated versions")
 		return
 	}
-	ok = m.load(versionList, stopChan)
-	if !ok {
+	defer func() {
+		if err := m.informer.RemoveEventHandler(registration); err != nil {
+			m.logger.Error(err, "Failed to remove the event handler for propagated versions")
+		}
+	}()
+
+	if !cache.WaitForCacheSync(stopChan, m.informer.HasSynced) {
+		m.logger.V(4).Info("Halting version manager sync due to closed stop channel")
 		return
 	}
+	m.load()
+
+	<-stopChan
 }
 
 // HasSynced indicates whether the manager's in-memory state has been
@@ -220,61 +244,47 @@ func (m *VersionManager) Delete(qualifiedName common.QualifiedName) {
 	m.Unlock()
 }
 
-func (m *VersionManager) list(stopChan <-chan struct{}) (runtimeclient.ObjectList, bool) {
-	// Attempt retrieval of list of versions until success or the channel is closed.
-	var versionList runtimeclient.ObjectList
-	err := wait.PollImmediateInfinite(1*time.Second, func() (bool, error) {
-		select {
-		case <-stopChan:
-			m.logger.V(4).Info("Halting version manager list due to closed stop channel")
-			return false, errors.New("")
-		default:
+// load processes the versions in the informer's store into the
+// in-memory cache and marks the manager as synced.
+func (m *VersionManager) load() {
+	for _, obj := range m.informer.GetStore().List() {
+		if m.isManagedVersion(obj) {
+			m.loadVersion(obj)
 		}
-		versionList = m.adapter.NewListObject()
-		err := m.client.List(context.TODO(), versionList, m.namespace)
-		if err != nil {
-			m.logger.WithValues("federated-kind", m.federatedKind).
-				Error(err, "Failed to list propagated versions for federatedKind")
-			// Do not return the error to allow the operation to be retried.
-			return false, nil
-		}
-		return true, nil
-	})
-	if err != nil {
-		return nil, false
 	}
-	return versionList, true
+	m.Lock()
+	m.hasSynced = true
+	m.Unlock()
+	m.logger.WithValues("federated-kind", m.federatedKind).
+		V(4).Info("Version manager for federatedKind synced")
 }
 
-// load processes a list of versions into in-memory cache.  Since the
-// version manager should not be used in advance of HasSynced
-// returning true, locking is assumed to be unnecessary.
-func (m *VersionManager) load(versionList runtimeclient.ObjectList, stopChan <-chan struct{}) bool {
-	typePrefix := PropagatedVersionPrefix(m.targetKind)
-	objs, err := meta.ExtractList(versionList)
-	if err != nil {
+// isManagedVersion indicates whether the given propagated version is
+// for the manager's type and namespace.
+func (m *VersionManager) isManagedVersion(obj interface{}) bool {
+	version, ok := obj.(runtimeclient.Object)
+	if !ok {
 		return false
 	}
-	for _, obj := range objs {
-		select {
-		case <-stopChan:
-			m.logger.V(4).Info("Halting version manager load due to closed stop channel")
-			return false
-		default:
-		}
-
-		qualifiedName := common.NewQualifiedName(obj)
-		// Ignore propagated version for other types
-		if strings.HasPrefix(qualifiedName.Name, typePrefix) {
-			m.versions[qualifiedName.String()] = obj.(runtimeclient.Object)
-		}
+	if m.namespace != "" && version.GetNamespace() != m.namespace {
+		return false
 	}
+	// Ignore propagated version for other types
+	return strings.HasPrefix(version.GetName(), PropagatedVersionPrefix(m.targetKind))
+}
+
+// loadVersion adds a propagated version observed by the informer to the
+// in-memory cache unless the manager already holds a version of the
+// same name, which is the one last written by the manager.
+func (m *VersionManager) loadVersion(obj interface{}) {
+	version := obj.(runtimeclient.Object)
+	key := common.NewQualifiedName(version).String()
 	m.Lock()
-	m.hasSynced = true
-	m.Unlock()
-	m.logger.WithValues("federated-kind", m.federatedKind).
-		V(4).Info("Version manager for federatedKind synced")
-	return true
+	defer m.Unlock()
+	if _, ok := m.versions[key]; !ok {
+		// The manager modifies the versions it holds, so it must not hold the informer's copy.
+		m.versions[key] = version.DeepCopyObject().(runtimeclient.Object)
+	}
 }
 
 // versionQualifiedName derives the qualified name of a version
@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	fedfake "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned/fake"
+	fedinformers "github.com/kubewharf/kubeadmiral/pkg/client/informers/externalversions"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+type testResource struct {
+	name common.QualifiedName
+}
+
+func (r *testResource) FederatedName() common.QualifiedName { return r.name }
+
+func (r *testResource) Object() *unstructured.Unstructured { return &unstructured.Unstructured{} }
+
+func (r *testResource) TemplateVersion() (string, error) { return "t1", nil }
+
+func (r *testResource) OverrideVersion() (string, error) { return "o1", nil }
+
+func newPropagatedVersion(namespace, name, cluster string) *fedcorev1a1.PropagatedVersion {
+	return &fedcorev1a1.PropagatedVersion{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Status: fedcorev1a1.PropagatedVersionStatus{
+			TemplateVersion: "t1",
+			OverrideVersion: "o1",
+			ClusterVersions: []fedcorev1a1.ClusterObjectVersion{{ClusterName: cluster, Version: "1"}},
+		},
+	}
+}
+
+func TestVersionManagerLoadsVersionsFromInformer(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	fedClient := fedfake.NewSimpleClientset(
+		newPropagatedVersion("default", "deployment-synced", "cluster-1"),
+		newPropagatedVersion("default", "configmap-other-type", "cluster-1"),
+	)
+	factory := fedinformers.NewSharedInformerFactory(fedClient, 0)
+	informer := factory.Core().V1alpha1().PropagatedVersions().Informer()
+
+	// The manager is given no client: Get must be served from memory.
+	manager := NewVersionManager(klog.Background(), nil, informer, true, "FederatedDeployment", "Deployment", "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	factory.Start(ctx.Done())
+	go manager.Sync(ctx.Done())
+	g.Eventually(manager.HasSynced).WithTimeout(wait.ForeverTestTimeout).Should(gomega.BeTrue())
+
+	g.Expect(manager.Get(&testResource{name: common.QualifiedName{Namespace: "default", Name: "synced"}})).
+		To(gomega.Equal(map[string]string{"cluster-1": "1"}))
+	g.Expect(manager.Get(&testResource{name: common.QualifiedName{Namespace: "default", Name: "other-type"}})).
+		To(gomega.BeEmpty())
+
+	// A version created after the manager was synced, e.g. by a restore, is loaded from the informer.
+	restored := &testResource{name: common.QualifiedName{Namespace: "default", Name: "restored"}}
+	g.Expect(manager.Get(restored)).To(gomega.BeEmpty())
+	_, err := fedClient.CoreV1alpha1().PropagatedVersions("default").Create(
+		ctx,
+		newPropagatedVersion("default", "deployment-restored", "cluster-2"),
+		metav1.CreateOptions{},
+	)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Eventually(func() (map[string]string, error) {
+		return manager.Get(restored)
+	}).WithTimeout(wait.ForeverTestTimeout).WithPolling(10 * time.Millisecond).
+		Should(gomega.Equal(map[string]string{"cluster-2": "1"}))
+}
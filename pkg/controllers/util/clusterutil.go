@@ -23,21 +23,32 @@ package util
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubeclient "k8s.io/client-go/kubernetes"
+	// Registers the oidc auth provider used by the OIDC credential provider.
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/transport"
+	"k8s.io/client-go/util/retry"
 
 	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
 	"github.com/kubewharf/kubeadmiral/pkg/client/generic"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
 )
 
 // User account keys
 const (
 	ClientCertificateKey    = "client-certificate-data"
 	ClientKeyKey            = "client-key-data"
+	BearerTokenKey          = "bearer-token-data"
 	CertificateAuthorityKey = "certificate-authority-data"
 )
 
@@ -54,6 +65,7 @@ func BuildClusterConfig(
 	fedClient kubeclient.Interface,
 	restConfig *restclient.Config,
 	fedSystemNamespace string,
+	credentialProviderPolicy *CredentialProviderPolicy,
 ) (*restclient.Config, error) {
 	return buildClusterConfig(
 		cluster,
@@ -61,6 +73,7 @@ func BuildClusterConfig(
 		restConfig,
 		fedSystemNamespace,
 		cluster.Spec.UseServiceAccountToken,
+		credentialProviderPolicy,
 	)
 }
 
@@ -71,6 +84,7 @@ func BuildRawClusterConfig(
 	fedClient kubeclient.Interface,
 	restConfig *restclient.Config,
 	fedSystemNamespace string,
+	credentialProviderPolicy *CredentialProviderPolicy,
 ) (*restclient.Config, error) {
 	return buildClusterConfig(
 		cluster,
@@ -78,6 +92,7 @@ func BuildRawClusterConfig(
 		restConfig,
 		fedSystemNamespace,
 		false,
+		credentialProviderPolicy,
 	)
 }
 
@@ -87,6 +102,7 @@ func buildClusterConfig(
 	restConfig *restclient.Config,
 	fedSystemNamespace string,
 	useServiceAccountToken bool,
+	credentialProviderPolicy *CredentialProviderPolicy,
 ) (*restclient.Config, error) {
 	apiEndpoint := cluster.Spec.APIEndpoint
 	if len(apiEndpoint) == 0 {
@@ -102,6 +118,14 @@ func buildClusterConfig(
 	clusterConfig.Burst = restConfig.Burst
 	clusterConfig.UserAgent = restConfig.UserAgent
 
+	proxyHeadersSecret, err := GetProxyHeadersSecret(context.TODO(), fedClient, fedSystemNamespace, cluster)
+	if err != nil {
+		return nil, err
+	}
+	if err := PopulateProxyDetails(clusterConfig, cluster.Spec.Proxy, proxyHeadersSecret); err != nil {
+		return nil, fmt.Errorf("cannot configure proxy: %w", err)
+	}
+
 	secretName := cluster.Spec.SecretRef.Name
 	if len(secretName) == 0 {
 		clusterConfig.CAFile = restConfig.CAFile
@@ -115,13 +139,231 @@ func buildClusterConfig(
 		return nil, err
 	}
 
-	err = PopulateAuthDetailsFromSecret(clusterConfig, cluster.Spec.Insecure, secret, useServiceAccountToken)
+	err = PopulateAuthDetails(
+		clusterConfig,
+		cluster,
+		secret,
+		useServiceAccountToken,
+		credentialProviderPolicy,
+		NewOIDCTokenPersister(fedClient, fedSystemNamespace, secretName),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("cannot build rest config from cluster secret: %w", err)
 	}
 	return clusterConfig, nil
 }
 
+// CredentialProviderPolicy restricts the credential providers that FederatedClusters may use. Exec and token file
+// providers run commands and read files on the host of the controller manager, so they are only allowed if the
+// operator has explicitly allowed the command or the directory containing the file. A nil policy allows neither.
+type CredentialProviderPolicy struct {
+	// AllowedExecCommands are the commands that exec providers may run. A command must match exactly.
+	AllowedExecCommands []string
+	// AllowedTokenFileDirectories are the absolute paths of the directories that token file providers may read from.
+	AllowedTokenFileDirectories []string
+}
+
+// Validate returns an error if the credential provider is not allowed by the policy.
+func (p *CredentialProviderPolicy) Validate(provider *fedcorev1a1.ClusterCredentialProvider) error {
+	switch {
+	case provider.Exec != nil:
+		if p != nil {
+			for _, command := range p.AllowedExecCommands {
+				if command == provider.Exec.Command {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf(
+			"exec credential provider command %q is not allowed, it must be allowed explicitly by the operator",
+			provider.Exec.Command,
+		)
+	case provider.TokenFile != nil:
+		path := provider.TokenFile.Path
+		if !filepath.IsAbs(path) {
+			return fmt.Errorf("token file credential provider path %q must be absolute", path)
+		}
+		if p != nil {
+			for _, dir := range p.AllowedTokenFileDirectories {
+				if rel, err := filepath.Rel(dir, filepath.Clean(path)); err == nil &&
+					rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf(
+			"token file credential provider path %q is not in a directory allowed explicitly by the operator",
+			path,
+		)
+	default:
+		return nil
+	}
+}
+
+// PopulateAuthDetails populates the auth details of the rest config using the credential provider of the cluster if
+// set, or the static credentials in the cluster secret otherwise. The persister is used to save the tokens refreshed by
+// the OIDC credential provider and may be nil.
+func PopulateAuthDetails(
+	clusterConfig *restclient.Config,
+	cluster *fedcorev1a1.FederatedCluster,
+	secret *corev1.Secret,
+	useServiceAccount bool,
+	credentialProviderPolicy *CredentialProviderPolicy,
+	persister restclient.AuthProviderConfigPersister,
+) error {
+	if cluster.Spec.CredentialProvider == nil {
+		return PopulateAuthDetailsFromSecret(clusterConfig, cluster.Spec.Insecure, secret, useServiceAccount)
+	}
+	return PopulateAuthDetailsFromCredentialProvider(
+		clusterConfig,
+		cluster.Spec.Insecure,
+		cluster.Spec.CredentialProvider,
+		secret,
+		credentialProviderPolicy,
+		persister,
+	)
+}
+
+// oidcTokenPersister saves the tokens refreshed by the oidc auth provider in the cluster secret. OIDC providers may
+// rotate the refresh token on every refresh and invalidate the previous one, so the client would be unable to
+// authenticate once it is rebuilt from the secret, e.g. after a restart, if the new refresh token was not saved.
+type oidcTokenPersister struct {
+	get    func(ctx context.Context) (*corev1.Secret, error)
+	update func(ctx context.Context, secret *corev1.Secret) error
+}
+
+// NewOIDCTokenPersister returns a restclient.AuthProviderConfigPersister that writes the refresh token and the ID
+// token refreshed by the oidc auth provider to the given cluster secret.
+func NewOIDCTokenPersister(client kubeclient.Interface, namespace, name string) restclient.AuthProviderConfigPersister {
+	return &oidcTokenPersister{
+		get: func(ctx context.Context) (*corev1.Secret, error) {
+			return client.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+		},
+		update: func(ctx context.Context, secret *corev1.Secret) error {
+			_, err := client.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{})
+			return err
+		},
+	}
+}
+
+func newGenericOIDCTokenPersister(client generic.Client, namespace, name string) restclient.AuthProviderConfigPersister {
+	return &oidcTokenPersister{
+		get: func(ctx context.Context) (*corev1.Secret, error) {
+			secret := &corev1.Secret{}
+			if err := client.Get(ctx, secret, namespace, name); err != nil {
+				return nil, err
+			}
+			return secret, nil
+		},
+		update: func(ctx context.Context, secret *corev1.Secret) error {
+			return client.Update(ctx, secret)
+		},
+	}
+}
+
+func (p *oidcTokenPersister) Persist(config map[string]string) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		secret, err := p.get(context.TODO())
+		if err != nil {
+			return err
+		}
+
+		refreshToken, idToken := config["refresh-token"], config["id-token"]
+		if string(secret.Data[common.ClusterOIDCRefreshTokenKey]) == refreshToken &&
+			string(secret.Data[common.ClusterOIDCIDTokenKey]) == idToken {
+			return nil
+		}
+
+		secret = secret.DeepCopy()
+		if secret.Data == nil {
+			secret.Data = make(map[string][]byte, 2)
+		}
+		secret.Data[common.ClusterOIDCRefreshTokenKey] = []byte(refreshToken)
+		secret.Data[common.ClusterOIDCIDTokenKey] = []byte(idToken)
+		return p.update(context.TODO(), secret)
+	})
+}
+
+// PopulateAuthDetailsFromCredentialProvider configures the rest config to obtain credentials from the given provider.
+// The refreshing of the credentials is handled by client-go: exec plugins are rerun when the credentials expire or
+// are rejected, OIDC ID tokens are refreshed when they expire and token files are reread periodically. Hence clients
+// built from the rest config do not need to be rebuilt when the credentials are rotated. Exec and token file providers
+// are rejected unless they are allowed by the policy. Refreshed OIDC tokens are saved with the persister if it is not
+// nil.
+func PopulateAuthDetailsFromCredentialProvider(
+	clusterConfig *restclient.Config,
+	insecure bool,
+	provider *fedcorev1a1.ClusterCredentialProvider,
+	secret *corev1.Secret,
+	policy *CredentialProviderPolicy,
+	persister restclient.AuthProviderConfigPersister,
+) error {
+	if err := policy.Validate(provider); err != nil {
+		return err
+	}
+
+	if insecure {
+		clusterConfig.Insecure = true
+	} else {
+		if secret == nil {
+			return fmt.Errorf("cluster secret is required for %q data when insecure is false", CertificateAuthorityKey)
+		}
+		var exists bool
+		clusterConfig.CAData, exists = secret.Data[CertificateAuthorityKey]
+		if !exists {
+			return fmt.Errorf("%q data is missing from secret and insecure is false", CertificateAuthorityKey)
+		}
+	}
+
+	switch {
+	case provider.Exec != nil:
+		apiVersion := provider.Exec.APIVersion
+		if apiVersion == "" {
+			apiVersion = "client.authentication.k8s.io/v1"
+		}
+		env := make([]clientcmdapi.ExecEnvVar, 0, len(provider.Exec.Env))
+		for _, envVar := range provider.Exec.Env {
+			env = append(env, clientcmdapi.ExecEnvVar{Name: envVar.Name, Value: envVar.Value})
+		}
+		clusterConfig.ExecProvider = &clientcmdapi.ExecConfig{
+			Command:         provider.Exec.Command,
+			Args:            provider.Exec.Args,
+			Env:             env,
+			APIVersion:      apiVersion,
+			InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+		}
+	case provider.OIDC != nil:
+		if secret == nil {
+			return fmt.Errorf("cluster secret is required for %q data", common.ClusterOIDCRefreshTokenKey)
+		}
+		refreshToken, exists := secret.Data[common.ClusterOIDCRefreshTokenKey]
+		if !exists {
+			return fmt.Errorf("%q data is missing from secret", common.ClusterOIDCRefreshTokenKey)
+		}
+		authConfig := map[string]string{
+			"idp-issuer-url": provider.OIDC.IssuerURL,
+			"client-id":      provider.OIDC.ClientID,
+			"refresh-token":  string(refreshToken),
+		}
+		if clientSecret, exists := secret.Data[common.ClusterOIDCClientSecretKey]; exists {
+			authConfig["client-secret"] = string(clientSecret)
+		}
+		// The oidc auth provider requires an ID token to be present and refreshes it if it has expired.
+		authConfig["id-token"] = string(secret.Data[common.ClusterOIDCIDTokenKey])
+		if len(provider.OIDC.ExtraScopes) > 0 {
+			authConfig["extra-scopes"] = strings.Join(provider.OIDC.ExtraScopes, ",")
+		}
+		clusterConfig.AuthProvider = &clientcmdapi.AuthProviderConfig{Name: "oidc", Config: authConfig}
+		clusterConfig.AuthConfigPersister = persister
+	case provider.TokenFile != nil:
+		clusterConfig.BearerTokenFile = filepath.Clean(provider.TokenFile.Path)
+	default:
+		return fmt.Errorf("no credential provider is specified")
+	}
+
+	return nil
+}
+
 func PopulateAuthDetailsFromSecret(
 	clusterConfig *restclient.Config,
 	insecure bool,
@@ -146,14 +388,19 @@ func PopulateAuthDetailsFromSecret(
 			}
 		}
 	} else {
-		clusterConfig.CertData, exists = secret.Data[ClientCertificateKey]
-		if !exists {
-			return fmt.Errorf("%q data is missing from secret", ClientCertificateKey)
-		}
+		// a static bearer token is only used if the secret contains no client certificate
+		if _, exists = secret.Data[ClientCertificateKey]; !exists && len(secret.Data[BearerTokenKey]) > 0 {
+			clusterConfig.BearerToken = string(secret.Data[BearerTokenKey])
+		} else {
+			clusterConfig.CertData, exists = secret.Data[ClientCertificateKey]
+			if !exists {
+				return fmt.Errorf("%q or %q data is missing from secret", ClientCertificateKey, BearerTokenKey)
+			}
 
-		clusterConfig.KeyData, exists = secret.Data[ClientKeyKey]
-		if !exists {
-			return fmt.Errorf("%q data is missing from secret", ClientKeyKey)
+			clusterConfig.KeyData, exists = secret.Data[ClientKeyKey]
+			if !exists {
+				return fmt.Errorf("%q data is missing from secret", ClientKeyKey)
+			}
 		}
 
 		if insecure {
@@ -176,6 +423,7 @@ func BuildClusterConfigWithGenericClient(
 	fedClient generic.Client,
 	restConfig *restclient.Config,
 	fedSystemNamespace string,
+	credentialProviderPolicy *CredentialProviderPolicy,
 ) (*restclient.Config, error) {
 	apiEndpoint := cluster.Spec.APIEndpoint
 	if len(apiEndpoint) == 0 {
@@ -190,16 +438,98 @@ func BuildClusterConfigWithGenericClient(
 	clusterConfig.QPS = restConfig.QPS
 	clusterConfig.Burst = restConfig.Burst
 
+	var proxyHeadersSecret *corev1.Secret
+	if proxy := cluster.Spec.Proxy; proxy != nil && proxy.HeadersSecretRef != nil {
+		proxyHeadersSecret = &corev1.Secret{}
+		err = fedClient.Get(context.TODO(), proxyHeadersSecret, fedSystemNamespace, proxy.HeadersSecretRef.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := PopulateProxyDetails(clusterConfig, cluster.Spec.Proxy, proxyHeadersSecret); err != nil {
+		return nil, fmt.Errorf("cannot configure proxy: %w", err)
+	}
+
 	secret := &corev1.Secret{}
 	err = fedClient.Get(context.TODO(), secret, fedSystemNamespace, cluster.Spec.SecretRef.Name)
 	if err != nil {
 		return nil, err
 	}
 
-	err = PopulateAuthDetailsFromSecret(clusterConfig, cluster.Spec.Insecure, secret, cluster.Spec.UseServiceAccountToken)
+	err = PopulateAuthDetails(
+		clusterConfig,
+		cluster,
+		secret,
+		cluster.Spec.UseServiceAccountToken,
+		credentialProviderPolicy,
+		newGenericOIDCTokenPersister(fedClient, fedSystemNamespace, cluster.Spec.SecretRef.Name),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("cannot build rest config from cluster secret: %w", err)
 	}
 
 	return clusterConfig, nil
 }
+
+// GetProxyHeadersSecret returns the secret containing the proxy headers of the given FederatedCluster, or nil if the
+// cluster does not specify one.
+func GetProxyHeadersSecret(
+	ctx context.Context,
+	fedClient kubeclient.Interface,
+	fedSystemNamespace string,
+	cluster *fedcorev1a1.FederatedCluster,
+) (*corev1.Secret, error) {
+	proxy := cluster.Spec.Proxy
+	if proxy == nil || proxy.HeadersSecretRef == nil {
+		return nil, nil
+	}
+
+	secret, err := fedClient.CoreV1().Secrets(fedSystemNamespace).Get(ctx, proxy.HeadersSecretRef.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get proxy headers secret: %w", err)
+	}
+	return secret, nil
+}
+
+// PopulateProxyDetails configures the rest config to access the member cluster through the given proxy. The data of
+// headersSecret, if not nil, are sent as headers to the proxy in CONNECT requests.
+func PopulateProxyDetails(
+	clusterConfig *restclient.Config,
+	proxy *fedcorev1a1.ClusterProxy,
+	headersSecret *corev1.Secret,
+) error {
+	if proxy == nil {
+		return nil
+	}
+
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		return fmt.Errorf("invalid proxy url %q: %w", proxy.URL, err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return fmt.Errorf("unsupported proxy scheme %q", proxyURL.Scheme)
+	}
+	clusterConfig.Proxy = http.ProxyURL(proxyURL)
+
+	if headersSecret == nil || len(headersSecret.Data) == 0 {
+		return nil
+	}
+	header := make(http.Header, len(headersSecret.Data))
+	for key, value := range headersSecret.Data {
+		header.Set(key, string(value))
+	}
+	// Transports are not cached by client-go if a proxy is set, so the transport passed to the wrapper is never shared
+	// with other clusters.
+	clusterConfig.WrapTransport = transport.Wrappers(
+		clusterConfig.WrapTransport,
+		func(rt http.RoundTripper) http.RoundTripper {
+			if httpTransport, ok := rt.(*http.Transport); ok {
+				httpTransport.ProxyConnectHeader = header.Clone()
+			}
+			return rt
+		},
+	)
+	return nil
+}
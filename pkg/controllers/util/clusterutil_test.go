@@ -0,0 +1,336 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	restclient "k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+)
+
+func TestPopulateProxyDetails(t *testing.T) {
+	t.Run("no proxy", func(t *testing.T) {
+		config := &restclient.Config{}
+		assert.NoError(t, PopulateProxyDetails(config, nil, nil))
+		assert.Nil(t, config.Proxy)
+		assert.Nil(t, config.WrapTransport)
+	})
+
+	t.Run("unsupported scheme", func(t *testing.T) {
+		config := &restclient.Config{}
+		assert.Error(t, PopulateProxyDetails(config, &fedcorev1a1.ClusterProxy{URL: "ftp://proxy:21"}, nil))
+	})
+
+	t.Run("proxy with headers", func(t *testing.T) {
+		config := &restclient.Config{}
+		secret := &corev1.Secret{Data: map[string][]byte{"Proxy-Authorization": []byte("Basic Zm9vOmJhcg==")}}
+		err := PopulateProxyDetails(config, &fedcorev1a1.ClusterProxy{URL: "socks5://proxy:1080"}, secret)
+		assert.NoError(t, err)
+
+		req, _ := http.NewRequest(http.MethodGet, "https://member:6443", nil)
+		proxyURL, err := config.Proxy(req)
+		assert.NoError(t, err)
+		assert.Equal(t, "socks5://proxy:1080", proxyURL.String())
+
+		httpTransport := &http.Transport{}
+		assert.Same(t, httpTransport, config.WrapTransport(httpTransport))
+		assert.Equal(t, "Basic Zm9vOmJhcg==", httpTransport.ProxyConnectHeader.Get("Proxy-Authorization"))
+	})
+}
+
+func TestPopulateAuthDetailsFromSecret(t *testing.T) {
+	t.Run("client certificate", func(t *testing.T) {
+		config := &restclient.Config{}
+		err := PopulateAuthDetailsFromSecret(config, false, &corev1.Secret{Data: map[string][]byte{
+			CertificateAuthorityKey: []byte("ca"),
+			ClientCertificateKey:    []byte("cert"),
+			ClientKeyKey:            []byte("key"),
+			BearerTokenKey:          []byte("token"),
+		}}, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("cert"), config.CertData)
+		assert.Equal(t, []byte("key"), config.KeyData)
+		assert.Empty(t, config.BearerToken)
+	})
+
+	t.Run("bearer token", func(t *testing.T) {
+		config := &restclient.Config{}
+		err := PopulateAuthDetailsFromSecret(config, false, &corev1.Secret{Data: map[string][]byte{
+			CertificateAuthorityKey: []byte("ca"),
+			BearerTokenKey:          []byte("token"),
+		}}, false)
+		assert.NoError(t, err)
+		assert.Equal(t, "token", config.BearerToken)
+		assert.Equal(t, []byte("ca"), config.CAData)
+		assert.Empty(t, config.CertData)
+	})
+
+	t.Run("missing credentials", func(t *testing.T) {
+		err := PopulateAuthDetailsFromSecret(&restclient.Config{}, true, &corev1.Secret{}, false)
+		assert.Error(t, err)
+	})
+}
+
+func TestPopulateAuthDetailsFromCredentialProvider(t *testing.T) {
+	secret := &corev1.Secret{Data: map[string][]byte{
+		CertificateAuthorityKey:           []byte("ca"),
+		common.ClusterOIDCRefreshTokenKey: []byte("refresh"),
+		common.ClusterOIDCClientSecretKey: []byte("client-secret"),
+	}}
+	policy := &CredentialProviderPolicy{
+		AllowedExecCommands:         []string{"get-token"},
+		AllowedTokenFileDirectories: []string{"/var/run/secrets/member"},
+	}
+
+	t.Run("exec", func(t *testing.T) {
+		config := &restclient.Config{}
+		err := PopulateAuthDetailsFromCredentialProvider(config, false, &fedcorev1a1.ClusterCredentialProvider{
+			Exec: &fedcorev1a1.ExecCredentialProvider{
+				Command: "get-token",
+				Args:    []string{"--cluster", "member"},
+				Env:     []fedcorev1a1.ExecEnvVar{{Name: "REGION", Value: "us"}},
+			},
+		}, secret, policy, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("ca"), config.CAData)
+		assert.Equal(t, "get-token", config.ExecProvider.Command)
+		assert.Equal(t, []string{"--cluster", "member"}, config.ExecProvider.Args)
+		assert.Equal(t, []clientcmdapi.ExecEnvVar{{Name: "REGION", Value: "us"}}, config.ExecProvider.Env)
+		assert.Equal(t, "client.authentication.k8s.io/v1", config.ExecProvider.APIVersion)
+		assert.Equal(t, clientcmdapi.NeverExecInteractiveMode, config.ExecProvider.InteractiveMode)
+	})
+
+	t.Run("oidc", func(t *testing.T) {
+		config := &restclient.Config{}
+		err := PopulateAuthDetailsFromCredentialProvider(config, false, &fedcorev1a1.ClusterCredentialProvider{
+			OIDC: &fedcorev1a1.OIDCCredentialProvider{
+				IssuerURL:   "https://issuer",
+				ClientID:    "kubeadmiral",
+				ExtraScopes: []string{"groups", "email"},
+			},
+		}, secret, nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "oidc", config.AuthProvider.Name)
+		assert.Equal(t, map[string]string{
+			"idp-issuer-url": "https://issuer",
+			"client-id":      "kubeadmiral",
+			"client-secret":  "client-secret",
+			"refresh-token":  "refresh",
+			"id-token":       "",
+			"extra-scopes":   "groups,email",
+		}, config.AuthProvider.Config)
+	})
+
+	t.Run("oidc without refresh token", func(t *testing.T) {
+		config := &restclient.Config{}
+		err := PopulateAuthDetailsFromCredentialProvider(config, true, &fedcorev1a1.ClusterCredentialProvider{
+			OIDC: &fedcorev1a1.OIDCCredentialProvider{IssuerURL: "https://issuer", ClientID: "kubeadmiral"},
+		}, &corev1.Secret{}, nil, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("token file", func(t *testing.T) {
+		config := &restclient.Config{}
+		err := PopulateAuthDetailsFromCredentialProvider(config, true, &fedcorev1a1.ClusterCredentialProvider{
+			TokenFile: &fedcorev1a1.TokenFileCredentialProvider{Path: "/var/run/secrets/member/token"},
+		}, nil, policy, nil)
+		assert.NoError(t, err)
+		assert.True(t, config.Insecure)
+		assert.Equal(t, "/var/run/secrets/member/token", config.BearerTokenFile)
+	})
+
+	t.Run("missing ca", func(t *testing.T) {
+		config := &restclient.Config{}
+		err := PopulateAuthDetailsFromCredentialProvider(config, false, &fedcorev1a1.ClusterCredentialProvider{
+			TokenFile: &fedcorev1a1.TokenFileCredentialProvider{Path: "/var/run/secrets/member/token"},
+		}, nil, policy, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("exec and token file are rejected without policy", func(t *testing.T) {
+		err := PopulateAuthDetailsFromCredentialProvider(&restclient.Config{}, true, &fedcorev1a1.ClusterCredentialProvider{
+			Exec: &fedcorev1a1.ExecCredentialProvider{Command: "get-token"},
+		}, nil, nil, nil)
+		assert.Error(t, err)
+
+		err = PopulateAuthDetailsFromCredentialProvider(&restclient.Config{}, true, &fedcorev1a1.ClusterCredentialProvider{
+			TokenFile: &fedcorev1a1.TokenFileCredentialProvider{Path: "/var/run/secrets/member/token"},
+		}, nil, nil, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestOIDCTokenPersister(t *testing.T) {
+	// the issuer rotates the refresh token on every refresh and only accepts the latest one
+	var lock sync.Mutex
+	refreshes := 0
+	var issuer *httptest.Server
+	issuer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"issuer":         issuer.URL,
+				"token_endpoint": issuer.URL + "/token",
+			})
+		case "/token":
+			lock.Lock()
+			defer lock.Unlock()
+
+			expected := "refresh"
+			if refreshes > 0 {
+				expected = fmt.Sprintf("refresh-%d", refreshes)
+			}
+			if r.FormValue("refresh_token") != expected {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+				return
+			}
+
+			refreshes++
+			// the ID tokens are already expired, so that every request refreshes them
+			claims, _ := json.Marshal(map[string]interface{}{"exp": time.Now().Add(-time.Minute).Unix()})
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token":  "access",
+				"token_type":    "Bearer",
+				"refresh_token": fmt.Sprintf("refresh-%d", refreshes),
+				"id_token":      fmt.Sprintf("header.%s.%d", base64.RawURLEncoding.EncodeToString(claims), refreshes),
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer issuer.Close()
+
+	var authorization string
+	member := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer member.Close()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-admiral-system", Name: "member"},
+		Data:       map[string][]byte{common.ClusterOIDCRefreshTokenKey: []byte("refresh")},
+	}
+	client := fake.NewSimpleClientset(secret)
+
+	config := &restclient.Config{Host: member.URL}
+	err := PopulateAuthDetailsFromCredentialProvider(config, true, &fedcorev1a1.ClusterCredentialProvider{
+		OIDC: &fedcorev1a1.OIDCCredentialProvider{IssuerURL: issuer.URL, ClientID: "kubeadmiral"},
+	}, secret, nil, NewOIDCTokenPersister(client, secret.Namespace, secret.Name))
+	assert.NoError(t, err)
+
+	httpClient, err := restclient.HTTPClientFor(config)
+	assert.NoError(t, err)
+
+	for i := 1; i <= 2; i++ {
+		resp, err := httpClient.Get(member.URL)
+		assert.NoError(t, err)
+		resp.Body.Close()
+
+		updated, err := client.CoreV1().Secrets(secret.Namespace).Get(context.TODO(), secret.Name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("refresh-%d", i), string(updated.Data[common.ClusterOIDCRefreshTokenKey]))
+		assert.Equal(t, "Bearer "+string(updated.Data[common.ClusterOIDCIDTokenKey]), authorization)
+	}
+}
+
+func TestCredentialProviderPolicyValidate(t *testing.T) {
+	policy := &CredentialProviderPolicy{
+		AllowedExecCommands:         []string{"/usr/local/bin/get-token"},
+		AllowedTokenFileDirectories: []string{"/var/run/secrets/member"},
+	}
+
+	testCases := map[string]struct {
+		provider    *fedcorev1a1.ClusterCredentialProvider
+		expectedErr bool
+	}{
+		"allowed exec command": {
+			provider: &fedcorev1a1.ClusterCredentialProvider{
+				Exec: &fedcorev1a1.ExecCredentialProvider{Command: "/usr/local/bin/get-token"},
+			},
+		},
+		"exec command not allowed": {
+			provider: &fedcorev1a1.ClusterCredentialProvider{
+				Exec: &fedcorev1a1.ExecCredentialProvider{Command: "/bin/sh"},
+			},
+			expectedErr: true,
+		},
+		"exec command resolved from PATH is not allowed": {
+			provider: &fedcorev1a1.ClusterCredentialProvider{
+				Exec: &fedcorev1a1.ExecCredentialProvider{Command: "get-token"},
+			},
+			expectedErr: true,
+		},
+		"token file in allowed directory": {
+			provider: &fedcorev1a1.ClusterCredentialProvider{
+				TokenFile: &fedcorev1a1.TokenFileCredentialProvider{Path: "/var/run/secrets/member/cluster-a/token"},
+			},
+		},
+		"token file outside allowed directories": {
+			provider: &fedcorev1a1.ClusterCredentialProvider{
+				TokenFile: &fedcorev1a1.TokenFileCredentialProvider{Path: "/etc/kubernetes/admin.conf"},
+			},
+			expectedErr: true,
+		},
+		"token file escaping allowed directory": {
+			provider: &fedcorev1a1.ClusterCredentialProvider{
+				TokenFile: &fedcorev1a1.TokenFileCredentialProvider{Path: "/var/run/secrets/member/../kubernetes.io/token"},
+			},
+			expectedErr: true,
+		},
+		"relative token file": {
+			provider: &fedcorev1a1.ClusterCredentialProvider{
+				TokenFile: &fedcorev1a1.TokenFileCredentialProvider{Path: "member/token"},
+			},
+			expectedErr: true,
+		},
+		"oidc is always allowed": {
+			provider: &fedcorev1a1.ClusterCredentialProvider{
+				OIDC: &fedcorev1a1.OIDCCredentialProvider{IssuerURL: "https://issuer", ClientID: "kubeadmiral"},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			err := policy.Validate(tc.provider)
+			if tc.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
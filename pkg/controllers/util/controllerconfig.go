@@ -27,6 +27,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	restclient "k8s.io/client-go/rest"
 
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/sharding"
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/tuning"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/worker"
 	"github.com/kubewharf/kubeadmiral/pkg/stats"
 )
 
@@ -49,10 +52,39 @@ type ControllerConfig struct {
 	WorkerCount                           int
 	NamespaceAutoPropagationExcludeRegexp *regexp.Regexp
 	CreateCrdForFtcs                      bool
+	PausePropagation                      bool
+	// OutOfSyncRecheckDelay is the delay before the monitor controller checks an object whose status is out of sync
+	// again.
+	OutOfSyncRecheckDelay time.Duration
+	// Shard is the shard of federated objects owned by the controllers, or nil if sharding is disabled.
+	Shard *sharding.Shard
+	// Tuning provides the worker and member cluster client settings of the controllers. WorkerCount is used for all
+	// controllers if it is nil.
+	Tuning *tuning.Provider
+	// CredentialProviderPolicy restricts the credential providers used to access member clusters.
+	CredentialProviderPolicy *CredentialProviderPolicy
 
 	Metrics stats.Metrics
 }
 
+// WorkerConfig returns the worker config of the named controller for the named FederatedTypeConfig.
+func (c *ControllerConfig) WorkerConfig(controller, ftc string) worker.ConfigSource {
+	if c.Tuning == nil {
+		return worker.StaticConfig(worker.Config{WorkerCount: c.WorkerCount})
+	}
+	return c.Tuning.Worker(controller, ftc)
+}
+
+// ClusterRestConfig returns a copy of restConfig with the rate limit of the clients of the named controller for
+// member clusters.
+func (c *ControllerConfig) ClusterRestConfig(restConfig *restclient.Config, controller, ftc string) *restclient.Config {
+	clusterConfig := restclient.CopyConfig(restConfig)
+	if c.Tuning != nil {
+		clusterConfig.QPS, clusterConfig.Burst = c.Tuning.ClusterClient(controller, ftc)
+	}
+	return clusterConfig
+}
+
 func (c *ControllerConfig) LimitedScope() bool {
 	return c.FederationNamespaces.TargetNamespace != metav1.NamespaceAll
 }
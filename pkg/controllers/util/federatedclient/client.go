@@ -40,6 +40,7 @@ import (
 	fedcorev1a1 "github.com/kubewharf/kubeadmiral/pkg/apis/core/v1alpha1"
 	fedclient "github.com/kubewharf/kubeadmiral/pkg/client/clientset/versioned"
 	fedcorev1a1informers "github.com/kubewharf/kubeadmiral/pkg/client/informers/externalversions/core/v1alpha1"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
 )
 
@@ -53,8 +54,9 @@ type federatedClientFactory struct {
 	informer   fedcorev1a1informers.FederatedClusterInformer
 	handle     cache.ResourceEventHandlerRegistration
 
-	fedSystemNamespace string
-	baseRestConfig     *rest.Config
+	fedSystemNamespace       string
+	baseRestConfig           *rest.Config
+	credentialProviderPolicy *util.CredentialProviderPolicy
 
 	clientUpdateHandlers []ClientUpdateHandler
 	queue                workqueue.Interface
@@ -75,24 +77,26 @@ func NewFederatedClientsetFactory(
 	informer fedcorev1a1informers.FederatedClusterInformer,
 	fedSystemNamespace string,
 	baseRestConfig *rest.Config,
+	credentialProviderPolicy *util.CredentialProviderPolicy,
 	maxPodListers int64,
 	enablePodPruning bool,
 ) FederatedClientFactory {
 	factory := &federatedClientFactory{
-		mu:                    sync.RWMutex{},
-		client:                client,
-		kubeClient:            kubeClient,
-		informer:              informer,
-		fedSystemNamespace:    fedSystemNamespace,
-		baseRestConfig:        baseRestConfig,
-		queue:                 workqueue.NewRateLimitingQueue(workqueue.DefaultItemBasedRateLimiter()),
-		clientUpdateHandlers:  []ClientUpdateHandler{},
-		clusterErrors:         map[string]error{},
-		kubeClientsetCache:    map[string]kubeclient.Interface{},
-		dynamicClientsetCache: map[string]dynamicclient.Interface{},
-		kubeInformerCache:     map[string]kubeinformer.SharedInformerFactory{},
-		dynamicInformerCache:  map[string]dynamicinformer.DynamicSharedInformerFactory{},
-		enablePodPruning:      enablePodPruning,
+		mu:                       sync.RWMutex{},
+		client:                   client,
+		kubeClient:               kubeClient,
+		informer:                 informer,
+		fedSystemNamespace:       fedSystemNamespace,
+		baseRestConfig:           baseRestConfig,
+		credentialProviderPolicy: credentialProviderPolicy,
+		queue:                    workqueue.NewRateLimitingQueue(workqueue.DefaultItemBasedRateLimiter()),
+		clientUpdateHandlers:     []ClientUpdateHandler{},
+		clusterErrors:            map[string]error{},
+		kubeClientsetCache:       map[string]kubeclient.Interface{},
+		dynamicClientsetCache:    map[string]dynamicclient.Interface{},
+		kubeInformerCache:        map[string]kubeinformer.SharedInformerFactory{},
+		dynamicInformerCache:     map[string]dynamicinformer.DynamicSharedInformerFactory{},
+		enablePodPruning:         enablePodPruning,
 	}
 	if maxPodListers > 0 {
 		factory.availablePodListers = semaphore.NewWeighted(maxPodListers)
@@ -106,9 +110,10 @@ func NewFederatedClientsetFactory(
 			oldCluster := oldObj.(*fedcorev1a1.FederatedCluster)
 			newCluster := newObj.(*fedcorev1a1.FederatedCluster)
 
-			// only enqueue on generation change or cluster join condition changes
+			// only enqueue on generation change, cluster join condition changes or token rotation
 			if oldCluster.Generation != newCluster.Generation ||
-				util.IsClusterJoined(&oldCluster.Status) != util.IsClusterJoined(&newCluster.Status) {
+				util.IsClusterJoined(&oldCluster.Status) != util.IsClusterJoined(&newCluster.Status) ||
+				oldCluster.Annotations[common.TokenRotatedAtAnnotation] != newCluster.Annotations[common.TokenRotatedAtAnnotation] {
 				factory.enqueueCluster(newCluster)
 			}
 		},
@@ -277,6 +282,11 @@ func (f *federatedClientFactory) processQueueItem(ctx context.Context) {
 		f.clearCaches(name)
 		return
 	}
+	if util.IsPullModeCluster(cluster) {
+		// pull-mode clusters are not reachable from the control plane
+		f.clearCaches(name)
+		return
+	}
 
 	var kubeClientset kubeclient.Interface
 	var dynamicClientset dynamicclient.Interface
@@ -295,17 +305,31 @@ func (f *federatedClientFactory) processQueueItem(ctx context.Context) {
 		return
 	}
 
-	if err := util.PopulateAuthDetailsFromSecret(
+	if err := util.PopulateAuthDetails(
 		restConfig,
-		cluster.Spec.Insecure,
+		cluster,
 		clusterSecretRef,
 		cluster.Spec.UseServiceAccountToken,
+		f.credentialProviderPolicy,
+		util.NewOIDCTokenPersister(f.kubeClient, f.fedSystemNamespace, cluster.Spec.SecretRef.Name),
 	); err != nil {
 		f.updateCachesWithError(name, fmt.Errorf("failed to build rest config from cluster secret: %w", err))
 		f.queue.Add(key)
 		return
 	}
 
+	proxyHeadersSecret, err := util.GetProxyHeadersSecret(ctx, f.kubeClient, f.fedSystemNamespace, cluster)
+	if err != nil {
+		f.updateCachesWithError(name, err)
+		f.queue.Add(key)
+		return
+	}
+	if err := util.PopulateProxyDetails(restConfig, cluster.Spec.Proxy, proxyHeadersSecret); err != nil {
+		f.updateCachesWithError(name, fmt.Errorf("failed to configure proxy: %w", err))
+		f.queue.Add(key)
+		return
+	}
+
 	if kubeClientset, err = kubeclient.NewForConfig(restConfig); err != nil {
 		f.updateCachesWithError(name, fmt.Errorf("failed to create kube clientset: %w", err))
 		f.queue.Add(key)
@@ -30,6 +30,7 @@ type ClientUpdateHandler func(cluster string, factory FederatedClientFactory)
 // FederatedClientFactory allows users to get shared clientsets and informer factories for joined member clusters.
 // Note that in the context of a FederatedClientFactory, a cluster will only "exist" when it becomes "Joined".
 // If a cluster is not "Joined", we may not have the necessary information to connect to the member cluster.
+// Pull-mode clusters never "exist" as they are not reachable from the control plane.
 type FederatedClientFactory interface {
 	Start(ctx context.Context)
 	AddClientUpdateHandler(handler ClientUpdateHandler)
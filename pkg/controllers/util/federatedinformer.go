@@ -24,6 +24,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
 	"time"
 
@@ -34,6 +35,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
@@ -99,6 +101,10 @@ type RegisteredClustersView interface {
 	// GetJoinedClusters returns a list of all joined clusters.
 	GetJoinedClusters() ([]*fedcorev1a1.FederatedCluster, error)
 
+	// GetJoinedPullClusters returns a list of all joined pull-mode clusters. Pull-mode clusters are synced by their
+	// agents and are excluded from the other cluster lists.
+	GetJoinedPullClusters() ([]*fedcorev1a1.FederatedCluster, error)
+
 	// GetReadyCluster returns the cluster with the given name, if found.
 	GetReadyCluster(name string) (*fedcorev1a1.FederatedCluster, bool, error)
 
@@ -184,6 +190,7 @@ func NewFederatedInformer(
 				fedClient,
 				restConfig,
 				config.FedSystemNamespace,
+				config.CredentialProviderPolicy,
 			)
 			if err != nil {
 				return nil, err
@@ -311,6 +318,49 @@ func IsClusterJoined(clusterStatus *fedcorev1a1.FederatedClusterStatus) bool {
 	return false
 }
 
+// IsClusterUnstable returns true if the readiness of the cluster changes frequently.
+func IsClusterUnstable(clusterStatus *fedcorev1a1.FederatedClusterStatus) bool {
+	for _, condition := range clusterStatus.Conditions {
+		if condition.Type == fedcorev1a1.ClusterUnstable {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// IsPullModeCluster returns true if resources are synced to the cluster by an agent running in the cluster.
+func IsPullModeCluster(cluster *fedcorev1a1.FederatedCluster) bool {
+	return cluster.Spec.SyncMode == fedcorev1a1.ClusterSyncModePull
+}
+
+// PullClusterKey returns the label and finalizer of federated objects placed in the given pull-mode cluster.
+func PullClusterKey(clusterName string) string {
+	return common.PullClusterPrefix + clusterName
+}
+
+// ValidatePullClusterName returns an error if the name of a pull-mode cluster cannot be used in PullClusterKey.
+func ValidatePullClusterName(clusterName string) error {
+	if errs := validation.IsQualifiedName(PullClusterKey(clusterName)); len(errs) > 0 {
+		return fmt.Errorf("cluster name %q cannot be used in label %q: %s",
+			clusterName, PullClusterKey(clusterName), strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// GetClusterMaintenanceMode returns the maintenance mode of the cluster, or an empty string if the cluster is not in
+// maintenance mode.
+func GetClusterMaintenanceMode(cluster *fedcorev1a1.FederatedCluster) fedcorev1a1.ClusterMaintenanceMode {
+	if cluster.Spec.Maintenance == nil {
+		return ""
+	}
+	return cluster.Spec.Maintenance.Mode
+}
+
+// IsClusterDraining returns true if the cluster is being drained.
+func IsClusterDraining(cluster *fedcorev1a1.FederatedCluster) bool {
+	return GetClusterMaintenanceMode(cluster) == fedcorev1a1.ClusterMaintenanceModeDrain
+}
+
 type informer struct {
 	controller cache.Controller
 	store      cache.Store
@@ -408,7 +458,7 @@ func (f *federatedInformerImpl) GetUnreadyClusters() ([]*fedcorev1a1.FederatedCl
 	result := make([]*fedcorev1a1.FederatedCluster, 0, len(items))
 	for _, item := range items {
 		if cluster, ok := item.(*fedcorev1a1.FederatedCluster); ok {
-			if !IsClusterReady(&cluster.Status) {
+			if !IsClusterReady(&cluster.Status) && !IsPullModeCluster(cluster) {
 				result = append(result, cluster)
 			}
 		} else {
@@ -428,7 +478,27 @@ func (f *federatedInformerImpl) GetJoinedClusters() ([]*fedcorev1a1.FederatedClu
 	return f.getJoinedClusters(false)
 }
 
+// GetJoinedPullClusters returns all joined pull-mode clusters regardless of ready state.
+func (f *federatedInformerImpl) GetJoinedPullClusters() ([]*fedcorev1a1.FederatedCluster, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	items := f.clusterInformer.store.List()
+	result := make([]*fedcorev1a1.FederatedCluster, 0, len(items))
+	for _, item := range items {
+		if cluster, ok := item.(*fedcorev1a1.FederatedCluster); ok {
+			if IsClusterJoined(&cluster.Status) && IsPullModeCluster(cluster) {
+				result = append(result, cluster)
+			}
+		} else {
+			return nil, errors.Errorf("wrong data in FederatedInformerImpl cluster store: %v", item)
+		}
+	}
+	return result, nil
+}
+
 // getJoinedClusters returns only ready clusters if onlyReady is true and all joined clusters otherwise.
+// Pull-mode clusters are never returned.
 func (f *federatedInformerImpl) getJoinedClusters(onlyReady bool) ([]*fedcorev1a1.FederatedCluster, error) {
 	f.Lock()
 	defer f.Unlock()
@@ -437,6 +507,9 @@ func (f *federatedInformerImpl) getJoinedClusters(onlyReady bool) ([]*fedcorev1a
 	result := make([]*fedcorev1a1.FederatedCluster, 0, len(items))
 	for _, item := range items {
 		if cluster, ok := item.(*fedcorev1a1.FederatedCluster); ok {
+			if IsPullModeCluster(cluster) {
+				continue
+			}
 			if IsClusterJoined(&cluster.Status) && (!onlyReady || IsClusterReady(&cluster.Status)) {
 				result = append(result, cluster)
 			}
@@ -473,7 +546,7 @@ func (f *federatedInformerImpl) GetReadyCluster(name string) (*fedcorev1a1.Feder
 
 func (f *federatedInformerImpl) getReadyClusterUnlocked(key string) (*fedcorev1a1.FederatedCluster, bool, error) {
 	if cluster, exist, err := f.getClusterUnlocked(key); exist && err == nil {
-		if IsClusterReady(&cluster.Status) {
+		if IsClusterReady(&cluster.Status) && !IsPullModeCluster(cluster) {
 			return cluster, true, nil
 		}
 		return nil, false, nil
@@ -487,8 +560,13 @@ func (f *federatedInformerImpl) ClustersSynced() bool {
 	return f.clusterInformer.controller.HasSynced()
 }
 
-// Adds the given cluster to federated informer.
+// Adds the given cluster to federated informer. Pull-mode clusters are not reachable from the control plane, so no
+// informer is created for them.
 func (f *federatedInformerImpl) addCluster(cluster *fedcorev1a1.FederatedCluster) {
+	if IsPullModeCluster(cluster) {
+		return
+	}
+
 	f.Lock()
 	defer f.Unlock()
 	name := cluster.Name
@@ -23,6 +23,7 @@ package util
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -60,6 +61,40 @@ type ResourceClusterStatus struct {
 	CollectedFields map[string]interface{} `json:"collectedFields,omitempty"`
 }
 
+// ClusterStatusesFromObject returns the cluster statuses of a federated status object.
+func ClusterStatusesFromObject(obj *unstructured.Unstructured) ([]ResourceClusterStatus, error) {
+	resource := &FederatedResource{}
+	if err := UnstructuredToInterface(obj, resource); err != nil {
+		return nil, err
+	}
+	return resource.ClusterStatus, nil
+}
+
+// CollectStatusFields returns the values of the given dot-separated fields of a cluster object. The fields that could
+// not be collected are returned along with the reason.
+func CollectStatusFields(clusterObj *unstructured.Unstructured, fields []string) (map[string]interface{}, []string) {
+	collectedFields := map[string]interface{}{}
+	failedFields := []string{}
+
+	for _, field := range fields {
+		fieldVal, found, err := unstructured.NestedFieldCopy(clusterObj.Object, strings.Split(field, ".")...)
+		if err != nil {
+			failedFields = append(failedFields, fmt.Sprintf("%s: %s", field, err.Error()))
+			continue
+		}
+		if !found {
+			failedFields = append(failedFields, fmt.Sprintf("%s: not found", field))
+			continue
+		}
+
+		if err := unstructured.SetNestedField(collectedFields, fieldVal, strings.Split(field, ".")...); err != nil {
+			failedFields = append(failedFields, fmt.Sprintf("%s: %s", field, err.Error()))
+		}
+	}
+
+	return collectedFields, failedFields
+}
+
 type LatestReplicasetDigest struct {
 	ClusterName        string `json:"clusterName,omitempty"`
 	ReplicasetName     string `json:"replicasetName,omitempty"`
@@ -37,6 +37,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
+	"github.com/kubewharf/kubeadmiral/pkg/controllermanager/sharding"
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/managedlabel"
 	"github.com/kubewharf/kubeadmiral/pkg/stats"
 )
@@ -58,6 +59,25 @@ func NewResourceInformer(
 	return newResourceInformer(client, namespace, NewTriggerOnAllChanges(triggerFunc), "", map[string]string{}, metrics)
 }
 
+// NewShardedResourceInformer returns an informer limited to the federated
+// objects of the given shard. It is unfiltered if the shard is nil.
+func NewShardedResourceInformer(
+	client ResourceClient,
+	namespace string,
+	shard *sharding.Shard,
+	triggerFunc func(pkgruntime.Object),
+	metrics stats.Metrics,
+) (cache.Store, cache.Controller) {
+	return newResourceInformer(
+		client,
+		namespace,
+		NewTriggerOnAllChanges(triggerFunc),
+		shard.LabelSelector(),
+		map[string]string{},
+		metrics,
+	)
+}
+
 func NewResourceInformerWithEventHandler(
 	client ResourceClient,
 	namespace string,
@@ -0,0 +1,59 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"time"
+)
+
+// Config configures the concurrency and rate limits of a ReconcileWorker.
+type Config struct {
+	// WorkerCount is the number of objects that are reconciled concurrently. Defaults to 1.
+	WorkerCount int
+	// QPS is the maximum number of reconciliations per second. Reconciliations are not rate limited if QPS is 0.
+	QPS float32
+	// Burst is the maximum number of reconciliations that may exceed QPS at once. Defaults to 1.
+	Burst int
+	// InitialBackoff overrides the initial backoff of the WorkerTiming of the worker if non-zero.
+	InitialBackoff time.Duration
+	// MaxBackoff overrides the maximum backoff of the WorkerTiming of the worker if non-zero.
+	MaxBackoff time.Duration
+}
+
+// ConfigSource provides the Config of a ReconcileWorker, which may change while the worker is running.
+type ConfigSource interface {
+	// Config returns the current Config.
+	Config() Config
+	// Changed returns a channel that is closed when the Config may have changed after the channel was returned. A nil
+	// channel is returned if the Config never changes.
+	Changed() <-chan struct{}
+}
+
+type staticConfigSource Config
+
+// StaticConfig returns a ConfigSource that always provides the given Config.
+func StaticConfig(config Config) ConfigSource {
+	return staticConfigSource(config)
+}
+
+func (s staticConfigSource) Config() Config {
+	return Config(s)
+}
+
+func (s staticConfigSource) Changed() <-chan struct{} {
+	return nil
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"container/heap"
+	"sync"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// priorityQueue is a workqueue.Interface that hands out items with higher priorities first. Items with the same priority
+// are handed out in FIFO order. Like the workqueue.Type, an item is never processed by multiple workers concurrently
+// and an item that is added multiple times before it is processed is only processed once.
+type priorityQueue struct {
+	priorityFunc func(item interface{}) int64
+
+	cond *sync.Cond
+
+	queue priorityHeap
+	// the sequence number of the next item pushed to the heap, used to preserve FIFO order between equal priorities
+	seq uint64
+
+	// dirty contains all the items that need to be processed and their priorities
+	dirty map[interface{}]int64
+	// processing contains all the items that are currently being processed
+	processing map[interface{}]struct{}
+
+	shuttingDown bool
+	drain        bool
+}
+
+var _ workqueue.Interface = &priorityQueue{}
+
+func newPriorityQueue(priorityFunc func(item interface{}) int64) *priorityQueue {
+	return &priorityQueue{
+		priorityFunc: priorityFunc,
+		cond:         sync.NewCond(&sync.Mutex{}),
+		dirty:        map[interface{}]int64{},
+		processing:   map[interface{}]struct{}{},
+	}
+}
+
+func (q *priorityQueue) Add(item interface{}) {
+	// the priority is computed outside the lock as the priority func may be expensive
+	priority := q.priorityFunc(item)
+
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	if q.shuttingDown {
+		return
+	}
+	if _, exists := q.dirty[item]; exists {
+		return
+	}
+
+	q.dirty[item] = priority
+	if _, exists := q.processing[item]; exists {
+		// the item will be pushed to the heap when it is done
+		return
+	}
+
+	q.push(item, priority)
+	q.cond.Signal()
+}
+
+func (q *priorityQueue) Len() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	return q.queue.Len()
+}
+
+func (q *priorityQueue) Get() (interface{}, bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	for q.queue.Len() == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if q.queue.Len() == 0 {
+		// we must be shutting down
+		return nil, true
+	}
+
+	item := heap.Pop(&q.queue).(*priorityItem).item
+	q.processing[item] = struct{}{}
+	delete(q.dirty, item)
+
+	return item, false
+}
+
+func (q *priorityQueue) Done(item interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	delete(q.processing, item)
+	if priority, exists := q.dirty[item]; exists {
+		q.push(item, priority)
+		q.cond.Signal()
+	} else if len(q.processing) == 0 {
+		q.cond.Broadcast()
+	}
+}
+
+func (q *priorityQueue) ShutDown() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	q.drain = false
+	q.shuttingDown = true
+	q.cond.Broadcast()
+}
+
+func (q *priorityQueue) ShutDownWithDrain() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	q.drain = true
+	q.shuttingDown = true
+	q.cond.Broadcast()
+
+	for len(q.processing) != 0 && q.drain {
+		q.cond.Wait()
+	}
+}
+
+func (q *priorityQueue) ShuttingDown() bool {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	return q.shuttingDown
+}
+
+// push must be called with the lock held.
+func (q *priorityQueue) push(item interface{}, priority int64) {
+	heap.Push(&q.queue, &priorityItem{item: item, priority: priority, seq: q.seq})
+	q.seq++
+}
+
+type priorityItem struct {
+	item     interface{}
+	priority int64
+	seq      uint64
+}
+
+// priorityHeap implements heap.Interface.
+type priorityHeap []*priorityItem
+
+func (h priorityHeap) Len() int { return len(h) }
+
+func (h priorityHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h priorityHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *priorityHeap) Push(x interface{}) { *h = append(*h, x.(*priorityItem)) }
+
+func (h *priorityHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	priorities := map[string]int64{
+		"low-1":  0,
+		"low-2":  0,
+		"high":   100,
+		"medium": 10,
+	}
+	q := newPriorityQueue(func(item interface{}) int64 { return priorities[item.(string)] })
+
+	for _, item := range []string{"low-1", "medium", "low-2", "high", "low-1"} {
+		q.Add(item)
+	}
+	g.Expect(q.Len()).To(gomega.Equal(4))
+
+	var order []string
+	for q.Len() > 0 {
+		item, shutdown := q.Get()
+		g.Expect(shutdown).To(gomega.BeFalse())
+		order = append(order, item.(string))
+		q.Done(item)
+	}
+	g.Expect(order).To(gomega.Equal([]string{"high", "medium", "low-1", "low-2"}))
+}
+
+func TestPriorityQueueRequeueWhileProcessing(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	q := newPriorityQueue(func(item interface{}) int64 { return 0 })
+
+	q.Add("a")
+	item, _ := q.Get()
+	g.Expect(item).To(gomega.Equal("a"))
+
+	// an item added while it is being processed is not handed out until it is done
+	q.Add("a")
+	g.Expect(q.Len()).To(gomega.Equal(0))
+
+	q.Done("a")
+	g.Expect(q.Len()).To(gomega.Equal(1))
+
+	item, _ = q.Get()
+	g.Expect(item).To(gomega.Equal("a"))
+	q.Done("a")
+
+	q.ShutDown()
+	_, shutdown := q.Get()
+	g.Expect(shutdown).To(gomega.BeTrue())
+}
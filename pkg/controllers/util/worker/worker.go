@@ -21,6 +21,7 @@ are Copyright 2023 The KubeAdmiral Authors.
 package worker
 
 import (
+	"sync"
 	"time"
 
 	pkgruntime "k8s.io/apimachinery/pkg/runtime"
@@ -29,13 +30,16 @@ import (
 	"k8s.io/client-go/util/workqueue"
 
 	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
-	"github.com/kubewharf/kubeadmiral/pkg/controllers/util"
 	deliverutil "github.com/kubewharf/kubeadmiral/pkg/controllers/util/delayingdeliver"
 	"github.com/kubewharf/kubeadmiral/pkg/stats"
 )
 
 type ReconcileFunc func(qualifiedName common.QualifiedName) Result
 
+// KeyPriorityFunc returns the priority of the object with the given qualified name.
+// Objects with higher priorities are reconciled first.
+type KeyPriorityFunc func(qualifiedName common.QualifiedName) int64
+
 type ReconcileWorker interface {
 	Enqueue(qualifiedName common.QualifiedName)
 	EnqueueObject(obj pkgruntime.Object)
@@ -64,10 +68,18 @@ type asyncWorker struct {
 	// Work queue allowing parallel processing of resources
 	queue workqueue.Interface
 
+	configSource ConfigSource
+
+	// lock protects the fields below, which are updated whenever the config of the worker changes.
+	lock   sync.Mutex
+	config Config
 	// Backoff manager
 	backoff *flowcontrol.Backoff
-
-	workerCount int
+	// rateLimiter is nil if reconciliations are not rate limited.
+	rateLimiter flowcontrol.RateLimiter
+	// workerStops contains a stop channel for each running worker goroutine.
+	workerStops []chan struct{}
+	stopped     bool
 
 	metrics    stats.Metrics
 	metricTags deliverutil.MetricTags
@@ -79,6 +91,24 @@ func NewReconcileWorker(
 	workerCount int,
 	metrics stats.Metrics,
 	metricTags deliverutil.MetricTags,
+) ReconcileWorker {
+	return NewConfigurableReconcileWorker(
+		reconcile,
+		timing,
+		StaticConfig(Config{WorkerCount: workerCount}),
+		metrics,
+		metricTags,
+	)
+}
+
+// NewConfigurableReconcileWorker returns a ReconcileWorker whose worker count, rate limit and backoff are provided by
+// configSource. Changes to the config are applied while the worker is running.
+func NewConfigurableReconcileWorker(
+	reconcile ReconcileFunc,
+	timing WorkerTiming,
+	configSource ConfigSource,
+	metrics stats.Metrics,
+	metricTags deliverutil.MetricTags,
 ) ReconcileWorker {
 	if timing.Interval == 0 {
 		timing.Interval = time.Second * 1
@@ -90,19 +120,54 @@ func NewReconcileWorker(
 		timing.MaxBackoff = time.Minute
 	}
 
-	if workerCount == 0 {
-		workerCount = 1
-	}
-	return &asyncWorker{
-		reconcile:   reconcile,
-		timing:      timing,
-		deliverer:   deliverutil.NewDelayingDeliverer(),
-		queue:       workqueue.New(),
-		backoff:     flowcontrol.NewBackOff(timing.InitialBackoff, timing.MaxBackoff),
-		workerCount: workerCount,
-		metrics:     metrics,
-		metricTags:  metricTags,
+	w := &asyncWorker{
+		reconcile:    reconcile,
+		timing:       timing,
+		deliverer:    deliverutil.NewDelayingDeliverer(),
+		queue:        workqueue.New(),
+		configSource: configSource,
+		metrics:      metrics,
+		metricTags:   metricTags,
 	}
+	w.applyConfig(configSource.Config())
+	return w
+}
+
+// NewPriorityReconcileWorker returns a ReconcileWorker that reconciles queued objects in the order of their
+// priorities as returned by priorityFunc. Objects with the same priority are reconciled in FIFO order.
+func NewPriorityReconcileWorker(
+	reconcile ReconcileFunc,
+	timing WorkerTiming,
+	workerCount int,
+	metrics stats.Metrics,
+	metricTags deliverutil.MetricTags,
+	priorityFunc KeyPriorityFunc,
+) ReconcileWorker {
+	return NewConfigurablePriorityReconcileWorker(
+		reconcile,
+		timing,
+		StaticConfig(Config{WorkerCount: workerCount}),
+		metrics,
+		metricTags,
+		priorityFunc,
+	)
+}
+
+// NewConfigurablePriorityReconcileWorker is the same as NewPriorityReconcileWorker, except that the worker count, rate
+// limit and backoff are provided by configSource.
+func NewConfigurablePriorityReconcileWorker(
+	reconcile ReconcileFunc,
+	timing WorkerTiming,
+	configSource ConfigSource,
+	metrics stats.Metrics,
+	metricTags deliverutil.MetricTags,
+	priorityFunc KeyPriorityFunc,
+) ReconcileWorker {
+	w := NewConfigurableReconcileWorker(reconcile, timing, configSource, metrics, metricTags).(*asyncWorker)
+	w.queue = newPriorityQueue(func(item interface{}) int64 {
+		return priorityFunc(common.NewQualifiedFromString(item.(string)))
+	})
+	return w
 }
 
 func (w *asyncWorker) Enqueue(qualifiedName common.QualifiedName) {
@@ -123,44 +188,152 @@ func (w *asyncWorker) EnqueueWithDelay(qualifiedName common.QualifiedName, delay
 }
 
 func (w *asyncWorker) Run(stopChan <-chan struct{}) {
-	util.StartBackoffGC(w.backoff, stopChan)
+	go w.runBackoffGC(stopChan)
 	w.deliverer.StartWithHandler(func(item *deliverutil.DelayingDelivererItem) {
 		w.queue.Add(item.Key)
 	})
 	go w.deliverer.RunMetricLoop(stopChan, 30*time.Second, w.metrics, w.metricTags)
 
-	for i := 0; i < w.workerCount; i++ {
-		go wait.Until(w.worker, w.timing.Interval, stopChan)
-	}
+	w.lock.Lock()
+	w.resizeLocked(w.config.WorkerCount)
+	w.lock.Unlock()
+
+	go w.watchConfig(stopChan)
 
 	// Ensure all goroutines are cleaned up when the stop channel closes
 	go func() {
 		<-stopChan
+		w.lock.Lock()
+		w.stopped = true
+		w.resizeLocked(0)
+		w.lock.Unlock()
 		w.queue.ShutDown()
 		w.deliverer.Stop()
 	}()
 }
 
+// watchConfig applies changes of the config of the worker until stopChan is closed.
+func (w *asyncWorker) watchConfig(stopChan <-chan struct{}) {
+	for {
+		changed := w.configSource.Changed()
+		w.applyConfig(w.configSource.Config())
+
+		select {
+		case <-stopChan:
+			return
+		case <-changed:
+		}
+	}
+}
+
+func (w *asyncWorker) applyConfig(config Config) {
+	if config.WorkerCount <= 0 {
+		config.WorkerCount = 1
+	}
+	if config.QPS > 0 && config.Burst <= 0 {
+		config.Burst = 1
+	}
+	if config.InitialBackoff == 0 {
+		config.InitialBackoff = w.timing.InitialBackoff
+	}
+	if config.MaxBackoff == 0 {
+		config.MaxBackoff = w.timing.MaxBackoff
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.stopped {
+		return
+	}
+
+	previous := w.config
+	w.config = config
+
+	if w.backoff == nil || config.InitialBackoff != previous.InitialBackoff || config.MaxBackoff != previous.MaxBackoff {
+		w.backoff = flowcontrol.NewBackOff(config.InitialBackoff, config.MaxBackoff)
+	}
+	if config.QPS != previous.QPS || config.Burst != previous.Burst {
+		w.rateLimiter = nil
+		if config.QPS > 0 {
+			w.rateLimiter = flowcontrol.NewTokenBucketRateLimiter(config.QPS, config.Burst)
+		}
+	}
+	// Worker goroutines are only started once the worker is running.
+	if len(w.workerStops) > 0 {
+		w.resizeLocked(config.WorkerCount)
+	}
+}
+
+// resizeLocked starts or stops worker goroutines until count goroutines are running. A stopped goroutine finishes
+// reconciling its current object before it exits.
+func (w *asyncWorker) resizeLocked(count int) {
+	for len(w.workerStops) < count {
+		stop := make(chan struct{})
+		w.workerStops = append(w.workerStops, stop)
+		go wait.Until(func() { w.worker(stop) }, w.timing.Interval, stop)
+	}
+	for len(w.workerStops) > count {
+		last := len(w.workerStops) - 1
+		close(w.workerStops[last])
+		w.workerStops = w.workerStops[:last]
+	}
+}
+
+func (w *asyncWorker) runBackoffGC(stopChan <-chan struct{}) {
+	for {
+		select {
+		case <-time.After(time.Minute):
+			w.getBackoff().GC()
+		case <-stopChan:
+			return
+		}
+	}
+}
+
+func (w *asyncWorker) getBackoff() *flowcontrol.Backoff {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.backoff
+}
+
+func (w *asyncWorker) getRateLimiter() flowcontrol.RateLimiter {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return w.rateLimiter
+}
+
 // deliver adds backoff to delay if backoff is true.  Otherwise, it
 // resets backoff.
 func (w *asyncWorker) deliver(qualifiedName common.QualifiedName, delay time.Duration, backoff bool) {
 	key := qualifiedName.String()
+	backoffManager := w.getBackoff()
 	if backoff {
-		w.backoff.Next(key, time.Now())
-		delay = delay + w.backoff.Get(key)
+		backoffManager.Next(key, time.Now())
+		delay = delay + backoffManager.Get(key)
 	} else {
-		w.backoff.Reset(key)
+		backoffManager.Reset(key)
 	}
 	w.deliverer.DeliverAfter(key, &qualifiedName, delay)
 }
 
-func (w *asyncWorker) worker() {
+func (w *asyncWorker) worker(stop <-chan struct{}) {
 	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
 		obj, quit := w.queue.Get()
 		if quit {
 			return
 		}
 
+		if rateLimiter := w.getRateLimiter(); rateLimiter != nil {
+			rateLimiter.Accept()
+		}
+
 		qualifiedName := common.NewQualifiedFromString(obj.(string))
 		result := w.reconcile(qualifiedName)
 		w.queue.Done(obj)
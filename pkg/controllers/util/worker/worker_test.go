@@ -0,0 +1,160 @@
+/*
+Copyright 2023 The KubeAdmiral Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/common"
+	"github.com/kubewharf/kubeadmiral/pkg/controllers/util/delayingdeliver"
+	"github.com/kubewharf/kubeadmiral/pkg/stats"
+)
+
+type fakeConfigSource struct {
+	lock    sync.Mutex
+	config  Config
+	changed chan struct{}
+}
+
+func newFakeConfigSource(config Config) *fakeConfigSource {
+	return &fakeConfigSource{config: config, changed: make(chan struct{})}
+}
+
+func (s *fakeConfigSource) Config() Config {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.config
+}
+
+func (s *fakeConfigSource) Changed() <-chan struct{} {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.changed
+}
+
+func (s *fakeConfigSource) set(config Config) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.config = config
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func TestConfigurableWorkerCount(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	var lock sync.Mutex
+	inFlight := 0
+	release := make(chan struct{})
+	reconcile := func(common.QualifiedName) Result {
+		lock.Lock()
+		inFlight++
+		lock.Unlock()
+
+		<-release
+
+		lock.Lock()
+		inFlight--
+		lock.Unlock()
+		return StatusAllOK
+	}
+	getInFlight := func() int {
+		lock.Lock()
+		defer lock.Unlock()
+		return inFlight
+	}
+
+	source := newFakeConfigSource(Config{WorkerCount: 2})
+	w := NewConfigurableReconcileWorker(
+		reconcile,
+		WorkerTiming{},
+		source,
+		stats.NewMock("test", "kubeadmiral-controller-manager", false),
+		delayingdeliver.NewMetricTags("test-worker", "test"),
+	)
+
+	stopChan := make(chan struct{})
+	defer close(stopChan)
+	defer close(release)
+	w.Run(stopChan)
+
+	for i := 0; i < 10; i++ {
+		w.Enqueue(common.QualifiedName{Namespace: "default", Name: fmt.Sprintf("obj-%d", i)})
+	}
+
+	g.Eventually(getInFlight).WithTimeout(5 * time.Second).Should(gomega.Equal(2))
+	g.Consistently(getInFlight).WithTimeout(200 * time.Millisecond).Should(gomega.Equal(2))
+
+	source.set(Config{WorkerCount: 5})
+	g.Eventually(getInFlight).WithTimeout(5 * time.Second).Should(gomega.Equal(5))
+	g.Consistently(getInFlight).WithTimeout(200 * time.Millisecond).Should(gomega.Equal(5))
+}
+
+func TestConfigurableWorkerRateLimiter(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	source := newFakeConfigSource(Config{})
+	w := NewConfigurableReconcileWorker(
+		func(common.QualifiedName) Result { return StatusAllOK },
+		WorkerTiming{},
+		source,
+		stats.NewMock("test", "kubeadmiral-controller-manager", false),
+		delayingdeliver.NewMetricTags("test-worker", "test"),
+	).(*asyncWorker)
+	g.Expect(w.getRateLimiter()).To(gomega.BeNil())
+	g.Expect(w.config.Burst).To(gomega.Equal(0))
+
+	w.applyConfig(Config{QPS: 10})
+	rateLimiter := w.getRateLimiter()
+	g.Expect(rateLimiter).NotTo(gomega.BeNil())
+	g.Expect(rateLimiter.QPS()).To(gomega.Equal(float32(10)))
+	g.Expect(w.config.Burst).To(gomega.Equal(1))
+
+	// the rate limiter is kept if the rate limit does not change
+	w.applyConfig(Config{QPS: 10, WorkerCount: 3})
+	g.Expect(w.getRateLimiter()).To(gomega.BeIdenticalTo(rateLimiter))
+
+	w.applyConfig(Config{})
+	g.Expect(w.getRateLimiter()).To(gomega.BeNil())
+}
+
+func TestConfigurableWorkerBackoff(t *testing.T) {
+	g := gomega.NewWithT(t)
+
+	source := newFakeConfigSource(Config{})
+	w := NewConfigurableReconcileWorker(
+		func(common.QualifiedName) Result { return StatusAllOK },
+		WorkerTiming{InitialBackoff: time.Second, MaxBackoff: 10 * time.Second},
+		source,
+		stats.NewMock("test", "kubeadmiral-controller-manager", false),
+		delayingdeliver.NewMetricTags("test-worker", "test"),
+	).(*asyncWorker)
+
+	key := "default/obj"
+	w.getBackoff().Next(key, time.Now())
+	g.Expect(w.getBackoff().Get(key)).To(gomega.Equal(time.Second))
+
+	w.applyConfig(Config{InitialBackoff: 3 * time.Second})
+	w.getBackoff().Next(key, time.Now())
+	g.Expect(w.getBackoff().Get(key)).To(gomega.Equal(3 * time.Second))
+	g.Expect(w.config.MaxBackoff).To(gomega.Equal(10 * time.Second))
+}